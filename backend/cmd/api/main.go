@@ -2,22 +2,35 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/Naman30903/Final-Year-Project/internal/crypto"
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
 	"github.com/Naman30903/Final-Year-Project/internal/handler"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/encrypted"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/instrumented"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/invalidating"
 	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
 	"github.com/Naman30903/Final-Year-Project/internal/service"
 	"github.com/joho/godotenv" // Add this import
 )
 
 func main() {
+	selfTest := flag.Bool("selftest", false, "check configuration and dependencies, print a report and exit")
+	flag.Parse()
+
 	// Initialize logger
 	logger := log.New(os.Stdout, "API: ", log.LstdFlags)
 
@@ -35,42 +48,680 @@ func main() {
 		logger.Printf("Warning: .env file not found, using environment variables")
 	}
 
-	// Get ML service URL from environment variable
-	mlServiceURL := os.Getenv("ML_SERVICE_URL")
-	if mlServiceURL == "" {
-		mlServiceURL = "http://localhost:8000" // Default for local development
-		logger.Printf("ML_SERVICE_URL not set, using default: %s", mlServiceURL)
-	} else {
-		logger.Printf("Using ML service at: %s", mlServiceURL)
+	if *selfTest {
+		if !runSelfTest(os.Stdout) {
+			os.Exit(1)
+		}
+		return
 	}
 
-	mlServiceAPIKey := os.Getenv("ML_SERVICE_API_KEY")
-	mlPredictPath := os.Getenv("ML_PREDICT_PATH")
-	if mlPredictPath == "" {
-		mlPredictPath = "/predict"
+	truncationStrategy := os.Getenv("ML_TRUNCATION_STRATEGY")
+	if truncationStrategy == "" {
+		truncationStrategy = domain.TruncationNone
 	}
-	mlHealthPath := os.Getenv("ML_HEALTH_PATH")
-	if mlHealthPath == "" {
-		mlHealthPath = "/health"
+	maxInputChars := getEnvInt("ML_MAX_INPUT_CHARS", service.DefaultMaxInputChars)
+
+	// Optional per-organization ML endpoints
+	var mlRouter *service.MLRouter
+	if routesFile := os.Getenv("ML_ROUTES_FILE"); routesFile != "" {
+		router, err := service.LoadMLRouter(routesFile)
+		if err != nil {
+			logger.Fatalf("Failed to load ML routes: %v", err)
+		}
+		mlRouter = router
+		logger.Printf("Loaded custom ML routes for %d API keys", mlRouter.Len())
 	}
 
-	// Initialize repositories
-	predictionRepo := memory.NewPredictionRepository()
+	// Requests from outside a key's allowed_ips are refused and recorded
+	keyViolations := middleware.NewKeyViolations()
+
+	// Optional HMAC request signing for server-to-server consumers
+	var requestSigner *middleware.RequestSigner
+	if keysFile := os.Getenv("SIGNING_KEYS_FILE"); keysFile != "" {
+		keys, err := middleware.LoadSigningKeys(keysFile)
+		if err != nil {
+			logger.Fatalf("Failed to load signing keys: %v", err)
+		}
+		requestSigner, err = middleware.NewRequestSigner(keys, getEnvSeconds("SIGNING_MAX_SKEW", 0))
+		if err != nil {
+			logger.Fatalf("Invalid signing keys: %v", err)
+		}
+		requestSigner.WithViolations(keyViolations)
+		logger.Printf("Request signing enabled for %d keys", len(keys))
+	}
+
+	// Internal components authenticate as least-privilege service accounts
+	var serviceAccounts *middleware.ServiceAccounts
+	if accountsFile := os.Getenv("SERVICE_ACCOUNTS_FILE"); accountsFile != "" {
+		var err error
+		serviceAccounts, err = middleware.LoadServiceAccounts(accountsFile)
+		if err != nil {
+			logger.Fatalf("Failed to load service accounts: %v", err)
+		}
+		logger.Printf("Loaded %d service accounts", serviceAccounts.Len())
+	}
+
+	adminToken := os.Getenv("ADMIN_API_TOKEN")
+	captchaSecret := os.Getenv("CAPTCHA_SECRET")
+	captchaVerifyURL := os.Getenv("CAPTCHA_VERIFY_URL")
+	if captchaVerifyURL == "" {
+		captchaVerifyURL = "https://hcaptcha.com/siteverify"
+	}
+	abuseConfig := middleware.AbuseConfig{
+		Threshold:   getEnvFloat("ABUSE_BAN_THRESHOLD", 0),
+		HalfLife:    getEnvSeconds("ABUSE_SCORE_HALF_LIFE", 0),
+		BanDuration: getEnvSeconds("ABUSE_BAN_DURATION", 0),
+	}
+
+	// Optional audit (and allow-listing) of every outbound destination
+	outboundMode, err := service.ParseOutboundMode(os.Getenv("OUTBOUND_AUDIT"))
+	if err != nil {
+		logger.Fatalf("Invalid OUTBOUND_AUDIT: %v", err)
+	}
+	outboundRules, err := service.ParseOutboundAllowlist(os.Getenv("OUTBOUND_ALLOWLIST"))
+	if err != nil {
+		logger.Fatalf("Invalid OUTBOUND_ALLOWLIST: %v", err)
+	}
+	var outboundAudit *service.OutboundAudit
+	if outboundMode != service.OutboundOff {
+		if outboundMode == service.OutboundEnforce && len(outboundRules) == 0 {
+			logger.Fatalf("OUTBOUND_AUDIT=enforce requires OUTBOUND_ALLOWLIST")
+		}
+		outboundAudit = service.NewOutboundAudit(outboundMode, outboundRules)
+		if auditFile := os.Getenv("OUTBOUND_AUDIT_FILE"); auditFile != "" {
+			f, err := os.OpenFile(auditFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+			if err != nil {
+				logger.Fatalf("Failed to open outbound audit file: %v", err)
+			}
+			defer f.Close()
+			outboundAudit.WithSink(f)
+		}
+		logger.Printf("Outbound audit enabled (%s, %d allow-list rules)", outboundMode, len(outboundRules))
+	}
+
+	// Outbound webhooks and push notifications share one retry policy
+	deliveryEngine := service.NewDeliveryEngine().
+		WithRetry(getEnvInt("DELIVERY_MAX_ATTEMPTS", service.DefaultDeliveryMaxAttempts),
+			getEnvSeconds("DELIVERY_BACKOFF", service.DefaultDeliveryBackoff)).
+		WithCircuitBreaker(getEnvFloat("DELIVERY_ERROR_BUDGET", service.DefaultDeliveryErrorBudget),
+			getEnvSeconds("DELIVERY_BREAKER_COOLDOWN", service.DefaultDeliveryBreakerCooldown))
+
+	// Latency SLOs and burn-rate alerting
+	var alerter service.Alerter = service.LogAlerter{}
+	if webhookURL := os.Getenv("ALERT_WEBHOOK_URL"); webhookURL != "" {
+		alerter = service.MultiAlerter{alerter, service.NewWebhookAlerter(webhookURL).WithDelivery(deliveryEngine).WithOutboundAudit(outboundAudit)}
+	}
+	sloTracker := service.NewSLOTracker([]service.SLO{
+		{
+			Name:      "analyze-latency",
+			Stage:     service.StageAnalyze,
+			Objective: getEnvFloat("SLO_ANALYZE_OBJECTIVE", 0.95),
+			Threshold: time.Duration(getEnvInt("SLO_ANALYZE_THRESHOLD_MS", 5000)) * time.Millisecond,
+		},
+		{
+			Name:      "scrape-latency",
+			Stage:     service.StageScrape,
+			Objective: getEnvFloat("SLO_SCRAPE_OBJECTIVE", 0.95),
+			Threshold: time.Duration(getEnvInt("SLO_SCRAPE_THRESHOLD_MS", 3000)) * time.Millisecond,
+		},
+		{
+			Name:      "ml-latency",
+			Stage:     service.StageML,
+			Objective: getEnvFloat("SLO_ML_OBJECTIVE", 0.99),
+			Threshold: time.Duration(getEnvInt("SLO_ML_THRESHOLD_MS", 2000)) * time.Millisecond,
+		},
+	}, alerter)
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go sloTracker.Run(bgCtx, time.Minute)
+
+	// Initialize repositories. Every call is timed out, logged when slow
+	// and counted per method.
+	repoRecorder := instrumented.NewRecorder().
+		WithTimeout(getEnvMillis("REPOSITORY_TIMEOUT_MS", instrumented.DefaultTimeout)).
+		WithSlowThreshold(getEnvMillis("REPOSITORY_SLOW_MS", instrumented.DefaultSlowThreshold))
+	// Prediction writes are published so derived caches never serve a
+	// stale verdict; caches subscribe as they are created below.
+	invalidationBus := service.NewInvalidationBus()
+	predictionRepo := invalidating.NewPredictionRepository(
+		instrumented.NewPredictionRepository(memory.NewPredictionRepository(), repoRecorder), invalidationBus)
 
 	// Initialize services
-	mlClient := service.NewMLClient(mlServiceURL).
-		WithAPIKey(mlServiceAPIKey).
-		WithPaths(mlPredictPath, mlHealthPath)
-	scraperService := service.NewScraperService()
-	newsService := service.NewNewsService(mlClient, scraperService, predictionRepo)
+	mlClient := newMLClient(logger).WithOutboundAudit(outboundAudit)
+
+	// Dependencies are awaited in the background: the server starts at once
+	// and reports the boot sequence in /readyz instead of exiting when
+	// another container is not up yet.
+	startup := service.NewStartupOrchestrator(
+		service.Dependency{Name: "ml", Check: mlClient.HealthCheckContext},
+	).WithDeadline(getEnvSeconds("STARTUP_DEADLINE", service.DefaultStartupDeadline))
+	startup.Start(bgCtx)
+	go startup.Wait(bgCtx)
+
+	// Sensitive stored fields are encrypted when master keys are configured
+	keyring, err := loadKeyring()
+	if err != nil {
+		logger.Fatalf("Invalid ENCRYPTION_KEYS: %v", err)
+	}
+	if keyring != nil {
+		logger.Printf("Encryption at rest enabled (current key %s)", keyring.CurrentKey())
+	}
+
+	// Scrapes and ML inferences are metered per organization or key, each
+	// against its own daily plan limit
+	quotaTracker := service.NewQuotaTracker()
+	for _, plan := range []string{domain.PlanFree, domain.PlanPro, domain.PlanEnterprise} {
+		suffix := strings.ToUpper(plan)
+		quotaTracker.
+			WithLimit(domain.QuotaScrapes, plan, getEnvInt("SCRAPE_QUOTA_"+suffix, domain.DailyQuota(domain.QuotaScrapes, plan))).
+			WithLimit(domain.QuotaInferences, plan, getEnvInt("INFERENCE_QUOTA_"+suffix, domain.DailyQuota(domain.QuotaInferences, plan)))
+	}
+
+	crawlBudget := service.NewCrawlBudget(getEnvInt("CRAWL_PAGES_PER_DAY", service.DefaultCrawlPagesPerDay)).
+		WithMaxCrawlDelay(getEnvSeconds("CRAWL_MAX_DELAY", service.DefaultMaxCrawlDelay))
+	scraperService := service.NewScraperService().
+		WithOutboundAudit(outboundAudit).
+		WithMaxBytes(int64(getEnvInt("SCRAPER_MAX_BYTES", service.DefaultMaxScrapeBytes))).
+		WithMaxRelated(getEnvInt("SCRAPER_MAX_RELATED", service.DefaultMaxRelated)).
+		WithHealthProbe(os.Getenv("SCRAPER_PROBE_URL"), getEnvInt("SCRAPER_POOL_SIZE", service.DefaultScraperPoolSize)).
+		WithAllowPrivateNetworks(os.Getenv("SCRAPER_ALLOW_PRIVATE_NETWORKS") == "true").
+		WithTimeouts(getEnvSeconds("SCRAPER_TIMEOUT", service.DefaultScrapeTimeout),
+			getEnvSeconds("SCRAPER_TIMEOUT_FLOOR", service.DefaultScrapeTimeoutFloor),
+			getEnvSeconds("SCRAPER_TIMEOUT_CEILING", service.DefaultScrapeTimeoutCeiling)).
+		WithCrawlBudget(crawlBudget).
+		WithQuotas(quotaTracker)
+	if credentialsFile := os.Getenv("SCRAPER_CREDENTIALS_FILE"); credentialsFile != "" {
+		creds, err := service.LoadScraperCredentials(credentialsFile, keyring)
+		if err != nil {
+			logger.Fatalf("Failed to load scraper credentials: %v", err)
+		}
+		scraperService.WithCredentials(creds)
+		logger.Printf("Loaded scraper credentials for %d hosts", creds.Len())
+	}
+	fixtureMode, err := service.ParseFixtureMode(os.Getenv("SCRAPER_FIXTURE_MODE"))
+	if err != nil {
+		logger.Fatalf("Invalid SCRAPER_FIXTURE_MODE: %v", err)
+	}
+	if _, err := scraperService.WithFixtures(fixtureMode, os.Getenv("SCRAPER_FIXTURE_DIR")); err != nil {
+		logger.Fatalf("Failed to configure scraper fixtures: %v", err)
+	}
+	if fixtureMode != service.FixtureOff {
+		logger.Printf("Scraper fixture mode: %s (%s)", fixtureMode, os.Getenv("SCRAPER_FIXTURE_DIR"))
+	}
+
+	// Curated publisher registry (domain summaries and source reputation)
+	var sourceRegistry *service.SourceRegistry
+	if registryFile := os.Getenv("SOURCE_REGISTRY_FILE"); registryFile != "" {
+		registry, err := service.LoadSourceRegistry(registryFile)
+		if err != nil {
+			logger.Fatalf("Failed to load source registry: %v", err)
+		}
+		sourceRegistry = registry
+		logger.Printf("Loaded %d sources into the registry", sourceRegistry.Len())
+	}
+	verdictWeights := os.Getenv("VERDICT_WEIGHTS")
+	if verdictWeights == "" {
+		verdictWeights = service.DefaultVerdictWeights
+	}
+	// Organizations' own domain blocklists and trusted allowlists
+	orgRuleRepo := instrumented.NewOrgDomainRuleRepository(memory.NewOrgDomainRuleRepository(), repoRecorder)
+	orgPolicy := service.NewOrgPolicyService(orgRuleRepo)
+	categoryScores := service.NewCategoryScores()
+	verdictFusion, err := newVerdictFusion(verdictWeights, sourceRegistry, orgPolicy, categoryScores)
+	if err != nil {
+		logger.Fatalf("Invalid VERDICT_WEIGHTS: %v", err)
+	}
+	// Verdict weights, threshold and category scores can be retuned by
+	// admins; the latest saved version overrides VERDICT_WEIGHTS
+	tuningFile := os.Getenv("TUNING_STATE_FILE")
+	if tuningFile == "" {
+		tuningFile = "tuning.json"
+	}
+	tuningService, err := service.NewTuningService(verdictFusion, categoryScores, tuningFile)
+	if err != nil {
+		logger.Fatalf("Failed to load tuning state: %v", err)
+	}
+	if tuning := tuningService.Current(); tuning.Version > 0 {
+		logger.Printf("Applied verdict tuning version %d (by %s at %s)", tuning.Version, tuning.UpdatedBy, tuning.UpdatedAt.Format(time.RFC3339))
+	}
+	// Organizations' glossaries of tracked narratives tag new predictions
+	narrativeRepo := instrumented.NewNarrativeRepository(memory.NewNarrativeRepository(), repoRecorder)
+	narrativeService := service.NewNarrativeService(narrativeRepo, predictionRepo).
+		WithMaxNarratives(getEnvInt("NARRATIVE_MAX_PER_ORG", service.DefaultMaxNarratives))
+	newsService := service.NewNewsService(mlClient, scraperService, predictionRepo).
+		WithTruncator(service.NewTruncator(truncationStrategy, maxInputChars)).
+		WithSLOTracker(sloTracker).
+		WithVerdictFusion(verdictFusion).
+		WithOrgPolicy(orgPolicy).
+		WithNarratives(narrativeService).
+		WithQuotas(quotaTracker)
+
+	// Articles from allowlisted publishers skip the model entirely
+	if trustedList := os.Getenv("TRUSTED_SOURCES"); trustedList != "" {
+		trusted, err := service.NewTrustedSources(strings.Split(trustedList, ","))
+		if err != nil {
+			logger.Fatalf("Invalid TRUSTED_SOURCES: %v", err)
+		}
+		newsService.WithTrustedSources(trusted)
+		logger.Printf("%d trusted sources are answered without the model", trusted.Len())
+	}
+
+	// Publisher favicons and logos, cached and served for source_info
+	var branding *service.BrandingService
+	if os.Getenv("SOURCE_BRANDING") != "false" {
+		branding = service.NewBrandingService(scraperService)
+		newsService.WithBranding(branding)
+	}
+
+	// Evidence for include_evidence comes from a news search API when one
+	// is configured, otherwise from previously analyzed articles
+	var evidenceSource service.EvidenceSource = service.NewCorpusEvidenceSource(predictionRepo)
+	if searchURL := os.Getenv("EVIDENCE_SEARCH_URL"); searchURL != "" {
+		evidenceSource = service.NewHTTPEvidenceSource(searchURL, os.Getenv("EVIDENCE_API_KEY")).WithOutboundAudit(outboundAudit)
+		logger.Printf("Evidence retrieval uses a news search API")
+	}
+	newsService.WithEvidence(service.NewEvidenceRetriever(evidenceSource).
+		WithLimits(getEnvInt("EVIDENCE_MAX_CLAIMS", service.DefaultMaxClaims), getEnvInt("EVIDENCE_TOP_K", service.DefaultEvidenceTopK)))
+
+	// Articles in languages the models don't support are translated
+	// before scoring when a translation service is configured
+	if translateURL := os.Getenv("TRANSLATION_URL"); translateURL != "" {
+		languages := os.Getenv("ML_LANGUAGES")
+		if languages == "" {
+			languages = "en"
+		}
+		newsService.WithTranslation(service.NewTranslationBridge(service.NewHTTPTranslator(translateURL, os.Getenv("TRANSLATION_API_KEY")).WithOutboundAudit(outboundAudit), strings.Split(languages, ",")).
+			WithPenalty(getEnvFloat("TRANSLATION_PENALTY", service.DefaultTranslationPenalty)))
+		logger.Printf("Translation enabled for languages other than %s", languages)
+	}
+
+	// Optional archive tier: old predictions move to compressed segments,
+	// leaving summary rows behind
+	if archiveDays := getEnvInt("ARCHIVE_AFTER_DAYS", 0); archiveDays > 0 {
+		archiveDir := getEnvString("ARCHIVE_DIR", "archive")
+		archive, err := service.NewFileArchive(archiveDir)
+		if err != nil {
+			logger.Fatalf("Failed to open prediction archive: %v", err)
+		}
+		newsService.WithArchive(archive)
+		archiver := service.NewArchiver(predictionRepo, archive, time.Duration(archiveDays)*24*time.Hour)
+		go archiver.Run(bgCtx, time.Hour)
+		logger.Printf("Archive enabled: predictions older than %d days move to %s", archiveDays, archiveDir)
+	}
+
+	// Known-fake URLs get a provisional verdict for requests that allow it
+	if getEnvString("KNOWN_FAKE_FILTER", "true") != "false" {
+		knownFakes := service.NewKnownFakeFilter(predictionRepo, sourceRegistry).
+			WithMinConfidence(getEnvFloat("KNOWN_FAKE_MIN_CONFIDENCE", service.DefaultKnownFakeMinConfidence))
+		if n, err := knownFakes.Rebuild(bgCtx); err != nil {
+			logger.Printf("Warning: %v", err)
+		} else {
+			logger.Printf("Known-fake filter built with %d entries", n)
+		}
+		newsService.WithKnownFakeFilter(knownFakes)
+		invalidationBus.Subscribe("known-fakes", knownFakes.Invalidate)
+		go knownFakes.Run(bgCtx, getEnvSeconds("KNOWN_FAKE_REBUILD_INTERVAL", service.DefaultKnownFakeRebuildInterval))
+	}
+
+	// Stored verdicts past this age are answered at once, marked stale,
+	// and re-scored in the background for requests that allow it
+	if staleAfter := getEnvSeconds("STALE_VERDICT_AGE", 0); staleAfter > 0 {
+		newsService.WithStaleAfter(staleAfter)
+		logger.Printf("Verdicts older than %s are refreshed in the background", staleAfter)
+	}
+
+	// Uncertain verdicts wait for human reviewers
+	var reviewQueue *service.ReviewQueue
+	if getEnvString("REVIEW_QUEUE", "true") != "false" {
+		reviewQueue = service.NewReviewQueue(newsService).
+			WithBand(getEnvFloat("REVIEW_QUEUE_MIN_CONFIDENCE", service.DefaultReviewMinConfidence),
+				getEnvFloat("REVIEW_QUEUE_MAX_CONFIDENCE", service.DefaultReviewMaxConfidence)).
+			WithMaxUncertainty(getEnvFloat("REVIEW_QUEUE_MAX_UNCERTAINTY", service.DefaultReviewMaxUncertainty)).
+			WithClaimTTL(getEnvSeconds("REVIEW_CLAIM_TTL", service.DefaultReviewClaimTTL)).
+			WithSLA(getEnvSeconds("REVIEW_SLA", service.DefaultReviewSLA))
+		newsService.WithReviewQueue(reviewQueue)
+		invalidationBus.Subscribe("review-queue", func(change domain.PredictionChange) {
+			if change.Kind == domain.ChangeDeleted {
+				reviewQueue.Remove(change.ID)
+			}
+		})
+	}
+
+	// Synthetic canary: known articles run through the full pipeline
+	var canary *service.Canary
+	if getEnvString("CANARY", "true") != "false" {
+		cases := service.DefaultCanaryCases
+		if casesFile := os.Getenv("CANARY_CASES_FILE"); casesFile != "" {
+			cases, err = service.LoadCanaryCases(casesFile)
+			if err != nil {
+				logger.Fatalf("Failed to load canary cases: %v", err)
+			}
+		}
+		canary = service.NewCanary(newsService, cases, alerter).
+			WithThresholds(getEnvMillis("CANARY_MAX_LATENCY_MS", service.DefaultCanaryMaxLatency),
+				getEnvInt("CANARY_FAILURE_THRESHOLD", service.DefaultCanaryFailureThreshold),
+				getEnvFloat("CANARY_DRIFT_TOLERANCE", service.DefaultCanaryDriftTolerance))
+		go canary.Run(bgCtx, getEnvSeconds("CANARY_INTERVAL", service.DefaultCanaryInterval))
+		logger.Printf("Canary enabled: %d cases", len(cases))
+	}
+
+	// Prediction drift: recent verdicts against the window before them
+	var drift *service.DriftMonitor
+	if getEnvString("DRIFT", "true") != "false" {
+		drift = service.NewDriftMonitor(predictionRepo, alerter).
+			WithWindows(getEnvSeconds("DRIFT_RECENT_WINDOW", service.DefaultDriftRecentWindow),
+				getEnvSeconds("DRIFT_BASELINE_WINDOW", service.DefaultDriftBaselineWindow)).
+			WithMinSamples(getEnvInt("DRIFT_MIN_SAMPLES", service.DefaultDriftMinSamples))
+		go drift.Run(bgCtx, getEnvSeconds("DRIFT_INTERVAL", service.DefaultDriftInterval))
+	}
+
+	// Long-term metrics: hourly rollups downsampled to daily ones, which
+	// outlive prediction retention
+	var rollupJob *service.MetricsRollupJob
+	if getEnvString("METRICS_ROLLUP", "true") != "false" {
+		rollupRepo := instrumented.NewMetricsRollupRepository(memory.NewMetricsRollupRepository(), repoRecorder)
+		rollupJob = service.NewMetricsRollupJob(predictionRepo, rollupRepo).
+			WithSettle(getEnvSeconds("METRICS_ROLLUP_SETTLE", service.DefaultRollupSettle)).
+			WithRetention(time.Duration(getEnvInt("METRICS_ROLLUP_HOURLY_DAYS", 0))*24*time.Hour,
+				time.Duration(getEnvInt("METRICS_ROLLUP_DAILY_DAYS", 0))*24*time.Hour)
+		now := time.Now()
+		if err := rollupJob.Rollup(bgCtx, now.Add(-rollupJob.HourlyRetention()), now); err != nil {
+			logger.Printf("Warning: metrics rollup backfill failed: %v", err)
+		}
+		go rollupJob.Run(bgCtx, getEnvSeconds("METRICS_ROLLUP_INTERVAL", service.DefaultRollupInterval))
+	}
+
+	// Optional retention; pinned predictions are always kept
+	if retentionDays := getEnvInt("RETENTION_DAYS", 0); retentionDays > 0 {
+		janitor := service.NewRetentionJanitor(predictionRepo, time.Duration(retentionDays)*24*time.Hour)
+		go janitor.Run(bgCtx, time.Hour)
+		logger.Printf("Retention enabled: unpinned predictions are kept for %d days", retentionDays)
+	}
+
+	// Domain trust summaries for integrators
+	domainSummaryService := service.NewDomainSummaryService(predictionRepo, sourceRegistry,
+		getEnvSeconds("DOMAIN_SUMMARY_CACHE_TTL", 5*time.Minute))
+	invalidationBus.Subscribe("domain-summaries", domainSummaryService.Invalidate)
+	domainRateLimiter := middleware.NewRateLimiter(getEnvFloat("DOMAIN_SUMMARY_RATE", 20), getEnvInt("DOMAIN_SUMMARY_BURST", 40)).
+		WithQueue(time.Duration(getEnvInt("DOMAIN_SUMMARY_MAX_WAIT_MS", 2000))*time.Millisecond, getEnvInt("DOMAIN_SUMMARY_QUEUE_SIZE", 10))
+
+	// Client addresses for bans, rate limits and key allowlists come from
+	// X-Forwarded-For only when a trusted proxy sent the request
+	trustedProxies, err := middleware.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		logger.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	// Registered API clients choose the default response verbosity
+	var apiClients *middleware.APIClients
+	if clientsFile := os.Getenv("API_CLIENTS_FILE"); clientsFile != "" {
+		clients, err := middleware.LoadAPIClients(clientsFile)
+		if err != nil {
+			logger.Fatalf("Failed to load API clients: %v", err)
+		}
+		apiClients = clients.WithViolations(keyViolations)
+		logger.Printf("Loaded %d API clients", apiClients.Len())
+	}
+
+	// Maintenance mode survives restarts so a migration window stays closed
+	maintenanceFile := os.Getenv("MAINTENANCE_STATE_FILE")
+	if maintenanceFile == "" {
+		maintenanceFile = "maintenance.json"
+	}
+	maintenance, err := middleware.NewMaintenance(maintenanceFile)
+	if err != nil {
+		logger.Fatalf("Failed to load maintenance state: %v", err)
+	}
+	if window := maintenance.Window(); window.Enabled {
+		logger.Printf("Maintenance mode is enabled (active now: %v)", window.ActiveAt(time.Now()))
+	}
+
+	// Initialize abuse protection for the public analyze endpoint
+	abuseGuard := middleware.NewAbuseGuard(abuseConfig).WithAdminToken(adminToken)
+	if captchaSecret != "" {
+		abuseGuard.WithCaptcha(service.NewCaptchaVerifier(captchaVerifyURL, captchaSecret).WithOutboundAudit(outboundAudit))
+		logger.Printf("CAPTCHA verification enabled for anonymous requests")
+	}
+
+	// Analyses scrape and run the model, so each client gets a bucket of
+	// them: per signed-in user, API key or IP
+	var analyzeRateLimiter *middleware.RateLimiter
+	if perMinute := getEnvFloat("ANALYZE_RATE_PER_MINUTE", 30); perMinute > 0 {
+		analyzeRateLimiter = middleware.NewRateLimiterPerMinute(perMinute, getEnvInt("ANALYZE_BURST", 10)).
+			WithQueue(time.Duration(getEnvInt("ANALYZE_MAX_WAIT_MS", 2000))*time.Millisecond, getEnvInt("ANALYZE_QUEUE_SIZE", 10))
+	}
+
+	// Initialize Web Push (optional)
+	var pushService *service.PushService
+	if vapidKey := os.Getenv("VAPID_PRIVATE_KEY"); vapidKey != "" {
+		vapidSubject := os.Getenv("VAPID_SUBJECT")
+		if vapidSubject == "" {
+			vapidSubject = "mailto:admin@localhost"
+		}
+		sender, err := service.NewWebPushSender(vapidSubject, vapidKey)
+		if err != nil {
+			logger.Fatalf("Failed to initialize Web Push: %v", err)
+		}
+		sender.WithOutboundAudit(outboundAudit)
+		var subscriptionStore repository.PushSubscriptionRepository = memory.NewPushSubscriptionRepository()
+		if keyring != nil {
+			subscriptionStore = encrypted.NewPushSubscriptionRepository(subscriptionStore, keyring)
+		}
+		subscriptionRepo := instrumented.NewPushSubscriptionRepository(subscriptionStore, repoRecorder)
+		pushService = service.NewPushService(subscriptionRepo, sender).WithDelivery(deliveryEngine)
+		logger.Printf("Web Push notifications enabled")
+	}
 
 	// Initialize handlers
-	newsHandler := handler.NewNewsHandler(newsService)
+	preferencesRepo := instrumented.NewPreferencesRepository(memory.NewPreferencesRepository(), repoRecorder)
+	templateRepo := instrumented.NewAnalysisTemplateRepository(memory.NewAnalysisTemplateRepository(), repoRecorder)
+	templateService := service.NewAnalysisTemplateService(templateRepo).
+		WithMaxTemplates(getEnvInt("TEMPLATE_MAX_PER_OWNER", service.DefaultMaxTemplates))
+	templateHandler := handler.NewTemplateHandler(templateService)
+	newsHandler := handler.NewNewsHandler(newsService).
+		WithAdminToken(adminToken).
+		WithStartup(startup).
+		WithCanary(canary).
+		WithPreferences(service.NewPreferencesService(preferencesRepo)).
+		WithTemplates(templateService).
+		WithClaimReview(service.ClaimReviewPublisher{
+			Name: os.Getenv("CLAIMREVIEW_PUBLISHER_NAME"),
+			URL:  os.Getenv("CLAIMREVIEW_PUBLISHER_URL"),
+		}, os.Getenv("PUBLIC_BASE_URL"))
+	if feedSecret := os.Getenv("FEED_TOKEN_SECRET"); feedSecret != "" {
+		newsHandler.WithFeeds(service.NewFeedTokens(feedSecret), os.Getenv("PUBLIC_BASE_URL"))
+		logger.Printf("History feeds enabled")
+	}
+	// Visitors who are not signed in keep their analyses under a signed
+	// anonymous session cookie until it expires or they sign in
+	var anonymousHistory *service.AnonymousHistory
+	if secret := getEnvString("ANONYMOUS_SESSION_SECRET", os.Getenv("SESSION_SECRET")); secret != "" {
+		anonymousHistory = service.NewAnonymousHistory(secret,
+			getEnvSeconds("ANONYMOUS_SESSION_TTL", service.DefaultAnonymousSessionTTL), newsService)
+		newsHandler.WithAnonymousHistory(anonymousHistory)
+		logger.Printf("Anonymous history enabled")
+	}
+	evaluationRepo := instrumented.NewEvaluationRepository(memory.NewEvaluationRepository(), repoRecorder)
+	evaluationService := service.NewEvaluationService(newsService, evaluationRepo).
+		WithMaxRows(getEnvInt("EVALUATION_MAX_ROWS", service.DefaultMaxEvaluationRows))
+
+	// Background jobs run on cmd/worker processes when a worker token or
+	// worker service account is set
+	var jobHandler *handler.JobHandler
+	var jobQueue *service.JobQueue
+	if workerToken := os.Getenv("WORKER_TOKEN"); workerToken != "" || serviceAccounts.HasRole(middleware.ServiceRoleWorker) {
+		jobQueue = service.NewJobQueue().
+			WithLease(getEnvSeconds("JOB_LEASE_TTL", service.DefaultJobLeaseTTL), getEnvInt("JOB_MAX_ATTEMPTS", service.DefaultJobMaxAttempts))
+		evaluationService.WithQueue(jobQueue)
+		jobHandler = handler.NewJobHandler(jobQueue, workerToken)
+		// Jobs deferred by an ML outage are retried once it recovers
+		go jobQueue.RunRecovery(bgCtx, getEnvSeconds("JOB_RECOVERY_INTERVAL", service.DefaultJobRecoveryInterval),
+			newsService.CheckMLHealth)
+		logger.Printf("Job queue enabled: evaluations run on workers")
+	}
+
+	// Article watches are rechecked in the background
+	watchRepo := instrumented.NewWatchRepository(memory.NewWatchRepository(), repoRecorder)
+	watchService := service.NewWatchService(newsService, watchRepo).
+		WithMaxWatches(getEnvInt("WATCH_MAX_PER_USER", service.DefaultMaxWatches)).
+		WithCrawlBudget(crawlBudget)
+	if pushService != nil {
+		watchService.WithNotifier(pushService)
+	}
+	go watchService.Run(bgCtx, getEnvSeconds("WATCH_RECHECK_INTERVAL", service.DefaultWatchInterval))
+	watchHandler := handler.NewWatchHandler(watchService)
+
+	// Saved history searches notify their owners of new matches
+	searchRepo := instrumented.NewSavedSearchRepository(memory.NewSavedSearchRepository(), repoRecorder)
+	searchService := service.NewSavedSearchService(newsService, searchRepo).
+		WithMaxSearches(getEnvInt("SAVED_SEARCH_MAX_PER_USER", service.DefaultMaxSavedSearches))
+	if pushService != nil {
+		searchService.WithNotifier(pushService)
+	}
+	go searchService.Run(bgCtx, getEnvSeconds("SAVED_SEARCH_CHECK_INTERVAL", service.DefaultSavedSearchInterval))
+	searchHandler := handler.NewSavedSearchHandler(searchService)
+
+	// Webhook subscriptions: signed event deliveries to subscriber URLs
+	var webhookStore repository.WebhookRepository = memory.NewWebhookRepository()
+	if keyring != nil {
+		webhookStore = encrypted.NewWebhookRepository(webhookStore, keyring)
+	}
+	webhookService := service.NewWebhookService(instrumented.NewWebhookRepository(webhookStore, repoRecorder)).
+		WithDelivery(deliveryEngine).
+		WithOutboundAudit(outboundAudit).
+		WithMaxSubscriptions(getEnvInt("WEBHOOK_MAX_PER_USER", service.DefaultMaxWebhooks)).
+		WithPrivateNetworks(os.Getenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS") == "true")
+	invalidationBus.Subscribe("webhooks", webhookService.Invalidate)
+	quotaTracker.OnThreshold(webhookService.QuotaCrossed)
+	if jobQueue != nil {
+		jobQueue.OnFinished(webhookService.JobFinished)
+	}
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+
+	// Nightly summary of the previous day's analyses
+	reportRepo := instrumented.NewReportRepository(memory.NewReportRepository(), repoRecorder)
+	reportService := service.NewReportService(predictionRepo, reportRepo).
+		WithEvaluations(evaluationRepo)
+	if webhookURL := os.Getenv("REPORT_WEBHOOK_URL"); webhookURL != "" {
+		reportService.WithDelivery(service.NewWebhookReportDelivery(webhookURL).WithDelivery(deliveryEngine).WithOutboundAudit(outboundAudit))
+		logger.Printf("Nightly reports are delivered to a webhook")
+	}
+	go reportService.Run(bgCtx, getEnvInt("REPORT_HOUR", service.DefaultReportHour))
+	orgHandler := handler.NewOrgHandler(orgPolicy, adminToken).WithNarratives(narrativeService)
+
+	// Single sign-on for institutional deployments, and social sign-in
+	// with Google or GitHub. Both provision users and issue sessions.
+	var ssoHandler *handler.SSOHandler
+	var oauthHandler *handler.OAuthHandler
+	var sessions *service.SessionTokens
+	ssoFile := os.Getenv("SSO_PROVIDERS_FILE")
+	googleClientID, githubClientID := os.Getenv("OAUTH_GOOGLE_CLIENT_ID"), os.Getenv("OAUTH_GITHUB_CLIENT_ID")
+	if ssoFile != "" || googleClientID != "" || githubClientID != "" {
+		sessionSecret, publicURL := os.Getenv("SESSION_SECRET"), os.Getenv("PUBLIC_BASE_URL")
+		if sessionSecret == "" || publicURL == "" {
+			logger.Fatalf("SSO_PROVIDERS_FILE and OAUTH_*_CLIENT_ID require SESSION_SECRET and PUBLIC_BASE_URL")
+		}
+		sessions = service.NewSessionTokens(sessionSecret, getEnvSeconds("SESSION_TTL", service.DefaultSessionTTL))
+		var userStore repository.UserRepository = memory.NewUserRepository()
+		if keyring != nil {
+			encryptedUsers := encrypted.NewUserRepository(userStore, keyring)
+			if n, err := encryptedUsers.Rotate(bgCtx); err != nil {
+				logger.Printf("Warning: failed to re-encrypt users: %v", err)
+			} else if n > 0 {
+				logger.Printf("Re-encrypted %d users with key %s", n, keyring.CurrentKey())
+			}
+			userStore = encryptedUsers
+		}
+		userRepo := instrumented.NewUserRepository(userStore, repoRecorder)
+		userService := service.NewUserService(userRepo)
+		successRedirect := os.Getenv("SSO_SUCCESS_REDIRECT")
+
+		if ssoFile != "" {
+			providers, err := service.LoadSSOProviders(ssoFile)
+			if err != nil {
+				logger.Fatalf("Failed to load SSO providers: %v", err)
+			}
+			ssoService, err := service.NewSSOService(providers, userService, sessions,
+				strings.TrimRight(publicURL, "/")+"/api/auth/sso/callback")
+			if err != nil {
+				logger.Fatalf("Invalid SSO providers: %v", err)
+			}
+			ssoService.WithOutboundAudit(outboundAudit)
+			ssoHandler = handler.NewSSOHandler(ssoService, successRedirect).WithAnonymousHistory(anonymousHistory)
+			logger.Printf("Single sign-on enabled for %d organizations", len(providers))
+		}
+
+		if googleClientID != "" || githubClientID != "" {
+			oauthService := service.NewOAuthService(userService, sessions, publicURL).
+				WithOutboundAudit(outboundAudit)
+			if googleClientID != "" {
+				oauthService.WithProvider(service.NewGoogleProvider(googleClientID, os.Getenv("OAUTH_GOOGLE_CLIENT_SECRET")))
+			}
+			if githubClientID != "" {
+				oauthService.WithProvider(service.NewGitHubProvider(githubClientID, os.Getenv("OAUTH_GITHUB_CLIENT_SECRET")))
+			}
+			if domains := os.Getenv("OAUTH_ALLOWED_DOMAINS"); domains != "" {
+				oauthService.WithAllowedDomains(strings.Split(domains, ","))
+			}
+			oauthHandler = handler.NewOAuthHandler(oauthService, getEnvString("OAUTH_SUCCESS_REDIRECT", successRedirect)).
+				WithAnonymousHistory(anonymousHistory)
+			for _, name := range oauthService.Providers() {
+				logger.Printf("Social sign-in enabled with %s (redirect URI %s)", name, oauthService.RedirectURL(name))
+			}
+		}
+	}
+
+	// Bearer JWTs from an identity provider sharing JWT_SECRET authenticate
+	// users on /api/*
+	var jwtAuth *middleware.JWTAuth
+	if jwtSecret := os.Getenv("JWT_SECRET"); jwtSecret != "" {
+		verifier := service.NewJWTVerifier(jwtSecret).
+			WithIssuer(os.Getenv("JWT_ISSUER")).
+			WithAudience(os.Getenv("JWT_AUDIENCE"))
+		jwtAuth = middleware.NewJWTAuth(verifier)
+		if os.Getenv("JWT_REQUIRED") == "true" {
+			jwtAuth.WithRequired(middleware.DefaultJWTPublicPaths...).
+				WithBearerTokens(adminToken, os.Getenv("WORKER_TOKEN"))
+		}
+		logger.Printf("JWT authentication enabled (required: %t)", os.Getenv("JWT_REQUIRED") == "true")
+	} else if os.Getenv("JWT_REQUIRED") == "true" {
+		logger.Fatalf("JWT_REQUIRED requires JWT_SECRET")
+	}
+
+	corpusRepo := instrumented.NewCorpusRepository(memory.NewCorpusRepository(), repoRecorder)
+	adminHandler := handler.NewAdminHandler(adminToken, abuseGuard, newsService).
+		WithEvaluations(evaluationService).
+		WithMaintenance(maintenance).
+		WithReports(reportService).
+		WithDeliveries(deliveryEngine).
+		WithCorpora(service.NewCorpusBuilder(predictionRepo, corpusRepo)).
+		WithOutboundAudit(outboundAudit).
+		WithTuning(tuningService).
+		WithAuditSampler(service.NewAuditSampler(predictionRepo)).
+		WithBulkDeleter(service.NewBulkDeleter(predictionRepo)).
+		WithCrawlBudget(crawlBudget).
+		WithKeyViolations(keyViolations)
+	if jobQueue != nil {
+		adminHandler.WithJobQueue(jobQueue)
+	}
+	if reviewQueue != nil {
+		adminHandler.WithReviewQueue(reviewQueue)
+	}
+	statsHandler := handler.NewStatsHandler(sloTracker, newsService).WithRepositoryStats(repoRecorder).WithCanary(canary).WithDrift(drift).
+		WithInvalidation(invalidationBus).WithRollups(rollupJob)
+	usageTracker := service.NewUsageTracker()
+	usageHandler := handler.NewUsageHandler(usageTracker, newsService).WithQuotas(quotaTracker)
+	domainHandler := handler.NewDomainHandler(domainSummaryService).WithBranding(branding)
+	var pushHandler *handler.PushHandler
+	if pushService != nil {
+		newsHandler.WithPushService(pushService)
+		pushHandler = handler.NewPushHandler(pushService).WithAdminToken(adminToken)
+	}
 
 	// Create HTTP server
 	srv := &http.Server{
 		Addr:         ":8080",
-		Handler:      setupRoutes(newsHandler),
+		Handler:      setupRoutes(newsHandler, adminHandler, pushHandler, statsHandler, domainHandler, abuseGuard, domainRateLimiter, mlRouter, requestSigner, apiClients, serviceAccounts, jobHandler, maintenance, watchHandler, searchHandler, templateHandler, ssoHandler, sessions, orgHandler, usageTracker, usageHandler, jwtAuth, webhookHandler, oauthHandler, analyzeRateLimiter, trustedProxies),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -100,20 +751,176 @@ func main() {
 	logger.Println("Server exited")
 }
 
-func setupRoutes(newsHandler *handler.NewsHandler) http.Handler {
+func setupRoutes(newsHandler *handler.NewsHandler, adminHandler *handler.AdminHandler,
+	pushHandler *handler.PushHandler, statsHandler *handler.StatsHandler, domainHandler *handler.DomainHandler,
+	abuseGuard *middleware.AbuseGuard, domainRateLimiter *middleware.RateLimiter, mlRouter *service.MLRouter,
+	requestSigner *middleware.RequestSigner, apiClients *middleware.APIClients, serviceAccounts *middleware.ServiceAccounts, jobHandler *handler.JobHandler,
+	maintenance *middleware.Maintenance, watchHandler *handler.WatchHandler, searchHandler *handler.SavedSearchHandler,
+	templateHandler *handler.TemplateHandler, ssoHandler *handler.SSOHandler, sessions *service.SessionTokens, orgHandler *handler.OrgHandler,
+	usageTracker *service.UsageTracker, usageHandler *handler.UsageHandler, jwtAuth *middleware.JWTAuth, webhookHandler *handler.WebhookHandler,
+	oauthHandler *handler.OAuthHandler, analyzeRateLimiter *middleware.RateLimiter, trustedProxies *middleware.TrustedProxies) http.Handler {
 	mux := http.NewServeMux()
+	scoped := func(pattern, scope string, h http.HandlerFunc) {
+		mux.Handle(pattern, middleware.RequireScope(scope, h))
+	}
 
 	// Basic health check
 	mux.HandleFunc("/health", healthCheckHandler)
+	mux.HandleFunc("/readyz", newsHandler.Readyz)
 
 	// News analysis endpoints
-	mux.HandleFunc("/api/analyze", newsHandler.AnalyzeNews)
-	mux.HandleFunc("/api/predictions", newsHandler.GetPrediction)
-	mux.HandleFunc("/api/history", newsHandler.GetHistory)
+	mux.Handle("/api/analyze", middleware.RequireScope(middleware.ScopeAnalyzeWrite, analyzeRateLimiter.Middleware(abuseGuard.Middleware(
+		middleware.MLRouting(mlRouter, http.HandlerFunc(newsHandler.AnalyzeNews))))))
+	scoped("/api/predictions", middleware.ScopeHistoryRead, newsHandler.GetPrediction)
+	scoped("/api/predictions/{id}/pin", middleware.ScopeAnalyzeWrite, newsHandler.PinPrediction)
+	mux.HandleFunc("/api/predictions/{id}/claimreview", newsHandler.ClaimReview)
+	scoped("/api/predictions/{id}/annotated", middleware.ScopeHistoryRead, newsHandler.AnnotatedPrediction)
+	scoped("/api/predictions/{id}/diagnostics", middleware.ScopeHistoryRead, newsHandler.PredictionDiagnostics)
+	scoped("/api/history", middleware.ScopeHistoryRead, newsHandler.GetHistory)
+	scoped("/api/history/feed", middleware.ScopeHistoryRead, newsHandler.HistoryFeedURL)
+	scoped("/api/history/feed.xml", middleware.ScopeHistoryRead, newsHandler.HistoryFeed)
 	mux.HandleFunc("/api/health", newsHandler.HealthCheck)
+	mux.HandleFunc("/api/watches", watchHandler.Watches)
+	mux.HandleFunc("/api/watches/{id}", watchHandler.Unwatch)
+	mux.HandleFunc("/api/searches", searchHandler.Searches)
+	mux.HandleFunc("/api/searches/{id}", searchHandler.Search)
+	scoped("/api/searches/{id}/results", middleware.ScopeHistoryRead, searchHandler.Results)
+
+	// Webhook subscriptions
+	scoped("/api/webhooks", middleware.ScopeWebhooksManage, webhookHandler.Webhooks)
+	scoped("/api/webhooks/{id}", middleware.ScopeWebhooksManage, webhookHandler.Webhook)
+	scoped("/api/webhooks/{id}/test", middleware.ScopeWebhooksManage, webhookHandler.Test)
+	mux.HandleFunc("/api/templates", templateHandler.Templates)
+	mux.HandleFunc("/api/templates/{id}", templateHandler.Template)
+
+	// Single sign-on and social sign-in
+	if ssoHandler != nil {
+		mux.HandleFunc("/api/auth/sso", ssoHandler.Organizations)
+		mux.HandleFunc("/api/auth/sso/{org}/login", ssoHandler.Login)
+		mux.HandleFunc("/api/auth/sso/callback", ssoHandler.Callback)
+	}
+	if oauthHandler != nil {
+		mux.HandleFunc("/api/auth/oauth", oauthHandler.Providers)
+		mux.HandleFunc("/api/auth/oauth/{provider}/login", oauthHandler.Login)
+		mux.HandleFunc("/api/auth/oauth/{provider}/callback", oauthHandler.Callback)
+	}
+	if sessions != nil {
+		sessionHandler := handler.NewSessionHandler()
+		mux.HandleFunc("/api/auth/me", sessionHandler.Me)
+		mux.HandleFunc("/api/auth/logout", sessionHandler.Logout)
+	}
+
+	// Organization settings
+	scoped("/api/orgs/{org}/domains", middleware.ScopeAdminOrgs, orgHandler.Domains)
+	scoped("/api/orgs/{org}/domains/{id}", middleware.ScopeAdminOrgs, orgHandler.DeleteDomain)
+	scoped("/api/orgs/{org}/narratives", middleware.ScopeAdminOrgs, orgHandler.Narratives)
+	scoped("/api/orgs/{org}/narratives/{id}", middleware.ScopeAdminOrgs, orgHandler.Narrative)
+	scoped("/api/orgs/{org}/narratives/{id}/stats", middleware.ScopeAdminOrgs, orgHandler.NarrativeStats)
+
+	// Callers' own usage
+	mux.HandleFunc("/api/users/me/usage", usageHandler.Usage)
+	mux.HandleFunc("/api/users/me/preferences", newsHandler.Preferences)
+
+	// Integrator API
+	mux.Handle("/api/v1/domains/{domain}/summary", domainRateLimiter.Middleware(http.HandlerFunc(domainHandler.Summary)))
+
+	// Cached source branding linked from predictions' source_info
+	mux.HandleFunc("/api/sources/{domain}/{asset}", domainHandler.Branding)
+
+	// Operational stats
+	mux.HandleFunc("/api/stats/slo", statsHandler.SLO)
+	mux.HandleFunc("/api/stats/ml", statsHandler.ML)
+	mux.HandleFunc("/api/stats/scraper", statsHandler.Scraper)
+	mux.HandleFunc("/api/stats/known-fakes", statsHandler.KnownFakes)
+	mux.HandleFunc("/api/stats/review-queue", statsHandler.ReviewQueue)
+	mux.HandleFunc("/api/stats/repository", statsHandler.Repository)
+	mux.HandleFunc("/api/stats/timeseries", statsHandler.TimeSeries)
+	mux.HandleFunc("/api/stats/compare", statsHandler.Compare)
+	mux.HandleFunc("/api/stats/canary", statsHandler.Canary)
+	mux.HandleFunc("/api/stats/drift", statsHandler.Drift)
+	mux.HandleFunc("/api/stats/invalidation", statsHandler.Invalidation)
+	mux.HandleFunc("/api/stats/rollups", statsHandler.Rollups)
+
+	// Web Push endpoints
+	if pushHandler != nil {
+		mux.HandleFunc("/api/push/vapid-public-key", pushHandler.PublicKey)
+		mux.HandleFunc("/api/push/subscribe", pushHandler.Subscribe)
+		mux.HandleFunc("/api/push/unsubscribe", pushHandler.Unsubscribe)
+	}
+
+	// Admin endpoints
+	scoped("/api/admin/bans", middleware.ScopeAdminBans, adminHandler.Bans)
+	scoped("/api/admin/rescore", middleware.ScopeAdminRescore, adminHandler.Rescore)
+	scoped("/api/admin/import", middleware.ScopeAdminImport, adminHandler.Import)
+	scoped("/api/admin/maintenance", middleware.ScopeAdminMaintenance, adminHandler.Maintenance)
+	scoped("/api/admin/jobs", middleware.ScopeAdminJobs, adminHandler.Jobs)
+	scoped("/api/admin/jobs/{id}/requeue", middleware.ScopeAdminJobs, adminHandler.RequeueJob)
+
+	// Benchmark evaluations (admin token)
+	scoped("/api/evaluate", middleware.ScopeAdminEvaluations, adminHandler.Evaluate)
+	scoped("/api/evaluations/{id}", middleware.ScopeAdminEvaluations, adminHandler.GetEvaluation)
+
+	// Outbound delivery dead letters (admin token)
+	scoped("/api/admin/deliveries", middleware.ScopeAdminDeliveries, adminHandler.Deliveries)
+	scoped("/api/admin/deliveries/{id}/redeliver", middleware.ScopeAdminDeliveries, adminHandler.Redeliver)
+
+	// Human fact-checker reviews, published as ClaimReview (admin token)
+	scoped("/api/admin/predictions/{id}/review", middleware.ScopeAdminReviews, adminHandler.Review)
+	scoped("/api/admin/review-queue", middleware.ScopeAdminReviews, adminHandler.ReviewQueue)
+	scoped("/api/admin/review-queue/claim", middleware.ScopeAdminReviews, adminHandler.ClaimReviewItem)
+	scoped("/api/admin/review-queue/{id}/{action}", middleware.ScopeAdminReviews, adminHandler.UpdateReviewItem)
+	scoped("/api/admin/audit-sample", middleware.ScopeAdminReviews, adminHandler.AuditSample)
+	scoped("/api/admin/audit-samples", middleware.ScopeAdminReviews, adminHandler.AuditSamples)
+	scoped("/api/admin/audit-samples/{id}", middleware.ScopeAdminReviews, adminHandler.GetAuditSample)
+	scoped("/api/admin/audit-samples/{id}/audited", middleware.ScopeAdminReviews, adminHandler.GetAuditSample)
+	scoped("/api/admin/predictions/bulk-delete", middleware.ScopeAdminDelete, adminHandler.BulkDelete)
+	scoped("/api/admin/bulk-deletes", middleware.ScopeAdminDelete, adminHandler.BulkDeletes)
+	scoped("/api/admin/bulk-deletes/{id}", middleware.ScopeAdminDelete, adminHandler.GetBulkDelete)
+	scoped("/api/admin/crawl-budget", middleware.ScopeAdminCrawl, adminHandler.CrawlBudget)
+	scoped("/api/admin/crawl-budget/{domain}", middleware.ScopeAdminCrawl, adminHandler.CrawlBudget)
+
+	// Research corpora for fine-tuning (admin token)
+	scoped("/api/admin/corpora", middleware.ScopeAdminCorpora, adminHandler.Corpora)
+	scoped("/api/admin/corpora/{id}", middleware.ScopeAdminCorpora, adminHandler.GetCorpus)
+	scoped("/api/admin/corpora/{id}/data", middleware.ScopeAdminCorpora, adminHandler.GetCorpus)
+	scoped("/api/admin/licenses", middleware.ScopeAdminCorpora, adminHandler.Licenses)
+
+	// Outbound destination audit (admin token)
+	scoped("/api/admin/outbound", middleware.ScopeAdminAudit, adminHandler.Outbound)
+	scoped("/api/admin/key-violations", middleware.ScopeAdminAudit, adminHandler.KeyViolations)
+
+	// Runtime verdict tuning (admin token)
+	scoped("/api/admin/tuning", middleware.ScopeAdminTuning, adminHandler.Tuning)
+
+	// Nightly reports (admin token)
+	scoped("/api/reports", middleware.ScopeAdminReports, adminHandler.Reports)
+	scoped("/api/reports/{id}", middleware.ScopeAdminReports, adminHandler.GetReport)
+	scoped("/api/reports/{id}/html", middleware.ScopeAdminReports, adminHandler.GetReport)
+
+	// Worker job queue (worker token or worker service account)
+	if jobHandler != nil {
+		scoped("/api/internal/jobs", middleware.ScopeInternalJobs, jobHandler.Stats)
+		scoped("/api/internal/jobs/lease", middleware.ScopeInternalJobs, jobHandler.Lease)
+		scoped("/api/internal/jobs/{id}/{action}", middleware.ScopeInternalJobs, jobHandler.Update)
+	}
+
+	var h http.Handler = handler.ClientCompatibility(handler.ContentNegotiation(maintenance.Middleware(mux)))
+	h = middleware.RecordUsage(usageTracker, h)
+	h = jwtAuth.Require(h)
+	h = apiClients.Middleware(h)
+	if sessions != nil {
+		h = middleware.SessionAuth(sessions, h)
+	}
+	h = jwtAuth.Middleware(h)
+	h = serviceAccounts.Middleware(h)
+	if requestSigner != nil {
+		h = requestSigner.Middleware(h)
+	}
+	h = middleware.RequestID(h)
+	h = trustedProxies.Middleware(h)
 
 	// Wrap with CORS middleware
-	return corsMiddleware(mux)
+	return corsMiddleware(h)
 }
 
 // corsMiddleware handles CORS preflight requests and adds necessary headers
@@ -122,7 +929,9 @@ func corsMiddleware(next http.Handler) http.Handler {
 		// Set CORS headers for all responses
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-Requested-With")
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-Requested-With, X-Captcha-Token, X-API-Key, "+
+			"X-Signature-Key-Id, X-Signature-Timestamp, X-Signature, X-Request-ID, traceparent, X-Client-Version, X-API-Version")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 		w.Header().Set("Access-Control-Max-Age", "3600")
 
 		// Handle preflight OPTIONS request
@@ -140,3 +949,134 @@ func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	fmt.Fprintf(w, "OK")
 }
+
+// newMLClient configures the ML client from the environment
+func newMLClient(logger *log.Logger) *service.MLClient {
+	mlServiceURL := os.Getenv("ML_SERVICE_URL")
+	if mlServiceURL == "" {
+		mlServiceURL = "http://localhost:8000" // Default for local development
+		logger.Printf("ML_SERVICE_URL not set, using default: %s", mlServiceURL)
+	} else {
+		logger.Printf("Using ML service at: %s", mlServiceURL)
+	}
+
+	mlPredictPath := os.Getenv("ML_PREDICT_PATH")
+	if mlPredictPath == "" {
+		mlPredictPath = "/predict"
+	}
+	mlHealthPath := os.Getenv("ML_HEALTH_PATH")
+	if mlHealthPath == "" {
+		mlHealthPath = "/health"
+	}
+
+	mlClient := service.NewMLClient(mlServiceURL).
+		WithAPIKey(os.Getenv("ML_SERVICE_API_KEY")).
+		WithPaths(mlPredictPath, mlHealthPath).
+		WithSummarizer(os.Getenv("ML_SUMMARIZE_URL"), os.Getenv("ML_SUMMARIZE_PATH")).
+		WithCompression(getEnvInt("ML_COMPRESS_MIN_BYTES", service.DefaultMLCompressMinBytes))
+	if fallbackModel := os.Getenv("ML_FALLBACK_MODEL"); fallbackModel != "" {
+		mlClient.WithFallback(fallbackModel, os.Getenv("ML_FALLBACK_URL"),
+			time.Duration(getEnvInt("ML_PRIMARY_TIMEOUT_MS", 10000))*time.Millisecond)
+		logger.Printf("ML fallback model enabled: %s", fallbackModel)
+	}
+	return mlClient
+}
+
+// newVerdictFusion builds the verdict pipeline from "name=weight" pairs
+func newVerdictFusion(spec string, registry *service.SourceRegistry, orgPolicy *service.OrgPolicyService, categories *service.CategoryScores) (*service.VerdictFusion, error) {
+	weights, err := service.ParseVerdictWeights(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every signal is registered so admins can weight it at runtime. No
+	// fact-check provider is integrated yet; that signal abstains.
+	signals := map[string]service.Signal{
+		domain.SignalML:               service.MLSignal{},
+		domain.SignalHeuristic:        service.HeuristicSignal{},
+		domain.SignalSourceReputation: service.SourceReputationSignal{Registry: registry, Scores: categories},
+		domain.SignalRecency:          service.RecencySignal{},
+		domain.SignalOrgPolicy:        service.OrgPolicySignal{Policy: orgPolicy},
+		domain.SignalFactCheck:        service.FactCheckSignal{},
+	}
+	// The org policy signal only speaks for organizations with allowlists,
+	// so it is on by default.
+	fusion := service.NewVerdictFusion().
+		WithCatalog(signals[domain.SignalHeuristic], signals[domain.SignalSourceReputation],
+			signals[domain.SignalRecency], signals[domain.SignalFactCheck]).
+		WithSignal(signals[domain.SignalOrgPolicy], service.DefaultOrgPolicyWeight)
+	for name, weight := range weights {
+		signal, ok := signals[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown signal %q", name)
+		}
+		fusion.WithSignal(signal, weight)
+	}
+	return fusion, nil
+}
+
+// loadKeyring reads master keys from ENCRYPTION_KEYS, or from the file
+// named by ENCRYPTION_KEYS_FILE as mounted by a secrets manager. It
+// returns nil when neither is set.
+func loadKeyring() (*crypto.Keyring, error) {
+	spec := os.Getenv("ENCRYPTION_KEYS")
+	if path := os.Getenv("ENCRYPTION_KEYS_FILE"); spec == "" && path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption keys: %w", err)
+		}
+		spec = strings.TrimSpace(string(data))
+	}
+	if spec == "" {
+		return nil, nil
+	}
+	return crypto.ParseKeyring(spec)
+}
+
+// getEnvSeconds reads an integer number of seconds from the environment
+func getEnvSeconds(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return defaultValue
+}
+
+// getEnvMillis reads a duration in milliseconds from the environment
+func getEnvMillis(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if ms, err := strconv.Atoi(value); err == nil {
+			return time.Duration(ms) * time.Millisecond
+		}
+	}
+	return defaultValue
+}
+
+// getEnvString reads a string from the environment
+func getEnvString(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvInt reads an integer from the environment
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+// getEnvFloat reads a float from the environment
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
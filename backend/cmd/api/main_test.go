@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/handler"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+func TestPinRouteRequiresWriteScope(t *testing.T) {
+	apiClients, err := middleware.NewAPIClients([]middleware.APIClient{
+		{APIKey: "reader", Name: "dashboard", Scopes: []string{middleware.ScopeHistoryRead}},
+		{APIKey: "writer", Name: "extension", Scopes: []string{middleware.ScopeAnalyzeWrite}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	maintenance, err := middleware.NewMaintenance("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newsHandler := handler.NewNewsHandler(service.NewNewsService(nil, nil, memory.NewPredictionRepository()))
+	routes := setupRoutes(newsHandler, nil, nil, nil, nil, nil, nil, nil, nil, apiClients, nil, nil,
+		maintenance, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	for _, tt := range []struct {
+		key  string
+		want func(int) bool
+	}{
+		{"reader", func(code int) bool { return code == http.StatusForbidden }},
+		{"writer", func(code int) bool { return code != http.StatusForbidden }},
+	} {
+		for _, method := range []string{http.MethodPost, http.MethodDelete} {
+			r := httptest.NewRequest(method, "/api/predictions/p1/pin", nil)
+			r.Header.Set("X-API-Key", tt.key)
+			rec := httptest.NewRecorder()
+			routes.ServeHTTP(rec, r)
+			if !tt.want(rec.Code) {
+				t.Errorf("%s %s key: status = %d", method, tt.key, rec.Code)
+			}
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// selfTestTimeout bounds each network check.
+const selfTestTimeout = 10 * time.Second
+
+// runSelfTest checks the configuration and dependencies the server would
+// use, writes a report to out and reports whether every check passed.
+// Degraded and skipped checks do not fail the self-test.
+func runSelfTest(out io.Writer) bool {
+	var checks []service.HealthCheck
+	checks = append(checks, checkConfig()...)
+	checks = append(checks, service.HealthCheck{
+		Name:   "storage",
+		Status: service.HealthSkipped,
+		Detail: "in-memory; no database, Redis or blob store is used",
+	})
+	checks = append(checks, checkML()...)
+	checks = append(checks, checkScraper()...)
+
+	failed := 0
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, c := range checks {
+		label := map[string]string{
+			service.HealthOK:       "PASS",
+			service.HealthDegraded: "WARN",
+			service.HealthDown:     "FAIL",
+			service.HealthSkipped:  "SKIP",
+		}[c.Status]
+		if c.Status == service.HealthDown {
+			failed++
+		}
+		detail := c.Detail
+		if c.LatencyMS > 0 {
+			detail = fmt.Sprintf("%s (%dms)", detail, c.LatencyMS)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", label, c.Name, detail)
+	}
+	tw.Flush()
+
+	if failed > 0 {
+		fmt.Fprintf(out, "\n%d of %d checks failed\n", failed, len(checks))
+		return false
+	}
+	fmt.Fprintf(out, "\nAll %d checks passed\n", len(checks))
+	return true
+}
+
+// checkConfig loads every configured file and validates settings the
+// server would otherwise reject at startup.
+func checkConfig() []service.HealthCheck {
+	var checks []service.HealthCheck
+	check := func(name string, run func() (string, error)) {
+		c := service.HealthCheck{Name: "config: " + name, Status: service.HealthOK}
+		detail, err := run()
+		switch {
+		case err != nil:
+			c.Status, c.Detail = service.HealthDown, err.Error()
+		case detail == "":
+			c.Status, c.Detail = service.HealthSkipped, "not set"
+		default:
+			c.Detail = detail
+		}
+		checks = append(checks, c)
+	}
+	file := func(env string, load func(path string) (string, error)) {
+		check(env, func() (string, error) {
+			path := os.Getenv(env)
+			if path == "" {
+				return "", nil
+			}
+			return load(path)
+		})
+	}
+
+	keyring, keyringErr := loadKeyring()
+	check("ENCRYPTION_KEYS", func() (string, error) {
+		if keyring == nil {
+			return "", keyringErr
+		}
+		return fmt.Sprintf("encrypting with key %s", keyring.CurrentKey()), nil
+	})
+	check("ML_TRUNCATION_STRATEGY", func() (string, error) {
+		strategy := os.Getenv("ML_TRUNCATION_STRATEGY")
+		if strategy == "" {
+			return "", nil
+		}
+		if !domain.IsValidTruncationStrategy(strategy) {
+			return "", fmt.Errorf("unknown strategy %q", strategy)
+		}
+		return strategy, nil
+	})
+	file("ML_ROUTES_FILE", func(path string) (string, error) {
+		router, err := service.LoadMLRouter(path)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d API keys routed", router.Len()), nil
+	})
+	file("SIGNING_KEYS_FILE", func(path string) (string, error) {
+		keys, err := middleware.LoadSigningKeys(path)
+		if err != nil {
+			return "", err
+		}
+		if _, err := middleware.NewRequestSigner(keys, getEnvSeconds("SIGNING_MAX_SKEW", 0)); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d signing keys", len(keys)), nil
+	})
+	file("SCRAPER_CREDENTIALS_FILE", func(path string) (string, error) {
+		creds, err := service.LoadScraperCredentials(path, keyring)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("credentials for %d hosts", creds.Len()), nil
+	})
+	file("CANARY_CASES_FILE", func(path string) (string, error) {
+		cases, err := service.LoadCanaryCases(path)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d canary cases", len(cases)), nil
+	})
+	file("SOURCE_REGISTRY_FILE", func(path string) (string, error) {
+		registry, err := service.LoadSourceRegistry(path)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d sources", registry.Len()), nil
+	})
+	check("TRUSTED_SOURCES", func() (string, error) {
+		list := os.Getenv("TRUSTED_SOURCES")
+		if list == "" {
+			return "", nil
+		}
+		trusted, err := service.NewTrustedSources(strings.Split(list, ","))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d trusted sources", trusted.Len()), nil
+	})
+	check("VERDICT_WEIGHTS", func() (string, error) {
+		spec := getEnvString("VERDICT_WEIGHTS", service.DefaultVerdictWeights)
+		if _, err := newVerdictFusion(spec, nil, nil, nil); err != nil {
+			return "", err
+		}
+		return spec, nil
+	})
+	file("API_CLIENTS_FILE", func(path string) (string, error) {
+		clients, err := middleware.LoadAPIClients(path)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d API clients", clients.Len()), nil
+	})
+	check("MAINTENANCE_STATE_FILE", func() (string, error) {
+		path := getEnvString("MAINTENANCE_STATE_FILE", "maintenance.json")
+		maintenance, err := middleware.NewMaintenance(path)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s (maintenance enabled: %v)", path, maintenance.Window().Enabled), nil
+	})
+	file("SSO_PROVIDERS_FILE", func(path string) (string, error) {
+		if os.Getenv("SESSION_SECRET") == "" || os.Getenv("PUBLIC_BASE_URL") == "" {
+			return "", fmt.Errorf("requires SESSION_SECRET and PUBLIC_BASE_URL")
+		}
+		providers, err := service.LoadSSOProviders(path)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d organizations", len(providers)), nil
+	})
+	check("VAPID_PRIVATE_KEY", func() (string, error) {
+		key := os.Getenv("VAPID_PRIVATE_KEY")
+		if key == "" {
+			return "", nil
+		}
+		if _, err := service.NewWebPushSender("mailto:selftest@localhost", key); err != nil {
+			return "", err
+		}
+		return "valid key", nil
+	})
+	return checks
+}
+
+// checkML fetches the ML service's health endpoint and model metadata.
+func checkML() []service.HealthCheck {
+	ctx, cancel := context.WithTimeout(context.Background(), selfTestTimeout)
+	defer cancel()
+
+	c := service.HealthCheck{Name: "ml: " + getEnvString("ML_SERVICE_URL", "http://localhost:8000")}
+	start := time.Now()
+	info, err := newMLClient(log.New(io.Discard, "", 0)).ModelInfo(ctx)
+	c.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		c.Status, c.Detail = service.HealthDown, err.Error()
+		return []service.HealthCheck{c}
+	}
+
+	c.Status = service.HealthOK
+	c.Detail = "model " + info.ModelVersion
+	if info.ModelVersion == "" {
+		c.Status, c.Detail = service.HealthDegraded, "healthy but reported no model version"
+	}
+	if info.Device != "" {
+		c.Detail += " on " + info.Device
+	}
+	return []service.HealthCheck{c}
+}
+
+// checkScraper resolves and fetches the scraper probe URL to prove
+// outbound internet access.
+func checkScraper() []service.HealthCheck {
+	ctx, cancel := context.WithTimeout(context.Background(), selfTestTimeout)
+	defer cancel()
+
+	scraper := service.NewScraperService().
+		WithHealthProbe(os.Getenv("SCRAPER_PROBE_URL"), 0).
+		WithAllowPrivateNetworks(os.Getenv("SCRAPER_ALLOW_PRIVATE_NETWORKS") == "true")
+	var checks []service.HealthCheck
+	for _, c := range scraper.SelfCheck(ctx).Checks {
+		if c.Name == "dns" || c.Name == "connectivity" {
+			c.Name = "scraper: " + c.Name
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/pkg/client"
+)
+
+func runCorpus(args []string) error {
+	fs := flag.NewFlagSet("corpus", flag.ExitOnError)
+	server := fs.String("server", getEnv("FNCTL_SERVER", "http://localhost:8080"), "API base URL")
+	token := fs.String("token", os.Getenv("ADMIN_API_TOKEN"), "admin bearer token")
+	size := fs.Int("size", 0, "records to sample (default: as many as stay balanced across labels)")
+	seed := fs.Int64("seed", 1, "sampling seed; the same seed over the same history gives the same corpus")
+	since := fs.String("since", "", "only sample predictions created on or after this date (YYYY-MM-DD)")
+	until := fs.String("until", "", "only sample predictions created before this date (YYYY-MM-DD)")
+	languages := fs.String("languages", "", "comma-separated ISO 639-1 languages to keep (default: all)")
+	out := fs.String("out", "corpus.jsonl", "JSONL export; the manifest is written next to it as .manifest.json")
+	fs.Parse(args)
+
+	req := client.CorpusRequest{Size: *size, Seed: *seed}
+	for flagName, value := range map[string]string{"since": *since, "until": *until} {
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return fmt.Errorf("invalid --%s %q: want YYYY-MM-DD", flagName, value)
+		}
+		if flagName == "since" {
+			req.Since = &t
+		} else {
+			req.Until = &t
+		}
+	}
+	if *languages != "" {
+		req.Languages = strings.Split(*languages, ",")
+	}
+
+	ctx := context.Background()
+	api := newAPIClient(*server, *token)
+	manifest, err := api.BuildCorpus(ctx, req)
+	if err != nil {
+		return err
+	}
+	data, err := api.CorpusData(ctx, manifest.ID)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != manifest.SHA256 {
+		return fmt.Errorf("corpus %s: export checksum %s does not match manifest %s", manifest.ID, got, manifest.SHA256)
+	}
+
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write corpus: %w", err)
+	}
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	manifestPath := strings.TrimSuffix(*out, ".jsonl") + ".manifest.json"
+	if err := os.WriteFile(manifestPath, manifestJSON, 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	fmt.Printf("%s: %d records from %d candidates across %d strata (%v)\n",
+		manifest.ID, manifest.Records, manifest.Candidates, manifest.Strata, manifest.ByLabel)
+	fmt.Printf("wrote %s and %s\n", *out, manifestPath)
+	return nil
+}
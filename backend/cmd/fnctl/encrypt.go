@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Naman30903/Final-Year-Project/internal/crypto"
+)
+
+func runEncrypt(args []string) error {
+	fs := flag.NewFlagSet("encrypt", flag.ExitOnError)
+	keys := fs.String("keys", os.Getenv("ENCRYPTION_KEYS"), "master keys as id:base64key,... (the first encrypts)")
+	newKey := fs.String("new-key", "", "print a fresh master key with this id instead of encrypting")
+	fs.Parse(args)
+
+	if *newKey != "" {
+		key := make([]byte, crypto.MasterKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return err
+		}
+		fmt.Printf("%s:%s\n", *newKey, base64.StdEncoding.EncodeToString(key))
+		return nil
+	}
+
+	if *keys == "" {
+		return errors.New("ENCRYPTION_KEYS or --keys is required")
+	}
+	keyring, err := crypto.ParseKeyring(*keys)
+	if err != nil {
+		return err
+	}
+
+	// Values come from the arguments, or one per line on stdin so they
+	// stay out of shell history
+	values := fs.Args()
+	if len(values) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				values = append(values, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read values: %w", err)
+		}
+	}
+	for _, value := range values {
+		sealed, err := keyring.Encrypt(value)
+		if err != nil {
+			return err
+		}
+		fmt.Println(sealed)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	server := fs.String("server", getEnv("FNCTL_SERVER", "http://localhost:8080"), "API base URL")
+	token := fs.String("token", os.Getenv("ADMIN_API_TOKEN"), "admin bearer token")
+	file := fs.String("file", "", "prototype JSON dump to import")
+	dryRun := fs.Bool("dry-run", false, "validate and count records without storing them")
+	fs.Parse(args)
+
+	if *file == "" {
+		return errors.New("--file is required")
+	}
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("failed to read dump: %w", err)
+	}
+	if !json.Valid(data) {
+		return fmt.Errorf("%s is not valid JSON", *file)
+	}
+
+	stats, err := newAPIClient(*server, *token).Import(context.Background(), data, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	mode := "imported"
+	if stats.DryRun {
+		mode = "would import"
+	}
+	fmt.Printf("%d records: %s %d, %d duplicates, %d invalid\n",
+		stats.Total, mode, stats.Imported, stats.Duplicates, stats.Invalid)
+	for _, msg := range stats.Errors {
+		fmt.Fprintf(os.Stderr, "  %s\n", msg)
+	}
+	return nil
+}
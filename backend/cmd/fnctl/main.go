@@ -0,0 +1,76 @@
+// Command fnctl is the operator CLI for the fake news detection API.
+//
+// Usage:
+//
+//	fnctl rescore --since 2024-01-01 --model v2 --concurrency 8
+//	fnctl import --file prototype-dump.json --dry-run
+//	fnctl corpus --size 5000 --seed 42 --out corpus.jsonl
+//	fnctl encrypt --new-key k2
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Naman30903/Final-Year-Project/pkg/client"
+)
+
+const usage = `fnctl - operator CLI for the fake news detection API
+
+Usage:
+  fnctl <command> [flags]
+
+Commands:
+  rescore   Re-run stored predictions through the ML service and report flips
+  import    Import predictions from the Python prototype's JSON dump
+  corpus    Export a balanced, anonymized training corpus with its manifest
+  encrypt   Encrypt secrets for config files, or generate a master key
+
+Environment:
+  FNCTL_SERVER      API base URL (default: http://localhost:8080)
+  ADMIN_API_TOKEN   Admin bearer token
+  ENCRYPTION_KEYS   Master keys for encrypt
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "rescore":
+		err = runRescore(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	case "corpus":
+		err = runCorpus(os.Args[2:])
+	case "encrypt":
+		err = runEncrypt(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fnctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// newAPIClient creates an SDK client for the API fnctl drives.
+func newAPIClient(server, token string) *client.Client {
+	return client.New(server, client.WithToken(token), client.WithUserAgent("fnctl"))
+}
+
+// getEnv gets an environment variable or returns a default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
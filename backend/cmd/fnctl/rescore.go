@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/pkg/client"
+)
+
+// reportHeader is the column layout of the per-prediction rescore report.
+// The report doubles as the resume checkpoint: IDs already present are
+// skipped when the command is re-run with the same --out file.
+var reportHeader = []string{
+	"id", "request_type", "created_at",
+	"previous_result", "previous_confidence",
+	"rescored_result", "rescored_confidence", "rescored_model",
+	"flipped", "error",
+}
+
+func runRescore(args []string) error {
+	fs := flag.NewFlagSet("rescore", flag.ExitOnError)
+	server := fs.String("server", getEnv("FNCTL_SERVER", "http://localhost:8080"), "API base URL")
+	token := fs.String("token", os.Getenv("ADMIN_API_TOKEN"), "admin bearer token")
+	since := fs.String("since", "", "only rescore predictions created on or after this date (YYYY-MM-DD)")
+	model := fs.String("model", "", "model version to rescore with (default: the service's current model)")
+	concurrency := fs.Int("concurrency", 4, "number of parallel rescore requests")
+	out := fs.String("out", "rescore-report.csv", "per-prediction report; also used as the resume checkpoint")
+	summaryPath := fs.String("summary", "rescore-summary.csv", "summary diff report")
+	fs.Parse(args)
+
+	if *concurrency < 1 {
+		return errors.New("--concurrency must be at least 1")
+	}
+	var sinceTime time.Time
+	if *since != "" {
+		t, err := time.Parse("2006-01-02", *since)
+		if err != nil {
+			return fmt.Errorf("invalid --since %q: want YYYY-MM-DD", *since)
+		}
+		sinceTime = t
+	}
+
+	api := newAPIClient(*server, *token)
+	history, err := api.History(context.Background(), client.HistoryQuery{All: true})
+	if err != nil {
+		return fmt.Errorf("failed to fetch history: %w", err)
+	}
+
+	done, err := loadCheckpoint(*out)
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	for _, p := range history {
+		if p.CreatedAt.Before(sinceTime) || done[p.ID] {
+			continue
+		}
+		ids = append(ids, p.ID)
+	}
+	sort.Strings(ids)
+
+	fmt.Fprintf(os.Stderr, "%d predictions in range, %d already done, %d to rescore\n",
+		len(ids)+len(done), len(done), len(ids))
+
+	report, err := openReport(*out)
+	if err != nil {
+		return err
+	}
+	defer report.close()
+
+	jobs := make(chan string)
+	progress := newProgressBar(len(ids))
+	var wg sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				report.write(rescoreRow(api, id, *model))
+				progress.increment()
+			}
+		}()
+	}
+	for _, id := range ids {
+		jobs <- id
+	}
+	close(jobs)
+	wg.Wait()
+	progress.finish()
+
+	if err := report.close(); err != nil {
+		return err
+	}
+	return writeSummary(*out, *summaryPath)
+}
+
+// rescoreRow rescores one prediction and formats it as a report row.
+func rescoreRow(api *client.Client, id, model string) []string {
+	row := make([]string, len(reportHeader))
+	row[0] = id
+
+	res, err := api.Rescore(context.Background(), id, model)
+	if err != nil {
+		row[9] = err.Error()
+		return row
+	}
+	row[1] = res.Previous.RequestType
+	row[2] = res.Previous.CreatedAt.Format(time.RFC3339)
+	row[3] = res.Previous.Result
+	row[4] = strconv.FormatFloat(res.Previous.Confidence, 'f', 4, 64)
+	row[5] = res.Rescored.Result
+	row[6] = strconv.FormatFloat(res.Rescored.Confidence, 'f', 4, 64)
+	row[7] = res.Rescored.ModelVersion
+	row[8] = strconv.FormatBool(res.Flipped)
+	return row
+}
+
+// loadCheckpoint returns the IDs already rescored successfully in an
+// existing report.
+func loadCheckpoint(path string) (map[string]bool, error) {
+	done := make(map[string]bool)
+	rows, err := readReport(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return done, nil
+		}
+		return nil, err
+	}
+	for _, row := range rows {
+		if row[9] == "" {
+			done[row[0]] = true
+		}
+	}
+	return done, nil
+}
+
+func readReport(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = len(reportHeader)
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report %s: %w", path, err)
+	}
+	if len(rows) > 0 && rows[0][0] == reportHeader[0] {
+		rows = rows[1:]
+	}
+	return rows, nil
+}
+
+// reportWriter appends rows to the report CSV, flushing after each row so
+// an interrupted run can be resumed.
+type reportWriter struct {
+	mu     sync.Mutex
+	file   *os.File
+	writer *csv.Writer
+	closed bool
+}
+
+func openReport(path string) (*reportWriter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open report: %w", err)
+	}
+	w := &reportWriter{file: f, writer: csv.NewWriter(f)}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.Size() == 0 {
+		w.write(reportHeader)
+	}
+	return w, nil
+}
+
+func (w *reportWriter) write(row []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writer.Write(row)
+	w.writer.Flush()
+}
+
+func (w *reportWriter) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// writeSummary aggregates the full report (including resumed rows) into a
+// metric,value CSV and prints it.
+func writeSummary(reportPath, summaryPath string) error {
+	rows, err := readReport(reportPath)
+	if err != nil {
+		return err
+	}
+
+	// Later rows win so retried IDs are counted once.
+	latest := make(map[string][]string)
+	for _, row := range rows {
+		latest[row[0]] = row
+	}
+
+	counts := map[string]int{}
+	for _, row := range latest {
+		if row[9] != "" {
+			counts["errors"]++
+			continue
+		}
+		counts["rescored"]++
+		if row[8] == "true" {
+			counts["flipped"]++
+			counts[fmt.Sprintf("flipped_%s_to_%s", strings.ToLower(row[3]), strings.ToLower(row[5]))]++
+		}
+	}
+
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	f, err := os.Create(summaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to create summary: %w", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"metric", "value"})
+	w.Write([]string{"total", strconv.Itoa(len(latest))})
+	fmt.Printf("total: %d\n", len(latest))
+	for _, k := range keys {
+		w.Write([]string{k, strconv.Itoa(counts[k])})
+		fmt.Printf("%s: %d\n", k, counts[k])
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	fmt.Printf("report: %s\nsummary: %s\n", reportPath, summaryPath)
+	return nil
+}
+
+// progressBar renders a single-line progress bar on stderr.
+type progressBar struct {
+	mu    sync.Mutex
+	out   io.Writer
+	total int
+	done  int
+}
+
+func newProgressBar(total int) *progressBar {
+	p := &progressBar{out: os.Stderr, total: total}
+	p.render()
+	return p
+}
+
+func (p *progressBar) increment() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.render()
+}
+
+func (p *progressBar) finish() {
+	fmt.Fprintln(p.out)
+}
+
+// render draws the bar. Callers must hold p.mu (or be the constructor).
+func (p *progressBar) render() {
+	const width = 40
+	filled := width
+	if p.total > 0 {
+		filled = p.done * width / p.total
+	}
+	fmt.Fprintf(p.out, "\r[%s%s] %d/%d",
+		strings.Repeat("=", filled), strings.Repeat(" ", width-filled), p.done, p.total)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// reportRow builds a report row; an empty errMsg is a successful rescore.
+func reportRow(id, previous, rescored, errMsg string) []string {
+	if errMsg != "" {
+		return []string{id, "", "", "", "", "", "", "", "", errMsg}
+	}
+	flipped := "false"
+	if previous != rescored {
+		flipped = "true"
+	}
+	return []string{id, "url", "2024-05-01T00:00:00Z", previous, "0.9000", rescored, "0.8000", "v2", flipped, ""}
+}
+
+func writeReport(t *testing.T, path string, rows ...[]string) {
+	t.Helper()
+	report, err := openReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		report.write(row)
+	}
+	if err := report.close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.csv")
+
+	done, err := loadCheckpoint(path)
+	if err != nil || len(done) != 0 {
+		t.Fatalf("missing report = %v, %v; want an empty checkpoint", done, err)
+	}
+
+	writeReport(t, path,
+		reportRow("a", "REAL", "REAL", ""),
+		reportRow("b", "", "", "status 502"),
+		reportRow("c", "FAKE", "REAL", ""),
+	)
+	// A resumed run appends to the same report
+	writeReport(t, path, reportRow("d", "", "", "timeout"))
+
+	done, err = loadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"a": true, "c": true}
+	if !reflect.DeepEqual(done, want) {
+		t.Errorf("checkpoint = %v, want %v (failed rows retried)", done, want)
+	}
+}
+
+func TestWriteSummaryCountsRetriedIDsOnce(t *testing.T) {
+	dir := t.TempDir()
+	reportPath, summaryPath := filepath.Join(dir, "report.csv"), filepath.Join(dir, "summary.csv")
+	writeReport(t, reportPath,
+		reportRow("a", "REAL", "REAL", ""),
+		reportRow("b", "", "", "status 502"),
+		reportRow("c", "", "", "timeout"),
+	)
+	// The resumed run retries b and c; b now succeeds and flips, c fails again
+	writeReport(t, reportPath,
+		reportRow("b", "REAL", "FAKE", ""),
+		reportRow("c", "", "", "timeout"),
+	)
+
+	if err := writeSummary(reportPath, summaryPath); err != nil {
+		t.Fatalf("writeSummary: %v", err)
+	}
+	f, err := os.Open(summaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	for _, row := range rows[1:] {
+		got[row[0]] = row[1]
+	}
+	want := map[string]string{"total": "3", "rescored": "2", "errors": "1", "flipped": "1", "flipped_real_to_fake": "1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("summary = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+// Command mockml is a stand-in for the Python ML service, for running the
+// full stack locally without a GPU.
+//
+// It serves the contracts the API and workers call, with deterministic
+// answers: the same text always gets the same verdict.
+//
+//	GET  /health          {status, model_version, device}
+//	POST /predict         {text, model, context} -> verdict
+//	POST /predict/url     {url, model} -> verdict for the URL itself
+//	POST /extract_claims  {text, max_claims} -> {claims}
+//	POST /summarize       {text, max_sentences} -> {summary}
+//
+// Usage:
+//
+//	mockml --addr :8000 --latency 200ms --error-rate 0.05
+//	ML_SERVICE_URL=http://localhost:8000 api
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+func main() {
+	logger := log.New(os.Stdout, "MOCKML: ", log.LstdFlags)
+	fs := flag.NewFlagSet("mockml", flag.ExitOnError)
+	addr := fs.String("addr", ":8000", "listen address")
+	verdict := fs.String("verdict", verdictHash, "hash (verdict derived from the text), FAKE or REAL")
+	confidence := fs.Float64("confidence", 0.9, "confidence of fixed FAKE/REAL verdicts")
+	fakeWords := fs.String("fake-words", "", "comma-separated words that make any text FAKE")
+	modelVersion := fs.String("model-version", "mock-1", "model_version reported when the request names no model")
+	latency := fs.Duration("latency", 0, "delay added to every response")
+	jitter := fs.Duration("jitter", 0, "random extra delay, up to this much")
+	errorRate := fs.Float64("error-rate", 0, "fraction of POST requests answered with 500")
+	failModels := fs.String("fail-models", "", "comma-separated model names that always answer 500, to exercise ML_FALLBACK_MODEL")
+	apiKey := fs.String("api-key", os.Getenv("ML_SERVICE_API_KEY"), "bearer token required on every request; empty accepts any")
+	seed := fs.Int64("seed", 1, "seed for jitter and error injection")
+	fs.Parse(os.Args[1:])
+
+	srv, err := newServer(config{
+		verdict:      strings.ToUpper(*verdict),
+		confidence:   *confidence,
+		fakeWords:    splitList(strings.ToLower(*fakeWords)),
+		modelVersion: *modelVersion,
+		latency:      *latency,
+		jitter:       *jitter,
+		errorRate:    *errorRate,
+		failModels:   splitList(*failModels),
+		apiKey:       *apiKey,
+		seed:         *seed,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mockml: %v\n", err)
+		os.Exit(2)
+	}
+
+	logger.Printf("Serving mock ML contracts on %s (verdict %s, latency %s, error rate %.2f)", *addr, *verdict, *latency, *errorRate)
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           srv.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if err := server.ListenAndServe(); err != nil {
+		logger.Fatalf("Server failed: %v", err)
+	}
+}
+
+// splitList parses a comma-separated flag, dropping empty entries.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
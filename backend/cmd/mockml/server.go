@@ -0,0 +1,227 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// verdictHash derives each verdict from a hash of the text.
+const verdictHash = "HASH"
+
+// defaultMaxClaims applies when /extract_claims is called without max_claims.
+const defaultMaxClaims = 3
+
+type config struct {
+	verdict      string
+	confidence   float64
+	fakeWords    []string
+	modelVersion string
+	latency      time.Duration
+	jitter       time.Duration
+	errorRate    float64
+	failModels   []string
+	apiKey       string
+	seed         int64
+}
+
+type server struct {
+	cfg config
+
+	mu  sync.Mutex
+	rng *rand.Rand // jitter and error injection
+}
+
+func newServer(cfg config) (*server, error) {
+	switch cfg.verdict {
+	case verdictHash, domain.LabelFake, domain.LabelReal:
+	default:
+		return nil, fmt.Errorf("verdict must be hash, FAKE or REAL, got %q", cfg.verdict)
+	}
+	if cfg.confidence < 0.5 || cfg.confidence > 1 {
+		return nil, fmt.Errorf("confidence must be between 0.5 and 1")
+	}
+	if cfg.errorRate < 0 || cfg.errorRate > 1 {
+		return nil, fmt.Errorf("error-rate must be between 0 and 1")
+	}
+	return &server{cfg: cfg, rng: rand.New(rand.NewSource(cfg.seed))}, nil
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", s.health)
+	mux.HandleFunc("POST /predict", s.predict)
+	mux.HandleFunc("POST /predict/url", s.predictURL)
+	mux.HandleFunc("POST /extract_claims", s.extractClaims)
+	mux.HandleFunc("POST /summarize", s.summarize)
+	return s.inject(mux)
+}
+
+// inject checks the bearer token, then delays the response and fails a
+// share of POST requests as configured.
+func (s *server) inject(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.cfg.apiKey != "" && r.Header.Get("Authorization") != "Bearer "+s.cfg.apiKey {
+			writeError(w, http.StatusUnauthorized, "invalid API key")
+			return
+		}
+
+		s.mu.Lock()
+		delay := s.cfg.latency
+		if s.cfg.jitter > 0 {
+			delay += time.Duration(s.rng.Int63n(int64(s.cfg.jitter)))
+		}
+		fail := r.Method == http.MethodPost && s.rng.Float64() < s.cfg.errorRate
+		s.mu.Unlock()
+
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		if fail {
+			writeError(w, http.StatusInternalServerError, "injected failure")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *server) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, service.MLServiceInfo{Status: "healthy", ModelVersion: s.cfg.modelVersion, Device: "mock"})
+}
+
+func (s *server) predict(w http.ResponseWriter, r *http.Request) {
+	var req service.MLPredictionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		writeError(w, http.StatusBadRequest, "text cannot be empty")
+		return
+	}
+	s.respondVerdict(w, req.Model, req.Text, "")
+}
+
+func (s *server) predictURL(w http.ResponseWriter, r *http.Request) {
+	var req service.MLURLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.URL) == "" {
+		writeError(w, http.StatusUnprocessableEntity, "url is required")
+		return
+	}
+	s.respondVerdict(w, req.Model, req.URL, req.URL)
+}
+
+func (s *server) respondVerdict(w http.ResponseWriter, model, text, sourceURL string) {
+	if slices.Contains(s.cfg.failModels, model) {
+		writeError(w, http.StatusInternalServerError, "model "+model+" is failing")
+		return
+	}
+	fake := math.Round(s.fakeProbability(text)*10000) / 10000
+	real := math.Round((1-fake)*10000) / 10000
+	resp := service.MLPredictionResponse{
+		Result:          domain.LabelReal,
+		Confidence:      real,
+		ModelVersion:    s.cfg.modelVersion,
+		FakeProbability: fake,
+		RealProbability: real,
+		SourceURL:       sourceURL,
+	}
+	if fake >= 0.5 {
+		resp.Result, resp.Confidence = domain.LabelFake, fake
+	}
+	if model != "" {
+		resp.ModelVersion = model
+	}
+	if sourceURL != "" {
+		resp.ExtractedTextPreview = "Mock article text for " + sourceURL // the mock does not fetch URLs
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// fakeProbability is the configured fixed verdict, or a probability in
+// [0.05, 0.95] derived from the text so repeated calls agree.
+func (s *server) fakeProbability(text string) float64 {
+	lower := strings.ToLower(text)
+	for _, word := range s.cfg.fakeWords {
+		if strings.Contains(lower, word) {
+			return s.cfg.confidence
+		}
+	}
+	switch s.cfg.verdict {
+	case domain.LabelFake:
+		return s.cfg.confidence
+	case domain.LabelReal:
+		return 1 - s.cfg.confidence
+	}
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(text), " ")))
+	return 0.05 + 0.9*float64(binary.BigEndian.Uint16(sum[:2]))/65535
+}
+
+// extractClaimsRequest is the payload for POST /extract_claims. The
+// Python service has no such endpoint yet; the mock answers with the
+// API's own claim extraction so a future client has something to call.
+type extractClaimsRequest struct {
+	Text      string `json:"text"`
+	MaxClaims int    `json:"max_claims"`
+}
+
+func (s *server) extractClaims(w http.ResponseWriter, r *http.Request) {
+	var req extractClaimsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "invalid request body")
+		return
+	}
+	if req.MaxClaims <= 0 {
+		req.MaxClaims = defaultMaxClaims
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"claims": service.ExtractClaims(req.Text, req.MaxClaims)})
+}
+
+// summarize returns the text's first sentences.
+func (s *server) summarize(w http.ResponseWriter, r *http.Request) {
+	var req service.MLSummarizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		writeError(w, http.StatusBadRequest, "text cannot be empty")
+		return
+	}
+	req.MaxSentences = min(max(req.MaxSentences, 1), 5)
+	var summary []string
+	rest := strings.Join(strings.Fields(req.Text), " ")
+	for len(summary) < req.MaxSentences && rest != "" {
+		end := strings.IndexAny(rest, ".!?")
+		if end < 0 {
+			end = len(rest) - 1
+		}
+		summary = append(summary, strings.TrimSpace(rest[:end+1]))
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+	writeJSON(w, http.StatusOK, service.MLSummarizeResponse{Summary: strings.Join(summary, " ")})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers in FastAPI's error shape, like the real service.
+func writeError(w http.ResponseWriter, code int, detail string) {
+	writeJSON(w, code, map[string]string{"detail": detail})
+}
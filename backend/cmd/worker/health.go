@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// healthRoutes serves the worker's own endpoints:
+//
+//	GET /healthz  process is up
+//	GET /readyz   ML service reachable and the queue answered recently (503 otherwise)
+//	GET /stats    job counters
+func (w *worker) healthRoutes(news *service.NewsService) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /healthz", func(rw http.ResponseWriter, r *http.Request) {
+		writeJSON(rw, http.StatusOK, map[string]string{"status": "ok"})
+	})
+
+	mux.HandleFunc("GET /readyz", func(rw http.ResponseWriter, r *http.Request) {
+		status, code := service.HealthOK, http.StatusOK
+		ml := map[string]interface{}{"status": service.HealthOK}
+		if err := news.CheckMLHealth(); err != nil {
+			ml = map[string]interface{}{"status": service.HealthDown, "detail": err.Error()}
+			status, code = service.HealthDown, http.StatusServiceUnavailable
+		}
+
+		queue := map[string]interface{}{"status": service.HealthOK}
+		staleAfter := 3*w.pollInterval + 30*time.Second
+		if last := w.lastContact.Load(); last == 0 || time.Since(time.Unix(0, last)) > staleAfter {
+			queue = map[string]interface{}{"status": service.HealthDown}
+			if msg, ok := w.lastError.Load().(string); ok {
+				queue["detail"] = msg
+			}
+			status, code = service.HealthDown, http.StatusServiceUnavailable
+		}
+
+		writeJSON(rw, code, map[string]interface{}{
+			"status":  status,
+			"ml":      ml,
+			"queue":   queue,
+			"startup": w.startup.Report(),
+		})
+	})
+
+	mux.HandleFunc("GET /stats", func(rw http.ResponseWriter, r *http.Request) {
+		writeJSON(rw, http.StatusOK, map[string]interface{}{
+			"worker_id":   w.queue.WorkerID(),
+			"concurrency": w.concurrency,
+			"running":     w.running.Load(),
+			"completed":   w.completed.Load(),
+			"failed":      w.failed.Load(),
+		})
+	})
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,150 @@
+// Command worker runs background jobs for the fake news detection API.
+//
+// Workers lease jobs from the API's queue (POST /api/internal/jobs/lease),
+// run them against the ML service and report the results, so API pods stay
+// latency-focused and workers scale independently. Each worker serves its
+// own health endpoints.
+//
+// Usage:
+//
+//	WORKER_TOKEN=... WORKER_API_URL=http://api:8080 ML_SERVICE_URL=http://ml:8000 worker
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+	"github.com/Naman30903/Final-Year-Project/pkg/client"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	logger := log.New(os.Stdout, "WORKER: ", log.LstdFlags)
+	if err := godotenv.Load(); err == nil {
+		logger.Printf("Loaded environment from .env")
+	}
+
+	workerToken := os.Getenv("WORKER_TOKEN")
+	if workerToken == "" {
+		logger.Fatalf("WORKER_TOKEN is required")
+	}
+	workerID := getEnv("WORKER_ID", defaultWorkerID())
+	apiURL := getEnv("WORKER_API_URL", "http://localhost:8080")
+
+	mlClient := service.NewMLClient(getEnv("ML_SERVICE_URL", "http://localhost:8000")).
+		WithAPIKey(os.Getenv("ML_SERVICE_API_KEY")).
+		WithPaths(getEnv("ML_PREDICT_PATH", "/predict"), getEnv("ML_HEALTH_PATH", "/health")).
+		WithCompression(getEnvInt("ML_COMPRESS_MIN_BYTES", service.DefaultMLCompressMinBytes))
+	if fallbackModel := os.Getenv("ML_FALLBACK_MODEL"); fallbackModel != "" {
+		mlClient.WithFallback(fallbackModel, os.Getenv("ML_FALLBACK_URL"),
+			time.Duration(getEnvInt("ML_PRIMARY_TIMEOUT_MS", 10000))*time.Millisecond)
+	}
+	// Workers only classify; predictions are not stored here.
+	newsService := service.NewNewsService(mlClient, service.NewScraperService(), memory.NewPredictionRepository()).
+		WithTruncator(service.NewTruncator(getEnv("ML_TRUNCATION_STRATEGY", domain.TruncationNone),
+			getEnvInt("ML_MAX_INPUT_CHARS", service.DefaultMaxInputChars)))
+
+	w := &worker{
+		queue: client.New(apiURL, client.WithToken(workerToken), client.WithHTTPClient(&http.Client{Timeout: 30 * time.Second})).
+			Jobs(workerID),
+		executors: map[string]executor{
+			domain.JobKindEvaluation: evaluationExecutor(newsService),
+		},
+		concurrency:  getEnvInt("WORKER_CONCURRENCY", 2),
+		pollInterval: time.Duration(getEnvInt("WORKER_POLL_INTERVAL_MS", 2000)) * time.Millisecond,
+		heartbeat:    time.Duration(getEnvInt("WORKER_HEARTBEAT_MS", 15000)) * time.Millisecond,
+		logger:       logger,
+	}
+
+	// Wait for the ML service and the API before polling, so a worker that
+	// boots first does not fail its first jobs. Past the deadline it polls
+	// anyway and keeps checking in the background.
+	w.startup = service.NewStartupOrchestrator(
+		service.Dependency{Name: "ml", Check: mlClient.HealthCheckContext},
+		service.Dependency{Name: "api", Check: apiHealthCheck(apiURL + "/api/health")},
+	).WithDeadline(time.Duration(getEnvInt("STARTUP_DEADLINE", 60)) * time.Second)
+
+	srv := &http.Server{
+		Addr:         getEnv("WORKER_HEALTH_ADDR", ":8081"),
+		Handler:      w.healthRoutes(newsService),
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+	go func() {
+		logger.Printf("Health endpoints on %s", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatalf("Health server failed: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	w.startup.Start(ctx)
+	if report := w.startup.Wait(ctx); report.Status == service.StartupReady {
+		logger.Printf("Dependencies ready")
+	}
+	logger.Printf("Worker %s polling %s for %v jobs with %d slots", workerID, apiURL, w.kinds(), w.concurrency)
+	w.run(ctx)
+
+	logger.Println("Shutting down worker...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv.Shutdown(shutdownCtx)
+	logger.Println("Worker exited")
+}
+
+// apiHealthCheck reports whether the API answers its health endpoint.
+func apiHealthCheck(endpoint string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+func defaultWorkerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "worker"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// getEnv gets an environment variable or returns a default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvInt reads an integer from the environment
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+	"github.com/Naman30903/Final-Year-Project/pkg/client"
+)
+
+// executor runs one kind of job. report sends progress with the next
+// heartbeat.
+type executor func(ctx context.Context, job *domain.Job, report func(progress interface{})) (interface{}, error)
+
+// worker leases jobs from the API and runs them.
+type worker struct {
+	queue        *client.JobQueue
+	executors    map[string]executor
+	concurrency  int
+	pollInterval time.Duration
+	heartbeat    time.Duration
+	logger       *log.Logger
+	startup      *service.StartupOrchestrator
+
+	// Health and counters for the worker's own endpoints
+	lastContact atomic.Int64 // unix nanos of the last successful queue call
+	lastError   atomic.Value // string
+	running     atomic.Int64
+	completed   atomic.Int64
+	failed      atomic.Int64
+}
+
+func (w *worker) kinds() []string {
+	kinds := make([]string, 0, len(w.executors))
+	for kind := range w.executors {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+// run polls for jobs with concurrency slots until ctx is cancelled, then
+// waits for running jobs to finish.
+func (w *worker) run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				if !w.runOne(ctx) {
+					select {
+					case <-ctx.Done():
+					case <-time.After(w.pollInterval):
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// runOne leases and runs a single job, reporting whether there was one.
+func (w *worker) runOne(ctx context.Context) bool {
+	job, err := w.queue.Lease(ctx, w.kinds())
+	if err != nil {
+		if ctx.Err() == nil {
+			w.recordError(err)
+		}
+		return false
+	}
+	w.lastContact.Store(time.Now().UnixNano())
+	if job == nil {
+		return false
+	}
+
+	w.running.Add(1)
+	defer w.running.Add(-1)
+	w.logger.Printf("Running %s job %s (attempt %d)", job.Kind, job.ID, job.Attempts)
+
+	// Jobs finish even if shutdown starts; their lease would otherwise lapse.
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	var mu sync.Mutex
+	var progress interface{}
+	report := func(p interface{}) {
+		mu.Lock()
+		progress = p
+		mu.Unlock()
+	}
+	go w.keepAlive(jobCtx, job.ID, func() interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		return progress
+	})
+
+	result, err := w.execute(jobCtx, job, report)
+	cancel()
+
+	if err != nil {
+		w.failed.Add(1)
+		failure := classifyFailure(err)
+		w.logger.Printf("%s job %s failed (%s): %v", job.Kind, job.ID, failure, err)
+		if err := w.queue.Fail(context.WithoutCancel(ctx), job.ID, err, failure); err != nil {
+			w.recordError(err)
+		}
+		return true
+	}
+	if err := w.queue.Complete(context.WithoutCancel(ctx), job.ID, result); err != nil {
+		w.failed.Add(1)
+		w.recordError(err)
+		return true
+	}
+	w.completed.Add(1)
+	return true
+}
+
+// classifyFailure tells failures that should clear once the ML service
+// recovers from those no retry can fix.
+func classifyFailure(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, domain.ErrMLServiceUnavailable),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr):
+		return client.FailureTransient
+	}
+	return client.FailurePermanent
+}
+
+func (w *worker) execute(ctx context.Context, job *domain.Job, report func(interface{})) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	exec, ok := w.executors[job.Kind]
+	if !ok {
+		return nil, fmt.Errorf("no executor for job kind %q", job.Kind)
+	}
+	return exec(ctx, job, report)
+}
+
+// keepAlive heartbeats the job until ctx ends.
+func (w *worker) keepAlive(ctx context.Context, id string, progress func() interface{}) {
+	ticker := time.NewTicker(w.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.queue.Heartbeat(ctx, id, progress()); err != nil && ctx.Err() == nil {
+				w.recordError(err)
+			}
+		}
+	}
+}
+
+func (w *worker) recordError(err error) {
+	w.lastError.Store(err.Error())
+	w.logger.Printf("Queue error: %v", err)
+}
+
+// evaluationExecutor scores benchmark datasets.
+func evaluationExecutor(news *service.NewsService) executor {
+	return func(ctx context.Context, job *domain.Job, report func(interface{})) (interface{}, error) {
+		return service.RunEvaluationJob(ctx, news, job.Payload, func(p service.EvaluationProgress) {
+			report(p)
+		})
+	}
+}
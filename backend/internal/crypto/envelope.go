@@ -0,0 +1,199 @@
+// Package crypto encrypts sensitive fields at rest with envelope
+// encryption. Each value is sealed with its own random data key, and the
+// data key is sealed with a master key from a Keyring. Old master keys
+// stay in the keyring to decrypt values until they are rotated to the
+// current one.
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Prefix marks an encrypted value: enc:v1:<key id>:<sealed data key>:<sealed value>.
+const Prefix = "enc:v1:"
+
+// MasterKeySize is the length of a master key: AES-256.
+const MasterKeySize = 32
+
+var (
+	ErrUnknownKey        = errors.New("value is encrypted with an unknown master key")
+	ErrInvalidCiphertext = errors.New("invalid encrypted value")
+	ErrInvalidKeyring    = errors.New("invalid encryption keys")
+)
+
+var encoding = base64.RawURLEncoding
+
+// Keyring holds the master keys. Values are encrypted with the current
+// key and decrypted with whichever key sealed them.
+type Keyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring creates a keyring encrypting with keys[current].
+func NewKeyring(current string, keys map[string][]byte) (*Keyring, error) {
+	k := &Keyring{current: current, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("%w: key id %q must be non-empty without colons", ErrInvalidKeyring, id)
+		}
+		if len(key) != MasterKeySize {
+			return nil, fmt.Errorf("%w: key %s is %d bytes, want %d", ErrInvalidKeyring, id, len(key), MasterKeySize)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[id] = aead
+	}
+	if _, ok := k.keys[current]; !ok {
+		return nil, fmt.Errorf("%w: current key %q is not in the keyring", ErrInvalidKeyring, current)
+	}
+	return k, nil
+}
+
+// ParseKeyring reads "id:base64key,id:base64key" (standard or URL base64
+// of 32 bytes). The first key is current; the rest only decrypt.
+func ParseKeyring(spec string) (*Keyring, error) {
+	keys := make(map[string][]byte)
+	current := ""
+	for _, part := range strings.Split(spec, ",") {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: expected id:base64key", ErrInvalidKeyring)
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %s is not base64", ErrInvalidKeyring, id)
+		}
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("%w: key %s is listed twice", ErrInvalidKeyring, id)
+		}
+		keys[id] = key
+		if current == "" {
+			current = id
+		}
+	}
+	return NewKeyring(current, keys)
+}
+
+// CurrentKey returns the ID of the key new values are encrypted with.
+func (k *Keyring) CurrentKey() string {
+	return k.current
+}
+
+// Encrypt seals plaintext under a fresh data key. Empty values stay empty.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	dataKey := make([]byte, MasterKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealedKey, err := seal(k.keys[k.current], dataKey, []byte(k.current))
+	if err != nil {
+		return "", err
+	}
+	sealedValue, err := seal(data, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+	return Prefix + k.current + ":" + encoding.EncodeToString(sealedKey) + ":" + encoding.EncodeToString(sealedValue), nil
+}
+
+// Decrypt opens a value from Encrypt. Values without the prefix were
+// stored before encryption was enabled and are returned as they are.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	rest, ok := strings.CutPrefix(value, Prefix)
+	if !ok {
+		return value, nil
+	}
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 {
+		return "", ErrInvalidCiphertext
+	}
+	master, ok := k.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, parts[0])
+	}
+	sealedKey, err1 := encoding.DecodeString(parts[1])
+	sealedValue, err2 := encoding.DecodeString(parts[2])
+	if err1 != nil || err2 != nil {
+		return "", ErrInvalidCiphertext
+	}
+	dataKey, err := open(master, sealedKey, []byte(parts[0]))
+	if err != nil {
+		return "", err
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	plaintext, err := open(data, sealedValue, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether value should be re-encrypted: it is
+// plaintext, or sealed with a key other than the current one.
+func (k *Keyring) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	return !strings.HasPrefix(value, Prefix+k.current+":")
+}
+
+// IsEncrypted reports whether value carries the encryption prefix.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal returns nonce || ciphertext.
+func seal(aead cipher.AEAD, plaintext, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+func open(aead cipher.AEAD, sealed, additional []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additional)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
+
+func decodeKey(s string) ([]byte, error) {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if key, err := enc.DecodeString(s); err == nil {
+			return key, nil
+		}
+	}
+	return nil, errors.New("not base64")
+}
@@ -0,0 +1,111 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, MasterKeySize)
+}
+
+func TestKeyringRoundTrip(t *testing.T) {
+	keyring, err := NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+
+	sealed, err := keyring.Encrypt("reader@example.com")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !strings.HasPrefix(sealed, Prefix+"k1:") || strings.Contains(sealed, "reader") {
+		t.Fatalf("Encrypt() = %q, want an enc:v1:k1 value without the plaintext", sealed)
+	}
+	if again, _ := keyring.Encrypt("reader@example.com"); again == sealed {
+		t.Error("Encrypt() should use a fresh data key and nonce each time")
+	}
+
+	opened, err := keyring.Decrypt(sealed)
+	if err != nil || opened != "reader@example.com" {
+		t.Errorf("Decrypt() = %q, %v", opened, err)
+	}
+
+	if plain, err := keyring.Decrypt("legacy@example.com"); err != nil || plain != "legacy@example.com" {
+		t.Errorf("Decrypt(plaintext) = %q, %v; want it unchanged", plain, err)
+	}
+	if empty, _ := keyring.Encrypt(""); empty != "" {
+		t.Errorf("Encrypt(\"\") = %q, want empty", empty)
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	old, _ := NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	sealed, _ := old.Encrypt("secret")
+
+	rotated, err := NewKeyring("k2", map[string][]byte{"k1": testKey(1), "k2": testKey(2)})
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	if opened, err := rotated.Decrypt(sealed); err != nil || opened != "secret" {
+		t.Errorf("Decrypt() with retired key = %q, %v", opened, err)
+	}
+	if !rotated.NeedsRotation(sealed) || !rotated.NeedsRotation("secret") {
+		t.Error("values under k1 and plaintext should need rotation")
+	}
+	resealed, _ := rotated.Encrypt("secret")
+	if rotated.NeedsRotation(resealed) || rotated.NeedsRotation("") {
+		t.Error("values under the current key should not need rotation")
+	}
+
+	newOnly, _ := NewKeyring("k2", map[string][]byte{"k2": testKey(2)})
+	if _, err := newOnly.Decrypt(sealed); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Decrypt() without k1 error = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestDecryptRejectsTampering(t *testing.T) {
+	keyring, _ := NewKeyring("k1", map[string][]byte{"k1": testKey(1)})
+	sealed, _ := keyring.Encrypt("secret")
+	parts := strings.Split(sealed, ":")
+
+	tampered := []string{
+		Prefix + "k1:garbage",
+		strings.Join(append(parts[:4:4], parts[4][:len(parts[4])-2]+"AA"), ":"),
+		strings.Join(append(parts[:3:3], parts[4], parts[3]), ":"),
+	}
+	for _, value := range tampered {
+		if _, err := keyring.Decrypt(value); !errors.Is(err, ErrInvalidCiphertext) {
+			t.Errorf("Decrypt(%q) error = %v, want ErrInvalidCiphertext", value, err)
+		}
+	}
+}
+
+func TestParseKeyring(t *testing.T) {
+	k1 := base64.StdEncoding.EncodeToString(testKey(1))
+	k2 := base64.RawURLEncoding.EncodeToString(testKey(2))
+
+	keyring, err := ParseKeyring("k2:" + k2 + ", k1:" + k1)
+	if err != nil {
+		t.Fatalf("ParseKeyring() error = %v", err)
+	}
+	if keyring.CurrentKey() != "k2" {
+		t.Errorf("CurrentKey() = %q, want k2", keyring.CurrentKey())
+	}
+
+	invalid := []string{
+		"",
+		"k1",
+		"k1:not-base64!",
+		"k1:" + base64.StdEncoding.EncodeToString([]byte("short")),
+		"k1:" + k1 + ",k1:" + k1,
+	}
+	for _, spec := range invalid {
+		if _, err := ParseKeyring(spec); !errors.Is(err, ErrInvalidKeyring) {
+			t.Errorf("ParseKeyring(%q) error = %v, want ErrInvalidKeyring", spec, err)
+		}
+	}
+}
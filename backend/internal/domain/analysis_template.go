@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Analysis template scopes
+const (
+	TemplateScopeUser = "user" // visible to its creator only
+	TemplateScopeOrg  = "org"  // shared with the creator's organization
+)
+
+// maxTemplateName bounds a template's name
+const maxTemplateName = 100
+
+// TemplateSettings are the analysis options a template fills in. Unset
+// fields leave the request (and the caller's saved defaults) alone.
+type TemplateSettings struct {
+	Truncation      string `json:"truncation,omitempty"`
+	Depth           *int   `json:"depth,omitempty"`
+	IncludeSummary  *bool  `json:"include_summary,omitempty"`
+	IncludeEvidence *bool  `json:"include_evidence,omitempty"`
+	Verbosity       string `json:"verbosity,omitempty"`
+	Model           string `json:"model,omitempty"`    // model version pin
+	Language        string `json:"language,omitempty"` // ISO 639-1 hint, skips detection
+}
+
+// AnalysisTemplate is a named analysis configuration referenced by
+// template_id in analysis requests.
+type AnalysisTemplate struct {
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	Scope     string           `json:"scope"`
+	UserID    string           `json:"user_id,omitempty"` // owner of user templates
+	OrgID     string           `json:"org_id,omitempty"`  // owner of org templates
+	Settings  TemplateSettings `json:"settings"`
+	CreatedBy string           `json:"created_by"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// Validate normalizes the name and settings and rejects unknown values
+func (t *AnalysisTemplate) Validate() error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidTemplate)
+	}
+	if utf8.RuneCountInString(t.Name) > maxTemplateName {
+		return fmt.Errorf("%w: name is longer than %d characters", ErrInvalidTemplate, maxTemplateName)
+	}
+	if t.Scope == "" {
+		t.Scope = TemplateScopeUser
+	}
+	if t.Scope != TemplateScopeUser && t.Scope != TemplateScopeOrg {
+		return fmt.Errorf("%w: scope must be user or org", ErrInvalidTemplate)
+	}
+
+	s := &t.Settings
+	s.Truncation = strings.ToLower(strings.TrimSpace(s.Truncation))
+	s.Verbosity = strings.ToLower(strings.TrimSpace(s.Verbosity))
+	s.Model = strings.TrimSpace(s.Model)
+	s.Language = strings.ToLower(strings.TrimSpace(s.Language))
+	if s.Truncation != "" && !IsValidTruncationStrategy(s.Truncation) {
+		return fmt.Errorf("%w: unknown truncation strategy %q", ErrInvalidTemplate, s.Truncation)
+	}
+	if s.Verbosity != "" && !IsValidVerbosity(s.Verbosity) {
+		return fmt.Errorf("%w: verbosity must be one of minimal, standard, full", ErrInvalidTemplate)
+	}
+	if s.Depth != nil && (*s.Depth < 0 || *s.Depth > 1) {
+		return fmt.Errorf("%w: depth must be 0 or 1", ErrInvalidTemplate)
+	}
+	if s.Language != "" && !IsValidLanguageHint(s.Language) {
+		return fmt.Errorf("%w: language must be an ISO 639-1 code", ErrInvalidTemplate)
+	}
+	return nil
+}
+
+// Apply fills the options of req that the caller did not send, as
+// AnalysisDefaults.Apply does. A template's depth only applies to URL
+// requests.
+func (s *TemplateSettings) Apply(req *AnalysisRequest, explicit map[string]bool) {
+	if !explicit["truncation"] && s.Truncation != "" {
+		req.Truncation = s.Truncation
+	}
+	if !explicit["depth"] && s.Depth != nil && req.Type == "url" {
+		req.Depth = *s.Depth
+	}
+	if !explicit["include_summary"] && s.IncludeSummary != nil {
+		req.IncludeSummary = *s.IncludeSummary
+	}
+	if !explicit["include_evidence"] && s.IncludeEvidence != nil {
+		req.IncludeEvidence = *s.IncludeEvidence
+	}
+	if !explicit["verbosity"] && s.Verbosity != "" {
+		req.Verbosity = s.Verbosity
+	}
+	if !explicit["model"] && s.Model != "" {
+		req.Model = s.Model
+	}
+	if !explicit["language"] && s.Language != "" {
+		req.Language = s.Language
+	}
+}
+
+// Fields returns the JSON names of the options the template sets.
+func (s *TemplateSettings) Fields() []string {
+	var fields []string
+	for name, set := range map[string]bool{
+		"truncation":       s.Truncation != "",
+		"depth":            s.Depth != nil,
+		"include_summary":  s.IncludeSummary != nil,
+		"include_evidence": s.IncludeEvidence != nil,
+		"verbosity":        s.Verbosity != "",
+		"model":            s.Model != "",
+		"language":         s.Language != "",
+	} {
+		if set {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,34 @@
+package domain
+
+// Annotation sources
+const (
+	AnnotationSourceText    = "text"    // the submitted text
+	AnnotationSourceExcerpt = "excerpt" // title, description and claims of a URL analysis; article bodies are not stored
+)
+
+// Annotation reasons
+const (
+	ReasonClickbait    = "clickbait"
+	ReasonShouting     = "shouting"
+	ReasonExclamation  = "exclamation"
+	ReasonRefutedClaim = "refuted_claim"
+)
+
+// AnnotatedPassage is a sentence that pushed the verdict towards FAKE.
+type AnnotatedPassage struct {
+	Paragraph int      `json:"paragraph"` // index into the annotated paragraphs
+	Text      string   `json:"text"`
+	Intensity float64  `json:"intensity"` // 0-1; higher passages weighed more
+	Reasons   []string `json:"reasons"`
+}
+
+// Annotation is a prediction's stored text as HTML, with suspicious
+// sentences wrapped in <mark> elements.
+type Annotation struct {
+	PredictionID string             `json:"prediction_id"`
+	Result       string             `json:"result"`
+	Confidence   float64            `json:"confidence"`
+	Source       string             `json:"source"`
+	HTML         string             `json:"html"`
+	Passages     []AnnotatedPassage `json:"passages"`
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// Audit sampling strategies
+const (
+	AuditStrategyStratified = "stratified" // equal draws per label/confidence stratum
+	AuditStrategyUniform    = "uniform"    // simple random sample
+)
+
+// AuditSampleRequest describes a spot-check sample of recent predictions.
+// The same request over the same unaudited predictions draws the same
+// sample.
+type AuditSampleRequest struct {
+	Size     int    `json:"size"`
+	Strategy string `json:"strategy"`
+	Seed     int64  `json:"seed"`
+	Days     int    `json:"days"` // how far back to sample from
+}
+
+// AuditSampleItem is one prediction drawn for a manual quality audit.
+type AuditSampleItem struct {
+	PredictionID string     `json:"prediction_id"`
+	Result       string     `json:"result"`
+	Confidence   float64    `json:"confidence"`
+	Stratum      string     `json:"stratum"` // label and confidence decile, e.g. FAKE|0.6
+	Weight       float64    `json:"weight"`  // predictions the item stands for; inverse of its draw probability
+	AuditedBy    string     `json:"audited_by,omitempty"`
+	AuditedAt    *time.Time `json:"audited_at,omitempty"`
+}
+
+// AuditSample is a recorded draw of predictions for a manual audit.
+type AuditSample struct {
+	ID         string             `json:"id"`
+	CreatedAt  time.Time          `json:"created_at"`
+	Request    AuditSampleRequest `json:"request"`
+	Since      time.Time          `json:"since"`
+	Candidates int                `json:"candidates"` // unaudited predictions sampled from
+	Strata     map[string]int     `json:"strata"`     // candidates per stratum
+	Audited    int                `json:"audited"`
+	Items      []AuditSampleItem  `json:"items"`
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Bulk delete job statuses
+const (
+	BulkDeleteRunning   = "running"
+	BulkDeleteCompleted = "completed"
+	BulkDeleteFailed    = "failed"
+)
+
+// BulkDeletePreview is a dry run of a bulk delete: what the filters match
+// before anything is removed.
+type BulkDeletePreview struct {
+	Filters SearchFilters `json:"filters"`
+	Matched int           `json:"matched"`
+	Pinned  int           `json:"pinned"` // matched but kept; pinned predictions are never bulk deleted
+	Sample  []*Prediction `json:"sample"` // newest matches that would be deleted
+}
+
+// BulkDelete is the record of one bulk delete job, kept as its audit
+// trail.
+type BulkDelete struct {
+	ID          string        `json:"id"`
+	Status      string        `json:"status"`
+	Filters     SearchFilters `json:"filters"`
+	RequestedBy string        `json:"requested_by"`
+	Reason      string        `json:"reason,omitempty"`
+	Matched     int           `json:"matched"`
+	Deleted     int           `json:"deleted"`
+	Skipped     int           `json:"skipped"` // pinned
+	Failed      int           `json:"failed"`
+	DeletedIDs  []string      `json:"deleted_ids,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	FinishedAt  *time.Time    `json:"finished_at,omitempty"`
+}
+
+// Progress returns the fraction of matched predictions handled so far.
+func (d *BulkDelete) Progress() float64 {
+	if d.Matched == 0 {
+		return 1
+	}
+	return float64(d.Deleted+d.Skipped+d.Failed) / float64(d.Matched)
+}
+
+// ValidateBulkDelete checks filters for a bulk delete. At least one filter
+// is required, so an empty body cannot wipe the history.
+func ValidateBulkDelete(f SearchFilters) error {
+	if f == (SearchFilters{}) {
+		return fmt.Errorf("%w: at least one filter is required", ErrInvalidBulkDelete)
+	}
+	if label := strings.ToUpper(f.Label); label != "" && label != LabelFake && label != LabelReal {
+		return fmt.Errorf("%w: label must be FAKE or REAL", ErrInvalidBulkDelete)
+	}
+	if t := f.Type; t != "" && t != "text" && t != "url" {
+		return fmt.Errorf("%w: type must be text or url", ErrInvalidBulkDelete)
+	}
+	if err := f.Query().Validate(); err != nil {
+		return fmt.Errorf("%w: invalid confidence or date range", ErrInvalidBulkDelete)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package domain
+
+// Evidence stances toward a claim
+const (
+	StanceSupports = "supports"
+	StanceRefutes  = "refutes"
+	StanceNeutral  = "neutral" // related, but takes no side
+)
+
+// Evidence is a snippet from another source that bears on a claim.
+type Evidence struct {
+	Title   string  `json:"title,omitempty"`
+	URL     string  `json:"url"`
+	Snippet string  `json:"snippet"`
+	Source  string  `json:"source,omitempty"`
+	Stance  string  `json:"stance"`
+	Score   float64 `json:"score"` // relevance to the claim, 0-1
+}
+
+// Claim is a check-worthy statement extracted from an article, with the
+// evidence retrieved for it.
+type Claim struct {
+	Text     string     `json:"text"`
+	Evidence []Evidence `json:"evidence"`
+	Error    string     `json:"error,omitempty"` // set when evidence retrieval failed
+}
@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// CorpusRequest describes a research corpus to sample from stored
+// predictions. The same request over the same predictions produces the
+// same corpus.
+type CorpusRequest struct {
+	Size      int        `json:"size,omitempty"` // records wanted; 0 = as many as stay balanced
+	Seed      int64      `json:"seed"`
+	Since     *time.Time `json:"since,omitempty"` // inclusive lower bound on CreatedAt
+	Until     *time.Time `json:"until,omitempty"` // exclusive upper bound on CreatedAt
+	Languages []string   `json:"languages,omitempty"`
+}
+
+// CorpusRecord is one anonymized training example.
+type CorpusRecord struct {
+	ID           string  `json:"id"` // stable hash of the prediction ID
+	Label        string  `json:"label"`
+	Confidence   float64 `json:"confidence"`
+	Language     string  `json:"language"`
+	Domain       string  `json:"domain,omitempty"`
+	Date         string  `json:"date"` // YYYY-MM-DD
+	RequestType  string  `json:"request_type"`
+	URL          string  `json:"url,omitempty"`
+	Title        string  `json:"title,omitempty"`
+	Text         string  `json:"text"`
+	ModelVersion string  `json:"model_version"`
+}
+
+// CorpusManifest records how a corpus was built so the export can be
+// reproduced and verified.
+type CorpusManifest struct {
+	ID         string         `json:"id"`
+	CreatedAt  time.Time      `json:"created_at"`
+	Request    CorpusRequest  `json:"request"`
+	Anonymizer string         `json:"anonymizer"` // anonymization pipeline version
+	Candidates int            `json:"candidates"` // eligible predictions sampled from
+	Withheld   int            `json:"withheld"`   // eligible predictions whose license does not allow export
+	Records    int            `json:"records"`
+	SHA256     string         `json:"sha256"` // of the JSONL export
+	Bytes      int            `json:"bytes"`
+	ByLabel    map[string]int `json:"by_label"`
+	ByLanguage map[string]int `json:"by_language"`
+	ByDomain   map[string]int `json:"by_domain"`
+	ByMonth    map[string]int `json:"by_month"`
+	Strata     int            `json:"strata"` // label/language/domain/month strata sampled from
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// CrawlSchedule is when background fetches from one domain can run. It is
+// shown on work waiting for the domain's crawl budget, so a slow recheck
+// does not look stuck.
+type CrawlSchedule struct {
+	Domain         string     `json:"domain"`
+	Queued         int        `json:"queued"`                  // fetches waiting for the domain
+	NextFetchAt    *time.Time `json:"next_fetch_at,omitempty"` // set while the Crawl-delay holds fetches back
+	PagesRemaining int        `json:"pages_remaining"`         // crawl fetches left today
+	PagesPerDay    int        `json:"pages_per_day"`           // including a temporary raise
+	BackoffUntil   *time.Time `json:"backoff_until,omitempty"`
+	RaisedUntil    *time.Time `json:"raised_until,omitempty"` // end of an admin's temporary raise
+}
@@ -0,0 +1,44 @@
+package domain
+
+// Scrape stages, in order; ScrapeDiagnostics.Stage is the last one reached
+const (
+	ScrapeStageValidate = "validate"
+	ScrapeStageFetch    = "fetch"
+	ScrapeStageParse    = "parse"
+	ScrapeStageClassify = "classify"
+	ScrapeStageExtract  = "extract"
+	ScrapeStageDone     = "done"
+)
+
+// robots.txt decisions recorded in ScrapeDiagnostics.Robots
+const (
+	RobotsNotChecked      = "not_checked" // interactive analyses do not consult robots.txt
+	RobotsCrawlDelay      = "crawl_delay_honored"
+	RobotsBudgetExhausted = "budget_exhausted" // crawl budget or backoff refused the fetch
+)
+
+// ScrapeDiagnostics records how the scraper handled one URL, so support
+// can triage a failed or poor extraction without server logs.
+type ScrapeDiagnostics struct {
+	URL           string      `json:"url"`
+	FinalURL      string      `json:"final_url,omitempty"`
+	Stage         string      `json:"stage"`
+	StatusCode    int         `json:"status_code,omitempty"`
+	ContentType   string      `json:"content_type,omitempty"`
+	RedirectChain []ScrapeHop `json:"redirect_chain,omitempty"` // every hop, the final page last
+	Robots        string      `json:"robots"`
+	PageType      string      `json:"page_type,omitempty"`
+	Extractor     string      `json:"extractor,omitempty"`
+	TextChars     int         `json:"text_chars"`
+	Paragraphs    int         `json:"paragraphs"`
+	BlockedBy     string      `json:"blocked_by,omitempty"` // best guess at what stopped the scraper
+	Error         string      `json:"error,omitempty"`
+	DurationMS    int64       `json:"duration_ms"`
+	TimeoutMS     int64       `json:"timeout_ms,omitempty"` // page fetch timeout chosen for the domain
+}
+
+// ScrapeHop is one request in a redirect chain.
+type ScrapeHop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+}
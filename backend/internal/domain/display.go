@@ -0,0 +1,88 @@
+package domain
+
+import "fmt"
+
+// DefaultLocale is used when the caller accepts none of the catalog's
+// locales.
+const DefaultLocale = "en"
+
+// VerdictDisplay is how a verdict should be shown to people: a localized
+// label and description plus a color, so clients don't each translate and
+// style the canonical labels.
+type VerdictDisplay struct {
+	Locale      string `json:"locale"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+	Color       string `json:"color"` // hex RGB
+}
+
+// verdictColors are locale-independent
+var verdictColors = map[string]string{
+	LabelFake: "#D32F2F",
+	LabelReal: "#388E3C",
+}
+
+type verdictStrings struct {
+	label       string
+	description string // formatted with the confidence percentage
+}
+
+// verdictCatalog holds the display strings for each locale and label
+var verdictCatalog = map[string]map[string]verdictStrings{
+	"en": {
+		LabelFake: {"Likely fake", "%d%% confidence this article is misleading or false"},
+		LabelReal: {"Likely real", "%d%% confidence this article is reliable"},
+	},
+	"hi": {
+		LabelFake: {"संभवतः फ़र्ज़ी", "%d%% विश्वास कि यह लेख भ्रामक या झूठा है"},
+		LabelReal: {"संभवतः सही", "%d%% विश्वास कि यह लेख विश्वसनीय है"},
+	},
+	"es": {
+		LabelFake: {"Probablemente falsa", "%d%% de confianza en que este artículo es engañoso o falso"},
+		LabelReal: {"Probablemente verdadera", "%d%% de confianza en que este artículo es fiable"},
+	},
+	"fr": {
+		LabelFake: {"Probablement fausse", "%d%% de confiance que cet article est trompeur ou faux"},
+		LabelReal: {"Probablement vraie", "%d%% de confiance que cet article est fiable"},
+	},
+	"de": {
+		LabelFake: {"Wahrscheinlich falsch", "%d%% Konfidenz, dass dieser Artikel irreführend oder falsch ist"},
+		LabelReal: {"Wahrscheinlich echt", "%d%% Konfidenz, dass dieser Artikel zuverlässig ist"},
+	},
+	"pt": {
+		LabelFake: {"Provavelmente falsa", "%d%% de confiança de que este artigo é enganoso ou falso"},
+		LabelReal: {"Provavelmente verdadeira", "%d%% de confiança de que este artigo é confiável"},
+	},
+}
+
+// IsSupportedLocale reports whether the catalog has strings for locale
+func IsSupportedLocale(locale string) bool {
+	_, ok := verdictCatalog[locale]
+	return ok
+}
+
+// DisplayVerdict returns the display strings for a verdict in locale,
+// falling back to DefaultLocale. Unknown labels get no display.
+func DisplayVerdict(label string, confidence float64, locale string) *VerdictDisplay {
+	if !IsSupportedLocale(locale) {
+		locale = DefaultLocale
+	}
+	strs, ok := verdictCatalog[locale][label]
+	if !ok {
+		return nil
+	}
+	return &VerdictDisplay{
+		Locale:      locale,
+		Label:       strs.label,
+		Description: fmt.Sprintf(strs.description, int(confidence*100+0.5)),
+		Color:       verdictColors[label],
+	}
+}
+
+// Localized returns a copy of the prediction carrying display strings for
+// locale; the stored prediction is left untouched.
+func (p *Prediction) Localized(locale string) *Prediction {
+	localized := *p
+	localized.Display = DisplayVerdict(p.Result, p.Confidence, locale)
+	return &localized
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Drift levels, by population stability index (PSI) as is customary:
+// under 0.1 is stable, 0.1 to 0.25 a moderate shift, above that a major one.
+const (
+	DriftNone         = "none"
+	DriftModerate     = "moderate"
+	DriftMajor        = "major"
+	DriftInsufficient = "insufficient_data" // too few predictions in a window to judge
+)
+
+// DriftBin is one bin's share of a window's predictions.
+type DriftBin struct {
+	Bin      string  `json:"bin"`
+	Baseline float64 `json:"baseline"`
+	Recent   float64 `json:"recent"`
+}
+
+// DistributionDrift compares one distribution across the two windows.
+// KL is the divergence of the recent distribution from the baseline.
+type DistributionDrift struct {
+	PSI   float64    `json:"psi"`
+	KL    float64    `json:"kl"`
+	Level string     `json:"level"`
+	Bins  []DriftBin `json:"bins"`
+}
+
+// DriftReport compares recent model verdicts with a baseline window:
+// the FAKE/REAL split, and the fake probability in ten bins.
+type DriftReport struct {
+	Baseline      Period            `json:"baseline"`
+	Recent        Period            `json:"recent"`
+	BaselineCount int               `json:"baseline_count"`
+	RecentCount   int               `json:"recent_count"`
+	Label         DistributionDrift `json:"label"`
+	Score         DistributionDrift `json:"score"`
+	Level         string            `json:"level"` // the worse of the two
+	ComputedAt    time.Time         `json:"computed_at"`
+}
@@ -4,10 +4,79 @@ import "errors"
 
 // News and Prediction related errors
 var (
-	ErrInvalidRequestType = errors.New("invalid request type: must be 'text' or 'url'")
-	ErrEmptyContent       = errors.New("content cannot be empty")
-	ErrURLScrapingFailed  = errors.New("failed to scrape content from URL")
-	ErrMLServiceUnavailable = errors.New("ML service is unavailable")
-	ErrPredictionFailed   = errors.New("prediction failed")
-	ErrInvalidURL         = errors.New("invalid URL provided")
+	ErrInvalidRequestType       = errors.New("invalid request type: must be 'text' or 'url'")
+	ErrEmptyContent             = errors.New("content cannot be empty")
+	ErrURLScrapingFailed        = errors.New("failed to scrape content from URL")
+	ErrMLServiceUnavailable     = errors.New("ML service is unavailable")
+	ErrPredictionFailed         = errors.New("prediction failed")
+	ErrInvalidURL               = errors.New("invalid URL provided")
+	ErrUnsupportedContentType   = errors.New("unsupported content type")
+	ErrNotAnArticle             = errors.New("URL does not point to a news article")
+	ErrAlreadyExists            = errors.New("prediction already exists")
+	ErrPredictionNotFound       = errors.New("prediction not found")
+	ErrInvalidQuery             = errors.New("invalid history query")
+	ErrInvalidTruncation        = errors.New("invalid truncation strategy: must be one of none, head, head_tail, lead, chunk")
+	ErrPinLimitReached          = errors.New("pin limit reached for your plan")
+	ErrPinnedByOther            = errors.New("prediction is pinned by another user")
+	ErrInvalidDomain            = errors.New("invalid domain name")
+	ErrUnknownLabel             = errors.New("unknown verdict label")
+	ErrInvalidImport            = errors.New("invalid import file")
+	ErrInvalidDataset           = errors.New("invalid evaluation dataset")
+	ErrEvaluationNotFound       = errors.New("evaluation not found")
+	ErrJobNotFound              = errors.New("job not found")
+	ErrJobLeaseLost             = errors.New("job is not leased to this worker")
+	ErrJobNotFailed             = errors.New("only failed jobs can be requeued")
+	ErrInvalidFeedToken         = errors.New("invalid feed token")
+	ErrWatchNotFound            = errors.New("watch not found")
+	ErrWatchLimitReached        = errors.New("watch limit reached")
+	ErrUserNotFound             = errors.New("user not found")
+	ErrUnknownOrganization      = errors.New("no single sign-on configured for organization")
+	ErrSSOFailed                = errors.New("single sign-on failed")
+	ErrUnknownOAuthProvider     = errors.New("no social sign-in configured for provider")
+	ErrOAuthFailed              = errors.New("social sign-in failed")
+	ErrInvalidSession           = errors.New("invalid or expired session")
+	ErrInvalidToken             = errors.New("invalid or expired token")
+	ErrInvalidVerbosity         = errors.New("invalid verbosity: must be one of minimal, standard, full")
+	ErrInvalidDepth             = errors.New("invalid depth: must be 0 or 1, and only for url requests")
+	ErrInvalidDomainRule        = errors.New("invalid domain rule")
+	ErrDomainRuleNotFound       = errors.New("domain rule not found")
+	ErrCrawlBudgetExhausted     = errors.New("crawl budget exhausted")
+	ErrReportNotFound           = errors.New("report not found")
+	ErrDeliveryNotFound         = errors.New("dead-lettered delivery not found")
+	ErrBrandingNotFound         = errors.New("source branding not found")
+	ErrCorpusNotFound           = errors.New("corpus not found")
+	ErrInvalidCorpusRequest     = errors.New("invalid corpus request")
+	ErrEmptyCorpus              = errors.New("no predictions match the corpus request")
+	ErrRepositoryTimeout        = errors.New("repository call timed out")
+	ErrInvalidReview            = errors.New("invalid review")
+	ErrNotReviewed              = errors.New("prediction has not been reviewed")
+	ErrVersionMismatch          = errors.New("prediction has changed since it was fetched")
+	ErrPreferencesNotFound      = errors.New("preferences not found")
+	ErrInvalidPreferences       = errors.New("invalid preferences")
+	ErrOutboundBlocked          = errors.New("outbound destination is not allow-listed")
+	ErrInvalidTuning            = errors.New("invalid tuning")
+	ErrSavedSearchNotFound      = errors.New("saved search not found")
+	ErrInvalidSavedSearch       = errors.New("invalid saved search")
+	ErrSavedSearchLimitReached  = errors.New("saved search limit reached")
+	ErrReviewItemNotFound       = errors.New("prediction is not in the review queue")
+	ErrReviewItemClaimed        = errors.New("review item is claimed by another reviewer")
+	ErrAuditSampleNotFound      = errors.New("audit sample not found")
+	ErrInvalidAuditSample       = errors.New("invalid audit sample request")
+	ErrBulkDeleteNotFound       = errors.New("bulk delete not found")
+	ErrInvalidBulkDelete        = errors.New("invalid bulk delete")
+	ErrInvalidCrawlRaise        = errors.New("invalid crawl budget raise")
+	ErrTemplateNotFound         = errors.New("analysis template not found")
+	ErrInvalidTemplate          = errors.New("invalid analysis template")
+	ErrTemplateLimitReached     = errors.New("analysis template limit reached")
+	ErrTemplateForbidden        = errors.New("not allowed to manage this analysis template")
+	ErrInvalidLanguage          = errors.New("language must be an ISO 639-1 code")
+	ErrNarrativeNotFound        = errors.New("narrative not found")
+	ErrInvalidNarrative         = errors.New("invalid narrative")
+	ErrNarrativeLimitReached    = errors.New("narrative limit reached")
+	ErrQuotaExceeded            = errors.New("daily quota exceeded for your plan")
+	ErrWebhookNotFound          = errors.New("webhook subscription not found")
+	ErrInvalidWebhook           = errors.New("invalid webhook subscription")
+	ErrWebhookLimitReached      = errors.New("webhook subscription limit reached")
+	ErrPushSubscriptionNotFound = errors.New("push subscription not found")
+	ErrPredictionPinned         = errors.New("prediction is pinned")
 )
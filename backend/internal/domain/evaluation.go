@@ -0,0 +1,97 @@
+package domain
+
+import "time"
+
+// Evaluation run states
+const (
+	EvaluationPending   = "pending"
+	EvaluationRunning   = "running"
+	EvaluationCompleted = "completed"
+	EvaluationFailed    = "failed"
+)
+
+// EvaluationLabelMapping accepts the label spellings seen in benchmark
+// datasets. Numeric labels are rejected because datasets disagree on
+// which class is 1.
+var EvaluationLabelMapping = LabelMapping{
+	"fake":       LabelFake,
+	"real":       LabelReal,
+	"false":      LabelFake,
+	"true":       LabelReal,
+	"unreliable": LabelFake,
+	"reliable":   LabelReal,
+}
+
+// EvaluationSample is one labeled row of a benchmark dataset
+type EvaluationSample struct {
+	Text  string `json:"text"`
+	Label string `json:"label"` // LabelFake or LabelReal
+}
+
+// ConfusionMatrix counts predictions against true labels, with FAKE as
+// the positive class
+type ConfusionMatrix struct {
+	TruePositive  int `json:"true_positive"`  // FAKE predicted FAKE
+	FalsePositive int `json:"false_positive"` // REAL predicted FAKE
+	TrueNegative  int `json:"true_negative"`  // REAL predicted REAL
+	FalseNegative int `json:"false_negative"` // FAKE predicted REAL
+}
+
+// Add records one prediction against its true label
+func (m *ConfusionMatrix) Add(label, predicted string) {
+	switch {
+	case label == LabelFake && predicted == LabelFake:
+		m.TruePositive++
+	case label == LabelReal && predicted == LabelFake:
+		m.FalsePositive++
+	case label == LabelReal && predicted == LabelReal:
+		m.TrueNegative++
+	case label == LabelFake && predicted == LabelReal:
+		m.FalseNegative++
+	}
+}
+
+// EvaluationMetrics summarizes model quality over a dataset
+type EvaluationMetrics struct {
+	Accuracy        float64         `json:"accuracy"`
+	Precision       float64         `json:"precision"`
+	Recall          float64         `json:"recall"`
+	F1              float64         `json:"f1"`
+	ConfusionMatrix ConfusionMatrix `json:"confusion_matrix"`
+}
+
+// Metrics computes accuracy, precision, recall and F1 from the matrix.
+// Ratios with an empty denominator are reported as 0.
+func (m ConfusionMatrix) Metrics() EvaluationMetrics {
+	ratio := func(num, den int) float64 {
+		if den == 0 {
+			return 0
+		}
+		return float64(num) / float64(den)
+	}
+
+	metrics := EvaluationMetrics{
+		Accuracy:        ratio(m.TruePositive+m.TrueNegative, m.TruePositive+m.FalsePositive+m.TrueNegative+m.FalseNegative),
+		Precision:       ratio(m.TruePositive, m.TruePositive+m.FalsePositive),
+		Recall:          ratio(m.TruePositive, m.TruePositive+m.FalseNegative),
+		ConfusionMatrix: m,
+	}
+	if metrics.Precision+metrics.Recall > 0 {
+		metrics.F1 = 2 * metrics.Precision * metrics.Recall / (metrics.Precision + metrics.Recall)
+	}
+	return metrics
+}
+
+// EvaluationRun is a benchmark of the model against a labeled dataset
+type EvaluationRun struct {
+	ID          string             `json:"id"`
+	Status      string             `json:"status"`
+	Model       string             `json:"model,omitempty"` // Model version requested, empty for the default
+	Total       int                `json:"total"`
+	Processed   int                `json:"processed"`
+	Failed      int                `json:"failed"` // Rows the ML service could not score
+	Metrics     *EvaluationMetrics `json:"metrics,omitempty"`
+	Error       string             `json:"error,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	CompletedAt *time.Time         `json:"completed_at,omitempty"`
+}
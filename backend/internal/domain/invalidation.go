@@ -0,0 +1,43 @@
+package domain
+
+import "strings"
+
+// Prediction change kinds
+const (
+	ChangeCreated = "created"
+	ChangeUpdated = "updated" // re-scored, annotated, reviewed, pinned...
+	ChangeDeleted = "deleted"
+)
+
+// PredictionChange is published after a prediction write commits, so
+// caches derived from predictions can drop what the write made stale.
+// Before is nil for creations and After is nil for deletions.
+type PredictionChange struct {
+	Kind   string      `json:"kind"`
+	ID     string      `json:"id"`
+	Before *Prediction `json:"-"`
+	After  *Prediction `json:"-"`
+}
+
+// Sources returns the distinct article source hosts the change touches,
+// lowercased: an update can move a prediction between domains.
+func (c PredictionChange) Sources() []string {
+	var sources []string
+	for _, p := range []*Prediction{c.Before, c.After} {
+		if p == nil || p.ArticleSource == "" {
+			continue
+		}
+		source := strings.ToLower(p.ArticleSource)
+		if len(sources) == 0 || sources[0] != source {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// InvalidationStats counts published changes and subscriber deliveries.
+type InvalidationStats struct {
+	Subscribers []string         `json:"subscribers"`
+	Published   map[string]int64 `json:"published"` // by change kind
+	Failures    int64            `json:"failures"`  // subscribers that panicked
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job states
+const (
+	JobQueued   = "queued"
+	JobLeased   = "leased"
+	JobDeferred = "deferred" // failed transiently; waits for the ML service to recover
+	JobDone     = "done"
+	JobFailed   = "failed" // out of attempts or failed permanently; the dead-letter queue
+)
+
+// Job failure classes reported by workers. Unclassified failures are
+// retried like expired leases.
+const (
+	JobFailureTransient = "transient" // the ML service was unavailable; retry once it recovers
+	JobFailurePermanent = "permanent" // retrying cannot help, e.g. an invalid payload
+)
+
+// Job kinds executed by cmd/worker
+const (
+	JobKindEvaluation = "evaluation"
+)
+
+// Job is a unit of background work leased to a worker
+type Job struct {
+	ID           string          `json:"id"`
+	Kind         string          `json:"kind"`
+	Payload      json.RawMessage `json:"payload"`
+	Status       string          `json:"status"`
+	Attempts     int             `json:"attempts"`
+	LeasedBy     string          `json:"leased_by,omitempty"`
+	LeaseExpires *time.Time      `json:"lease_expires,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	Failure      string          `json:"failure,omitempty"` // class of the last failure reported by a worker
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Canonical verdict labels
+const (
+	LabelFake = "FAKE"
+	LabelReal = "REAL"
+)
+
+// LabelMapping maps external or legacy label spellings (lowercased) onto
+// the canonical labels
+type LabelMapping map[string]string
+
+// PrototypeLabelMapping covers the Python-only prototype, whose Keras model
+// encoded fake as 1 and real as 0 (the reverse of the current model's
+// class IDs), plus the spellings seen in its exports.
+var PrototypeLabelMapping = LabelMapping{
+	"1":          LabelFake,
+	"0":          LabelReal,
+	"fake":       LabelFake,
+	"real":       LabelReal,
+	"false":      LabelFake,
+	"true":       LabelReal,
+	"unreliable": LabelFake,
+	"reliable":   LabelReal,
+}
+
+// Map returns the canonical label for raw
+func (m LabelMapping) Map(raw string) (string, error) {
+	key := strings.ToLower(strings.TrimSpace(raw))
+	if label, ok := m[key]; ok {
+		return label, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownLabel, raw)
+}
@@ -0,0 +1,58 @@
+package domain
+
+// Content licenses, recorded on a prediction when its content is ingested
+const (
+	LicenseScrapedFairUse  = "scraped_fair_use" // fetched from the publisher for analysis only
+	LicenseUserSubmitted   = "user_submitted"   // text pasted by the caller
+	LicensePartnerProvided = "partner_provided" // sent by a partner under agreement
+)
+
+// LicenseUnrecorded groups predictions stored before licenses were recorded
+// in composition reports.
+const LicenseUnrecorded = "unrecorded"
+
+// ContentLicense records the terms under which a prediction's content was
+// ingested.
+type ContentLicense struct {
+	License         string `json:"license"`
+	ResearchConsent bool   `json:"research_consent"`  // submitter or partner agreement allows research use
+	Partner         string `json:"partner,omitempty"` // API client that provided the content
+}
+
+// Exportable reports whether the content may leave the service in research
+// exports. Scraped articles may be analyzed but not redistributed; user and
+// partner content needs research consent. Content with no recorded license
+// is never exported.
+func (l *ContentLicense) Exportable() bool {
+	if l == nil {
+		return false
+	}
+	switch l.License {
+	case LicenseUserSubmitted, LicensePartnerProvided:
+		return l.ResearchConsent
+	}
+	return false
+}
+
+// Name returns the license, or LicenseUnrecorded for nil.
+func (l *ContentLicense) Name() string {
+	if l == nil || l.License == "" {
+		return LicenseUnrecorded
+	}
+	return l.License
+}
+
+// LicenseComposition breaks the stored corpus down by content license.
+type LicenseComposition struct {
+	Total      int                      `json:"total"`
+	Exportable int                      `json:"exportable"`
+	ByLicense  map[string]*LicenseCount `json:"by_license"`
+	ByPartner  map[string]int           `json:"by_partner,omitempty"`
+}
+
+// LicenseCount counts the predictions under one license.
+type LicenseCount struct {
+	Predictions int            `json:"predictions"`
+	Exportable  int            `json:"exportable"`
+	ByLabel     map[string]int `json:"by_label"`
+}
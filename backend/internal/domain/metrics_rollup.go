@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// Rollup resolutions. Hourly rollups are downsampled into daily ones,
+// which are kept for much longer.
+const (
+	RollupHour = "hour"
+	RollupDay  = "day"
+)
+
+// IsValidRollupResolution reports whether resolution is a rollup resolution
+func IsValidRollupResolution(resolution string) bool {
+	return resolution == RollupHour || resolution == RollupDay
+}
+
+// RollupLatencyBounds are the upper bounds, in milliseconds, of the
+// processing time histogram buckets. A last bucket holds everything slower.
+// Fixed buckets let rollups be merged exactly when downsampling, which
+// stored percentiles could not be.
+var RollupLatencyBounds = []int64{50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000}
+
+// RollupLatency is the processing time distribution of a rollup's
+// predictions. Percentiles are the upper bound of the bucket they fall in,
+// or Max for the last bucket.
+type RollupLatency struct {
+	P50       int64   `json:"p50_ms"`
+	P90       int64   `json:"p90_ms"`
+	P99       int64   `json:"p99_ms"`
+	Max       int64   `json:"max_ms"`
+	Histogram []int64 `json:"histogram"` // counts per RollupLatencyBounds bucket, plus one
+}
+
+// MetricsRollup aggregates the predictions of one hour or one UTC day, so
+// trends outlive prediction retention and metrics scraping.
+type MetricsRollup struct {
+	ID          string    `json:"id"` // resolution + ":" + start
+	Resolution  string    `json:"resolution"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	Total         int     `json:"total"`
+	Fake          int     `json:"fake"`
+	Real          int     `json:"real"`
+	URLRequests   int     `json:"url_requests"`
+	TextRequests  int     `json:"text_requests"`
+	FakeRatio     float64 `json:"fake_ratio"`
+	AvgConfidence float64 `json:"avg_confidence"`
+
+	Latency RollupLatency `json:"latency"`
+
+	// Human reviews of the period's predictions, the model's accuracy
+	// against that feedback
+	Reviewed int     `json:"reviewed"`
+	Agreed   int     `json:"agreed"`
+	Accuracy float64 `json:"accuracy"` // Agreed / Reviewed; 0 without reviews
+
+	confidenceSum float64
+}
+
+// RollupID returns the ID of the rollup of a resolution starting at start
+func RollupID(resolution string, start time.Time) string {
+	return resolution + ":" + start.UTC().Format(time.RFC3339)
+}
+
+// NewMetricsRollup returns an empty rollup of [start, end)
+func NewMetricsRollup(resolution string, start, end time.Time) *MetricsRollup {
+	return &MetricsRollup{
+		ID:         RollupID(resolution, start),
+		Resolution: resolution,
+		Start:      start.UTC(),
+		End:        end.UTC(),
+		Latency:    RollupLatency{Histogram: make([]int64, len(RollupLatencyBounds)+1)},
+	}
+}
+
+// Add counts one prediction. Call Finish once all are added.
+func (r *MetricsRollup) Add(p *Prediction) {
+	r.Total++
+	switch p.Result {
+	case LabelFake:
+		r.Fake++
+	case LabelReal:
+		r.Real++
+	}
+	switch p.RequestType {
+	case "url":
+		r.URLRequests++
+	case "text":
+		r.TextRequests++
+	}
+	r.confidenceSum += p.Confidence
+	r.Latency.Histogram[latencyBucket(p.ProcessingTime)]++
+	r.Latency.Max = max(r.Latency.Max, p.ProcessingTime)
+	if p.Review != nil {
+		r.Reviewed++
+		if p.Review.Verdict == p.Result {
+			r.Agreed++
+		}
+	}
+}
+
+// Merge adds another rollup's counts into r, for downsampling. Call
+// Finish afterwards.
+func (r *MetricsRollup) Merge(o *MetricsRollup) {
+	r.Total += o.Total
+	r.Fake += o.Fake
+	r.Real += o.Real
+	r.URLRequests += o.URLRequests
+	r.TextRequests += o.TextRequests
+	r.confidenceSum += o.AvgConfidence * float64(o.Total)
+	for i := range r.Latency.Histogram {
+		if i < len(o.Latency.Histogram) {
+			r.Latency.Histogram[i] += o.Latency.Histogram[i]
+		}
+	}
+	r.Latency.Max = max(r.Latency.Max, o.Latency.Max)
+	r.Reviewed += o.Reviewed
+	r.Agreed += o.Agreed
+}
+
+// Finish computes the ratios and percentiles from the counts.
+func (r *MetricsRollup) Finish() {
+	if r.Total > 0 {
+		r.FakeRatio = float64(r.Fake) / float64(r.Total)
+		r.AvgConfidence = r.confidenceSum / float64(r.Total)
+	}
+	if r.Reviewed > 0 {
+		r.Accuracy = float64(r.Agreed) / float64(r.Reviewed)
+	}
+	r.Latency.P50 = r.percentile(0.5)
+	r.Latency.P90 = r.percentile(0.9)
+	r.Latency.P99 = r.percentile(0.99)
+}
+
+func (r *MetricsRollup) percentile(q float64) int64 {
+	var total int64
+	for _, n := range r.Latency.Histogram {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(q * float64(total)))
+	var seen int64
+	for i, n := range r.Latency.Histogram {
+		seen += n
+		if seen >= rank {
+			if i < len(RollupLatencyBounds) {
+				return min(RollupLatencyBounds[i], r.Latency.Max)
+			}
+			return r.Latency.Max
+		}
+	}
+	return r.Latency.Max
+}
+
+func latencyBucket(ms int64) int {
+	for i, bound := range RollupLatencyBounds {
+		if ms <= bound {
+			return i
+		}
+	}
+	return len(RollupLatencyBounds)
+}
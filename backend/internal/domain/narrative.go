@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Narrative limits
+const (
+	maxNarrativeName        = 100
+	maxNarrativeDescription = 2000
+	MaxNarrativeTerms       = 50 // keywords plus patterns
+)
+
+// Narrative is a named misinformation story an organization tracks, such
+// as one viral rumor. Predictions whose text contains any keyword or
+// matches any pattern are tagged with it. Matching is case-insensitive.
+type Narrative struct {
+	ID          string    `json:"id"`
+	OrgID       string    `json:"org_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Keywords    []string  `json:"keywords,omitempty"` // plain substrings
+	Patterns    []string  `json:"patterns,omitempty"` // Go regular expressions
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Validate trims the fields, drops empty keywords and checks that every
+// pattern compiles
+func (n *Narrative) Validate() error {
+	n.Name = strings.TrimSpace(n.Name)
+	n.Description = strings.TrimSpace(n.Description)
+	if n.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidNarrative)
+	}
+	if utf8.RuneCountInString(n.Name) > maxNarrativeName {
+		return fmt.Errorf("%w: name is longer than %d characters", ErrInvalidNarrative, maxNarrativeName)
+	}
+	if utf8.RuneCountInString(n.Description) > maxNarrativeDescription {
+		return fmt.Errorf("%w: description is longer than %d characters", ErrInvalidNarrative, maxNarrativeDescription)
+	}
+
+	keywords := make([]string, 0, len(n.Keywords))
+	for _, k := range n.Keywords {
+		if k = strings.TrimSpace(k); k != "" {
+			keywords = append(keywords, k)
+		}
+	}
+	n.Keywords = keywords
+	for _, p := range n.Patterns {
+		if _, err := regexp.Compile(p); err != nil || p == "" {
+			return fmt.Errorf("%w: pattern %q is not a valid regular expression", ErrInvalidNarrative, p)
+		}
+	}
+	if len(n.Keywords)+len(n.Patterns) == 0 {
+		return fmt.Errorf("%w: at least one keyword or pattern is required", ErrInvalidNarrative)
+	}
+	if len(n.Keywords)+len(n.Patterns) > MaxNarrativeTerms {
+		return fmt.Errorf("%w: at most %d keywords and patterns", ErrInvalidNarrative, MaxNarrativeTerms)
+	}
+	return nil
+}
+
+// NarrativeStats aggregates the predictions tagged with a narrative over
+// a period.
+type NarrativeStats struct {
+	Narrative *Narrative `json:"narrative"`
+	PeriodStats
+	FirstSeen *time.Time        `json:"first_seen,omitempty"`
+	LastSeen  *time.Time        `json:"last_seen,omitempty"`
+	Interval  string            `json:"interval"`
+	Series    []TimeSeriesPoint `json:"series"` // value is the fake ratio
+}
@@ -4,18 +4,57 @@ import "time"
 
 // NewsArticle represents a news article to be analyzed
 type NewsArticle struct {
-	ID          string    `json:"id"`
-	Content     string    `json:"content"`     // The text content of the article
-	URL         string    `json:"url"`         // Original URL if scraped
-	Title       string    `json:"title"`       // Article title
-	Source      string    `json:"source"`      // Source of the article
-	CreatedAt   time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+	Content   string    `json:"content"` // The text content of the article
+	URL       string    `json:"url"`     // Original URL if scraped
+	Title     string    `json:"title"`   // Article title
+	Source    string    `json:"source"`  // Source of the article
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Truncation strategies applied to long article text before prediction
+const (
+	TruncationNone     = "none"      // send the text as-is
+	TruncationHead     = "head"      // keep the beginning of the text
+	TruncationHeadTail = "head_tail" // keep the beginning and the end
+	TruncationLead     = "lead"      // keep the lead paragraph plus sampled sentences
+	TruncationChunk    = "chunk"     // split into chunks and aggregate predictions
+)
+
+// IsValidTruncationStrategy reports whether s names a known strategy
+func IsValidTruncationStrategy(s string) bool {
+	switch s {
+	case TruncationNone, TruncationHead, TruncationHeadTail, TruncationLead, TruncationChunk:
+		return true
+	}
+	return false
 }
 
 // AnalysisRequest represents a request to analyze news
 type AnalysisRequest struct {
-	Type    string `json:"type"`    // "text" or "url"
-	Content string `json:"content"` // Text content or URL
+	Type       string `json:"type"`                 // "text" or "url"
+	Content    string `json:"content"`              // Text content or URL
+	Truncation string `json:"truncation,omitempty"` // Optional truncation strategy override
+	Depth      int    `json:"depth,omitempty"`      // 1 also scrapes linked same-site articles as context
+
+	IncludeSummary  bool   `json:"include_summary,omitempty"`  // Also generate a short summary of the article
+	IncludeEvidence bool   `json:"include_evidence,omitempty"` // Also retrieve evidence for the article's claims
+	Verbosity       string `json:"verbosity,omitempty"`        // minimal, standard or full; defaults per API client
+
+	Model      string `json:"model,omitempty"`       // Pin a model version instead of the default
+	Language   string `json:"language,omitempty"`    // ISO 639-1 hint; skips language detection
+	TemplateID string `json:"template_id,omitempty"` // Saved analysis template filling in the options above
+
+	// Accept a provisional verdict for URLs already known to be fake; the
+	// full analysis then runs in the background
+	AllowProvisional bool `json:"allow_provisional,omitempty"`
+
+	// Accept a stored verdict older than the stale age, marked stale, while
+	// it is refreshed in the background. Extension clients default to true
+	AllowStale bool `json:"allow_stale,omitempty"`
+
+	// Allow submitted text to be used in research corpora
+	ResearchConsent bool `json:"research_consent,omitempty"`
 }
 
 // Validate validates the analysis request
@@ -26,5 +65,20 @@ func (r *AnalysisRequest) Validate() error {
 	if r.Content == "" {
 		return ErrEmptyContent
 	}
+	if r.Truncation != "" && !IsValidTruncationStrategy(r.Truncation) {
+		return ErrInvalidTruncation
+	}
+	if r.Depth < 0 || r.Depth > 1 || (r.Depth > 0 && r.Type != "url") {
+		return ErrInvalidDepth
+	}
+	if r.Language != "" && !IsValidLanguageHint(r.Language) {
+		return ErrInvalidLanguage
+	}
 	return nil
 }
+
+// IsValidLanguageHint reports whether lang is a lowercase two-letter
+// ISO 639-1 code.
+func IsValidLanguageHint(lang string) bool {
+	return len(lang) == 2 && lang[0] >= 'a' && lang[0] <= 'z' && lang[1] >= 'a' && lang[1] <= 'z'
+}
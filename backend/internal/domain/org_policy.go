@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Organization domain lists
+const (
+	DomainListBlock = "block" // always flagged as fake, never scraped
+	DomainListAllow = "allow" // trusted; pulls the fused verdict towards real
+)
+
+// OrgDomainRule puts a domain, and its subdomains, on one of an
+// organization's domain lists. Rules only apply to analyses made by members
+// of that organization.
+type OrgDomainRule struct {
+	ID     string `json:"id"`
+	OrgID  string `json:"org_id"`
+	Domain string `json:"domain"`
+	List   string `json:"list"` // block or allow
+
+	// Weight of a trusted domain in the verdict fusion; 0 uses the
+	// default org policy weight. Ignored for blocked domains.
+	Weight float64 `json:"weight,omitempty"`
+
+	Note      string    `json:"note,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks the rule's list and weight
+func (r *OrgDomainRule) Validate() error {
+	if r.List != DomainListBlock && r.List != DomainListAllow {
+		return fmt.Errorf("%w: list must be block or allow", ErrInvalidDomainRule)
+	}
+	if r.Weight < 0 {
+		return fmt.Errorf("%w: weight must not be negative", ErrInvalidDomainRule)
+	}
+	return nil
+}
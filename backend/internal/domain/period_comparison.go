@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Period comparison limits
+const (
+	DefaultCompareTopDomains = 10
+	MaxCompareTopDomains     = 50
+)
+
+// Period is a half-open time range [Since, Until).
+type Period struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+}
+
+// Validate checks that the period is non-empty.
+func (p Period) Validate() error {
+	if p.Since.IsZero() || p.Until.IsZero() || !p.Since.Before(p.Until) {
+		return fmt.Errorf("%w: a range must end after it starts", ErrInvalidQuery)
+	}
+	return nil
+}
+
+// DomainCount is one source domain's predictions within a period.
+type DomainCount struct {
+	Domain    string  `json:"domain"`
+	Count     int     `json:"count"`
+	FakeRatio float64 `json:"fake_ratio"`
+}
+
+// PeriodStats summarizes the predictions made in a period.
+type PeriodStats struct {
+	Period
+	Count         int           `json:"count"`
+	FakeCount     int           `json:"fake_count"`
+	FakeRatio     float64       `json:"fake_ratio"`
+	AvgConfidence float64       `json:"avg_confidence"`
+	TopDomains    []DomainCount `json:"top_domains"`
+
+	// Mean model std dev over the predictions that report one
+	AvgUncertainty float64 `json:"avg_uncertainty"`
+}
+
+// DomainDelta compares one domain across two periods.
+type DomainDelta struct {
+	Domain     string  `json:"domain"`
+	CountA     int     `json:"count_a"`
+	CountB     int     `json:"count_b"`
+	CountDelta int     `json:"count_delta"`
+	FakeRatioA float64 `json:"fake_ratio_a"`
+	FakeRatioB float64 `json:"fake_ratio_b"`
+}
+
+// PeriodDelta is period B minus period A. Ratios and confidence change in
+// absolute points; CountChange is relative and nil when A is empty.
+type PeriodDelta struct {
+	Count         int           `json:"count"`
+	CountChange   *float64      `json:"count_change"`
+	FakeRatio     float64       `json:"fake_ratio"`
+	AvgConfidence float64       `json:"avg_confidence"`
+	Domains       []DomainDelta `json:"domains"` // every domain in either period's top list
+
+	AvgUncertainty float64 `json:"avg_uncertainty"`
+}
+
+// PeriodComparison compares prediction statistics between two periods.
+type PeriodComparison struct {
+	A     PeriodStats `json:"a"`
+	B     PeriodStats `json:"b"`
+	Delta PeriodDelta `json:"delta"`
+}
@@ -0,0 +1,95 @@
+package domain
+
+import "time"
+
+// Subscription plans, which set per-caller quotas
+const (
+	PlanFree       = "free"
+	PlanPro        = "pro"
+	PlanEnterprise = "enterprise"
+)
+
+// pinLimits is how many predictions a caller on each plan may pin.
+// Zero means unlimited.
+var pinLimits = map[string]int{
+	PlanFree:       10,
+	PlanPro:        200,
+	PlanEnterprise: 0,
+}
+
+// NormalizePlan returns plan if it is a known plan, else the free plan.
+func NormalizePlan(plan string) string {
+	if _, ok := pinLimits[plan]; ok {
+		return plan
+	}
+	return PlanFree
+}
+
+// PinLimit returns the pin quota for a plan (0 = unlimited). Unknown or
+// empty plans get the free quota.
+func PinLimit(plan string) int {
+	if limit, ok := pinLimits[plan]; ok {
+		return limit
+	}
+	return pinLimits[PlanFree]
+}
+
+// Quota dimensions, metered and limited independently: a caller whose
+// articles are already analyzed still uses scraping bandwidth, and one
+// submitting text uses inference without scraping.
+const (
+	QuotaScrapes    = "scrapes"    // article pages fetched by the scraper
+	QuotaInferences = "inferences" // requests scored by the ML service
+)
+
+// dailyQuotas is how many operations of each dimension a caller on each
+// plan may make per UTC day. Zero means unlimited.
+var dailyQuotas = map[string]map[string]int{
+	QuotaScrapes: {
+		PlanFree:       100,
+		PlanPro:        5000,
+		PlanEnterprise: 0,
+	},
+	QuotaInferences: {
+		PlanFree:       100,
+		PlanPro:        2000,
+		PlanEnterprise: 0,
+	},
+}
+
+// IsValidQuota reports whether dimension is a quota dimension.
+func IsValidQuota(dimension string) bool {
+	_, ok := dailyQuotas[dimension]
+	return ok
+}
+
+// DailyQuota returns a plan's daily quota for a dimension (0 = unlimited).
+// Unknown or empty plans get the free quota.
+func DailyQuota(dimension, plan string) int {
+	if limit, ok := dailyQuotas[dimension][plan]; ok {
+		return limit
+	}
+	return dailyQuotas[dimension][PlanFree]
+}
+
+// QuotaThresholds are the shares of a daily limit, in percent, whose
+// crossing is reported.
+var QuotaThresholds = []int{80, 100}
+
+// QuotaCrossing is a caller's use of a daily quota reaching one of
+// QuotaThresholds.
+type QuotaCrossing struct {
+	Dimension string    `json:"dimension"` // scrapes or inferences
+	Plan      string    `json:"plan"`
+	Threshold int       `json:"threshold"` // percent of the limit
+	Used      int       `json:"used"`
+	Limit     int       `json:"limit"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
+
+// QuotaUsage is a caller's use of one quota dimension today.
+type QuotaUsage struct {
+	Used     int       `json:"used"`
+	Limit    int       `json:"limit"` // 0 = unlimited
+	ResetsAt time.Time `json:"resets_at"`
+}
@@ -1,30 +1,193 @@
 package domain
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
 
 // Prediction represents the ML model's prediction result
 type Prediction struct {
-	ID              string    `json:"id"`
-	ArticleID       string    `json:"article_id"`
-	RequestType     string    `json:"request_type"`      // "text" or "url"
-	OriginalContent string    `json:"original_content"`  // Original text or URL
-	
+	ID              string `json:"id"`
+	ArticleID       string `json:"article_id"`
+	RequestType     string `json:"request_type"`            // "text" or "url"
+	OriginalContent string `json:"original_content"`        // Original text or URL
+	CanonicalURL    string `json:"canonical_url,omitempty"` // Normalized/canonical article URL (URL requests)
+
 	// Prediction results
-	Result          string    `json:"result"`              // "FAKE" or "REAL"
-	Confidence      float64   `json:"confidence"`          // Confidence score (0-1)
-	FakeProbability float64   `json:"fake_probability"`    // P(FAKE)
-	RealProbability float64   `json:"real_probability"`    // P(REAL)
-	ModelVersion    string    `json:"model_version"`       // Version of model used
+	Result          string  `json:"result"`                   // "FAKE" or "REAL"
+	Confidence      float64 `json:"confidence"`               // Confidence score (0-1)
+	FakeProbability float64 `json:"fake_probability"`         // P(FAKE)
+	RealProbability float64 `json:"real_probability"`         // P(REAL)
+	ModelVersion    string  `json:"model_version"`            // Version of model used
+	ModelRoute      string  `json:"model_route,omitempty"`    // Organization whose custom model answered
+	FallbackModel   string  `json:"fallback_model,omitempty"` // Set when the secondary (lower-fidelity) model answered
+	Method          string  `json:"method,omitempty"`         // How the verdict was reached; empty on older predictions
+	Provisional     bool    `json:"provisional,omitempty"`    // Fast-path verdict; the full analysis is still running
+	Stale           bool    `json:"stale,omitempty"`          // Stored verdict past the stale age; a refresh is running. Never stored
+
+	// Monte-Carlo dropout spread of FakeProbability, when the model
+	// reports it
+	Uncertainty *Uncertainty `json:"uncertainty,omitempty"`
+
+	// Localized label, description and color; set per response from
+	// Accept-Language, never stored
+	Display *VerdictDisplay `json:"display,omitempty"`
 
 	// Extracted metadata (populated for URL requests)
-	ArticleTitle       string `json:"article_title,omitempty"`
-	ArticleDescription string `json:"article_description,omitempty"`
-	ArticleAuthor      string `json:"article_author,omitempty"`
-	ArticleSource      string `json:"article_source,omitempty"`
-	
+	ArticleTitle       string      `json:"article_title,omitempty"`
+	ArticleDescription string      `json:"article_description,omitempty"`
+	ArticleAuthor      string      `json:"article_author,omitempty"`
+	ArticleSource      string      `json:"article_source,omitempty"`
+	ArticlePublishedAt *time.Time  `json:"article_published_at,omitempty"`
+	ContentType        string      `json:"content_type,omitempty"` // Media type scraped: text/html, application/pdf, ...
+	Summary            string      `json:"summary,omitempty"`      // Short ML-generated summary (include_summary)
+	SourceInfo         *SourceInfo `json:"source_info,omitempty"`  // Publisher branding captured while scraping
+
+	// Input preparation (recorded for reproducibility)
+	TruncationStrategy string `json:"truncation_strategy,omitempty"`
+	InputChars         int    `json:"input_chars,omitempty"` // Characters of article text before truncation
+	ChunkCount         int    `json:"chunk_count,omitempty"` // Number of chunks scored (chunk strategy only)
+
+	// Set when the article was machine-translated before scoring; the
+	// penalty factor has been applied to Confidence
+	TranslatedFrom    string  `json:"translated_from,omitempty"`
+	ConfidencePenalty float64 `json:"confidence_penalty,omitempty"`
+
+	// Per-signal breakdown of the fused verdict (Result and Confidence);
+	// the probabilities above are the ML model's own
+	Signals []SignalContribution `json:"signals,omitempty"`
+
+	// How the verdict was produced, step by step
+	Provenance *Provenance `json:"provenance,omitempty"`
+
+	// How the Go scraper handled the URL, kept for support triage even
+	// when the ML service's scraper answered instead
+	ScrapeDiagnostics *ScrapeDiagnostics `json:"scrape_diagnostics,omitempty"`
+
+	// Check-worthy claims with retrieved evidence (include_evidence)
+	Claims []Claim `json:"claims,omitempty"`
+
+	// Human fact-checker verdict; reviewed predictions are published as
+	// ClaimReview
+	Review *Review `json:"review,omitempty"`
+
+	// Linked articles whose lead paragraphs were sent as context (depth=1)
+	RelatedArticles []string `json:"related_articles,omitempty"`
+
+	// IDs of the organization narratives the article matched
+	Narratives []string `json:"narratives,omitempty"`
+
+	// Ownership
+	OwnerID string `json:"owner_id,omitempty"` // Authenticated caller who requested the analysis
+	// Anonymous session of a caller who was not signed in; cleared when
+	// the session is merged into an account. Never serialized
+	AnonymousSession string `json:"-"`
+
+	// Terms the content was ingested under; decides research export
+	License *ContentLicense `json:"license,omitempty"`
+
+	// Retention
+	Pinned   bool   `json:"pinned"`              // Pinned predictions are never removed by retention
+	PinnedBy string `json:"pinned_by,omitempty"` // Caller who pinned it
+	Archived bool   `json:"archived,omitempty"`  // Only the summary is stored; the full record is in the archive tier
+
+	// Tracing (for cross-service debugging)
+	RequestID   string `json:"request_id,omitempty"`    // X-Request-ID of the analyze request
+	MLRequestID string `json:"ml_request_id,omitempty"` // Request ID reported by the ML service
+
 	// Metadata
-	ProcessingTime  int64     `json:"processing_time_ms"` // Time taken in milliseconds
-	CreatedAt       time.Time `json:"created_at"`
+	ProcessingTime int64      `json:"processing_time_ms"` // Time taken in milliseconds
+	CreatedAt      time.Time  `json:"created_at"`
+	RefreshedAt    *time.Time `json:"refreshed_at,omitempty"` // When a stale verdict was last re-scored in the background
+}
+
+// ScoredAt returns when the stored verdict was last computed.
+func (p *Prediction) ScoredAt() time.Time {
+	if p.RefreshedAt != nil {
+		return *p.RefreshedAt
+	}
+	return p.CreatedAt
+}
+
+// Verdict methods recorded on a Prediction
+const (
+	MethodModel         = "model"          // scored by the ML model
+	MethodTrustedSource = "trusted_source" // deployment allowlist; the model was not run
+	MethodOrgPolicy     = "org_policy"     // organization blocklist; the model was not run
+	MethodKnownFake     = "known_fake"     // provisional: the URL or its domain is a known fake
+)
+
+// Version is a content hash of the verdict: result, scores, model and
+// signals. It changes when the prediction is re-scored, but not when it is
+// pinned or reviewed, and is sent as the ETag so reviews can be submitted
+// with If-Match against the revision the reviewer saw.
+func (p *Prediction) Version() string {
+	data, _ := json.Marshal(struct {
+		Result          string
+		Confidence      float64
+		FakeProbability float64
+		RealProbability float64
+		ModelVersion    string
+		ModelRoute      string
+		FallbackModel   string
+		Method          string
+		Signals         []SignalContribution
+		CreatedAt       time.Time
+	}{p.Result, p.Confidence, p.FakeProbability, p.RealProbability, p.ModelVersion,
+		p.ModelRoute, p.FallbackModel, p.Method, p.Signals, p.CreatedAt})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ArchiveSummary returns the row kept in primary storage once the full
+// prediction has moved to the archive tier: enough for history, stats and
+// ClaimReview lookups, without article text, evidence or summaries.
+func (p *Prediction) ArchiveSummary() *Prediction {
+	summary := &Prediction{
+		ID:                 p.ID,
+		ArticleID:          p.ArticleID,
+		RequestType:        p.RequestType,
+		CanonicalURL:       p.CanonicalURL,
+		Result:             p.Result,
+		Confidence:         p.Confidence,
+		FakeProbability:    p.FakeProbability,
+		RealProbability:    p.RealProbability,
+		Uncertainty:        p.Uncertainty,
+		ModelVersion:       p.ModelVersion,
+		ModelRoute:         p.ModelRoute,
+		FallbackModel:      p.FallbackModel,
+		Method:             p.Method,
+		ArticleTitle:       p.ArticleTitle,
+		ArticleSource:      p.ArticleSource,
+		ArticlePublishedAt: p.ArticlePublishedAt,
+		ContentType:        p.ContentType,
+		TranslatedFrom:     p.TranslatedFrom,
+		Signals:            p.Signals,
+		Review:             p.Review,
+		Narratives:         p.Narratives,
+		OwnerID:            p.OwnerID,
+		Pinned:             p.Pinned,
+		PinnedBy:           p.PinnedBy,
+		Archived:           true,
+		RequestID:          p.RequestID,
+		ProcessingTime:     p.ProcessingTime,
+		CreatedAt:          p.CreatedAt,
+	}
+	if p.RequestType == "url" {
+		summary.OriginalContent = p.OriginalContent
+	}
+	return summary
+}
+
+// SourceInfo is a publisher's branding. Image URLs point at copies served
+// by this API, so clients never fetch from the publisher.
+type SourceInfo struct {
+	Domain     string `json:"domain"`
+	Name       string `json:"name,omitempty"`
+	FaviconURL string `json:"favicon_url,omitempty"`
+	LogoURL    string `json:"logo_url,omitempty"`
 }
 
 // PredictionResponse represents the API response for prediction
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// PredictionQuery describes a filtered, ordered slice of prediction history.
+// Zero values mean "no filter". Build one with NewPredictionQuery and the
+// With* methods, or fill the fields directly.
+type PredictionQuery struct {
+	Label         string    // "FAKE" or "REAL"
+	RequestType   string    // "text" or "url"
+	Domain        string    // article source host, matches subdomains too
+	ModelVersion  string    // exact model version
+	MinConfidence float64   // inclusive lower bound
+	MaxConfidence float64   // inclusive upper bound, 0 = no bound
+	Since         time.Time // inclusive lower bound on CreatedAt
+	Until         time.Time // exclusive upper bound on CreatedAt
+
+	PinnedOnly       bool   // only pinned predictions
+	PinnedBy         string // only predictions pinned by this caller
+	OwnerID          string // only predictions requested by this caller
+	AnonymousSession string // only predictions requested in this anonymous session
+	Narrative        string // only predictions tagged with this narrative ID
+
+	HasUncertainty bool // only predictions with a model uncertainty estimate
+
+	OldestFirst bool // default ordering is newest first
+	PinnedFirst bool // pinned predictions before the rest, each in date order
+	Limit       int  // 0 = no limit
+	Offset      int
+}
+
+// Page sizes of listed prediction history. Streamed exports are not paged.
+const (
+	DefaultHistoryPageSize = 50
+	MaxHistoryPageSize     = 500
+)
+
+// PageInfo places one page of results in the full result.
+type PageInfo struct {
+	Total      int  `json:"total"` // results matching the filters, across all pages
+	Limit      int  `json:"limit"`
+	Offset     int  `json:"offset"`
+	HasMore    bool `json:"has_more"`
+	NextOffset *int `json:"next_offset,omitempty"` // offset of the next page, unless this is the last
+}
+
+// NewPageInfo describes a page of returned results starting at offset
+func NewPageInfo(total, limit, offset, returned int) PageInfo {
+	info := PageInfo{Total: total, Limit: limit, Offset: offset}
+	if next := offset + returned; returned > 0 && next < total {
+		info.HasMore = true
+		info.NextOffset = &next
+	}
+	return info
+}
+
+// NewPredictionQuery returns an empty query matching all predictions
+func NewPredictionQuery() *PredictionQuery {
+	return &PredictionQuery{}
+}
+
+// WithLabel filters by verdict label
+func (q *PredictionQuery) WithLabel(label string) *PredictionQuery {
+	q.Label = strings.ToUpper(label)
+	return q
+}
+
+// WithRequestType filters by request type
+func (q *PredictionQuery) WithRequestType(requestType string) *PredictionQuery {
+	q.RequestType = requestType
+	return q
+}
+
+// WithDomain filters by article source host
+func (q *PredictionQuery) WithDomain(domain string) *PredictionQuery {
+	q.Domain = strings.ToLower(domain)
+	return q
+}
+
+// WithModelVersion filters by model version
+func (q *PredictionQuery) WithModelVersion(version string) *PredictionQuery {
+	q.ModelVersion = version
+	return q
+}
+
+// WithNarrative filters by narrative tag
+func (q *PredictionQuery) WithNarrative(id string) *PredictionQuery {
+	q.Narrative = id
+	return q
+}
+
+// WithConfidence filters by confidence range
+func (q *PredictionQuery) WithConfidence(min, max float64) *PredictionQuery {
+	q.MinConfidence = min
+	q.MaxConfidence = max
+	return q
+}
+
+// WithDateRange filters by creation time
+func (q *PredictionQuery) WithDateRange(since, until time.Time) *PredictionQuery {
+	q.Since = since
+	q.Until = until
+	return q
+}
+
+// WithPage sets limit and offset
+func (q *PredictionQuery) WithPage(limit, offset int) *PredictionQuery {
+	q.Limit = limit
+	q.Offset = offset
+	return q
+}
+
+// Validate validates the query bounds
+func (q *PredictionQuery) Validate() error {
+	if q.MinConfidence < 0 || q.MinConfidence > 1 || q.MaxConfidence < 0 || q.MaxConfidence > 1 {
+		return ErrInvalidQuery
+	}
+	if q.MaxConfidence > 0 && q.MinConfidence > q.MaxConfidence {
+		return ErrInvalidQuery
+	}
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Since.Before(q.Until) {
+		return ErrInvalidQuery
+	}
+	if q.Limit < 0 || q.Offset < 0 {
+		return ErrInvalidQuery
+	}
+	return nil
+}
+
+// Matches reports whether a prediction satisfies the query's filters.
+// Ordering and paging are left to the repository.
+func (q *PredictionQuery) Matches(p *Prediction) bool {
+	if q.Label != "" && !strings.EqualFold(p.Result, q.Label) {
+		return false
+	}
+	if q.RequestType != "" && p.RequestType != q.RequestType {
+		return false
+	}
+	if q.Domain != "" {
+		source := strings.ToLower(p.ArticleSource)
+		if source != q.Domain && !strings.HasSuffix(source, "."+q.Domain) {
+			return false
+		}
+	}
+	if q.ModelVersion != "" && p.ModelVersion != q.ModelVersion {
+		return false
+	}
+	if q.PinnedOnly && !p.Pinned {
+		return false
+	}
+	if q.PinnedBy != "" && (!p.Pinned || p.PinnedBy != q.PinnedBy) {
+		return false
+	}
+	if q.OwnerID != "" && p.OwnerID != q.OwnerID {
+		return false
+	}
+	if q.AnonymousSession != "" && p.AnonymousSession != q.AnonymousSession {
+		return false
+	}
+	if q.Narrative != "" && !slices.Contains(p.Narratives, q.Narrative) {
+		return false
+	}
+	if q.HasUncertainty && p.Uncertainty == nil {
+		return false
+	}
+	if p.Confidence < q.MinConfidence {
+		return false
+	}
+	if q.MaxConfidence > 0 && p.Confidence > q.MaxConfidence {
+		return false
+	}
+	if !q.Since.IsZero() && p.CreatedAt.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !p.CreatedAt.Before(q.Until) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AnalysisDefaults are a user's saved analysis options. They fill in the
+// options an analysis request leaves out, so clients need not send them on
+// every call; options in the request always win.
+type AnalysisDefaults struct {
+	Verbosity       string    `json:"verbosity,omitempty"`
+	Truncation      string    `json:"truncation,omitempty"`
+	IncludeSummary  *bool     `json:"include_summary,omitempty"`
+	IncludeEvidence *bool     `json:"include_evidence,omitempty"`
+	Locale          string    `json:"locale,omitempty"` // language of verdict labels
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Validate normalizes the options and rejects unknown values
+func (d *AnalysisDefaults) Validate() error {
+	d.Verbosity = strings.ToLower(strings.TrimSpace(d.Verbosity))
+	d.Truncation = strings.ToLower(strings.TrimSpace(d.Truncation))
+	d.Locale = strings.ToLower(strings.TrimSpace(d.Locale))
+	if d.Verbosity != "" && !IsValidVerbosity(d.Verbosity) {
+		return fmt.Errorf("%w: verbosity must be one of minimal, standard, full", ErrInvalidPreferences)
+	}
+	if d.Truncation != "" && !IsValidTruncationStrategy(d.Truncation) {
+		return fmt.Errorf("%w: unknown truncation strategy %q", ErrInvalidPreferences, d.Truncation)
+	}
+	if d.Locale != "" && !IsSupportedLocale(d.Locale) {
+		return fmt.Errorf("%w: unsupported locale %q", ErrInvalidPreferences, d.Locale)
+	}
+	return nil
+}
+
+// Apply fills the options of req that the caller did not send. explicit
+// holds the JSON names of the fields present in the request body, so an
+// explicit false or empty value is kept.
+func (d *AnalysisDefaults) Apply(req *AnalysisRequest, explicit map[string]bool) {
+	if !explicit["verbosity"] && d.Verbosity != "" {
+		req.Verbosity = d.Verbosity
+	}
+	if !explicit["truncation"] && d.Truncation != "" {
+		req.Truncation = d.Truncation
+	}
+	if !explicit["include_summary"] && d.IncludeSummary != nil {
+		req.IncludeSummary = *d.IncludeSummary
+	}
+	if !explicit["include_evidence"] && d.IncludeEvidence != nil {
+		req.IncludeEvidence = *d.IncludeEvidence
+	}
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAnalysisDefaultsApply(t *testing.T) {
+	on, off := true, false
+	defaults := AnalysisDefaults{Verbosity: VerbosityMinimal, Truncation: TruncationHeadTail, IncludeSummary: &on, IncludeEvidence: &off}
+
+	tests := []struct {
+		name     string
+		req      AnalysisRequest
+		explicit []string
+		want     AnalysisRequest
+	}{
+		{
+			name: "defaults fill omitted options",
+			req:  AnalysisRequest{Type: "text", Content: "x"},
+			want: AnalysisRequest{Type: "text", Content: "x", Verbosity: VerbosityMinimal, Truncation: TruncationHeadTail, IncludeSummary: true},
+		},
+		{
+			name:     "explicit values win, including false",
+			req:      AnalysisRequest{Verbosity: VerbosityFull, IncludeEvidence: true},
+			explicit: []string{"verbosity", "include_summary", "include_evidence"},
+			want:     AnalysisRequest{Verbosity: VerbosityFull, Truncation: TruncationHeadTail, IncludeEvidence: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explicit := map[string]bool{}
+			for _, f := range tt.explicit {
+				explicit[f] = true
+			}
+			req := tt.req
+			defaults.Apply(&req, explicit)
+			if req != tt.want {
+				t.Errorf("Apply() = %+v, want %+v", req, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalysisDefaultsValidate(t *testing.T) {
+	valid := AnalysisDefaults{Verbosity: " Standard ", Locale: "ES"}
+	if err := valid.Validate(); err != nil || valid.Verbosity != VerbosityStandard || valid.Locale != "es" {
+		t.Errorf("Validate() = %v, normalized to %+v", err, valid)
+	}
+	for _, d := range []AnalysisDefaults{{Verbosity: "loud"}, {Truncation: "middle"}, {Locale: "xx"}} {
+		if err := d.Validate(); !errors.Is(err, ErrInvalidPreferences) {
+			t.Errorf("Validate(%+v) = %v, want ErrInvalidPreferences", d, err)
+		}
+	}
+}
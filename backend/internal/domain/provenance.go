@@ -0,0 +1,45 @@
+package domain
+
+// Cache layers recorded in Provenance.CacheHits
+const (
+	CacheStoredPrediction = "stored_prediction" // an earlier analysis of the same canonical URL was returned
+	CacheStoredSummary    = "stored_summary"    // the summary came from that earlier analysis
+	CacheStoredEvidence   = "stored_evidence"   // the claims and evidence came from that earlier analysis
+)
+
+// Provenance records how a prediction's verdict was produced, so an
+// evaluation can be reproduced exactly.
+type Provenance struct {
+	// Article extraction (URL requests)
+	ScraperVersion string   `json:"scraper_version,omitempty"`
+	Extractor      string   `json:"extractor,omitempty"` // body extraction strategy, or "ml-service" when the ML service scraped
+	Cleaning       []string `json:"cleaning,omitempty"`  // cleaning steps applied to the text, in order
+
+	// Scoring
+	Truncation    string `json:"truncation,omitempty"`
+	ModelEndpoint string `json:"model_endpoint,omitempty"` // ML endpoint that answered
+	ModelVersion  string `json:"model_version,omitempty"`
+
+	// Fusion settings in effect when the verdict was fused
+	FusionWeights map[string]float64 `json:"fusion_weights,omitempty"`
+	FakeThreshold float64            `json:"fake_threshold,omitempty"`
+
+	CacheHits []string `json:"cache_hits,omitempty"`
+}
+
+// WithCacheHit returns a copy of p that also records a hit on layer. It is
+// nil-safe, so predictions stored before provenance was recorded can
+// still report cache hits.
+func (p *Provenance) WithCacheHit(layer string) *Provenance {
+	copied := Provenance{}
+	if p != nil {
+		copied = *p
+	}
+	for _, hit := range copied.CacheHits {
+		if hit == layer {
+			return &copied
+		}
+	}
+	copied.CacheHits = append(append([]string(nil), copied.CacheHits...), layer)
+	return &copied
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+// PushSubscription is a browser Web Push subscription (the JSON produced by
+// PushManager.subscribe() on the frontend)
+type PushSubscription struct {
+	ID        string               `json:"id"`
+	UserID    string               `json:"user_id"`
+	Endpoint  string               `json:"endpoint"`
+	Keys      PushSubscriptionKeys `json:"keys"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+// PushSubscriptionKeys holds the client's encryption keys (base64url)
+type PushSubscriptionKeys struct {
+	P256dh string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+// Validate validates the push subscription
+func (s *PushSubscription) Validate() error {
+	if s.UserID == "" {
+		return errors.New("user is required")
+	}
+	if s.Endpoint == "" {
+		return errors.New("endpoint is required")
+	}
+	if u, err := url.Parse(s.Endpoint); err != nil || u.Scheme != "https" || u.Host == "" {
+		return errors.New("endpoint must be an https URL")
+	}
+	if s.Keys.P256dh == "" || s.Keys.Auth == "" {
+		return errors.New("keys.p256dh and keys.auth are required")
+	}
+	return nil
+}
+
+// PushNotification is the payload delivered to the service worker
+type PushNotification struct {
+	Title string      `json:"title"`
+	Body  string      `json:"body"`
+	URL   string      `json:"url,omitempty"`  // page to open on click
+	Tag   string      `json:"tag,omitempty"`  // collapses notifications with the same tag
+	Data  interface{} `json:"data,omitempty"` // extra data for the service worker
+}
@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// Report is the nightly summary of one UTC day of analyses
+type Report struct {
+	ID          string    `json:"id"`   // "report-" + Date; regenerating a day replaces it
+	Date        string    `json:"date"` // YYYY-MM-DD (UTC)
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	Total         int     `json:"total"`
+	Fake          int     `json:"fake"`
+	Real          int     `json:"real"`
+	URLRequests   int     `json:"url_requests"`
+	TextRequests  int     `json:"text_requests"`
+	FakeRatio     float64 `json:"fake_ratio"`
+	AvgConfidence float64 `json:"avg_confidence"`
+
+	TopDomains []ReportDomain `json:"top_domains"`
+	Models     []ReportModel  `json:"models"`
+
+	// Stories analyzed repeatedly during the day
+	Clusters []ReportCluster `json:"clusters"`
+
+	// Benchmark evaluations completed during the day; the API collects no
+	// user feedback on verdicts yet, so these are the accuracy measure
+	Evaluations []ReportEvaluation `json:"evaluations"`
+}
+
+// ReportDomain is one article source's volume in a report
+type ReportDomain struct {
+	Domain    string  `json:"domain"`
+	Count     int     `json:"count"`
+	FakeRatio float64 `json:"fake_ratio"`
+}
+
+// ReportModel is one model version's volume in a report
+type ReportModel struct {
+	Version       string  `json:"version"`
+	Count         int     `json:"count"`
+	AvgConfidence float64 `json:"avg_confidence"`
+	Fallbacks     int     `json:"fallbacks"` // Answered by the fallback model
+}
+
+// ReportCluster groups analyses of the same story
+type ReportCluster struct {
+	Key       string  `json:"key"` // Canonical URL, or the title for text analyses
+	Title     string  `json:"title,omitempty"`
+	Count     int     `json:"count"`
+	FakeRatio float64 `json:"fake_ratio"`
+}
+
+// ReportEvaluation is a benchmark run's headline metrics in a report
+type ReportEvaluation struct {
+	ID       string  `json:"id"`
+	Model    string  `json:"model,omitempty"`
+	Total    int     `json:"total"`
+	Accuracy float64 `json:"accuracy"`
+	F1       float64 `json:"f1"`
+}
+
+// ReportID returns the ID of the report for a UTC date
+func ReportID(date string) string {
+	return "report-" + date
+}
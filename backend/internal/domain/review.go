@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Review is a human fact-checker's verdict on a prediction. Reviewed
+// predictions are published as schema.org ClaimReview.
+type Review struct {
+	Verdict    string    `json:"verdict"`  // FAKE or REAL, as judged by the reviewer
+	Claim      string    `json:"claim"`    // the claim reviewed, as it will be published
+	Reviewer   string    `json:"reviewer"` // who reviewed it
+	Note       string    `json:"note,omitempty"`
+	ReviewedAt time.Time `json:"reviewed_at"`
+}
+
+// Validate normalizes the verdict and checks the required fields
+func (r *Review) Validate() error {
+	r.Verdict = strings.ToUpper(strings.TrimSpace(r.Verdict))
+	r.Claim = strings.TrimSpace(r.Claim)
+	r.Reviewer = strings.TrimSpace(r.Reviewer)
+	if r.Verdict != LabelFake && r.Verdict != LabelReal {
+		return fmt.Errorf("%w: verdict must be FAKE or REAL", ErrInvalidReview)
+	}
+	if r.Claim == "" {
+		return fmt.Errorf("%w: claim is required", ErrInvalidReview)
+	}
+	if r.Reviewer == "" {
+		return fmt.Errorf("%w: reviewer is required", ErrInvalidReview)
+	}
+	return nil
+}
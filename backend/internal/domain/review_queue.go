@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// Review queue item states
+const (
+	ReviewItemPending = "pending"
+	ReviewItemClaimed = "claimed"
+)
+
+// ReviewItem is an uncertain prediction waiting for a human verdict.
+type ReviewItem struct {
+	PredictionID string     `json:"prediction_id"`
+	Result       string     `json:"result"`
+	Confidence   float64    `json:"confidence"`
+	Uncertainty  float64    `json:"uncertainty,omitempty"` // model std dev, when reported
+	Status       string     `json:"status"`
+	ClaimedBy    string     `json:"claimed_by,omitempty"`
+	ClaimExpires *time.Time `json:"claim_expires,omitempty"`
+	EnqueuedAt   time.Time  `json:"enqueued_at"`
+	DueAt        time.Time  `json:"due_at"` // review SLA deadline
+}
+
+// Overdue reports whether the item has missed its SLA at now.
+func (i *ReviewItem) Overdue(now time.Time) bool {
+	return now.After(i.DueAt)
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxSavedSearchName bounds a saved search's name
+const maxSavedSearchName = 100
+
+// SearchFilters are the /api/history filters a saved search re-runs.
+type SearchFilters struct {
+	Label         string     `json:"label,omitempty"`
+	Type          string     `json:"type,omitempty"`
+	Domain        string     `json:"domain,omitempty"`
+	Model         string     `json:"model,omitempty"`
+	MinConfidence float64    `json:"min_confidence,omitempty"`
+	MaxConfidence float64    `json:"max_confidence,omitempty"`
+	Since         *time.Time `json:"since,omitempty"`
+	Until         *time.Time `json:"until,omitempty"`
+	PinnedOnly    bool       `json:"pinned,omitempty"`
+}
+
+// Query builds the prediction query for the filters.
+func (f SearchFilters) Query() *PredictionQuery {
+	q := NewPredictionQuery().
+		WithLabel(f.Label).
+		WithRequestType(f.Type).
+		WithDomain(f.Domain).
+		WithModelVersion(f.Model)
+	q.MinConfidence = f.MinConfidence
+	q.MaxConfidence = f.MaxConfidence
+	q.PinnedOnly = f.PinnedOnly
+	if f.Since != nil {
+		q.Since = *f.Since
+	}
+	if f.Until != nil {
+		q.Until = *f.Until
+	}
+	return q
+}
+
+// SavedSearch is a user's named history search. With Notify set, the user
+// is told when predictions created after CheckedUntil match it.
+type SavedSearch struct {
+	ID      string        `json:"id"`
+	UserID  string        `json:"user_id"`
+	Name    string        `json:"name"`
+	Filters SearchFilters `json:"filters"`
+	Notify  bool          `json:"notify"`
+
+	CheckedUntil time.Time  `json:"checked_until"` // newest prediction already considered for notifications
+	LastMatchAt  *time.Time `json:"last_match_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Validate normalizes the name and checks the filters.
+func (s *SavedSearch) Validate() error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidSavedSearch)
+	}
+	if utf8.RuneCountInString(s.Name) > maxSavedSearchName {
+		return fmt.Errorf("%w: name is longer than %d characters", ErrInvalidSavedSearch, maxSavedSearchName)
+	}
+	if label := strings.ToUpper(s.Filters.Label); label != "" && label != LabelFake && label != LabelReal {
+		return fmt.Errorf("%w: label must be FAKE or REAL", ErrInvalidSavedSearch)
+	}
+	if t := s.Filters.Type; t != "" && t != "text" && t != "url" {
+		return fmt.Errorf("%w: type must be text or url", ErrInvalidSavedSearch)
+	}
+	if err := s.Filters.Query().Validate(); err != nil {
+		return fmt.Errorf("%w: invalid confidence or date range", ErrInvalidSavedSearch)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package domain
+
+// Verdict signal names
+const (
+	SignalML               = "ml"
+	SignalHeuristic        = "heuristic"
+	SignalSourceReputation = "source_reputation"
+	SignalFactCheck        = "fact_check"
+	SignalRecency          = "recency"
+	SignalOrgPolicy        = "org_policy"
+	SignalTrustedSource    = "trusted_source"
+	SignalKnownFake        = "known_fake"
+)
+
+// SignalContribution explains how one signal moved the final verdict
+type SignalContribution struct {
+	Name         string  `json:"name"`
+	FakeScore    float64 `json:"fake_score"`       // 0 (real) .. 1 (fake) as judged by this signal
+	Weight       float64 `json:"weight"`           // Configured weight
+	Contribution float64 `json:"contribution"`     // Share of the fused fake score (weight-normalized)
+	Detail       string  `json:"detail,omitempty"` // Human-readable reason
+}
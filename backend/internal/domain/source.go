@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// Source is a publisher entry in the source registry
+type Source struct {
+	Domain   string `json:"domain"`
+	Name     string `json:"name"`
+	Category string `json:"category,omitempty"` // e.g. "mainstream", "satire", "state media"
+	Country  string `json:"country,omitempty"`
+	Notes    string `json:"notes,omitempty"`
+}
+
+// DomainStats aggregates the predictions made for articles from one domain
+type DomainStats struct {
+	Total         int     `json:"total"`
+	Fake          int     `json:"fake"`
+	Real          int     `json:"real"`
+	AvgConfidence float64 `json:"avg_confidence"`
+}
+
+// DomainSummary answers "how trustworthy is this domain" for integrators
+type DomainSummary struct {
+	Domain         string      `json:"domain"`
+	Source         *Source     `json:"source"` // nil when the domain is not in the registry
+	Stats          DomainStats `json:"stats"`
+	Reputation     float64     `json:"reputation"` // 0 (fake) .. 1 (real), 0.5 with no data
+	LastAnalyzedAt *time.Time  `json:"last_analyzed_at"`
+	GeneratedAt    time.Time   `json:"generated_at"`
+}
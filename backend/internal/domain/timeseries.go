@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Time-series metrics
+const (
+	MetricFakeRatio      = "fake_ratio"      // share of predictions labelled FAKE
+	MetricCount          = "count"           // number of predictions
+	MetricAvgConfidence  = "avg_confidence"  // mean verdict confidence
+	MetricAvgUncertainty = "avg_uncertainty" // mean model std dev, over predictions that report one
+)
+
+// MaxTimeSeriesBuckets bounds how many buckets one query may produce.
+const MaxTimeSeriesBuckets = 2000
+
+// TimeSeriesQuery buckets the predictions matching Filter by creation time.
+// Buckets are Interval wide, aligned like time.Truncate (daily buckets start
+// at midnight UTC), and cover [Since, Until).
+type TimeSeriesQuery struct {
+	Metric   string
+	Interval time.Duration
+	Since    time.Time
+	Until    time.Time
+	Filter   PredictionQuery // date range, ordering and paging are ignored
+}
+
+// TimeSeriesPoint is one bucket of a time series. Empty buckets have a
+// Count and Value of 0.
+type TimeSeriesPoint struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+	Value float64   `json:"value"`
+}
+
+// IsValidMetric reports whether m names a known time-series metric
+func IsValidMetric(m string) bool {
+	switch m {
+	case MetricFakeRatio, MetricCount, MetricAvgConfidence, MetricAvgUncertainty:
+		return true
+	}
+	return false
+}
+
+// Validate validates the metric, interval and range
+func (q *TimeSeriesQuery) Validate() error {
+	if !IsValidMetric(q.Metric) {
+		return fmt.Errorf("%w: unknown metric %q", ErrInvalidQuery, q.Metric)
+	}
+	if q.Interval < time.Minute {
+		return fmt.Errorf("%w: interval must be at least 1m", ErrInvalidQuery)
+	}
+	if q.Since.IsZero() || !q.Since.Before(q.Until) {
+		return fmt.Errorf("%w: range must be positive", ErrInvalidQuery)
+	}
+	if q.Until.Sub(q.Since)/q.Interval >= MaxTimeSeriesBuckets {
+		return fmt.Errorf("%w: range spans more than %d intervals", ErrInvalidQuery, MaxTimeSeriesBuckets)
+	}
+	return nil
+}
+
+// Bucket returns the key (Unix seconds of its start) of the bucket
+// containing t
+func (q *TimeSeriesQuery) Bucket(t time.Time) int64 {
+	return t.Truncate(q.Interval).Unix()
+}
+
+// Sample returns what a prediction adds to its bucket's sum
+func (q *TimeSeriesQuery) Sample(p *Prediction) float64 {
+	switch q.Metric {
+	case MetricFakeRatio:
+		if p.Result == LabelFake {
+			return 1
+		}
+	case MetricAvgConfidence:
+		return p.Confidence
+	case MetricAvgUncertainty:
+		if p.Uncertainty != nil {
+			return p.Uncertainty.StdDev
+		}
+	}
+	return 0
+}
+
+// Points turns per-bucket counts and sums of Sample, keyed by Bucket, into
+// the query's series, filling empty buckets.
+func (q *TimeSeriesQuery) Points(counts map[int64]int, sums map[int64]float64) []TimeSeriesPoint {
+	points := make([]TimeSeriesPoint, 0)
+	for start := q.Since.Truncate(q.Interval); start.Before(q.Until); start = start.Add(q.Interval) {
+		key := start.Unix()
+		point := TimeSeriesPoint{Start: start.UTC(), Count: counts[key]}
+		switch {
+		case q.Metric == MetricCount:
+			point.Value = float64(point.Count)
+		case point.Count > 0:
+			point.Value = sums[key] / float64(point.Count)
+		}
+		points = append(points, point)
+	}
+	return points
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultFakeThreshold is the fused fake score at which a verdict becomes
+// FAKE.
+const DefaultFakeThreshold = 0.5
+
+// Tuning holds the verdict parameters that can be changed at runtime.
+// Every change is kept as a new version.
+type Tuning struct {
+	Version int `json:"version"` // 0 is the configuration the process started with
+
+	Weights        map[string]float64 `json:"weights"`         // verdict signal weights; 0 disables a signal
+	FakeThreshold  float64            `json:"fake_threshold"`  // fused fake score at or above which the verdict is FAKE
+	CategoryScores map[string]float64 `json:"category_scores"` // source registry category -> fake score
+
+	Note      string    `json:"note,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks value ranges. Signal names are checked by the fusion
+// stage, which knows which signals exist.
+func (t *Tuning) Validate() error {
+	if t.FakeThreshold <= 0 || t.FakeThreshold >= 1 {
+		return fmt.Errorf("%w: fake_threshold must be between 0 and 1", ErrInvalidTuning)
+	}
+	total := 0.0
+	for name, weight := range t.Weights {
+		if weight < 0 {
+			return fmt.Errorf("%w: weight of %s must not be negative", ErrInvalidTuning, name)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return fmt.Errorf("%w: at least one signal needs a positive weight", ErrInvalidTuning)
+	}
+	for category, score := range t.CategoryScores {
+		if score < 0 || score > 1 {
+			return fmt.Errorf("%w: score of category %q must be between 0 and 1", ErrInvalidTuning, category)
+		}
+	}
+	return nil
+}
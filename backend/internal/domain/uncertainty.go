@@ -0,0 +1,37 @@
+package domain
+
+import "math"
+
+// uncertaintyZ is the normal quantile of the reported interval (95%).
+const uncertaintyZ = 1.96
+
+// Uncertainty is the ML model's Monte-Carlo dropout estimate of how much
+// its fake probability varies between stochastic forward passes, with a
+// 95% interval around the fake probability.
+type Uncertainty struct {
+	Variance float64 `json:"variance"`
+	StdDev   float64 `json:"std_dev"`
+	Lower    float64 `json:"lower"` // interval on fake_probability, clamped to [0, 1]
+	Upper    float64 `json:"upper"`
+	Samples  int     `json:"samples,omitempty"` // forward passes, when the ML service reports them
+}
+
+// NewUncertainty builds the estimate for a fake probability from its
+// variance. A negative variance is treated as zero.
+func NewUncertainty(fakeProbability, variance float64, samples int) *Uncertainty {
+	variance = math.Max(variance, 0)
+	stdDev := math.Sqrt(variance)
+	return &Uncertainty{
+		Variance: variance,
+		StdDev:   stdDev,
+		Lower:    math.Max(fakeProbability-uncertaintyZ*stdDev, 0),
+		Upper:    math.Min(fakeProbability+uncertaintyZ*stdDev, 1),
+		Samples:  samples,
+	}
+}
+
+// Straddles reports whether the interval contains the 0.5 decision
+// boundary, so another set of forward passes could flip the label.
+func (u *Uncertainty) Straddles() bool {
+	return u.Lower < 0.5 && u.Upper > 0.5
+}
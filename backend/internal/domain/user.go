@@ -5,6 +5,33 @@ import (
 	"time"
 )
 
+// User roles, lowest privilege first
+const (
+	RoleMember  = "member"
+	RoleAnalyst = "analyst"
+	RoleAdmin   = "admin"
+)
+
+var roleRanks = map[string]int{
+	RoleMember:  1,
+	RoleAnalyst: 2,
+	RoleAdmin:   3,
+}
+
+// IsValidRole reports whether role is a known role
+func IsValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// HigherRole returns the more privileged of two roles
+func HigherRole(a, b string) string {
+	if roleRanks[b] > roleRanks[a] {
+		return b
+	}
+	return a
+}
+
 // User represents a user entity
 type User struct {
 	ID        string
@@ -12,6 +39,12 @@ type User struct {
 	Name      string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	// Single sign-on
+	OrgID       string // organization the user signed in through
+	Role        string
+	ExternalID  string // "issuer|subject" of the identity provider account
+	LastLoginAt time.Time
 }
 
 // Validate performs validation on the User entity
@@ -0,0 +1,78 @@
+package domain
+
+import "encoding/json"
+
+// Response verbosity levels for prediction payloads
+const (
+	VerbosityMinimal  = "minimal"  // verdict only
+	VerbosityStandard = "standard" // verdict, probabilities, timings and source info
+	VerbosityFull     = "full"     // everything, including original content and tracing
+)
+
+// Client types used to pick a default verbosity
+const (
+	ClientTypeExtension = "extension"
+	ClientTypeDashboard = "dashboard"
+)
+
+// IsValidVerbosity reports whether v names a known verbosity level
+func IsValidVerbosity(v string) bool {
+	switch v {
+	case VerbosityMinimal, VerbosityStandard, VerbosityFull:
+		return true
+	}
+	return false
+}
+
+// DefaultVerbosity returns the verbosity used for a client type when the
+// request does not ask for one. Unknown clients get the full payload so
+// existing integrations keep working.
+func DefaultVerbosity(clientType string) string {
+	switch clientType {
+	case ClientTypeExtension:
+		return VerbosityMinimal
+	case ClientTypeDashboard:
+		return VerbosityFull
+	}
+	return VerbosityFull
+}
+
+// predictionFields lists the JSON fields included at each reduced level
+var predictionFields = map[string][]string{
+	VerbosityMinimal: {
+		"id", "result", "confidence", "display", "fallback_model", "method", "provisional", "stale", "created_at",
+	},
+	VerbosityStandard: {
+		"id", "result", "confidence", "display", "fallback_model", "method", "provisional", "stale", "created_at",
+		"request_type", "canonical_url", "fake_probability", "real_probability",
+		"model_version", "model_route", "article_title", "article_description",
+		"article_author", "article_source", "article_published_at", "content_type", "source_info", "review", "signals", "claims", "summary", "related_articles",
+		"pinned", "processing_time_ms", "refreshed_at",
+	},
+}
+
+// View returns the prediction as it should be serialized at the given
+// verbosity. Full (or unknown) verbosity returns the prediction itself.
+func (p *Prediction) View(verbosity string) interface{} {
+	fields, ok := predictionFields[verbosity]
+	if !ok {
+		return p
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return p
+	}
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return p
+	}
+
+	view := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		if v, ok := all[f]; ok {
+			view[f] = v
+		}
+	}
+	return view
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPredictionView(t *testing.T) {
+	p := &Prediction{
+		ID:              "p1",
+		RequestType:     "url",
+		OriginalContent: "https://example.com/a",
+		Result:          "FAKE",
+		Confidence:      0.9,
+		FakeProbability: 0.9,
+		RealProbability: 0.1,
+		ArticleSource:   "example.com",
+		RequestID:       "req-1",
+		ProcessingTime:  120,
+		CreatedAt:       time.Now(),
+	}
+
+	tests := []struct {
+		verbosity string
+		present   []string
+		absent    []string
+	}{
+		{VerbosityMinimal, []string{"id", "result", "confidence"}, []string{"fake_probability", "article_source", "processing_time_ms", "original_content"}},
+		{VerbosityStandard, []string{"id", "fake_probability", "article_source", "processing_time_ms"}, []string{"original_content", "request_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.verbosity, func(t *testing.T) {
+			view, ok := p.View(tt.verbosity).(map[string]interface{})
+			if !ok {
+				t.Fatalf("expected map view, got %T", p.View(tt.verbosity))
+			}
+			for _, k := range tt.present {
+				if _, ok := view[k]; !ok {
+					t.Errorf("expected %q in %s view", k, tt.verbosity)
+				}
+			}
+			for _, k := range tt.absent {
+				if _, ok := view[k]; ok {
+					t.Errorf("did not expect %q in %s view", k, tt.verbosity)
+				}
+			}
+		})
+	}
+
+	if p.View(VerbosityFull) != p {
+		t.Error("full view should return the prediction itself")
+	}
+}
+
+func TestDefaultVerbosity(t *testing.T) {
+	tests := map[string]string{
+		ClientTypeExtension: VerbosityMinimal,
+		ClientTypeDashboard: VerbosityFull,
+		"":                  VerbosityFull,
+	}
+	for clientType, want := range tests {
+		if got := DefaultVerbosity(clientType); got != want {
+			t.Errorf("DefaultVerbosity(%q) = %q, want %q", clientType, got, want)
+		}
+	}
+}
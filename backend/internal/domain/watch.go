@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// Watch is a user's subscription to changes of an analyzed article. The
+// stored snapshot is what the next recheck is compared against.
+type Watch struct {
+	ID           string `json:"id"`
+	UserID       string `json:"user_id"`
+	URL          string `json:"url"`
+	CanonicalURL string `json:"canonical_url"`
+	Title        string `json:"title,omitempty"`
+
+	// Snapshot from the last successful check
+	ContentHash string  `json:"content_hash"`
+	Result      string  `json:"result"`
+	Confidence  float64 `json:"confidence"`
+
+	CreatedAt     time.Time  `json:"created_at"`
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"`
+	LastChangedAt *time.Time `json:"last_changed_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+
+	// Schedule is the article domain's crawl budget when listed; it is
+	// not stored.
+	Schedule *CrawlSchedule `json:"schedule,omitempty"`
+}
+
+// ArticleSnapshot is the state of an article at one point in time.
+type ArticleSnapshot struct {
+	CanonicalURL string
+	Title        string
+	ContentHash  string
+	Result       string
+	Confidence   float64
+}
+
+// WatchChange describes how an article differs from its watch snapshot.
+type WatchChange struct {
+	Edited         bool
+	VerdictChanged bool
+	PreviousResult string
+}
+
+// Changed reports whether the watcher should be told about the change.
+func (c WatchChange) Changed() bool {
+	return c.Edited || c.VerdictChanged
+}
+
+// Diff compares a fresh snapshot against the watch's stored one.
+func (w *Watch) Diff(s *ArticleSnapshot) WatchChange {
+	return WatchChange{
+		Edited:         s.ContentHash != w.ContentHash,
+		VerdictChanged: s.Result != w.Result,
+		PreviousResult: w.Result,
+	}
+}
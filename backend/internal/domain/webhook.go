@@ -0,0 +1,209 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Webhook event types. WebhookEventTest is only sent by the test-delivery
+// endpoint and cannot be subscribed to.
+const (
+	WebhookPredictionCreated = "prediction.created"
+	WebhookVerdictChanged    = "prediction.verdict_changed"
+	WebhookReviewDecided     = "review.decided"
+	WebhookJobCompleted      = "job.completed"
+	WebhookQuotaThreshold    = "quota.threshold"
+	WebhookEventTest         = "webhook.test"
+)
+
+// WebhookEventTypes lists the event types subscriptions can select.
+var WebhookEventTypes = []string{
+	WebhookPredictionCreated,
+	WebhookVerdictChanged,
+	WebhookReviewDecided,
+	WebhookJobCompleted,
+	WebhookQuotaThreshold,
+}
+
+// maxWebhookURL bounds a subscription's destination URL
+const maxWebhookURL = 2048
+
+// WebhookPrediction is the part of a prediction carried by prediction and
+// review events.
+type WebhookPrediction struct {
+	ID           string    `json:"id"`
+	RequestType  string    `json:"request_type"`
+	URL          string    `json:"url,omitempty"` // the analyzed URL; text is never sent
+	Title        string    `json:"title,omitempty"`
+	Source       string    `json:"source,omitempty"`
+	Result       string    `json:"result"`
+	Confidence   float64   `json:"confidence"`
+	ModelVersion string    `json:"model_version,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewWebhookPrediction summarizes p for an event.
+func NewWebhookPrediction(p *Prediction) *WebhookPrediction {
+	wp := &WebhookPrediction{
+		ID:           p.ID,
+		RequestType:  p.RequestType,
+		Title:        p.ArticleTitle,
+		Source:       p.ArticleSource,
+		Result:       p.Result,
+		Confidence:   p.Confidence,
+		ModelVersion: p.ModelVersion,
+		CreatedAt:    p.CreatedAt,
+	}
+	if p.RequestType == "url" {
+		wp.URL = p.OriginalContent
+	}
+	return wp
+}
+
+// WebhookVerdict is a prediction's verdict before a re-score.
+type WebhookVerdict struct {
+	Result       string  `json:"result"`
+	Confidence   float64 `json:"confidence"`
+	ModelVersion string  `json:"model_version,omitempty"`
+}
+
+// WebhookJob is a finished background job.
+type WebhookJob struct {
+	ID       string `json:"id"`
+	Kind     string `json:"kind"`
+	Status   string `json:"status"` // done or failed
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+}
+
+// WebhookEvent is the JSON body of a webhook delivery. Which of the
+// optional fields are set depends on Type.
+type WebhookEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Prediction *WebhookPrediction `json:"prediction,omitempty"` // prediction.*, review.decided
+	Previous   *WebhookVerdict    `json:"previous,omitempty"`   // prediction.verdict_changed
+	Review     *Review            `json:"review,omitempty"`     // review.decided
+	Job        *WebhookJob        `json:"job,omitempty"`        // job.completed
+	Quota      *QuotaCrossing     `json:"quota,omitempty"`      // quota.threshold
+
+	// OwnerID is whose data the event is about; empty for system events
+	// such as job completion
+	OwnerID string `json:"-"`
+}
+
+// WebhookFilter narrows the events a subscription receives. Prediction
+// filters apply to prediction and review events, JobKind to job events
+// and Dimension to quota events; events a filter does not apply to pass.
+type WebhookFilter struct {
+	Label         string  `json:"label,omitempty"`
+	Type          string  `json:"type,omitempty"`
+	Domain        string  `json:"domain,omitempty"`
+	MinConfidence float64 `json:"min_confidence,omitempty"`
+	JobKind       string  `json:"job_kind,omitempty"`
+	Dimension     string  `json:"dimension,omitempty"`
+}
+
+// Matches reports whether event passes the filter.
+func (f WebhookFilter) Matches(event *WebhookEvent) bool {
+	if p := event.Prediction; p != nil {
+		if f.Label != "" && !strings.EqualFold(p.Result, f.Label) {
+			return false
+		}
+		if f.Type != "" && p.RequestType != f.Type {
+			return false
+		}
+		if domain := strings.ToLower(f.Domain); domain != "" {
+			source := strings.ToLower(p.Source)
+			if source != domain && !strings.HasSuffix(source, "."+domain) {
+				return false
+			}
+		}
+		if p.Confidence < f.MinConfidence {
+			return false
+		}
+	}
+	if event.Job != nil && f.JobKind != "" && event.Job.Kind != f.JobKind {
+		return false
+	}
+	if event.Quota != nil && f.Dimension != "" && event.Quota.Dimension != f.Dimension {
+		return false
+	}
+	return true
+}
+
+// WebhookSubscription sends the selected event types to a URL. Events are
+// signed with Secret. A subscription receives events about its owner's
+// predictions and quota; with AllOwners, set only by admins, it receives
+// everyone's and system events too.
+type WebhookSubscription struct {
+	ID        string        `json:"id"`
+	OwnerID   string        `json:"owner_id"`
+	URL       string        `json:"url"`
+	Events    []string      `json:"events"`
+	Filter    WebhookFilter `json:"filter"`
+	AllOwners bool          `json:"all_owners,omitempty"`
+	Active    bool          `json:"active"`
+	Secret    string        `json:"secret,omitempty"` // shown once, on creation
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// Validate normalizes the event list and checks the URL and filter.
+func (s *WebhookSubscription) Validate() error {
+	u, err := url.Parse(strings.TrimSpace(s.URL))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: credentials in url are not allowed", ErrInvalidWebhook)
+	}
+	if len(s.URL) > maxWebhookURL {
+		return fmt.Errorf("%w: url is longer than %d characters", ErrInvalidWebhook, maxWebhookURL)
+	}
+	s.URL = u.String()
+
+	if len(s.Events) == 0 {
+		return fmt.Errorf("%w: at least one event type is required", ErrInvalidWebhook)
+	}
+	events := make([]string, 0, len(s.Events))
+	for _, e := range s.Events {
+		if !slices.Contains(WebhookEventTypes, e) {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidWebhook, e)
+		}
+		if !slices.Contains(events, e) {
+			events = append(events, e)
+		}
+	}
+	s.Events = events
+
+	if label := strings.ToUpper(s.Filter.Label); label != "" && label != LabelFake && label != LabelReal {
+		return fmt.Errorf("%w: label must be FAKE or REAL", ErrInvalidWebhook)
+	}
+	if t := s.Filter.Type; t != "" && t != "text" && t != "url" {
+		return fmt.Errorf("%w: type must be text or url", ErrInvalidWebhook)
+	}
+	if s.Filter.MinConfidence < 0 || s.Filter.MinConfidence > 1 {
+		return fmt.Errorf("%w: min_confidence must be between 0 and 1", ErrInvalidWebhook)
+	}
+	if d := s.Filter.Dimension; d != "" && !IsValidQuota(d) {
+		return fmt.Errorf("%w: dimension must be scrapes or inferences", ErrInvalidWebhook)
+	}
+	return nil
+}
+
+// Wants reports whether the subscription receives event.
+func (s *WebhookSubscription) Wants(event *WebhookEvent) bool {
+	if !s.Active || !slices.Contains(s.Events, event.Type) {
+		return false
+	}
+	if !s.AllOwners && (event.OwnerID == "" || event.OwnerID != s.OwnerID) {
+		return false
+	}
+	return s.Filter.Matches(event)
+}
@@ -0,0 +1,1385 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// AdminHandler handles operator-only HTTP requests
+type AdminHandler struct {
+	adminToken  string
+	abuseGuard  *middleware.AbuseGuard
+	newsService *service.NewsService
+	evaluations *service.EvaluationService
+	maintenance *middleware.Maintenance
+	jobs        *service.JobQueue
+	reports     *service.ReportService
+	deliveries  *service.DeliveryEngine
+	corpora     *service.CorpusBuilder
+	outbound    *service.OutboundAudit
+	tuning      *service.TuningService
+	reviewQueue *service.ReviewQueue
+	audits      *service.AuditSampler
+	violations  *middleware.KeyViolations
+	bulkDeletes *service.BulkDeleter
+	crawlBudget *service.CrawlBudget
+}
+
+// NewAdminHandler creates a new admin handler. An empty token disables
+// token access to the admin endpoints; users signed in with the admin role
+// can use them either way.
+func NewAdminHandler(adminToken string, abuseGuard *middleware.AbuseGuard,
+	newsService *service.NewsService) *AdminHandler {
+	return &AdminHandler{
+		adminToken:  adminToken,
+		abuseGuard:  abuseGuard,
+		newsService: newsService,
+	}
+}
+
+// WithEvaluations enables the benchmark evaluation endpoints
+func (h *AdminHandler) WithEvaluations(evaluations *service.EvaluationService) *AdminHandler {
+	h.evaluations = evaluations
+	return h
+}
+
+// WithMaintenance enables the maintenance mode endpoint
+func (h *AdminHandler) WithMaintenance(maintenance *middleware.Maintenance) *AdminHandler {
+	h.maintenance = maintenance
+	return h
+}
+
+// WithJobQueue enables the dead-letter queue endpoints
+func (h *AdminHandler) WithJobQueue(jobs *service.JobQueue) *AdminHandler {
+	h.jobs = jobs
+	return h
+}
+
+// WithReports enables the nightly report endpoints
+func (h *AdminHandler) WithReports(reports *service.ReportService) *AdminHandler {
+	h.reports = reports
+	return h
+}
+
+// WithDeliveries enables the delivery dead-letter endpoints
+func (h *AdminHandler) WithDeliveries(deliveries *service.DeliveryEngine) *AdminHandler {
+	h.deliveries = deliveries
+	return h
+}
+
+// WithCorpora enables the research corpus endpoints
+func (h *AdminHandler) WithCorpora(corpora *service.CorpusBuilder) *AdminHandler {
+	h.corpora = corpora
+	return h
+}
+
+// WithOutboundAudit enables the outbound destination report
+func (h *AdminHandler) WithOutboundAudit(audit *service.OutboundAudit) *AdminHandler {
+	h.outbound = audit
+	return h
+}
+
+// WithTuning enables the runtime verdict tuning endpoint
+func (h *AdminHandler) WithTuning(tuning *service.TuningService) *AdminHandler {
+	h.tuning = tuning
+	return h
+}
+
+// WithReviewQueue enables the human review queue endpoints
+func (h *AdminHandler) WithReviewQueue(queue *service.ReviewQueue) *AdminHandler {
+	h.reviewQueue = queue
+	return h
+}
+
+// WithAuditSampler enables the spot-check audit sampling endpoints
+func (h *AdminHandler) WithAuditSampler(audits *service.AuditSampler) *AdminHandler {
+	h.audits = audits
+	return h
+}
+
+// WithKeyViolations enables the key IP allowlist violation report
+func (h *AdminHandler) WithKeyViolations(violations *middleware.KeyViolations) *AdminHandler {
+	h.violations = violations
+	return h
+}
+
+// WithBulkDeleter enables bulk deletion of predictions by filter
+func (h *AdminHandler) WithBulkDeleter(bulkDeletes *service.BulkDeleter) *AdminHandler {
+	h.bulkDeletes = bulkDeletes
+	return h
+}
+
+// WithCrawlBudget enables the crawl budget endpoints
+func (h *AdminHandler) WithCrawlBudget(budget *service.CrawlBudget) *AdminHandler {
+	h.crawlBudget = budget
+	return h
+}
+
+// banRequest is the payload for POST /api/admin/bans
+type banRequest struct {
+	IP              string `json:"ip"`
+	Reason          string `json:"reason"`
+	DurationSeconds int    `json:"duration_seconds"`
+}
+
+// rescoreRequest is the payload for POST /api/admin/rescore
+type rescoreRequest struct {
+	ID    string `json:"id"`
+	Model string `json:"model,omitempty"`
+}
+
+// Rescore handles POST /api/admin/rescore
+func (h *AdminHandler) Rescore(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req rescoreRequest
+	if err := decodeRequest(r, &req); err != nil || req.ID == "" {
+		respondWithError(w, http.StatusBadRequest, "prediction id is required")
+		return
+	}
+
+	previous, err := h.newsService.GetPrediction(req.ID)
+	if err != nil {
+		respondWithServiceError(w, err, "Failed to retrieve prediction")
+		return
+	}
+
+	rescored, err := h.newsService.Rescore(r.Context(), req.ID, req.Model)
+	if err != nil {
+		respondWithServiceError(w, err, "Failed to rescore prediction")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"previous": previous,
+		"rescored": rescored,
+		"flipped":  previous.Result != rescored.Result,
+	})
+}
+
+// Review handles POST and DELETE /api/admin/predictions/{id}/review
+//
+// POST records a human fact-checker's verdict ({"verdict", "claim",
+// "reviewer", "note"}), publishing the prediction as ClaimReview; DELETE
+// withdraws it. Signed-in reviewers default to their own ID.
+func (h *AdminHandler) Review(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+
+	var prediction *domain.Prediction
+	var err error
+	switch r.Method {
+	case http.MethodPost:
+		var review domain.Review
+		if err := decodeRequest(r, &review); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if principal, ok := middleware.PrincipalFromContext(r.Context()); ok && review.Reviewer == "" {
+			review.Reviewer = principal.ID
+		}
+		prediction, err = h.newsService.ReviewPrediction(r.Context(), r.PathValue("id"), ifMatchVersion(r), review)
+	case http.MethodDelete:
+		prediction, err = h.newsService.RemoveReview(r.Context(), r.PathValue("id"))
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if errors.Is(err, domain.ErrVersionMismatch) {
+		setVersionETag(w, prediction)
+		respondWithJSON(w, http.StatusPreconditionFailed, map[string]interface{}{
+			"error":   "Prediction has changed since it was fetched; re-fetch it and review again",
+			"version": prediction.Version(),
+		})
+		return
+	}
+	if err != nil {
+		respondWithServiceError(w, err, "Failed to update review")
+		return
+	}
+
+	setVersionETag(w, prediction)
+	respondWithJSON(w, http.StatusOK, domain.PredictionResponse{
+		Success:    true,
+		Prediction: prediction,
+	})
+}
+
+// queueReviewer returns the caller's reviewer identity, or responds 400.
+// Signed-in reviewers are identified by their session; admin token
+// callers name themselves in the body's reviewer field.
+func queueReviewer(w http.ResponseWriter, r *http.Request, named string) (string, bool) {
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		return principal.ID, true
+	}
+	if named = strings.TrimSpace(named); named == "" {
+		respondWithError(w, http.StatusBadRequest, "reviewer is required")
+		return "", false
+	}
+	return named, true
+}
+
+// ReviewQueue handles GET /api/admin/review-queue?status=pending|claimed|overdue
+func (h *AdminHandler) ReviewQueue(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.reviewQueue == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", domain.ReviewItemPending, domain.ReviewItemClaimed, "overdue":
+	default:
+		respondWithError(w, http.StatusBadRequest, "status must be pending, claimed or overdue")
+		return
+	}
+	items := h.reviewQueue.List(status)
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"count":   len(items),
+		"items":   items,
+		"stats":   h.reviewQueue.Stats(),
+	})
+}
+
+// ClaimReviewItem handles POST /api/admin/review-queue/claim
+//
+// Claims the queued prediction closest to its SLA deadline for the caller.
+// Responds 204 when the queue is empty.
+func (h *AdminHandler) ClaimReviewItem(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.reviewQueue == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	var req domain.Review
+	if r.ContentLength != 0 {
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	reviewer, ok := queueReviewer(w, r, req.Reviewer)
+	if !ok {
+		return
+	}
+
+	for {
+		item, ok := h.reviewQueue.Claim(reviewer)
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		prediction, err := h.newsService.GetPrediction(item.PredictionID)
+		if errors.Is(err, domain.ErrPredictionNotFound) {
+			// Removed by retention since it was queued.
+			h.reviewQueue.Remove(item.PredictionID)
+			continue
+		}
+		if err != nil {
+			h.reviewQueue.Release(item.PredictionID, reviewer)
+			respondWithError(w, http.StatusInternalServerError, "Failed to load prediction")
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success":    true,
+			"item":       item,
+			"prediction": prediction,
+		})
+		return
+	}
+}
+
+// UpdateReviewItem handles POST /api/admin/review-queue/{id}/{action} where
+// action is decision or release
+func (h *AdminHandler) UpdateReviewItem(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.reviewQueue == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	var req domain.Review
+	if err := decodeRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	reviewer, ok := queueReviewer(w, r, req.Reviewer)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("id")
+	var prediction *domain.Prediction
+	var err error
+	switch r.PathValue("action") {
+	case "decision":
+		prediction, err = h.reviewQueue.Decide(r.Context(), id, reviewer, req)
+	case "release":
+		err = h.reviewQueue.Release(id, reviewer)
+	default:
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrReviewItemNotFound), errors.Is(err, domain.ErrPredictionNotFound):
+			respondWithError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, domain.ErrReviewItemClaimed):
+			respondWithError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, domain.ErrInvalidReview):
+			respondWithError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Failed to update review item")
+		}
+		return
+	}
+
+	if prediction == nil {
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+		return
+	}
+	setVersionETag(w, prediction)
+	respondWithJSON(w, http.StatusOK, domain.PredictionResponse{
+		Success:    true,
+		Prediction: prediction,
+	})
+}
+
+// auditedRequest is the payload for POST /api/admin/audit-samples/{id}/audited
+type auditedRequest struct {
+	Reviewer      string   `json:"reviewer"`
+	PredictionIDs []string `json:"prediction_ids"` // empty marks the whole sample
+}
+
+// AuditSample handles GET /api/admin/audit-sample?n=50&strategy=stratified&seed=&days=
+//
+// Draws and records a random sample of recent, unaudited predictions. A
+// missing seed is chosen at random and returned, so the draw can be
+// repeated.
+func (h *AdminHandler) AuditSample(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.audits == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	params := r.URL.Query()
+	req := domain.AuditSampleRequest{
+		Strategy: params.Get("strategy"),
+		Seed:     time.Now().UnixNano(),
+	}
+	var err error
+	if v := params.Get("n"); v != "" {
+		if req.Size, err = strconv.Atoi(v); err != nil {
+			respondWithError(w, http.StatusBadRequest, "invalid n")
+			return
+		}
+	}
+	if v := params.Get("days"); v != "" {
+		if req.Days, err = strconv.Atoi(v); err != nil {
+			respondWithError(w, http.StatusBadRequest, "invalid days")
+			return
+		}
+	}
+	if v := params.Get("seed"); v != "" {
+		if req.Seed, err = strconv.ParseInt(v, 10, 64); err != nil {
+			respondWithError(w, http.StatusBadRequest, "invalid seed")
+			return
+		}
+	}
+
+	sample, err := h.audits.Sample(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAuditSample) {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to draw audit sample")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"sample":  sample,
+	})
+}
+
+// AuditSamples handles GET /api/admin/audit-samples
+func (h *AdminHandler) AuditSamples(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.audits == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	samples := h.audits.List()
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"count":   len(samples),
+		"samples": samples,
+	})
+}
+
+// GetAuditSample handles GET /api/admin/audit-samples/{id} and POST
+// /api/admin/audit-samples/{id}/audited, which records the audit of some
+// or all of the sample's predictions
+func (h *AdminHandler) GetAuditSample(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if h.audits == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	var sample *domain.AuditSample
+	var err error
+	switch {
+	case r.Method == http.MethodGet && !strings.HasSuffix(r.URL.Path, "/audited"):
+		sample, err = h.audits.Get(r.PathValue("id"))
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/audited"):
+		var req auditedRequest
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		reviewer, ok := queueReviewer(w, r, req.Reviewer)
+		if !ok {
+			return
+		}
+		sample, err = h.audits.MarkAudited(r.PathValue("id"), reviewer, req.PredictionIDs)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAuditSampleNotFound):
+			respondWithError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, domain.ErrInvalidAuditSample):
+			respondWithError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Failed to load audit sample")
+		}
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"sample":  sample,
+	})
+}
+
+// bulkDeleteRequest is the payload for POST
+// /api/admin/predictions/bulk-delete
+type bulkDeleteRequest struct {
+	Filters domain.SearchFilters `json:"filters"` // as for /api/history and saved searches
+	Reason  string               `json:"reason"`
+}
+
+// BulkDelete handles POST /api/admin/predictions/bulk-delete?dry_run=true
+//
+// With dry_run the matching predictions are counted and sampled but kept;
+// otherwise they are deleted by a background job, answered with 202 and
+// followed at /api/admin/bulk-deletes/{id}.
+func (h *AdminHandler) BulkDelete(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.bulkDeletes == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	var req bulkDeleteRequest
+	if err := decodeRequest(r, &req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if r.URL.Query().Get("dry_run") == "true" {
+		preview, err := h.bulkDeletes.Preview(r.Context(), req.Filters)
+		if err != nil {
+			respondWithBulkDeleteError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"dry_run": true,
+			"preview": preview,
+		})
+		return
+	}
+
+	requestedBy := "admin"
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		requestedBy = principal.ID
+	}
+	job, err := h.bulkDeletes.Start(r.Context(), req.Filters, requestedBy, strings.TrimSpace(req.Reason))
+	if err != nil {
+		respondWithBulkDeleteError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusAccepted, map[string]interface{}{
+		"success":  true,
+		"job":      job,
+		"progress": job.Progress(),
+	})
+}
+
+// BulkDeletes handles GET /api/admin/bulk-deletes
+func (h *AdminHandler) BulkDeletes(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.bulkDeletes == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	jobs := h.bulkDeletes.List()
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"count":   len(jobs),
+		"jobs":    jobs,
+	})
+}
+
+// GetBulkDelete handles GET /api/admin/bulk-deletes/{id}
+func (h *AdminHandler) GetBulkDelete(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.bulkDeletes == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	job, err := h.bulkDeletes.Get(r.PathValue("id"))
+	if err != nil {
+		respondWithBulkDeleteError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"job":      job,
+		"progress": job.Progress(),
+	})
+}
+
+func respondWithBulkDeleteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrBulkDeleteNotFound):
+		respondWithError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidBulkDelete):
+		respondWithError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondWithError(w, http.StatusInternalServerError, "Bulk delete failed")
+	}
+}
+
+// crawlRaiseRequest is the payload for POST /api/admin/crawl-budget/{domain}
+type crawlRaiseRequest struct {
+	ExtraPages      int `json:"extra_pages"`
+	DurationSeconds int `json:"duration_seconds"`
+}
+
+// CrawlBudget handles GET /api/admin/crawl-budget, every tracked domain's
+// crawl budget, and GET, POST and DELETE /api/admin/crawl-budget/{domain}
+//
+// POST temporarily raises the domain's daily pages ({"extra_pages",
+// "duration_seconds"}); DELETE ends the raise.
+func (h *AdminHandler) CrawlBudget(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if h.crawlBudget == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	host := r.PathValue("domain")
+	if host == "" {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		domains := h.crawlBudget.Stats()
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"count":   len(domains),
+			"domains": domains,
+		})
+		return
+	}
+
+	var schedule domain.CrawlSchedule
+	var err error
+	switch r.Method {
+	case http.MethodGet:
+		schedule = h.crawlBudget.Schedule(host)
+	case http.MethodPost:
+		var req crawlRaiseRequest
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		schedule, err = h.crawlBudget.Raise(host, req.ExtraPages, time.Duration(req.DurationSeconds)*time.Second)
+	case http.MethodDelete:
+		schedule, err = h.crawlBudget.Raise(host, 0, 0)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"schedule": schedule,
+	})
+}
+
+// Bans handles GET, POST and DELETE /api/admin/bans
+func (h *AdminHandler) Bans(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		bans := h.abuseGuard.Bans()
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"count":   len(bans),
+			"bans":    bans,
+		})
+
+	case http.MethodPost:
+		var req banRequest
+		if err := decodeRequest(r, &req); err != nil || req.IP == "" {
+			respondWithError(w, http.StatusBadRequest, "ip is required")
+			return
+		}
+		if req.Reason == "" {
+			req.Reason = "banned by admin"
+		}
+		ban := h.abuseGuard.BanIP(req.IP, req.Reason, time.Duration(req.DurationSeconds)*time.Second)
+		respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+			"success": true,
+			"ban":     ban,
+		})
+
+	case http.MethodDelete:
+		ip := r.URL.Query().Get("ip")
+		if ip == "" {
+			respondWithError(w, http.StatusBadRequest, "ip is required")
+			return
+		}
+		if !h.abuseGuard.Unban(ip) {
+			respondWithError(w, http.StatusNotFound, "No active ban for ip")
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"ip":      ip,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// maintenanceRequest is the payload for POST /api/admin/maintenance
+type maintenanceRequest struct {
+	Enabled  bool       `json:"enabled"`
+	Message  string     `json:"message"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+// Maintenance handles GET, POST and DELETE /api/admin/maintenance
+//
+// POST turns maintenance on or off, optionally for a scheduled
+// starts_at/ends_at window; DELETE turns it off.
+func (h *AdminHandler) Maintenance(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if h.maintenance == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	window := h.maintenance.Window()
+	switch r.Method {
+	case http.MethodGet:
+
+	case http.MethodPost, http.MethodDelete:
+		var next middleware.MaintenanceWindow
+		if r.Method == http.MethodPost {
+			var req maintenanceRequest
+			if err := decodeRequest(r, &req); err != nil {
+				respondWithError(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+			if req.EndsAt != nil && !req.EndsAt.After(time.Now()) {
+				respondWithError(w, http.StatusBadRequest, "ends_at must be in the future")
+				return
+			}
+			next = middleware.MaintenanceWindow{
+				Enabled:  req.Enabled,
+				Message:  req.Message,
+				StartsAt: req.StartsAt,
+				EndsAt:   req.EndsAt,
+			}
+		}
+		if err := next.Validate(); err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		var err error
+		if window, err = h.maintenance.Set(next); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to save maintenance state")
+			return
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":     true,
+		"active":      window.ActiveAt(time.Now()),
+		"maintenance": window,
+	})
+}
+
+// maxImportBytes caps the size of an uploaded prototype dump
+const maxImportBytes = 64 << 20
+
+// Import handles POST /api/admin/import
+//
+// The body is the prototype's JSON dump; ?dry_run=true validates and
+// counts without storing anything.
+func (h *AdminHandler) Import(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxImportBytes+1))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to read import file")
+		return
+	}
+	if len(data) > maxImportBytes {
+		respondWithError(w, http.StatusRequestEntityTooLarge, "Import file too large")
+		return
+	}
+
+	stats, err := h.newsService.ImportLegacyPredictions(r.Context(), data, r.URL.Query().Get("dry_run") == "true")
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidImport) {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Import failed")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"stats":   stats,
+	})
+}
+
+// maxDatasetBytes caps the size of an uploaded evaluation dataset
+const maxDatasetBytes = 32 << 20
+
+// Evaluate handles POST /api/evaluate
+//
+// The body is a text,label CSV, sent raw or as the "file" field of a
+// multipart form; ?model= picks a model version. Scoring runs in the
+// background and the run is polled at GET /api/evaluations/{id}.
+func (h *AdminHandler) Evaluate(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.evaluations == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxDatasetBytes)
+	var dataset io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "file is required")
+			return
+		}
+		defer file.Close()
+		dataset = file
+	}
+
+	run, err := h.evaluations.Start(r.Context(), dataset, r.URL.Query().Get("model"))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			respondWithError(w, http.StatusRequestEntityTooLarge, "Dataset too large")
+		case errors.Is(err, domain.ErrInvalidDataset):
+			respondWithError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Failed to start evaluation")
+		}
+		return
+	}
+
+	w.Header().Set("Location", "/api/evaluations/"+run.ID)
+	respondWithJSON(w, http.StatusAccepted, map[string]interface{}{
+		"success":    true,
+		"evaluation": run,
+	})
+}
+
+// GetEvaluation handles GET /api/evaluations/{id}
+func (h *AdminHandler) GetEvaluation(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.evaluations == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	run, err := h.evaluations.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Evaluation not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"evaluation": run,
+	})
+}
+
+// Jobs handles GET /api/admin/jobs?status=
+//
+// Lists background jobs in one state, failed (the dead-letter queue) by
+// default; status=all lists every job.
+func (h *AdminHandler) Jobs(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.jobs == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = domain.JobFailed
+	case "all":
+		status = ""
+	}
+	jobs := h.jobs.List(status)
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"count":   len(jobs),
+		"jobs":    jobs,
+	})
+}
+
+// RequeueJob handles POST /api/admin/jobs/{id}/requeue
+func (h *AdminHandler) RequeueJob(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.jobs == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	job, err := h.jobs.Requeue(r.Context(), r.PathValue("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrJobNotFound):
+			respondWithError(w, http.StatusNotFound, "Job not found")
+		case errors.Is(err, domain.ErrJobNotFailed):
+			respondWithError(w, http.StatusConflict, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Failed to requeue job")
+		}
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"job":     job,
+	})
+}
+
+// Deliveries handles GET /api/admin/deliveries
+//
+// Lists webhook and push deliveries that ran out of attempts, and each
+// destination's circuit breaker.
+func (h *AdminHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.deliveries == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	dead := h.deliveries.DeadLetters()
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":      true,
+		"count":        len(dead),
+		"dead_letters": dead,
+		"destinations": h.deliveries.Destinations(),
+	})
+}
+
+// Redeliver handles POST /api/admin/deliveries/{id}/redeliver
+func (h *AdminHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.deliveries == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	delivery, err := h.deliveries.Redeliver(r.Context(), r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Delivery not found")
+		return
+	}
+	respondWithJSON(w, http.StatusAccepted, map[string]interface{}{
+		"success":  true,
+		"delivery": delivery,
+	})
+}
+
+// Reports handles GET /api/reports and POST /api/reports?date=YYYY-MM-DD
+//
+// GET lists the stored nightly reports, newest first. POST generates (or
+// regenerates) the report for a UTC date, yesterday by default.
+func (h *AdminHandler) Reports(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if h.reports == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		reports, err := h.reports.List(r.Context())
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to list reports")
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"count":   len(reports),
+			"reports": reports,
+		})
+	case http.MethodPost:
+		day := time.Now().UTC().AddDate(0, 0, -1)
+		if date := r.URL.Query().Get("date"); date != "" {
+			parsed, err := time.Parse("2006-01-02", date)
+			if err != nil {
+				respondWithError(w, http.StatusBadRequest, "date must be YYYY-MM-DD")
+				return
+			}
+			day = parsed
+		}
+		report, _, err := h.reports.Generate(r.Context(), day)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to generate report")
+			return
+		}
+		w.Header().Set("Location", "/api/reports/"+report.ID)
+		respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+			"success": true,
+			"report":  report,
+		})
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetReport handles GET /api/reports/{id} and GET /api/reports/{id}/html
+func (h *AdminHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.reports == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	id := r.PathValue("id")
+	if strings.HasSuffix(r.URL.Path, "/html") {
+		html, err := h.reports.HTML(r.Context(), id)
+		if err != nil {
+			respondWithError(w, http.StatusNotFound, "Report not found")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(html)
+		return
+	}
+
+	report, err := h.reports.Get(r.Context(), id)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Report not found")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"report":  report,
+	})
+}
+
+// Corpora handles GET and POST /api/admin/corpora
+//
+// POST samples a balanced, anonymized training corpus from stored
+// predictions; GET lists the manifests of corpora built so far.
+func (h *AdminHandler) Corpora(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if h.corpora == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		manifests, err := h.corpora.List(r.Context())
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to list corpora")
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"count":   len(manifests),
+			"corpora": manifests,
+		})
+	case http.MethodPost:
+		var req domain.CorpusRequest
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		manifest, err := h.corpora.Build(r.Context(), req)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrInvalidCorpusRequest):
+				respondWithError(w, http.StatusBadRequest, err.Error())
+			case errors.Is(err, domain.ErrEmptyCorpus):
+				respondWithError(w, http.StatusUnprocessableEntity, err.Error())
+			default:
+				respondWithError(w, http.StatusInternalServerError, "Failed to build corpus")
+			}
+			return
+		}
+		w.Header().Set("Location", "/api/admin/corpora/"+manifest.ID)
+		respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+			"success":  true,
+			"manifest": manifest,
+		})
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetCorpus handles GET /api/admin/corpora/{id} and GET
+// /api/admin/corpora/{id}/data, the JSONL export
+func (h *AdminHandler) GetCorpus(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.corpora == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	id := r.PathValue("id")
+	if strings.HasSuffix(r.URL.Path, "/data") {
+		data, err := h.corpora.Data(r.Context(), id)
+		if err != nil {
+			respondWithError(w, http.StatusNotFound, "Corpus not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+id+`.jsonl"`)
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+		return
+	}
+
+	manifest, err := h.corpora.Get(r.Context(), id)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Corpus not found")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"manifest": manifest,
+	})
+}
+
+// Licenses handles GET /api/admin/licenses, the composition of stored
+// content by license and how much of it research exports may include
+func (h *AdminHandler) Licenses(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.corpora == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	composition, err := h.corpora.LicenseComposition(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load license composition")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":     true,
+		"composition": composition,
+	})
+}
+
+// Outbound handles GET /api/admin/outbound?limit=N
+//
+// Reports every external destination contacted since startup, by purpose,
+// with the N most recent requests (default 100).
+func (h *AdminHandler) Outbound(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.outbound == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			respondWithError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		limit = n
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"report":  h.outbound.Report(limit),
+	})
+}
+
+// KeyViolations handles GET /api/admin/key-violations?limit=
+func (h *AdminHandler) KeyViolations(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.violations == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			respondWithError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		limit = n
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"report":  h.violations.Report(limit),
+	})
+}
+
+// Tuning handles GET and PUT /api/admin/tuning
+//
+// PUT takes any of weights, fake_threshold and category_scores plus a
+// note, and applies them as a new version at once.
+func (h *AdminHandler) Tuning(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if h.tuning == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"tuning":  h.tuning.Current(),
+			"history": h.tuning.History(),
+		})
+
+	case http.MethodPut:
+		var change domain.Tuning
+		if err := decodeRequest(r, &change); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		actor := "admin token"
+		if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+			actor = principal.ID
+		}
+		tuning, err := h.tuning.Update(change, actor)
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidTuning) {
+				respondWithError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, "Failed to save tuning")
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"tuning":  tuning,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *AdminHandler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if isAdminRequest(r, h.adminToken) {
+		return true
+	}
+	// Service accounts reach here only through their route's RequireScope
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok && middleware.IsServiceAccount(principal) {
+		return true
+	}
+	if h.adminToken == "" {
+		respondWithError(w, http.StatusNotFound, "Not found")
+	} else {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+	}
+	return false
+}
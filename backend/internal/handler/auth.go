@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+)
+
+// UserFromContext returns the user a request was authenticated as, by a
+// bearer JWT or a single sign-on session. Signing keys, service accounts
+// and API keys are callers but not users, and are not returned.
+func UserFromContext(ctx context.Context) (*middleware.Principal, bool) {
+	principal, ok := middleware.PrincipalFromContext(ctx)
+	if !ok || (principal.Method != middleware.AuthJWT && principal.Method != middleware.AuthSession) {
+		return nil, false
+	}
+	return principal, true
+}
+
+// isAdminRequest reports whether a request is made by an admin user or
+// with the admin bearer token. Service accounts are not admins: they get
+// only what their scopes grant.
+func isAdminRequest(r *http.Request, adminToken string) bool {
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok && principal.Role == domain.RoleAdmin {
+		return true
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1
+}
@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// cborCodec implements application/cbor (RFC 8949) for the JSON data model.
+type cborCodec struct{}
+
+func (cborCodec) ContentType() string { return "application/cbor" }
+
+func (cborCodec) Encode(w io.Writer, v interface{}) error {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	if err := encodeCBOR(bw, generic); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func (cborCodec) Decode(r io.Reader, v interface{}) error {
+	generic, err := decodeCBOR(bufio.NewReader(r), 0)
+	if err != nil {
+		return fmt.Errorf("invalid cbor: %w", err)
+	}
+	return fromGeneric(generic, v)
+}
+
+// CBOR major types
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborTag    = 6
+	cborSimple = 7
+)
+
+// cborBreak ends an indefinite-length item.
+const cborBreak = 0xff
+
+func encodeCBOR(w *bufio.Writer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		return w.WriteByte(0xf6)
+	case bool:
+		if v {
+			return w.WriteByte(0xf5)
+		}
+		return w.WriteByte(0xf4)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			if i >= 0 {
+				writeCBORHead(w, cborUint, uint64(i))
+			} else {
+				writeCBORHead(w, cborNegInt, uint64(-1-i))
+			}
+			return nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return err
+		}
+		w.WriteByte(0xfb)
+		return binary.Write(w, binary.BigEndian, math.Float64bits(f))
+	case string:
+		writeCBORHead(w, cborText, uint64(len(v)))
+		_, err := w.WriteString(v)
+		return err
+	case []interface{}:
+		writeCBORHead(w, cborArray, uint64(len(v)))
+		for _, item := range v {
+			if err := encodeCBOR(w, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		writeCBORHead(w, cborMap, uint64(len(v)))
+		for _, k := range sortedKeys(v) {
+			if err := encodeCBOR(w, k); err != nil {
+				return err
+			}
+			if err := encodeCBOR(w, v[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("cbor: unsupported type %T", v)
+}
+
+// writeCBORHead writes a major type with its argument in the shortest form.
+func writeCBORHead(w *bufio.Writer, major byte, n uint64) {
+	m := major << 5
+	switch {
+	case n < 24:
+		w.WriteByte(m | byte(n))
+	case n <= math.MaxUint8:
+		w.Write([]byte{m | 24, byte(n)})
+	case n <= math.MaxUint16:
+		w.WriteByte(m | 25)
+		binary.Write(w, binary.BigEndian, uint16(n))
+	case n <= math.MaxUint32:
+		w.WriteByte(m | 26)
+		binary.Write(w, binary.BigEndian, uint32(n))
+	default:
+		w.WriteByte(m | 27)
+		binary.Write(w, binary.BigEndian, n)
+	}
+}
+
+// errCBORBreak is returned when a break code ends an indefinite item.
+var errCBORBreak = errors.New("unexpected cbor break")
+
+func decodeCBOR(r *bufio.Reader, depth int) (interface{}, error) {
+	if depth > maxDecodeNesting {
+		return nil, errors.New("cbor nesting too deep")
+	}
+	b, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if b == cborBreak {
+		return nil, errCBORBreak
+	}
+	major, info := b>>5, b&0x1f
+
+	if major == cborSimple {
+		switch info {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23: // null, undefined
+			return nil, nil
+		case 25:
+			n, err := readUint(r, 2)
+			return halfToFloat(uint16(n)), err
+		case 26:
+			n, err := readUint(r, 4)
+			return float64(math.Float32frombits(uint32(n))), err
+		case 27:
+			n, err := readUint(r, 8)
+			return math.Float64frombits(n), err
+		}
+		return nil, fmt.Errorf("unsupported cbor simple value %d", info)
+	}
+
+	indefinite := info == 31
+	var n uint64
+	if !indefinite {
+		if n, err = readCBORArgument(r, info); err != nil {
+			return nil, err
+		}
+	} else if major == cborUint || major == cborNegInt || major == cborTag {
+		return nil, fmt.Errorf("invalid indefinite length for major type %d", major)
+	}
+
+	switch major {
+	case cborUint:
+		if n > math.MaxInt64 {
+			return float64(n), nil
+		}
+		return int64(n), nil
+	case cborNegInt:
+		if n > math.MaxInt64 {
+			return -1 - float64(n), nil
+		}
+		return -1 - int64(n), nil
+	case cborBytes, cborText:
+		if !indefinite {
+			if n > maxDecodeLength {
+				return nil, fmt.Errorf("string of %d bytes is too large", n)
+			}
+			return readString(r, int(n))
+		}
+		var s []byte
+		for {
+			chunk, err := decodeCBOR(r, depth+1)
+			if errors.Is(err, errCBORBreak) {
+				return string(s), nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			str, ok := chunk.(string)
+			if !ok || len(s)+len(str) > maxDecodeLength {
+				return nil, errors.New("invalid indefinite-length string")
+			}
+			s = append(s, str...)
+		}
+	case cborArray:
+		if !indefinite && n > maxDecodeLength {
+			return nil, fmt.Errorf("array of %d items is too large", n)
+		}
+		items := make([]interface{}, 0)
+		for i := uint64(0); indefinite || i < n; i++ {
+			item, err := decodeCBOR(r, depth+1)
+			if indefinite && errors.Is(err, errCBORBreak) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case cborMap:
+		if !indefinite && n > maxDecodeLength {
+			return nil, fmt.Errorf("map of %d entries is too large", n)
+		}
+		m := make(map[string]interface{})
+		for i := uint64(0); indefinite || i < n; i++ {
+			key, err := decodeCBOR(r, depth+1)
+			if indefinite && errors.Is(err, errCBORBreak) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeCBOR(r, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			m[fmt.Sprint(key)] = value
+		}
+		return m, nil
+	case cborTag:
+		// Tags (dates, bignums, ...) are dropped; the tagged item is kept.
+		return decodeCBOR(r, depth+1)
+	}
+	return nil, fmt.Errorf("unsupported cbor major type %d", major)
+}
+
+// readCBORArgument reads the argument that follows an initial byte.
+func readCBORArgument(r *bufio.Reader, info byte) (uint64, error) {
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info <= 27:
+		return readUint(r, 1<<(info-24))
+	}
+	return 0, fmt.Errorf("invalid cbor additional info %d", info)
+}
+
+// halfToFloat converts an IEEE 754 half-precision float.
+func halfToFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
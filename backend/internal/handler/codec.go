@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Codec encodes response payloads and decodes request bodies in one
+// media type. Non-JSON codecs bridge through encoding/json so payloads keep
+// the same field names and shapes in every format.
+type Codec interface {
+	ContentType() string
+	Encode(w io.Writer, v interface{}) error
+	Decode(r io.Reader, v interface{}) error
+}
+
+// codecs lists the supported media types; JSON is the default.
+var codecs = map[string]Codec{
+	"application/json":    jsonCodec{},
+	"application/msgpack": msgpackCodec{},
+	"application/cbor":    cborCodec{},
+}
+
+// RegisterCodec adds or replaces the codec for its media type.
+func RegisterCodec(c Codec) {
+	codecs[c.ContentType()] = c
+}
+
+// ContentNegotiation picks the response codec from the Accept header.
+// Clients that do not ask for a supported format get JSON.
+func ContentNegotiation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		codec := negotiateCodec(r.Header.Get("Accept"))
+		if codec.ContentType() != "application/json" {
+			w.Header().Add("Vary", "Accept")
+			w = &negotiatedWriter{ResponseWriter: w, codec: codec}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// negotiateCodec returns the first supported media type in accept,
+// honoring q-values.
+func negotiateCodec(accept string) Codec {
+	type candidate struct {
+		codec Codec
+		q     float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		codec, ok := codecs[mediaType]
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			fmt.Sscanf(v, "%g", &q)
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{codec, q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	if len(candidates) == 0 {
+		return jsonCodec{}
+	}
+	return candidates[0].codec
+}
+
+// negotiatedWriter carries the response codec chosen for the request.
+type negotiatedWriter struct {
+	http.ResponseWriter
+	codec Codec
+}
+
+func (n *negotiatedWriter) Unwrap() http.ResponseWriter {
+	return n.ResponseWriter
+}
+
+// responseCodec finds the negotiated codec through any wrapping writers.
+func responseCodec(w http.ResponseWriter) Codec {
+	for {
+		switch rw := w.(type) {
+		case *negotiatedWriter:
+			return rw.codec
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = rw.Unwrap()
+		default:
+			return jsonCodec{}
+		}
+	}
+}
+
+// decodeRequest reads the request body in its declared content type.
+// Bodies in any other (or no) content type are read as JSON, as before
+// negotiation existed.
+func decodeRequest(r *http.Request, v interface{}) error {
+	codec := Codec(jsonCodec{})
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil {
+		if c, ok := codecs[mediaType]; ok {
+			codec = c
+		}
+	}
+	return codec.Decode(r.Body, v)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string { return "application/json" }
+
+func (jsonCodec) Encode(w io.Writer, v interface{}) error { return json.NewEncoder(w).Encode(v) }
+
+func (jsonCodec) Decode(r io.Reader, v interface{}) error { return json.NewDecoder(r).Decode(v) }
+
+// toGeneric converts v to the maps, slices, strings, numbers, bools and
+// nils of its JSON form.
+func toGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic interface{}
+	err = dec.Decode(&generic)
+	return generic, err
+}
+
+// fromGeneric fills v from a decoded generic value.
+func fromGeneric(generic, v interface{}) error {
+	data, err := json.Marshal(generic)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// sortedKeys returns m's keys in order so encodings are deterministic.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	want := domain.Prediction{
+		ID:              "p1",
+		Result:          "FAKE",
+		Confidence:      0.875,
+		FakeProbability: 0.875,
+		RealProbability: 0.125,
+		OriginalContent: string(bytes.Repeat([]byte("long article text "), 40)),
+		RelatedArticles: []string{"https://example.com/a", "https://example.com/b"},
+		ProcessingTime:  -1,
+		CreatedAt:       createdAt,
+	}
+
+	for _, codec := range []Codec{jsonCodec{}, msgpackCodec{}, cborCodec{}} {
+		t.Run(codec.ContentType(), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := codec.Encode(&buf, want); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			var got domain.Prediction
+			if err := codec.Decode(&buf, &got); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestCodecKnownEncodings(t *testing.T) {
+	payload := map[string]interface{}{"a": 1, "b": []interface{}{true, nil, -2}}
+	tests := []struct {
+		codec Codec
+		want  string
+	}{
+		// {"a": 1, "b": [true, nil, -2]}
+		{msgpackCodec{}, "82a16101a16293c3c0fe"},
+		{cborCodec{}, "a2616101616283f5f621"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := tt.codec.Encode(&buf, payload); err != nil {
+			t.Fatalf("%s: Encode() error = %v", tt.codec.ContentType(), err)
+		}
+		if got := hex.EncodeToString(buf.Bytes()); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.codec.ContentType(), got, tt.want)
+		}
+	}
+}
+
+func TestContentNegotiation(t *testing.T) {
+	h := ContentNegotiation(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req domain.AnalysisRequest
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondWithJSON(w, http.StatusOK, req)
+	}))
+
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", "application/json"},
+		{"text/html, application/msgpack", "application/msgpack"},
+		{"application/msgpack;q=0.5, application/cbor", "application/cbor"},
+		{"application/xml", "application/json"},
+	}
+	for _, tt := range tests {
+		var body bytes.Buffer
+		cborCodec{}.Encode(&body, domain.AnalysisRequest{Type: "text", Content: "hello"})
+		req := httptest.NewRequest(http.MethodPost, "/api/analyze", &body)
+		req.Header.Set("Content-Type", "application/cbor")
+		req.Header.Set("Accept", tt.accept)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if got := rec.Header().Get("Content-Type"); got != tt.want {
+			t.Errorf("Accept %q: Content-Type = %q, want %q", tt.accept, got, tt.want)
+		}
+		var got domain.AnalysisRequest
+		if err := codecs[tt.want].Decode(rec.Body, &got); err != nil || got.Content != "hello" {
+			t.Errorf("Accept %q: decoded %+v, err %v", tt.accept, got, err)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Client version headers. X-API-Version N is read as client version N.0.
+const (
+	HeaderClientVersion = "X-Client-Version"
+	HeaderAPIVersion    = "X-API-Version"
+)
+
+// ResponseShim adapts predictions in a response for clients older than
+// Before, the first client version that understands the current shape.
+// Apply edits the prediction's JSON form in place.
+type ResponseShim struct {
+	Name   string
+	Before string
+	Apply  func(prediction map[string]interface{})
+}
+
+// legacyShapeVersion is the first client version that reads nested
+// prediction fields; older mobile apps expect the original flat shape.
+const legacyShapeVersion = "2.0"
+
+// responseShims lists the shims in the order they are applied.
+var responseShims = []ResponseShim{
+	{Name: "source_info", Before: legacyShapeVersion, Apply: func(p map[string]interface{}) {
+		flattenField(p, "source_info", "source_")
+	}},
+	{Name: "display", Before: legacyShapeVersion, Apply: func(p map[string]interface{}) {
+		flattenField(p, "display", "display_")
+	}},
+	{Name: "review", Before: legacyShapeVersion, Apply: func(p map[string]interface{}) {
+		flattenField(p, "review", "review_")
+	}},
+	{Name: "license", Before: legacyShapeVersion, Apply: func(p map[string]interface{}) {
+		flattenField(p, "license", "content_")
+	}},
+	{Name: "claims", Before: legacyShapeVersion, Apply: func(p map[string]interface{}) {
+		claims, ok := p["claims"].([]interface{})
+		if !ok {
+			return
+		}
+		texts := make([]interface{}, 0, len(claims))
+		for _, c := range claims {
+			if claim, ok := c.(map[string]interface{}); ok {
+				texts = append(texts, claim["text"])
+			}
+		}
+		p["claims"] = texts
+	}},
+	{Name: "breakdowns", Before: legacyShapeVersion, Apply: func(p map[string]interface{}) {
+		// Per-signal, provenance and scrape breakdowns have no flat equivalent.
+		delete(p, "signals")
+		delete(p, "provenance")
+		delete(p, "scrape_diagnostics")
+	}},
+}
+
+// RegisterResponseShim adds a shim, applied after the existing ones.
+func RegisterResponseShim(shim ResponseShim) {
+	responseShims = append(responseShims, shim)
+}
+
+// ClientCompatibility adapts prediction payloads to the shape the calling
+// client expects, from its X-Client-Version or X-API-Version header.
+// Clients that send neither get the current shape.
+func ClientCompatibility(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if shims := shimsFor(clientVersion(r)); len(shims) > 0 {
+			w.Header().Add("Vary", HeaderClientVersion+", "+HeaderAPIVersion)
+			w = &shimmedWriter{ResponseWriter: w, shims: shims}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientVersion returns the caller's version as numeric parts, or nil if
+// it sent none or it does not parse.
+func clientVersion(r *http.Request) []int {
+	if v := r.Header.Get(HeaderClientVersion); v != "" {
+		return parseVersion(v)
+	}
+	if v := r.Header.Get(HeaderAPIVersion); v != "" {
+		return parseVersion(v)
+	}
+	return nil
+}
+
+// parseVersion parses a dotted version such as "1.4.2" or "v2". Build
+// and pre-release suffixes are ignored.
+func parseVersion(v string) []int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, _, _ = strings.Cut(v, "-")
+	v, _, _ = strings.Cut(v, "+")
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return nil
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+// compareVersions returns -1, 0 or 1; missing parts count as 0.
+func compareVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// shimsFor returns the shims that apply to a client version.
+func shimsFor(version []int) []ResponseShim {
+	if version == nil {
+		return nil
+	}
+	var shims []ResponseShim
+	for _, shim := range responseShims {
+		if compareVersions(version, parseVersion(shim.Before)) < 0 {
+			shims = append(shims, shim)
+		}
+	}
+	return shims
+}
+
+// shimmedWriter carries the shims chosen for the request.
+type shimmedWriter struct {
+	http.ResponseWriter
+	shims []ResponseShim
+}
+
+func (s *shimmedWriter) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// writerShims finds the request's shims through any wrapping writers.
+func writerShims(w http.ResponseWriter) []ResponseShim {
+	for {
+		switch rw := w.(type) {
+		case *shimmedWriter:
+			return rw.shims
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = rw.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+// applyShims returns payload's JSON form with every prediction in it
+// adapted. The payload is returned unchanged if it cannot be converted.
+func applyShims(payload interface{}, shims []ResponseShim) interface{} {
+	generic, err := toGeneric(payload)
+	if err != nil {
+		return payload
+	}
+	shimPredictions(generic, shims)
+	return generic
+}
+
+// shimPredictions walks a generic JSON value and adapts every object that
+// looks like a prediction.
+func shimPredictions(v interface{}, shims []ResponseShim) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, child := range v {
+			shimPredictions(child, shims)
+		}
+		if isPrediction(v) {
+			for _, shim := range shims {
+				shim.Apply(v)
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			shimPredictions(child, shims)
+		}
+	}
+}
+
+func isPrediction(v map[string]interface{}) bool {
+	_, id := v["id"]
+	_, result := v["result"]
+	_, confidence := v["confidence"]
+	return id && result && confidence
+}
+
+// flattenField replaces the object under key with prefixed top-level
+// fields. Fields already present are not overwritten.
+func flattenField(p map[string]interface{}, key, prefix string) {
+	nested, ok := p[key].(map[string]interface{})
+	delete(p, key)
+	if !ok {
+		return
+	}
+	for k, v := range nested {
+		if _, exists := p[prefix+k]; !exists {
+			p[prefix+k] = v
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+func TestClientCompatibility(t *testing.T) {
+	prediction := &domain.Prediction{
+		ID:         "p1",
+		Result:     domain.LabelFake,
+		Confidence: 0.9,
+		SourceInfo: &domain.SourceInfo{Domain: "news.example", Name: "Example News"},
+		Display:    &domain.VerdictDisplay{Locale: "en", Label: "Likely fake", Color: "#d32f2f"},
+		Claims:     []domain.Claim{{Text: "The moon is cheese.", Evidence: []domain.Evidence{{URL: "https://a.example"}}}},
+		Signals:    []domain.SignalContribution{{Name: domain.SignalML, FakeScore: 0.9, Weight: 1}},
+	}
+	h := ClientCompatibility(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondWithJSON(w, http.StatusOK, domain.PredictionResponse{Success: true, Prediction: prediction})
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		legacy bool
+	}{
+		{"no version", "", "", false},
+		{"old app", HeaderClientVersion, "1.9.3", true},
+		{"old app pre-release", HeaderClientVersion, "v1.4.0-beta.2", true},
+		{"current app", HeaderClientVersion, "2.0", false},
+		{"newer app", HeaderClientVersion, "2.1.0", false},
+		{"api version 1", HeaderAPIVersion, "1", true},
+		{"unparsable", HeaderClientVersion, "nightly", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/predictions?id=p1", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			var body struct {
+				Prediction map[string]interface{} `json:"prediction"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			p := body.Prediction
+			_, nested := p["source_info"]
+			if nested == tt.legacy {
+				t.Fatalf("source_info present = %v, want %v: %v", nested, !tt.legacy, p)
+			}
+			if !tt.legacy {
+				return
+			}
+
+			want := map[string]interface{}{
+				"source_domain": "news.example",
+				"source_name":   "Example News",
+				"display_label": "Likely fake",
+				"display_color": "#d32f2f",
+				"claims":        []interface{}{"The moon is cheese."},
+			}
+			for k, v := range want {
+				if !reflect.DeepEqual(p[k], v) {
+					t.Errorf("%s = %v, want %v", k, p[k], v)
+				}
+			}
+			for _, k := range []string{"display", "signals"} {
+				if _, ok := p[k]; ok {
+					t.Errorf("legacy prediction still has %s", k)
+				}
+			}
+			if w.Header().Get("Vary") == "" {
+				t.Error("legacy response has no Vary header")
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// DomainHandler handles domain-level lookups for integrators
+type DomainHandler struct {
+	summaryService *service.DomainSummaryService
+	branding       *service.BrandingService
+}
+
+// NewDomainHandler creates a new domain handler
+func NewDomainHandler(summaryService *service.DomainSummaryService) *DomainHandler {
+	return &DomainHandler{summaryService: summaryService}
+}
+
+// WithBranding serves cached publisher favicons and logos.
+func (h *DomainHandler) WithBranding(branding *service.BrandingService) *DomainHandler {
+	h.branding = branding
+	return h
+}
+
+// Summary handles GET /api/v1/domains/{domain}/summary
+func (h *DomainHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	summary, err := h.summaryService.Summary(r.Context(), r.PathValue("domain"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidDomain) {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to build domain summary")
+		return
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.summaryService.TTL().Seconds())))
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"summary": summary,
+	})
+}
+
+// Branding handles GET /api/sources/{domain}/{asset}, serving the cached
+// favicon or logo linked from a prediction's source_info.
+func (h *DomainHandler) Branding(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	asset := r.PathValue("asset")
+	if h.branding == nil || (asset != service.BrandingFavicon && asset != service.BrandingLogo) {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	contentType, data, err := h.branding.Image(r.Context(), r.PathValue("domain"), asset)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// serviceError is the response a client gets for an error returned by
+// the news service. Client errors send the error's own text, which says
+// what to fix; server errors send a fixed message, so upstream response
+// bodies and other internals never reach the client.
+type serviceError struct {
+	err     error
+	status  int
+	message string // empty to send the error's text
+}
+
+// serviceErrors maps the news service's sentinel errors to responses. It
+// is matched in order with errors.Is, so a wrapped error is found by the
+// first sentinel in its chain that is listed.
+var serviceErrors = []serviceError{
+	// Client errors
+	{domain.ErrInvalidRequestType, http.StatusBadRequest, ""},
+	{domain.ErrEmptyContent, http.StatusBadRequest, ""},
+	{domain.ErrInvalidTruncation, http.StatusBadRequest, ""},
+	{domain.ErrInvalidDepth, http.StatusBadRequest, ""},
+	{domain.ErrInvalidLanguage, http.StatusBadRequest, ""},
+	{domain.ErrInvalidVerbosity, http.StatusBadRequest, ""},
+	{domain.ErrInvalidQuery, http.StatusBadRequest, ""},
+	{domain.ErrInvalidReview, http.StatusBadRequest, ""},
+	{domain.ErrInvalidURL, http.StatusBadRequest, ""},
+	{domain.ErrUnsupportedContentType, http.StatusUnsupportedMediaType, ""},
+	{domain.ErrNotAnArticle, http.StatusUnprocessableEntity, ""},
+	{domain.ErrPredictionNotFound, http.StatusNotFound, "Prediction not found"},
+	{domain.ErrNotReviewed, http.StatusNotFound, "Prediction has not been reviewed"},
+	{domain.ErrPinLimitReached, http.StatusForbidden, ""},
+	{domain.ErrPinnedByOther, http.StatusForbidden, ""},
+	{domain.ErrQuotaExceeded, http.StatusTooManyRequests, ""},
+
+	// Server errors
+	{domain.ErrURLScrapingFailed, http.StatusBadGateway, "Failed to scrape URL content"},
+	{domain.ErrMLServiceUnavailable, http.StatusServiceUnavailable, "ML service unavailable"},
+	{domain.ErrPredictionFailed, http.StatusServiceUnavailable, "ML service unavailable"},
+	{domain.ErrRepositoryTimeout, http.StatusServiceUnavailable, "Storage is temporarily unavailable"},
+}
+
+// lookupServiceError returns the status and message for err. Errors
+// missing from serviceErrors are internal: 500 with fallback.
+func lookupServiceError(err error, fallback string) (int, string) {
+	for _, e := range serviceErrors {
+		if errors.Is(err, e.err) {
+			if e.message == "" {
+				return e.status, err.Error()
+			}
+			return e.status, e.message
+		}
+	}
+	return http.StatusInternalServerError, fallback
+}
+
+// respondWithServiceError responds to a failed news service call, with
+// the scraper's diagnostics when err carries them. Internal errors are
+// logged, since the client only sees fallback.
+func respondWithServiceError(w http.ResponseWriter, err error, fallback string) {
+	status, message := lookupServiceError(err, fallback)
+	if status == http.StatusInternalServerError {
+		log.Printf("Warning: %s: %v", fallback, err)
+	}
+	respondWithScrapeError(w, status, message, err)
+}
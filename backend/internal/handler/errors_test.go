@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+func TestServiceErrorMapping(t *testing.T) {
+	for _, e := range serviceErrors {
+		// Services wrap sentinels with detail, sometimes more than once
+		err := fmt.Errorf("analyze: %w", fmt.Errorf("%w: upstream said no", e.err))
+		status, message := lookupServiceError(err, "fallback")
+		if status != e.status {
+			t.Errorf("%v: status = %d, want %d", e.err, status, e.status)
+		}
+		switch {
+		case status >= 500 && strings.Contains(message, "upstream said no"):
+			t.Errorf("%v: server error leaks its detail: %q", e.err, message)
+		case e.message == "" && message != err.Error():
+			t.Errorf("%v: message = %q, want the error's text", e.err, message)
+		case e.message != "" && message != e.message:
+			t.Errorf("%v: message = %q, want %q", e.err, message, e.message)
+		}
+	}
+
+	status, message := lookupServiceError(errors.New("disk on fire"), "Failed to retrieve history")
+	if status != http.StatusInternalServerError || message != "Failed to retrieve history" {
+		t.Errorf("unknown error = %d %q, want 500 with the fallback", status, message)
+	}
+
+	// The ML fallback failure wraps the scrape error, which wins
+	err := fmt.Errorf("%w (ML fallback also failed: %v)", domain.ErrURLScrapingFailed, domain.ErrMLServiceUnavailable)
+	if status, _ := lookupServiceError(err, ""); status != http.StatusBadGateway {
+		t.Errorf("scrape failure with ML fallback failure: status = %d, want 502", status)
+	}
+}
+
+func TestRespondWithServiceErrorIncludesDiagnostics(t *testing.T) {
+	diagnostics := &domain.ScrapeDiagnostics{URL: "https://example.com/a", BlockedBy: "paywall"}
+	err := &service.ScrapeError{Err: fmt.Errorf("%w: HTTP 402", domain.ErrURLScrapingFailed), Diagnostics: diagnostics}
+
+	rec := httptest.NewRecorder()
+	respondWithServiceError(rec, err, "Internal server error")
+	var body struct {
+		Error       string                    `json:"error"`
+		Diagnostics *domain.ScrapeDiagnostics `json:"diagnostics"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusBadGateway || body.Error != "Failed to scrape URL content" {
+		t.Errorf("response = %d %q, want 502 with the fixed message", rec.Code, body.Error)
+	}
+	if body.Diagnostics == nil || body.Diagnostics.BlockedBy != "paywall" {
+		t.Errorf("diagnostics = %+v, want the scraper's", body.Diagnostics)
+	}
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// setVersionETag sends the prediction's version as a strong ETag.
+func setVersionETag(w http.ResponseWriter, p *domain.Prediction) {
+	w.Header().Set("ETag", `"`+p.Version()+`"`)
+}
+
+// ifMatchVersion returns the version named by the If-Match header, or ""
+// when the header is absent or "*". Weak tags are compared as strong ones,
+// and only the first tag of a list is used.
+func ifMatchVersion(r *http.Request) string {
+	tag, _, _ := strings.Cut(r.Header.Get("If-Match"), ",")
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+	if tag == "*" {
+		return ""
+	}
+	return strings.Trim(tag, `"`)
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		header, want string
+	}{
+		{"", ""},
+		{"*", ""},
+		{`"abc123"`, "abc123"},
+		{`W/"abc123"`, "abc123"},
+		{`"abc123", "def456"`, "abc123"},
+		{"abc123", "abc123"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/api/admin/predictions/p1/review", nil)
+		if tt.header != "" {
+			r.Header.Set("If-Match", tt.header)
+		}
+		if got := ifMatchVersion(r); got != tt.want {
+			t.Errorf("ifMatchVersion(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+)
+
+// feedItems is how many recent predictions a history feed carries
+const feedItems = 50
+
+// rssFeed is an RSS 2.0 document
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Category    string  `xml:"category"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// newHistoryFeed renders predictions as an RSS feed whose self link is
+// feedURL
+func newHistoryFeed(feedURL, siteURL string, predictions []*domain.Prediction) rssFeed {
+	items := make([]rssItem, 0, len(predictions))
+	for _, p := range predictions {
+		items = append(items, rssItem{
+			Title:       feedItemTitle(p),
+			Link:        feedItemLink(p),
+			Description: feedItemDescription(p),
+			GUID:        rssGUID{Value: "prediction:" + p.ID},
+			PubDate:     p.CreatedAt.Format(time.RFC1123Z),
+			Category:    p.Result,
+		})
+	}
+	return rssFeed{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         "Fake news analysis history",
+			Link:          siteURL,
+			Description:   "Your most recent fake news analyses with verdict and confidence",
+			LastBuildDate: time.Now().Format(time.RFC1123Z),
+			Self:          atomLink{Href: feedURL, Rel: "self", Type: "application/rss+xml"},
+			Items:         items,
+		},
+	}
+}
+
+func feedItemTitle(p *domain.Prediction) string {
+	title := p.ArticleTitle
+	if title == "" {
+		title = p.OriginalContent
+	}
+	if runes := []rune(title); len(runes) > 100 {
+		title = string(runes[:100]) + "…"
+	}
+	return fmt.Sprintf("[%s] %s", p.Result, title)
+}
+
+func feedItemLink(p *domain.Prediction) string {
+	if p.RequestType != "url" {
+		return ""
+	}
+	if p.CanonicalURL != "" {
+		return p.CanonicalURL
+	}
+	return p.OriginalContent
+}
+
+func feedItemDescription(p *domain.Prediction) string {
+	description := fmt.Sprintf("Verdict: %s (%.0f%% confidence). Fake probability %.0f%%, real probability %.0f%%. Model %s.",
+		p.Result, p.Confidence*100, p.FakeProbability*100, p.RealProbability*100, p.ModelVersion)
+	if p.Summary != "" {
+		description += " Summary: " + p.Summary
+	}
+	return description
+}
+
+// HistoryFeed handles GET /api/history/feed.xml
+//
+// The feed is authorized by the ?token= issued at GET /api/history/feed,
+// since feed readers cannot send an Authorization header.
+func (h *NewsHandler) HistoryFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.feedTokens == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	ownerID, err := h.feedTokens.Verify(r.URL.Query().Get("token"))
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Invalid feed token")
+		return
+	}
+
+	query := domain.NewPredictionQuery().WithPage(feedItems, 0)
+	query.OwnerID = ownerID
+	predictions, err := h.newsService.QueryHistory(r.Context(), query)
+	if err != nil {
+		respondWithServiceError(w, err, "Failed to retrieve history")
+		return
+	}
+
+	data, err := xml.MarshalIndent(newHistoryFeed(h.feedURL(r, ownerID), h.baseURL(r), predictions), "", "  ")
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to render feed")
+		return
+	}
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	w.Write(data)
+}
+
+// HistoryFeedURL handles GET /api/history/feed, returning the caller's
+// private feed URL
+func (h *NewsHandler) HistoryFeedURL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.feedTokens == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"feed_url": h.feedURL(r, principal.ID),
+	})
+}
+
+func (h *NewsHandler) feedURL(r *http.Request, ownerID string) string {
+	return h.baseURL(r) + "/api/history/feed.xml?token=" + url.QueryEscape(h.feedTokens.Issue(ownerID))
+}
+
+func (h *NewsHandler) baseURL(r *http.Request) string {
+	if h.publicBaseURL != "" {
+		return h.publicBaseURL
+	}
+	scheme := "http"
+	if isHTTPS(r) {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+func TestGetHistoryIsScopedToCaller(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	for i, owner := range []string{"ada", "ada", "grace", ""} {
+		p := &domain.Prediction{
+			ID: string(rune('a' + i)), RequestType: "text", Result: domain.LabelReal,
+			OwnerID: owner, CreatedAt: time.Now().Add(-time.Duration(i) * time.Minute),
+		}
+		if err := repo.CreatePrediction(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := NewNewsHandler(service.NewNewsService(service.NewMLClient(""), service.NewScraperService(), repo)).
+		WithAdminToken("s3cret")
+
+	get := func(target string, principal *middleware.Principal, token string) (int, []string) {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		if principal != nil {
+			r = r.WithContext(middleware.ContextWithPrincipal(r.Context(), principal))
+		}
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.GetHistory(rec, r)
+		var body struct {
+			History []domain.Prediction `json:"history"`
+		}
+		json.NewDecoder(rec.Body).Decode(&body)
+		var ids []string
+		for _, p := range body.History {
+			ids = append(ids, p.ID)
+		}
+		return rec.Code, ids
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		principal  *middleware.Principal
+		token      string
+		wantStatus int
+		wantIDs    int
+	}{
+		{"own history", "/api/history", &middleware.Principal{ID: "ada", Role: domain.RoleMember}, "", http.StatusOK, 2},
+		{"filters still apply", "/api/history?label=FAKE", &middleware.Principal{ID: "ada"}, "", http.StatusOK, 0},
+		{"no identity", "/api/history", nil, "", http.StatusOK, 0},
+		{"member asking for all", "/api/history?all=true", &middleware.Principal{ID: "ada", Role: domain.RoleMember}, "", http.StatusForbidden, 0},
+		{"admin user", "/api/history?all=true", &middleware.Principal{ID: "root", Role: domain.RoleAdmin}, "", http.StatusOK, 4},
+		{"admin user without all", "/api/history", &middleware.Principal{ID: "root", Role: domain.RoleAdmin}, "", http.StatusOK, 0},
+		{"admin token", "/api/history?all=true", nil, "s3cret", http.StatusOK, 4},
+		{"wrong token", "/api/history?all=true", nil, "guess", http.StatusForbidden, 0},
+	}
+	for _, tt := range tests {
+		status, ids := get(tt.target, tt.principal, tt.token)
+		if status != tt.wantStatus || len(ids) != tt.wantIDs {
+			t.Errorf("%s: %d with %v, want %d with %d predictions", tt.name, status, ids, tt.wantStatus, tt.wantIDs)
+		}
+		if tt.principal != nil && tt.principal.ID == "ada" {
+			for _, id := range ids {
+				if id != "a" && id != "b" {
+					t.Errorf("%s: got %s, which ada does not own", tt.name, id)
+				}
+			}
+		}
+	}
+}
+
+func TestGetHistoryPages(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		p := &domain.Prediction{
+			ID: string(rune('a' + i)), RequestType: "text", Result: domain.LabelReal,
+			OwnerID: "ada", CreatedAt: now.Add(-time.Duration(i) * time.Minute),
+		}
+		if err := repo.CreatePrediction(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := NewNewsHandler(service.NewNewsService(service.NewMLClient(""), service.NewScraperService(), repo))
+
+	get := func(target string) (int, []string, domain.PageInfo) {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r = r.WithContext(middleware.ContextWithPrincipal(r.Context(), &middleware.Principal{ID: "ada"}))
+		rec := httptest.NewRecorder()
+		h.GetHistory(rec, r)
+		var body struct {
+			History    []domain.Prediction `json:"history"`
+			Pagination domain.PageInfo     `json:"pagination"`
+		}
+		json.NewDecoder(rec.Body).Decode(&body)
+		var ids []string
+		for _, p := range body.History {
+			ids = append(ids, p.ID)
+		}
+		return rec.Code, ids, body.Pagination
+	}
+
+	_, ids, page := get("/api/history?limit=2")
+	if len(ids) != 2 || ids[0] != "a" || page.Total != 5 || !page.HasMore || page.NextOffset == nil || *page.NextOffset != 2 {
+		t.Errorf("first page = %v with %+v", ids, page)
+	}
+	_, ids, page = get("/api/history?limit=2&offset=4")
+	if len(ids) != 1 || ids[0] != "e" || page.Total != 5 || page.HasMore || page.NextOffset != nil {
+		t.Errorf("last page = %v with %+v", ids, page)
+	}
+	_, ids, page = get("/api/history")
+	if len(ids) != 5 || page.Limit != domain.DefaultHistoryPageSize || page.Total != 5 || page.HasMore {
+		t.Errorf("default page = %v with %+v", ids, page)
+	}
+	if _, _, page = get("/api/history?limit=100000"); page.Limit != domain.MaxHistoryPageSize {
+		t.Errorf("limit = %d, want it capped at %d", page.Limit, domain.MaxHistoryPageSize)
+	}
+	if status, _, _ := get("/api/history?offset=-1"); status != http.StatusBadRequest {
+		t.Errorf("negative offset: status = %d, want 400", status)
+	}
+}
+
+func TestAnalyzeReusedVerdictIsInEachCallersHistory(t *testing.T) {
+	const article = "https://news.example/2024/harbor-reopens"
+	repo := memory.NewPredictionRepository()
+	if err := repo.CreatePrediction(&domain.Prediction{
+		ID: "shared", RequestType: "url", OriginalContent: article, CanonicalURL: service.NormalizeURL(article),
+		Result: domain.LabelFake, Confidence: 0.9, Method: domain.MethodModel, OwnerID: "someone",
+		CreatedAt: time.Now().Add(-time.Hour),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// No ML service or scraper is reachable: both callers must reuse the stored verdict
+	h := NewNewsHandler(service.NewNewsService(service.NewMLClient(""), service.NewScraperService(), repo))
+
+	for _, user := range []string{"ada", "grace"} {
+		principal := &middleware.Principal{ID: user}
+		r := httptest.NewRequest(http.MethodPost, "/api/analyze", strings.NewReader(`{"type":"url","content":"`+article+`"}`))
+		r = r.WithContext(middleware.ContextWithPrincipal(r.Context(), principal))
+		rec := httptest.NewRecorder()
+		h.AnalyzeNews(rec, r)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: analyze status = %d: %s", user, rec.Code, rec.Body)
+		}
+
+		r = httptest.NewRequest(http.MethodGet, "/api/history", nil)
+		r = r.WithContext(middleware.ContextWithPrincipal(r.Context(), principal))
+		rec = httptest.NewRecorder()
+		h.GetHistory(rec, r)
+		var body struct {
+			History []domain.Prediction `json:"history"`
+		}
+		json.NewDecoder(rec.Body).Decode(&body)
+		if len(body.History) != 1 {
+			t.Fatalf("%s: history = %+v, want the reused analysis", user, body.History)
+		}
+		got := body.History[0]
+		if got.ID == "shared" || got.Result != domain.LabelFake || got.OwnerID != user ||
+			got.Provenance == nil || len(got.Provenance.CacheHits) == 0 || got.Provenance.CacheHits[0] != domain.CacheStoredPrediction {
+			t.Errorf("%s: history entry = %+v; want an owned copy recording the cache hit", user, got)
+		}
+	}
+
+	if shared, err := repo.GetPredictionByID("shared"); err != nil || shared.OwnerID != "someone" {
+		t.Errorf("shared prediction = %+v, %v; want it left with its owner", shared, err)
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// History streaming
+const (
+	ndjsonContentType = "application/x-ndjson"
+
+	streamHeartbeat   = 15 * time.Second // idle time before an empty keep-alive line
+	streamWriteWindow = 30 * time.Second // write deadline granted per flush, past the server's WriteTimeout
+	streamFlushEvery  = 100              // rows between flushes
+)
+
+// wantsHistoryStream reports whether the caller asked for NDJSON, with
+// format=ndjson or an Accept header preferring it.
+func wantsHistoryStream(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "ndjson" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(strings.Split(r.Header.Get("Accept"), ",")[0]))
+	return err == nil && mediaType == ndjsonContentType
+}
+
+// streamHistory writes the predictions matching query as NDJSON, one per
+// line, as the repository yields them. An error after the first line is
+// reported as a final {"error": ...} line, since the status is sent.
+func (h *NewsHandler) streamHistory(w http.ResponseWriter, r *http.Request, query *domain.PredictionQuery) {
+	if err := query.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	locale := negotiateLocale(w, r)
+	shims := writerShims(w)
+	stream := newNDJSONStream(w, streamHeartbeat)
+	err := h.newsService.StreamHistory(r.Context(), query, func(p *domain.Prediction) error {
+		var line interface{} = p.Localized(locale)
+		if len(shims) > 0 {
+			line = applyShims(line, shims)
+		}
+		return stream.Write(line)
+	})
+	if err != nil && r.Context().Err() == nil {
+		stream.Write(map[string]string{"error": "Failed to retrieve history"})
+	}
+	stream.Close()
+}
+
+// ndjsonStream writes JSON lines, flushing every streamFlushEvery rows and
+// sending an empty line when nothing was written for a heartbeat interval,
+// so proxies do not time out a slow export. NDJSON readers skip empty
+// lines.
+type ndjsonStream struct {
+	rc   *http.ResponseController
+	stop chan struct{}
+	done chan struct{}
+
+	mu      sync.Mutex
+	enc     *json.Encoder
+	w       http.ResponseWriter
+	pending int  // rows since the last flush
+	active  bool // written since the last heartbeat tick
+	err     error
+}
+
+// newNDJSONStream sends the response header and starts the heartbeat.
+func newNDJSONStream(w http.ResponseWriter, heartbeat time.Duration) *ndjsonStream {
+	w.Header().Set("Content-Type", ndjsonContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx: pass lines through as they come
+	w.WriteHeader(http.StatusOK)
+
+	s := &ndjsonStream{
+		rc:   http.NewResponseController(w),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+		enc:  json.NewEncoder(w),
+		w:    w,
+	}
+	s.mu.Lock()
+	s.flush()
+	s.mu.Unlock()
+	go s.heartbeat(heartbeat)
+	return s
+}
+
+// Write encodes v as one line. It returns the first write error, which
+// means the client has gone.
+func (s *ndjsonStream) Write(v interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if s.err = s.enc.Encode(v); s.err != nil {
+		return s.err
+	}
+	s.active = true
+	if s.pending++; s.pending >= streamFlushEvery {
+		s.flush()
+	}
+	return nil
+}
+
+// Close stops the heartbeat and flushes what is left.
+func (s *ndjsonStream) Close() {
+	close(s.stop)
+	<-s.done
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flush()
+}
+
+func (s *ndjsonStream) heartbeat(interval time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			if !s.active && s.err == nil {
+				if _, s.err = s.w.Write([]byte("\n")); s.err == nil {
+					s.flush()
+				}
+			}
+			s.active = false
+			s.mu.Unlock()
+		}
+	}
+}
+
+// flush pushes buffered lines to the client and extends the write
+// deadline. Callers hold s.mu. Writers that cannot flush or take
+// deadlines, such as test recorders, are written to as they are.
+func (s *ndjsonStream) flush() {
+	s.rc.SetWriteDeadline(time.Now().Add(streamWriteWindow))
+	s.rc.Flush()
+	s.pending = 0
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWantsHistoryStream(t *testing.T) {
+	tests := []struct {
+		url, accept string
+		want        bool
+	}{
+		{"/api/history", "", false},
+		{"/api/history?format=ndjson", "", true},
+		{"/api/history", "application/x-ndjson", true},
+		{"/api/history", "application/x-ndjson; charset=utf-8, application/json", true},
+		{"/api/history", "application/json, application/x-ndjson", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.url, nil)
+		r.Header.Set("Accept", tt.accept)
+		if got := wantsHistoryStream(r); got != tt.want {
+			t.Errorf("wantsHistoryStream(%s, %q) = %v, want %v", tt.url, tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestNDJSONStream(t *testing.T) {
+	rec := httptest.NewRecorder()
+	stream := newNDJSONStream(rec, 10*time.Millisecond)
+	if err := stream.Write(map[string]int{"n": 1}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond) // idle: heartbeats
+	if err := stream.Write(map[string]int{"n": 2}); err != nil {
+		t.Fatal(err)
+	}
+	stream.Close()
+
+	if ct := rec.Header().Get("Content-Type"); ct != ndjsonContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var rows, blank int
+	scanner := bufio.NewScanner(strings.NewReader(rec.Body.String()))
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			blank++
+			continue
+		}
+		var row map[string]int
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		if rows++; row["n"] != rows {
+			t.Errorf("row %d = %v", rows, row)
+		}
+	}
+	if rows != 2 || blank == 0 {
+		t.Errorf("got %d rows and %d heartbeats, want 2 rows and at least one heartbeat", rows, blank)
+	}
+}
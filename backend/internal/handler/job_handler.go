@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// JobHandler serves the job queue to cmd/worker processes
+type JobHandler struct {
+	queue       *service.JobQueue
+	workerToken string
+}
+
+// NewJobHandler creates a new job handler. An empty token disables the
+// worker endpoints.
+func NewJobHandler(queue *service.JobQueue, workerToken string) *JobHandler {
+	return &JobHandler{queue: queue, workerToken: workerToken}
+}
+
+// leaseRequest is the payload for POST /api/internal/jobs/lease
+type leaseRequest struct {
+	WorkerID string   `json:"worker_id"`
+	Kinds    []string `json:"kinds"`
+}
+
+// jobUpdateRequest is the payload for heartbeat, complete and fail
+type jobUpdateRequest struct {
+	WorkerID string          `json:"worker_id"`
+	Progress json.RawMessage `json:"progress,omitempty"`
+	Result   json.RawMessage `json:"result,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Failure  string          `json:"failure,omitempty"` // transient, permanent or empty
+}
+
+// Lease handles POST /api/internal/jobs/lease
+//
+// Responds 204 when no job of the requested kinds is waiting.
+func (h *JobHandler) Lease(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req leaseRequest
+	if err := decodeRequest(r, &req); err != nil || req.WorkerID == "" || len(req.Kinds) == 0 {
+		respondWithError(w, http.StatusBadRequest, "worker_id and kinds are required")
+		return
+	}
+
+	job, ok := h.queue.Lease(r.Context(), req.WorkerID, req.Kinds, time.Now())
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"job":     job,
+	})
+}
+
+// Update handles POST /api/internal/jobs/{id}/{action} where action is
+// heartbeat, complete or fail
+func (h *JobHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req jobUpdateRequest
+	if err := decodeRequest(r, &req); err != nil || req.WorkerID == "" {
+		respondWithError(w, http.StatusBadRequest, "worker_id is required")
+		return
+	}
+	if req.Failure != "" && req.Failure != domain.JobFailureTransient && req.Failure != domain.JobFailurePermanent {
+		respondWithError(w, http.StatusBadRequest, "failure must be transient or permanent")
+		return
+	}
+
+	id := r.PathValue("id")
+	var err error
+	switch r.PathValue("action") {
+	case "heartbeat":
+		err = h.queue.Heartbeat(r.Context(), id, req.WorkerID, req.Progress)
+	case "complete":
+		err = h.queue.Complete(r.Context(), id, req.WorkerID, req.Result)
+	case "fail":
+		err = h.queue.Fail(r.Context(), id, req.WorkerID, req.Error, req.Failure)
+	default:
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrJobNotFound):
+			respondWithError(w, http.StatusNotFound, "Job not found")
+		case errors.Is(err, domain.ErrJobLeaseLost):
+			respondWithError(w, http.StatusConflict, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Failed to update job")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+}
+
+// Stats handles GET /api/internal/jobs
+func (h *JobHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"jobs":    h.queue.Stats(),
+	})
+}
+
+func (h *JobHandler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok && middleware.IsServiceAccount(principal) {
+		return true
+	}
+	if h.workerToken == "" {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.workerToken)) != 1 {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+func TestMatchAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"hi-IN,hi;q=0.9,en;q=0.8", "hi"},
+		{"pt-BR", "pt"},
+		{"ja, fr;q=0.5, de;q=0.7", "de"},
+		{"ja, zh", "en"},
+		{"fr;q=bad, es;q=0.2", "es"},
+	}
+	for _, tt := range tests {
+		if got := matchAcceptLanguage(tt.header); got != tt.want {
+			t.Errorf("matchAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestNegotiateLocaleLocalizesPrediction(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/predictions?id=p1&lang=es", nil)
+	r.Header.Set("Accept-Language", "hi")
+	w := httptest.NewRecorder()
+
+	locale := negotiateLocale(w, r)
+	if locale != "es" {
+		t.Fatalf("locale = %q, want the lang parameter to win", locale)
+	}
+	if w.Header().Get("Content-Language") != "es" || w.Header().Get("Vary") != "Accept-Language" {
+		t.Errorf("headers = %v", w.Header())
+	}
+
+	stored := &domain.Prediction{ID: "p1", Result: domain.LabelFake, Confidence: 0.874}
+	p := stored.Localized(locale)
+	if p.Display == nil || p.Display.Label != "Probablemente falsa" || p.Display.Color != "#D32F2F" {
+		t.Fatalf("display = %+v", p.Display)
+	}
+	if p.Display.Description != "87% de confianza en que este artículo es engañoso o falso" {
+		t.Errorf("description = %q", p.Display.Description)
+	}
+	if stored.Display != nil {
+		t.Error("Localized modified the stored prediction")
+	}
+}
+
+func TestNegotiatePreferredLocale(t *testing.T) {
+	tests := []struct {
+		query, preferred, want string
+	}{
+		{"", "es", "es"},         // saved preference beats Accept-Language
+		{"?lang=pt", "es", "pt"}, // the lang parameter beats the preference
+		{"", "", "hi"},           // no preference: Accept-Language
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/api/analyze"+tt.query, nil)
+		r.Header.Set("Accept-Language", "hi")
+		if got := negotiatePreferredLocale(httptest.NewRecorder(), r, tt.preferred); got != tt.want {
+			t.Errorf("negotiatePreferredLocale(%q, %q) = %q, want %q", tt.query, tt.preferred, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// msgpackCodec implements application/msgpack for the JSON data model.
+type msgpackCodec struct{}
+
+func (msgpackCodec) ContentType() string { return "application/msgpack" }
+
+func (msgpackCodec) Encode(w io.Writer, v interface{}) error {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	if err := encodeMsgpack(bw, generic); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func (msgpackCodec) Decode(r io.Reader, v interface{}) error {
+	generic, err := decodeMsgpack(bufio.NewReader(r), 0)
+	if err != nil {
+		return fmt.Errorf("invalid msgpack: %w", err)
+	}
+	return fromGeneric(generic, v)
+}
+
+func encodeMsgpack(w *bufio.Writer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		return w.WriteByte(0xc0)
+	case bool:
+		if v {
+			return w.WriteByte(0xc3)
+		}
+		return w.WriteByte(0xc2)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return encodeMsgpackInt(w, i)
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return err
+		}
+		w.WriteByte(0xcb)
+		return binary.Write(w, binary.BigEndian, math.Float64bits(f))
+	case string:
+		n := len(v)
+		switch {
+		case n < 32:
+			w.WriteByte(0xa0 | byte(n))
+		case n <= math.MaxUint8:
+			w.Write([]byte{0xd9, byte(n)})
+		case n <= math.MaxUint16:
+			w.WriteByte(0xda)
+			binary.Write(w, binary.BigEndian, uint16(n))
+		default:
+			w.WriteByte(0xdb)
+			binary.Write(w, binary.BigEndian, uint32(n))
+		}
+		_, err := w.WriteString(v)
+		return err
+	case []interface{}:
+		writeMsgpackLength(w, len(v), 0x90, 0xdc, 0xdd)
+		for _, item := range v {
+			if err := encodeMsgpack(w, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		writeMsgpackLength(w, len(v), 0x80, 0xde, 0xdf)
+		for _, k := range sortedKeys(v) {
+			if err := encodeMsgpack(w, k); err != nil {
+				return err
+			}
+			if err := encodeMsgpack(w, v[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("msgpack: unsupported type %T", v)
+}
+
+func encodeMsgpackInt(w *bufio.Writer, i int64) error {
+	switch {
+	case i >= 0 && i < 128:
+		return w.WriteByte(byte(i))
+	case i < 0 && i >= -32:
+		return w.WriteByte(byte(int8(i)))
+	case i >= 0:
+		w.WriteByte(0xcf)
+		return binary.Write(w, binary.BigEndian, uint64(i))
+	default:
+		w.WriteByte(0xd3)
+		return binary.Write(w, binary.BigEndian, i)
+	}
+}
+
+// writeMsgpackLength writes an array or map header: fix, 16-bit or 32-bit.
+func writeMsgpackLength(w *bufio.Writer, n int, fix, b16, b32 byte) {
+	switch {
+	case n < 16:
+		w.WriteByte(fix | byte(n))
+	case n <= math.MaxUint16:
+		w.WriteByte(b16)
+		binary.Write(w, binary.BigEndian, uint16(n))
+	default:
+		w.WriteByte(b32)
+		binary.Write(w, binary.BigEndian, uint32(n))
+	}
+}
+
+// maxDecodeLength bounds declared string and container sizes so a short
+// hostile body cannot make us allocate gigabytes.
+const maxDecodeLength = 16 << 20
+
+// maxDecodeNesting bounds recursion on hostile input.
+const maxDecodeNesting = 64
+
+func decodeMsgpack(r *bufio.Reader, depth int) (interface{}, error) {
+	if depth > maxDecodeNesting {
+		return nil, fmt.Errorf("msgpack nesting too deep")
+	}
+	b, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case b <= 0x7f:
+		return int64(b), nil
+	case b >= 0xe0:
+		return int64(int8(b)), nil
+	case b&0xe0 == 0xa0:
+		return readString(r, int(b&0x1f))
+	case b&0xf0 == 0x90:
+		return decodeMsgpackArray(r, int(b&0x0f), depth)
+	case b&0xf0 == 0x80:
+		return decodeMsgpackMap(r, int(b&0x0f), depth)
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xca:
+		var bits uint32
+		err := binary.Read(r, binary.BigEndian, &bits)
+		return float64(math.Float32frombits(bits)), err
+	case 0xcb:
+		var bits uint64
+		err := binary.Read(r, binary.BigEndian, &bits)
+		return math.Float64frombits(bits), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err := readUint(r, 1<<(b-0xcc))
+		if n > math.MaxInt64 {
+			return float64(n), err
+		}
+		return int64(n), err
+	case 0xd0:
+		n, err := readUint(r, 1)
+		return int64(int8(n)), err
+	case 0xd1:
+		n, err := readUint(r, 2)
+		return int64(int16(n)), err
+	case 0xd2:
+		n, err := readUint(r, 4)
+		return int64(int32(n)), err
+	case 0xd3:
+		n, err := readUint(r, 8)
+		return int64(n), err
+	case 0xd9, 0xda, 0xdb, 0xc4, 0xc5, 0xc6: // str 8/16/32, bin 8/16/32
+		size := map[byte]int{0xd9: 1, 0xda: 2, 0xdb: 4, 0xc4: 1, 0xc5: 2, 0xc6: 4}[b]
+		n, err := readUint(r, size)
+		if err != nil {
+			return nil, err
+		}
+		return readString(r, int(n))
+	case 0xdc, 0xdd:
+		n, err := readUint(r, 2<<(b-0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return decodeMsgpackArray(r, int(n), depth)
+	case 0xde, 0xdf:
+		n, err := readUint(r, 2<<(b-0xde))
+		if err != nil {
+			return nil, err
+		}
+		return decodeMsgpackMap(r, int(n), depth)
+	}
+	return nil, fmt.Errorf("unsupported msgpack type 0x%02x", b)
+}
+
+func decodeMsgpackArray(r *bufio.Reader, n, depth int) (interface{}, error) {
+	if n > maxDecodeLength {
+		return nil, fmt.Errorf("array of %d items is too large", n)
+	}
+	items := make([]interface{}, 0, min(n, 1024))
+	for i := 0; i < n; i++ {
+		item, err := decodeMsgpack(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func decodeMsgpackMap(r *bufio.Reader, n, depth int) (interface{}, error) {
+	if n > maxDecodeLength {
+		return nil, fmt.Errorf("map of %d entries is too large", n)
+	}
+	m := make(map[string]interface{}, min(n, 1024))
+	for i := 0; i < n; i++ {
+		key, err := decodeMsgpack(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		value, err := decodeMsgpack(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(key)] = value
+	}
+	return m, nil
+}
+
+// readUint reads a big-endian unsigned integer of size bytes.
+func readUint(r io.Reader, size int) (uint64, error) {
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, err
+	}
+	var n uint64
+	for _, b := range buf {
+		n = n<<8 | uint64(b)
+	}
+	return n, nil
+}
+
+func readString(r io.Reader, n int) (string, error) {
+	if n > maxDecodeLength {
+		return "", fmt.Errorf("string of %d bytes is too large", n)
+	}
+	buf := make([]byte, n)
+	_, err := io.ReadFull(r, buf)
+	return string(buf), err
+}
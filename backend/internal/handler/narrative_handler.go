@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// narrativeEditorDenied is the error for members who may read but not
+// change the glossary
+const narrativeEditorDenied = "Organization admin or analyst role required"
+
+// narrativeRequest is the body of POST and PUT narrative requests
+type narrativeRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Keywords    []string `json:"keywords"`
+	Patterns    []string `json:"patterns"`
+}
+
+func (req narrativeRequest) narrative() domain.Narrative {
+	return domain.Narrative{Name: req.Name, Description: req.Description, Keywords: req.Keywords, Patterns: req.Patterns}
+}
+
+// Narratives handles GET and POST /api/orgs/{org}/narratives. Members
+// read the glossary; admins and analysts add to it.
+func (h *OrgHandler) Narratives(w http.ResponseWriter, r *http.Request) {
+	if h.narratives == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	orgID := r.PathValue("org")
+
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := h.authorizeRoles(w, r, orgID, "Organization membership required"); !ok {
+			return
+		}
+		narratives, err := h.narratives.List(r.Context(), orgID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to list narratives")
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success":    true,
+			"count":      len(narratives),
+			"narratives": narratives,
+		})
+
+	case http.MethodPost:
+		actor, ok := h.authorizeRoles(w, r, orgID, narrativeEditorDenied, domain.RoleAdmin, domain.RoleAnalyst)
+		if !ok {
+			return
+		}
+		var req narrativeRequest
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		narrative, tagged, err := h.narratives.Create(r.Context(), orgID, actor, req.narrative())
+		if err != nil {
+			respondWithNarrativeError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+			"success":   true,
+			"narrative": narrative,
+			"tagged":    tagged,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Narrative handles GET, PUT and DELETE /api/orgs/{org}/narratives/{id}
+func (h *OrgHandler) Narrative(w http.ResponseWriter, r *http.Request) {
+	if h.narratives == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	orgID, id := r.PathValue("org"), r.PathValue("id")
+
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := h.authorizeRoles(w, r, orgID, "Organization membership required"); !ok {
+			return
+		}
+		narrative, err := h.narratives.Get(r.Context(), orgID, id)
+		if err != nil {
+			respondWithNarrativeError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success":   true,
+			"narrative": narrative,
+		})
+
+	case http.MethodPut:
+		if _, ok := h.authorizeRoles(w, r, orgID, narrativeEditorDenied, domain.RoleAdmin, domain.RoleAnalyst); !ok {
+			return
+		}
+		var req narrativeRequest
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		narrative, tagged, err := h.narratives.Update(r.Context(), orgID, id, req.narrative())
+		if err != nil {
+			respondWithNarrativeError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success":   true,
+			"narrative": narrative,
+			"tagged":    tagged,
+		})
+
+	case http.MethodDelete:
+		if _, ok := h.authorizeRoles(w, r, orgID, narrativeEditorDenied, domain.RoleAdmin, domain.RoleAnalyst); !ok {
+			return
+		}
+		if err := h.narratives.Delete(r.Context(), orgID, id); err != nil {
+			respondWithNarrativeError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"id":      id,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// NarrativeStats handles GET /api/orgs/{org}/narratives/{id}/stats?range=&interval=&top=.
+// range (default 30d) and interval (default 1d) accept Go durations plus d
+// and w suffixes; top (default 10) bounds the source domains listed.
+func (h *OrgHandler) NarrativeStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.narratives == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	orgID := r.PathValue("org")
+	if _, ok := h.authorizeRoles(w, r, orgID, "Organization membership required"); !ok {
+		return
+	}
+
+	params := r.URL.Query()
+	span, err := parseSpan(params.Get("range"), 30*24*time.Hour)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "range must be a duration such as 24h or 30d")
+		return
+	}
+	interval, err := parseSpan(params.Get("interval"), 24*time.Hour)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "interval must be a duration such as 1h or 1d")
+		return
+	}
+	top := domain.DefaultCompareTopDomains
+	if v := params.Get("top"); v != "" {
+		if top, err = strconv.Atoi(v); err != nil {
+			respondWithError(w, http.StatusBadRequest, "top must be an integer")
+			return
+		}
+	}
+
+	now := time.Now()
+	stats, err := h.narratives.Stats(r.Context(), orgID, r.PathValue("id"), domain.Period{Since: now.Add(-span), Until: now}, interval, top)
+	if err != nil {
+		respondWithNarrativeError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"stats":   stats,
+	})
+}
+
+func respondWithNarrativeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNarrativeNotFound):
+		respondWithError(w, http.StatusNotFound, "Narrative not found")
+	case errors.Is(err, domain.ErrInvalidNarrative), errors.Is(err, domain.ErrInvalidQuery):
+		respondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrNarrativeLimitReached):
+		respondWithError(w, http.StatusForbidden, err.Error())
+	default:
+		respondWithError(w, http.StatusInternalServerError, "Failed to process narrative")
+	}
+}
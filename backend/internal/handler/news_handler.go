@@ -1,16 +1,32 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
 	"github.com/Naman30903/Final-Year-Project/internal/service"
 )
 
 // NewsHandler handles news analysis HTTP requests
 type NewsHandler struct {
-	newsService *service.NewsService
+	newsService   *service.NewsService
+	pushService   *service.PushService
+	feedTokens    *service.FeedTokens
+	publicBaseURL string
+	publisher     service.ClaimReviewPublisher
+	startup       *service.StartupOrchestrator
+	canary        *service.Canary
+	templates     *service.AnalysisTemplateService
+	preferences   *service.PreferencesService
+	anonymous     *service.AnonymousHistory
+	adminToken    string
 }
 
 // NewNewsHandler creates a new news handler
@@ -20,6 +36,63 @@ func NewNewsHandler(newsService *service.NewsService) *NewsHandler {
 	}
 }
 
+// WithAnonymousHistory groups the analyses of callers who are not signed
+// in under an anonymous session cookie, and scopes their history to it
+func (h *NewsHandler) WithAnonymousHistory(anonymous *service.AnonymousHistory) *NewsHandler {
+	h.anonymous = anonymous
+	return h
+}
+
+// WithAdminToken lets the admin bearer token list everyone's history
+func (h *NewsHandler) WithAdminToken(adminToken string) *NewsHandler {
+	h.adminToken = adminToken
+	return h
+}
+
+// WithPushService enables push notifications to authenticated callers when
+// their analysis completes
+func (h *NewsHandler) WithPushService(pushService *service.PushService) *NewsHandler {
+	h.pushService = pushService
+	return h
+}
+
+// WithFeeds enables the per-user history feed. baseURL is the public
+// origin feed links are built from; when empty it is taken from the request.
+func (h *NewsHandler) WithFeeds(tokens *service.FeedTokens, baseURL string) *NewsHandler {
+	h.feedTokens = tokens
+	h.publicBaseURL = strings.TrimRight(baseURL, "/")
+	return h
+}
+
+// WithClaimReview credits publisher as the author of published
+// ClaimReviews. baseURL is the public origin review URLs are built from;
+// when empty it is taken from the request.
+func (h *NewsHandler) WithClaimReview(publisher service.ClaimReviewPublisher, baseURL string) *NewsHandler {
+	h.publisher = publisher
+	if baseURL != "" {
+		h.publicBaseURL = strings.TrimRight(baseURL, "/")
+	}
+	return h
+}
+
+// WithStartup adds the boot sequence to /readyz.
+func (h *NewsHandler) WithStartup(startup *service.StartupOrchestrator) *NewsHandler {
+	h.startup = startup
+	return h
+}
+
+// WithTemplates resolves template_id in analysis requests
+func (h *NewsHandler) WithTemplates(templates *service.AnalysisTemplateService) *NewsHandler {
+	h.templates = templates
+	return h
+}
+
+// WithCanary reports the synthetic canary's state in /readyz
+func (h *NewsHandler) WithCanary(canary *service.Canary) *NewsHandler {
+	h.canary = canary
+	return h
+}
+
 // AnalyzeNews handles POST /api/analyze
 func (h *NewsHandler) AnalyzeNews(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -27,34 +100,82 @@ func (h *NewsHandler) AnalyzeNews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse request
+	// Parse request, noting which options the caller sent so saved
+	// defaults only fill in the rest
+	var body map[string]json.RawMessage
 	var req domain.AnalysisRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeRequest(r, &body); err != nil || fromGeneric(body, &req) != nil {
 		respondWithError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	explicit := make(map[string]bool, len(body))
+	for field := range body {
+		explicit[strings.ToLower(field)] = true
+	}
+	// A template fills in options first, so saved defaults only cover
+	// what neither the request nor the template sets
+	if req.TemplateID != "" {
+		settings, status, err := h.analysisTemplate(r, req.TemplateID)
+		if err != nil {
+			respondWithError(w, status, err.Error())
+			return
+		}
+		settings.Apply(&req, explicit)
+		for _, field := range settings.Fields() {
+			explicit[field] = true
+		}
+	}
+	locale := ""
+	if defaults := h.analysisDefaults(r); defaults != nil {
+		defaults.Apply(&req, explicit)
+		locale = defaults.Locale
+	}
 
-	// Analyze news
-	prediction, err := h.newsService.AnalyzeNews(&req)
+	verbosity, err := resolveVerbosity(r, req.Verbosity)
 	if err != nil {
-		// Handle specific errors
-		switch err {
-		case domain.ErrInvalidRequestType, domain.ErrEmptyContent, domain.ErrInvalidURL:
-			respondWithError(w, http.StatusBadRequest, err.Error())
-		case domain.ErrURLScrapingFailed:
-			respondWithError(w, http.StatusBadGateway, "Failed to scrape URL content")
-		case domain.ErrMLServiceUnavailable, domain.ErrPredictionFailed:
-			respondWithError(w, http.StatusServiceUnavailable, "ML service unavailable")
-		default:
-			respondWithError(w, http.StatusInternalServerError, "Internal server error")
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	if principal, ok := middleware.PrincipalFromContext(ctx); ok {
+		ctx = service.ContextWithOwner(ctx, principal.ID)
+		if principal.OrgID != "" {
+			ctx = service.ContextWithOrg(ctx, principal.OrgID)
+		}
+	}
+	if sessionID := anonymousSession(w, r, h.anonymous, true); sessionID != "" {
+		ctx = service.ContextWithAnonymousSession(ctx, sessionID)
+	}
+	if client, ok := middleware.APIClientFromContext(ctx); ok {
+		if client.Partner {
+			ctx = service.ContextWithPartner(ctx, client.Name, client.ResearchExport)
 		}
+		// The extension wants an instant answer over a fresh one
+		if client.Type == domain.ClientTypeExtension && !explicit["allow_stale"] {
+			req.AllowStale = true
+		}
+	}
+	if subject, plan := middleware.QuotaSubject(r); subject != "" {
+		ctx = service.ContextWithQuota(ctx, subject, plan)
+	}
+
+	// Analyze news
+	prediction, err := h.newsService.AnalyzeNews(ctx, &req)
+	if err != nil {
+		respondWithServiceError(w, err, "Internal server error")
 		return
 	}
 
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok && h.pushService != nil && !prediction.Provisional {
+		go h.pushService.NotifyAnalysisComplete(context.Background(), principal.ID, prediction)
+	}
+
 	// Send response
-	respondWithJSON(w, http.StatusOK, domain.PredictionResponse{
-		Success:    true,
-		Prediction: prediction,
+	locale = negotiatePreferredLocale(w, r, locale)
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"prediction": prediction.Localized(locale).View(verbosity),
 	})
 }
 
@@ -73,37 +194,258 @@ func (h *NewsHandler) GetPrediction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	verbosity, err := resolveVerbosity(r, "")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	// Get prediction
 	prediction, err := h.newsService.GetPrediction(id)
 	if err != nil {
-		respondWithError(w, http.StatusNotFound, "Prediction not found")
+		respondWithServiceError(w, err, "Failed to retrieve prediction")
+		return
+	}
+
+	setVersionETag(w, prediction)
+	respondWithJSON(w, http.StatusOK, prediction.Localized(negotiateLocale(w, r)).View(verbosity))
+}
+
+// ClaimReview handles GET /api/predictions/{id}/claimreview
+//
+// Publishes a reviewed prediction as schema.org ClaimReview JSON-LD for
+// search engines and fact-check aggregators. It needs no authentication:
+// reviewing a prediction is what publishes it.
+func (h *NewsHandler) ClaimReview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	prediction, err := h.newsService.GetPrediction(r.PathValue("id"))
+	if err != nil {
+		respondWithServiceError(w, err, "Failed to retrieve prediction")
+		return
+	}
+	claimReview, err := service.BuildClaimReview(prediction, h.publisher, h.baseURL(r)+r.URL.Path)
+	if err != nil {
+		respondWithServiceError(w, err, "Failed to build ClaimReview")
+		return
+	}
+
+	data, err := json.Marshal(claimReview)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to encode ClaimReview")
+		return
+	}
+	w.Header().Set("Content-Type", "application/ld+json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// AnnotatedPrediction handles GET /api/predictions/{id}/annotated
+//
+// Returns the stored text with suspicious sentences marked up. With
+// format=html only the HTML fragment is returned, for embedding.
+func (h *NewsHandler) AnnotatedPrediction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	prediction, err := h.newsService.GetPrediction(r.PathValue("id"))
+	if err != nil {
+		respondWithServiceError(w, err, "Failed to retrieve prediction")
+		return
+	}
+	annotation := service.AnnotatePrediction(prediction)
+
+	setVersionETag(w, prediction)
+	if r.URL.Query().Get("format") == "html" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(annotation.HTML))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"annotation": annotation,
+	})
+}
+
+// PredictionDiagnostics handles GET /api/predictions/{id}/diagnostics
+//
+// Returns how the scraper handled the prediction's URL: status, redirect
+// chain, robots decision, extraction stats and what probably blocked it.
+func (h *NewsHandler) PredictionDiagnostics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	prediction, err := h.newsService.GetPrediction(r.PathValue("id"))
+	if err != nil {
+		respondWithServiceError(w, err, "Failed to retrieve prediction")
+		return
+	}
+	if prediction.ScrapeDiagnostics == nil {
+		respondWithError(w, http.StatusNotFound, "No scrape diagnostics for this prediction")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":     true,
+		"diagnostics": prediction.ScrapeDiagnostics,
+	})
+}
+
+// PinPrediction handles POST and DELETE /api/predictions/{id}/pin
+func (h *NewsHandler) PinPrediction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var prediction *domain.Prediction
+	var err error
+	if r.Method == http.MethodPost {
+		prediction, err = h.newsService.PinPrediction(r.Context(), r.PathValue("id"), principal.ID, principal.Plan)
+	} else {
+		prediction, err = h.newsService.UnpinPrediction(r.Context(), r.PathValue("id"), principal.ID)
+	}
+	if err != nil {
+		respondWithServiceError(w, err, "Failed to update pin")
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, prediction)
+	respondWithJSON(w, http.StatusOK, domain.PredictionResponse{
+		Success:    true,
+		Prediction: prediction,
+	})
 }
 
 // GetHistory handles GET /api/history
+//
+// Optional filters: label, type, domain, model, narrative, min_confidence,
+// max_confidence, since and until (RFC 3339 or YYYY-MM-DD), pinned=true,
+// order=oldest and pinned_first=true. format=ndjson (or Accept:
+// application/x-ndjson) streams one prediction per line instead of
+// buffering the whole result.
+//
+// Lists are paged with limit (default 50, at most 500) and offset, and
+// report the total matching and the next page's offset under pagination.
+// Streams return every match unless limit is given.
+//
+// Callers see only their own predictions: a signed-in caller's, or an
+// anonymous visitor's session. Admins pass all=true for everyone's.
 func (h *NewsHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Get all predictions
-	predictions, err := h.newsService.GetHistory()
+	query, err := parseHistoryQuery(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if r.URL.Query().Get("all") == "true" {
+		if !isAdminRequest(r, h.adminToken) {
+			respondWithError(w, http.StatusForbidden, "Only admins can list all history")
+			return
+		}
+	} else if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		query.OwnerID = principal.ID
+	} else if query.AnonymousSession = anonymousSession(w, r, h.anonymous, false); query.AnonymousSession == "" {
+		// A caller with no identity has no analyses of its own
+		respondWithEmptyHistory(w, r, query)
+		return
+	}
+	if wantsHistoryStream(r) {
+		h.streamHistory(w, r, query)
+		return
+	}
+
+	predictions, page, err := h.newsService.QueryHistoryPage(r.Context(), query)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve history")
+		respondWithServiceError(w, err, "Failed to retrieve history")
 		return
 	}
 
+	locale := negotiateLocale(w, r)
+	for i, p := range predictions {
+		predictions[i] = p.Localized(locale)
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"count":      len(predictions),
+		"history":    predictions,
+		"pagination": page,
+	})
+}
+
+// respondWithEmptyHistory answers a history request that cannot match
+// anything, in the format asked for.
+func respondWithEmptyHistory(w http.ResponseWriter, r *http.Request, query *domain.PredictionQuery) {
+	if wantsHistoryStream(r) {
+		w.Header().Set("Content-Type", ndjsonContentType)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
 	respondWithJSON(w, http.StatusOK, map[string]interface{}{
-		"success": true,
-		"count":   len(predictions),
-		"history": predictions,
+		"success":    true,
+		"count":      0,
+		"history":    []*domain.Prediction{},
+		"pagination": domain.NewPageInfo(0, query.Limit, query.Offset, 0),
 	})
 }
 
+// parseHistoryQuery builds a prediction query from URL parameters
+func parseHistoryQuery(r *http.Request) (*domain.PredictionQuery, error) {
+	params := r.URL.Query()
+	q := domain.NewPredictionQuery().
+		WithLabel(params.Get("label")).
+		WithRequestType(params.Get("type")).
+		WithDomain(params.Get("domain")).
+		WithModelVersion(params.Get("model")).
+		WithNarrative(params.Get("narrative"))
+	q.OldestFirst = params.Get("order") == "oldest"
+	q.PinnedOnly = params.Get("pinned") == "true"
+	q.PinnedFirst = params.Get("pinned_first") == "true"
+
+	var err error
+	if q.MinConfidence, err = parseFloatParam(params.Get("min_confidence")); err != nil {
+		return nil, fmt.Errorf("%w: min_confidence must be a number", domain.ErrInvalidQuery)
+	}
+	if q.MaxConfidence, err = parseFloatParam(params.Get("max_confidence")); err != nil {
+		return nil, fmt.Errorf("%w: max_confidence must be a number", domain.ErrInvalidQuery)
+	}
+	if q.Since, err = parseTimeParam(params.Get("since")); err != nil {
+		return nil, fmt.Errorf("%w: since must be RFC 3339 or YYYY-MM-DD", domain.ErrInvalidQuery)
+	}
+	if q.Until, err = parseTimeParam(params.Get("until")); err != nil {
+		return nil, fmt.Errorf("%w: until must be RFC 3339 or YYYY-MM-DD", domain.ErrInvalidQuery)
+	}
+
+	limit, offset, err := parsePage(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidQuery, err)
+	}
+	if !wantsHistoryStream(r) {
+		if limit == 0 {
+			limit = domain.DefaultHistoryPageSize
+		}
+		limit = min(limit, domain.MaxHistoryPageSize)
+	}
+	return q.WithPage(limit, offset), nil
+}
+
 // HealthCheck handles GET /api/health
 func (h *NewsHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -128,12 +470,142 @@ func (h *NewsHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Readyz handles GET /readyz
+//
+// Reports per-subsystem detail so operators can tell whether analysis
+// failures are ML- or scraping-side. Responds 503 when either is down.
+func (h *NewsHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ml := map[string]interface{}{"status": service.HealthOK}
+	if err := h.newsService.CheckMLHealth(); err != nil {
+		ml = map[string]interface{}{"status": service.HealthDown, "detail": err.Error()}
+	}
+	scraper := h.newsService.CheckScraperHealth(r.Context())
+
+	status := service.HealthOK
+	code := http.StatusOK
+	switch {
+	case ml["status"] == service.HealthDown || scraper.Status == service.HealthDown:
+		status = service.HealthDown
+		code = http.StatusServiceUnavailable
+	case scraper.Status == service.HealthDegraded:
+		status = service.HealthDegraded
+	}
+
+	resp := map[string]interface{}{
+		"status":  status,
+		"ml":      ml,
+		"scraper": scraper,
+	}
+	// A failing canary degrades readiness but never takes the instance
+	// out of rotation: the pipeline answers, just not as expected
+	if h.canary != nil {
+		canary := h.canary.Status()
+		resp["canary"] = map[string]interface{}{"status": canary.Status, "pass_rate": canary.PassRate, "runs": canary.Runs}
+		if canary.Status != service.HealthOK && status == service.HealthOK {
+			resp["status"] = service.HealthDegraded
+		}
+	}
+	if h.startup != nil {
+		resp["startup"] = h.startup.Report()
+	}
+	respondWithJSON(w, code, resp)
+}
+
 // Helper functions
 
+// resolveVerbosity picks the response verbosity: the verbosity query
+// parameter, then the request body, then the API client's default.
+func resolveVerbosity(r *http.Request, requested string) (string, error) {
+	if v := r.URL.Query().Get("verbosity"); v != "" {
+		requested = v
+	}
+	if requested != "" {
+		if !domain.IsValidVerbosity(requested) {
+			return "", domain.ErrInvalidVerbosity
+		}
+		return requested, nil
+	}
+	if client, ok := middleware.APIClientFromContext(r.Context()); ok {
+		return client.DefaultVerbosity(), nil
+	}
+	return domain.DefaultVerbosity(""), nil
+}
+
+// negotiateLocale picks the display locale from Accept-Language (or a lang
+// query parameter) and records the choice in the response headers.
+func negotiateLocale(w http.ResponseWriter, r *http.Request) string {
+	return negotiatePreferredLocale(w, r, "")
+}
+
+// negotiatePreferredLocale is negotiateLocale with a saved preference that
+// outranks Accept-Language but not the lang parameter.
+func negotiatePreferredLocale(w http.ResponseWriter, r *http.Request, preferred string) string {
+	locale := strings.ToLower(r.URL.Query().Get("lang"))
+	if !domain.IsSupportedLocale(locale) {
+		locale = preferred
+	}
+	if !domain.IsSupportedLocale(locale) {
+		locale = matchAcceptLanguage(r.Header.Get("Accept-Language"))
+	}
+	w.Header().Add("Vary", "Accept-Language")
+	w.Header().Set("Content-Language", locale)
+	return locale
+}
+
+// matchAcceptLanguage returns the supported locale the header prefers
+// most, matching on the primary language subtag ("pt-BR" is "pt").
+func matchAcceptLanguage(header string) string {
+	best, bestQ := domain.DefaultLocale, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if q > bestQ && domain.IsSupportedLocale(primary) {
+			best, bestQ = primary, q
+		}
+	}
+	return best
+}
+
+func parseFloatParam(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// respondWithJSON writes payload as JSON, or in the format negotiated by
+// ContentNegotiation, in the shape chosen by ClientCompatibility.
 func respondWithJSON(w http.ResponseWriter, statusCode int, payload interface{}) {
-	w.Header().Set("Content-Type", "application/json")
+	if shims := writerShims(w); len(shims) > 0 {
+		payload = applyShims(payload, shims)
+	}
+	codec := responseCodec(w)
+	w.Header().Set("Content-Type", codec.ContentType())
 	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(payload)
+	codec.Encode(w, payload)
 }
 
 func respondWithError(w http.ResponseWriter, statusCode int, message string) {
@@ -141,3 +613,17 @@ func respondWithError(w http.ResponseWriter, statusCode int, message string) {
 		"error": message,
 	})
 }
+
+// respondWithScrapeError is respondWithError plus the scraper's
+// diagnostics, when err carries them.
+func respondWithScrapeError(w http.ResponseWriter, statusCode int, message string, err error) {
+	diagnostics := service.ScrapeDiagnosticsOf(err)
+	if diagnostics == nil {
+		respondWithError(w, statusCode, message)
+		return
+	}
+	respondWithJSON(w, statusCode, map[string]interface{}{
+		"error":       message,
+		"diagnostics": diagnostics,
+	})
+}
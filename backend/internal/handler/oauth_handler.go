@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// oauthStateCookie binds a social sign-in to the browser that started it
+const oauthStateCookie = "fn_oauth_state"
+
+// OAuthHandler handles social sign-in HTTP requests
+type OAuthHandler struct {
+	oauthService    *service.OAuthService
+	successRedirect string
+	anonymous       *service.AnonymousHistory
+}
+
+// NewOAuthHandler creates a new social sign-in handler. After sign-in the
+// browser is sent to successRedirect; when it is empty the session is
+// returned as JSON instead.
+func NewOAuthHandler(oauthService *service.OAuthService, successRedirect string) *OAuthHandler {
+	return &OAuthHandler{oauthService: oauthService, successRedirect: successRedirect}
+}
+
+// WithAnonymousHistory merges the browser's anonymous predictions into
+// the account on sign-in
+func (h *OAuthHandler) WithAnonymousHistory(anonymous *service.AnonymousHistory) *OAuthHandler {
+	h.anonymous = anonymous
+	return h
+}
+
+// Providers handles GET /api/auth/oauth
+func (h *OAuthHandler) Providers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":   true,
+		"providers": h.oauthService.Providers(),
+	})
+}
+
+// Login handles GET /api/auth/oauth/{provider}/login
+func (h *OAuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authURL, state, err := h.oauthService.LoginURL(r.Context(), r.PathValue("provider"))
+	if err != nil {
+		if errors.Is(err, domain.ErrUnknownOAuthProvider) {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to start sign-in")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     "/api/auth/oauth",
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback handles GET /api/auth/oauth/{provider}/callback, the redirect
+// URI registered with the provider
+func (h *OAuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	if providerErr := params.Get("error"); providerErr != "" {
+		respondWithError(w, http.StatusUnauthorized, "Sign-in was not completed: "+providerErr)
+		return
+	}
+	state := params.Get("state")
+	cookie, err := r.Cookie(oauthStateCookie)
+	if err != nil || cookie.Value == "" || cookie.Value != state {
+		respondWithError(w, http.StatusBadRequest, "Sign-in state does not match this browser, please retry")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: oauthStateCookie, Path: "/api/auth/oauth", MaxAge: -1})
+
+	login, err := h.oauthService.Callback(r.Context(), r.PathValue("provider"), state, params.Get("code"))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnknownOAuthProvider):
+			respondWithError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, domain.ErrOAuthFailed):
+			respondWithError(w, http.StatusUnauthorized, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Sign-in failed")
+		}
+		return
+	}
+	mergeAnonymousHistory(w, r, h.anonymous, login.User.ID)
+	respondWithLogin(w, r, login, h.successRedirect)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// OrgHandler handles organization settings HTTP requests
+type OrgHandler struct {
+	orgPolicy  *service.OrgPolicyService
+	narratives *service.NarrativeService
+	adminToken string
+}
+
+// NewOrgHandler creates a new organization handler. The admin token, if
+// set, manages every organization's settings.
+func NewOrgHandler(orgPolicy *service.OrgPolicyService, adminToken string) *OrgHandler {
+	return &OrgHandler{orgPolicy: orgPolicy, adminToken: adminToken}
+}
+
+// WithNarratives enables the organization narrative glossary
+func (h *OrgHandler) WithNarratives(narratives *service.NarrativeService) *OrgHandler {
+	h.narratives = narratives
+	return h
+}
+
+// Domains handles GET and POST /api/orgs/{org}/domains
+func (h *OrgHandler) Domains(w http.ResponseWriter, r *http.Request) {
+	orgID := r.PathValue("org")
+	actor, ok := h.authorize(w, r, orgID)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		rules, err := h.orgPolicy.List(r.Context(), orgID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to list domain rules")
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"count":   len(rules),
+			"rules":   rules,
+		})
+
+	case http.MethodPost:
+		var req struct {
+			Domain string  `json:"domain"`
+			List   string  `json:"list"`
+			Weight float64 `json:"weight"`
+			Note   string  `json:"note"`
+		}
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		rule, err := h.orgPolicy.Put(r.Context(), orgID, domain.OrgDomainRule{
+			Domain:    req.Domain,
+			List:      req.List,
+			Weight:    req.Weight,
+			Note:      req.Note,
+			CreatedBy: actor,
+		})
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidDomain) || errors.Is(err, domain.ErrInvalidDomainRule) {
+				respondWithError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, "Failed to save domain rule")
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"rule":    rule,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// DeleteDomain handles DELETE /api/orgs/{org}/domains/{id}
+func (h *OrgHandler) DeleteDomain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	orgID := r.PathValue("org")
+	if _, ok := h.authorize(w, r, orgID); !ok {
+		return
+	}
+
+	if err := h.orgPolicy.Remove(r.Context(), orgID, r.PathValue("id")); err != nil {
+		if errors.Is(err, domain.ErrDomainRuleNotFound) {
+			respondWithError(w, http.StatusNotFound, "Domain rule not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to remove domain rule")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"id":      r.PathValue("id"),
+	})
+}
+
+// authorize admits admins of the organization and holders of the admin
+// token, returning who is acting.
+func (h *OrgHandler) authorize(w http.ResponseWriter, r *http.Request, orgID string) (string, bool) {
+	return h.authorizeRoles(w, r, orgID, "Organization admin role required", domain.RoleAdmin)
+}
+
+// authorizeRoles admits members of the organization with one of roles (any
+// member when none are given), service accounts and holders of the admin
+// token, returning who is acting.
+func (h *OrgHandler) authorizeRoles(w http.ResponseWriter, r *http.Request, orgID, denied string, roles ...string) (string, bool) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if ok && middleware.IsServiceAccount(principal) {
+		return principal.ID, true
+	}
+	if ok && principal.OrgID != "" {
+		if principal.OrgID == orgID && (len(roles) == 0 || slices.Contains(roles, principal.Role)) {
+			return principal.ID, true
+		}
+		respondWithError(w, http.StatusForbidden, denied)
+		return "", false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if h.adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return "", false
+	}
+	return "admin", true
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// WithPreferences applies each caller's saved analysis defaults to their
+// analysis requests
+func (h *NewsHandler) WithPreferences(preferences *service.PreferencesService) *NewsHandler {
+	h.preferences = preferences
+	return h
+}
+
+// Preferences handles GET, PUT and DELETE /api/users/me/preferences
+func (h *NewsHandler) Preferences(w http.ResponseWriter, r *http.Request) {
+	if h.preferences == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var defaults *domain.AnalysisDefaults
+	var err error
+	switch r.Method {
+	case http.MethodGet:
+		defaults, err = h.preferences.Defaults(r.Context(), principal.ID)
+
+	case http.MethodPut:
+		var req domain.AnalysisDefaults
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		defaults, err = h.preferences.SetDefaults(r.Context(), principal.ID, &req)
+		if errors.Is(err, domain.ErrInvalidPreferences) {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+	case http.MethodDelete:
+		defaults, err = &domain.AnalysisDefaults{}, h.preferences.ClearDefaults(r.Context(), principal.ID)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load preferences")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":     true,
+		"preferences": defaults,
+	})
+}
+
+// analysisDefaults returns the caller's saved defaults, or nil when there
+// are none to apply. A failed lookup is logged and the request proceeds
+// without defaults.
+func (h *NewsHandler) analysisDefaults(r *http.Request) *domain.AnalysisDefaults {
+	if h.preferences == nil {
+		return nil
+	}
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		return nil
+	}
+	defaults, err := h.preferences.Defaults(r.Context(), principal.ID)
+	if err != nil {
+		log.Printf("Warning: failed to load analysis defaults for %s: %v", principal.ID, err)
+		return nil
+	}
+	return defaults
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// PushHandler handles Web Push subscription HTTP requests
+type PushHandler struct {
+	pushService *service.PushService
+	adminToken  string
+}
+
+// NewPushHandler creates a new push handler
+func NewPushHandler(pushService *service.PushService) *PushHandler {
+	return &PushHandler{pushService: pushService}
+}
+
+// WithAdminToken lets the admin token remove any user's subscription
+func (h *PushHandler) WithAdminToken(token string) *PushHandler {
+	h.adminToken = token
+	return h
+}
+
+// PublicKey handles GET /api/push/vapid-public-key
+func (h *PushHandler) PublicKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"public_key": h.pushService.PublicKey(),
+	})
+}
+
+// Subscribe handles POST /api/push/subscribe
+func (h *PushHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var sub domain.PushSubscription
+	if err := decodeRequest(r, &sub); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	sub.UserID = principal.ID
+
+	if err := h.pushService.Subscribe(r.Context(), &sub); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"success":      true,
+		"subscription": sub,
+	})
+}
+
+// Unsubscribe handles POST /api/push/unsubscribe
+//
+// Users remove their own subscriptions; admins can remove anyone's.
+func (h *PushHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var userID string
+	if !isAdminRequest(r, h.adminToken) {
+		principal, ok := middleware.PrincipalFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+		userID = principal.ID
+	}
+
+	var req struct {
+		Endpoint string `json:"endpoint"`
+	}
+	if err := decodeRequest(r, &req); err != nil || req.Endpoint == "" {
+		respondWithError(w, http.StatusBadRequest, "endpoint is required")
+		return
+	}
+
+	if err := h.pushService.Unsubscribe(r.Context(), req.Endpoint, userID); err != nil {
+		if errors.Is(err, domain.ErrPushSubscriptionNotFound) {
+			respondWithError(w, http.StatusNotFound, "Subscription not found")
+			return
+		}
+		respondWithServiceError(w, err, "Failed to remove subscription")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+	})
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+func TestUnsubscribeChecksOwner(t *testing.T) {
+	push := service.NewPushService(memory.NewPushSubscriptionRepository(), nil)
+	h := NewPushHandler(push).WithAdminToken("s3cret")
+	const endpoint = "https://fcm.googleapis.com/fcm/send/ada"
+	subscribe := func() {
+		sub := &domain.PushSubscription{UserID: "ada", Endpoint: endpoint, Keys: domain.PushSubscriptionKeys{P256dh: "p", Auth: "a"}}
+		if err := push.Subscribe(context.Background(), sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+	unsubscribe := func(principal *middleware.Principal, token string) int {
+		r := httptest.NewRequest(http.MethodPost, "/api/push/unsubscribe", strings.NewReader(`{"endpoint":"`+endpoint+`"}`))
+		if principal != nil {
+			r = r.WithContext(middleware.ContextWithPrincipal(r.Context(), principal))
+		}
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.Unsubscribe(rec, r)
+		return rec.Code
+	}
+
+	subscribe()
+	if code := unsubscribe(nil, ""); code != http.StatusUnauthorized {
+		t.Errorf("anonymous: status = %d, want 401", code)
+	}
+	if code := unsubscribe(&middleware.Principal{ID: "grace"}, ""); code != http.StatusNotFound {
+		t.Errorf("another user: status = %d, want 404", code)
+	}
+	worker := &middleware.Principal{ID: "service:worker", Method: middleware.AuthServiceAccount, Role: middleware.ServiceRoleWorker, Scopes: []string{middleware.ScopeInternalJobs}}
+	if code := unsubscribe(worker, ""); code != http.StatusNotFound {
+		t.Errorf("worker service account: status = %d, want 404", code)
+	}
+	if code := unsubscribe(&middleware.Principal{ID: "ada"}, ""); code != http.StatusOK {
+		t.Errorf("owner: status = %d, want 200", code)
+	}
+	if code := unsubscribe(&middleware.Principal{ID: "ada"}, ""); code != http.StatusNotFound {
+		t.Errorf("already removed: status = %d, want 404", code)
+	}
+
+	subscribe()
+	if code := unsubscribe(nil, "s3cret"); code != http.StatusOK {
+		t.Errorf("admin token: status = %d, want 200", code)
+	}
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// SavedSearchHandler handles saved history search HTTP requests
+type SavedSearchHandler struct {
+	searchService *service.SavedSearchService
+}
+
+// NewSavedSearchHandler creates a new saved search handler
+func NewSavedSearchHandler(searchService *service.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{searchService: searchService}
+}
+
+// savedSearchRequest is the body of POST and PUT saved search requests
+type savedSearchRequest struct {
+	Name    string               `json:"name"`
+	Filters domain.SearchFilters `json:"filters"`
+	Notify  bool                 `json:"notify"`
+}
+
+func (req savedSearchRequest) search() domain.SavedSearch {
+	return domain.SavedSearch{Name: req.Name, Filters: req.Filters, Notify: req.Notify}
+}
+
+// Searches handles GET and POST /api/searches
+func (h *SavedSearchHandler) Searches(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		searches, err := h.searchService.List(r.Context(), principal.ID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to list saved searches")
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success":  true,
+			"count":    len(searches),
+			"searches": searches,
+		})
+
+	case http.MethodPost:
+		var req savedSearchRequest
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		search, err := h.searchService.Create(r.Context(), principal.ID, req.search())
+		if err != nil {
+			respondWithSavedSearchError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+			"success": true,
+			"search":  search,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Search handles GET, PUT and DELETE /api/searches/{id}
+func (h *SavedSearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	id := r.PathValue("id")
+
+	switch r.Method {
+	case http.MethodGet:
+		search, err := h.searchService.Get(r.Context(), principal.ID, id)
+		if err != nil {
+			respondWithSavedSearchError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"search":  search,
+		})
+
+	case http.MethodPut:
+		var req savedSearchRequest
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		search, err := h.searchService.Update(r.Context(), principal.ID, id, req.search())
+		if err != nil {
+			respondWithSavedSearchError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"search":  search,
+		})
+
+	case http.MethodDelete:
+		if err := h.searchService.Delete(r.Context(), principal.ID, id); err != nil {
+			respondWithSavedSearchError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"id":      id,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Results handles GET /api/searches/{id}/results
+//
+// Re-runs the saved filters over history, newest first. Optional limit and
+// offset page through the matches.
+func (h *SavedSearchHandler) Results(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	limit, offset, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "limit and offset must be non-negative integers")
+		return
+	}
+
+	search, predictions, err := h.searchService.Results(r.Context(), principal.ID, r.PathValue("id"), limit, offset)
+	if err != nil {
+		respondWithSavedSearchError(w, err)
+		return
+	}
+
+	locale := negotiateLocale(w, r)
+	for i, p := range predictions {
+		predictions[i] = p.Localized(locale)
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"search":  search,
+		"count":   len(predictions),
+		"results": predictions,
+	})
+}
+
+// parsePage reads the optional limit and offset URL parameters
+func parsePage(r *http.Request) (limit, offset int, err error) {
+	params := r.URL.Query()
+	if v := params.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			return 0, 0, errors.New("invalid limit")
+		}
+	}
+	if v := params.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			return 0, 0, errors.New("invalid offset")
+		}
+	}
+	return limit, offset, nil
+}
+
+func respondWithSavedSearchError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrSavedSearchNotFound):
+		respondWithError(w, http.StatusNotFound, "Saved search not found")
+	case errors.Is(err, domain.ErrInvalidSavedSearch), errors.Is(err, domain.ErrInvalidQuery):
+		respondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrSavedSearchLimitReached):
+		respondWithError(w, http.StatusForbidden, err.Error())
+	default:
+		respondWithError(w, http.StatusInternalServerError, "Failed to process saved search")
+	}
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// anonymousSessionCookie groups the predictions of a visitor who is not
+// signed in
+const anonymousSessionCookie = "fn_anon"
+
+// SessionHandler handles requests about the caller's session, whichever
+// way it signed in
+type SessionHandler struct{}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler() *SessionHandler {
+	return &SessionHandler{}
+}
+
+// Me handles GET /api/auth/me
+func (h *SessionHandler) Me(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"id":      principal.ID,
+		"method":  principal.Method,
+		"role":    principal.Role,
+		"org_id":  principal.OrgID,
+		"plan":    principal.Plan,
+	})
+}
+
+// Logout handles POST /api/auth/logout by clearing the session cookie.
+// Bearer session tokens stay valid until they expire.
+func (h *SessionHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: middleware.SessionCookie, Path: "/", MaxAge: -1})
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+}
+
+// respondWithLogin sets the session cookie of a completed sign-on, then
+// sends the browser to successRedirect or, when it is empty, returns the
+// session as JSON.
+func respondWithLogin(w http.ResponseWriter, r *http.Request, login *service.SSOLogin, successRedirect string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.SessionCookie,
+		Value:    login.Token,
+		Path:     "/",
+		Expires:  login.Session.ExpiresAt,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	if successRedirect != "" {
+		http.Redirect(w, r, successRedirect, http.StatusFound)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"token":      login.Token,
+		"expires_at": login.Session.ExpiresAt,
+		"user":       userResponse(login.User),
+	})
+}
+
+// anonymousSession returns the anonymous session of a caller who is not
+// signed in. With issue, a caller without a valid one gets a new session
+// and its cookie.
+func anonymousSession(w http.ResponseWriter, r *http.Request, anonymous *service.AnonymousHistory, issue bool) string {
+	if anonymous == nil {
+		return ""
+	}
+	if _, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		return ""
+	}
+	if cookie, err := r.Cookie(anonymousSessionCookie); err == nil {
+		if sessionID, err := anonymous.Verify(cookie.Value); err == nil {
+			return sessionID
+		}
+	}
+	if !issue {
+		return ""
+	}
+	sessionID, token, expires, err := anonymous.Issue()
+	if err != nil {
+		return ""
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     anonymousSessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	return sessionID
+}
+
+// mergeAnonymousHistory gives the predictions of the browser's anonymous
+// session to a user who has just signed in, and ends the session. Failures
+// are logged: they must not fail the sign-in.
+func mergeAnonymousHistory(w http.ResponseWriter, r *http.Request, anonymous *service.AnonymousHistory, userID string) {
+	if anonymous == nil {
+		return
+	}
+	cookie, err := r.Cookie(anonymousSessionCookie)
+	if err != nil {
+		return
+	}
+	if _, err := anonymous.Merge(r.Context(), cookie.Value, userID); err != nil && !errors.Is(err, domain.ErrInvalidSession) {
+		log.Printf("Warning: failed to merge anonymous history into user %s: %v", userID, err)
+	}
+	http.SetCookie(w, &http.Cookie{Name: anonymousSessionCookie, Path: "/", MaxAge: -1})
+}
+
+func userResponse(u *domain.User) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            u.ID,
+		"email":         u.Email,
+		"name":          u.Name,
+		"org_id":        u.OrgID,
+		"role":          u.Role,
+		"last_login_at": u.LastLoginAt,
+	}
+}
+
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
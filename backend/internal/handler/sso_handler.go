@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// ssoStateCookie binds a sign-on to the browser that started it
+const ssoStateCookie = "fn_sso_state"
+
+// SSOHandler handles single sign-on HTTP requests
+type SSOHandler struct {
+	ssoService      *service.SSOService
+	successRedirect string
+	anonymous       *service.AnonymousHistory
+}
+
+// NewSSOHandler creates a new SSO handler. After sign-on the browser is
+// sent to successRedirect; when it is empty the session is returned as
+// JSON instead.
+func NewSSOHandler(ssoService *service.SSOService, successRedirect string) *SSOHandler {
+	return &SSOHandler{ssoService: ssoService, successRedirect: successRedirect}
+}
+
+// WithAnonymousHistory merges the browser's anonymous predictions into
+// the account on sign-in
+func (h *SSOHandler) WithAnonymousHistory(anonymous *service.AnonymousHistory) *SSOHandler {
+	h.anonymous = anonymous
+	return h
+}
+
+// Organizations handles GET /api/auth/sso
+func (h *SSOHandler) Organizations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":       true,
+		"organizations": h.ssoService.Organizations(),
+	})
+}
+
+// Login handles GET /api/auth/sso/{org}/login
+func (h *SSOHandler) Login(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authURL, state, err := h.ssoService.LoginURL(r.Context(), r.PathValue("org"))
+	if err != nil {
+		if errors.Is(err, domain.ErrUnknownOrganization) {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusBadGateway, "Identity provider unavailable")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoStateCookie,
+		Value:    state,
+		Path:     "/api/auth/sso",
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback handles GET /api/auth/sso/callback, the redirect URI
+// registered with every identity provider
+func (h *SSOHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	if idpErr := params.Get("error"); idpErr != "" {
+		respondWithError(w, http.StatusUnauthorized, "Sign-on was not completed: "+idpErr)
+		return
+	}
+	state := params.Get("state")
+	cookie, err := r.Cookie(ssoStateCookie)
+	if err != nil || cookie.Value == "" || cookie.Value != state {
+		respondWithError(w, http.StatusBadRequest, "Sign-on state does not match this browser, please retry")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: ssoStateCookie, Path: "/api/auth/sso", MaxAge: -1})
+
+	login, err := h.ssoService.Callback(r.Context(), state, params.Get("code"))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnknownOrganization):
+			respondWithError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, domain.ErrSSOFailed):
+			respondWithError(w, http.StatusUnauthorized, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Sign-on failed")
+		}
+		return
+	}
+
+	mergeAnonymousHistory(w, r, h.anonymous, login.User.ID)
+	respondWithLogin(w, r, login, h.successRedirect)
+}
@@ -0,0 +1,430 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/instrumented"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// StatsHandler handles operational statistics HTTP requests
+type StatsHandler struct {
+	sloTracker  *service.SLOTracker
+	newsService *service.NewsService
+	repository  *instrumented.Recorder
+	canary      *service.Canary
+	drift       *service.DriftMonitor
+	bus         *service.InvalidationBus
+	rollups     *service.MetricsRollupJob
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(sloTracker *service.SLOTracker, newsService *service.NewsService) *StatsHandler {
+	return &StatsHandler{sloTracker: sloTracker, newsService: newsService}
+}
+
+// WithRepositoryStats enables the repository call stats endpoint
+func (h *StatsHandler) WithRepositoryStats(recorder *instrumented.Recorder) *StatsHandler {
+	h.repository = recorder
+	return h
+}
+
+// WithCanary enables the synthetic canary endpoint
+func (h *StatsHandler) WithCanary(canary *service.Canary) *StatsHandler {
+	h.canary = canary
+	return h
+}
+
+// WithDrift enables the prediction drift endpoint
+func (h *StatsHandler) WithDrift(monitor *service.DriftMonitor) *StatsHandler {
+	h.drift = monitor
+	return h
+}
+
+// WithInvalidation enables the cache invalidation endpoint
+func (h *StatsHandler) WithInvalidation(bus *service.InvalidationBus) *StatsHandler {
+	h.bus = bus
+	return h
+}
+
+// WithRollups enables the long-term metrics rollup endpoint
+func (h *StatsHandler) WithRollups(job *service.MetricsRollupJob) *StatsHandler {
+	h.rollups = job
+	return h
+}
+
+// SLO handles GET /api/stats/slo
+func (h *StatsHandler) SLO(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"slos":    h.sloTracker.Status(),
+	})
+}
+
+// Scraper handles GET /api/stats/scraper
+func (h *StatsHandler) Scraper(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"scraper": h.newsService.ScraperStats(),
+	})
+}
+
+// KnownFakes handles GET /api/stats/known-fakes
+func (h *StatsHandler) KnownFakes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	stats := h.newsService.KnownFakeStats()
+	if stats == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":     true,
+		"known_fakes": stats,
+	})
+}
+
+// ReviewQueue handles GET /api/stats/review-queue
+func (h *StatsHandler) ReviewQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	stats := h.newsService.ReviewQueueStats()
+	if stats == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":      true,
+		"review_queue": stats,
+	})
+}
+
+// Canary handles GET /api/stats/canary
+func (h *StatsHandler) Canary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.canary == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"canary":  h.canary.Status(),
+	})
+}
+
+// Drift handles GET /api/stats/drift?recent=&baseline=&type=&domain=&model=.
+// recent and baseline accept Go durations plus d and w suffixes and default
+// to the monitor's configured windows.
+func (h *StatsHandler) Drift(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.drift == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	params := r.URL.Query()
+	recent, err := parseSpan(params.Get("recent"), 0)
+	if err != nil || recent < 0 {
+		respondWithError(w, http.StatusBadRequest, "recent must be a duration such as 24h or 1d")
+		return
+	}
+	baseline, err := parseSpan(params.Get("baseline"), 0)
+	if err != nil || baseline < 0 {
+		respondWithError(w, http.StatusBadRequest, "baseline must be a duration such as 168h or 7d")
+		return
+	}
+	filter := domain.NewPredictionQuery().
+		WithRequestType(params.Get("type")).
+		WithDomain(params.Get("domain")).
+		WithModelVersion(params.Get("model"))
+
+	report, err := h.drift.Compute(r.Context(), *filter, recent, baseline)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidQuery) {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to compute drift")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"drift":   report,
+	})
+}
+
+// Repository handles GET /api/stats/repository
+func (h *StatsHandler) Repository(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.repository == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"operations": h.repository.Stats(),
+	})
+}
+
+// Invalidation handles GET /api/stats/invalidation
+func (h *StatsHandler) Invalidation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.bus == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":      true,
+		"invalidation": h.bus.Stats(),
+	})
+}
+
+// Rollups handles GET /api/stats/rollups?resolution=&range=. resolution is
+// day (default) or hour; range defaults to 180d for daily and 7d for
+// hourly rollups.
+func (h *StatsHandler) Rollups(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.rollups == nil {
+		respondWithError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	params := r.URL.Query()
+	resolution := params.Get("resolution")
+	if resolution == "" {
+		resolution = domain.RollupDay
+	}
+	def := 180 * 24 * time.Hour
+	if resolution == domain.RollupHour {
+		def = 7 * 24 * time.Hour
+	}
+	span, err := parseSpan(params.Get("range"), def)
+	if err != nil || span <= 0 {
+		respondWithError(w, http.StatusBadRequest, "range must be a duration such as 7d or 26w")
+		return
+	}
+
+	now := time.Now()
+	rollups, err := h.rollups.List(r.Context(), resolution, now.Add(-span), now)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidQuery) {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to load rollups")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"resolution": resolution,
+		"rollups":    rollups,
+	})
+}
+
+// ML handles GET /api/stats/ml
+func (h *StatsHandler) ML(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"ml":      h.newsService.MLStats(),
+	})
+}
+
+// TimeSeries handles GET /api/stats/timeseries?metric=&interval=&range=.
+// metric is fake_ratio (default), count, avg_confidence or avg_uncertainty;
+// interval (default 1d) and range (default 30d) accept Go durations plus d
+// and w suffixes. The label, type, domain and model filters of /api/history
+// also apply.
+func (h *StatsHandler) TimeSeries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query, err := parseTimeSeriesQuery(r, time.Now())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	points, err := h.newsService.TimeSeries(r.Context(), query)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidQuery) {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to compute time series")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"metric":   query.Metric,
+		"interval": query.Interval.String(),
+		"since":    query.Since.UTC(),
+		"until":    query.Until.UTC(),
+		"points":   points,
+	})
+}
+
+// Compare handles GET /api/stats/compare?range_a=&range_b=&type=&domain=&model=&top=.
+//
+// Ranges are "start..end", end exclusive, as dates (2024-06-01) or RFC 3339
+// times. Deltas are range_b minus range_a.
+func (h *StatsHandler) Compare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	a, err := parsePeriod(params.Get("range_a"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "range_a: "+err.Error())
+		return
+	}
+	b, err := parsePeriod(params.Get("range_b"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "range_b: "+err.Error())
+		return
+	}
+	top := domain.DefaultCompareTopDomains
+	if v := params.Get("top"); v != "" {
+		if top, err = strconv.Atoi(v); err != nil {
+			respondWithError(w, http.StatusBadRequest, "top must be an integer")
+			return
+		}
+	}
+	filter := domain.NewPredictionQuery().
+		WithRequestType(params.Get("type")).
+		WithDomain(params.Get("domain")).
+		WithModelVersion(params.Get("model"))
+
+	comparison, err := h.newsService.ComparePeriods(r.Context(), *filter, a, b, top)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidQuery) {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to compare ranges")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"comparison": comparison,
+	})
+}
+
+// parsePeriod parses "start..end", where each bound is a date or an
+// RFC 3339 time.
+func parsePeriod(s string) (domain.Period, error) {
+	start, end, ok := strings.Cut(s, "..")
+	if !ok {
+		return domain.Period{}, errors.New("must be start..end, e.g. 2024-06-01..2024-06-08")
+	}
+	var period domain.Period
+	for _, bound := range []struct {
+		value string
+		t     *time.Time
+	}{{start, &period.Since}, {end, &period.Until}} {
+		t, err := time.Parse(time.RFC3339, bound.value)
+		if err != nil {
+			if t, err = time.Parse(time.DateOnly, bound.value); err != nil {
+				return domain.Period{}, fmt.Errorf("invalid time %q", bound.value)
+			}
+		}
+		*bound.t = t
+	}
+	return period, period.Validate()
+}
+
+// parseTimeSeriesQuery builds a time-series query ending at now from URL
+// parameters
+func parseTimeSeriesQuery(r *http.Request, now time.Time) (*domain.TimeSeriesQuery, error) {
+	params := r.URL.Query()
+	q := &domain.TimeSeriesQuery{
+		Metric: params.Get("metric"),
+		Until:  now,
+		Filter: *domain.NewPredictionQuery().
+			WithLabel(params.Get("label")).
+			WithRequestType(params.Get("type")).
+			WithDomain(params.Get("domain")).
+			WithModelVersion(params.Get("model")),
+	}
+	if q.Metric == "" {
+		q.Metric = domain.MetricFakeRatio
+	}
+
+	var err error
+	if q.Interval, err = parseSpan(params.Get("interval"), 24*time.Hour); err != nil {
+		return nil, fmt.Errorf("%w: interval must be a duration such as 1h or 1d", domain.ErrInvalidQuery)
+	}
+	span, err := parseSpan(params.Get("range"), 30*24*time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("%w: range must be a duration such as 24h or 30d", domain.ErrInvalidQuery)
+	}
+	q.Since = now.Add(-span)
+	return q, nil
+}
+
+// parseSpan parses a Go duration, or a whole number of days ("30d") or
+// weeks ("2w"). Empty returns def.
+func parseSpan(s string, def time.Duration) (time.Duration, error) {
+	if s == "" {
+		return def, nil
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count <= 0 {
+				return 0, fmt.Errorf("invalid span %q", s)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// TemplateHandler handles analysis template HTTP requests
+type TemplateHandler struct {
+	templateService *service.AnalysisTemplateService
+}
+
+// NewTemplateHandler creates a new analysis template handler
+func NewTemplateHandler(templateService *service.AnalysisTemplateService) *TemplateHandler {
+	return &TemplateHandler{templateService: templateService}
+}
+
+// templateRequest is the body of POST and PUT template requests
+type templateRequest struct {
+	Name     string                  `json:"name"`
+	Scope    string                  `json:"scope"`
+	Settings domain.TemplateSettings `json:"settings"`
+}
+
+func (req templateRequest) template() domain.AnalysisTemplate {
+	return domain.AnalysisTemplate{Name: req.Name, Scope: req.Scope, Settings: req.Settings}
+}
+
+// templateCaller describes the caller for the template service. Session
+// logins belong to an organization; its admins and analysts manage the
+// organization's templates.
+func templateCaller(principal *middleware.Principal) service.TemplateCaller {
+	return service.TemplateCaller{
+		UserID:    principal.ID,
+		OrgID:     principal.OrgID,
+		OrgEditor: principal.OrgID != "" && (principal.Role == domain.RoleAdmin || principal.Role == domain.RoleAnalyst),
+	}
+}
+
+// Templates handles GET and POST /api/templates
+func (h *TemplateHandler) Templates(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	caller := templateCaller(principal)
+
+	switch r.Method {
+	case http.MethodGet:
+		templates, err := h.templateService.List(r.Context(), caller)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to list analysis templates")
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success":   true,
+			"count":     len(templates),
+			"templates": templates,
+		})
+
+	case http.MethodPost:
+		var req templateRequest
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		template, err := h.templateService.Create(r.Context(), caller, req.template())
+		if err != nil {
+			respondWithTemplateError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+			"success":  true,
+			"template": template,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Template handles GET, PUT and DELETE /api/templates/{id}
+func (h *TemplateHandler) Template(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	caller := templateCaller(principal)
+	id := r.PathValue("id")
+
+	switch r.Method {
+	case http.MethodGet:
+		template, err := h.templateService.Get(r.Context(), caller, id)
+		if err != nil {
+			respondWithTemplateError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success":  true,
+			"template": template,
+		})
+
+	case http.MethodPut:
+		var req templateRequest
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		template, err := h.templateService.Update(r.Context(), caller, id, req.template())
+		if err != nil {
+			respondWithTemplateError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success":  true,
+			"template": template,
+		})
+
+	case http.MethodDelete:
+		if err := h.templateService.Delete(r.Context(), caller, id); err != nil {
+			respondWithTemplateError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"id":      id,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// analysisTemplate returns the settings of the caller's template id, or an
+// error and the status to answer with.
+func (h *NewsHandler) analysisTemplate(r *http.Request, id string) (*domain.TemplateSettings, int, error) {
+	if h.templates == nil {
+		return nil, http.StatusNotFound, domain.ErrTemplateNotFound
+	}
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		return nil, http.StatusUnauthorized, errors.New("template_id requires authentication")
+	}
+	settings, err := h.templates.Resolve(r.Context(), templateCaller(principal), id)
+	switch {
+	case errors.Is(err, domain.ErrTemplateNotFound):
+		return nil, http.StatusNotFound, domain.ErrTemplateNotFound
+	case err != nil:
+		log.Printf("Warning: failed to load analysis template %s: %v", id, err)
+		return nil, http.StatusInternalServerError, errors.New("failed to load analysis template")
+	}
+	return settings, http.StatusOK, nil
+}
+
+func respondWithTemplateError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrTemplateNotFound):
+		respondWithError(w, http.StatusNotFound, "Analysis template not found")
+	case errors.Is(err, domain.ErrInvalidTemplate):
+		respondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrTemplateForbidden), errors.Is(err, domain.ErrTemplateLimitReached):
+		respondWithError(w, http.StatusForbidden, err.Error())
+	default:
+		respondWithError(w, http.StatusInternalServerError, "Failed to process analysis template")
+	}
+}
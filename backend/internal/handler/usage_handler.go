@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// UsageHandler lets callers inspect their own API usage
+type UsageHandler struct {
+	tracker     *service.UsageTracker
+	newsService *service.NewsService
+	quotas      *service.QuotaTracker
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(tracker *service.UsageTracker, newsService *service.NewsService) *UsageHandler {
+	return &UsageHandler{tracker: tracker, newsService: newsService}
+}
+
+// WithQuotas reports the caller's daily scrape and inference quotas
+func (h *UsageHandler) WithQuotas(quotas *service.QuotaTracker) *UsageHandler {
+	h.quotas = quotas
+	return h
+}
+
+// pinQuota is the pin quota section of a usage response
+type pinQuota struct {
+	Used  int `json:"used"`
+	Limit int `json:"limit"` // 0 = unlimited
+}
+
+// Usage handles GET /api/users/me/usage?window=1h|24h|7d|30d
+func (h *UsageHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	caller := middleware.UsageCaller(r)
+	if caller == "" {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	window := r.URL.Query().Get("window")
+	if window == "" {
+		window = service.DefaultUsageWindow
+	}
+	summary, err := h.tracker.Summary(caller, window)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "window must be one of 1h, 24h, 7d, 30d")
+		return
+	}
+
+	quota := map[string]interface{}{}
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		used, limit, err := h.newsService.PinUsage(r.Context(), principal.ID, principal.Plan)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to load quota")
+			return
+		}
+		quota["pins"] = pinQuota{Used: used, Limit: limit}
+	}
+	if subject, plan := middleware.QuotaSubject(r); subject != "" && h.quotas != nil {
+		for dimension, usage := range h.quotas.Usage(subject, plan) {
+			quota[dimension] = usage
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"caller":  caller,
+		"usage":   summary,
+		"quota":   quota,
+	})
+}
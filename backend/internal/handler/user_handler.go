@@ -27,7 +27,7 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var user domain.User
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+	if err := decodeRequest(r, &user); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
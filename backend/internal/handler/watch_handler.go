@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// WatchHandler handles article watch HTTP requests
+type WatchHandler struct {
+	watchService *service.WatchService
+}
+
+// NewWatchHandler creates a new watch handler
+func NewWatchHandler(watchService *service.WatchService) *WatchHandler {
+	return &WatchHandler{watchService: watchService}
+}
+
+// Watches handles GET and POST /api/watches
+func (h *WatchHandler) Watches(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		watches, err := h.watchService.List(r.Context(), principal.ID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to list watches")
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"count":   len(watches),
+			"watches": watches,
+		})
+
+	case http.MethodPost:
+		var req struct {
+			URL string `json:"url"`
+		}
+		if err := decodeRequest(r, &req); err != nil || req.URL == "" {
+			respondWithError(w, http.StatusBadRequest, "url is required")
+			return
+		}
+
+		watch, err := h.watchService.Watch(r.Context(), principal.ID, req.URL)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrInvalidURL), errors.Is(err, domain.ErrEmptyContent):
+				respondWithError(w, http.StatusBadRequest, err.Error())
+			case errors.Is(err, domain.ErrWatchLimitReached):
+				respondWithError(w, http.StatusForbidden, err.Error())
+			case errors.Is(err, domain.ErrUnsupportedContentType), errors.Is(err, domain.ErrNotAnArticle):
+				respondWithError(w, http.StatusUnprocessableEntity, err.Error())
+			case errors.Is(err, domain.ErrMLServiceUnavailable), errors.Is(err, domain.ErrPredictionFailed):
+				respondWithError(w, http.StatusServiceUnavailable, "ML service unavailable")
+			default:
+				respondWithError(w, http.StatusBadGateway, "Failed to check article")
+			}
+			return
+		}
+		respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+			"success": true,
+			"watch":   watch,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Unwatch handles DELETE /api/watches/{id}
+func (h *WatchHandler) Unwatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	if err := h.watchService.Unwatch(r.Context(), principal.ID, r.PathValue("id")); err != nil {
+		if errors.Is(err, domain.ErrWatchNotFound) {
+			respondWithError(w, http.StatusNotFound, "Watch not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to remove watch")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"id":      r.PathValue("id"),
+	})
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// WebhookHandler handles webhook subscription HTTP requests
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// webhookRequest is the body of POST and PUT webhook requests
+type webhookRequest struct {
+	URL       string               `json:"url"`
+	Events    []string             `json:"events"`
+	Filter    domain.WebhookFilter `json:"filter"`
+	AllOwners bool                 `json:"all_owners"`
+	Active    *bool                `json:"active"` // PUT only; defaults to true
+}
+
+func (req webhookRequest) subscription() domain.WebhookSubscription {
+	return domain.WebhookSubscription{
+		URL:       req.URL,
+		Events:    req.Events,
+		Filter:    req.Filter,
+		AllOwners: req.AllOwners,
+		Active:    req.Active == nil || *req.Active,
+	}
+}
+
+// Webhooks handles GET and POST /api/webhooks
+func (h *WebhookHandler) Webhooks(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		subs, err := h.webhookService.List(r.Context(), principal.ID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to list webhooks")
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success":     true,
+			"count":       len(subs),
+			"webhooks":    subs,
+			"event_types": domain.WebhookEventTypes,
+		})
+
+	case http.MethodPost:
+		var req webhookRequest
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.AllOwners && !canWatchAllOwners(principal) {
+			respondWithError(w, http.StatusForbidden, "Only admins can subscribe to every owner's events")
+			return
+		}
+		sub, err := h.webhookService.Create(r.Context(), principal.ID, req.subscription())
+		if err != nil {
+			respondWithWebhookError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+			"success": true,
+			"webhook": sub,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Webhook handles GET, PUT and DELETE /api/webhooks/{id}
+func (h *WebhookHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	id := r.PathValue("id")
+
+	switch r.Method {
+	case http.MethodGet:
+		sub, err := h.webhookService.Get(r.Context(), principal.ID, id)
+		if err != nil {
+			respondWithWebhookError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"webhook": sub,
+		})
+
+	case http.MethodPut:
+		var req webhookRequest
+		if err := decodeRequest(r, &req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.AllOwners && !canWatchAllOwners(principal) {
+			respondWithError(w, http.StatusForbidden, "Only admins can subscribe to every owner's events")
+			return
+		}
+		sub, err := h.webhookService.Update(r.Context(), principal.ID, id, req.subscription())
+		if err != nil {
+			respondWithWebhookError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"webhook": sub,
+		})
+
+	case http.MethodDelete:
+		if err := h.webhookService.Delete(r.Context(), principal.ID, id); err != nil {
+			respondWithWebhookError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"id":      id,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Test handles POST /api/webhooks/{id}/test
+//
+// Sends a signed webhook.test event once and reports the response status,
+// so a receiver can be checked before real events arrive.
+func (h *WebhookHandler) Test(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	result, err := h.webhookService.Test(r.Context(), principal.ID, r.PathValue("id"))
+	if err != nil {
+		respondWithWebhookError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"test":    result,
+	})
+}
+
+// canWatchAllOwners reports whether a caller may receive every owner's
+// events: admins and service accounts.
+func canWatchAllOwners(principal *middleware.Principal) bool {
+	return principal.Role == domain.RoleAdmin || middleware.IsServiceAccount(principal)
+}
+
+func respondWithWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrWebhookNotFound):
+		respondWithError(w, http.StatusNotFound, "Webhook not found")
+	case errors.Is(err, domain.ErrInvalidWebhook):
+		respondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrWebhookLimitReached):
+		respondWithError(w, http.StatusForbidden, err.Error())
+	default:
+		respondWithError(w, http.StatusInternalServerError, "Failed to process webhook")
+	}
+}
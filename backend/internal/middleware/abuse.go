@@ -0,0 +1,295 @@
+package middleware
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TokenVerifier verifies a client-supplied CAPTCHA token.
+type TokenVerifier interface {
+	Verify(token, remoteIP string) error
+}
+
+// AbuseConfig holds tuning knobs for the abuse guard.
+type AbuseConfig struct {
+	Threshold   float64       // score at which a client gets banned
+	HalfLife    time.Duration // time for a client's score to decay by half
+	BanDuration time.Duration // how long automatic bans last
+}
+
+// DefaultAbuseConfig returns conservative defaults for the public endpoint.
+func DefaultAbuseConfig() AbuseConfig {
+	return AbuseConfig{
+		Threshold:   40,
+		HalfLife:    5 * time.Minute,
+		BanDuration: 30 * time.Minute,
+	}
+}
+
+// Score weights for observed client behaviour.
+const (
+	weightRequest     = 1.0 // every request costs something
+	weightBurst       = 2.0 // request arrived less than a second after the last one
+	weightClientError = 3.0 // 4xx — invalid URLs, rejected content types, ...
+	weightScrapeError = 5.0 // 502 — the target site could not be scraped
+	weightCaptchaFail = 5.0 // missing or invalid CAPTCHA token
+)
+
+// Ban describes an active IP ban.
+type Ban struct {
+	IP        string    `json:"ip"`
+	Reason    string    `json:"reason"`
+	Manual    bool      `json:"manual"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type clientScore struct {
+	value    float64
+	updated  time.Time
+	lastSeen time.Time
+}
+
+// AbuseGuard scores client IPs on anonymous endpoints and temporarily bans
+// clients that look like they are using the API as a scraping proxy.
+type AbuseGuard struct {
+	cfg        AbuseConfig
+	captcha    TokenVerifier
+	adminToken string
+
+	mu      sync.Mutex
+	scores  map[string]*clientScore
+	bans    map[string]Ban
+	records int
+}
+
+// NewAbuseGuard creates a new abuse guard.
+func NewAbuseGuard(cfg AbuseConfig) *AbuseGuard {
+	defaults := DefaultAbuseConfig()
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = defaults.Threshold
+	}
+	if cfg.HalfLife <= 0 {
+		cfg.HalfLife = defaults.HalfLife
+	}
+	if cfg.BanDuration <= 0 {
+		cfg.BanDuration = defaults.BanDuration
+	}
+	return &AbuseGuard{
+		cfg:    cfg,
+		scores: make(map[string]*clientScore),
+		bans:   make(map[string]Ban),
+	}
+}
+
+// WithCaptcha requires anonymous clients to present a valid CAPTCHA token.
+func (g *AbuseGuard) WithCaptcha(verifier TokenVerifier) *AbuseGuard {
+	g.captcha = verifier
+	return g
+}
+
+// WithAdminToken exempts requests bearing the admin token from the CAPTCHA.
+func (g *AbuseGuard) WithAdminToken(token string) *AbuseGuard {
+	g.adminToken = token
+	return g
+}
+
+// Middleware wraps next with ban enforcement, CAPTCHA checks and scoring.
+func (g *AbuseGuard) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ip := ClientIP(r)
+		if ban, banned := g.activeBan(ip); banned {
+			retryAfter := int(math.Ceil(time.Until(ban.ExpiresAt).Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			writeJSONError(w, http.StatusForbidden, "Too many suspicious requests, try again later")
+			return
+		}
+
+		if g.captcha != nil && isAnonymous(r, g.adminToken) {
+			if err := g.captcha.Verify(r.Header.Get("X-Captcha-Token"), ip); err != nil {
+				g.record(ip, weightCaptchaFail)
+				writeJSONError(w, http.StatusForbidden, "CAPTCHA verification required")
+				return
+			}
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		weight := weightRequest
+		switch {
+		case rec.status == http.StatusBadGateway:
+			weight += weightScrapeError
+		case rec.status >= 400 && rec.status < 500:
+			weight += weightClientError
+		}
+		g.record(ip, weight)
+	})
+}
+
+// Score returns the current (decayed) anomaly score for an IP.
+func (g *AbuseGuard) Score(ip string) float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	cs, ok := g.scores[ip]
+	if !ok {
+		return 0
+	}
+	return g.decayed(cs, time.Now())
+}
+
+// Bans returns all active bans, soonest expiry first.
+func (g *AbuseGuard) Bans() []Ban {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	bans := make([]Ban, 0, len(g.bans))
+	for ip, ban := range g.bans {
+		if now.After(ban.ExpiresAt) {
+			delete(g.bans, ip)
+			continue
+		}
+		bans = append(bans, ban)
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].ExpiresAt.Before(bans[j].ExpiresAt)
+	})
+	return bans
+}
+
+// BanIP manually bans an IP. A zero duration uses the configured default.
+func (g *AbuseGuard) BanIP(ip, reason string, duration time.Duration) Ban {
+	if duration <= 0 {
+		duration = g.cfg.BanDuration
+	}
+	ban := Ban{
+		IP:        ip,
+		Reason:    reason,
+		Manual:    true,
+		ExpiresAt: time.Now().Add(duration),
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.bans[ip] = ban
+	return ban
+}
+
+// Unban lifts a ban and resets the IP's score. It reports whether a ban existed.
+func (g *AbuseGuard) Unban(ip string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, existed := g.bans[ip]
+	delete(g.bans, ip)
+	delete(g.scores, ip)
+	return existed
+}
+
+// ---------- private helpers ----------
+
+func (g *AbuseGuard) activeBan(ip string) (Ban, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ban, ok := g.bans[ip]
+	if !ok {
+		return Ban{}, false
+	}
+	if time.Now().After(ban.ExpiresAt) {
+		delete(g.bans, ip)
+		return Ban{}, false
+	}
+	return ban, true
+}
+
+func (g *AbuseGuard) record(ip string, weight float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	cs, ok := g.scores[ip]
+	if !ok {
+		cs = &clientScore{updated: now}
+		g.scores[ip] = cs
+	} else if now.Sub(cs.lastSeen) < time.Second {
+		weight += weightBurst
+	}
+
+	cs.value = g.decayed(cs, now) + weight
+	cs.updated = now
+	cs.lastSeen = now
+
+	if cs.value >= g.cfg.Threshold {
+		g.bans[ip] = Ban{
+			IP:        ip,
+			Reason:    "anomaly score exceeded threshold",
+			ExpiresAt: now.Add(g.cfg.BanDuration),
+		}
+		delete(g.scores, ip)
+	}
+
+	g.records++
+	if g.records%1024 == 0 {
+		g.sweep(now)
+	}
+}
+
+// decayed applies exponential decay to a score. Callers must hold g.mu.
+func (g *AbuseGuard) decayed(cs *clientScore, now time.Time) float64 {
+	elapsed := now.Sub(cs.updated)
+	if elapsed <= 0 {
+		return cs.value
+	}
+	return cs.value * math.Pow(0.5, float64(elapsed)/float64(g.cfg.HalfLife))
+}
+
+// sweep drops expired bans and negligible scores. Callers must hold g.mu.
+func (g *AbuseGuard) sweep(now time.Time) {
+	for ip, ban := range g.bans {
+		if now.After(ban.ExpiresAt) {
+			delete(g.bans, ip)
+		}
+	}
+	for ip, cs := range g.scores {
+		if g.decayed(cs, now) < 0.5 {
+			delete(g.scores, ip)
+		}
+	}
+}
+
+// statusRecorder captures the status code written by the wrapped handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController and to
+// handlers looking for their own writer wrappers.
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+func writeJSONError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAbuseGuard_BansAfterThreshold(t *testing.T) {
+	guard := NewAbuseGuard(AbuseConfig{Threshold: 10, HalfLife: time.Hour, BanDuration: time.Minute})
+	handler := guard.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	var last int
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/api/analyze", nil)
+		req.RemoteAddr = "203.0.113.7:4321"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		last = rec.Code
+	}
+
+	if last != http.StatusForbidden {
+		t.Errorf("Middleware() status = %d, want %d", last, http.StatusForbidden)
+	}
+	if len(guard.Bans()) != 1 {
+		t.Errorf("Bans() got %d bans, want 1", len(guard.Bans()))
+	}
+
+	if !guard.Unban("203.0.113.7") {
+		t.Error("Unban() should report an existing ban")
+	}
+	if len(guard.Bans()) != 0 {
+		t.Error("Bans() should be empty after Unban()")
+	}
+}
+
+type captchaFunc func(token string) error
+
+func (f captchaFunc) Verify(token, remoteIP string) error { return f(token) }
+
+func TestAbuseGuardCaptchaForUnverifiedCallers(t *testing.T) {
+	guard := NewAbuseGuard(AbuseConfig{}).WithAdminToken("s3cret").WithCaptcha(captchaFunc(func(token string) error {
+		if token != "solved" {
+			return errors.New("invalid captcha")
+		}
+		return nil
+	}))
+	handler := guard.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name string
+		ctx  func(context.Context) context.Context
+		auth string
+		want int
+	}{
+		{"anonymous", nil, "", http.StatusForbidden},
+		{"junk bearer token", nil, "Bearer x", http.StatusForbidden},
+		{"admin token", nil, "Bearer s3cret", http.StatusOK},
+		{"signed-in user", func(ctx context.Context) context.Context {
+			return ContextWithPrincipal(ctx, &Principal{ID: "ada", Method: AuthJWT})
+		}, "Bearer header.payload.sig", http.StatusOK},
+		{"registered API key", func(ctx context.Context) context.Context {
+			return ContextWithAPIClient(ctx, &APIClient{APIKey: "k", Name: "extension"})
+		}, "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/analyze", nil)
+			req.RemoteAddr = "198.51.100.9:4321"
+			if tt.ctx != nil {
+				req = req.WithContext(tt.ctx(req.Context()))
+			}
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8, 192.0.2.7")
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		proxies *TrustedProxies
+		remote  string
+		fwd     []string
+		want    string
+	}{
+		{"direct", proxies, "198.51.100.9:1234", nil, "198.51.100.9"},
+		{"untrusted sender's header is ignored", proxies, "198.51.100.9:1234", []string{"203.0.113.1"}, "198.51.100.9"},
+		{"no trusted proxies", nil, "10.0.0.1:1234", []string{"203.0.113.1"}, "10.0.0.1"},
+		{"through a proxy", proxies, "10.0.0.1:1234", []string{"203.0.113.1"}, "203.0.113.1"},
+		{"spoofed first hop", proxies, "10.0.0.1:1234", []string{"1.2.3.4, 203.0.113.1"}, "203.0.113.1"},
+		{"chained proxies", proxies, "10.0.0.1:1234", []string{"1.2.3.4, 203.0.113.1, 192.0.2.7"}, "203.0.113.1"},
+		{"repeated headers", proxies, "10.0.0.1:1234", []string{"1.2.3.4", "203.0.113.1, 10.1.1.1"}, "203.0.113.1"},
+		{"only proxies", proxies, "10.0.0.1:1234", []string{"10.2.2.2"}, "10.2.2.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			for _, v := range tt.fwd {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+			var got string
+			tt.proxies.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ClientIP(r)
+			})).ServeHTTP(httptest.NewRecorder(), req)
+			if got != tt.want {
+				t.Errorf("ClientIP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Without the middleware the header is never read
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.1")
+	if got := ClientIP(req); got != "10.0.0.1" {
+		t.Errorf("ClientIP() = %v, want the connection's address", got)
+	}
+	if _, err := ParseTrustedProxies("10.0.0.0/8, proxy"); err == nil {
+		t.Error("ParseTrustedProxies() accepted a host name")
+	}
+}
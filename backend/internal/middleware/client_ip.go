@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// TrustedProxies are the proxies in front of the API whose X-Forwarded-For
+// header is believed. A client can put any address in that header, so it
+// is only read from requests a trusted proxy sent, and only up to the
+// first hop no trusted proxy vouches for. A nil TrustedProxies trusts no
+// proxy and every request's address is its connection's.
+type TrustedProxies struct {
+	prefixes []netip.Prefix
+}
+
+// ParseTrustedProxies parses a comma-separated list of proxy addresses and
+// CIDR ranges, such as "10.0.0.0/8, 172.16.0.1". An empty list returns nil.
+func ParseTrustedProxies(list string) (*TrustedProxies, error) {
+	var entries []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	prefixes, err := parsePrefixes(entries, "TRUSTED_PROXIES")
+	if err != nil {
+		return nil, err
+	}
+	return &TrustedProxies{prefixes: prefixes}, nil
+}
+
+// trusts reports whether ip is a trusted proxy.
+func (t *TrustedProxies) trusts(ip string) bool {
+	if t == nil {
+		return false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range t.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve returns the originating client IP of r. Starting from the
+// connection, it walks X-Forwarded-For from the right, the hop each proxy
+// appended, while the address so far is a trusted proxy.
+func (t *TrustedProxies) resolve(r *http.Request) string {
+	ip := remoteIP(r)
+	if !t.trusts(ip) {
+		return ip
+	}
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0 && t.trusts(ip); i-- {
+		if hop := strings.TrimSpace(hops[i]); hop != "" {
+			ip = hop
+		}
+	}
+	return ip
+}
+
+// Middleware resolves each request's client IP once for ClientIP.
+func (t *TrustedProxies) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), clientIPKey{}, t.resolve(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+type clientIPKey struct{}
+
+// ClientIP returns the originating client IP used for bans, rate limits
+// and key allowlists: as resolved by TrustedProxies.Middleware, else the
+// connection's address.
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
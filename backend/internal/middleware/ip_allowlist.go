@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxKeyViolations is the number of recent violations kept for the audit.
+const maxKeyViolations = 500
+
+// IPAllowlist restricts a key to source addresses and CIDR ranges. An
+// empty allowlist allows every address.
+type IPAllowlist []netip.Prefix
+
+// ParseIPAllowlist parses addresses ("203.0.113.7") and CIDR ranges
+// ("203.0.113.0/24", "2001:db8::/32").
+func ParseIPAllowlist(entries []string) (IPAllowlist, error) {
+	return parsePrefixes(entries, "allowed_ips")
+}
+
+// parsePrefixes parses addresses and CIDR ranges, naming setting in errors.
+func parsePrefixes(entries []string, setting string) ([]netip.Prefix, error) {
+	var list []netip.Prefix
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s entry %q", setting, entry)
+			}
+			list = append(list, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s entry %q", setting, entry)
+		}
+		list = append(list, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return list, nil
+}
+
+// Allows reports whether ip may use the key.
+func (l IPAllowlist) Allows(ip string) bool {
+	if len(l) == 0 {
+		return true
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range l {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyViolation is a request refused because its key was used from an
+// address outside the key's allowlist.
+type KeyViolation struct {
+	Key    string    `json:"key"`    // API client name or signing key ID, never the secret
+	Method string    `json:"method"` // api_key or signature
+	IP     string    `json:"ip"`
+	Path   string    `json:"path"`
+	At     time.Time `json:"at"`
+}
+
+// KeyViolationReport summarizes recorded allowlist violations.
+type KeyViolationReport struct {
+	Total  int            `json:"total"`
+	ByKey  map[string]int `json:"by_key"`
+	Recent []KeyViolation `json:"recent"` // newest first
+}
+
+// KeyViolations is the audit trail of allowlist violations. A burst of
+// them for one key usually means the key has leaked.
+type KeyViolations struct {
+	mu     sync.Mutex
+	recent []KeyViolation
+	total  int
+	byKey  map[string]int
+}
+
+// NewKeyViolations creates an empty audit trail.
+func NewKeyViolations() *KeyViolations {
+	return &KeyViolations{byKey: make(map[string]int)}
+}
+
+// Record logs and keeps a violation.
+func (v *KeyViolations) Record(violation KeyViolation) {
+	log.Printf("Warning: %s %q used from disallowed address %s (%s)", violation.Method, violation.Key, violation.IP, violation.Path)
+	if v == nil {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.total++
+	v.byKey[violation.Key]++
+	v.recent = append(v.recent, violation)
+	if len(v.recent) > maxKeyViolations {
+		v.recent = v.recent[len(v.recent)-maxKeyViolations:]
+	}
+}
+
+// Report returns the totals and up to limit recent violations.
+func (v *KeyViolations) Report(limit int) KeyViolationReport {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	report := KeyViolationReport{Total: v.total, ByKey: make(map[string]int, len(v.byKey)), Recent: []KeyViolation{}}
+	for key, n := range v.byKey {
+		report.ByKey[key] = n
+	}
+	for i := len(v.recent) - 1; i >= 0 && len(report.Recent) < limit; i-- {
+		report.Recent = append(report.Recent, v.recent[i])
+	}
+	return report
+}
+
+// Key authentication methods recorded on a KeyViolation.
+const (
+	keyMethodAPIKey    = "api_key"
+	keyMethodSignature = AuthSignature
+)
+
+// enforceAllowlist responds 403 and records a violation when the request
+// comes from outside allowlist.
+func enforceAllowlist(w http.ResponseWriter, r *http.Request, allowlist IPAllowlist, violations *KeyViolations, key, method string) bool {
+	ip := ClientIP(r)
+	if allowlist.Allows(ip) {
+		return true
+	}
+	violations.Record(KeyViolation{Key: key, Method: method, IP: ip, Path: r.URL.Path, At: time.Now().UTC()})
+	writeJSONError(w, http.StatusForbidden, "This key is not allowed from your IP address ("+ip+")")
+	return false
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestIPAllowlist(t *testing.T) {
+	list, err := ParseIPAllowlist([]string{"203.0.113.7", "198.51.100.0/24", "2001:db8::/32"})
+	if err != nil {
+		t.Fatalf("ParseIPAllowlist() error = %v", err)
+	}
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"203.0.113.7", true},
+		{"203.0.113.8", false},
+		{"198.51.100.200", true},
+		{"::ffff:198.51.100.1", true},
+		{"2001:db8:1::5", true},
+		{"2001:db9::1", false},
+		{"not-an-ip", false},
+	}
+	for _, tt := range tests {
+		if got := list.Allows(tt.ip); got != tt.want {
+			t.Errorf("Allows(%q) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+	if !IPAllowlist(nil).Allows("192.0.2.1") {
+		t.Error("an empty allowlist should allow every address")
+	}
+	if _, err := ParseIPAllowlist([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("ParseIPAllowlist() accepted an invalid range")
+	}
+}
+
+func TestKeyAllowlistEnforcement(t *testing.T) {
+	violations := NewKeyViolations()
+	clients, err := NewAPIClients([]APIClient{{APIKey: "leaked", Name: "partner-feed", AllowedIPs: []string{"198.51.100.0/24"}}})
+	if err != nil {
+		t.Fatalf("NewAPIClients() error = %v", err)
+	}
+	clients.WithViolations(violations)
+	key := SigningKey{KeyID: "partner", Secret: "s3cret", AllowedIPs: []string{"198.51.100.10"}}
+	signer, err := NewRequestSigner([]SigningKey{key}, time.Minute)
+	if err != nil {
+		t.Fatalf("NewRequestSigner() error = %v", err)
+	}
+	signer.WithViolations(violations)
+	handler := signer.Middleware(clients.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	apiKeyRequest := func(ip string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/predictions", nil)
+		req.Header.Set("X-API-Key", "leaked")
+		req.RemoteAddr = ip + ":4000"
+		return req
+	}
+	signedRequest := func(ip string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/predictions", nil)
+		ts := time.Now().Unix()
+		req.Header.Set(HeaderSignatureKeyID, key.KeyID)
+		req.Header.Set(HeaderSignatureTimestamp, strconv.FormatInt(ts, 10))
+		req.Header.Set(HeaderSignature, hex.EncodeToString(Sign(key, http.MethodGet, "/api/predictions", ts, nil)))
+		req.RemoteAddr = ip + ":4000"
+		return req
+	}
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+	}{
+		{"api key inside range", apiKeyRequest("198.51.100.42"), http.StatusOK},
+		{"api key outside range", apiKeyRequest("192.0.2.1"), http.StatusForbidden},
+		{"api key with spoofed forwarding", func() *http.Request {
+			req := apiKeyRequest("192.0.2.1")
+			req.Header.Set("X-Forwarded-For", "198.51.100.42")
+			return req
+		}(), http.StatusForbidden},
+		{"signed from allowed address", signedRequest("198.51.100.10"), http.StatusOK},
+		{"signed from elsewhere", signedRequest("192.0.2.1"), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, tt.req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	report := violations.Report(10)
+	if report.Total != 3 || report.ByKey["partner-feed"] != 2 || report.ByKey["partner"] != 1 {
+		t.Errorf("report = %+v", report)
+	}
+	if len(report.Recent) != 3 || report.Recent[0].Method != keyMethodSignature || report.Recent[0].IP != "192.0.2.1" {
+		t.Errorf("recent = %+v", report.Recent)
+	}
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// AuthJWT is the Principal.Method of callers with a bearer JWT.
+const AuthJWT = "jwt"
+
+// DefaultJWTPublicPaths stay reachable without credentials when JWTs are
+// required: health checks and the single sign-on login flow.
+var DefaultJWTPublicPaths = []string{"/api/health", "/api/auth/"}
+
+// JWTAuth authenticates bearer JWTs on /api/* routes. A request whose
+// bearer token is a JWT gets its user as the principal, or 401 when the
+// token does not verify. Other credentials (session tokens, service
+// account tokens, API keys) pass through to their own middleware.
+type JWTAuth struct {
+	verifier *service.JWTVerifier
+	required bool
+	public   []string
+	tokens   []string // opaque bearer tokens handlers check themselves
+}
+
+// NewJWTAuth creates the middleware around verifier.
+func NewJWTAuth(verifier *service.JWTVerifier) *JWTAuth {
+	return &JWTAuth{verifier: verifier}
+}
+
+// WithRequired makes Require answer 401 to /api/* requests that no
+// credential authenticated, except under the public path prefixes.
+func (a *JWTAuth) WithRequired(public ...string) *JWTAuth {
+	a.required = true
+	a.public = public
+	return a
+}
+
+// WithBearerTokens lets Require accept opaque bearer tokens that handlers
+// check themselves, such as the admin and worker tokens. Empty tokens are
+// ignored.
+func (a *JWTAuth) WithBearerTokens(tokens ...string) *JWTAuth {
+	for _, t := range tokens {
+		if t != "" {
+			a.tokens = append(a.tokens, t)
+		}
+	}
+	return a
+}
+
+// Middleware applies JWT authentication to next.
+func (a *JWTAuth) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := PrincipalFromContext(r.Context()); a == nil || ok || !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, bearer := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !bearer || !service.IsJWT(token) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		claims, err := a.verifier.Verify(token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`)
+			writeJSONError(w, http.StatusUnauthorized, "Invalid or expired token")
+			return
+		}
+		scopes := SessionScopes(claims.Role)
+		if claims.Scope != "" {
+			scopes = strings.Fields(claims.Scope)
+			if err := ValidateScopes(scopes); err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`)
+				writeJSONError(w, http.StatusUnauthorized, "Invalid token: "+err.Error())
+				return
+			}
+		}
+		ctx := ContextWithPrincipal(r.Context(), &Principal{
+			ID:     claims.Subject,
+			Method: AuthJWT,
+			Plan:   claims.Plan,
+			Role:   claims.Role,
+			OrgID:  claims.OrgID,
+			Scopes: scopes,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (a *JWTAuth) isPublic(path string) bool {
+	for _, prefix := range a.public {
+		if path == prefix || strings.HasSuffix(prefix, "/") && strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Require enforces WithRequired. It goes inside the other authentication
+// middleware, so it sees only what they verified: a bearer token or
+// session that failed to verify, or an unregistered API key, does not
+// count as a credential.
+func (a *JWTAuth) Require(next http.Handler) http.Handler {
+	if a == nil || !a.required {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") || a.isPublic(r.URL.Path) || a.authenticated(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+		writeJSONError(w, http.StatusUnauthorized, "Authentication required")
+	})
+}
+
+// authenticated reports whether a request has a verified principal or a
+// registered API client, or carries one of the opaque bearer tokens.
+func (a *JWTAuth) authenticated(r *http.Request) bool {
+	if _, ok := PrincipalFromContext(r.Context()); ok {
+		return true
+	}
+	if _, ok := APIClientFromContext(r.Context()); ok {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return true
+		}
+	}
+	return false
+}
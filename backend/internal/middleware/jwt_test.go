@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// signJWT mints a compact JWT with the given algorithm header, signed
+// HS256 with secret.
+func signJWT(t *testing.T, alg, secret string, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestJWTAuth(t *testing.T) {
+	const secret = "jwt-secret"
+	exp := time.Now().Add(time.Hour).Unix()
+	claims := func(extra map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{"sub": "user-1", "iss": "https://id.example", "aud": []string{"api", "web"}, "exp": exp}
+		for k, v := range extra {
+			c[k] = v
+		}
+		return c
+	}
+	verifier := service.NewJWTVerifier(secret).WithIssuer("https://id.example").WithAudience("api")
+	auth := NewJWTAuth(verifier)
+
+	var seen *Principal
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/history", func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = PrincipalFromContext(r.Context())
+	})
+	mux.Handle("/api/admin/tuning", RequireScope(ScopeAdminTuning, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name     string
+		path     string
+		token    string
+		want     int
+		wantUser string
+	}{
+		{"valid token", "/api/history", signJWT(t, "HS256", secret, claims(map[string]interface{}{"org": "acme", "plan": "pro"})), http.StatusOK, "user-1"},
+		{"no token passes through", "/api/history", "", http.StatusOK, ""},
+		{"opaque bearer passes through", "/api/history", "admin-token", http.StatusOK, ""},
+		{"wrong secret", "/api/history", signJWT(t, "HS256", "other", claims(nil)), http.StatusUnauthorized, ""},
+		{"alg none", "/api/history", signJWT(t, "none", secret, claims(nil)), http.StatusUnauthorized, ""},
+		{"expired", "/api/history", signJWT(t, "HS256", secret, claims(map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()})), http.StatusUnauthorized, ""},
+		{"no expiry", "/api/history", signJWT(t, "HS256", secret, claims(map[string]interface{}{"exp": 0})), http.StatusUnauthorized, ""},
+		{"wrong issuer", "/api/history", signJWT(t, "HS256", secret, claims(map[string]interface{}{"iss": "https://evil.example"})), http.StatusUnauthorized, ""},
+		{"wrong audience", "/api/history", signJWT(t, "HS256", secret, claims(map[string]interface{}{"aud": "web"})), http.StatusUnauthorized, ""},
+		{"unknown scope", "/api/history", signJWT(t, "HS256", secret, claims(map[string]interface{}{"scope": "history:read root"})), http.StatusUnauthorized, ""},
+		{"member lacks admin scope", "/api/admin/tuning", signJWT(t, "HS256", secret, claims(nil)), http.StatusForbidden, ""},
+		{"scope claim grants admin", "/api/admin/tuning", signJWT(t, "HS256", secret, claims(map[string]interface{}{"scope": "admin:tuning"})), http.StatusOK, ""},
+		{"outside /api", "/health", signJWT(t, "HS256", "other", claims(nil)), http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = nil
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			auth.Middleware(mux).ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without WWW-Authenticate")
+			}
+			if tt.wantUser != "" {
+				if seen == nil || seen.ID != tt.wantUser || seen.Method != AuthJWT || seen.OrgID != "acme" || seen.Plan != "pro" {
+					t.Errorf("principal = %+v, want %s from the token", seen, tt.wantUser)
+				}
+			}
+		})
+	}
+}
+
+func TestJWTAuthRequired(t *testing.T) {
+	auth := NewJWTAuth(service.NewJWTVerifier("s")).
+		WithRequired(DefaultJWTPublicPaths...).
+		WithBearerTokens("admin-token", "")
+	clients, err := NewAPIClients([]APIClient{{APIKey: "k", Name: "extension"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Require goes inside the other authentication, as in main.go
+	h := auth.Middleware(clients.Middleware(auth.Require(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))))
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		value  string
+		want   int
+	}{
+		{"anonymous", "/api/history", "", "", http.StatusUnauthorized},
+		{"API key", "/api/history", "X-API-Key", "k", http.StatusOK},
+		{"unknown API key", "/api/history", "X-API-Key", "guess", http.StatusUnauthorized},
+		{"admin token", "/api/history", "Authorization", "Bearer admin-token", http.StatusOK},
+		{"unverified bearer", "/api/history", "Authorization", "Bearer x", http.StatusUnauthorized},
+		{"empty bearer", "/api/history", "Authorization", "Bearer ", http.StatusUnauthorized},
+		{"public health", "/api/health", "", "", http.StatusOK},
+		{"public login", "/api/auth/sso/acme/login", "", "", http.StatusOK},
+		{"not a prefix match", "/api/healthz", "", "", http.StatusUnauthorized},
+		{"outside /api", "/readyz", "", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
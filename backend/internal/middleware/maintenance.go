@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultMaintenanceMessage is shown when an operator gives no message.
+const defaultMaintenanceMessage = "The service is undergoing maintenance; changes are temporarily disabled"
+
+// MaintenanceWindow is the operator-set maintenance state. An enabled
+// window without StartsAt begins immediately; one without EndsAt lasts
+// until it is turned off.
+type MaintenanceWindow struct {
+	Enabled   bool       `json:"enabled"`
+	Message   string     `json:"message,omitempty"`
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// ActiveAt reports whether writes are blocked at t.
+func (m MaintenanceWindow) ActiveAt(t time.Time) bool {
+	if !m.Enabled {
+		return false
+	}
+	if m.StartsAt != nil && t.Before(*m.StartsAt) {
+		return false
+	}
+	return m.EndsAt == nil || t.Before(*m.EndsAt)
+}
+
+// Validate checks that the window's bounds make sense.
+func (m MaintenanceWindow) Validate() error {
+	if m.StartsAt != nil && m.EndsAt != nil && !m.EndsAt.After(*m.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	return nil
+}
+
+// Maintenance blocks write requests during an operator-controlled
+// maintenance window. The state is saved to a file so a restart in the
+// middle of a migration does not silently reopen writes.
+type Maintenance struct {
+	path string
+
+	mu     sync.RWMutex
+	window MaintenanceWindow
+}
+
+// NewMaintenance loads the maintenance state from path, starting disabled
+// when the file does not exist. An empty path keeps the state in memory.
+func NewMaintenance(path string) (*Maintenance, error) {
+	m := &Maintenance{path: path}
+	if path == "" {
+		return m, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read maintenance state: %w", err)
+	}
+	if err := json.Unmarshal(data, &m.window); err != nil {
+		return nil, fmt.Errorf("failed to parse maintenance state %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Window returns the current maintenance state.
+func (m *Maintenance) Window() MaintenanceWindow {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.window
+}
+
+// Set replaces the maintenance state and saves it.
+func (m *Maintenance) Set(window MaintenanceWindow) (MaintenanceWindow, error) {
+	if err := window.Validate(); err != nil {
+		return MaintenanceWindow{}, err
+	}
+	window.UpdatedAt = time.Now().UTC()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.save(window); err != nil {
+		return MaintenanceWindow{}, err
+	}
+	m.window = window
+	return window, nil
+}
+
+// save writes the state through a temporary file so a crash never leaves
+// a truncated file behind.
+func (m *Maintenance) save(window MaintenanceWindow) error {
+	if m.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(window, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), ".maintenance-*")
+	if err != nil {
+		return fmt.Errorf("failed to save maintenance state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save maintenance state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save maintenance state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), m.path); err != nil {
+		return fmt.Errorf("failed to save maintenance state: %w", err)
+	}
+	return nil
+}
+
+// Middleware rejects write requests with 503 while maintenance is active.
+// Reads, health checks and the admin API keep working so operators can
+// watch the service and lift maintenance.
+func (m *Maintenance) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isReadMethod(r.Method) || strings.HasPrefix(r.URL.Path, "/api/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		now := time.Now()
+		window := m.Window()
+		if !window.ActiveAt(now) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		message := window.Message
+		if message == "" {
+			message = defaultMaintenanceMessage
+		}
+		body := map[string]interface{}{
+			"success":     false,
+			"error":       "maintenance",
+			"message":     message,
+			"maintenance": true,
+		}
+		if window.EndsAt != nil {
+			body["ends_at"] = window.EndsAt
+			retryAfter := int(math.Ceil(window.EndsAt.Sub(now).Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(body)
+	})
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindowActiveAt(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		want   bool
+	}{
+		{"disabled", MaintenanceWindow{}, false},
+		{"open ended", MaintenanceWindow{Enabled: true}, true},
+		{"scheduled", MaintenanceWindow{Enabled: true, StartsAt: &future}, false},
+		{"in window", MaintenanceWindow{Enabled: true, StartsAt: &past, EndsAt: &future}, true},
+		{"ended", MaintenanceWindow{Enabled: true, EndsAt: &past}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.ActiveAt(now); got != tt.want {
+				t.Errorf("ActiveAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceMiddleware(t *testing.T) {
+	m, _ := NewMaintenance("")
+	ends := time.Now().Add(time.Minute)
+	if _, err := m.Set(MaintenanceWindow{Enabled: true, EndsAt: &ends}); err != nil {
+		t.Fatal(err)
+	}
+	h := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodGet, "/api/history", http.StatusOK},
+		{http.MethodPost, "/api/analyze", http.StatusServiceUnavailable},
+		{http.MethodDelete, "/api/predictions/1/pin", http.StatusServiceUnavailable},
+		{http.MethodPost, "/api/admin/maintenance", http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s %s = %d, want %d", tt.method, tt.path, rec.Code, tt.want)
+		}
+		if rec.Code == http.StatusServiceUnavailable && rec.Header().Get("Retry-After") == "" {
+			t.Errorf("%s %s: missing Retry-After", tt.method, tt.path)
+		}
+	}
+}
+
+func TestMaintenancePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "maintenance.json")
+	m, err := NewMaintenance(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Set(MaintenanceWindow{Enabled: true, Message: "migrating"}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewMaintenance(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := reloaded.Window(); !w.Enabled || w.Message != "migrating" {
+		t.Errorf("reloaded window = %+v, want enabled with message", w)
+	}
+}
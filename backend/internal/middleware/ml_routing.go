@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// MLRouting attaches the caller's custom ML route, resolved from the
+// X-API-Key header, to the request context. Unknown or missing keys use the
+// default model.
+func MLRouting(router *service.MLRouter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route, ok := router.Resolve(r.Header.Get("X-API-Key")); ok {
+			r = r.WithContext(service.ContextWithMLRoute(r.Context(), route))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
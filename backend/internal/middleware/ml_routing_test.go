@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+func TestMLRouting(t *testing.T) {
+	router, err := service.NewMLRouter([]service.MLRoute{
+		{Organization: "acme", APIKeys: []string{"acme-1"}, BaseURL: "http://acme.ml"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		router  *service.MLRouter
+		apiKey  string
+		wantOrg string
+	}{
+		{"routed key", router, "acme-1", "acme"},
+		{"unknown key", router, "other", ""},
+		{"no key", router, "", ""},
+		{"no routes configured", nil, "acme-1", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotOrg string
+			h := MLRouting(tt.router, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if route, ok := service.MLRouteFromContext(r.Context()); ok {
+					gotOrg = route.Organization
+				}
+			}))
+			r := httptest.NewRequest(http.MethodPost, "/api/analyze", nil)
+			if tt.apiKey != "" {
+				r.Header.Set("X-API-Key", tt.apiKey)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), r)
+			if gotOrg != tt.wantOrg {
+				t.Errorf("route = %q, want %q", gotOrg, tt.wantOrg)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Authentication methods recorded on a Principal.
+const (
+	AuthSignature = "signature" // HMAC request signing
+	AuthSession   = "session"   // single sign-on session token
+)
+
+// Principal identifies the authenticated caller of a request.
+type Principal struct {
+	ID     string   // key ID, user ID, ...
+	Method string   // how the caller authenticated
+	Plan   string   // subscription plan; empty means free
+	Role   string   // user role for session logins; empty otherwise
+	OrgID  string   // organization for session logins
+	Scopes []string // permissions; nil means unrestricted
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal attaches the authenticated caller to ctx.
+func ContextWithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the authenticated caller, if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// isAnonymous reports whether a request carries no verified credentials:
+// no principal, no registered API key and not the admin token. An
+// unverified Authorization header does not count.
+func isAnonymous(r *http.Request, adminToken string) bool {
+	if _, ok := PrincipalFromContext(r.Context()); ok {
+		return false
+	}
+	if _, ok := APIClientFromContext(r.Context()); ok {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return !ok || adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1
+}
@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a per-client token bucket. Authenticated callers are keyed
+// by principal, even when they also send an API key; other registered API
+// clients by their key and everyone else by client IP. A nil limiter lets
+// every request through.
+//
+// With a queue configured, authenticated callers over the limit are held
+// until a token frees up instead of being rejected, as long as the wait is
+// short enough and their queue has room.
+type RateLimiter struct {
+	rate  float64 // tokens added per second
+	burst float64
+
+	maxWait   time.Duration // longest a queued request is held
+	queueSize int           // queued requests allowed per client
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	checks  int
+}
+
+type bucket struct {
+	tokens  float64 // negative while queued requests hold reservations
+	updated time.Time
+	queued  int
+}
+
+// NewRateLimiter allows rate requests per second per client, with bursts
+// of up to burst requests.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{rate: rate, burst: float64(burst), buckets: make(map[string]*bucket)}
+}
+
+// NewRateLimiterPerMinute allows perMinute requests per minute per client,
+// with bursts of up to burst requests.
+func NewRateLimiterPerMinute(perMinute float64, burst int) *RateLimiter {
+	return NewRateLimiter(perMinute/60, burst)
+}
+
+// WithQueue lets authenticated callers over the limit wait up to maxWait
+// for a token, with at most queueSize requests waiting per caller.
+func (l *RateLimiter) WithQueue(maxWait time.Duration, queueSize int) *RateLimiter {
+	l.maxWait = maxWait
+	l.queueSize = queueSize
+	return l
+}
+
+// Middleware rejects requests over the limit with 429 and Retry-After,
+// or holds them in the caller's queue when queueing applies.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := "ip:" + ClientIP(r)
+		p, authenticated := PrincipalFromContext(r.Context())
+		if authenticated {
+			key = "principal:" + p.ID
+		} else if client, ok := APIClientFromContext(r.Context()); ok {
+			key = "key:" + apiKeyID(client.APIKey)
+		}
+
+		wait, ok := l.reserve(key, time.Now(), authenticated)
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeJSONError(w, http.StatusTooManyRequests, "Rate limit exceeded")
+			return
+		}
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+				l.dequeue(key, false)
+			case <-r.Context().Done():
+				// The caller gave up; return the reserved token.
+				timer.Stop()
+				l.dequeue(key, true)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiKeyID names an API key's bucket without using the secret key itself
+// as a map key.
+func apiKeyID(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// allow takes a token for key, or reports how long until one is available.
+func (l *RateLimiter) allow(key string, now time.Time) (time.Duration, bool) {
+	return l.reserve(key, now, false)
+}
+
+// reserve takes a token for key. When none is left and queue is set, it
+// reserves the next token if the wait fits and the caller's queue has
+// room, returning how long to wait; the caller must then call dequeue.
+// Otherwise it reports how long until a token is available.
+func (l *RateLimiter) reserve(key string, now time.Time, queue bool) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+	b.updated = now
+
+	l.checks++
+	if l.checks%1024 == 0 {
+		l.sweep(now)
+	}
+
+	if b.tokens < 1 {
+		if l.rate <= 0 {
+			return time.Minute, false
+		}
+		wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+		if !queue || wait > l.maxWait || b.queued >= l.queueSize {
+			return wait, false
+		}
+		b.tokens--
+		b.queued++
+		return wait, true
+	}
+	b.tokens--
+	return 0, true
+}
+
+// dequeue releases a queue slot, refunding the token if the request was
+// abandoned.
+func (l *RateLimiter) dequeue(key string, refund bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		return
+	}
+	b.queued--
+	if refund {
+		b.tokens++
+	}
+}
+
+// sweep drops idle buckets that have refilled completely. Callers must
+// hold l.mu.
+func (l *RateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if b.queued == 0 && b.tokens+now.Sub(b.updated).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
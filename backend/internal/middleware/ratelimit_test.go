@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	l := NewRateLimiter(1, 2)
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if _, ok := l.allow("a", now); !ok {
+			t.Fatalf("request %d within burst was rejected", i)
+		}
+	}
+	wait, ok := l.allow("a", now)
+	if ok {
+		t.Fatal("request over burst was allowed")
+	}
+	if wait <= 0 || wait > time.Second {
+		t.Errorf("wait = %v, want (0, 1s]", wait)
+	}
+	if _, ok := l.allow("b", now); !ok {
+		t.Error("other clients should have their own bucket")
+	}
+	if _, ok := l.allow("a", now.Add(time.Second)); !ok {
+		t.Error("bucket should refill over time")
+	}
+}
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	h := NewRateLimiter(0.1, 1).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/domains/example.com/summary", nil)
+	req.RemoteAddr = "203.0.113.7:1234"
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("first request status = %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request status = %d, want 429", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("missing Retry-After header")
+	}
+}
+
+func TestRateLimiterQueue(t *testing.T) {
+	l := NewRateLimiter(10, 1).WithQueue(250*time.Millisecond, 2)
+	now := time.Now()
+
+	if wait, ok := l.reserve("a", now, true); !ok || wait != 0 {
+		t.Fatalf("first request: wait=%v ok=%v", wait, ok)
+	}
+	for i, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond} {
+		wait, ok := l.reserve("a", now, true)
+		if !ok || (wait-want).Abs() > time.Millisecond {
+			t.Fatalf("queued request %d: wait=%v ok=%v, want %v", i, wait, ok, want)
+		}
+	}
+	if _, ok := l.reserve("a", now, true); ok {
+		t.Error("request beyond the queue size was queued")
+	}
+	if _, ok := l.reserve("b", now, false); !ok {
+		t.Fatal("first anonymous request rejected")
+	}
+	if _, ok := l.reserve("b", now, false); ok {
+		t.Error("anonymous requests must not be queued")
+	}
+
+	l.dequeue("a", true)
+	l.dequeue("a", false)
+	if _, ok := l.reserve("a", now, true); !ok {
+		t.Error("freed queue slot was not reused")
+	}
+}
+
+func TestRateLimiterMiddlewareQueue(t *testing.T) {
+	h := NewRateLimiter(20, 1).WithQueue(time.Second, 1).
+		Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/domains/example.com/summary", nil)
+	req = req.WithContext(ContextWithPrincipal(req.Context(), &Principal{ID: "key-1"}))
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	start := time.Now()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("queued request status = %d, want 200", rec.Code)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("queued request was not held (took %v)", elapsed)
+	}
+}
+
+func TestRateLimiterKeysByAPIKey(t *testing.T) {
+	clients, err := NewAPIClients([]APIClient{{APIKey: "k1", Name: "extension"}, {APIKey: "k2", Name: "dashboard"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := clients.Middleware(NewRateLimiterPerMinute(1, 1).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	do := func(apiKey, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/analyze", nil)
+		req.RemoteAddr = ip + ":1234"
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do("k1", "203.0.113.7"); rec.Code != http.StatusOK {
+		t.Fatalf("first k1 request status = %d", rec.Code)
+	}
+	// The key's bucket follows it across addresses
+	rec := do("k1", "198.51.100.9")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "60" {
+		t.Errorf("second k1 request = %d, Retry-After %q; want 429 after 60s", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := do("k2", "203.0.113.7"); rec.Code != http.StatusOK {
+		t.Errorf("k2 from the same address status = %d, want its own bucket", rec.Code)
+	}
+	if rec := do("", "203.0.113.7"); rec.Code != http.StatusOK {
+		t.Errorf("keyless request status = %d, want the address's own bucket", rec.Code)
+	}
+	if rec := do("unregistered", "203.0.113.7"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("unregistered key status = %d, want 429 from the address's bucket", rec.Code)
+	}
+	// A signed-in user sending the exhausted key has a bucket of their own
+	req := httptest.NewRequest(http.MethodPost, "/api/analyze", nil)
+	req.Header.Set("X-API-Key", "k1")
+	req = req.WithContext(ContextWithPrincipal(req.Context(), &Principal{ID: "ada"}))
+	rec = httptest.NewRecorder()
+	if h.ServeHTTP(rec, req); rec.Code != http.StatusOK {
+		t.Errorf("signed-in request with k1 status = %d, want the user's own bucket", rec.Code)
+	}
+
+	var disabled *RateLimiter
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	if got := disabled.Middleware(next); got == nil {
+		t.Error("nil limiter should pass requests through")
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+	"github.com/google/uuid"
+)
+
+// validRequestID limits caller-supplied request IDs to something safe to log
+// and forward.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// validTraceParent matches a W3C traceparent header (version 00).
+var validTraceParent = regexp.MustCompile(`^00-[0-9a-f]{32}-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+// RequestID ensures every request has an X-Request-ID, reusing the caller's
+// when valid, echoes it on the response, and carries it together with any
+// W3C traceparent in the request context for outbound calls.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(service.HeaderRequestID)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.New().String()
+		}
+		trace := service.Trace{RequestID: requestID}
+		if tp := r.Header.Get(service.HeaderTraceParent); validTraceParent.MatchString(tp) {
+			trace.TraceParent = tp
+		}
+
+		w.Header().Set(service.HeaderRequestID, requestID)
+		next.ServeHTTP(w, r.WithContext(service.ContextWithTrace(r.Context(), trace)))
+	})
+}
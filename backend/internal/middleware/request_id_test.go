@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+func TestRequestID(t *testing.T) {
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	tests := []struct {
+		name            string
+		requestID       string
+		traceParent     string
+		wantRequestID   string // empty means a generated ID
+		wantTraceParent string
+	}{
+		{name: "generated", wantTraceParent: ""},
+		{name: "caller supplied", requestID: "abc-123", traceParent: traceParent,
+			wantRequestID: "abc-123", wantTraceParent: traceParent},
+		{name: "invalid values replaced", requestID: "bad id\nwith newline", traceParent: "garbage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got service.Trace
+			h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = service.TraceFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/history", nil)
+			if tt.requestID != "" {
+				req.Header.Set("X-Request-ID", tt.requestID)
+			}
+			if tt.traceParent != "" {
+				req.Header.Set("traceparent", tt.traceParent)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if got.RequestID == "" || (tt.wantRequestID != "" && got.RequestID != tt.wantRequestID) {
+				t.Errorf("RequestID = %q, want %q", got.RequestID, tt.wantRequestID)
+			}
+			if tt.wantRequestID == "" && got.RequestID == tt.requestID {
+				t.Errorf("expected a generated request ID, got %q", got.RequestID)
+			}
+			if got.TraceParent != tt.wantTraceParent {
+				t.Errorf("TraceParent = %q, want %q", got.TraceParent, tt.wantTraceParent)
+			}
+			if rec.Header().Get("X-Request-ID") != got.RequestID {
+				t.Errorf("response X-Request-ID = %q, want %q", rec.Header().Get("X-Request-ID"), got.RequestID)
+			}
+		})
+	}
+}
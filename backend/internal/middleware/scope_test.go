@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+func TestRequireScope(t *testing.T) {
+	handler := RequireScope(ScopeAdminBans, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name       string
+		principal  *Principal
+		client     *APIClient
+		header     string
+		wantStatus int
+	}{
+		{name: "anonymous", wantStatus: http.StatusOK},
+		{name: "unscoped key", principal: &Principal{ID: "partner"}, wantStatus: http.StatusOK},
+		{name: "exact scope", principal: &Principal{Scopes: []string{ScopeAdminBans}}, wantStatus: http.StatusOK},
+		{name: "admin wildcard", principal: &Principal{Scopes: []string{ScopeAdminAll}}, wantStatus: http.StatusOK},
+		{name: "missing scope", principal: &Principal{Scopes: []string{ScopeAnalyzeWrite, ScopeHistoryRead}}, wantStatus: http.StatusForbidden},
+		{name: "member session", principal: &Principal{Scopes: SessionScopes(domain.RoleMember)}, wantStatus: http.StatusForbidden},
+		{name: "admin session", principal: &Principal{Scopes: SessionScopes(domain.RoleAdmin)}, wantStatus: http.StatusOK},
+		{name: "scoped API client", client: &APIClient{Name: "dashboard", Scopes: []string{ScopeHistoryRead}}, wantStatus: http.StatusForbidden},
+		{
+			name:       "every credential must grant",
+			principal:  &Principal{Scopes: []string{ScopeAdminAll}},
+			client:     &APIClient{Name: "dashboard", Scopes: []string{ScopeHistoryRead}},
+			wantStatus: http.StatusForbidden,
+		},
+		{name: "downgraded shared key", principal: &Principal{Scopes: []string{ScopeAdminAll}}, header: "history:read", wantStatus: http.StatusForbidden},
+		{name: "downgrade keeps requested scope", principal: &Principal{Scopes: []string{ScopeAdminAll}}, header: "history:read, admin:bans", wantStatus: http.StatusOK},
+		{name: "downgrade cannot widen", principal: &Principal{Scopes: []string{ScopeHistoryRead}}, header: "admin:*", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/bans", nil)
+			ctx := req.Context()
+			if tt.principal != nil {
+				ctx = ContextWithPrincipal(ctx, tt.principal)
+			}
+			if tt.client != nil {
+				ctx = ContextWithAPIClient(ctx, tt.client)
+			}
+			req = req.WithContext(ctx)
+			if tt.header != "" {
+				req.Header.Set(HeaderScope, tt.header)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("RequireScope() status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusForbidden && !strings.Contains(rec.Header().Get("WWW-Authenticate"), "insufficient_scope") {
+				t.Errorf("WWW-Authenticate = %q, want insufficient_scope", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestValidateScopes(t *testing.T) {
+	if err := ValidateScopes([]string{ScopeAnalyzeWrite, ScopeAdminAll, ScopeWebhooksManage}); err != nil {
+		t.Errorf("ValidateScopes() error = %v", err)
+	}
+	if err := ValidateScopes([]string{"history:write"}); err == nil {
+		t.Error("ValidateScopes() accepted an unknown scope")
+	}
+	if _, err := NewAPIClients([]APIClient{{APIKey: "k", Name: "bad", Scopes: []string{"admin"}}}); err == nil {
+		t.Error("NewAPIClients() accepted an unknown scope")
+	}
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Internal component roles. Each has a least-privilege default scope set.
+const (
+	ServiceRoleWorker    = "worker"    // leases and runs background jobs
+	ServiceRoleScheduler = "scheduler" // starts periodic rescoring, reports and evaluations
+	ServiceRoleMigrator  = "migrator"  // imports history during maintenance windows
+)
+
+// AuthServiceAccount is the Principal.Method of internal components.
+const AuthServiceAccount = "service_account"
+
+var serviceRoleScopes = map[string][]string{
+	ServiceRoleWorker:    {ScopeInternalJobs},
+	ServiceRoleScheduler: {ScopeAdminRescore, ScopeAdminReports, ScopeAdminEvaluations},
+	ServiceRoleMigrator:  {ScopeAdminImport, ScopeAdminMaintenance},
+}
+
+// ServiceAccount is the identity of an internal component. It
+// authenticates with a bearer token and gets only its scopes, never the
+// admin token's blanket access.
+type ServiceAccount struct {
+	Name   string   `json:"name"`
+	Role   string   `json:"role"`
+	Token  string   `json:"token"`            // may reference an environment variable as ${NAME}
+	Scopes []string `json:"scopes,omitempty"` // replaces the role's defaults; admin:* is refused
+}
+
+// ServiceAccounts resolves bearer tokens to service accounts.
+type ServiceAccounts struct {
+	byToken map[string]*ServiceAccount
+}
+
+// NewServiceAccounts creates a registry, filling in each role's default
+// scopes.
+func NewServiceAccounts(accounts []ServiceAccount) (*ServiceAccounts, error) {
+	r := &ServiceAccounts{byToken: make(map[string]*ServiceAccount)}
+	names := make(map[string]bool)
+	for i := range accounts {
+		a := accounts[i]
+		a.Token = os.ExpandEnv(a.Token)
+		defaults, ok := serviceRoleScopes[a.Role]
+		switch {
+		case a.Name == "" || a.Token == "":
+			return nil, fmt.Errorf("service account %q requires name and token", a.Name)
+		case !ok:
+			return nil, fmt.Errorf("service account %q: unknown role %q", a.Name, a.Role)
+		case names[a.Name]:
+			return nil, fmt.Errorf("service account %q is defined twice", a.Name)
+		case r.byToken[a.Token] != nil:
+			return nil, fmt.Errorf("service account %q reuses another account's token", a.Name)
+		}
+		if a.Scopes == nil {
+			a.Scopes = defaults
+		}
+		if err := ValidateScopes(a.Scopes); err != nil {
+			return nil, fmt.Errorf("service account %q: %w", a.Name, err)
+		}
+		for _, scope := range a.Scopes {
+			if strings.HasSuffix(scope, ":*") {
+				return nil, fmt.Errorf("service account %q: wildcard scope %q is not allowed", a.Name, scope)
+			}
+		}
+		names[a.Name] = true
+		r.byToken[a.Token] = &a
+	}
+	return r, nil
+}
+
+// LoadServiceAccounts reads a JSON array of ServiceAccount from a file.
+func LoadServiceAccounts(path string) (*ServiceAccounts, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service accounts: %w", err)
+	}
+	var accounts []ServiceAccount
+	if err := json.Unmarshal(data, &accounts); err != nil {
+		return nil, fmt.Errorf("failed to parse service accounts: %w", err)
+	}
+	return NewServiceAccounts(accounts)
+}
+
+// Len returns the number of service accounts.
+func (r *ServiceAccounts) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.byToken)
+}
+
+// HasRole reports whether any account has role.
+func (r *ServiceAccounts) HasRole(role string) bool {
+	if r == nil {
+		return false
+	}
+	for _, a := range r.byToken {
+		if a.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware authenticates service account bearer tokens as principals
+// named "service:<name>". Other requests pass through untouched.
+func (r *ServiceAccounts) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, ok := PrincipalFromContext(req.Context()); r == nil || ok {
+			next.ServeHTTP(w, req)
+			return
+		}
+		token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if a, found := r.byToken[token]; ok && found {
+			req = req.WithContext(ContextWithPrincipal(req.Context(), &Principal{
+				ID:     "service:" + a.Name,
+				Method: AuthServiceAccount,
+				Role:   a.Role,
+				Scopes: a.Scopes,
+			}))
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// IsServiceAccount reports whether p is an internal component. Handlers
+// behind a scoped route may trust it: RequireScope has already checked
+// its scopes.
+func IsServiceAccount(p *Principal) bool {
+	return p != nil && p.Method == AuthServiceAccount
+}
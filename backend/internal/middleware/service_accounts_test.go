@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewServiceAccounts(t *testing.T) {
+	t.Setenv("WORKER_SECRET", "w-secret")
+	tests := []struct {
+		name     string
+		accounts []ServiceAccount
+		wantErr  bool
+	}{
+		{"role defaults", []ServiceAccount{{Name: "worker", Role: ServiceRoleWorker, Token: "${WORKER_SECRET}"}}, false},
+		{"explicit scopes", []ServiceAccount{{Name: "cron", Role: ServiceRoleScheduler, Token: "t", Scopes: []string{ScopeAdminReports}}}, false},
+		{"unknown role", []ServiceAccount{{Name: "x", Role: "superuser", Token: "t"}}, true},
+		{"missing token", []ServiceAccount{{Name: "x", Role: ServiceRoleWorker, Token: "${UNSET_SECRET}"}}, true},
+		{"wildcard scope", []ServiceAccount{{Name: "x", Role: ServiceRoleMigrator, Token: "t", Scopes: []string{ScopeAdminAll}}}, true},
+		{"unknown scope", []ServiceAccount{{Name: "x", Role: ServiceRoleMigrator, Token: "t", Scopes: []string{"admin:everything"}}}, true},
+		{"duplicate name", []ServiceAccount{{Name: "x", Role: ServiceRoleWorker, Token: "a"}, {Name: "x", Role: ServiceRoleWorker, Token: "b"}}, true},
+		{"shared token", []ServiceAccount{{Name: "x", Role: ServiceRoleWorker, Token: "a"}, {Name: "y", Role: ServiceRoleMigrator, Token: "a"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewServiceAccounts(tt.accounts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewServiceAccounts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceAccountsEnforceScopes(t *testing.T) {
+	accounts, err := NewServiceAccounts([]ServiceAccount{
+		{Name: "worker", Role: ServiceRoleWorker, Token: "worker-token"},
+		{Name: "migrator", Role: ServiceRoleMigrator, Token: "migrator-token"},
+	})
+	if err != nil {
+		t.Fatalf("NewServiceAccounts() error = %v", err)
+	}
+	if !accounts.HasRole(ServiceRoleWorker) || accounts.HasRole(ServiceRoleScheduler) {
+		t.Error("HasRole() does not match the configured accounts")
+	}
+
+	var seen *Principal
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = PrincipalFromContext(r.Context())
+	})
+	mux := http.NewServeMux()
+	mux.Handle("/jobs", RequireScope(ScopeInternalJobs, ok))
+	mux.Handle("/import", RequireScope(ScopeAdminImport, ok))
+	mux.Handle("/rescore", RequireScope(ScopeAdminRescore, ok))
+	h := accounts.Middleware(mux)
+
+	tests := []struct {
+		token, path string
+		want        int
+		wantID      string
+	}{
+		{"worker-token", "/jobs", http.StatusOK, "service:worker"},
+		{"worker-token", "/import", http.StatusForbidden, ""},
+		{"migrator-token", "/import", http.StatusOK, "service:migrator"},
+		{"migrator-token", "/rescore", http.StatusForbidden, ""},
+		{"unknown-token", "/jobs", http.StatusOK, ""}, // anonymous; the handler checks the token
+	}
+	for _, tt := range tests {
+		seen = nil
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s %s: status = %d, want %d", tt.token, tt.path, rec.Code, tt.want)
+			continue
+		}
+		var id string
+		if seen != nil {
+			id = seen.ID
+			if !IsServiceAccount(seen) {
+				t.Errorf("%s: principal method = %q", tt.token, seen.Method)
+			}
+		}
+		if id != tt.wantID {
+			t.Errorf("%s %s: principal = %q, want %q", tt.token, tt.path, id, tt.wantID)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// SessionCookie carries the session token for browser clients.
+const SessionCookie = "fn_session"
+
+// SessionAuth attaches the caller of a valid single sign-on session to
+// the request. The token is read from an "Authorization: Bearer" header
+// or the session cookie. Requests without a valid session pass through
+// unauthenticated, since bearer tokens are also used for other
+// credentials such as the admin token.
+func SessionAuth(sessions *service.SessionTokens, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := PrincipalFromContext(r.Context()); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == r.Header.Get("Authorization") || token == "" {
+			if cookie, err := r.Cookie(SessionCookie); err == nil {
+				token = cookie.Value
+			}
+		}
+		if token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		session, err := sessions.Verify(token)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := ContextWithPrincipal(r.Context(), &Principal{
+			ID:     session.UserID,
+			Method: AuthSession,
+			Plan:   session.Plan,
+			Role:   session.Role,
+			OrgID:  session.OrgID,
+			Scopes: SessionScopes(session.Role),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
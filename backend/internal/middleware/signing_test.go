@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestSigner_Middleware(t *testing.T) {
+	key := SigningKey{KeyID: "partner", Secret: "s3cret", Algorithm: "hmac-sha512"}
+	signer, err := NewRequestSigner([]SigningKey{key}, time.Minute)
+	if err != nil {
+		t.Fatalf("NewRequestSigner() error = %v", err)
+	}
+
+	var gotPrincipal *Principal
+	handler := signer.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPrincipal, _ = PrincipalFromContext(r.Context())
+	}))
+
+	body := `{"type":"text","content":"hello"}`
+	newRequest := func(ts int64, secret string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/api/analyze?x=1", strings.NewReader(body))
+		k := key
+		k.Secret = secret
+		req.Header.Set(HeaderSignatureKeyID, key.KeyID)
+		req.Header.Set(HeaderSignatureTimestamp, strconv.FormatInt(ts, 10))
+		req.Header.Set(HeaderSignature, hex.EncodeToString(Sign(k, http.MethodPost, "/api/analyze?x=1", ts, []byte(body))))
+		return req
+	}
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+	}{
+		{name: "valid signature", req: newRequest(time.Now().Unix(), "s3cret"), wantStatus: http.StatusOK},
+		{name: "wrong secret", req: newRequest(time.Now().Unix(), "nope"), wantStatus: http.StatusUnauthorized},
+		{name: "stale timestamp", req: newRequest(time.Now().Add(-time.Hour).Unix(), "s3cret"), wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPrincipal = nil
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, tt.req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Middleware() status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && (gotPrincipal == nil || gotPrincipal.ID != "partner") {
+				t.Errorf("Middleware() principal = %v, want partner", gotPrincipal)
+			}
+		})
+	}
+}
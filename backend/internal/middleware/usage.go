@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/service"
+)
+
+// UsageCaller identifies the caller usage is recorded for: the
+// authenticated principal, else the registered API client. Anonymous
+// requests have no caller.
+func UsageCaller(r *http.Request) string {
+	if p, ok := PrincipalFromContext(r.Context()); ok {
+		return p.Method + ":" + p.ID
+	}
+	if c, ok := APIClientFromContext(r.Context()); ok {
+		return "client:" + c.Name
+	}
+	return ""
+}
+
+// QuotaSubject identifies who a request's scrapes and inferences are
+// charged to, and on which plan: an organization member's organization,
+// else the authenticated principal, else a registered API key with a plan.
+// Other requests are not metered and get an empty subject.
+func QuotaSubject(r *http.Request) (subject, plan string) {
+	if p, ok := PrincipalFromContext(r.Context()); ok {
+		if p.OrgID != "" {
+			return "org:" + p.OrgID, p.Plan
+		}
+		return p.Method + ":" + p.ID, p.Plan
+	}
+	if c, ok := APIClientFromContext(r.Context()); ok && c.Plan != "" {
+		return "client:" + c.Name, c.Plan
+	}
+	return "", ""
+}
+
+// RecordUsage counts every identified caller's requests, statuses and
+// latencies in tracker.
+func RecordUsage(tracker *service.UsageTracker, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller := UsageCaller(r)
+		if caller == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		tracker.Record(caller, r.Method, r.URL.Path, rec.status, time.Since(start))
+	})
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// AnalysisTemplateRepository defines the interface for analysis template storage
+type AnalysisTemplateRepository interface {
+	// Save stores a template, replacing any existing one with the same ID
+	Save(ctx context.Context, template *domain.AnalysisTemplate) error
+	GetByID(ctx context.Context, id string) (*domain.AnalysisTemplate, error)
+	// ListByUser returns the user-scoped templates a user owns
+	ListByUser(ctx context.Context, userID string) ([]*domain.AnalysisTemplate, error)
+	// ListByOrg returns an organization's shared templates
+	ListByOrg(ctx context.Context, orgID string) ([]*domain.AnalysisTemplate, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// CorpusRepository defines the interface for research corpus storage. Each
+// manifest is stored with its JSONL export.
+type CorpusRepository interface {
+	Save(ctx context.Context, manifest *domain.CorpusManifest, data []byte) error
+	GetByID(ctx context.Context, id string) (*domain.CorpusManifest, error)
+	Data(ctx context.Context, id string) ([]byte, error)
+	// List returns every manifest, newest first
+	List(ctx context.Context) ([]*domain.CorpusManifest, error)
+}
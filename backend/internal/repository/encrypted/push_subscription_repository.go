@@ -0,0 +1,74 @@
+package encrypted
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Naman30903/Final-Year-Project/internal/crypto"
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// PushSubscriptionRepository encrypts subscriptions' message encryption
+// keys. The endpoint stays in plaintext because subscriptions are looked
+// up by it.
+type PushSubscriptionRepository struct {
+	next    repository.PushSubscriptionRepository
+	keyring *crypto.Keyring
+}
+
+// NewPushSubscriptionRepository wraps next
+func NewPushSubscriptionRepository(next repository.PushSubscriptionRepository, keyring *crypto.Keyring) *PushSubscriptionRepository {
+	return &PushSubscriptionRepository{next: next, keyring: keyring}
+}
+
+func (r *PushSubscriptionRepository) Save(ctx context.Context, sub *domain.PushSubscription) error {
+	sealed := *sub
+	var err error
+	if sealed.Keys.P256dh, err = r.keyring.Encrypt(sub.Keys.P256dh); err == nil {
+		sealed.Keys.Auth, err = r.keyring.Encrypt(sub.Keys.Auth)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encrypt push subscription: %w", err)
+	}
+	return r.next.Save(ctx, &sealed)
+}
+
+func (r *PushSubscriptionRepository) ListByUser(ctx context.Context, userID string) ([]*domain.PushSubscription, error) {
+	subs, err := r.next.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	opened := make([]*domain.PushSubscription, len(subs))
+	for i, stored := range subs {
+		if opened[i], err = r.open(stored); err != nil {
+			return nil, err
+		}
+	}
+	return opened, nil
+}
+
+func (r *PushSubscriptionRepository) GetByEndpoint(ctx context.Context, endpoint string) (*domain.PushSubscription, error) {
+	stored, err := r.next.GetByEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return r.open(stored)
+}
+
+// open returns a copy of stored with its keys decrypted
+func (r *PushSubscriptionRepository) open(stored *domain.PushSubscription) (*domain.PushSubscription, error) {
+	sub := *stored
+	var err error
+	if sub.Keys.P256dh, err = r.keyring.Decrypt(stored.Keys.P256dh); err == nil {
+		sub.Keys.Auth, err = r.keyring.Decrypt(stored.Keys.Auth)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt push subscription: %w", err)
+	}
+	return &sub, nil
+}
+
+func (r *PushSubscriptionRepository) DeleteByEndpoint(ctx context.Context, endpoint string) error {
+	return r.next.DeleteByEndpoint(ctx, endpoint)
+}
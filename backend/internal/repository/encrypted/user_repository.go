@@ -0,0 +1,134 @@
+// Package encrypted wraps repositories so sensitive fields are stored
+// encrypted (see internal/crypto) and decrypted on the way out. Callers
+// see plaintext; the wrapped store only ever holds ciphertext.
+package encrypted
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Naman30903/Final-Year-Project/internal/crypto"
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// rotateBatch is how many users Rotate loads at a time
+const rotateBatch = 500
+
+// UserRepository encrypts users' email addresses.
+type UserRepository struct {
+	next    repository.UserRepository
+	keyring *crypto.Keyring
+}
+
+// NewUserRepository wraps next
+func NewUserRepository(next repository.UserRepository, keyring *crypto.Keyring) *UserRepository {
+	return &UserRepository{next: next, keyring: keyring}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	sealed, err := r.seal(user)
+	if err != nil {
+		return err
+	}
+	if err := r.next.Create(ctx, sealed); err != nil {
+		return err
+	}
+	user.CreatedAt, user.UpdatedAt = sealed.CreatedAt, sealed.UpdatedAt
+	return nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	user, err := r.next.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.open(user)
+}
+
+func (r *UserRepository) GetByExternalID(ctx context.Context, externalID string) (*domain.User, error) {
+	user, err := r.next.GetByExternalID(ctx, externalID)
+	if err != nil {
+		return nil, err
+	}
+	return r.open(user)
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	sealed, err := r.seal(user)
+	if err != nil {
+		return err
+	}
+	if err := r.next.Update(ctx, sealed); err != nil {
+		return err
+	}
+	user.UpdatedAt = sealed.UpdatedAt
+	return nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	return r.next.Delete(ctx, id)
+}
+
+func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*domain.User, error) {
+	users, err := r.next.List(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	opened := make([]*domain.User, len(users))
+	for i, user := range users {
+		if opened[i], err = r.open(user); err != nil {
+			return nil, err
+		}
+	}
+	return opened, nil
+}
+
+// Rotate re-encrypts every email that is plaintext or sealed with an old
+// master key, so the old key can be retired. It returns how many users
+// were rewritten.
+func (r *UserRepository) Rotate(ctx context.Context) (int, error) {
+	rotated := 0
+	for offset := 0; ; offset += rotateBatch {
+		users, err := r.next.List(ctx, rotateBatch, offset)
+		if err != nil {
+			return rotated, err
+		}
+		for _, stored := range users {
+			if !r.keyring.NeedsRotation(stored.Email) {
+				continue
+			}
+			user, err := r.open(stored)
+			if err != nil {
+				return rotated, err
+			}
+			if err := r.Update(ctx, user); err != nil {
+				return rotated, err
+			}
+			rotated++
+		}
+		if len(users) < rotateBatch {
+			return rotated, nil
+		}
+	}
+}
+
+// seal returns a copy of user with its email encrypted.
+func (r *UserRepository) seal(user *domain.User) (*domain.User, error) {
+	sealed := *user
+	var err error
+	if sealed.Email, err = r.keyring.Encrypt(user.Email); err != nil {
+		return nil, fmt.Errorf("failed to encrypt user %s: %w", user.ID, err)
+	}
+	return &sealed, nil
+}
+
+// open returns a copy of a stored user with its email decrypted.
+func (r *UserRepository) open(stored *domain.User) (*domain.User, error) {
+	user := *stored
+	var err error
+	if user.Email, err = r.keyring.Decrypt(stored.Email); err != nil {
+		return nil, fmt.Errorf("failed to decrypt user %s: %w", stored.ID, err)
+	}
+	return &user, nil
+}
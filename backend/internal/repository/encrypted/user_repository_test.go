@@ -0,0 +1,79 @@
+package encrypted
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/crypto"
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func testKeyring(t *testing.T, current string, ids ...string) *crypto.Keyring {
+	t.Helper()
+	keys := make(map[string][]byte)
+	for i, id := range ids {
+		keys[id] = bytes.Repeat([]byte{byte(i + 1)}, crypto.MasterKeySize)
+	}
+	keyring, err := crypto.NewKeyring(current, keys)
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	return keyring
+}
+
+func TestUserRepositoryEncryptsEmail(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewUserRepository()
+	repo := NewUserRepository(store, testKeyring(t, "k1", "k1"))
+
+	user := &domain.User{ID: "1", Email: "reader@example.com", Name: "Reader"}
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if user.Email != "reader@example.com" {
+		t.Errorf("Create() changed the caller's email to %q", user.Email)
+	}
+
+	stored, _ := store.GetByID(ctx, "1")
+	if !crypto.IsEncrypted(stored.Email) || strings.Contains(stored.Email, "reader") {
+		t.Errorf("stored email = %q, want ciphertext", stored.Email)
+	}
+
+	got, err := repo.GetByID(ctx, "1")
+	if err != nil || got.Email != "reader@example.com" {
+		t.Errorf("GetByID() = %+v, %v", got, err)
+	}
+	users, err := repo.List(ctx, 10, 0)
+	if err != nil || len(users) != 1 || users[0].Email != "reader@example.com" {
+		t.Errorf("List() = %+v, %v", users, err)
+	}
+}
+
+func TestUserRepositoryRotate(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewUserRepository()
+	_ = NewUserRepository(store, testKeyring(t, "k1", "k1")).Create(ctx, &domain.User{ID: "1", Email: "old@example.com"})
+	_ = store.Create(ctx, &domain.User{ID: "2", Email: "plain@example.com"})
+
+	rotated := testKeyring(t, "k2", "k1", "k2")
+	repo := NewUserRepository(store, rotated)
+	n, err := repo.Rotate(ctx)
+	if err != nil || n != 2 {
+		t.Fatalf("Rotate() = %d, %v; want 2", n, err)
+	}
+	for id, email := range map[string]string{"1": "old@example.com", "2": "plain@example.com"} {
+		stored, _ := store.GetByID(ctx, id)
+		if rotated.NeedsRotation(stored.Email) {
+			t.Errorf("user %s still stored as %q", id, stored.Email)
+		}
+		if got, _ := repo.GetByID(ctx, id); got.Email != email {
+			t.Errorf("user %s email = %q, want %q", id, got.Email, email)
+		}
+	}
+	if n, _ := repo.Rotate(ctx); n != 0 {
+		t.Errorf("second Rotate() = %d, want 0", n)
+	}
+}
@@ -0,0 +1,79 @@
+package encrypted
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Naman30903/Final-Year-Project/internal/crypto"
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// WebhookRepository encrypts subscriptions' signing secrets.
+type WebhookRepository struct {
+	next    repository.WebhookRepository
+	keyring *crypto.Keyring
+}
+
+// NewWebhookRepository wraps next
+func NewWebhookRepository(next repository.WebhookRepository, keyring *crypto.Keyring) *WebhookRepository {
+	return &WebhookRepository{next: next, keyring: keyring}
+}
+
+func (r *WebhookRepository) Save(ctx context.Context, sub *domain.WebhookSubscription) error {
+	sealed := *sub
+	var err error
+	if sealed.Secret, err = r.keyring.Encrypt(sub.Secret); err != nil {
+		return fmt.Errorf("failed to encrypt webhook subscription: %w", err)
+	}
+	return r.next.Save(ctx, &sealed)
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	sub, err := r.next.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.open(sub)
+}
+
+func (r *WebhookRepository) ListByOwner(ctx context.Context, ownerID string) ([]*domain.WebhookSubscription, error) {
+	subs, err := r.next.ListByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return r.openAll(subs)
+}
+
+func (r *WebhookRepository) List(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	subs, err := r.next.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.openAll(subs)
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	return r.next.Delete(ctx, id)
+}
+
+func (r *WebhookRepository) open(stored *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	sub := *stored
+	var err error
+	if sub.Secret, err = r.keyring.Decrypt(stored.Secret); err != nil {
+		return nil, fmt.Errorf("failed to decrypt webhook subscription: %w", err)
+	}
+	return &sub, nil
+}
+
+func (r *WebhookRepository) openAll(subs []*domain.WebhookSubscription) ([]*domain.WebhookSubscription, error) {
+	opened := make([]*domain.WebhookSubscription, len(subs))
+	for i, stored := range subs {
+		sub, err := r.open(stored)
+		if err != nil {
+			return nil, err
+		}
+		opened[i] = sub
+	}
+	return opened, nil
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// EvaluationRepository defines the interface for evaluation run storage
+type EvaluationRepository interface {
+	// Save stores a run, replacing any existing one with the same ID
+	Save(ctx context.Context, run *domain.EvaluationRun) error
+	GetByID(ctx context.Context, id string) (*domain.EvaluationRun, error)
+	// List returns every run, oldest first
+	List(ctx context.Context) ([]*domain.EvaluationRun, error)
+}
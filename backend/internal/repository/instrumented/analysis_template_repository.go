@@ -0,0 +1,49 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// AnalysisTemplateRepository instruments a repository.AnalysisTemplateRepository
+type AnalysisTemplateRepository struct {
+	next     repository.AnalysisTemplateRepository
+	recorder *Recorder
+}
+
+// NewAnalysisTemplateRepository wraps next
+func NewAnalysisTemplateRepository(next repository.AnalysisTemplateRepository, recorder *Recorder) *AnalysisTemplateRepository {
+	return &AnalysisTemplateRepository{next: next, recorder: recorder}
+}
+
+func (r *AnalysisTemplateRepository) Save(ctx context.Context, template *domain.AnalysisTemplate) error {
+	return exec(ctx, r.recorder, "templates.Save", func(ctx context.Context) error {
+		return r.next.Save(ctx, template)
+	}, 1)
+}
+
+func (r *AnalysisTemplateRepository) GetByID(ctx context.Context, id string) (*domain.AnalysisTemplate, error) {
+	return call(ctx, r.recorder, "templates.GetByID", func(ctx context.Context) (*domain.AnalysisTemplate, error) {
+		return r.next.GetByID(ctx, id)
+	}, one)
+}
+
+func (r *AnalysisTemplateRepository) ListByUser(ctx context.Context, userID string) ([]*domain.AnalysisTemplate, error) {
+	return call(ctx, r.recorder, "templates.ListByUser", func(ctx context.Context) ([]*domain.AnalysisTemplate, error) {
+		return r.next.ListByUser(ctx, userID)
+	}, count)
+}
+
+func (r *AnalysisTemplateRepository) ListByOrg(ctx context.Context, orgID string) ([]*domain.AnalysisTemplate, error) {
+	return call(ctx, r.recorder, "templates.ListByOrg", func(ctx context.Context) ([]*domain.AnalysisTemplate, error) {
+		return r.next.ListByOrg(ctx, orgID)
+	}, count)
+}
+
+func (r *AnalysisTemplateRepository) Delete(ctx context.Context, id string) error {
+	return exec(ctx, r.recorder, "templates.Delete", func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	}, 1)
+}
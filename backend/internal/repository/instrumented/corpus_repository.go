@@ -0,0 +1,43 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// CorpusRepository instruments a repository.CorpusRepository
+type CorpusRepository struct {
+	next     repository.CorpusRepository
+	recorder *Recorder
+}
+
+// NewCorpusRepository wraps next
+func NewCorpusRepository(next repository.CorpusRepository, recorder *Recorder) *CorpusRepository {
+	return &CorpusRepository{next: next, recorder: recorder}
+}
+
+func (r *CorpusRepository) Save(ctx context.Context, manifest *domain.CorpusManifest, data []byte) error {
+	return exec(ctx, r.recorder, "corpora.Save", func(ctx context.Context) error {
+		return r.next.Save(ctx, manifest, data)
+	}, 1)
+}
+
+func (r *CorpusRepository) GetByID(ctx context.Context, id string) (*domain.CorpusManifest, error) {
+	return call(ctx, r.recorder, "corpora.GetByID", func(ctx context.Context) (*domain.CorpusManifest, error) {
+		return r.next.GetByID(ctx, id)
+	}, one)
+}
+
+func (r *CorpusRepository) Data(ctx context.Context, id string) ([]byte, error) {
+	return call(ctx, r.recorder, "corpora.Data", func(ctx context.Context) ([]byte, error) {
+		return r.next.Data(ctx, id)
+	}, one)
+}
+
+func (r *CorpusRepository) List(ctx context.Context) ([]*domain.CorpusManifest, error) {
+	return call(ctx, r.recorder, "corpora.List", func(ctx context.Context) ([]*domain.CorpusManifest, error) {
+		return r.next.List(ctx)
+	}, count)
+}
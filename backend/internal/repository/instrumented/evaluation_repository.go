@@ -0,0 +1,37 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// EvaluationRepository instruments a repository.EvaluationRepository
+type EvaluationRepository struct {
+	next     repository.EvaluationRepository
+	recorder *Recorder
+}
+
+// NewEvaluationRepository wraps next
+func NewEvaluationRepository(next repository.EvaluationRepository, recorder *Recorder) *EvaluationRepository {
+	return &EvaluationRepository{next: next, recorder: recorder}
+}
+
+func (r *EvaluationRepository) Save(ctx context.Context, run *domain.EvaluationRun) error {
+	return exec(ctx, r.recorder, "evaluations.Save", func(ctx context.Context) error {
+		return r.next.Save(ctx, run)
+	}, 1)
+}
+
+func (r *EvaluationRepository) GetByID(ctx context.Context, id string) (*domain.EvaluationRun, error) {
+	return call(ctx, r.recorder, "evaluations.GetByID", func(ctx context.Context) (*domain.EvaluationRun, error) {
+		return r.next.GetByID(ctx, id)
+	}, one)
+}
+
+func (r *EvaluationRepository) List(ctx context.Context) ([]*domain.EvaluationRun, error) {
+	return call(ctx, r.recorder, "evaluations.List", func(ctx context.Context) ([]*domain.EvaluationRun, error) {
+		return r.next.List(ctx)
+	}, count)
+}
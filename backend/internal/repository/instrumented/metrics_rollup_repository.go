@@ -0,0 +1,38 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// MetricsRollupRepository instruments a repository.MetricsRollupRepository
+type MetricsRollupRepository struct {
+	next     repository.MetricsRollupRepository
+	recorder *Recorder
+}
+
+// NewMetricsRollupRepository wraps next
+func NewMetricsRollupRepository(next repository.MetricsRollupRepository, recorder *Recorder) *MetricsRollupRepository {
+	return &MetricsRollupRepository{next: next, recorder: recorder}
+}
+
+func (r *MetricsRollupRepository) Save(ctx context.Context, rollup *domain.MetricsRollup) error {
+	return exec(ctx, r.recorder, "rollups.Save", func(ctx context.Context) error {
+		return r.next.Save(ctx, rollup)
+	}, 1)
+}
+
+func (r *MetricsRollupRepository) List(ctx context.Context, resolution string, since, until time.Time) ([]*domain.MetricsRollup, error) {
+	return call(ctx, r.recorder, "rollups.List", func(ctx context.Context) ([]*domain.MetricsRollup, error) {
+		return r.next.List(ctx, resolution, since, until)
+	}, count)
+}
+
+func (r *MetricsRollupRepository) DeleteBefore(ctx context.Context, resolution string, cutoff time.Time) (int, error) {
+	return call(ctx, r.recorder, "rollups.DeleteBefore", func(ctx context.Context) (int, error) {
+		return r.next.DeleteBefore(ctx, resolution, cutoff)
+	}, func(n int) int { return n })
+}
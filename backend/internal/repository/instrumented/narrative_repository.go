@@ -0,0 +1,49 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// NarrativeRepository instruments a repository.NarrativeRepository
+type NarrativeRepository struct {
+	next     repository.NarrativeRepository
+	recorder *Recorder
+}
+
+// NewNarrativeRepository wraps next
+func NewNarrativeRepository(next repository.NarrativeRepository, recorder *Recorder) *NarrativeRepository {
+	return &NarrativeRepository{next: next, recorder: recorder}
+}
+
+func (r *NarrativeRepository) Save(ctx context.Context, narrative *domain.Narrative) error {
+	return exec(ctx, r.recorder, "narratives.Save", func(ctx context.Context) error {
+		return r.next.Save(ctx, narrative)
+	}, 1)
+}
+
+func (r *NarrativeRepository) GetByID(ctx context.Context, id string) (*domain.Narrative, error) {
+	return call(ctx, r.recorder, "narratives.GetByID", func(ctx context.Context) (*domain.Narrative, error) {
+		return r.next.GetByID(ctx, id)
+	}, one)
+}
+
+func (r *NarrativeRepository) ListByOrg(ctx context.Context, orgID string) ([]*domain.Narrative, error) {
+	return call(ctx, r.recorder, "narratives.ListByOrg", func(ctx context.Context) ([]*domain.Narrative, error) {
+		return r.next.ListByOrg(ctx, orgID)
+	}, count)
+}
+
+func (r *NarrativeRepository) List(ctx context.Context) ([]*domain.Narrative, error) {
+	return call(ctx, r.recorder, "narratives.List", func(ctx context.Context) ([]*domain.Narrative, error) {
+		return r.next.List(ctx)
+	}, count)
+}
+
+func (r *NarrativeRepository) Delete(ctx context.Context, id string) error {
+	return exec(ctx, r.recorder, "narratives.Delete", func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	}, 1)
+}
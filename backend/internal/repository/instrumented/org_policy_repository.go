@@ -0,0 +1,43 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// OrgDomainRuleRepository instruments a repository.OrgDomainRuleRepository
+type OrgDomainRuleRepository struct {
+	next     repository.OrgDomainRuleRepository
+	recorder *Recorder
+}
+
+// NewOrgDomainRuleRepository wraps next
+func NewOrgDomainRuleRepository(next repository.OrgDomainRuleRepository, recorder *Recorder) *OrgDomainRuleRepository {
+	return &OrgDomainRuleRepository{next: next, recorder: recorder}
+}
+
+func (r *OrgDomainRuleRepository) Save(ctx context.Context, rule *domain.OrgDomainRule) error {
+	return exec(ctx, r.recorder, "org_domain_rules.Save", func(ctx context.Context) error {
+		return r.next.Save(ctx, rule)
+	}, 1)
+}
+
+func (r *OrgDomainRuleRepository) GetByID(ctx context.Context, id string) (*domain.OrgDomainRule, error) {
+	return call(ctx, r.recorder, "org_domain_rules.GetByID", func(ctx context.Context) (*domain.OrgDomainRule, error) {
+		return r.next.GetByID(ctx, id)
+	}, one)
+}
+
+func (r *OrgDomainRuleRepository) ListByOrg(ctx context.Context, orgID string) ([]*domain.OrgDomainRule, error) {
+	return call(ctx, r.recorder, "org_domain_rules.ListByOrg", func(ctx context.Context) ([]*domain.OrgDomainRule, error) {
+		return r.next.ListByOrg(ctx, orgID)
+	}, count)
+}
+
+func (r *OrgDomainRuleRepository) Delete(ctx context.Context, id string) error {
+	return exec(ctx, r.recorder, "org_domain_rules.Delete", func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	}, 1)
+}
@@ -0,0 +1,113 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// PredictionStore is the prediction storage the API is built on (the
+// service package's NewsRepository).
+type PredictionStore interface {
+	CreatePrediction(prediction *domain.Prediction) error
+	UpdatePrediction(prediction *domain.Prediction) error
+	GetPredictionByID(id string) (*domain.Prediction, error)
+	GetAllPredictions() ([]*domain.Prediction, error)
+	GetPredictionByCanonicalURL(canonicalURL string) (*domain.Prediction, error)
+	Query(ctx context.Context, q domain.PredictionQuery) ([]*domain.Prediction, error)
+	Count(ctx context.Context, q domain.PredictionQuery) (int, error)
+	Iterate(ctx context.Context, q domain.PredictionQuery, fn func(*domain.Prediction) error) error
+	Aggregate(ctx context.Context, q domain.TimeSeriesQuery) ([]domain.TimeSeriesPoint, error)
+	DeletePrediction(id string) error
+	DeleteUnpinnedPrediction(id string) error
+}
+
+// PredictionRepository instruments a PredictionStore. Methods without a
+// context get the timeout from a background context.
+type PredictionRepository struct {
+	next     PredictionStore
+	recorder *Recorder
+}
+
+// NewPredictionRepository wraps next.
+func NewPredictionRepository(next PredictionStore, recorder *Recorder) *PredictionRepository {
+	return &PredictionRepository{next: next, recorder: recorder}
+}
+
+func (r *PredictionRepository) CreatePrediction(prediction *domain.Prediction) error {
+	return exec(context.Background(), r.recorder, "predictions.CreatePrediction", func(context.Context) error {
+		return r.next.CreatePrediction(prediction)
+	}, 1)
+}
+
+func (r *PredictionRepository) UpdatePrediction(prediction *domain.Prediction) error {
+	return exec(context.Background(), r.recorder, "predictions.UpdatePrediction", func(context.Context) error {
+		return r.next.UpdatePrediction(prediction)
+	}, 1)
+}
+
+func (r *PredictionRepository) GetPredictionByID(id string) (*domain.Prediction, error) {
+	return call(context.Background(), r.recorder, "predictions.GetPredictionByID", func(context.Context) (*domain.Prediction, error) {
+		return r.next.GetPredictionByID(id)
+	}, one)
+}
+
+func (r *PredictionRepository) GetAllPredictions() ([]*domain.Prediction, error) {
+	return call(context.Background(), r.recorder, "predictions.GetAllPredictions", func(context.Context) ([]*domain.Prediction, error) {
+		return r.next.GetAllPredictions()
+	}, count)
+}
+
+func (r *PredictionRepository) GetPredictionByCanonicalURL(canonicalURL string) (*domain.Prediction, error) {
+	return call(context.Background(), r.recorder, "predictions.GetPredictionByCanonicalURL", func(context.Context) (*domain.Prediction, error) {
+		return r.next.GetPredictionByCanonicalURL(canonicalURL)
+	}, one)
+}
+
+func (r *PredictionRepository) Query(ctx context.Context, q domain.PredictionQuery) ([]*domain.Prediction, error) {
+	return call(ctx, r.recorder, "predictions.Query", func(ctx context.Context) ([]*domain.Prediction, error) {
+		return r.next.Query(ctx, q)
+	}, count)
+}
+
+func (r *PredictionRepository) Count(ctx context.Context, q domain.PredictionQuery) (int, error) {
+	return call(ctx, r.recorder, "predictions.Count", func(ctx context.Context) (int, error) {
+		return r.next.Count(ctx, q)
+	}, one)
+}
+
+// Iterate is recorded but not timed out: how long it runs depends on how
+// fast fn consumes the rows, such as a client reading a streamed export.
+// The recorded duration leaves out the time spent in fn.
+func (r *PredictionRepository) Iterate(ctx context.Context, q domain.PredictionQuery, fn func(*domain.Prediction) error) error {
+	start := time.Now()
+	var consumer time.Duration
+	rows := 0
+	err := r.next.Iterate(ctx, q, func(p *domain.Prediction) error {
+		rows++
+		fnStart := time.Now()
+		defer func() { consumer += time.Since(fnStart) }()
+		return fn(p)
+	})
+	r.recorder.record("predictions.Iterate", time.Since(start)-consumer, rows, err, false)
+	return err
+}
+
+func (r *PredictionRepository) Aggregate(ctx context.Context, q domain.TimeSeriesQuery) ([]domain.TimeSeriesPoint, error) {
+	return call(ctx, r.recorder, "predictions.Aggregate", func(ctx context.Context) ([]domain.TimeSeriesPoint, error) {
+		return r.next.Aggregate(ctx, q)
+	}, count)
+}
+
+func (r *PredictionRepository) DeletePrediction(id string) error {
+	return exec(context.Background(), r.recorder, "predictions.DeletePrediction", func(context.Context) error {
+		return r.next.DeletePrediction(id)
+	}, 1)
+}
+
+func (r *PredictionRepository) DeleteUnpinnedPrediction(id string) error {
+	return exec(context.Background(), r.recorder, "predictions.DeleteUnpinnedPrediction", func(context.Context) error {
+		return r.next.DeleteUnpinnedPrediction(id)
+	}, 1)
+}
@@ -0,0 +1,37 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// PreferencesRepository instruments a repository.PreferencesRepository
+type PreferencesRepository struct {
+	next     repository.PreferencesRepository
+	recorder *Recorder
+}
+
+// NewPreferencesRepository wraps next
+func NewPreferencesRepository(next repository.PreferencesRepository, recorder *Recorder) *PreferencesRepository {
+	return &PreferencesRepository{next: next, recorder: recorder}
+}
+
+func (r *PreferencesRepository) Get(ctx context.Context, userID string) (*domain.AnalysisDefaults, error) {
+	return call(ctx, r.recorder, "preferences.Get", func(ctx context.Context) (*domain.AnalysisDefaults, error) {
+		return r.next.Get(ctx, userID)
+	}, one)
+}
+
+func (r *PreferencesRepository) Save(ctx context.Context, userID string, defaults *domain.AnalysisDefaults) error {
+	return exec(ctx, r.recorder, "preferences.Save", func(ctx context.Context) error {
+		return r.next.Save(ctx, userID, defaults)
+	}, 1)
+}
+
+func (r *PreferencesRepository) Delete(ctx context.Context, userID string) error {
+	return exec(ctx, r.recorder, "preferences.Delete", func(ctx context.Context) error {
+		return r.next.Delete(ctx, userID)
+	}, 1)
+}
@@ -0,0 +1,43 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// PushSubscriptionRepository instruments a repository.PushSubscriptionRepository
+type PushSubscriptionRepository struct {
+	next     repository.PushSubscriptionRepository
+	recorder *Recorder
+}
+
+// NewPushSubscriptionRepository wraps next
+func NewPushSubscriptionRepository(next repository.PushSubscriptionRepository, recorder *Recorder) *PushSubscriptionRepository {
+	return &PushSubscriptionRepository{next: next, recorder: recorder}
+}
+
+func (r *PushSubscriptionRepository) Save(ctx context.Context, sub *domain.PushSubscription) error {
+	return exec(ctx, r.recorder, "push_subscriptions.Save", func(ctx context.Context) error {
+		return r.next.Save(ctx, sub)
+	}, 1)
+}
+
+func (r *PushSubscriptionRepository) ListByUser(ctx context.Context, userID string) ([]*domain.PushSubscription, error) {
+	return call(ctx, r.recorder, "push_subscriptions.ListByUser", func(ctx context.Context) ([]*domain.PushSubscription, error) {
+		return r.next.ListByUser(ctx, userID)
+	}, count)
+}
+
+func (r *PushSubscriptionRepository) GetByEndpoint(ctx context.Context, endpoint string) (*domain.PushSubscription, error) {
+	return call(ctx, r.recorder, "push_subscriptions.GetByEndpoint", func(ctx context.Context) (*domain.PushSubscription, error) {
+		return r.next.GetByEndpoint(ctx, endpoint)
+	}, one)
+}
+
+func (r *PushSubscriptionRepository) DeleteByEndpoint(ctx context.Context, endpoint string) error {
+	return exec(ctx, r.recorder, "push_subscriptions.DeleteByEndpoint", func(ctx context.Context) error {
+		return r.next.DeleteByEndpoint(ctx, endpoint)
+	}, 1)
+}
@@ -0,0 +1,184 @@
+// Package instrumented wraps repositories with a per-call timeout,
+// slow-call logging and per-method metrics, whatever the backend.
+package instrumented
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// Recorder defaults
+const (
+	DefaultTimeout       = 5 * time.Second
+	DefaultSlowThreshold = 250 * time.Millisecond
+)
+
+// OperationStats are one repository method's counters since startup.
+type OperationStats struct {
+	Operation    string  `json:"operation"` // "<repository>.<method>"
+	Calls        int64   `json:"calls"`
+	Errors       int64   `json:"errors"` // including timeouts
+	Timeouts     int64   `json:"timeouts"`
+	Slow         int64   `json:"slow"`
+	Rows         int64   `json:"rows"`
+	AvgLatencyMS float64 `json:"avg_latency_ms"`
+	MaxLatencyMS float64 `json:"max_latency_ms"`
+}
+
+type operationCounters struct {
+	calls, errors, timeouts, slow, rows int64
+	total, max                          time.Duration
+}
+
+// Recorder is shared by every instrumented repository. Calls that outlive
+// the timeout return domain.ErrRepositoryTimeout; calls slower than the
+// slow threshold are logged with their row counts.
+type Recorder struct {
+	timeout time.Duration
+	slow    time.Duration
+
+	mu         sync.Mutex
+	operations map[string]*operationCounters
+}
+
+// NewRecorder creates a recorder with the default timeout and threshold.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		timeout:    DefaultTimeout,
+		slow:       DefaultSlowThreshold,
+		operations: make(map[string]*operationCounters),
+	}
+}
+
+// WithTimeout sets how long a repository call may take.
+func (r *Recorder) WithTimeout(timeout time.Duration) *Recorder {
+	if timeout > 0 {
+		r.timeout = timeout
+	}
+	return r
+}
+
+// WithSlowThreshold sets the duration above which calls are logged.
+func (r *Recorder) WithSlowThreshold(slow time.Duration) *Recorder {
+	if slow > 0 {
+		r.slow = slow
+	}
+	return r
+}
+
+// Stats returns every operation's counters, sorted by name.
+func (r *Recorder) Stats() []OperationStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make([]OperationStats, 0, len(r.operations))
+	for name, c := range r.operations {
+		s := OperationStats{
+			Operation:    name,
+			Calls:        c.calls,
+			Errors:       c.errors,
+			Timeouts:     c.timeouts,
+			Slow:         c.slow,
+			Rows:         c.rows,
+			MaxLatencyMS: float64(c.max) / float64(time.Millisecond),
+		}
+		if c.calls > 0 {
+			s.AvgLatencyMS = float64(c.total) / float64(time.Millisecond) / float64(c.calls)
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Operation < stats[j].Operation })
+	return stats
+}
+
+// record counts one finished call.
+func (r *Recorder) record(op string, elapsed time.Duration, rows int, err error, timedOut bool) {
+	slow := elapsed >= r.slow
+	r.mu.Lock()
+	c, ok := r.operations[op]
+	if !ok {
+		c = &operationCounters{}
+		r.operations[op] = c
+	}
+	c.calls++
+	c.rows += int64(rows)
+	c.total += elapsed
+	c.max = max(c.max, elapsed)
+	if err != nil {
+		c.errors++
+	}
+	if timedOut {
+		c.timeouts++
+	}
+	if slow {
+		c.slow++
+	}
+	r.mu.Unlock()
+
+	if timedOut {
+		log.Printf("Warning: repository call %s timed out after %s", op, elapsed.Round(time.Millisecond))
+	} else if slow {
+		log.Printf("Warning: slow repository call %s took %s (%d rows)", op, elapsed.Round(time.Millisecond), rows)
+	}
+}
+
+type result[T any] struct {
+	value T
+	err   error
+}
+
+// call runs fn under the recorder's timeout and records it. fn runs on
+// its own goroutine so a backend that ignores ctx cannot hold the caller
+// past the timeout; its late result is dropped. rows counts the rows a
+// result carries.
+func call[T any](ctx context.Context, r *Recorder, op string, fn func(ctx context.Context) (T, error), rows func(T) int) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan result[T], 1)
+	go func() {
+		value, err := fn(ctx)
+		done <- result[T]{value: value, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		n := 0
+		if res.err == nil && rows != nil {
+			n = rows(res.value)
+		}
+		r.record(op, time.Since(start), n, res.err, false)
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero T
+		err := ctx.Err()
+		timedOut := errors.Is(err, context.DeadlineExceeded)
+		if timedOut {
+			err = fmt.Errorf("%w: %s after %s", domain.ErrRepositoryTimeout, op, r.timeout)
+		}
+		r.record(op, time.Since(start), 0, err, timedOut)
+		return zero, err
+	}
+}
+
+// exec is call for methods that return only an error.
+func exec(ctx context.Context, r *Recorder, op string, fn func(ctx context.Context) error, rows int) error {
+	_, err := call(ctx, r, op, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, func(struct{}) int { return rows })
+	return err
+}
+
+// one counts a single row.
+func one[T any](T) int { return 1 }
+
+// count counts a slice's rows.
+func count[T any](values []T) int { return len(values) }
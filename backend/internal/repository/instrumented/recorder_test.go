@@ -0,0 +1,73 @@
+package instrumented
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+// slowStore delays queries to simulate a degraded backend.
+type slowStore struct {
+	*memory.PredictionRepository
+	delay time.Duration
+}
+
+func (s slowStore) Query(ctx context.Context, q domain.PredictionQuery) ([]*domain.Prediction, error) {
+	time.Sleep(s.delay)
+	return s.PredictionRepository.Query(ctx, q)
+}
+
+func TestPredictionRepositoryRecordsCalls(t *testing.T) {
+	recorder := NewRecorder().WithSlowThreshold(20 * time.Millisecond)
+	store := slowStore{PredictionRepository: memory.NewPredictionRepository(), delay: 30 * time.Millisecond}
+	repo := NewPredictionRepository(store, recorder)
+
+	for _, id := range []string{"a", "b"} {
+		if err := repo.CreatePrediction(&domain.Prediction{ID: id, CreatedAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := repo.GetPredictionByID("missing"); !errors.Is(err, domain.ErrPredictionNotFound) {
+		t.Errorf("GetPredictionByID(missing) error = %v, want ErrPredictionNotFound", err)
+	}
+	got, err := repo.Query(context.Background(), domain.PredictionQuery{})
+	if err != nil || len(got) != 2 {
+		t.Fatalf("Query() = %d predictions, %v", len(got), err)
+	}
+
+	stats := map[string]OperationStats{}
+	for _, s := range recorder.Stats() {
+		stats[s.Operation] = s
+	}
+	if s := stats["predictions.CreatePrediction"]; s.Calls != 2 || s.Rows != 2 || s.Slow != 0 {
+		t.Errorf("CreatePrediction stats = %+v", s)
+	}
+	if s := stats["predictions.GetPredictionByID"]; s.Calls != 1 || s.Errors != 1 || s.Rows != 0 {
+		t.Errorf("GetPredictionByID stats = %+v", s)
+	}
+	if s := stats["predictions.Query"]; s.Calls != 1 || s.Rows != 2 || s.Slow != 1 || s.MaxLatencyMS < 30 {
+		t.Errorf("Query stats = %+v", s)
+	}
+}
+
+func TestPredictionRepositoryTimesOut(t *testing.T) {
+	recorder := NewRecorder().WithTimeout(10 * time.Millisecond)
+	store := slowStore{PredictionRepository: memory.NewPredictionRepository(), delay: 200 * time.Millisecond}
+	repo := NewPredictionRepository(store, recorder)
+
+	start := time.Now()
+	_, err := repo.Query(context.Background(), domain.PredictionQuery{})
+	if !errors.Is(err, domain.ErrRepositoryTimeout) {
+		t.Fatalf("Query() error = %v, want ErrRepositoryTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Query() returned after %s, want the 10ms timeout", elapsed)
+	}
+	if s := recorder.Stats()[0]; s.Timeouts != 1 || s.Errors != 1 {
+		t.Errorf("stats = %+v", s)
+	}
+}
@@ -0,0 +1,43 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// ReportRepository instruments a repository.ReportRepository
+type ReportRepository struct {
+	next     repository.ReportRepository
+	recorder *Recorder
+}
+
+// NewReportRepository wraps next
+func NewReportRepository(next repository.ReportRepository, recorder *Recorder) *ReportRepository {
+	return &ReportRepository{next: next, recorder: recorder}
+}
+
+func (r *ReportRepository) Save(ctx context.Context, report *domain.Report, html []byte) error {
+	return exec(ctx, r.recorder, "reports.Save", func(ctx context.Context) error {
+		return r.next.Save(ctx, report, html)
+	}, 1)
+}
+
+func (r *ReportRepository) GetByID(ctx context.Context, id string) (*domain.Report, error) {
+	return call(ctx, r.recorder, "reports.GetByID", func(ctx context.Context) (*domain.Report, error) {
+		return r.next.GetByID(ctx, id)
+	}, one)
+}
+
+func (r *ReportRepository) HTML(ctx context.Context, id string) ([]byte, error) {
+	return call(ctx, r.recorder, "reports.HTML", func(ctx context.Context) ([]byte, error) {
+		return r.next.HTML(ctx, id)
+	}, one)
+}
+
+func (r *ReportRepository) List(ctx context.Context) ([]*domain.Report, error) {
+	return call(ctx, r.recorder, "reports.List", func(ctx context.Context) ([]*domain.Report, error) {
+		return r.next.List(ctx)
+	}, count)
+}
@@ -0,0 +1,49 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// SavedSearchRepository instruments a repository.SavedSearchRepository
+type SavedSearchRepository struct {
+	next     repository.SavedSearchRepository
+	recorder *Recorder
+}
+
+// NewSavedSearchRepository wraps next
+func NewSavedSearchRepository(next repository.SavedSearchRepository, recorder *Recorder) *SavedSearchRepository {
+	return &SavedSearchRepository{next: next, recorder: recorder}
+}
+
+func (r *SavedSearchRepository) Save(ctx context.Context, search *domain.SavedSearch) error {
+	return exec(ctx, r.recorder, "searches.Save", func(ctx context.Context) error {
+		return r.next.Save(ctx, search)
+	}, 1)
+}
+
+func (r *SavedSearchRepository) GetByID(ctx context.Context, id string) (*domain.SavedSearch, error) {
+	return call(ctx, r.recorder, "searches.GetByID", func(ctx context.Context) (*domain.SavedSearch, error) {
+		return r.next.GetByID(ctx, id)
+	}, one)
+}
+
+func (r *SavedSearchRepository) ListByUser(ctx context.Context, userID string) ([]*domain.SavedSearch, error) {
+	return call(ctx, r.recorder, "searches.ListByUser", func(ctx context.Context) ([]*domain.SavedSearch, error) {
+		return r.next.ListByUser(ctx, userID)
+	}, count)
+}
+
+func (r *SavedSearchRepository) List(ctx context.Context) ([]*domain.SavedSearch, error) {
+	return call(ctx, r.recorder, "searches.List", func(ctx context.Context) ([]*domain.SavedSearch, error) {
+		return r.next.List(ctx)
+	}, count)
+}
+
+func (r *SavedSearchRepository) Delete(ctx context.Context, id string) error {
+	return exec(ctx, r.recorder, "searches.Delete", func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	}, 1)
+}
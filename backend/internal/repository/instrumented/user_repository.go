@@ -0,0 +1,55 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// UserRepository instruments a repository.UserRepository
+type UserRepository struct {
+	next     repository.UserRepository
+	recorder *Recorder
+}
+
+// NewUserRepository wraps next
+func NewUserRepository(next repository.UserRepository, recorder *Recorder) *UserRepository {
+	return &UserRepository{next: next, recorder: recorder}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	return exec(ctx, r.recorder, "users.Create", func(ctx context.Context) error {
+		return r.next.Create(ctx, user)
+	}, 1)
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	return call(ctx, r.recorder, "users.GetByID", func(ctx context.Context) (*domain.User, error) {
+		return r.next.GetByID(ctx, id)
+	}, one)
+}
+
+func (r *UserRepository) GetByExternalID(ctx context.Context, externalID string) (*domain.User, error) {
+	return call(ctx, r.recorder, "users.GetByExternalID", func(ctx context.Context) (*domain.User, error) {
+		return r.next.GetByExternalID(ctx, externalID)
+	}, one)
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	return exec(ctx, r.recorder, "users.Update", func(ctx context.Context) error {
+		return r.next.Update(ctx, user)
+	}, 1)
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	return exec(ctx, r.recorder, "users.Delete", func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	}, 1)
+}
+
+func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*domain.User, error) {
+	return call(ctx, r.recorder, "users.List", func(ctx context.Context) ([]*domain.User, error) {
+		return r.next.List(ctx, limit, offset)
+	}, count)
+}
@@ -0,0 +1,49 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// WatchRepository instruments a repository.WatchRepository
+type WatchRepository struct {
+	next     repository.WatchRepository
+	recorder *Recorder
+}
+
+// NewWatchRepository wraps next
+func NewWatchRepository(next repository.WatchRepository, recorder *Recorder) *WatchRepository {
+	return &WatchRepository{next: next, recorder: recorder}
+}
+
+func (r *WatchRepository) Save(ctx context.Context, watch *domain.Watch) error {
+	return exec(ctx, r.recorder, "watches.Save", func(ctx context.Context) error {
+		return r.next.Save(ctx, watch)
+	}, 1)
+}
+
+func (r *WatchRepository) GetByID(ctx context.Context, id string) (*domain.Watch, error) {
+	return call(ctx, r.recorder, "watches.GetByID", func(ctx context.Context) (*domain.Watch, error) {
+		return r.next.GetByID(ctx, id)
+	}, one)
+}
+
+func (r *WatchRepository) ListByUser(ctx context.Context, userID string) ([]*domain.Watch, error) {
+	return call(ctx, r.recorder, "watches.ListByUser", func(ctx context.Context) ([]*domain.Watch, error) {
+		return r.next.ListByUser(ctx, userID)
+	}, count)
+}
+
+func (r *WatchRepository) List(ctx context.Context) ([]*domain.Watch, error) {
+	return call(ctx, r.recorder, "watches.List", func(ctx context.Context) ([]*domain.Watch, error) {
+		return r.next.List(ctx)
+	}, count)
+}
+
+func (r *WatchRepository) Delete(ctx context.Context, id string) error {
+	return exec(ctx, r.recorder, "watches.Delete", func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	}, 1)
+}
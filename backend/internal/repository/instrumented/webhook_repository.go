@@ -0,0 +1,49 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// WebhookRepository instruments a repository.WebhookRepository
+type WebhookRepository struct {
+	next     repository.WebhookRepository
+	recorder *Recorder
+}
+
+// NewWebhookRepository wraps next
+func NewWebhookRepository(next repository.WebhookRepository, recorder *Recorder) *WebhookRepository {
+	return &WebhookRepository{next: next, recorder: recorder}
+}
+
+func (r *WebhookRepository) Save(ctx context.Context, sub *domain.WebhookSubscription) error {
+	return exec(ctx, r.recorder, "webhooks.Save", func(ctx context.Context) error {
+		return r.next.Save(ctx, sub)
+	}, 1)
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	return call(ctx, r.recorder, "webhooks.GetByID", func(ctx context.Context) (*domain.WebhookSubscription, error) {
+		return r.next.GetByID(ctx, id)
+	}, one)
+}
+
+func (r *WebhookRepository) ListByOwner(ctx context.Context, ownerID string) ([]*domain.WebhookSubscription, error) {
+	return call(ctx, r.recorder, "webhooks.ListByOwner", func(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+		return r.next.ListByOwner(ctx, ownerID)
+	}, count)
+}
+
+func (r *WebhookRepository) List(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	return call(ctx, r.recorder, "webhooks.List", func(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+		return r.next.List(ctx)
+	}, count)
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	return exec(ctx, r.recorder, "webhooks.Delete", func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	}, 1)
+}
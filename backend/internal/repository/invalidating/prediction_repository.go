@@ -0,0 +1,64 @@
+// Package invalidating wraps the prediction repository so every committed
+// write is published as a domain.PredictionChange, whichever service made
+// it. Caches subscribe to the changes instead of each write path having to
+// remember them.
+package invalidating
+
+import (
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/instrumented"
+)
+
+// Publisher receives committed prediction changes (service.InvalidationBus).
+type Publisher interface {
+	Publish(change domain.PredictionChange)
+}
+
+// PredictionRepository publishes the writes made through it. Reads pass
+// straight through to the wrapped store.
+type PredictionRepository struct {
+	instrumented.PredictionStore
+	publisher Publisher
+}
+
+// NewPredictionRepository wraps next.
+func NewPredictionRepository(next instrumented.PredictionStore, publisher Publisher) *PredictionRepository {
+	return &PredictionRepository{PredictionStore: next, publisher: publisher}
+}
+
+func (r *PredictionRepository) CreatePrediction(prediction *domain.Prediction) error {
+	if err := r.PredictionStore.CreatePrediction(prediction); err != nil {
+		return err
+	}
+	r.publisher.Publish(domain.PredictionChange{Kind: domain.ChangeCreated, ID: prediction.ID, After: prediction})
+	return nil
+}
+
+// UpdatePrediction looks up the stored prediction first, so subscribers
+// can invalidate what it was as well as what it has become.
+func (r *PredictionRepository) UpdatePrediction(prediction *domain.Prediction) error {
+	before, _ := r.PredictionStore.GetPredictionByID(prediction.ID)
+	if err := r.PredictionStore.UpdatePrediction(prediction); err != nil {
+		return err
+	}
+	r.publisher.Publish(domain.PredictionChange{Kind: domain.ChangeUpdated, ID: prediction.ID, Before: before, After: prediction})
+	return nil
+}
+
+func (r *PredictionRepository) DeletePrediction(id string) error {
+	before, _ := r.PredictionStore.GetPredictionByID(id)
+	if err := r.PredictionStore.DeletePrediction(id); err != nil {
+		return err
+	}
+	r.publisher.Publish(domain.PredictionChange{Kind: domain.ChangeDeleted, ID: id, Before: before})
+	return nil
+}
+
+func (r *PredictionRepository) DeleteUnpinnedPrediction(id string) error {
+	before, _ := r.PredictionStore.GetPredictionByID(id)
+	if err := r.PredictionStore.DeleteUnpinnedPrediction(id); err != nil {
+		return err
+	}
+	r.publisher.Publish(domain.PredictionChange{Kind: domain.ChangeDeleted, ID: id, Before: before})
+	return nil
+}
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// AnalysisTemplateRepository is an in-memory implementation keyed by template ID
+type AnalysisTemplateRepository struct {
+	mu        sync.RWMutex
+	templates map[string]domain.AnalysisTemplate
+}
+
+// NewAnalysisTemplateRepository creates a new in-memory analysis template repository
+func NewAnalysisTemplateRepository() *AnalysisTemplateRepository {
+	return &AnalysisTemplateRepository{
+		templates: make(map[string]domain.AnalysisTemplate),
+	}
+}
+
+// Save stores a copy of template
+func (r *AnalysisTemplateRepository) Save(ctx context.Context, template *domain.AnalysisTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.templates[template.ID] = *template
+	return nil
+}
+
+func (r *AnalysisTemplateRepository) GetByID(ctx context.Context, id string) (*domain.AnalysisTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	template, exists := r.templates[id]
+	if !exists {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrTemplateNotFound, id)
+	}
+	return &template, nil
+}
+
+// ListByUser returns a user's own templates, oldest first
+func (r *AnalysisTemplateRepository) ListByUser(ctx context.Context, userID string) ([]*domain.AnalysisTemplate, error) {
+	return r.list(func(t *domain.AnalysisTemplate) bool {
+		return t.Scope == domain.TemplateScopeUser && t.UserID == userID
+	}), nil
+}
+
+// ListByOrg returns an organization's templates, oldest first
+func (r *AnalysisTemplateRepository) ListByOrg(ctx context.Context, orgID string) ([]*domain.AnalysisTemplate, error) {
+	return r.list(func(t *domain.AnalysisTemplate) bool {
+		return t.Scope == domain.TemplateScopeOrg && t.OrgID == orgID
+	}), nil
+}
+
+func (r *AnalysisTemplateRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.templates[id]; !exists {
+		return fmt.Errorf("%w with id: %s", domain.ErrTemplateNotFound, id)
+	}
+	delete(r.templates, id)
+	return nil
+}
+
+func (r *AnalysisTemplateRepository) list(keep func(*domain.AnalysisTemplate) bool) []*domain.AnalysisTemplate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	templates := make([]*domain.AnalysisTemplate, 0)
+	for _, t := range r.templates {
+		if keep(&t) {
+			template := t
+			templates = append(templates, &template)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].CreatedAt.Before(templates[j].CreatedAt)
+	})
+	return templates
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+type storedCorpus struct {
+	manifest domain.CorpusManifest
+	data     []byte
+}
+
+// CorpusRepository is an in-memory implementation keyed by corpus ID
+type CorpusRepository struct {
+	mu      sync.RWMutex
+	corpora map[string]storedCorpus
+}
+
+// NewCorpusRepository creates a new in-memory corpus repository
+func NewCorpusRepository() *CorpusRepository {
+	return &CorpusRepository{
+		corpora: make(map[string]storedCorpus),
+	}
+}
+
+// Save stores a copy of manifest and its export
+func (r *CorpusRepository) Save(ctx context.Context, manifest *domain.CorpusManifest, data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.corpora[manifest.ID] = storedCorpus{manifest: *manifest, data: append([]byte(nil), data...)}
+	return nil
+}
+
+func (r *CorpusRepository) GetByID(ctx context.Context, id string) (*domain.CorpusManifest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, exists := r.corpora[id]
+	if !exists {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrCorpusNotFound, id)
+	}
+	return &stored.manifest, nil
+}
+
+func (r *CorpusRepository) Data(ctx context.Context, id string) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, exists := r.corpora[id]
+	if !exists {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrCorpusNotFound, id)
+	}
+	return stored.data, nil
+}
+
+// List returns every manifest, newest first
+func (r *CorpusRepository) List(ctx context.Context) ([]*domain.CorpusManifest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	manifests := make([]*domain.CorpusManifest, 0, len(r.corpora))
+	for _, stored := range r.corpora {
+		manifest := stored.manifest
+		manifests = append(manifests, &manifest)
+	}
+	sort.Slice(manifests, func(i, j int) bool { return manifests[i].CreatedAt.After(manifests[j].CreatedAt) })
+	return manifests, nil
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// EvaluationRepository is an in-memory implementation keyed by run ID
+type EvaluationRepository struct {
+	mu   sync.RWMutex
+	runs map[string]domain.EvaluationRun
+}
+
+// NewEvaluationRepository creates a new in-memory evaluation repository
+func NewEvaluationRepository() *EvaluationRepository {
+	return &EvaluationRepository{
+		runs: make(map[string]domain.EvaluationRun),
+	}
+}
+
+// Save stores a copy of run so later updates by the caller are not visible
+// until saved again
+func (r *EvaluationRepository) Save(ctx context.Context, run *domain.EvaluationRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.runs[run.ID] = *run
+	return nil
+}
+
+func (r *EvaluationRepository) GetByID(ctx context.Context, id string) (*domain.EvaluationRun, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	run, exists := r.runs[id]
+	if !exists {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrEvaluationNotFound, id)
+	}
+	return &run, nil
+}
+
+// List returns every run, oldest first
+func (r *EvaluationRepository) List(ctx context.Context) ([]*domain.EvaluationRun, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	runs := make([]*domain.EvaluationRun, 0, len(r.runs))
+	for _, run := range r.runs {
+		run := run
+		runs = append(runs, &run)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].CreatedAt.Before(runs[j].CreatedAt) })
+	return runs, nil
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// MetricsRollupRepository is an in-memory implementation keyed by rollup ID
+type MetricsRollupRepository struct {
+	mu      sync.RWMutex
+	rollups map[string]domain.MetricsRollup
+}
+
+// NewMetricsRollupRepository creates a new in-memory rollup repository
+func NewMetricsRollupRepository() *MetricsRollupRepository {
+	return &MetricsRollupRepository{rollups: make(map[string]domain.MetricsRollup)}
+}
+
+// Save stores a copy of rollup
+func (r *MetricsRollupRepository) Save(ctx context.Context, rollup *domain.MetricsRollup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *rollup
+	stored.Latency.Histogram = append([]int64(nil), rollup.Latency.Histogram...)
+	r.rollups[rollup.ID] = stored
+	return nil
+}
+
+func (r *MetricsRollupRepository) List(ctx context.Context, resolution string, since, until time.Time) ([]*domain.MetricsRollup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var rollups []*domain.MetricsRollup
+	for _, stored := range r.rollups {
+		if stored.Resolution != resolution || stored.Start.Before(since) || !stored.Start.Before(until) {
+			continue
+		}
+		rollup := stored
+		rollups = append(rollups, &rollup)
+	}
+	sort.Slice(rollups, func(i, j int) bool { return rollups[i].Start.Before(rollups[j].Start) })
+	return rollups, nil
+}
+
+func (r *MetricsRollupRepository) DeleteBefore(ctx context.Context, resolution string, cutoff time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for id, stored := range r.rollups {
+		if stored.Resolution == resolution && stored.Start.Before(cutoff) {
+			delete(r.rollups, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
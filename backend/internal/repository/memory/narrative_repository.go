@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// NarrativeRepository is an in-memory implementation keyed by narrative ID
+type NarrativeRepository struct {
+	mu         sync.RWMutex
+	narratives map[string]domain.Narrative
+}
+
+// NewNarrativeRepository creates a new in-memory narrative repository
+func NewNarrativeRepository() *NarrativeRepository {
+	return &NarrativeRepository{
+		narratives: make(map[string]domain.Narrative),
+	}
+}
+
+// Save stores a copy of narrative
+func (r *NarrativeRepository) Save(ctx context.Context, narrative *domain.Narrative) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.narratives[narrative.ID] = *narrative
+	return nil
+}
+
+func (r *NarrativeRepository) GetByID(ctx context.Context, id string) (*domain.Narrative, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	narrative, exists := r.narratives[id]
+	if !exists {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrNarrativeNotFound, id)
+	}
+	return &narrative, nil
+}
+
+// ListByOrg returns an organization's narratives, oldest first
+func (r *NarrativeRepository) ListByOrg(ctx context.Context, orgID string) ([]*domain.Narrative, error) {
+	return r.list(func(n *domain.Narrative) bool { return n.OrgID == orgID }), nil
+}
+
+// List returns all narratives, oldest first
+func (r *NarrativeRepository) List(ctx context.Context) ([]*domain.Narrative, error) {
+	return r.list(func(*domain.Narrative) bool { return true }), nil
+}
+
+func (r *NarrativeRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.narratives[id]; !exists {
+		return fmt.Errorf("%w with id: %s", domain.ErrNarrativeNotFound, id)
+	}
+	delete(r.narratives, id)
+	return nil
+}
+
+func (r *NarrativeRepository) list(keep func(*domain.Narrative) bool) []*domain.Narrative {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	narratives := make([]*domain.Narrative, 0)
+	for _, n := range r.narratives {
+		if keep(&n) {
+			narrative := n
+			narratives = append(narratives, &narrative)
+		}
+	}
+	sort.Slice(narratives, func(i, j int) bool {
+		return narratives[i].CreatedAt.Before(narratives[j].CreatedAt)
+	})
+	return narratives
+}
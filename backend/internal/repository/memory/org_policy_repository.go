@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// OrgDomainRuleRepository is an in-memory implementation keyed by rule ID
+type OrgDomainRuleRepository struct {
+	mu    sync.RWMutex
+	rules map[string]domain.OrgDomainRule
+}
+
+// NewOrgDomainRuleRepository creates a new in-memory domain rule repository
+func NewOrgDomainRuleRepository() *OrgDomainRuleRepository {
+	return &OrgDomainRuleRepository{
+		rules: make(map[string]domain.OrgDomainRule),
+	}
+}
+
+// Save stores a copy of rule
+func (r *OrgDomainRuleRepository) Save(ctx context.Context, rule *domain.OrgDomainRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rules[rule.ID] = *rule
+	return nil
+}
+
+func (r *OrgDomainRuleRepository) GetByID(ctx context.Context, id string) (*domain.OrgDomainRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rule, exists := r.rules[id]
+	if !exists {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrDomainRuleNotFound, id)
+	}
+	return &rule, nil
+}
+
+// ListByOrg returns an organization's rules ordered by domain
+func (r *OrgDomainRuleRepository) ListByOrg(ctx context.Context, orgID string) ([]*domain.OrgDomainRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rules := make([]*domain.OrgDomainRule, 0)
+	for _, rule := range r.rules {
+		if rule.OrgID == orgID {
+			rule := rule
+			rules = append(rules, &rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Domain < rules[j].Domain
+	})
+	return rules, nil
+}
+
+func (r *OrgDomainRuleRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.rules[id]; !exists {
+		return fmt.Errorf("%w with id: %s", domain.ErrDomainRuleNotFound, id)
+	}
+	delete(r.rules, id)
+	return nil
+}
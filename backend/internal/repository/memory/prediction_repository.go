@@ -1,7 +1,9 @@
 package memory
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/Naman30903/Final-Year-Project/internal/domain"
@@ -20,14 +22,32 @@ func NewPredictionRepository() *PredictionRepository {
 	}
 }
 
-// SavePrediction saves a prediction to memory
-func (r *PredictionRepository) SavePrediction(prediction *domain.Prediction) error {
+// CreatePrediction stores a new prediction. It fails with
+// domain.ErrAlreadyExists if the ID is already taken.
+func (r *PredictionRepository) CreatePrediction(prediction *domain.Prediction) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if prediction.ID == "" {
 		return fmt.Errorf("prediction ID cannot be empty")
 	}
+	if _, exists := r.predictions[prediction.ID]; exists {
+		return fmt.Errorf("%w: %s", domain.ErrAlreadyExists, prediction.ID)
+	}
+
+	r.predictions[prediction.ID] = prediction
+	return nil
+}
+
+// UpdatePrediction replaces an existing prediction. It fails with
+// domain.ErrPredictionNotFound if there is nothing to update.
+func (r *PredictionRepository) UpdatePrediction(prediction *domain.Prediction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.predictions[prediction.ID]; !exists {
+		return fmt.Errorf("%w with id: %s", domain.ErrPredictionNotFound, prediction.ID)
+	}
 
 	r.predictions[prediction.ID] = prediction
 	return nil
@@ -40,7 +60,7 @@ func (r *PredictionRepository) GetPredictionByID(id string) (*domain.Prediction,
 
 	prediction, exists := r.predictions[id]
 	if !exists {
-		return nil, fmt.Errorf("prediction not found with id: %s", id)
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrPredictionNotFound, id)
 	}
 
 	return prediction, nil
@@ -59,19 +79,156 @@ func (r *PredictionRepository) GetAllPredictions() ([]*domain.Prediction, error)
 	return predictions, nil
 }
 
+// GetPredictionByCanonicalURL retrieves the most recent prediction for a
+// canonical article URL
+func (r *PredictionRepository) GetPredictionByCanonicalURL(canonicalURL string) (*domain.Prediction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latest *domain.Prediction
+	for _, p := range r.predictions {
+		if p.CanonicalURL != canonicalURL {
+			continue
+		}
+		if latest == nil || p.CreatedAt.After(latest.CreatedAt) {
+			latest = p
+		}
+	}
+	if latest == nil || canonicalURL == "" {
+		return nil, fmt.Errorf("%w with canonical url: %s", domain.ErrPredictionNotFound, canonicalURL)
+	}
+
+	return latest, nil
+}
+
+// Query retrieves predictions matching q, ordered by creation time
+func (r *PredictionRepository) Query(ctx context.Context, q domain.PredictionQuery) ([]*domain.Prediction, error) {
+	r.mu.RLock()
+	matched := make([]*domain.Prediction, 0)
+	for _, p := range r.predictions {
+		if q.Matches(p) {
+			matched = append(matched, p)
+		}
+	}
+	r.mu.RUnlock()
+
+	sortForQuery(matched, q)
+	if q.Offset >= len(matched) {
+		return []*domain.Prediction{}, nil
+	}
+	matched = matched[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(matched) {
+		matched = matched[:q.Limit]
+	}
+	return matched, nil
+}
+
+// Count returns how many predictions match q's filters, ignoring its page
+func (r *PredictionRepository) Count(ctx context.Context, q domain.PredictionQuery) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n := 0
+	for _, p := range r.predictions {
+		if q.Matches(p) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Iterate calls fn with each prediction matching q, in Query's order,
+// stopping at the first error. Only pointers to the matches are held, and
+// the lock is released before fn runs, so a slow consumer does not block
+// writers.
+func (r *PredictionRepository) Iterate(ctx context.Context, q domain.PredictionQuery, fn func(*domain.Prediction) error) error {
+	r.mu.RLock()
+	matched := make([]*domain.Prediction, 0)
+	for _, p := range r.predictions {
+		if q.Matches(p) {
+			matched = append(matched, p)
+		}
+	}
+	r.mu.RUnlock()
+
+	sortForQuery(matched, q)
+	for i := q.Offset; i < len(matched); i++ {
+		if q.Limit > 0 && i-q.Offset == q.Limit {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(matched[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortForQuery orders predictions as q asks: newest first unless
+// OldestFirst, optionally with pinned predictions ahead.
+func sortForQuery(predictions []*domain.Prediction, q domain.PredictionQuery) {
+	sort.Slice(predictions, func(i, j int) bool {
+		if q.PinnedFirst && predictions[i].Pinned != predictions[j].Pinned {
+			return predictions[i].Pinned
+		}
+		if q.OldestFirst {
+			return predictions[i].CreatedAt.Before(predictions[j].CreatedAt)
+		}
+		return predictions[i].CreatedAt.After(predictions[j].CreatedAt)
+	})
+}
+
+// Aggregate buckets the predictions matching q by creation time
+func (r *PredictionRepository) Aggregate(ctx context.Context, q domain.TimeSeriesQuery) ([]domain.TimeSeriesPoint, error) {
+	filter := q.Filter
+	filter.Since, filter.Until = q.Since, q.Until
+
+	counts := make(map[int64]int)
+	sums := make(map[int64]float64)
+	r.mu.RLock()
+	for _, p := range r.predictions {
+		if filter.Matches(p) {
+			bucket := q.Bucket(p.CreatedAt)
+			counts[bucket]++
+			sums[bucket] += q.Sample(p)
+		}
+	}
+	r.mu.RUnlock()
+	return q.Points(counts, sums), nil
+}
+
 // DeletePrediction deletes a prediction by ID
 func (r *PredictionRepository) DeletePrediction(id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if _, exists := r.predictions[id]; !exists {
-		return fmt.Errorf("prediction not found with id: %s", id)
+		return fmt.Errorf("%w with id: %s", domain.ErrPredictionNotFound, id)
 	}
 
 	delete(r.predictions, id)
 	return nil
 }
 
+// DeleteUnpinnedPrediction deletes a prediction by ID unless it is pinned.
+// The pin is checked under the same lock as the delete, so a pin made
+// while a bulk delete runs is never lost.
+func (r *PredictionRepository) DeleteUnpinnedPrediction(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, exists := r.predictions[id]
+	if !exists {
+		return fmt.Errorf("%w with id: %s", domain.ErrPredictionNotFound, id)
+	}
+	if p.Pinned {
+		return fmt.Errorf("%w with id: %s", domain.ErrPredictionPinned, id)
+	}
+	delete(r.predictions, id)
+	return nil
+}
+
 // Clear removes all predictions (useful for testing)
 func (r *PredictionRepository) Clear() {
 	r.mu.Lock()
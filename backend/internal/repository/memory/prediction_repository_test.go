@@ -0,0 +1,188 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+func TestPredictionRepository_Query(t *testing.T) {
+	repo := NewPredictionRepository()
+	ctx := context.Background()
+	now := time.Now()
+
+	seed := []*domain.Prediction{
+		{ID: "1", Result: "FAKE", Confidence: 0.9, ArticleSource: "news.example.com", CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "2", Result: "REAL", Confidence: 0.6, ArticleSource: "example.com", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "3", Result: "FAKE", Confidence: 0.55, ArticleSource: "other.org", CreatedAt: now.Add(-1 * time.Hour)},
+	}
+	for _, p := range seed {
+		_ = repo.CreatePrediction(p)
+	}
+
+	// Newest first by default
+	all, err := repo.Query(ctx, domain.PredictionQuery{})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(all) != 3 || all[0].ID != "3" {
+		t.Errorf("Query() should return all predictions newest first, got %d starting with %v", len(all), all[0].ID)
+	}
+
+	// Label + confidence
+	fake, _ := repo.Query(ctx, *domain.NewPredictionQuery().WithLabel("fake").WithConfidence(0.8, 0))
+	if len(fake) != 1 || fake[0].ID != "1" {
+		t.Errorf("Query() label/confidence filter got %v, want [1]", fake)
+	}
+
+	// Domain matches subdomains
+	byDomain, _ := repo.Query(ctx, *domain.NewPredictionQuery().WithDomain("example.com"))
+	if len(byDomain) != 2 {
+		t.Errorf("Query() domain filter got %d predictions, want 2", len(byDomain))
+	}
+
+	// Paging
+	page, _ := repo.Query(ctx, *domain.NewPredictionQuery().WithPage(1, 1))
+	if len(page) != 1 || page[0].ID != "2" {
+		t.Errorf("Query() paging got %v, want [2]", page)
+	}
+	// Count ignores the page
+	if n, _ := repo.Count(ctx, *domain.NewPredictionQuery().WithLabel("fake").WithPage(1, 1)); n != 2 {
+		t.Errorf("Count() = %d, want 2", n)
+	}
+}
+
+func TestPredictionRepository_Iterate(t *testing.T) {
+	repo := NewPredictionRepository()
+	now := time.Now()
+	for i, id := range []string{"1", "2", "3", "4"} {
+		_ = repo.CreatePrediction(&domain.Prediction{ID: id, Result: "FAKE", CreatedAt: now.Add(time.Duration(i) * time.Hour)})
+	}
+
+	collect := func(q domain.PredictionQuery) []string {
+		t.Helper()
+		var ids []string
+		if err := repo.Iterate(context.Background(), q, func(p *domain.Prediction) error {
+			ids = append(ids, p.ID)
+			return nil
+		}); err != nil {
+			t.Fatalf("Iterate() error = %v", err)
+		}
+		return ids
+	}
+	// Same order and paging as Query
+	for _, q := range []domain.PredictionQuery{{}, {OldestFirst: true}, *domain.NewPredictionQuery().WithPage(2, 1)} {
+		want, _ := repo.Query(context.Background(), q)
+		got := collect(q)
+		if len(got) != len(want) {
+			t.Fatalf("Iterate(%+v) yielded %v, Query returned %d", q, got, len(want))
+		}
+		for i := range want {
+			if got[i] != want[i].ID {
+				t.Errorf("Iterate(%+v) = %v, differs from Query at %d", q, got, i)
+			}
+		}
+	}
+
+	// The callback's error stops the iteration
+	stop := errors.New("stop")
+	calls := 0
+	err := repo.Iterate(context.Background(), domain.PredictionQuery{}, func(*domain.Prediction) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Iterate() = %v after %d calls, want stop after 1", err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := repo.Iterate(ctx, domain.PredictionQuery{}, func(*domain.Prediction) error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("Iterate() with a cancelled context = %v", err)
+	}
+}
+
+func TestPredictionRepository_CreateAndUpdate(t *testing.T) {
+	repo := NewPredictionRepository()
+
+	prediction := &domain.Prediction{ID: "1", Result: "FAKE"}
+	if err := repo.CreatePrediction(prediction); err != nil {
+		t.Fatalf("CreatePrediction() error = %v", err)
+	}
+
+	// Duplicate IDs must not overwrite
+	err := repo.CreatePrediction(&domain.Prediction{ID: "1", Result: "REAL"})
+	if !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Errorf("CreatePrediction() error = %v, want ErrAlreadyExists", err)
+	}
+	stored, _ := repo.GetPredictionByID("1")
+	if stored.Result != "FAKE" {
+		t.Errorf("CreatePrediction() overwrote existing prediction")
+	}
+
+	if err := repo.UpdatePrediction(&domain.Prediction{ID: "1", Result: "REAL"}); err != nil {
+		t.Errorf("UpdatePrediction() error = %v", err)
+	}
+	err = repo.UpdatePrediction(&domain.Prediction{ID: "999"})
+	if !errors.Is(err, domain.ErrPredictionNotFound) {
+		t.Errorf("UpdatePrediction() error = %v, want ErrPredictionNotFound", err)
+	}
+}
+
+func TestPredictionRepository_Aggregate(t *testing.T) {
+	repo := NewPredictionRepository()
+	ctx := context.Background()
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	seed := []*domain.Prediction{
+		{ID: "1", Result: "FAKE", Confidence: 0.9, RequestType: "url", CreatedAt: day.Add(10 * time.Minute)},
+		{ID: "2", Result: "REAL", Confidence: 0.7, RequestType: "url", CreatedAt: day.Add(50 * time.Minute)},
+		{ID: "3", Result: "FAKE", Confidence: 0.8, RequestType: "text", CreatedAt: day.Add(2*time.Hour + time.Minute).In(time.FixedZone("IST", 19800))},
+		{ID: "4", Result: "FAKE", Confidence: 0.6, RequestType: "url", CreatedAt: day.Add(-time.Minute)}, // before range
+	}
+	for _, p := range seed {
+		_ = repo.CreatePrediction(p)
+	}
+
+	tests := []struct {
+		name       string
+		metric     string
+		filter     domain.PredictionQuery
+		wantCounts []int
+		wantValues []float64
+	}{
+		{"fake ratio", domain.MetricFakeRatio, domain.PredictionQuery{}, []int{2, 0, 1}, []float64{0.5, 0, 1}},
+		{"count", domain.MetricCount, domain.PredictionQuery{}, []int{2, 0, 1}, []float64{2, 0, 1}},
+		{"average confidence", domain.MetricAvgConfidence, domain.PredictionQuery{}, []int{2, 0, 1}, []float64{0.8, 0, 0.8}},
+		{"filtered", domain.MetricCount, *domain.NewPredictionQuery().WithRequestType("url"), []int{2, 0, 0}, []float64{2, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, err := repo.Aggregate(ctx, domain.TimeSeriesQuery{
+				Metric:   tt.metric,
+				Interval: time.Hour,
+				Since:    day,
+				Until:    day.Add(3 * time.Hour),
+				Filter:   tt.filter,
+			})
+			if err != nil {
+				t.Fatalf("Aggregate() error = %v", err)
+			}
+			if len(points) != len(tt.wantCounts) {
+				t.Fatalf("Aggregate() returned %d buckets, want %d", len(points), len(tt.wantCounts))
+			}
+			for i, p := range points {
+				if !p.Start.Equal(day.Add(time.Duration(i) * time.Hour)) {
+					t.Errorf("bucket %d starts at %v", i, p.Start)
+				}
+				if p.Count != tt.wantCounts[i] || math.Abs(p.Value-tt.wantValues[i]) > 1e-9 {
+					t.Errorf("bucket %d = %d / %v, want %d / %v", i, p.Count, p.Value, tt.wantCounts[i], tt.wantValues[i])
+				}
+			}
+		})
+	}
+}
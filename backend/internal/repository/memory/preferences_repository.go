@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// PreferencesRepository is an in-memory implementation keyed by user ID
+type PreferencesRepository struct {
+	mu       sync.RWMutex
+	defaults map[string]domain.AnalysisDefaults
+}
+
+// NewPreferencesRepository creates a new in-memory preferences repository
+func NewPreferencesRepository() *PreferencesRepository {
+	return &PreferencesRepository{
+		defaults: make(map[string]domain.AnalysisDefaults),
+	}
+}
+
+func (r *PreferencesRepository) Get(ctx context.Context, userID string) (*domain.AnalysisDefaults, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	defaults, exists := r.defaults[userID]
+	if !exists {
+		return nil, fmt.Errorf("%w for user: %s", domain.ErrPreferencesNotFound, userID)
+	}
+	return &defaults, nil
+}
+
+// Save stores a copy of defaults
+func (r *PreferencesRepository) Save(ctx context.Context, userID string, defaults *domain.AnalysisDefaults) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.defaults[userID] = *defaults
+	return nil
+}
+
+func (r *PreferencesRepository) Delete(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.defaults[userID]; !exists {
+		return fmt.Errorf("%w for user: %s", domain.ErrPreferencesNotFound, userID)
+	}
+	delete(r.defaults, userID)
+	return nil
+}
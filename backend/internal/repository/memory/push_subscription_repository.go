@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// PushSubscriptionRepository is an in-memory implementation keyed by endpoint
+type PushSubscriptionRepository struct {
+	mu            sync.RWMutex
+	subscriptions map[string]*domain.PushSubscription
+}
+
+// NewPushSubscriptionRepository creates a new in-memory push subscription repository
+func NewPushSubscriptionRepository() *PushSubscriptionRepository {
+	return &PushSubscriptionRepository{
+		subscriptions: make(map[string]*domain.PushSubscription),
+	}
+}
+
+func (r *PushSubscriptionRepository) Save(ctx context.Context, sub *domain.PushSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscriptions[sub.Endpoint] = sub
+	return nil
+}
+
+func (r *PushSubscriptionRepository) ListByUser(ctx context.Context, userID string) ([]*domain.PushSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subs := make([]*domain.PushSubscription, 0)
+	for _, sub := range r.subscriptions {
+		if sub.UserID == userID {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+func (r *PushSubscriptionRepository) GetByEndpoint(ctx context.Context, endpoint string) (*domain.PushSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sub, exists := r.subscriptions[endpoint]
+	if !exists {
+		return nil, domain.ErrPushSubscriptionNotFound
+	}
+	return sub, nil
+}
+
+func (r *PushSubscriptionRepository) DeleteByEndpoint(ctx context.Context, endpoint string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.subscriptions[endpoint]; !exists {
+		return domain.ErrPushSubscriptionNotFound
+	}
+	delete(r.subscriptions, endpoint)
+	return nil
+}
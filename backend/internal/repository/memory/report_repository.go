@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+type storedReport struct {
+	report domain.Report
+	html   []byte
+}
+
+// ReportRepository is an in-memory implementation keyed by report ID
+type ReportRepository struct {
+	mu      sync.RWMutex
+	reports map[string]storedReport
+}
+
+// NewReportRepository creates a new in-memory report repository
+func NewReportRepository() *ReportRepository {
+	return &ReportRepository{
+		reports: make(map[string]storedReport),
+	}
+}
+
+// Save stores a copy of report and its HTML
+func (r *ReportRepository) Save(ctx context.Context, report *domain.Report, html []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reports[report.ID] = storedReport{report: *report, html: append([]byte(nil), html...)}
+	return nil
+}
+
+func (r *ReportRepository) GetByID(ctx context.Context, id string) (*domain.Report, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, exists := r.reports[id]
+	if !exists {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrReportNotFound, id)
+	}
+	return &stored.report, nil
+}
+
+func (r *ReportRepository) HTML(ctx context.Context, id string) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, exists := r.reports[id]
+	if !exists {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrReportNotFound, id)
+	}
+	return stored.html, nil
+}
+
+// List returns every report, newest first
+func (r *ReportRepository) List(ctx context.Context) ([]*domain.Report, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reports := make([]*domain.Report, 0, len(r.reports))
+	for _, stored := range r.reports {
+		report := stored.report
+		reports = append(reports, &report)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Date > reports[j].Date })
+	return reports, nil
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// SavedSearchRepository is an in-memory implementation keyed by search ID
+type SavedSearchRepository struct {
+	mu       sync.RWMutex
+	searches map[string]domain.SavedSearch
+}
+
+// NewSavedSearchRepository creates a new in-memory saved search repository
+func NewSavedSearchRepository() *SavedSearchRepository {
+	return &SavedSearchRepository{
+		searches: make(map[string]domain.SavedSearch),
+	}
+}
+
+// Save stores a copy of search
+func (r *SavedSearchRepository) Save(ctx context.Context, search *domain.SavedSearch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.searches[search.ID] = *search
+	return nil
+}
+
+func (r *SavedSearchRepository) GetByID(ctx context.Context, id string) (*domain.SavedSearch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	search, exists := r.searches[id]
+	if !exists {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrSavedSearchNotFound, id)
+	}
+	return &search, nil
+}
+
+// ListByUser returns a user's saved searches, oldest first
+func (r *SavedSearchRepository) ListByUser(ctx context.Context, userID string) ([]*domain.SavedSearch, error) {
+	return r.list(func(s *domain.SavedSearch) bool { return s.UserID == userID }), nil
+}
+
+// List returns every saved search, oldest first
+func (r *SavedSearchRepository) List(ctx context.Context) ([]*domain.SavedSearch, error) {
+	return r.list(func(*domain.SavedSearch) bool { return true }), nil
+}
+
+func (r *SavedSearchRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.searches[id]; !exists {
+		return fmt.Errorf("%w with id: %s", domain.ErrSavedSearchNotFound, id)
+	}
+	delete(r.searches, id)
+	return nil
+}
+
+func (r *SavedSearchRepository) list(keep func(*domain.SavedSearch) bool) []*domain.SavedSearch {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	searches := make([]*domain.SavedSearch, 0)
+	for _, s := range r.searches {
+		if keep(&s) {
+			search := s
+			searches = append(searches, &search)
+		}
+	}
+	sort.Slice(searches, func(i, j int) bool {
+		return searches[i].CreatedAt.Before(searches[j].CreatedAt)
+	})
+	return searches
+}
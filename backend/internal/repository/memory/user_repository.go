@@ -42,17 +42,29 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User,
 
 	user, exists := r.users[id]
 	if !exists {
-		return nil, errors.New("user not found")
+		return nil, domain.ErrUserNotFound
 	}
 	return user, nil
 }
 
+func (r *UserRepository) GetByExternalID(ctx context.Context, externalID string) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.ExternalID != "" && user.ExternalID == externalID {
+			return user, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
 func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if _, exists := r.users[user.ID]; !exists {
-		return errors.New("user not found")
+		return domain.ErrUserNotFound
 	}
 
 	user.UpdatedAt = time.Now()
@@ -65,7 +77,7 @@ func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	defer r.mu.Unlock()
 
 	if _, exists := r.users[id]; !exists {
-		return errors.New("user not found")
+		return domain.ErrUserNotFound
 	}
 
 	delete(r.users, id)
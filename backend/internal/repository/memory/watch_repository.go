@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// WatchRepository is an in-memory implementation keyed by watch ID
+type WatchRepository struct {
+	mu      sync.RWMutex
+	watches map[string]domain.Watch
+}
+
+// NewWatchRepository creates a new in-memory watch repository
+func NewWatchRepository() *WatchRepository {
+	return &WatchRepository{
+		watches: make(map[string]domain.Watch),
+	}
+}
+
+// Save stores a copy of watch
+func (r *WatchRepository) Save(ctx context.Context, watch *domain.Watch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.watches[watch.ID] = *watch
+	return nil
+}
+
+func (r *WatchRepository) GetByID(ctx context.Context, id string) (*domain.Watch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	watch, exists := r.watches[id]
+	if !exists {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrWatchNotFound, id)
+	}
+	return &watch, nil
+}
+
+// ListByUser returns a user's watches, oldest first
+func (r *WatchRepository) ListByUser(ctx context.Context, userID string) ([]*domain.Watch, error) {
+	return r.list(func(w *domain.Watch) bool { return w.UserID == userID }), nil
+}
+
+// List returns every watch, oldest first
+func (r *WatchRepository) List(ctx context.Context) ([]*domain.Watch, error) {
+	return r.list(func(*domain.Watch) bool { return true }), nil
+}
+
+func (r *WatchRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.watches[id]; !exists {
+		return fmt.Errorf("%w with id: %s", domain.ErrWatchNotFound, id)
+	}
+	delete(r.watches, id)
+	return nil
+}
+
+func (r *WatchRepository) list(keep func(*domain.Watch) bool) []*domain.Watch {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	watches := make([]*domain.Watch, 0)
+	for _, w := range r.watches {
+		if keep(&w) {
+			watch := w
+			watches = append(watches, &watch)
+		}
+	}
+	sort.Slice(watches, func(i, j int) bool {
+		return watches[i].CreatedAt.Before(watches[j].CreatedAt)
+	})
+	return watches
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// WebhookRepository is an in-memory implementation keyed by subscription ID
+type WebhookRepository struct {
+	mu   sync.RWMutex
+	subs map[string]domain.WebhookSubscription
+}
+
+// NewWebhookRepository creates a new in-memory webhook repository
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{
+		subs: make(map[string]domain.WebhookSubscription),
+	}
+}
+
+// Save stores a copy of sub
+func (r *WebhookRepository) Save(ctx context.Context, sub *domain.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *sub
+	stored.Events = slices.Clone(sub.Events)
+	r.subs[sub.ID] = stored
+	return nil
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sub, exists := r.subs[id]
+	if !exists {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrWebhookNotFound, id)
+	}
+	sub.Events = slices.Clone(sub.Events)
+	return &sub, nil
+}
+
+// ListByOwner returns an owner's subscriptions, oldest first
+func (r *WebhookRepository) ListByOwner(ctx context.Context, ownerID string) ([]*domain.WebhookSubscription, error) {
+	return r.list(func(s *domain.WebhookSubscription) bool { return s.OwnerID == ownerID }), nil
+}
+
+// List returns every subscription, oldest first
+func (r *WebhookRepository) List(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	return r.list(func(*domain.WebhookSubscription) bool { return true }), nil
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.subs[id]; !exists {
+		return fmt.Errorf("%w with id: %s", domain.ErrWebhookNotFound, id)
+	}
+	delete(r.subs, id)
+	return nil
+}
+
+func (r *WebhookRepository) list(keep func(*domain.WebhookSubscription) bool) []*domain.WebhookSubscription {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subs := make([]*domain.WebhookSubscription, 0)
+	for _, s := range r.subs {
+		if keep(&s) {
+			sub := s
+			sub.Events = slices.Clone(s.Events)
+			subs = append(subs, &sub)
+		}
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].CreatedAt.Before(subs[j].CreatedAt)
+	})
+	return subs
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// MetricsRollupRepository defines the interface for long-term metrics
+// rollup storage
+type MetricsRollupRepository interface {
+	// Save stores a rollup, replacing any existing one with the same ID
+	Save(ctx context.Context, rollup *domain.MetricsRollup) error
+	// List returns a resolution's rollups starting in [since, until),
+	// oldest first
+	List(ctx context.Context, resolution string, since, until time.Time) ([]*domain.MetricsRollup, error)
+	// DeleteBefore removes a resolution's rollups starting before cutoff
+	// and returns how many it removed
+	DeleteBefore(ctx context.Context, resolution string, cutoff time.Time) (int, error)
+}
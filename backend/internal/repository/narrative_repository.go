@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// NarrativeRepository defines the interface for organization narrative
+// storage
+type NarrativeRepository interface {
+	// Save stores a narrative, replacing any existing one with the same ID
+	Save(ctx context.Context, narrative *domain.Narrative) error
+	GetByID(ctx context.Context, id string) (*domain.Narrative, error)
+	ListByOrg(ctx context.Context, orgID string) ([]*domain.Narrative, error)
+	// List returns every organization's narratives, for tagging
+	List(ctx context.Context) ([]*domain.Narrative, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// OrgDomainRuleRepository defines the interface for organization domain
+// list storage
+type OrgDomainRuleRepository interface {
+	// Save stores a rule, replacing any existing one with the same ID
+	Save(ctx context.Context, rule *domain.OrgDomainRule) error
+	GetByID(ctx context.Context, id string) (*domain.OrgDomainRule, error)
+	ListByOrg(ctx context.Context, orgID string) ([]*domain.OrgDomainRule, error)
+	Delete(ctx context.Context, id string) error
+}
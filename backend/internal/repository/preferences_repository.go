@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// PreferencesRepository defines the interface for per-user analysis
+// defaults storage
+type PreferencesRepository interface {
+	Get(ctx context.Context, userID string) (*domain.AnalysisDefaults, error)
+	// Save stores the user's defaults, replacing any existing ones
+	Save(ctx context.Context, userID string, defaults *domain.AnalysisDefaults) error
+	Delete(ctx context.Context, userID string) error
+}
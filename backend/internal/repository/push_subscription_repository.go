@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// PushSubscriptionRepository defines the interface for Web Push subscription storage
+type PushSubscriptionRepository interface {
+	// Save stores a subscription, replacing any existing one with the same endpoint
+	Save(ctx context.Context, sub *domain.PushSubscription) error
+	ListByUser(ctx context.Context, userID string) ([]*domain.PushSubscription, error)
+	// GetByEndpoint returns domain.ErrPushSubscriptionNotFound when no subscription has endpoint
+	GetByEndpoint(ctx context.Context, endpoint string) (*domain.PushSubscription, error)
+	DeleteByEndpoint(ctx context.Context, endpoint string) error
+}
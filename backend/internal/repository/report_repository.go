@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// ReportRepository defines the interface for nightly report storage. Each
+// report is stored with its rendered HTML.
+type ReportRepository interface {
+	// Save stores a report, replacing any existing one with the same ID
+	Save(ctx context.Context, report *domain.Report, html []byte) error
+	GetByID(ctx context.Context, id string) (*domain.Report, error)
+	HTML(ctx context.Context, id string) ([]byte, error)
+	// List returns every report, newest first
+	List(ctx context.Context) ([]*domain.Report, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// SavedSearchRepository defines the interface for saved history search storage
+type SavedSearchRepository interface {
+	// Save stores a search, replacing any existing one with the same ID
+	Save(ctx context.Context, search *domain.SavedSearch) error
+	GetByID(ctx context.Context, id string) (*domain.SavedSearch, error)
+	ListByUser(ctx context.Context, userID string) ([]*domain.SavedSearch, error)
+	List(ctx context.Context) ([]*domain.SavedSearch, error)
+	Delete(ctx context.Context, id string) error
+}
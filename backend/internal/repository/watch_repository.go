@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// WatchRepository defines the interface for article watch storage
+type WatchRepository interface {
+	// Save stores a watch, replacing any existing one with the same ID
+	Save(ctx context.Context, watch *domain.Watch) error
+	GetByID(ctx context.Context, id string) (*domain.Watch, error)
+	ListByUser(ctx context.Context, userID string) ([]*domain.Watch, error)
+	List(ctx context.Context) ([]*domain.Watch, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// WebhookRepository defines the interface for webhook subscription storage
+type WebhookRepository interface {
+	// Save stores a subscription, replacing any existing one with the same ID
+	Save(ctx context.Context, sub *domain.WebhookSubscription) error
+	GetByID(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	ListByOwner(ctx context.Context, ownerID string) ([]*domain.WebhookSubscription, error)
+	List(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Alert severities.
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+	SeverityResolved = "resolved"
+)
+
+// Alert is an operator-facing notification.
+type Alert struct {
+	Name     string            `json:"name"`
+	Severity string            `json:"severity"`
+	Summary  string            `json:"summary"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	FiredAt  time.Time         `json:"fired_at"`
+}
+
+// Alerter delivers alerts to operators.
+type Alerter interface {
+	Fire(alert Alert) error
+}
+
+// LogAlerter writes alerts to the standard logger.
+type LogAlerter struct{}
+
+// Fire logs the alert.
+func (LogAlerter) Fire(alert Alert) error {
+	log.Printf("ALERT [%s] %s: %s", alert.Severity, alert.Name, alert.Summary)
+	return nil
+}
+
+// WebhookAlerter posts alerts as JSON to a webhook (Slack/Discord/etc.
+// bridges, Alertmanager-compatible receivers, ...).
+type WebhookAlerter struct {
+	url        string
+	httpClient *http.Client
+	delivery   *DeliveryEngine
+}
+
+// NewWebhookAlerter creates a new webhook alerter.
+func NewWebhookAlerter(url string) *WebhookAlerter {
+	return &WebhookAlerter{
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// WithDelivery retries alerts through engine instead of posting each once.
+func (a *WebhookAlerter) WithDelivery(engine *DeliveryEngine) *WebhookAlerter {
+	a.delivery = engine
+	return a
+}
+
+// WithOutboundAudit records alert posts in audit.
+func (a *WebhookAlerter) WithOutboundAudit(audit *OutboundAudit) *WebhookAlerter {
+	audit.Client(a.httpClient, PurposeWebhook)
+	return a
+}
+
+// Fire posts the alert to the webhook.
+func (a *WebhookAlerter) Fire(alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+	return a.delivery.Send(context.Background(), ChannelAlert, deliveryDestination(a.url), func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+		if err != nil {
+			return PermanentDeliveryError(fmt.Errorf("alert delivery failed: %w", err))
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := a.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("alert delivery failed: %w", err)
+		}
+		defer resp.Body.Close()
+		return deliveryStatusError("alert delivery", resp.StatusCode)
+	})
+}
+
+// MultiAlerter fans an alert out to several alerters.
+type MultiAlerter []Alerter
+
+// Fire delivers to every alerter, returning the first error.
+func (m MultiAlerter) Fire(alert Alert) error {
+	var firstErr error
+	for _, a := range m {
+		if err := a.Fire(alert); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+	"github.com/google/uuid"
+)
+
+// DefaultMaxTemplates caps templates per user and per organization.
+const DefaultMaxTemplates = 50
+
+// TemplateCaller is who uses or manages templates: the user and, for
+// session logins, their organization and whether they may manage its
+// shared templates.
+type TemplateCaller struct {
+	UserID    string
+	OrgID     string
+	OrgEditor bool
+}
+
+// AnalysisTemplateService stores named analysis configurations, private
+// to a user or shared with an organization, and resolves them for
+// analysis requests.
+type AnalysisTemplateService struct {
+	repo         repository.AnalysisTemplateRepository
+	maxTemplates int
+	now          func() time.Time
+}
+
+// NewAnalysisTemplateService creates an analysis template service.
+func NewAnalysisTemplateService(repo repository.AnalysisTemplateRepository) *AnalysisTemplateService {
+	return &AnalysisTemplateService{
+		repo:         repo,
+		maxTemplates: DefaultMaxTemplates,
+		now:          time.Now,
+	}
+}
+
+// WithMaxTemplates caps how many templates one user or organization can
+// save.
+func (s *AnalysisTemplateService) WithMaxTemplates(max int) *AnalysisTemplateService {
+	if max > 0 {
+		s.maxTemplates = max
+	}
+	return s
+}
+
+// Create saves a template for the caller, or for the caller's
+// organization when its scope is org.
+func (s *AnalysisTemplateService) Create(ctx context.Context, caller TemplateCaller, template domain.AnalysisTemplate) (*domain.AnalysisTemplate, error) {
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+
+	saved := &domain.AnalysisTemplate{
+		ID:        uuid.New().String(),
+		Name:      template.Name,
+		Scope:     template.Scope,
+		Settings:  template.Settings,
+		CreatedBy: caller.UserID,
+	}
+	var existing []*domain.AnalysisTemplate
+	var err error
+	if template.Scope == domain.TemplateScopeOrg {
+		if caller.OrgID == "" || !caller.OrgEditor {
+			return nil, fmt.Errorf("%w: organization templates need an organization admin or analyst", domain.ErrTemplateForbidden)
+		}
+		saved.OrgID = caller.OrgID
+		existing, err = s.repo.ListByOrg(ctx, caller.OrgID)
+	} else {
+		saved.UserID = caller.UserID
+		existing, err = s.repo.ListByUser(ctx, caller.UserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= s.maxTemplates {
+		return nil, fmt.Errorf("%w (%d)", domain.ErrTemplateLimitReached, s.maxTemplates)
+	}
+
+	now := s.now()
+	saved.CreatedAt, saved.UpdatedAt = now, now
+	if err := s.repo.Save(ctx, saved); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// List returns the caller's own templates followed by their
+// organization's.
+func (s *AnalysisTemplateService) List(ctx context.Context, caller TemplateCaller) ([]*domain.AnalysisTemplate, error) {
+	templates, err := s.repo.ListByUser(ctx, caller.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if caller.OrgID == "" {
+		return templates, nil
+	}
+	shared, err := s.repo.ListByOrg(ctx, caller.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	return append(templates, shared...), nil
+}
+
+// Get returns a template the caller can use. Templates of other users and
+// organizations are reported as not found.
+func (s *AnalysisTemplateService) Get(ctx context.Context, caller TemplateCaller, id string) (*domain.AnalysisTemplate, error) {
+	template, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case template.Scope == domain.TemplateScopeUser && template.UserID == caller.UserID:
+	case template.Scope == domain.TemplateScopeOrg && caller.OrgID != "" && template.OrgID == caller.OrgID:
+	default:
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrTemplateNotFound, id)
+	}
+	return template, nil
+}
+
+// Update replaces a template's name and settings. Its scope is fixed at
+// creation.
+func (s *AnalysisTemplateService) Update(ctx context.Context, caller TemplateCaller, id string, change domain.AnalysisTemplate) (*domain.AnalysisTemplate, error) {
+	template, err := s.manageable(ctx, caller, id)
+	if err != nil {
+		return nil, err
+	}
+	change.Scope = template.Scope
+	if err := change.Validate(); err != nil {
+		return nil, err
+	}
+
+	template.Name = change.Name
+	template.Settings = change.Settings
+	template.UpdatedAt = s.now()
+	if err := s.repo.Save(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// Delete removes a template the caller manages.
+func (s *AnalysisTemplateService) Delete(ctx context.Context, caller TemplateCaller, id string) error {
+	if _, err := s.manageable(ctx, caller, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Resolve returns the settings of a template the caller can use, for an
+// analysis request's template_id.
+func (s *AnalysisTemplateService) Resolve(ctx context.Context, caller TemplateCaller, id string) (*domain.TemplateSettings, error) {
+	template, err := s.Get(ctx, caller, id)
+	if err != nil {
+		return nil, err
+	}
+	return &template.Settings, nil
+}
+
+// manageable returns a template the caller may change: their own, or
+// their organization's when they edit its templates.
+func (s *AnalysisTemplateService) manageable(ctx context.Context, caller TemplateCaller, id string) (*domain.AnalysisTemplate, error) {
+	template, err := s.Get(ctx, caller, id)
+	if err != nil {
+		return nil, err
+	}
+	if template.Scope == domain.TemplateScopeOrg && !caller.OrgEditor {
+		return nil, fmt.Errorf("%w: organization templates need an organization admin or analyst", domain.ErrTemplateForbidden)
+	}
+	return template, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestAnalysisTemplateScopes(t *testing.T) {
+	templates := NewAnalysisTemplateService(memory.NewAnalysisTemplateRepository())
+	ctx := context.Background()
+	analyst := TemplateCaller{UserID: "ana", OrgID: "uni", OrgEditor: true}
+	member := TemplateCaller{UserID: "mem", OrgID: "uni"}
+	outsider := TemplateCaller{UserID: "out", OrgID: "other"}
+
+	evidence := true
+	private, err := templates.Create(ctx, analyst, domain.AnalysisTemplate{Name: " Hindi chunks ", Settings: domain.TemplateSettings{Truncation: "CHUNK", Language: "hi"}})
+	if err != nil {
+		t.Fatalf("Create(user) error = %v", err)
+	}
+	if private.Scope != domain.TemplateScopeUser || private.UserID != "ana" || private.Name != "Hindi chunks" || private.Settings.Truncation != "chunk" {
+		t.Errorf("Create(user) = %+v", private)
+	}
+	shared, err := templates.Create(ctx, analyst, domain.AnalysisTemplate{Name: "Evidence v2", Scope: domain.TemplateScopeOrg, Settings: domain.TemplateSettings{Model: "v2", IncludeEvidence: &evidence}})
+	if err != nil {
+		t.Fatalf("Create(org) error = %v", err)
+	}
+
+	// Members use the organization's templates but cannot change them.
+	if _, err := templates.Resolve(ctx, member, shared.ID); err != nil {
+		t.Errorf("member Resolve(org) error = %v", err)
+	}
+	if _, err := templates.Resolve(ctx, member, private.ID); !errors.Is(err, domain.ErrTemplateNotFound) {
+		t.Errorf("member Resolve(private) error = %v, want not found", err)
+	}
+	if _, err := templates.Resolve(ctx, outsider, shared.ID); !errors.Is(err, domain.ErrTemplateNotFound) {
+		t.Errorf("outsider Resolve(org) error = %v, want not found", err)
+	}
+	if err := templates.Delete(ctx, member, shared.ID); !errors.Is(err, domain.ErrTemplateForbidden) {
+		t.Errorf("member Delete(org) error = %v, want forbidden", err)
+	}
+	if _, err := templates.Create(ctx, member, domain.AnalysisTemplate{Name: "x", Scope: domain.TemplateScopeOrg}); !errors.Is(err, domain.ErrTemplateForbidden) {
+		t.Errorf("member Create(org) error = %v, want forbidden", err)
+	}
+
+	if list, _ := templates.List(ctx, member); len(list) != 1 || list[0].ID != shared.ID {
+		t.Errorf("member List() = %v, want the shared template", list)
+	}
+	if list, _ := templates.List(ctx, analyst); len(list) != 2 {
+		t.Errorf("analyst List() = %v, want both templates", list)
+	}
+
+	updated, err := templates.Update(ctx, analyst, shared.ID, domain.AnalysisTemplate{Name: "Evidence v3", Scope: domain.TemplateScopeUser, Settings: domain.TemplateSettings{Model: "v3"}})
+	if err != nil || updated.Scope != domain.TemplateScopeOrg || updated.Settings.Model != "v3" {
+		t.Errorf("Update() = %+v, %v; want the scope kept", updated, err)
+	}
+}
+
+func TestAnalysisTemplateValidation(t *testing.T) {
+	templates := NewAnalysisTemplateService(memory.NewAnalysisTemplateRepository()).WithMaxTemplates(1)
+	ctx := context.Background()
+	caller := TemplateCaller{UserID: "u"}
+	depth := 2
+
+	for name, tmpl := range map[string]domain.AnalysisTemplate{
+		"no name":    {},
+		"scope":      {Name: "x", Scope: "team"},
+		"truncation": {Name: "x", Settings: domain.TemplateSettings{Truncation: "middle"}},
+		"depth":      {Name: "x", Settings: domain.TemplateSettings{Depth: &depth}},
+		"language":   {Name: "x", Settings: domain.TemplateSettings{Language: "hindi"}},
+	} {
+		if _, err := templates.Create(ctx, caller, tmpl); !errors.Is(err, domain.ErrInvalidTemplate) {
+			t.Errorf("%s: error = %v, want ErrInvalidTemplate", name, err)
+		}
+	}
+
+	if _, err := templates.Create(ctx, caller, domain.AnalysisTemplate{Name: "one"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := templates.Create(ctx, caller, domain.AnalysisTemplate{Name: "two"}); !errors.Is(err, domain.ErrTemplateLimitReached) {
+		t.Errorf("error = %v, want ErrTemplateLimitReached", err)
+	}
+}
+
+func TestTemplateSettingsApply(t *testing.T) {
+	on, depth := true, 1
+	settings := domain.TemplateSettings{Truncation: "chunk", Depth: &depth, IncludeEvidence: &on, Model: "v2", Language: "hi"}
+
+	req := domain.AnalysisRequest{Type: "url", Content: "https://example.com/a", Truncation: "head"}
+	settings.Apply(&req, map[string]bool{"truncation": true})
+	if req.Truncation != "head" || req.Depth != 1 || !req.IncludeEvidence || req.Model != "v2" || req.Language != "hi" {
+		t.Errorf("Apply() = %+v; want the request's truncation kept and the rest filled in", req)
+	}
+
+	text := domain.AnalysisRequest{Type: "text", Content: "x"}
+	settings.Apply(&text, nil)
+	if text.Depth != 0 {
+		t.Errorf("Apply() set depth %d on a text request", text.Depth)
+	}
+}
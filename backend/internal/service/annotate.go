@@ -0,0 +1,121 @@
+package service
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// minPassageIntensity is the least intensity a sentence needs to be marked.
+const minPassageIntensity = 0.15
+
+// AnnotatePrediction renders a prediction's stored text as HTML paragraphs
+// and wraps suspicious sentences in
+//
+//	<mark class="suspicious" data-intensity="0.50" data-reasons="clickbait">
+//
+// Sentences are scored on the writing style markers of the heuristic
+// signal and on claims the retrieved evidence refutes. URL analyses only
+// keep the article's title, description and claims, so only those are
+// annotated.
+func AnnotatePrediction(p *domain.Prediction) *domain.Annotation {
+	annotation := &domain.Annotation{
+		PredictionID: p.ID,
+		Result:       p.Result,
+		Confidence:   p.Confidence,
+		Passages:     []domain.AnnotatedPassage{},
+	}
+
+	var paragraphs []string
+	if p.RequestType == "url" {
+		annotation.Source = domain.AnnotationSourceExcerpt
+		paragraphs = append(paragraphs, p.ArticleTitle, p.ArticleDescription)
+		for _, claim := range p.Claims {
+			paragraphs = append(paragraphs, claim.Text)
+		}
+	} else {
+		annotation.Source = domain.AnnotationSourceText
+		paragraphs = strings.Split(p.OriginalContent, "\n")
+	}
+
+	refuted := make(map[string]bool)
+	for _, claim := range p.Claims {
+		if isRefuted(claim) {
+			refuted[strings.Join(strings.Fields(claim.Text), " ")] = true
+		}
+	}
+
+	var b strings.Builder
+	index := 0
+	for _, paragraph := range paragraphs {
+		paragraph = strings.Join(strings.Fields(paragraph), " ")
+		if paragraph == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		for i, sentence := range splitSentences(paragraph) {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			intensity, reasons := scorePassage(sentence, refuted[sentence])
+			if intensity < minPassageIntensity {
+				b.WriteString(html.EscapeString(sentence))
+				continue
+			}
+			annotation.Passages = append(annotation.Passages, domain.AnnotatedPassage{
+				Paragraph: index,
+				Text:      sentence,
+				Intensity: intensity,
+				Reasons:   reasons,
+			})
+			fmt.Fprintf(&b, `<mark class="suspicious" data-intensity="%.2f" data-reasons="%s">%s</mark>`,
+				intensity, strings.Join(reasons, " "), html.EscapeString(sentence))
+		}
+		b.WriteString("</p>\n")
+		index++
+	}
+	annotation.HTML = b.String()
+	return annotation
+}
+
+// scorePassage scores one sentence from 0 (clean) to 1.
+func scorePassage(sentence string, refuted bool) (float64, []string) {
+	phrases, capsRatio, exclaimRatio := styleMarkers(sentence)
+	var score float64
+	reasons := []string{}
+	if phrases > 0 {
+		score += 0.35 * math.Min(float64(phrases), 2)
+		reasons = append(reasons, domain.ReasonClickbait)
+	}
+	if capsRatio >= 0.3 {
+		score += 0.25
+		reasons = append(reasons, domain.ReasonShouting)
+	}
+	if exclaimRatio > 0 {
+		score += 0.15
+		reasons = append(reasons, domain.ReasonExclamation)
+	}
+	if refuted {
+		score += 0.4
+		reasons = append(reasons, domain.ReasonRefutedClaim)
+	}
+	return math.Round(math.Min(score, 1)*100) / 100, reasons
+}
+
+// isRefuted reports whether more of a claim's evidence refutes it than
+// supports it.
+func isRefuted(claim domain.Claim) bool {
+	balance := 0
+	for _, ev := range claim.Evidence {
+		switch ev.Stance {
+		case domain.StanceRefutes:
+			balance++
+		case domain.StanceSupports:
+			balance--
+		}
+	}
+	return balance > 0
+}
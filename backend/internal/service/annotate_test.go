@@ -0,0 +1,77 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+func TestAnnotatePrediction(t *testing.T) {
+	p := &domain.Prediction{
+		ID:          "p1",
+		RequestType: "text",
+		Result:      domain.LabelFake,
+		Confidence:  0.9,
+		OriginalContent: "The council met on Tuesday. You won't believe what happened next!\n\n" +
+			"Officials said the vote was 9-4. THE MEDIA IS HIDING <THIS> FROM YOU.",
+	}
+
+	a := AnnotatePrediction(p)
+	if a.Source != domain.AnnotationSourceText || a.Result != domain.LabelFake {
+		t.Errorf("source = %q, result = %q", a.Source, a.Result)
+	}
+	if len(a.Passages) != 2 {
+		t.Fatalf("passages = %+v, want 2", a.Passages)
+	}
+	first, second := a.Passages[0], a.Passages[1]
+	if first.Paragraph != 0 || !reflect.DeepEqual(first.Reasons, []string{domain.ReasonClickbait, domain.ReasonExclamation}) || first.Intensity != 0.5 {
+		t.Errorf("first passage = %+v", first)
+	}
+	if second.Paragraph != 1 || !reflect.DeepEqual(second.Reasons, []string{domain.ReasonShouting}) {
+		t.Errorf("second passage = %+v", second)
+	}
+
+	wantHTML := "<p>The council met on Tuesday. " +
+		`<mark class="suspicious" data-intensity="0.50" data-reasons="clickbait exclamation">You won&#39;t believe what happened next!</mark></p>` + "\n" +
+		"<p>Officials said the vote was 9-4. " +
+		`<mark class="suspicious" data-intensity="0.25" data-reasons="shouting">THE MEDIA IS HIDING &lt;THIS&gt; FROM YOU.</mark></p>` + "\n"
+	if a.HTML != wantHTML {
+		t.Errorf("HTML =\n%s\nwant\n%s", a.HTML, wantHTML)
+	}
+}
+
+func TestAnnotateURLPredictionMarksRefutedClaims(t *testing.T) {
+	p := &domain.Prediction{
+		ID:                 "p2",
+		RequestType:        "url",
+		Result:             domain.LabelFake,
+		ArticleTitle:       "Moon made of cheese, say scientists",
+		ArticleDescription: "A new study claims the moon is dairy.",
+		Claims: []domain.Claim{
+			{Text: "Researchers at NASA  confirmed the moon is 90% cheese.", Evidence: []domain.Evidence{
+				{Stance: domain.StanceRefutes}, {Stance: domain.StanceRefutes}, {Stance: domain.StanceSupports},
+			}},
+			{Text: "The study was published in 2024 by Oxford.", Evidence: []domain.Evidence{
+				{Stance: domain.StanceSupports},
+			}},
+		},
+	}
+
+	a := AnnotatePrediction(p)
+	if a.Source != domain.AnnotationSourceExcerpt {
+		t.Errorf("source = %q, want excerpt", a.Source)
+	}
+	if len(a.Passages) != 1 {
+		t.Fatalf("passages = %+v, want only the refuted claim", a.Passages)
+	}
+	got := a.Passages[0]
+	if got.Paragraph != 2 || got.Text != "Researchers at NASA confirmed the moon is 90% cheese." ||
+		!reflect.DeepEqual(got.Reasons, []string{domain.ReasonRefutedClaim}) {
+		t.Errorf("passage = %+v", got)
+	}
+	if n := strings.Count(a.HTML, "<p>"); n != 4 {
+		t.Errorf("HTML has %d paragraphs, want 4:\n%s", n, a.HTML)
+	}
+}
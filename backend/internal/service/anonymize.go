@@ -0,0 +1,81 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// AnonymizerVersion names the anonymization rules below; it is recorded
+// in corpus manifests and must change whenever the rules do.
+const AnonymizerVersion = "v1"
+
+// scrubRules replace personal details in article text, in order.
+var scrubRules = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[EMAIL]"},
+	{regexp.MustCompile(`\+\d[\d\s().-]{7,}\d`), "[PHONE]"},
+	{regexp.MustCompile(`\(?\b\d{3,4}\)?[\s.-]?\d{3,4}[\s.-]?\d{4}\b`), "[PHONE]"},
+	{regexp.MustCompile(`(^|[^\w])@\w{2,}`), "$1[HANDLE]"},
+}
+
+// scrubText removes email addresses, phone numbers and social handles.
+func scrubText(text string) string {
+	for _, rule := range scrubRules {
+		text = rule.pattern.ReplaceAllString(text, rule.replacement)
+	}
+	return strings.TrimSpace(text)
+}
+
+// anonymizePrediction turns a stored prediction into a corpus record.
+// Caller identity, tracing IDs and pin state are dropped, the ID is
+// hashed, URLs lose their query strings and text is scrubbed. URL
+// predictions keep only the article's title and description, since the
+// article body is not stored.
+func anonymizePrediction(p *domain.Prediction) domain.CorpusRecord {
+	sum := sha256.Sum256([]byte("corpus:" + p.ID))
+	record := domain.CorpusRecord{
+		ID:           hex.EncodeToString(sum[:8]),
+		Label:        p.Result,
+		Confidence:   p.Confidence,
+		Date:         p.CreatedAt.UTC().Format("2006-01-02"),
+		RequestType:  p.RequestType,
+		ModelVersion: p.ModelVersion,
+	}
+	if p.RequestType == "url" {
+		record.Domain = normalizeDomain(p.ArticleSource)
+		record.URL = stripQuery(p.CanonicalURL)
+		if record.URL == "" {
+			record.URL = stripQuery(p.OriginalContent)
+		}
+		record.Title = scrubText(p.ArticleTitle)
+		record.Text = scrubText(strings.TrimSpace(p.ArticleTitle + "\n\n" + p.ArticleDescription))
+	} else {
+		record.Text = scrubText(p.OriginalContent)
+	}
+
+	record.Language = p.TranslatedFrom
+	if record.Language == "" {
+		record.Language = DetectLanguage(record.Text)
+	}
+	if record.Language == "" {
+		record.Language = "und"
+	}
+	return record
+}
+
+// stripQuery drops a URL's query string and fragment.
+func stripQuery(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	u.RawQuery, u.Fragment = "", ""
+	return u.String()
+}
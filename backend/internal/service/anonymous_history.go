@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/google/uuid"
+)
+
+// DefaultAnonymousSessionTTL is how long an anonymous visitor can find
+// their predictions.
+const DefaultAnonymousSessionTTL = 24 * time.Hour
+
+// anonymousSession is what an anonymous session token asserts.
+type anonymousSession struct {
+	ID        string    `json:"sid"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+// AnonymousHistory groups the predictions of a visitor who is not signed
+// in under a signed, self-contained session token, so they can list them
+// for a while and keep them when they sign in.
+type AnonymousHistory struct {
+	tokens *SessionTokens
+	news   *NewsService
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewAnonymousHistory creates an anonymous history from a server-side
+// secret. Sessions last ttl from their first analysis.
+func NewAnonymousHistory(secret string, ttl time.Duration, news *NewsService) *AnonymousHistory {
+	if ttl <= 0 {
+		ttl = DefaultAnonymousSessionTTL
+	}
+	return &AnonymousHistory{tokens: NewSessionTokens(secret, ttl), news: news, ttl: ttl, now: time.Now}
+}
+
+// Issue starts an anonymous session, returning its ID, its token and
+// when it expires.
+func (a *AnonymousHistory) Issue() (string, string, time.Time, error) {
+	session := anonymousSession{ID: uuid.New().String(), ExpiresAt: a.now().Add(a.ttl).UTC().Truncate(time.Second)}
+	token, err := a.tokens.seal("anonymous-session", session)
+	return session.ID, token, session.ExpiresAt, err
+}
+
+// Verify returns the ID of the session a token carries if it is authentic
+// and unexpired.
+func (a *AnonymousHistory) Verify(token string) (string, error) {
+	var session anonymousSession
+	if err := a.tokens.open("anonymous-session", token, &session); err != nil || session.ID == "" {
+		return "", domain.ErrInvalidSession
+	}
+	if !a.now().Before(session.ExpiresAt) {
+		return "", fmt.Errorf("%w: expired", domain.ErrInvalidSession)
+	}
+	return session.ID, nil
+}
+
+// Merge hands the predictions of the session a token carries to a user
+// who has just signed in, returning how many moved. An expired session's
+// predictions are not merged.
+func (a *AnonymousHistory) Merge(ctx context.Context, token, ownerID string) (int, error) {
+	sessionID, err := a.Verify(token)
+	if err != nil {
+		return 0, err
+	}
+	n, err := a.news.ClaimAnonymousHistory(ctx, sessionID, ownerID)
+	if n > 0 {
+		log.Printf("Merged %d anonymous predictions into user %s", n, ownerID)
+	}
+	return n, err
+}
+
+type anonymousSessionKey struct{}
+
+// ContextWithAnonymousSession records the anonymous session an analysis
+// is made for, when the caller is not signed in.
+func ContextWithAnonymousSession(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, anonymousSessionKey{}, sessionID)
+}
+
+// AnonymousSessionFromContext returns the session recorded by
+// ContextWithAnonymousSession, if any.
+func AnonymousSessionFromContext(ctx context.Context) string {
+	sessionID, _ := ctx.Value(anonymousSessionKey{}).(string)
+	return sessionID
+}
+
+// ClaimAnonymousHistory gives the predictions of an anonymous session to
+// an owner and detaches them from the session.
+func (s *NewsService) ClaimAnonymousHistory(ctx context.Context, sessionID, ownerID string) (int, error) {
+	if sessionID == "" || ownerID == "" {
+		return 0, nil
+	}
+	q := domain.NewPredictionQuery()
+	q.AnonymousSession = sessionID
+	var claimed []*domain.Prediction
+	err := s.repository.Iterate(ctx, *q, func(p *domain.Prediction) error {
+		claimed = append(claimed, p)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i, p := range claimed {
+		updated := *p
+		updated.OwnerID = ownerID
+		updated.AnonymousSession = ""
+		if err := s.repository.UpdatePrediction(&updated); err != nil {
+			return i, fmt.Errorf("failed to claim prediction %s: %w", p.ID, err)
+		}
+	}
+	return len(claimed), nil
+}
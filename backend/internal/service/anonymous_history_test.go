@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestAnonymousHistoryTokens(t *testing.T) {
+	history := NewAnonymousHistory("secret", time.Hour, nil)
+	sessionID, token, expires, err := history.Issue()
+	if err != nil || sessionID == "" || !expires.After(time.Now()) {
+		t.Fatalf("Issue = %q, %v, %v", sessionID, expires, err)
+	}
+	if got, err := history.Verify(token); err != nil || got != sessionID {
+		t.Errorf("Verify = %q, %v; want %q", got, err, sessionID)
+	}
+
+	other := NewAnonymousHistory("other-secret", time.Hour, nil)
+	if _, err := other.Verify(token); !errors.Is(err, domain.ErrInvalidSession) {
+		t.Errorf("token under another secret err = %v, want ErrInvalidSession", err)
+	}
+	// A sign-in session token is not an anonymous session token
+	signIn, _, _ := NewSessionTokens("secret", time.Hour).Issue(Session{UserID: "u-1", Role: domain.RoleMember})
+	if _, err := history.Verify(signIn); !errors.Is(err, domain.ErrInvalidSession) {
+		t.Errorf("sign-in token err = %v, want ErrInvalidSession", err)
+	}
+	history.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, err := history.Verify(token); !errors.Is(err, domain.ErrInvalidSession) {
+		t.Errorf("expired token err = %v, want ErrInvalidSession", err)
+	}
+}
+
+func TestAnonymousHistoryMergesOnSignIn(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(MLPredictionResponse{Result: "REAL", Confidence: 0.8, FakeProbability: 0.2, RealProbability: 0.8})
+	}))
+	defer srv.Close()
+	repo := memory.NewPredictionRepository()
+	news := NewNewsService(NewMLClient(srv.URL), NewScraperService(), repo)
+	history := NewAnonymousHistory("secret", time.Hour, news)
+
+	sessionID, token, _, err := history.Issue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	anonymous := ContextWithAnonymousSession(context.Background(), sessionID)
+	text := func(content string) *domain.AnalysisRequest {
+		return &domain.AnalysisRequest{Type: "text", Content: content}
+	}
+	for _, content := range []string{"The council approved the budget.", "The bridge reopened on Monday."} {
+		if _, err := news.AnalyzeNews(anonymous, text(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := news.AnalyzeNews(context.Background(), text("Someone else's analysis.")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := news.AnalyzeNews(ContextWithOwner(anonymous, "signed-in"), text("Owned analysis.")); err != nil {
+		t.Fatal(err)
+	}
+
+	q := domain.NewPredictionQuery()
+	q.AnonymousSession = sessionID
+	if session, _ := news.QueryHistory(context.Background(), q); len(session) != 2 {
+		t.Fatalf("session history = %d predictions, want 2", len(session))
+	}
+
+	n, err := history.Merge(context.Background(), token, "user-1")
+	if err != nil || n != 2 {
+		t.Fatalf("Merge = %d, %v; want 2", n, err)
+	}
+	if session, _ := news.QueryHistory(context.Background(), q); len(session) != 0 {
+		t.Errorf("session history after merge = %d predictions, want 0", len(session))
+	}
+	owned := domain.NewPredictionQuery()
+	owned.OwnerID = "user-1"
+	if mine, _ := news.QueryHistory(context.Background(), owned); len(mine) != 2 {
+		t.Errorf("user history after merge = %d predictions, want 2", len(mine))
+	}
+}
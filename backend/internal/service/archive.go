@@ -0,0 +1,245 @@
+package service
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// archiveIndexFile maps prediction IDs to the segment holding them.
+const archiveIndexFile = "index.json"
+
+// maxArchiveRecord bounds one archived prediction's JSON line.
+const maxArchiveRecord = 16 << 20
+
+type archiveIndex struct {
+	Segments []string          `json:"segments"`
+	Records  map[string]string `json:"records"` // prediction ID -> segment
+}
+
+// ArchiveStats describes the archive tier.
+type ArchiveStats struct {
+	Segments int   `json:"segments"`
+	Records  int   `json:"records"`
+	Bytes    int64 `json:"bytes"`
+}
+
+// FileArchive is the cold storage tier for old predictions: each sweep
+// writes one gzip-compressed JSONL segment to a directory, and an index
+// maps prediction IDs to segments. The directory can live on a mounted
+// bucket. Reads decompress a whole segment, so they are slower than
+// primary storage.
+type FileArchive struct {
+	dir string
+
+	mu    sync.RWMutex
+	index archiveIndex
+}
+
+// NewFileArchive opens the archive in dir, creating it if needed.
+func NewFileArchive(dir string) (*FileArchive, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	a := &FileArchive{dir: dir, index: archiveIndex{Records: make(map[string]string)}}
+	data, err := os.ReadFile(filepath.Join(dir, archiveIndexFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive index: %w", err)
+	}
+	if err := json.Unmarshal(data, &a.index); err != nil {
+		return nil, fmt.Errorf("failed to parse archive index in %s: %w", dir, err)
+	}
+	if a.index.Records == nil {
+		a.index.Records = make(map[string]string)
+	}
+	return a, nil
+}
+
+// Put writes predictions to a new segment and indexes them. Nothing is
+// indexed unless the whole segment was written.
+func (a *FileArchive) Put(predictions []*domain.Prediction) error {
+	if len(predictions) == 0 {
+		return nil
+	}
+	segment := fmt.Sprintf("predictions-%s.jsonl.gz", time.Now().UTC().Format("20060102T150405.000000000"))
+	if err := a.writeAtomic(segment, func(w io.Writer) error {
+		gz := gzip.NewWriter(w)
+		enc := json.NewEncoder(gz)
+		for _, p := range predictions {
+			if err := enc.Encode(p); err != nil {
+				return err
+			}
+		}
+		return gz.Close()
+	}); err != nil {
+		return fmt.Errorf("failed to write archive segment: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	next := archiveIndex{
+		Segments: append(append([]string{}, a.index.Segments...), segment),
+		Records:  make(map[string]string, len(a.index.Records)+len(predictions)),
+	}
+	for id, s := range a.index.Records {
+		next.Records[id] = s
+	}
+	for _, p := range predictions {
+		next.Records[p.ID] = segment
+	}
+	data, err := json.Marshal(next)
+	if err == nil {
+		err = a.writeAtomic(archiveIndexFile, func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		})
+	}
+	if err != nil {
+		os.Remove(filepath.Join(a.dir, segment))
+		return fmt.Errorf("failed to save archive index: %w", err)
+	}
+	a.index = next
+	return nil
+}
+
+// Get reads an archived prediction.
+func (a *FileArchive) Get(id string) (*domain.Prediction, error) {
+	a.mu.RLock()
+	segment, ok := a.index.Records[id]
+	a.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not archived", domain.ErrPredictionNotFound, id)
+	}
+
+	f, err := os.Open(filepath.Join(a.dir, segment))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive segment: %w", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive segment %s: %w", segment, err)
+	}
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64*1024), maxArchiveRecord)
+	for scanner.Scan() {
+		var p domain.Prediction
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			return nil, fmt.Errorf("failed to parse archive segment %s: %w", segment, err)
+		}
+		if p.ID == id {
+			return &p, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read archive segment %s: %w", segment, err)
+	}
+	return nil, fmt.Errorf("%w: %s is missing from %s", domain.ErrPredictionNotFound, id, segment)
+}
+
+// Stats returns the number of segments and records and their total size.
+func (a *FileArchive) Stats() ArchiveStats {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	stats := ArchiveStats{Segments: len(a.index.Segments), Records: len(a.index.Records)}
+	for _, segment := range a.index.Segments {
+		if info, err := os.Stat(filepath.Join(a.dir, segment)); err == nil {
+			stats.Bytes += info.Size()
+		}
+	}
+	return stats
+}
+
+// writeAtomic writes name through a temporary file so a crash never
+// leaves a truncated segment or index behind.
+func (a *FileArchive) writeAtomic(name string, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(a.dir, ".archive-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(a.dir, name))
+}
+
+// Archiver moves predictions older than maxAge to the archive tier,
+// leaving an ArchiveSummary row in primary storage so history and stats
+// still see them. Pinned predictions stay in primary storage.
+type Archiver struct {
+	repository NewsRepository
+	archive    *FileArchive
+	maxAge     time.Duration
+	now        func() time.Time
+}
+
+// NewArchiver creates an archiver for predictions older than maxAge.
+func NewArchiver(repo NewsRepository, archive *FileArchive, maxAge time.Duration) *Archiver {
+	return &Archiver{repository: repo, archive: archive, maxAge: maxAge, now: time.Now}
+}
+
+// Sweep archives old, unpinned predictions and returns how many were
+// moved.
+func (a *Archiver) Sweep(ctx context.Context) (int, error) {
+	q := domain.NewPredictionQuery().WithDateRange(time.Time{}, a.now().Add(-a.maxAge))
+	old, err := a.repository.Query(ctx, *q)
+	if err != nil {
+		return 0, err
+	}
+	var batch []*domain.Prediction
+	for _, p := range old {
+		if !p.Pinned && !p.Archived {
+			batch = append(batch, p)
+		}
+	}
+	if err := a.archive.Put(batch); err != nil {
+		return 0, err
+	}
+
+	archived := 0
+	for _, p := range batch {
+		if err := a.repository.UpdatePrediction(p.ArchiveSummary()); err != nil {
+			log.Printf("Warning: archive failed to replace prediction %s with its summary: %v", p.ID, err)
+			continue
+		}
+		archived++
+	}
+	return archived, nil
+}
+
+// Run sweeps every interval until ctx is cancelled.
+func (a *Archiver) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := a.Sweep(ctx); err != nil {
+				log.Printf("Warning: archive sweep failed: %v", err)
+			} else if n > 0 {
+				log.Printf("Archived %d old predictions", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestArchiverMovesOldPredictions(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewPredictionRepository()
+	for _, p := range []*domain.Prediction{
+		{ID: "old", RequestType: "text", OriginalContent: "Long article text", Result: domain.LabelFake, Summary: "A summary",
+			Claims: []domain.Claim{{Text: "claim"}}, CreatedAt: now.AddDate(0, 0, -100)},
+		{ID: "old-pinned", CreatedAt: now.AddDate(0, 0, -100), Pinned: true, PinnedBy: "alice"},
+		{ID: "recent", CreatedAt: now.AddDate(0, 0, -5)},
+	} {
+		if err := repo.CreatePrediction(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dir := t.TempDir()
+	archive, err := NewFileArchive(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archiver := NewArchiver(repo, archive, 90*24*time.Hour)
+	archiver.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if n, err := archiver.Sweep(ctx); err != nil || n != 1 {
+		t.Fatalf("Sweep() = %d, %v, want 1", n, err)
+	}
+	if n, err := archiver.Sweep(ctx); err != nil || n != 0 {
+		t.Errorf("second Sweep() = %d, %v, want nothing left to archive", n, err)
+	}
+	if stats := archive.Stats(); stats.Segments != 1 || stats.Records != 1 || stats.Bytes == 0 {
+		t.Errorf("Stats() = %+v, want one record in one segment", stats)
+	}
+
+	// The summary row stays queryable without the heavy fields.
+	summary, err := repo.GetPredictionByID("old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !summary.Archived || summary.Result != domain.LabelFake || summary.OriginalContent != "" || summary.Summary != "" || summary.Claims != nil {
+		t.Errorf("summary row = %+v", summary)
+	}
+	for _, id := range []string{"old-pinned", "recent"} {
+		if p, _ := repo.GetPredictionByID(id); p.Archived {
+			t.Errorf("%s was archived", id)
+		}
+	}
+
+	// Reads fall back to the archive, reopened from disk, and keep the
+	// review recorded on the summary row afterwards.
+	reopened, err := NewFileArchive(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	news := NewNewsService(nil, nil, repo).WithArchive(reopened)
+	if _, err := news.ReviewPrediction(ctx, "old", summary.Version(), domain.Review{Verdict: "fake", Claim: "claim", Reviewer: "ana"}); err != nil {
+		t.Fatalf("ReviewPrediction(archived) error = %v", err)
+	}
+	full, err := news.GetPrediction("old")
+	if err != nil {
+		t.Fatalf("GetPrediction(archived) error = %v", err)
+	}
+	if full.OriginalContent != "Long article text" || full.Summary != "A summary" || len(full.Claims) != 1 || full.Review == nil || !full.Archived {
+		t.Errorf("GetPrediction(archived) = %+v, want the full record with its review", full)
+	}
+	if full.Version() != summary.Version() {
+		t.Error("the archived record and its summary have different versions")
+	}
+
+	// Records removed from primary storage are still served.
+	if err := repo.DeletePrediction("old"); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := news.GetPrediction("old"); err != nil || p.Summary != "A summary" {
+		t.Errorf("GetPrediction(deleted) = %+v, %v, want the archived record", p, err)
+	}
+	if _, err := news.GetPrediction("missing"); !errors.Is(err, domain.ErrPredictionNotFound) {
+		t.Errorf("GetPrediction(missing) error = %v, want ErrPredictionNotFound", err)
+	}
+}
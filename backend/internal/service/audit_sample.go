@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Audit sampling limits
+const (
+	DefaultAuditSampleSize = 50
+	MaxAuditSampleSize     = 500
+	DefaultAuditDays       = 7
+	MaxAuditDays           = 90
+	maxAuditSamples        = 100 // recorded samples kept; older ones are dropped
+)
+
+// AuditSampler draws reproducible random samples of recent predictions for
+// manual quality audits and records which predictions have been audited,
+// so later samples skip them. Samples and audit records live in memory.
+type AuditSampler struct {
+	predictions NewsRepository
+	now         func() time.Time
+
+	mu      sync.Mutex
+	samples []*domain.AuditSample // oldest first
+	audited map[string]time.Time  // prediction ID -> when it was audited
+}
+
+// NewAuditSampler creates a sampler over predictions.
+func NewAuditSampler(predictions NewsRepository) *AuditSampler {
+	return &AuditSampler{
+		predictions: predictions,
+		now:         time.Now,
+		audited:     make(map[string]time.Time),
+	}
+}
+
+// Sample draws and records a sample of the model verdicts made in the last
+// req.Days days that have not been audited yet. Stratified samples draw
+// round-robin across label/confidence-decile strata, so rare strata such
+// as low-confidence REAL verdicts are represented; each item's weight says
+// how many candidates it stands for. Candidates are shuffled with req.Seed.
+func (s *AuditSampler) Sample(ctx context.Context, req domain.AuditSampleRequest) (*domain.AuditSample, error) {
+	if req.Size == 0 {
+		req.Size = DefaultAuditSampleSize
+	}
+	if req.Days == 0 {
+		req.Days = DefaultAuditDays
+	}
+	if req.Strategy == "" {
+		req.Strategy = domain.AuditStrategyStratified
+	}
+	switch {
+	case req.Size < 0 || req.Size > MaxAuditSampleSize:
+		return nil, fmt.Errorf("%w: n must be between 1 and %d", domain.ErrInvalidAuditSample, MaxAuditSampleSize)
+	case req.Days < 0 || req.Days > MaxAuditDays:
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", domain.ErrInvalidAuditSample, MaxAuditDays)
+	case req.Strategy != domain.AuditStrategyStratified && req.Strategy != domain.AuditStrategyUniform:
+		return nil, fmt.Errorf("%w: strategy must be stratified or uniform", domain.ErrInvalidAuditSample)
+	}
+
+	now := s.now().UTC()
+	q := domain.NewPredictionQuery()
+	q.Since = now.AddDate(0, 0, -req.Days)
+	predictions, err := s.predictions.Query(ctx, *q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load predictions: %w", err)
+	}
+
+	s.mu.Lock()
+	strata := make(map[string][]domain.AuditSampleItem)
+	for _, p := range predictions {
+		if _, ok := s.audited[p.ID]; ok || !auditEligible(p) {
+			continue
+		}
+		item := domain.AuditSampleItem{
+			PredictionID: p.ID,
+			Result:       p.Result,
+			Confidence:   p.Confidence,
+			Stratum:      auditStratum(p),
+		}
+		strata[item.Stratum] = append(strata[item.Stratum], item)
+	}
+	s.mu.Unlock()
+
+	sample := &domain.AuditSample{
+		ID:        "audit-" + uuid.New().String(),
+		CreatedAt: now,
+		Request:   req,
+		Since:     q.Since,
+		Strata:    make(map[string]int, len(strata)),
+	}
+	for key, items := range strata {
+		sample.Strata[key] = len(items)
+		sample.Candidates += len(items)
+	}
+
+	rng := rand.New(rand.NewSource(req.Seed))
+	if req.Strategy == domain.AuditStrategyUniform {
+		sample.Items = drawUniform(rng, strata, req.Size)
+	} else {
+		sample.Items = drawStratified(rng, strata, req.Size)
+	}
+	// Interleave strata so auditors do not see one label in a run.
+	rng.Shuffle(len(sample.Items), func(i, j int) {
+		sample.Items[i], sample.Items[j] = sample.Items[j], sample.Items[i]
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.samples = append(s.samples, sample)
+	if len(s.samples) > maxAuditSamples {
+		s.samples = s.samples[len(s.samples)-maxAuditSamples:]
+	}
+	return copyAuditSample(sample), nil
+}
+
+// MarkAudited records that auditor checked the given predictions of a
+// sample, or all of them when predictionIDs is empty.
+func (s *AuditSampler) MarkAudited(id, auditor string, predictionIDs []string) (*domain.AuditSample, error) {
+	now := s.now().UTC()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sample := s.find(id)
+	if sample == nil {
+		return nil, domain.ErrAuditSampleNotFound
+	}
+
+	marked := make(map[string]bool, len(predictionIDs))
+	for _, pid := range predictionIDs {
+		marked[pid] = true
+	}
+	for pid := range marked {
+		found := false
+		for _, item := range sample.Items {
+			found = found || item.PredictionID == pid
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: prediction %s is not in the sample", domain.ErrInvalidAuditSample, pid)
+		}
+	}
+
+	for i := range sample.Items {
+		item := &sample.Items[i]
+		if item.AuditedAt != nil || (len(marked) > 0 && !marked[item.PredictionID]) {
+			continue
+		}
+		at := now
+		item.AuditedBy = auditor
+		item.AuditedAt = &at
+		sample.Audited++
+		s.audited[item.PredictionID] = now
+	}
+	return copyAuditSample(sample), nil
+}
+
+// Get returns a recorded sample.
+func (s *AuditSampler) Get(id string) (*domain.AuditSample, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sample := s.find(id)
+	if sample == nil {
+		return nil, domain.ErrAuditSampleNotFound
+	}
+	return copyAuditSample(sample), nil
+}
+
+// List returns the recorded samples, newest first, without their items.
+func (s *AuditSampler) List() []domain.AuditSample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	samples := make([]domain.AuditSample, 0, len(s.samples))
+	for i := len(s.samples) - 1; i >= 0; i-- {
+		summary := *s.samples[i]
+		summary.Items = nil
+		samples = append(samples, summary)
+	}
+	return samples
+}
+
+// find returns the sample with id. Callers hold s.mu.
+func (s *AuditSampler) find(id string) *domain.AuditSample {
+	for _, sample := range s.samples {
+		if sample.ID == id {
+			return sample
+		}
+	}
+	return nil
+}
+
+// auditEligible reports whether p is a settled model verdict worth
+// auditing. Allowlist and blocklist answers were never scored.
+func auditEligible(p *domain.Prediction) bool {
+	if p.Provisional || (p.Method != "" && p.Method != domain.MethodModel) {
+		return false
+	}
+	return p.Result == domain.LabelFake || p.Result == domain.LabelReal
+}
+
+// auditStratum places p by label and confidence decile.
+func auditStratum(p *domain.Prediction) string {
+	decile := math.Min(math.Floor(p.Confidence*10), 9) / 10
+	return fmt.Sprintf("%s|%.1f", p.Result, decile)
+}
+
+// drawStratified draws n items round-robin across shuffled strata and
+// weights each by its stratum's size over the items drawn from it.
+func drawStratified(rng *rand.Rand, strata map[string][]domain.AuditSampleItem, n int) []domain.AuditSampleItem {
+	keys := sortedKeys(strata)
+	rng.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+	queues := make([][]domain.AuditSampleItem, len(keys))
+	for i, key := range keys {
+		queues[i] = shuffledItems(rng, strata[key])
+	}
+
+	drawn := make([]int, len(keys))
+	var sample []domain.AuditSampleItem
+	for progress := true; progress && len(sample) < n; {
+		progress = false
+		for i := range queues {
+			if len(sample) == n {
+				break
+			}
+			if drawn[i] < len(queues[i]) {
+				sample = append(sample, queues[i][drawn[i]])
+				drawn[i]++
+				progress = true
+			}
+		}
+	}
+	perStratum := make(map[string]int, len(keys))
+	for i, key := range keys {
+		perStratum[key] = drawn[i]
+	}
+	for i := range sample {
+		key := sample[i].Stratum
+		sample[i].Weight = float64(len(strata[key])) / float64(perStratum[key])
+	}
+	return sample
+}
+
+// drawUniform draws n items with equal probability, ignoring strata.
+func drawUniform(rng *rand.Rand, strata map[string][]domain.AuditSampleItem, n int) []domain.AuditSampleItem {
+	var all []domain.AuditSampleItem
+	for _, key := range sortedKeys(strata) {
+		all = append(all, strata[key]...)
+	}
+	all = shuffledItems(rng, all)
+	sample := all[:min(n, len(all))]
+	for i := range sample {
+		sample[i].Weight = float64(len(all)) / float64(len(sample))
+	}
+	return sample
+}
+
+// shuffledItems returns a seeded shuffle of items, sorted first so the
+// result does not depend on repository order.
+func shuffledItems(rng *rand.Rand, items []domain.AuditSampleItem) []domain.AuditSampleItem {
+	shuffled := append([]domain.AuditSampleItem(nil), items...)
+	sort.Slice(shuffled, func(i, j int) bool { return shuffled[i].PredictionID < shuffled[j].PredictionID })
+	rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	return shuffled
+}
+
+func copyAuditSample(sample *domain.AuditSample) *domain.AuditSample {
+	copied := *sample
+	copied.Items = append([]domain.AuditSampleItem(nil), sample.Items...)
+	copied.Strata = make(map[string]int, len(sample.Strata))
+	for k, v := range sample.Strata {
+		copied.Strata[k] = v
+	}
+	return &copied
+}
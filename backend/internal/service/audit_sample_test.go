@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestAuditSampler(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	add := func(p *domain.Prediction) {
+		t.Helper()
+		if err := repo.CreatePrediction(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 20 confident FAKE verdicts and 2 uncertain REAL ones.
+	for i := 0; i < 20; i++ {
+		add(&domain.Prediction{ID: fmt.Sprintf("fake-%02d", i), Result: domain.LabelFake, Confidence: 0.95, CreatedAt: now.Add(-time.Hour)})
+	}
+	for i := 0; i < 2; i++ {
+		add(&domain.Prediction{ID: fmt.Sprintf("real-%d", i), Result: domain.LabelReal, Confidence: 0.55, CreatedAt: now.Add(-time.Hour)})
+	}
+	add(&domain.Prediction{ID: "old", Result: domain.LabelFake, Confidence: 0.9, CreatedAt: now.AddDate(0, 0, -30)})
+	add(&domain.Prediction{ID: "trusted", Result: domain.LabelReal, Confidence: 1, Method: domain.MethodTrustedSource, CreatedAt: now})
+	add(&domain.Prediction{ID: "provisional", Result: domain.LabelFake, Confidence: 0.9, Provisional: true, CreatedAt: now})
+
+	sampler := NewAuditSampler(repo)
+	sampler.now = func() time.Time { return now }
+	ctx := context.Background()
+	req := domain.AuditSampleRequest{Size: 6, Seed: 7}
+
+	first, err := sampler.Sample(ctx, req)
+	if err != nil {
+		t.Fatalf("Sample: %v", err)
+	}
+	if first.Candidates != 22 || first.Strata["FAKE|0.9"] != 20 || first.Strata["REAL|0.5"] != 2 {
+		t.Errorf("candidates = %d, strata = %v", first.Candidates, first.Strata)
+	}
+	weights := make(map[string]float64)
+	for _, item := range first.Items {
+		weights[item.Stratum] = item.Weight
+	}
+	// Both uncertain REAL verdicts make it in despite being rare.
+	if len(first.Items) != 6 || weights["REAL|0.5"] != 1 || weights["FAKE|0.9"] != 5 {
+		t.Errorf("items = %+v", first.Items)
+	}
+
+	again, err := sampler.Sample(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again.Items, first.Items) {
+		t.Errorf("same seed drew %+v, want %+v", again.Items, first.Items)
+	}
+
+	// Audited predictions are not drawn again.
+	marked, err := sampler.MarkAudited(first.ID, "alice", nil)
+	if err != nil {
+		t.Fatalf("MarkAudited: %v", err)
+	}
+	if marked.Audited != 6 || marked.Items[0].AuditedBy != "alice" || marked.Items[0].AuditedAt == nil {
+		t.Errorf("marked = %+v", marked)
+	}
+	uniform, err := sampler.Sample(ctx, domain.AuditSampleRequest{Size: 100, Strategy: domain.AuditStrategyUniform})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uniform.Candidates != 16 || len(uniform.Items) != 16 {
+		t.Errorf("after audit: candidates = %d, items = %d, want 16", uniform.Candidates, len(uniform.Items))
+	}
+	for _, item := range uniform.Items {
+		if item.Stratum == "REAL|0.5" {
+			t.Errorf("audited prediction %s drawn again", item.PredictionID)
+		}
+	}
+	if samples := sampler.List(); len(samples) != 3 || samples[0].ID != uniform.ID || samples[0].Items != nil {
+		t.Errorf("List = %+v", samples)
+	}
+
+	if _, err := sampler.MarkAudited(uniform.ID, "bob", []string{"real-0"}); !errors.Is(err, domain.ErrInvalidAuditSample) {
+		t.Errorf("marking a prediction outside the sample: err = %v", err)
+	}
+	if _, err := sampler.MarkAudited("audit-missing", "bob", nil); !errors.Is(err, domain.ErrAuditSampleNotFound) {
+		t.Errorf("unknown sample: err = %v", err)
+	}
+	for _, bad := range []domain.AuditSampleRequest{{Size: MaxAuditSampleSize + 1}, {Days: -1}, {Strategy: "weighted"}} {
+		if _, err := sampler.Sample(ctx, bad); !errors.Is(err, domain.ErrInvalidAuditSample) {
+			t.Errorf("Sample(%+v): err = %v, want ErrInvalidAuditSample", bad, err)
+		}
+	}
+}
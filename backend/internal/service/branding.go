@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// Branding assets served per source
+const (
+	BrandingFavicon = "favicon"
+	BrandingLogo    = "logo"
+)
+
+// Branding cache limits
+const (
+	brandingTTL          = 7 * 24 * time.Hour // fetched images are refreshed weekly
+	brandingRetry        = time.Hour          // failed fetches are retried hourly
+	brandingFetchTimeout = 10 * time.Second
+	maxBrandingSources   = 1000 // least recently seen sources are evicted past this
+)
+
+// brandingFetcher downloads images; the scraper does so under its URL policy.
+type brandingFetcher interface {
+	FetchImage(ctx context.Context, urlStr string) (contentType string, data []byte, err error)
+}
+
+type brandingImage struct {
+	remoteURL   string
+	contentType string
+	data        []byte
+	fetchedAt   time.Time
+	failed      bool
+	fetching    bool
+}
+
+type brandingSource struct {
+	name   string
+	images map[string]*brandingImage // keyed by BrandingFavicon / BrandingLogo
+	seen   time.Time
+}
+
+// BrandingService keeps each source's favicon and publisher logo, found
+// while scraping, and caches copies of the images so history views and
+// reports can show them without fetching from the publisher.
+type BrandingService struct {
+	fetcher brandingFetcher
+	now     func() time.Time
+
+	mu      sync.Mutex
+	sources map[string]*brandingSource
+}
+
+// NewBrandingService creates a branding cache that fetches through fetcher.
+func NewBrandingService(fetcher brandingFetcher) *BrandingService {
+	return &BrandingService{
+		fetcher: fetcher,
+		now:     time.Now,
+		sources: make(map[string]*brandingSource),
+	}
+}
+
+// Record stores the branding found on a scraped page, starts fetching any
+// images not yet cached and returns the source's branding.
+func (b *BrandingService) Record(ctx context.Context, result *ScrapeResult) *domain.SourceInfo {
+	if b == nil || result == nil {
+		return nil
+	}
+	host := normalizeDomain(result.Source)
+	if host == "" {
+		return nil
+	}
+
+	b.mu.Lock()
+	source, ok := b.sources[host]
+	if !ok {
+		b.evict()
+		source = &brandingSource{images: make(map[string]*brandingImage)}
+		b.sources[host] = source
+	}
+	source.seen = b.now()
+	if result.SiteName != "" {
+		source.name = result.SiteName
+	}
+	var pending []string
+	for kind, remote := range map[string]string{BrandingFavicon: result.FaviconURL, BrandingLogo: result.LogoURL} {
+		if remote == "" {
+			continue
+		}
+		img, ok := source.images[kind]
+		if !ok || img.remoteURL != remote {
+			img = &brandingImage{remoteURL: remote}
+			source.images[kind] = img
+		}
+		if !img.fetching && !b.fresh(img) {
+			img.fetching = true
+			pending = append(pending, kind)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, kind := range pending {
+		go b.fetch(context.WithoutCancel(ctx), host, kind)
+	}
+	return b.Info(host)
+}
+
+// Info returns host's branding, or nil if none was recorded. Image URLs
+// are left out for images that failed to fetch and have no cached copy.
+func (b *BrandingService) Info(host string) *domain.SourceInfo {
+	if b == nil {
+		return nil
+	}
+	host = normalizeDomain(host)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	source, ok := b.sources[host]
+	if !ok {
+		return nil
+	}
+	info := &domain.SourceInfo{Domain: host, Name: source.name}
+	if img, ok := source.images[BrandingFavicon]; ok && (!img.failed || img.data != nil) {
+		info.FaviconURL = brandingPath(host, BrandingFavicon)
+	}
+	if img, ok := source.images[BrandingLogo]; ok && (!img.failed || img.data != nil) {
+		info.LogoURL = brandingPath(host, BrandingLogo)
+	}
+	return info
+}
+
+// Image returns host's cached favicon or logo, fetching it if it is not
+// cached yet.
+func (b *BrandingService) Image(ctx context.Context, host, kind string) (contentType string, data []byte, err error) {
+	host = normalizeDomain(host)
+	notFound := fmt.Errorf("%w: %s %s", domain.ErrBrandingNotFound, host, kind)
+
+	b.mu.Lock()
+	source, ok := b.sources[host]
+	var img *brandingImage
+	if ok {
+		img = source.images[kind]
+	}
+	if img == nil {
+		b.mu.Unlock()
+		return "", nil, notFound
+	}
+	if !b.fresh(img) {
+		b.mu.Unlock()
+		b.fetch(ctx, host, kind)
+		b.mu.Lock()
+		// The entry may have been replaced or evicted while fetching.
+		if source, ok = b.sources[host]; !ok || source.images[kind] == nil {
+			b.mu.Unlock()
+			return "", nil, notFound
+		}
+		img = source.images[kind]
+	}
+	defer b.mu.Unlock()
+	if img.data == nil {
+		return "", nil, notFound
+	}
+	return img.contentType, img.data, nil
+}
+
+// fetch downloads host's kind image and caches the outcome.
+func (b *BrandingService) fetch(ctx context.Context, host, kind string) {
+	b.mu.Lock()
+	source, ok := b.sources[host]
+	if !ok || source.images[kind] == nil {
+		b.mu.Unlock()
+		return
+	}
+	img := source.images[kind]
+	remote := img.remoteURL
+	b.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, brandingFetchTimeout)
+	defer cancel()
+	contentType, data, err := b.fetcher.FetchImage(ctx, remote)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	img.fetching = false
+	img.fetchedAt = b.now()
+	if err != nil {
+		log.Printf("Warning: failed to fetch %s %s: %v", host, kind, err)
+		img.failed = true
+		return
+	}
+	img.failed = false
+	img.contentType, img.data = contentType, data
+}
+
+// fresh reports whether img needs no refetch. Callers hold b.mu.
+func (b *BrandingService) fresh(img *brandingImage) bool {
+	if img.fetchedAt.IsZero() {
+		return false
+	}
+	ttl := brandingTTL
+	if img.failed {
+		ttl = brandingRetry
+	}
+	return b.now().Sub(img.fetchedAt) < ttl
+}
+
+// evict drops the least recently seen source once the cache is full.
+// Callers hold b.mu.
+func (b *BrandingService) evict() {
+	if len(b.sources) < maxBrandingSources {
+		return
+	}
+	var oldest string
+	for host, source := range b.sources {
+		if oldest == "" || source.seen.Before(b.sources[oldest].seen) {
+			oldest = host
+		}
+	}
+	delete(b.sources, oldest)
+}
+
+// brandingPath is the API path serving a source's image.
+func brandingPath(host, kind string) string {
+	return "/api/sources/" + host + "/" + kind
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/PuerkitoBio/goquery"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestExtractBranding(t *testing.T) {
+	base, _ := url.Parse("https://www.news.example/politics/story")
+	tests := []struct {
+		name                         string
+		html                         string
+		wantName, wantIcon, wantLogo string
+	}{
+		{
+			name:     "defaults to favicon.ico",
+			html:     `<html><head><title>x</title></head></html>`,
+			wantIcon: "https://www.news.example/favicon.ico",
+		},
+		{
+			name: "declared icon and JSON-LD logo object",
+			html: `<html><head>
+				<meta property="og:site_name" content="News Example">
+				<link rel="icon" href="/static/icon.png">
+				<script type="application/ld+json">{"@type":"NewsArticle","publisher":{"name":"NE","logo":{"@type":"ImageObject","url":"https://cdn.news.example/logo.png"}}}</script>
+			</head></html>`,
+			wantName: "News Example",
+			wantIcon: "https://www.news.example/static/icon.png",
+			wantLogo: "https://cdn.news.example/logo.png",
+		},
+		{
+			name: "logo string inside @graph names the publisher",
+			html: `<html><head>
+				<link rel="apple-touch-icon" href="//cdn.news.example/touch.png">
+				<script type="application/ld+json">not json</script>
+				<script type="application/ld+json">{"@graph":[{"@type":"WebPage"},{"publisher":{"name":"Graph News","logo":"/logo.png"}}]}</script>
+			</head></html>`,
+			wantName: "Graph News",
+			wantIcon: "https://cdn.news.example/touch.png",
+			wantLogo: "https://www.news.example/logo.png",
+		},
+		{
+			name:     "non-http icons are ignored",
+			html:     `<html><head><link rel="icon" href="data:image/png;base64,AAAA"></head></html>`,
+			wantIcon: "https://www.news.example/favicon.ico",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			name, icon, logo := extractBranding(doc, base)
+			if name != tt.wantName || icon != tt.wantIcon || logo != tt.wantLogo {
+				t.Errorf("extractBranding() = %q, %q, %q; want %q, %q, %q",
+					name, icon, logo, tt.wantName, tt.wantIcon, tt.wantLogo)
+			}
+		})
+	}
+}
+
+func TestScraperFetchImage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/icon.png":
+			w.Header().Set("Content-Type", "text/html") // the bytes decide
+			w.Write(pngHeader)
+		case "/page.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("<html><script>alert(1)</script></html>"))
+		case "/huge.png":
+			w.Write(append(pngHeader, make([]byte, MaxBrandingImageBytes)...))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	// The URL policy only allows standard ports, so send news.example to
+	// the test server.
+	scraper := NewScraperService()
+	scraper.httpClient = &http.Client{Transport: rewriteTransport{target: srv.URL}}
+
+	contentType, data, err := scraper.FetchImage(context.Background(), "http://news.example/icon.png")
+	if err != nil || contentType != "image/png" || len(data) != len(pngHeader) {
+		t.Errorf("FetchImage(icon) = %q, %d bytes, %v", contentType, len(data), err)
+	}
+	if _, _, err := scraper.FetchImage(context.Background(), "http://news.example/page.png"); !errors.Is(err, domain.ErrUnsupportedContentType) {
+		t.Errorf("FetchImage(page) error = %v, want ErrUnsupportedContentType", err)
+	}
+	if _, _, err := scraper.FetchImage(context.Background(), "http://news.example/huge.png"); !errors.Is(err, domain.ErrURLScrapingFailed) {
+		t.Errorf("FetchImage(huge) error = %v, want ErrURLScrapingFailed", err)
+	}
+	if _, _, err := scraper.FetchImage(context.Background(), "http://127.0.0.1/icon.png"); !errors.Is(err, domain.ErrInvalidURL) {
+		t.Errorf("FetchImage(loopback) error = %v, want ErrInvalidURL", err)
+	}
+}
+
+type fakeImageFetcher struct {
+	fetches atomic.Int32
+}
+
+func (f *fakeImageFetcher) FetchImage(_ context.Context, urlStr string) (string, []byte, error) {
+	f.fetches.Add(1)
+	if strings.HasSuffix(urlStr, "/logo.png") {
+		return "", nil, domain.ErrUnsupportedContentType
+	}
+	return "image/png", pngHeader, nil
+}
+
+func TestBrandingServiceCachesImages(t *testing.T) {
+	fetcher := &fakeImageFetcher{}
+	branding := NewBrandingService(fetcher)
+	info := branding.Record(context.Background(), &ScrapeResult{
+		Source:     "www.news.example",
+		SiteName:   "News Example",
+		FaviconURL: "https://www.news.example/favicon.ico",
+		LogoURL:    "https://www.news.example/logo.png",
+	})
+	if info == nil || info.Domain != "news.example" || info.Name != "News Example" ||
+		info.FaviconURL != "/api/sources/news.example/favicon" {
+		t.Fatalf("Record() = %+v", info)
+	}
+
+	// Wait for the background fetches started by Record.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		branding.mu.Lock()
+		done := true
+		for _, img := range branding.sources["news.example"].images {
+			done = done && !img.fetchedAt.IsZero()
+		}
+		branding.mu.Unlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("background fetches did not finish")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	contentType, data, err := branding.Image(context.Background(), "news.example", BrandingFavicon)
+	if err != nil || contentType != "image/png" || len(data) == 0 {
+		t.Errorf("Image(favicon) = %q, %d bytes, %v", contentType, len(data), err)
+	}
+	if _, _, err := branding.Image(context.Background(), "news.example", BrandingLogo); !errors.Is(err, domain.ErrBrandingNotFound) {
+		t.Errorf("Image(logo) error = %v, want ErrBrandingNotFound", err)
+	}
+	if info := branding.Info("news.example"); info.LogoURL != "" {
+		t.Errorf("Info().LogoURL = %q for a logo that failed to fetch", info.LogoURL)
+	}
+	if got := fetcher.fetches.Load(); got != 2 {
+		t.Errorf("fetches = %d, want 2 (cached images are not refetched)", got)
+	}
+	if _, _, err := branding.Image(context.Background(), "unknown.example", BrandingFavicon); !errors.Is(err, domain.ErrBrandingNotFound) {
+		t.Errorf("Image(unknown) error = %v, want ErrBrandingNotFound", err)
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Bulk delete limits
+const (
+	bulkDeletePreviewSize = 10  // matches shown by a dry run
+	maxBulkDeletes        = 100 // job records kept; older ones are dropped
+)
+
+// BulkDeleter removes the predictions matching history filters as
+// background jobs. Pinned predictions are kept. Job records, including
+// who asked, why and which predictions went, live in memory as the audit
+// trail.
+type BulkDeleter struct {
+	repository NewsRepository
+	now        func() time.Time
+
+	mu   sync.Mutex
+	jobs []*domain.BulkDelete // oldest first
+}
+
+// NewBulkDeleter creates a bulk deleter over repo.
+func NewBulkDeleter(repo NewsRepository) *BulkDeleter {
+	return &BulkDeleter{repository: repo, now: time.Now}
+}
+
+// Preview returns what a bulk delete with filters would remove, without
+// removing anything.
+func (d *BulkDeleter) Preview(ctx context.Context, filters domain.SearchFilters) (*domain.BulkDeletePreview, error) {
+	if err := domain.ValidateBulkDelete(filters); err != nil {
+		return nil, err
+	}
+	matched, err := d.repository.Query(ctx, *filters.Query())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query predictions: %w", err)
+	}
+	preview := &domain.BulkDeletePreview{Filters: filters, Sample: []*domain.Prediction{}}
+	for _, p := range matched {
+		preview.Matched++
+		if p.Pinned {
+			preview.Pinned++
+			continue
+		}
+		if len(preview.Sample) < bulkDeletePreviewSize {
+			preview.Sample = append(preview.Sample, p)
+		}
+	}
+	return preview, nil
+}
+
+// Start records a bulk delete job for the predictions matching filters now
+// and deletes them in the background. Predictions stored later are not
+// touched even if they match.
+func (d *BulkDeleter) Start(ctx context.Context, filters domain.SearchFilters, requestedBy, reason string) (*domain.BulkDelete, error) {
+	if err := domain.ValidateBulkDelete(filters); err != nil {
+		return nil, err
+	}
+	matched, err := d.repository.Query(ctx, *filters.Query())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query predictions: %w", err)
+	}
+
+	job := &domain.BulkDelete{
+		ID:          "bulk-delete-" + uuid.New().String(),
+		Status:      domain.BulkDeleteRunning,
+		Filters:     filters,
+		RequestedBy: requestedBy,
+		Reason:      reason,
+		Matched:     len(matched),
+		CreatedAt:   d.now().UTC(),
+	}
+	d.mu.Lock()
+	d.jobs = append(d.jobs, job)
+	if len(d.jobs) > maxBulkDeletes {
+		d.jobs = d.jobs[len(d.jobs)-maxBulkDeletes:]
+	}
+	snapshot := copyBulkDelete(job)
+	d.mu.Unlock()
+
+	log.Printf("Bulk delete %s started by %s: %d predictions match (reason: %q)", job.ID, requestedBy, len(matched), reason)
+	go d.run(job, matched)
+	return snapshot, nil
+}
+
+// run deletes matched, updating job's progress as it goes. Pins are
+// checked by the repository at each delete, not taken from matched, so a
+// prediction pinned while the job runs is kept.
+func (d *BulkDeleter) run(job *domain.BulkDelete, matched []*domain.Prediction) {
+	for _, p := range matched {
+		err := d.repository.DeleteUnpinnedPrediction(p.ID)
+		d.mu.Lock()
+		switch {
+		case errors.Is(err, domain.ErrPredictionPinned):
+			job.Skipped++
+		case err != nil:
+			job.Failed++
+			log.Printf("Warning: bulk delete %s failed to delete prediction %s: %v", job.ID, p.ID, err)
+		default:
+			job.Deleted++
+			job.DeletedIDs = append(job.DeletedIDs, p.ID)
+		}
+		d.mu.Unlock()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	finished := d.now().UTC()
+	job.FinishedAt = &finished
+	job.Status = domain.BulkDeleteCompleted
+	if job.Failed > 0 {
+		job.Status = domain.BulkDeleteFailed
+		job.Error = fmt.Sprintf("%d predictions could not be deleted", job.Failed)
+	}
+	log.Printf("Bulk delete %s %s: %d deleted, %d pinned kept, %d failed", job.ID, job.Status, job.Deleted, job.Skipped, job.Failed)
+}
+
+// Get returns a bulk delete job with the IDs it has deleted.
+func (d *BulkDeleter) Get(id string) (*domain.BulkDelete, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, job := range d.jobs {
+		if job.ID == id {
+			return copyBulkDelete(job), nil
+		}
+	}
+	return nil, domain.ErrBulkDeleteNotFound
+}
+
+// List returns the recorded jobs, newest first, without deleted IDs.
+func (d *BulkDeleter) List() []domain.BulkDelete {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	jobs := make([]domain.BulkDelete, 0, len(d.jobs))
+	for i := len(d.jobs) - 1; i >= 0; i-- {
+		summary := *d.jobs[i]
+		summary.DeletedIDs = nil
+		jobs = append(jobs, summary)
+	}
+	return jobs
+}
+
+func copyBulkDelete(job *domain.BulkDelete) *domain.BulkDelete {
+	copied := *job
+	copied.DeletedIDs = append([]string(nil), job.DeletedIDs...)
+	return &copied
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestBulkDeleter(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 15; i++ {
+		p := &domain.Prediction{ID: fmt.Sprintf("spam-%02d", i), Result: domain.LabelFake, RequestType: "url", CreatedAt: now.Add(-time.Duration(i) * time.Minute)}
+		p.Pinned = i == 0
+		if err := repo.CreatePrediction(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.CreatePrediction(&domain.Prediction{ID: "keep", Result: domain.LabelReal, RequestType: "url", CreatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	deleter := NewBulkDeleter(repo)
+	ctx := context.Background()
+	filters := domain.SearchFilters{Label: "fake"}
+
+	preview, err := deleter.Preview(ctx, filters)
+	if err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+	if preview.Matched != 15 || preview.Pinned != 1 || len(preview.Sample) != bulkDeletePreviewSize {
+		t.Errorf("preview = %d matched, %d pinned, %d sampled", preview.Matched, preview.Pinned, len(preview.Sample))
+	}
+	if all, _ := repo.GetAllPredictions(); len(all) != 16 {
+		t.Fatalf("dry run deleted predictions: %d left", len(all))
+	}
+
+	job, err := deleter.Start(ctx, filters, "admin-1", "spam wave")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if job.Status != domain.BulkDeleteRunning || job.Matched != 15 {
+		t.Errorf("started job = %+v", job)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for job.Status == domain.BulkDeleteRunning && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		if job, err = deleter.Get(job.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if job.Status != domain.BulkDeleteCompleted || job.Deleted != 14 || job.Skipped != 1 || len(job.DeletedIDs) != 14 || job.Progress() != 1 {
+		t.Errorf("finished job = %+v", job)
+	}
+	if job.RequestedBy != "admin-1" || job.Reason != "spam wave" || job.FinishedAt == nil {
+		t.Errorf("audit trail = %+v", job)
+	}
+	left, _ := repo.GetAllPredictions()
+	if len(left) != 2 {
+		t.Errorf("%d predictions left, want the pinned one and keep", len(left))
+	}
+
+	list := deleter.List()
+	if len(list) != 1 || list[0].DeletedIDs != nil {
+		t.Errorf("List() = %+v", list)
+	}
+	if _, err := deleter.Get("missing"); !errors.Is(err, domain.ErrBulkDeleteNotFound) {
+		t.Errorf("Get(missing) error = %v", err)
+	}
+}
+
+func TestBulkDeleterKeepsPinsMadeWhileRunning(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	for _, id := range []string{"a", "b"} {
+		if err := repo.CreatePrediction(&domain.Prediction{ID: id, Result: domain.LabelFake, RequestType: "url", CreatedAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deleter := NewBulkDeleter(repo)
+	matched, err := repo.Query(context.Background(), *domain.NewPredictionQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pinned after the job matched it
+	pinned := *matched[0]
+	pinned.Pinned = true
+	if err := repo.UpdatePrediction(&pinned); err != nil {
+		t.Fatal(err)
+	}
+	job := &domain.BulkDelete{ID: "bulk-delete-test", Status: domain.BulkDeleteRunning, Matched: len(matched)}
+	deleter.run(job, matched)
+
+	if job.Deleted != 1 || job.Skipped != 1 || job.Failed != 0 {
+		t.Errorf("job = %d deleted, %d skipped, %d failed; want 1, 1, 0", job.Deleted, job.Skipped, job.Failed)
+	}
+	if _, err := repo.GetPredictionByID(pinned.ID); err != nil {
+		t.Errorf("prediction pinned during the job was deleted: %v", err)
+	}
+}
+
+func TestValidateBulkDelete(t *testing.T) {
+	since := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	until := since.Add(-time.Hour)
+	tests := []struct {
+		name    string
+		filters domain.SearchFilters
+		wantErr bool
+	}{
+		{"label", domain.SearchFilters{Label: "REAL"}, false},
+		{"domain", domain.SearchFilters{Domain: "spam.example"}, false},
+		{"no filters", domain.SearchFilters{}, true},
+		{"bad label", domain.SearchFilters{Label: "maybe"}, true},
+		{"bad type", domain.SearchFilters{Type: "image"}, true},
+		{"inverted range", domain.SearchFilters{Since: &since, Until: &until}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := domain.ValidateBulkDelete(tt.filters)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBulkDelete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, domain.ErrInvalidBulkDelete) {
+				t.Errorf("error %v does not wrap ErrInvalidBulkDelete", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// Canary defaults.
+const (
+	DefaultCanaryInterval         = 5 * time.Minute
+	DefaultCanaryMaxLatency       = 10 * time.Second
+	DefaultCanaryFailureThreshold = 3
+	DefaultCanaryDriftTolerance   = 0.15
+)
+
+// canaryHistorySize keeps a day of runs at the default interval.
+const canaryHistorySize = 288
+
+// canaryTimeout bounds one probe.
+const canaryTimeout = 30 * time.Second
+
+// CanaryCase is a fixed article with the verdict the pipeline must give.
+type CanaryCase struct {
+	Name          string `json:"name"`
+	Text          string `json:"text"`
+	ExpectedLabel string `json:"expected_label"`
+}
+
+// DefaultCanaryCases are used when no cases file is configured: one
+// obvious fabrication and one plain wire report.
+var DefaultCanaryCases = []CanaryCase{
+	{
+		Name: "fabricated-miracle-cure",
+		Text: "SHOCKING: Doctors HATE this one simple trick! Scientists confirm that drinking bleach mixed with lemon juice " +
+			"cures every known disease overnight, but the government and big pharma are hiding the truth from you. " +
+			"Share this before it gets deleted! The mainstream media will never report on this miracle.",
+		ExpectedLabel: domain.LabelFake,
+	},
+	{
+		Name: "wire-report",
+		Text: "The central bank left its benchmark interest rate unchanged on Wednesday, citing steady inflation and a " +
+			"stable labor market. In a statement after its two-day meeting, the rate-setting committee said it would " +
+			"continue to monitor economic data and adjust policy as needed. The decision was widely expected by economists.",
+		ExpectedLabel: domain.LabelReal,
+	},
+}
+
+// CanaryProber runs text through the analysis pipeline without storing
+// it. NewsService implements it.
+type CanaryProber interface {
+	Probe(ctx context.Context, text string) (*domain.Prediction, error)
+}
+
+// CanaryRun is the outcome of one case in one canary round.
+type CanaryRun struct {
+	Case       string    `json:"case"`
+	At         time.Time `json:"at"`
+	LatencyMS  int64     `json:"latency_ms"`
+	Label      string    `json:"label,omitempty"`
+	Confidence float64   `json:"confidence,omitempty"`
+	Passed     bool      `json:"passed"`
+	Drift      float64   `json:"drift"` // confidence change from the case's baseline
+	Error      string    `json:"error,omitempty"`
+}
+
+// CanaryCaseStatus is one case's current state.
+type CanaryCaseStatus struct {
+	Name                string     `json:"name"`
+	ExpectedLabel       string     `json:"expected_label"`
+	Baseline            float64    `json:"baseline_confidence"` // confidence of the first passing run
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Drifting            bool       `json:"drifting"`
+	LastRun             *CanaryRun `json:"last_run,omitempty"`
+}
+
+// CanaryStatus is the canary's report for /api/stats/canary.
+type CanaryStatus struct {
+	Status          string             `json:"status"`
+	IntervalSeconds float64            `json:"interval_seconds"`
+	MaxLatencyMS    int64              `json:"max_latency_ms"`
+	Cases           []CanaryCaseStatus `json:"cases"`
+	Runs            int                `json:"runs"`
+	PassRate        float64            `json:"pass_rate"` // over the kept history
+	History         []CanaryRun        `json:"history"`   // newest first
+}
+
+type canaryState struct {
+	CanaryCase
+	baseline    float64
+	hasBaseline bool
+	failures    int
+	drifting    bool
+	failFiring  bool
+	driftFiring bool
+	lastRun     *CanaryRun
+}
+
+// Canary periodically runs known articles through the full pipeline,
+// records latency and correctness, and alerts when a case keeps failing
+// or its confidence drifts from its baseline.
+type Canary struct {
+	prober         CanaryProber
+	alerter        Alerter
+	maxLatency     time.Duration
+	failThreshold  int
+	driftTolerance float64
+	interval       time.Duration
+
+	mu      sync.Mutex
+	cases   []*canaryState
+	history []CanaryRun // ring buffer
+	next    int
+	runs    int
+	now     func() time.Time
+}
+
+// NewCanary creates a canary for cases. A nil alerter logs alerts.
+func NewCanary(prober CanaryProber, cases []CanaryCase, alerter Alerter) *Canary {
+	if alerter == nil {
+		alerter = LogAlerter{}
+	}
+	c := &Canary{
+		prober:         prober,
+		alerter:        alerter,
+		maxLatency:     DefaultCanaryMaxLatency,
+		failThreshold:  DefaultCanaryFailureThreshold,
+		driftTolerance: DefaultCanaryDriftTolerance,
+		interval:       DefaultCanaryInterval,
+		now:            time.Now,
+	}
+	for _, cs := range cases {
+		c.cases = append(c.cases, &canaryState{CanaryCase: cs})
+	}
+	return c
+}
+
+// WithThresholds sets the slowest passing run, the consecutive failures
+// that fire an alert and the confidence change counted as drift.
+func (c *Canary) WithThresholds(maxLatency time.Duration, failures int, driftTolerance float64) *Canary {
+	if maxLatency > 0 {
+		c.maxLatency = maxLatency
+	}
+	if failures > 0 {
+		c.failThreshold = failures
+	}
+	if driftTolerance > 0 {
+		c.driftTolerance = driftTolerance
+	}
+	return c
+}
+
+// LoadCanaryCases reads a JSON array of CanaryCase from a file.
+func LoadCanaryCases(path string) ([]CanaryCase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read canary cases: %w", err)
+	}
+	var cases []CanaryCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		return nil, fmt.Errorf("failed to parse canary cases: %w", err)
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("canary cases file %s is empty", path)
+	}
+	for _, cs := range cases {
+		if cs.Name == "" || cs.Text == "" {
+			return nil, fmt.Errorf("canary case needs a name and text")
+		}
+		if cs.ExpectedLabel != domain.LabelFake && cs.ExpectedLabel != domain.LabelReal {
+			return nil, fmt.Errorf("canary case %s: expected_label must be %s or %s", cs.Name, domain.LabelFake, domain.LabelReal)
+		}
+	}
+	return cases, nil
+}
+
+// RunOnce probes every case once, records the results and fires or
+// resolves alerts.
+func (c *Canary) RunOnce(ctx context.Context) []CanaryRun {
+	runs := make([]CanaryRun, len(c.cases))
+	for i, cs := range c.cases {
+		runs[i] = c.probe(ctx, cs.CanaryCase)
+	}
+
+	c.mu.Lock()
+	var alerts []Alert
+	for i, cs := range c.cases {
+		alerts = append(alerts, c.record(cs, &runs[i])...)
+	}
+	c.mu.Unlock()
+
+	for _, alert := range alerts {
+		if err := c.alerter.Fire(alert); err != nil {
+			log.Printf("Warning: failed to deliver alert %s: %v", alert.Name, err)
+		}
+	}
+	return runs
+}
+
+func (c *Canary) probe(ctx context.Context, cs CanaryCase) CanaryRun {
+	ctx, cancel := context.WithTimeout(ctx, canaryTimeout)
+	defer cancel()
+
+	run := CanaryRun{Case: cs.Name, At: c.now()}
+	start := time.Now()
+	prediction, err := c.prober.Probe(ctx, cs.Text)
+	latency := time.Since(start)
+	run.LatencyMS = latency.Milliseconds()
+	if err != nil {
+		run.Error = err.Error()
+		return run
+	}
+
+	run.Label, run.Confidence = prediction.Result, prediction.Confidence
+	switch {
+	case prediction.Result != cs.ExpectedLabel:
+		run.Error = fmt.Sprintf("got %s, want %s", prediction.Result, cs.ExpectedLabel)
+	case latency > c.maxLatency:
+		run.Error = fmt.Sprintf("took %s, limit %s", latency.Round(time.Millisecond), c.maxLatency)
+	default:
+		run.Passed = true
+	}
+	return run
+}
+
+// record stores run against its case and returns alerts for state
+// changes. Callers hold c.mu.
+func (c *Canary) record(cs *canaryState, run *CanaryRun) []Alert {
+	if run.Passed {
+		cs.failures = 0
+		if !cs.hasBaseline {
+			cs.baseline, cs.hasBaseline = run.Confidence, true
+		}
+		run.Drift = run.Confidence - cs.baseline
+		cs.drifting = math.Abs(run.Drift) > c.driftTolerance
+	} else {
+		cs.failures++
+	}
+	cs.lastRun = run
+
+	if len(c.history) < canaryHistorySize {
+		c.history = append(c.history, *run)
+	} else {
+		c.history[c.next] = *run
+	}
+	c.next = (c.next + 1) % canaryHistorySize
+	c.runs++
+
+	var alerts []Alert
+	labels := map[string]string{"case": cs.Name}
+	if failing := cs.failures >= c.failThreshold; failing != cs.failFiring {
+		cs.failFiring = failing
+		alert := Alert{Name: "CanaryFailing", Labels: labels, FiredAt: run.At}
+		if failing {
+			alert.Severity = SeverityCritical
+			alert.Summary = fmt.Sprintf("canary %s failed %d times in a row: %s", cs.Name, cs.failures, run.Error)
+		} else {
+			alert.Severity = SeverityResolved
+			alert.Summary = fmt.Sprintf("canary %s is passing again", cs.Name)
+		}
+		alerts = append(alerts, alert)
+	}
+	// Drift is only judged on passing runs; a failing run keeps the
+	// previous state
+	if run.Passed && cs.drifting != cs.driftFiring {
+		cs.driftFiring = cs.drifting
+		alert := Alert{Name: "CanaryDrift", Labels: labels, FiredAt: run.At}
+		if cs.drifting {
+			alert.Severity = SeverityWarning
+			alert.Summary = fmt.Sprintf("canary %s confidence moved %+.2f from its baseline %.2f", cs.Name, run.Drift, cs.baseline)
+		} else {
+			alert.Severity = SeverityResolved
+			alert.Summary = fmt.Sprintf("canary %s confidence is back within %.2f of its baseline", cs.Name, c.driftTolerance)
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+// Status returns the canary's cases and recent history.
+func (c *Canary) Status() CanaryStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status := CanaryStatus{
+		Status:          HealthOK,
+		IntervalSeconds: c.interval.Seconds(),
+		MaxLatencyMS:    c.maxLatency.Milliseconds(),
+		Runs:            c.runs,
+		History:         make([]CanaryRun, 0, len(c.history)),
+	}
+	for _, cs := range c.cases {
+		cstat := CanaryCaseStatus{
+			Name:                cs.Name,
+			ExpectedLabel:       cs.ExpectedLabel,
+			Baseline:            cs.baseline,
+			ConsecutiveFailures: cs.failures,
+			Drifting:            cs.drifting,
+		}
+		if cs.lastRun != nil {
+			last := *cs.lastRun
+			cstat.LastRun = &last
+		}
+		switch {
+		case cs.failures >= c.failThreshold:
+			status.Status = HealthDown
+		case (cs.failures > 0 || cs.drifting) && status.Status == HealthOK:
+			status.Status = HealthDegraded
+		}
+		status.Cases = append(status.Cases, cstat)
+	}
+
+	passed := 0
+	for i := 0; i < len(c.history); i++ {
+		run := c.history[(c.next-1-i+canaryHistorySize)%canaryHistorySize]
+		status.History = append(status.History, run)
+		if run.Passed {
+			passed++
+		}
+	}
+	if len(c.history) > 0 {
+		status.PassRate = float64(passed) / float64(len(c.history))
+	}
+	return status
+}
+
+// Run probes every interval until ctx is cancelled.
+func (c *Canary) Run(ctx context.Context, interval time.Duration) {
+	c.mu.Lock()
+	c.interval = interval
+	c.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.RunOnce(ctx)
+		}
+	}
+}
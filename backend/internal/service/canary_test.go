@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+type fakeProber struct {
+	result     string
+	confidence float64
+	err        error
+}
+
+func (p *fakeProber) Probe(ctx context.Context, text string) (*domain.Prediction, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &domain.Prediction{Result: p.result, Confidence: p.confidence}, nil
+}
+
+func TestCanaryFailureAlerts(t *testing.T) {
+	prober := &fakeProber{result: domain.LabelFake, confidence: 0.9}
+	alerter := &recordingAlerter{}
+	canary := NewCanary(prober, []CanaryCase{{Name: "fake", Text: "x", ExpectedLabel: domain.LabelFake}}, alerter).
+		WithThresholds(0, 2, 0)
+
+	if runs := canary.RunOnce(context.Background()); !runs[0].Passed {
+		t.Fatalf("RunOnce() = %+v, want a pass", runs[0])
+	}
+
+	// A wrong verdict and an error both count; the alert fires once.
+	prober.result = domain.LabelReal
+	canary.RunOnce(context.Background())
+	if status := canary.Status(); status.Status != HealthDegraded || len(alerter.alerts) != 0 {
+		t.Fatalf("after one failure: status %s, alerts %v", status.Status, alerter.alerts)
+	}
+	prober.err = errors.New("ml down")
+	canary.RunOnce(context.Background())
+	canary.RunOnce(context.Background())
+	if len(alerter.alerts) != 1 || alerter.alerts[0].Name != "CanaryFailing" || alerter.alerts[0].Severity != SeverityCritical {
+		t.Fatalf("alerts = %v, want one critical CanaryFailing", alerter.alerts)
+	}
+	if status := canary.Status(); status.Status != HealthDown || status.Cases[0].ConsecutiveFailures != 3 {
+		t.Errorf("status = %+v", status)
+	}
+
+	prober.err, prober.result = nil, domain.LabelFake
+	canary.RunOnce(context.Background())
+	if len(alerter.alerts) != 2 || alerter.alerts[1].Severity != SeverityResolved {
+		t.Fatalf("alerts = %v, want a resolution", alerter.alerts)
+	}
+
+	status := canary.Status()
+	if status.Runs != 5 || len(status.History) != 5 || !status.History[0].Passed || status.History[1].Error != "ml down" {
+		t.Errorf("history = %+v, want 5 runs newest first", status.History)
+	}
+	if status.PassRate != 0.4 {
+		t.Errorf("PassRate = %v, want 0.4", status.PassRate)
+	}
+}
+
+func TestCanaryDrift(t *testing.T) {
+	prober := &fakeProber{result: domain.LabelFake, confidence: 0.95}
+	alerter := &recordingAlerter{}
+	canary := NewCanary(prober, []CanaryCase{{Name: "fake", Text: "x", ExpectedLabel: domain.LabelFake}}, alerter).
+		WithThresholds(0, 0, 0.1)
+
+	canary.RunOnce(context.Background())
+	prober.confidence = 0.7
+	runs := canary.RunOnce(context.Background())
+	if !runs[0].Passed || runs[0].Drift > -0.24 {
+		t.Fatalf("run = %+v, want a pass with drift -0.25", runs[0])
+	}
+	if len(alerter.alerts) != 1 || alerter.alerts[0].Name != "CanaryDrift" {
+		t.Fatalf("alerts = %v, want CanaryDrift", alerter.alerts)
+	}
+	if status := canary.Status(); status.Status != HealthDegraded || !status.Cases[0].Drifting || status.Cases[0].Baseline != 0.95 {
+		t.Errorf("status = %+v", status)
+	}
+
+	prober.confidence = 0.9
+	canary.RunOnce(context.Background())
+	if len(alerter.alerts) != 2 || alerter.alerts[1].Severity != SeverityResolved {
+		t.Errorf("alerts = %v, want the drift resolved", alerter.alerts)
+	}
+}
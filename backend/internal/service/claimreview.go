@@ -0,0 +1,92 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// ClaimReviewPublisher is the organization credited as the author of
+// published ClaimReviews. Without a name, the reviewer is credited.
+type ClaimReviewPublisher struct {
+	Name string
+	URL  string
+}
+
+// ClaimReview is a schema.org ClaimReview in JSON-LD.
+type ClaimReview struct {
+	Context       string           `json:"@context"`
+	Type          string           `json:"@type"`
+	URL           string           `json:"url,omitempty"`
+	ClaimReviewed string           `json:"claimReviewed"`
+	DatePublished string           `json:"datePublished"`
+	Author        ClaimReviewThing `json:"author"`
+	ReviewRating  ClaimReviewThing `json:"reviewRating"`
+	ItemReviewed  ClaimReviewThing `json:"itemReviewed"`
+	ReviewBody    string           `json:"reviewBody,omitempty"`
+}
+
+// ClaimReviewThing is a nested schema.org object; unset fields are omitted.
+type ClaimReviewThing struct {
+	Type          string             `json:"@type"`
+	Name          string             `json:"name,omitempty"`
+	URL           string             `json:"url,omitempty"`
+	Headline      string             `json:"headline,omitempty"`
+	DatePublished string             `json:"datePublished,omitempty"`
+	RatingValue   int                `json:"ratingValue,omitempty"`
+	BestRating    int                `json:"bestRating,omitempty"`
+	WorstRating   int                `json:"worstRating,omitempty"`
+	AlternateName string             `json:"alternateName,omitempty"`
+	Author        *ClaimReviewThing  `json:"author,omitempty"`
+	Appearance    []ClaimReviewThing `json:"appearance,omitempty"`
+}
+
+// reviewRatings maps review verdicts onto a 1-5 truthfulness scale.
+var reviewRatings = map[string]ClaimReviewThing{
+	domain.LabelFake: {Type: "Rating", RatingValue: 1, BestRating: 5, WorstRating: 1, AlternateName: "False"},
+	domain.LabelReal: {Type: "Rating", RatingValue: 5, BestRating: 5, WorstRating: 1, AlternateName: "True"},
+}
+
+// BuildClaimReview renders a reviewed prediction as ClaimReview. Only the
+// human review is published; the model's verdict is not. pageURL is where
+// the review can be read.
+func BuildClaimReview(p *domain.Prediction, publisher ClaimReviewPublisher, pageURL string) (*ClaimReview, error) {
+	review := p.Review
+	if review == nil {
+		return nil, domain.ErrNotReviewed
+	}
+
+	cr := &ClaimReview{
+		Context:       "https://schema.org",
+		Type:          "ClaimReview",
+		URL:           pageURL,
+		ClaimReviewed: review.Claim,
+		DatePublished: review.ReviewedAt.UTC().Format("2006-01-02"),
+		Author:        ClaimReviewThing{Type: "Person", Name: review.Reviewer},
+		ReviewRating:  reviewRatings[review.Verdict],
+		ItemReviewed:  ClaimReviewThing{Type: "Claim"},
+		ReviewBody:    review.Note,
+	}
+	if publisher.Name != "" {
+		cr.Author = ClaimReviewThing{Type: "Organization", Name: publisher.Name, URL: publisher.URL}
+	}
+
+	if p.RequestType == "url" {
+		appearance := ClaimReviewThing{Type: "CreativeWork", URL: p.CanonicalURL, Headline: p.ArticleTitle}
+		if appearance.URL == "" {
+			appearance.URL = p.OriginalContent
+		}
+		if p.ArticlePublishedAt != nil {
+			appearance.DatePublished = p.ArticlePublishedAt.UTC().Format(time.RFC3339)
+		}
+		cr.ItemReviewed.Appearance = []ClaimReviewThing{appearance}
+		if source := p.ArticleSource; source != "" {
+			name := source
+			if p.SourceInfo != nil && p.SourceInfo.Name != "" {
+				name = p.SourceInfo.Name
+			}
+			cr.ItemReviewed.Author = &ClaimReviewThing{Type: "Organization", Name: name}
+		}
+	}
+	return cr, nil
+}
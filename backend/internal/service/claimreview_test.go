@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestReviewPredictionPublishesClaimReview(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	published := time.Date(2024, 5, 30, 8, 0, 0, 0, time.UTC)
+	if err := repo.CreatePrediction(&domain.Prediction{
+		ID: "p1", RequestType: "url", Result: domain.LabelReal, Confidence: 0.7,
+		CanonicalURL: "https://rumors.example/story", ArticleTitle: "Moon made of cheese, say scientists",
+		ArticleSource: "rumors.example", ArticlePublishedAt: &published,
+		SourceInfo: &domain.SourceInfo{Domain: "rumors.example", Name: "Rumors Daily"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	news := NewNewsService(nil, nil, repo)
+	ctx := context.Background()
+
+	stored, _ := repo.GetPredictionByID("p1")
+	if _, err := BuildClaimReview(stored, ClaimReviewPublisher{}, ""); !errors.Is(err, domain.ErrNotReviewed) {
+		t.Errorf("BuildClaimReview(unreviewed) error = %v, want ErrNotReviewed", err)
+	}
+	if _, err := news.ReviewPrediction(ctx, "p1", "", domain.Review{Verdict: "maybe", Reviewer: "ana"}); !errors.Is(err, domain.ErrInvalidReview) {
+		t.Errorf("ReviewPrediction(maybe) error = %v, want ErrInvalidReview", err)
+	}
+
+	reviewed, err := news.ReviewPrediction(ctx, "p1", "", domain.Review{Verdict: "fake", Reviewer: "ana", Note: "No such study exists."})
+	if err != nil {
+		t.Fatalf("ReviewPrediction() error = %v", err)
+	}
+	if reviewed.Review.Verdict != domain.LabelFake || reviewed.Review.Claim != "Moon made of cheese, say scientists" {
+		t.Errorf("Review = %+v, want FAKE with the title as claim", reviewed.Review)
+	}
+
+	cr, err := BuildClaimReview(reviewed, ClaimReviewPublisher{Name: "Campus Fact Check", URL: "https://factcheck.example"},
+		"https://api.example/api/predictions/p1/claimreview")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(cr)
+	for _, want := range []string{
+		`"@type":"ClaimReview"`,
+		`"claimReviewed":"Moon made of cheese, say scientists"`,
+		`"author":{"@type":"Organization","name":"Campus Fact Check","url":"https://factcheck.example"}`,
+		`"reviewRating":{"@type":"Rating","ratingValue":1,"bestRating":5,"worstRating":1,"alternateName":"False"}`,
+		`"url":"https://rumors.example/story"`,
+		`"name":"Rumors Daily"`,
+		`"reviewBody":"No such study exists."`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("ClaimReview is missing %s:\n%s", want, data)
+		}
+	}
+	// The model's own verdict is never published.
+	if strings.Contains(string(data), "0.7") || strings.Contains(string(data), `"True"`) {
+		t.Errorf("ClaimReview leaks the model verdict:\n%s", data)
+	}
+
+	withdrawn, err := news.RemoveReview(ctx, "p1")
+	if err != nil || withdrawn.Review != nil {
+		t.Errorf("RemoveReview() = %+v, %v", withdrawn, err)
+	}
+}
+
+func TestReviewPredictionChecksVersion(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	if err := repo.CreatePrediction(&domain.Prediction{ID: "p1", Result: domain.LabelReal, Confidence: 0.7, ModelVersion: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	news := NewNewsService(nil, nil, repo)
+	ctx := context.Background()
+
+	stored, _ := repo.GetPredictionByID("p1")
+	seen := stored.Version()
+	review := domain.Review{Verdict: "fake", Claim: "claim", Reviewer: "ana"}
+	reviewed, err := news.ReviewPrediction(ctx, "p1", seen, review)
+	if err != nil {
+		t.Fatalf("ReviewPrediction(current version) error = %v", err)
+	}
+	if reviewed.Version() != seen {
+		t.Error("reviewing changed the prediction's version")
+	}
+
+	// A re-score replaces the verdict the reviewer saw.
+	rescored := *reviewed
+	rescored.Result, rescored.Confidence, rescored.ModelVersion = domain.LabelFake, 0.9, "v2"
+	if err := repo.UpdatePrediction(&rescored); err != nil {
+		t.Fatal(err)
+	}
+	current, err := news.ReviewPrediction(ctx, "p1", seen, review)
+	if !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Fatalf("ReviewPrediction(stale version) error = %v, want ErrVersionMismatch", err)
+	}
+	if current.Version() == seen || current.ModelVersion != "v2" {
+		t.Errorf("mismatch returned %+v, want the current revision", current)
+	}
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+	"github.com/google/uuid"
+)
+
+// MaxCorpusSize caps the records in one corpus.
+const MaxCorpusSize = 100000
+
+// CorpusBuilder samples balanced, anonymized training sets from stored
+// predictions for the next fine-tuning round.
+type CorpusBuilder struct {
+	predictions NewsRepository
+	corpora     repository.CorpusRepository
+	now         func() time.Time
+}
+
+// NewCorpusBuilder creates a corpus builder.
+func NewCorpusBuilder(predictions NewsRepository, corpora repository.CorpusRepository) *CorpusBuilder {
+	return &CorpusBuilder{predictions: predictions, corpora: corpora, now: time.Now}
+}
+
+// Build samples a corpus and stores its JSONL export with a manifest.
+//
+// Every label gets the same number of records (Size split evenly, or as
+// many as the rarest label has when Size is 0). Within a label, records
+// are drawn round-robin across language/domain/month strata so no single
+// publisher or period dominates. Strata and their members are shuffled
+// with Seed, so the same request over the same predictions yields the
+// same export.
+func (b *CorpusBuilder) Build(ctx context.Context, req domain.CorpusRequest) (*domain.CorpusManifest, error) {
+	if req.Size < 0 || req.Size > MaxCorpusSize {
+		return nil, fmt.Errorf("%w: size must be between 0 and %d", domain.ErrInvalidCorpusRequest, MaxCorpusSize)
+	}
+	q := domain.NewPredictionQuery()
+	q.OldestFirst = true
+	if req.Since != nil {
+		q.Since = *req.Since
+	}
+	if req.Until != nil {
+		q.Until = *req.Until
+	}
+	predictions, err := b.predictions.Query(ctx, *q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load predictions: %w", err)
+	}
+
+	languages := make(map[string]bool, len(req.Languages))
+	for _, lang := range req.Languages {
+		languages[lang] = true
+	}
+	// label -> stratum key -> records
+	byLabel := make(map[string]map[string][]domain.CorpusRecord)
+	candidates, withheld := 0, 0
+	for _, p := range predictions {
+		if !corpusEligible(p) {
+			continue
+		}
+		if !p.License.Exportable() {
+			withheld++
+			continue
+		}
+		record := anonymizePrediction(p)
+		if record.Text == "" || (len(languages) > 0 && !languages[record.Language]) {
+			continue
+		}
+		strata, ok := byLabel[record.Label]
+		if !ok {
+			strata = make(map[string][]domain.CorpusRecord)
+			byLabel[record.Label] = strata
+		}
+		key := record.Language + "|" + record.Domain + "|" + record.Date[:7]
+		strata[key] = append(strata[key], record)
+		candidates++
+	}
+	if candidates == 0 {
+		return nil, domain.ErrEmptyCorpus
+	}
+
+	perLabel := -1
+	for _, strata := range byLabel {
+		n := 0
+		for _, records := range strata {
+			n += len(records)
+		}
+		if perLabel < 0 || n < perLabel {
+			perLabel = n
+		}
+	}
+	if req.Size > 0 {
+		perLabel = min(perLabel, req.Size/len(byLabel))
+	}
+
+	manifest := &domain.CorpusManifest{
+		ID:         "corpus-" + uuid.New().String(),
+		CreatedAt:  b.now().UTC(),
+		Request:    req,
+		Anonymizer: AnonymizerVersion,
+		Candidates: candidates,
+		Withheld:   withheld,
+		ByLabel:    make(map[string]int),
+		ByLanguage: make(map[string]int),
+		ByDomain:   make(map[string]int),
+		ByMonth:    make(map[string]int),
+	}
+	rng := rand.New(rand.NewSource(req.Seed))
+	var sample []domain.CorpusRecord
+	for _, label := range sortedKeys(byLabel) {
+		strata := byLabel[label]
+		manifest.Strata += len(strata)
+		sample = append(sample, sampleStrata(rng, strata, perLabel)...)
+	}
+	// Interleave labels so a truncated read is still balanced.
+	rng.Shuffle(len(sample), func(i, j int) { sample[i], sample[j] = sample[j], sample[i] })
+
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	for _, record := range sample {
+		if err := enc.Encode(record); err != nil {
+			return nil, fmt.Errorf("failed to encode corpus record: %w", err)
+		}
+		manifest.ByLabel[record.Label]++
+		manifest.ByLanguage[record.Language]++
+		if record.Domain != "" {
+			manifest.ByDomain[record.Domain]++
+		}
+		manifest.ByMonth[record.Date[:7]]++
+	}
+	sum := sha256.Sum256(data.Bytes())
+	manifest.Records = len(sample)
+	manifest.SHA256 = hex.EncodeToString(sum[:])
+	manifest.Bytes = data.Len()
+
+	if err := b.corpora.Save(ctx, manifest, data.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to save corpus: %w", err)
+	}
+	return manifest, nil
+}
+
+// List returns stored corpus manifests, newest first.
+func (b *CorpusBuilder) List(ctx context.Context) ([]*domain.CorpusManifest, error) {
+	return b.corpora.List(ctx)
+}
+
+// Get returns one corpus manifest.
+func (b *CorpusBuilder) Get(ctx context.Context, id string) (*domain.CorpusManifest, error) {
+	return b.corpora.GetByID(ctx, id)
+}
+
+// Data returns a corpus's JSONL export.
+func (b *CorpusBuilder) Data(ctx context.Context, id string) ([]byte, error) {
+	return b.corpora.Data(ctx, id)
+}
+
+// LicenseComposition breaks stored predictions down by content license,
+// label and partner, and counts those research exports may include.
+func (b *CorpusBuilder) LicenseComposition(ctx context.Context) (*domain.LicenseComposition, error) {
+	predictions, err := b.predictions.Query(ctx, *domain.NewPredictionQuery())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load predictions: %w", err)
+	}
+	composition := &domain.LicenseComposition{
+		ByLicense: make(map[string]*domain.LicenseCount),
+		ByPartner: make(map[string]int),
+	}
+	for _, p := range predictions {
+		name := p.License.Name()
+		count, ok := composition.ByLicense[name]
+		if !ok {
+			count = &domain.LicenseCount{ByLabel: make(map[string]int)}
+			composition.ByLicense[name] = count
+		}
+		count.Predictions++
+		count.ByLabel[p.Result]++
+		if p.License.Exportable() {
+			count.Exportable++
+			composition.Exportable++
+		}
+		if p.License != nil && p.License.Partner != "" {
+			composition.ByPartner[p.License.Partner]++
+		}
+		composition.Total++
+	}
+	return composition, nil
+}
+
+// corpusEligible reports whether p is a model verdict fit for training.
+// Allowlist and blocklist answers were never scored, and predictions
+// routed to an organization's own model stay with that organization.
+func corpusEligible(p *domain.Prediction) bool {
+	if p.Method != "" && p.Method != domain.MethodModel {
+		return false
+	}
+	if p.ModelRoute != "" {
+		return false
+	}
+	return p.Result == domain.LabelFake || p.Result == domain.LabelReal
+}
+
+// sampleStrata draws n records round-robin across shuffled strata.
+func sampleStrata(rng *rand.Rand, strata map[string][]domain.CorpusRecord, n int) []domain.CorpusRecord {
+	keys := sortedKeys(strata)
+	rng.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+	queues := make([][]domain.CorpusRecord, len(keys))
+	for i, key := range keys {
+		records := append([]domain.CorpusRecord(nil), strata[key]...)
+		sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+		rng.Shuffle(len(records), func(i, j int) { records[i], records[j] = records[j], records[i] })
+		queues[i] = records
+	}
+
+	sample := make([]domain.CorpusRecord, 0, n)
+	for len(sample) < n {
+		for i := range queues {
+			if len(sample) == n {
+				break
+			}
+			if len(queues[i]) > 0 {
+				sample = append(sample, queues[i][0])
+				queues[i] = queues[i][1:]
+			}
+		}
+	}
+	return sample
+}
+
+// sortedKeys returns m's keys in order, so seeded shuffles are repeatable.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestScrubText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Mail jane.doe@example.com now", "Mail [EMAIL] now"},
+		{"Call +91 98765 43210 or (555) 123-4567", "Call [PHONE] or [PHONE]"},
+		{"Posted by @rumor_mill today", "Posted by [HANDLE] today"},
+		{"On 2024-06-01 about 1,200 people", "On 2024-06-01 about 1,200 people"},
+	}
+	for _, tt := range tests {
+		if got := scrubText(tt.in); got != tt.want {
+			t.Errorf("scrubText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCorpusBuilderBuild(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	day := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	add := func(p *domain.Prediction) {
+		t.Helper()
+		if err := repo.CreatePrediction(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	partner := &domain.ContentLicense{License: domain.LicensePartnerProvided, ResearchConsent: true, Partner: "rumor-watch"}
+	consented := &domain.ContentLicense{License: domain.LicenseUserSubmitted, ResearchConsent: true}
+	for i := 0; i < 6; i++ {
+		add(&domain.Prediction{ID: fmt.Sprintf("fake-%d", i), Result: domain.LabelFake, RequestType: "url",
+			OriginalContent: "https://rumors.example/story?utm_source=x", ArticleSource: "rumors.example",
+			ArticleTitle: "The secret they hide", ArticleDescription: "Contact tips@rumors.example for more",
+			OwnerID: "user-1", CreatedAt: day.AddDate(0, i%2, i), License: partner})
+	}
+	for i := 0; i < 3; i++ {
+		add(&domain.Prediction{ID: fmt.Sprintf("real-%d", i), Result: domain.LabelReal, RequestType: "text",
+			OriginalContent: "The council approved the budget for the new year", Method: domain.MethodModel,
+			CreatedAt: day.AddDate(0, 0, i), License: consented})
+	}
+	add(&domain.Prediction{ID: "trusted", Result: domain.LabelReal, RequestType: "url", Method: domain.MethodTrustedSource,
+		OriginalContent: "https://wire.example/a", ArticleTitle: "Wire story", CreatedAt: day})
+	add(&domain.Prediction{ID: "tenant", Result: domain.LabelReal, RequestType: "text", ModelRoute: "acme",
+		OriginalContent: "Internal memo text", CreatedAt: day})
+	// Eligible, but not licensed for export.
+	add(&domain.Prediction{ID: "scraped", Result: domain.LabelFake, RequestType: "url", Method: domain.MethodModel,
+		OriginalContent: "https://paper.example/a", ArticleTitle: "Scraped headline", CreatedAt: day,
+		License: &domain.ContentLicense{License: domain.LicenseScrapedFairUse}})
+	add(&domain.Prediction{ID: "no-consent", Result: domain.LabelReal, RequestType: "text", Method: domain.MethodModel,
+		OriginalContent: "Private letter to the editor", CreatedAt: day,
+		License: &domain.ContentLicense{License: domain.LicenseUserSubmitted}})
+	add(&domain.Prediction{ID: "unrecorded", Result: domain.LabelReal, RequestType: "text", Method: domain.MethodModel,
+		OriginalContent: "Text from before licenses", CreatedAt: day})
+
+	corpora := memory.NewCorpusRepository()
+	builder := NewCorpusBuilder(repo, corpora)
+	ctx := context.Background()
+
+	manifest, err := builder.Build(ctx, domain.CorpusRequest{Seed: 7})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if manifest.Candidates != 9 || manifest.Records != 6 ||
+		manifest.ByLabel[domain.LabelFake] != 3 || manifest.ByLabel[domain.LabelReal] != 3 {
+		t.Errorf("manifest = %+v, want 3 records per label from 9 candidates", manifest)
+	}
+	if manifest.Withheld != 3 {
+		t.Errorf("manifest.Withheld = %d, want 3", manifest.Withheld)
+	}
+	if manifest.Anonymizer != AnonymizerVersion || manifest.ByDomain["rumors.example"] != 3 {
+		t.Errorf("manifest = %+v", manifest)
+	}
+
+	data, err := builder.Data(ctx, manifest.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"tips@rumors.example", "utm_source", "user-1", "fake-", "Internal memo", "Wire story", "Scraped headline", "Private letter", "before licenses"} {
+		if bytes.Contains(data, []byte(leaked)) {
+			t.Errorf("export contains %q:\n%s", leaked, data)
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var record domain.CorpusRecord
+	if len(lines) != 6 || json.Unmarshal([]byte(lines[0]), &record) != nil || record.Text == "" || record.Language != "en" {
+		t.Errorf("export = %d lines, first record %+v", len(lines), record)
+	}
+
+	// The same request samples the same records.
+	again, err := builder.Build(ctx, domain.CorpusRequest{Seed: 7})
+	if err != nil || again.SHA256 != manifest.SHA256 {
+		t.Errorf("rebuild SHA256 = %q, %v; want %q", again.SHA256, err, manifest.SHA256)
+	}
+	if list, _ := builder.List(ctx); len(list) != 2 {
+		t.Errorf("List() = %d corpora, want 2", len(list))
+	}
+
+	if _, err := builder.Build(ctx, domain.CorpusRequest{Size: MaxCorpusSize + 1}); !errors.Is(err, domain.ErrInvalidCorpusRequest) {
+		t.Errorf("oversized Build() error = %v, want ErrInvalidCorpusRequest", err)
+	}
+	if _, err := builder.Build(ctx, domain.CorpusRequest{Languages: []string{"fr"}}); !errors.Is(err, domain.ErrEmptyCorpus) {
+		t.Errorf("Build(fr) error = %v, want ErrEmptyCorpus", err)
+	}
+}
+
+func TestCorpusBuilderLicenseComposition(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	for i, p := range []*domain.Prediction{
+		{Result: domain.LabelFake, License: &domain.ContentLicense{License: domain.LicenseScrapedFairUse}},
+		{Result: domain.LabelReal, License: &domain.ContentLicense{License: domain.LicenseScrapedFairUse}},
+		{Result: domain.LabelReal, License: &domain.ContentLicense{License: domain.LicenseUserSubmitted, ResearchConsent: true}},
+		{Result: domain.LabelFake, License: &domain.ContentLicense{License: domain.LicensePartnerProvided, Partner: "wire"}},
+		{Result: domain.LabelFake},
+	} {
+		p.ID = fmt.Sprintf("p%d", i)
+		if err := repo.CreatePrediction(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, err := NewCorpusBuilder(repo, memory.NewCorpusRepository()).LicenseComposition(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Total != 5 || c.Exportable != 1 || c.ByPartner["wire"] != 1 {
+		t.Errorf("composition = %+v", c)
+	}
+	tests := []struct {
+		license                       string
+		predictions, exportable, fake int
+	}{
+		{domain.LicenseScrapedFairUse, 2, 0, 1},
+		{domain.LicenseUserSubmitted, 1, 1, 0},
+		{domain.LicensePartnerProvided, 1, 0, 1},
+		{domain.LicenseUnrecorded, 1, 0, 1},
+	}
+	for _, tt := range tests {
+		got := c.ByLicense[tt.license]
+		if got == nil || got.Predictions != tt.predictions || got.Exportable != tt.exportable || got.ByLabel[domain.LabelFake] != tt.fake {
+			t.Errorf("%s = %+v, want %d predictions, %d exportable, %d fake", tt.license, got, tt.predictions, tt.exportable, tt.fake)
+		}
+	}
+}
+
+func TestContentLicense(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		req        domain.AnalysisRequest
+		license    string
+		exportable bool
+	}{
+		{"scraped article", ctx, domain.AnalysisRequest{Type: "url", ResearchConsent: true}, domain.LicenseScrapedFairUse, false},
+		{"pasted text", ctx, domain.AnalysisRequest{Type: "text"}, domain.LicenseUserSubmitted, false},
+		{"pasted text with consent", ctx, domain.AnalysisRequest{Type: "text", ResearchConsent: true}, domain.LicenseUserSubmitted, true},
+		{"partner", ContextWithPartner(ctx, "wire", true), domain.AnalysisRequest{Type: "url"}, domain.LicensePartnerProvided, true},
+		{"partner without export rights", ContextWithPartner(ctx, "wire", false), domain.AnalysisRequest{Type: "text", ResearchConsent: true}, domain.LicensePartnerProvided, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := contentLicense(tt.ctx, &tt.req)
+			if got.License != tt.license || got.Exportable() != tt.exportable {
+				t.Errorf("contentLicense() = %+v (exportable %v), want %s (exportable %v)", got, got.Exportable(), tt.license, tt.exportable)
+			}
+		})
+	}
+}
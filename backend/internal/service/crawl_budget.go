@@ -0,0 +1,396 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// Crawl budget defaults
+const (
+	DefaultCrawlPagesPerDay = 500              // per domain
+	DefaultMaxCrawlDelay    = 30 * time.Second // longer robots.txt delays are capped
+	robotsTTL               = 24 * time.Hour
+	minCrawlBackoff         = time.Minute
+	maxCrawlBackoff         = 6 * time.Hour
+	crawlStateIdleTTL       = 48 * time.Hour
+	maxCrawlRaise           = 7 * 24 * time.Hour
+)
+
+type crawlKey struct{}
+
+// ContextWithCrawl marks fetches made with ctx as background crawling, so
+// they draw from the crawl budget. Interactive analyses are never budgeted.
+func ContextWithCrawl(ctx context.Context) context.Context {
+	return context.WithValue(ctx, crawlKey{}, true)
+}
+
+func isCrawl(ctx context.Context) bool {
+	crawl, _ := ctx.Value(crawlKey{}).(bool)
+	return crawl
+}
+
+// CrawlDomainState is one domain's crawl budget as shown in scraper stats.
+type CrawlDomainState struct {
+	Domain       string     `json:"domain"`
+	PagesToday   int        `json:"pages_today"`
+	PagesPerDay  int        `json:"pages_per_day"` // including a temporary raise
+	CrawlDelayMS int64      `json:"crawl_delay_ms"`
+	Queued       int        `json:"queued,omitempty"`
+	BackoffUntil *time.Time `json:"backoff_until,omitempty"`
+	RaisedUntil  *time.Time `json:"raised_until,omitempty"`
+	LastStatus   int        `json:"last_status,omitempty"`
+}
+
+type crawlDomain struct {
+	day          string // UTC date PagesToday counts for
+	pages        int
+	nextFetch    time.Time // earliest start of the next crawl fetch
+	crawlDelay   time.Duration
+	robotsAt     time.Time
+	backoff      time.Duration
+	backoffUntil time.Time
+	lastStatus   int
+	lastSeen     time.Time
+	queued       int // fetchers sleeping until their slot
+	raise        int // extra pages per day until raiseUntil
+	raiseUntil   time.Time
+}
+
+// CrawlBudget limits how hard background fetchers hit each domain: a
+// daily page cap, the Crawl-delay from robots.txt and exponential backoff
+// after 429 or 403 responses. One budget is shared by every fetcher.
+type CrawlBudget struct {
+	pagesPerDay   int
+	maxCrawlDelay time.Duration
+	httpClient    *http.Client
+	now           func() time.Time
+	sleep         func(ctx context.Context, d time.Duration) error
+
+	mu      sync.Mutex
+	domains map[string]*crawlDomain
+}
+
+// NewCrawlBudget creates a budget allowing pagesPerDay crawl fetches per
+// domain.
+func NewCrawlBudget(pagesPerDay int) *CrawlBudget {
+	if pagesPerDay <= 0 {
+		pagesPerDay = DefaultCrawlPagesPerDay
+	}
+	return &CrawlBudget{
+		pagesPerDay:   pagesPerDay,
+		maxCrawlDelay: DefaultMaxCrawlDelay,
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+		now:           time.Now,
+		sleep:         sleepContext,
+		domains:       make(map[string]*crawlDomain),
+	}
+}
+
+// WithMaxCrawlDelay caps the Crawl-delay honoured from robots.txt.
+func (b *CrawlBudget) WithMaxCrawlDelay(d time.Duration) *CrawlBudget {
+	if d > 0 {
+		b.maxCrawlDelay = d
+	}
+	return b
+}
+
+// Acquire waits until a crawl fetch of target is allowed. It fails with
+// domain.ErrCrawlBudgetExhausted while the domain's daily pages are used
+// up or it is backing off.
+func (b *CrawlBudget) Acquire(ctx context.Context, target *url.URL) error {
+	host := normalizeDomain(target.Hostname())
+	b.refreshRobots(ctx, target.Scheme+"://"+target.Host, host)
+
+	b.mu.Lock()
+	now := b.now()
+	d := b.domain(host, now)
+	if now.Before(d.backoffUntil) {
+		until := d.backoffUntil
+		b.mu.Unlock()
+		return fmt.Errorf("%w: backing off %s until %s", domain.ErrCrawlBudgetExhausted, host, until.Format(time.RFC3339))
+	}
+	if today := now.UTC().Format("2006-01-02"); d.day != today {
+		d.day, d.pages = today, 0
+	}
+	if d.pages >= b.limit(d, now) {
+		b.mu.Unlock()
+		return fmt.Errorf("%w: %d pages from %s today", domain.ErrCrawlBudgetExhausted, d.pages, host)
+	}
+
+	// Reserve the next slot so concurrent fetchers queue behind each other.
+	start := now
+	if d.nextFetch.After(start) {
+		start = d.nextFetch
+	}
+	d.nextFetch = start.Add(d.crawlDelay)
+	d.pages++
+	wait := start.Sub(now)
+	if wait > 0 {
+		d.queued++
+	}
+	b.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	err := b.sleep(ctx, wait)
+	b.mu.Lock()
+	d.queued--
+	b.mu.Unlock()
+	return err
+}
+
+// Report records the status of a fetch from host. 429 and 403 double the
+// domain's backoff (or honour Retry-After); success clears it.
+func (b *CrawlBudget) Report(host string, status int, retryAfter string) {
+	host = normalizeDomain(host)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	d := b.domain(host, now)
+	d.lastStatus = status
+	switch {
+	case status == http.StatusTooManyRequests || status == http.StatusForbidden:
+		d.backoff = min(max(2*d.backoff, minCrawlBackoff), maxCrawlBackoff)
+		wait := d.backoff
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && time.Duration(seconds)*time.Second > wait {
+			wait = min(time.Duration(seconds)*time.Second, maxCrawlBackoff)
+		}
+		d.backoffUntil = now.Add(wait)
+	case status >= 200 && status < 300:
+		d.backoff, d.backoffUntil = 0, time.Time{}
+	}
+}
+
+// Stats returns every tracked domain's budget, sorted by domain.
+func (b *CrawlBudget) Stats() []CrawlDomainState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	today := now.UTC().Format("2006-01-02")
+	states := make([]CrawlDomainState, 0, len(b.domains))
+	for host, d := range b.domains {
+		state := CrawlDomainState{
+			Domain:       host,
+			PagesPerDay:  b.limit(d, now),
+			CrawlDelayMS: d.crawlDelay.Milliseconds(),
+			Queued:       d.queued,
+			LastStatus:   d.lastStatus,
+		}
+		if d.day == today {
+			state.PagesToday = d.pages
+		}
+		if now.Before(d.backoffUntil) {
+			until := d.backoffUntil
+			state.BackoffUntil = &until
+		}
+		if now.Before(d.raiseUntil) {
+			until := d.raiseUntil
+			state.RaisedUntil = &until
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Domain < states[j].Domain })
+	return states
+}
+
+// Schedule returns when crawl fetches from host can next run. A domain
+// not crawled yet has its whole budget.
+func (b *CrawlBudget) Schedule(host string) domain.CrawlSchedule {
+	host = normalizeDomain(host)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	schedule := domain.CrawlSchedule{Domain: host, PagesPerDay: b.pagesPerDay, PagesRemaining: b.pagesPerDay}
+	d, ok := b.domains[host]
+	if !ok {
+		return schedule
+	}
+	schedule.Queued = d.queued
+	schedule.PagesPerDay = b.limit(d, now)
+	schedule.PagesRemaining = schedule.PagesPerDay
+	if d.day == now.UTC().Format("2006-01-02") {
+		schedule.PagesRemaining = max(schedule.PagesPerDay-d.pages, 0)
+	}
+	if now.Before(d.nextFetch) {
+		next := d.nextFetch
+		schedule.NextFetchAt = &next
+	}
+	if now.Before(d.backoffUntil) {
+		until := d.backoffUntil
+		schedule.BackoffUntil = &until
+	}
+	if now.Before(d.raiseUntil) {
+		until := d.raiseUntil
+		schedule.RaisedUntil = &until
+	}
+	return schedule
+}
+
+// Raise lets host take extraPages more crawl fetches per day for the next
+// duration, e.g. to let a large recheck of one site finish. A new raise
+// replaces the previous one; extraPages 0 ends it. Backoff and the
+// Crawl-delay still apply.
+func (b *CrawlBudget) Raise(host string, extraPages int, duration time.Duration) (domain.CrawlSchedule, error) {
+	host = normalizeDomain(host)
+	switch {
+	case host == "":
+		return domain.CrawlSchedule{}, fmt.Errorf("%w: domain is required", domain.ErrInvalidCrawlRaise)
+	case extraPages < 0:
+		return domain.CrawlSchedule{}, fmt.Errorf("%w: extra_pages cannot be negative", domain.ErrInvalidCrawlRaise)
+	case extraPages > 0 && (duration <= 0 || duration > maxCrawlRaise):
+		return domain.CrawlSchedule{}, fmt.Errorf("%w: duration must be positive and at most %s", domain.ErrInvalidCrawlRaise, maxCrawlRaise)
+	}
+
+	b.mu.Lock()
+	now := b.now()
+	d := b.domain(host, now)
+	d.raise, d.raiseUntil = extraPages, now.Add(duration)
+	if extraPages == 0 {
+		d.raiseUntil = time.Time{}
+	}
+	b.mu.Unlock()
+	if extraPages > 0 {
+		log.Printf("Crawl budget of %s raised by %d pages/day for %s", host, extraPages, duration)
+	} else {
+		log.Printf("Crawl budget raise of %s ended", host)
+	}
+	return b.Schedule(host), nil
+}
+
+// limit returns d's daily page cap at now. Callers hold b.mu.
+func (b *CrawlBudget) limit(d *crawlDomain, now time.Time) int {
+	if now.Before(d.raiseUntil) {
+		return b.pagesPerDay + d.raise
+	}
+	return b.pagesPerDay
+}
+
+// RobotsCached returns how many domains have a fresh robots.txt result.
+func (b *CrawlBudget) RobotsCached() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := 0
+	for _, d := range b.domains {
+		if !d.robotsAt.IsZero() && b.now().Sub(d.robotsAt) < robotsTTL {
+			n++
+		}
+	}
+	return n
+}
+
+// domain returns host's state, creating it and pruning idle domains that
+// have no raise running.
+// Callers hold b.mu.
+func (b *CrawlBudget) domain(host string, now time.Time) *crawlDomain {
+	d, ok := b.domains[host]
+	if !ok {
+		for h, other := range b.domains {
+			if now.Sub(other.lastSeen) > crawlStateIdleTTL && !now.Before(other.raiseUntil) {
+				delete(b.domains, h)
+			}
+		}
+		d = &crawlDomain{}
+		b.domains[host] = d
+	}
+	d.lastSeen = now
+	return d
+}
+
+// refreshRobots fetches host's robots.txt when the cached Crawl-delay is
+// stale. Failures are cached too, as no delay.
+func (b *CrawlBudget) refreshRobots(ctx context.Context, origin, host string) {
+	b.mu.Lock()
+	d := b.domain(host, b.now())
+	fresh := !d.robotsAt.IsZero() && b.now().Sub(d.robotsAt) < robotsTTL
+	b.mu.Unlock()
+	if fresh {
+		return
+	}
+
+	delay := b.fetchCrawlDelay(ctx, origin)
+	b.mu.Lock()
+	d = b.domain(host, b.now())
+	d.crawlDelay = min(delay, b.maxCrawlDelay)
+	d.robotsAt = b.now()
+	b.mu.Unlock()
+}
+
+func (b *CrawlBudget) fetchCrawlDelay(ctx context.Context, origin string) time.Duration {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return 0
+	}
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return 0
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0
+	}
+	return parseCrawlDelay(io.LimitReader(resp.Body, 512<<10))
+}
+
+// parseCrawlDelay returns the Crawl-delay of robots.txt's "*" group.
+func parseCrawlDelay(r io.Reader) time.Duration {
+	scanner := bufio.NewScanner(r)
+	inWildcard, inAgents := false, false
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			// Consecutive User-agent lines share one group.
+			if !inAgents {
+				inWildcard = false
+			}
+			inAgents = true
+			if value == "*" {
+				inWildcard = true
+			}
+		case "crawl-delay":
+			inAgents = false
+			if inWildcard {
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					return time.Duration(seconds * float64(time.Second))
+				}
+			}
+		default:
+			inAgents = false
+		}
+	}
+	return 0
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
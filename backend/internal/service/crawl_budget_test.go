@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+func TestParseCrawlDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		robots string
+		want   time.Duration
+	}{
+		{"wildcard group", "User-agent: *\nDisallow: /private\nCrawl-delay: 5\n", 5 * time.Second},
+		{"fractional", "User-agent: *\nCrawl-delay: 0.5 # be gentle\n", 500 * time.Millisecond},
+		{"other agent only", "User-agent: Googlebot\nCrawl-delay: 10\n", 0},
+		{"shared group", "User-agent: Bingbot\nUser-agent: *\nCrawl-delay: 3\n", 3 * time.Second},
+		{"later wildcard group", "User-agent: Bingbot\nCrawl-delay: 9\n\nUser-agent: *\nCrawl-delay: 2\n", 2 * time.Second},
+		{"none", "User-agent: *\nDisallow:\n", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCrawlDelay(strings.NewReader(tt.robots)); got != tt.want {
+				t.Errorf("parseCrawlDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestCrawlBudget returns a budget on a fake clock that records waits
+// instead of sleeping.
+func newTestCrawlBudget(pagesPerDay int, waits *[]time.Duration) (*CrawlBudget, *time.Time) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	b := NewCrawlBudget(pagesPerDay)
+	b.now = func() time.Time { return now }
+	b.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return b, &now
+}
+
+func TestCrawlBudgetDelayAndDailyCap(t *testing.T) {
+	var robotsFetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			robotsFetches.Add(1)
+			w.Write([]byte("User-agent: *\nCrawl-delay: 2\n"))
+		}
+	}))
+	defer srv.Close()
+	target, _ := url.Parse(srv.URL + "/story")
+
+	var waits []time.Duration
+	budget, now := newTestCrawlBudget(2, &waits)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := budget.Acquire(ctx, target); err != nil {
+			t.Fatalf("Acquire #%d: %v", i+1, err)
+		}
+	}
+	if len(waits) != 1 || waits[0] != 2*time.Second {
+		t.Errorf("waits = %v, want one 2s crawl delay", waits)
+	}
+	if err := budget.Acquire(ctx, target); !errors.Is(err, domain.ErrCrawlBudgetExhausted) {
+		t.Errorf("third Acquire = %v, want ErrCrawlBudgetExhausted", err)
+	}
+
+	*now = now.Add(24 * time.Hour)
+	if err := budget.Acquire(ctx, target); err != nil {
+		t.Errorf("Acquire the next day: %v", err)
+	}
+	if got := robotsFetches.Load(); got != 2 {
+		t.Errorf("robots.txt fetched %d times, want once per day", got)
+	}
+
+	stats := budget.Stats()
+	if len(stats) != 1 || stats[0].PagesToday != 1 || stats[0].CrawlDelayMS != 2000 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestCrawlBudgetBackoff(t *testing.T) {
+	target, _ := url.Parse("http://127.0.0.1:1/story") // robots.txt fetch fails: no delay
+	var waits []time.Duration
+	budget, now := newTestCrawlBudget(100, &waits)
+	ctx := context.Background()
+
+	budget.Report("127.0.0.1", http.StatusTooManyRequests, "")
+	if err := budget.Acquire(ctx, target); !errors.Is(err, domain.ErrCrawlBudgetExhausted) {
+		t.Fatalf("Acquire after 429 = %v, want ErrCrawlBudgetExhausted", err)
+	}
+
+	*now = now.Add(minCrawlBackoff)
+	if err := budget.Acquire(ctx, target); err != nil {
+		t.Fatalf("Acquire after backoff: %v", err)
+	}
+
+	// A second refusal doubles the backoff; Retry-After can extend it.
+	budget.Report("127.0.0.1", http.StatusForbidden, "")
+	if until := budget.Stats()[0].BackoffUntil; until == nil || !until.Equal(now.Add(2*minCrawlBackoff)) {
+		t.Errorf("BackoffUntil = %v, want %v", until, now.Add(2*minCrawlBackoff))
+	}
+	budget.Report("127.0.0.1", http.StatusTooManyRequests, "3600")
+	if until := budget.Stats()[0].BackoffUntil; until == nil || !until.Equal(now.Add(time.Hour)) {
+		t.Errorf("BackoffUntil = %v, want Retry-After of 1h", until)
+	}
+
+	budget.Report("127.0.0.1", http.StatusOK, "")
+	if err := budget.Acquire(ctx, target); err != nil {
+		t.Errorf("Acquire after success: %v", err)
+	}
+}
+
+func TestCrawlBudgetScheduleAndRaise(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("User-agent: *\nCrawl-delay: 2\n"))
+	}))
+	defer srv.Close()
+	target, _ := url.Parse(srv.URL + "/story")
+	host := target.Hostname()
+
+	var waits []time.Duration
+	budget, now := newTestCrawlBudget(2, &waits)
+	ctx := context.Background()
+	if got := budget.Schedule("unseen.example"); got.PagesRemaining != 2 || got.Queued != 0 || got.NextFetchAt != nil {
+		t.Errorf("Schedule(unseen) = %+v, want the whole budget", got)
+	}
+	for i := 0; i < 2; i++ {
+		if err := budget.Acquire(ctx, target); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := budget.Schedule(host)
+	if got.PagesRemaining != 0 || got.NextFetchAt == nil || !got.NextFetchAt.Equal(now.Add(4*time.Second)) {
+		t.Errorf("Schedule() = %+v, want no pages left and next fetch in 4s", got)
+	}
+
+	for _, bad := range []struct {
+		pages    int
+		duration time.Duration
+	}{{-1, time.Hour}, {10, 0}, {10, 8 * 24 * time.Hour}} {
+		if _, err := budget.Raise(host, bad.pages, bad.duration); !errors.Is(err, domain.ErrInvalidCrawlRaise) {
+			t.Errorf("Raise(%d, %s) error = %v, want ErrInvalidCrawlRaise", bad.pages, bad.duration, err)
+		}
+	}
+	raised, err := budget.Raise(host, 3, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raised.PagesPerDay != 5 || raised.PagesRemaining != 3 || raised.RaisedUntil == nil {
+		t.Errorf("Raise() = %+v, want 3 more pages for an hour", raised)
+	}
+	if err := budget.Acquire(ctx, target); err != nil {
+		t.Errorf("Acquire after raise: %v", err)
+	}
+
+	*now = now.Add(2 * time.Hour)
+	if err := budget.Acquire(ctx, target); !errors.Is(err, domain.ErrCrawlBudgetExhausted) {
+		t.Errorf("Acquire after the raise expired = %v, want ErrCrawlBudgetExhausted", err)
+	}
+	if _, err := budget.Raise(host, 3, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if ended, _ := budget.Raise(host, 0, 0); ended.PagesPerDay != 2 || ended.RaisedUntil != nil {
+		t.Errorf("ending a raise = %+v", ended)
+	}
+}
+
+func TestCrawlBudgetCountsQueuedFetches(t *testing.T) {
+	target, _ := url.Parse("http://127.0.0.1:1/story")
+	budget := NewCrawlBudget(10)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	budget.now = func() time.Time { return now }
+	budget.domain("127.0.0.1", now).crawlDelay = time.Minute
+	budget.domain("127.0.0.1", now).robotsAt = now
+	release := make(chan struct{})
+	sleeping := make(chan struct{}, 2)
+	budget.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeping <- struct{}{}
+		<-release
+		return nil
+	}
+
+	done := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() { done <- budget.Acquire(context.Background(), target) }()
+	}
+	<-sleeping
+	<-sleeping
+	if got := budget.Schedule("127.0.0.1"); got.Queued != 2 || got.PagesRemaining != 7 {
+		t.Errorf("Schedule() = %+v, want 2 queued and 7 pages left", got)
+	}
+	close(release)
+	for i := 0; i < 3; i++ {
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := budget.Schedule("127.0.0.1"); got.Queued != 0 {
+		t.Errorf("Queued = %d after the fetches started, want 0", got.Queued)
+	}
+}
+
+func TestScraperCrawlBudgetOnlyLimitsCrawls(t *testing.T) {
+	var pageFetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		pageFetches.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	// The URL policy only allows standard ports, so send news.example to
+	// the test server.
+	scraper := NewScraperService()
+	scraper.httpClient = &http.Client{Transport: rewriteTransport{target: srv.URL}}
+	scraper.WithCrawlBudget(NewCrawlBudget(10))
+	ctx := context.Background()
+	story := "http://news.example/story"
+
+	if _, err := scraper.ScrapeArticle(ContextWithCrawl(ctx), story); !errors.Is(err, domain.ErrURLScrapingFailed) {
+		t.Fatalf("first crawl = %v, want the 429 as a scrape failure", err)
+	}
+	if _, err := scraper.ScrapeArticle(ContextWithCrawl(ctx), story); !errors.Is(err, domain.ErrCrawlBudgetExhausted) {
+		t.Errorf("crawl during backoff = %v, want ErrCrawlBudgetExhausted", err)
+	}
+	if _, err := scraper.ScrapeArticle(ctx, story); !errors.Is(err, domain.ErrURLScrapingFailed) {
+		t.Errorf("interactive scrape = %v, want it to reach the site", err)
+	}
+	if got := pageFetches.Load(); got != 2 {
+		t.Errorf("site was fetched %d times, want 2", got)
+	}
+
+	stats := scraper.Stats()
+	if stats.Failures != 2 || len(stats.CrawlBudget) != 1 || stats.CrawlBudget[0].BackoffUntil == nil {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+// rewriteTransport sends every request to target, keeping the path.
+type rewriteTransport struct {
+	target string
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	u, _ := url.Parse(t.target)
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = u.Scheme, u.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Delivery channels
+const (
+	ChannelAlert   = "alert"
+	ChannelReport  = "report"
+	ChannelPush    = "push"
+	ChannelWebhook = "webhook"
+)
+
+// Delivery engine defaults
+const (
+	DefaultDeliveryMaxAttempts     = 5
+	DefaultDeliveryBackoff         = 2 * time.Second
+	DefaultDeliveryErrorBudget     = 0.5 // failure ratio that opens a destination's breaker
+	DefaultDeliveryBreakerCooldown = time.Minute
+	maxDeliveryBackoff             = 5 * time.Minute
+	deliveryBreakerWindow          = 20 // recent outcomes judged per destination
+	deliveryBreakerMinimum         = 5  // outcomes needed before the breaker can open
+	deadLetterTTL                  = 7 * 24 * time.Hour
+)
+
+// errCircuitOpen is recorded for attempts skipped by an open breaker.
+var errCircuitOpen = errors.New("circuit open: destination is failing")
+
+type permanentDeliveryError struct{ err error }
+
+func (e permanentDeliveryError) Error() string { return e.err.Error() }
+func (e permanentDeliveryError) Unwrap() error { return e.err }
+
+// PermanentDeliveryError marks err as not worth retrying, e.g. a 4xx
+// response; the delivery goes straight to the dead-letter list.
+func PermanentDeliveryError(err error) error {
+	return permanentDeliveryError{err: err}
+}
+
+// DeadLetter is a delivery that ran out of attempts.
+type DeadLetter struct {
+	ID          string    `json:"id"`
+	Channel     string    `json:"channel"`
+	Destination string    `json:"destination"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error"`
+	CreatedAt   time.Time `json:"created_at"`
+	FailedAt    time.Time `json:"failed_at"`
+
+	send func(ctx context.Context) error
+}
+
+// DestinationState is one destination's circuit breaker as shown to
+// operators.
+type DestinationState struct {
+	Destination string     `json:"destination"`
+	Attempts    int        `json:"recent_attempts"`
+	Failures    int        `json:"recent_failures"`
+	OpenUntil   *time.Time `json:"open_until,omitempty"`
+}
+
+type destinationState struct {
+	results   []bool // recent outcomes, true = success
+	openUntil time.Time
+	halfOpen  bool // the next failure reopens the breaker at once
+}
+
+// DeliveryEngine sends outbound notifications (alert, report and
+// subscriber webhooks, Web Push) with one retry policy: exponential backoff up to a maximum
+// number of attempts, and a per-destination circuit breaker that opens
+// once recent failures exceed the error budget. While a breaker is open,
+// attempts to that destination are spent without contacting it. Deliveries
+// out of attempts are kept as dead letters for manual redelivery.
+type DeliveryEngine struct {
+	maxAttempts int
+	backoff     time.Duration
+	errorBudget float64
+	cooldown    time.Duration
+	now         func() time.Time
+	sleep       func(ctx context.Context, d time.Duration) error
+
+	mu           sync.Mutex
+	destinations map[string]*destinationState
+	dead         map[string]*DeadLetter
+}
+
+// NewDeliveryEngine creates an engine with the default policy.
+func NewDeliveryEngine() *DeliveryEngine {
+	return &DeliveryEngine{
+		maxAttempts:  DefaultDeliveryMaxAttempts,
+		backoff:      DefaultDeliveryBackoff,
+		errorBudget:  DefaultDeliveryErrorBudget,
+		cooldown:     DefaultDeliveryBreakerCooldown,
+		now:          time.Now,
+		sleep:        sleepContext,
+		destinations: make(map[string]*destinationState),
+		dead:         make(map[string]*DeadLetter),
+	}
+}
+
+// WithRetry sets the attempts per delivery and the first backoff, which
+// doubles after each failed attempt.
+func (e *DeliveryEngine) WithRetry(maxAttempts int, backoff time.Duration) *DeliveryEngine {
+	if maxAttempts > 0 {
+		e.maxAttempts = maxAttempts
+	}
+	if backoff > 0 {
+		e.backoff = backoff
+	}
+	return e
+}
+
+// WithCircuitBreaker sets the failure ratio (0-1] that opens a
+// destination's breaker and how long it stays open.
+func (e *DeliveryEngine) WithCircuitBreaker(errorBudget float64, cooldown time.Duration) *DeliveryEngine {
+	if errorBudget > 0 && errorBudget <= 1 {
+		e.errorBudget = errorBudget
+	}
+	if cooldown > 0 {
+		e.cooldown = cooldown
+	}
+	return e
+}
+
+// Send delivers on channel to destination with send. Without an engine
+// send runs once and its error is returned; otherwise the delivery is
+// retried in the background and Send returns nil.
+func (e *DeliveryEngine) Send(ctx context.Context, channel, destination string, send func(ctx context.Context) error) error {
+	if e == nil {
+		return send(ctx)
+	}
+	letter := &DeadLetter{
+		ID:          uuid.New().String(),
+		Channel:     channel,
+		Destination: destination,
+		CreatedAt:   e.now(),
+		send:        send,
+	}
+	go e.deliver(context.WithoutCancel(ctx), letter)
+	return nil
+}
+
+// DeadLetters returns deliveries that ran out of attempts in the last
+// seven days, newest first.
+func (e *DeliveryEngine) DeadLetters() []DeadLetter {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	cutoff := e.now().Add(-deadLetterTTL)
+	letters := make([]DeadLetter, 0, len(e.dead))
+	for id, d := range e.dead {
+		if d.FailedAt.Before(cutoff) {
+			delete(e.dead, id)
+			continue
+		}
+		letters = append(letters, *d)
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i].FailedAt.After(letters[j].FailedAt) })
+	return letters
+}
+
+// Redeliver takes a dead letter off the list and delivers it again with a
+// fresh set of attempts.
+func (e *DeliveryEngine) Redeliver(ctx context.Context, id string) (*DeadLetter, error) {
+	e.mu.Lock()
+	letter, ok := e.dead[id]
+	delete(e.dead, id)
+	e.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrDeliveryNotFound, id)
+	}
+
+	retry := *letter
+	retry.Attempts, retry.LastError, retry.FailedAt = 0, "", time.Time{}
+	snapshot := retry
+	go e.deliver(context.WithoutCancel(ctx), &retry)
+	return &snapshot, nil
+}
+
+// Destinations returns every destination's breaker state, sorted.
+func (e *DeliveryEngine) Destinations() []DestinationState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	states := make([]DestinationState, 0, len(e.destinations))
+	for name, d := range e.destinations {
+		state := DestinationState{Destination: name, Attempts: len(d.results)}
+		for _, ok := range d.results {
+			if !ok {
+				state.Failures++
+			}
+		}
+		if now.Before(d.openUntil) {
+			until := d.openUntil
+			state.OpenUntil = &until
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Destination < states[j].Destination })
+	return states
+}
+
+// deliver attempts letter until it succeeds, fails permanently or runs out
+// of attempts.
+func (e *DeliveryEngine) deliver(ctx context.Context, letter *DeadLetter) {
+	for {
+		letter.Attempts++
+		wait, err := e.attempt(ctx, letter)
+		if err == nil {
+			return
+		}
+		letter.LastError = err.Error()
+
+		var permanent permanentDeliveryError
+		if errors.As(err, &permanent) || letter.Attempts >= e.maxAttempts {
+			e.mu.Lock()
+			letter.FailedAt = e.now()
+			e.dead[letter.ID] = letter
+			e.mu.Unlock()
+			log.Printf("Warning: %s delivery to %s failed after %d attempts: %v",
+				letter.Channel, letter.Destination, letter.Attempts, err)
+			return
+		}
+		if err := e.sleep(ctx, wait); err != nil {
+			return
+		}
+	}
+}
+
+// attempt makes one attempt unless the destination's breaker is open, and
+// returns the wait before the next one.
+func (e *DeliveryEngine) attempt(ctx context.Context, letter *DeadLetter) (time.Duration, error) {
+	backoff := min(e.backoff<<(letter.Attempts-1), maxDeliveryBackoff)
+
+	e.mu.Lock()
+	d := e.destination(letter.Destination)
+	if open := d.openUntil.Sub(e.now()); open > 0 {
+		e.mu.Unlock()
+		return max(backoff, open), errCircuitOpen
+	}
+	e.mu.Unlock()
+
+	err := letter.send(ctx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	d.results = append(d.results, err == nil)
+	if len(d.results) > deliveryBreakerWindow {
+		d.results = d.results[len(d.results)-deliveryBreakerWindow:]
+	}
+	if err == nil {
+		d.halfOpen = false
+		return 0, nil
+	}
+	failures := 0
+	for _, ok := range d.results {
+		if !ok {
+			failures++
+		}
+	}
+	if d.halfOpen || (len(d.results) >= deliveryBreakerMinimum && float64(failures)/float64(len(d.results)) > e.errorBudget) {
+		d.openUntil = e.now().Add(e.cooldown)
+		d.halfOpen = true
+		d.results = nil
+		log.Printf("Warning: delivery circuit to %s open for %s", letter.Destination, e.cooldown)
+	}
+	return backoff, err
+}
+
+// destination returns name's state, creating it. Callers hold e.mu.
+func (e *DeliveryEngine) destination(name string) *destinationState {
+	d, ok := e.destinations[name]
+	if !ok {
+		d = &destinationState{}
+		e.destinations[name] = d
+	}
+	return d
+}
+
+// deliveryDestination is the host breakers are kept per.
+func deliveryDestination(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return rawURL
+}
+
+// deliveryStatusError turns a webhook response status into an error;
+// client errors other than 408 and 429 are permanent.
+func deliveryStatusError(what string, status int) error {
+	if status < 300 {
+		return nil
+	}
+	err := fmt.Errorf("%s failed: status %d", what, status)
+	if status >= 400 && status < 500 && status != 408 && status != 429 {
+		return PermanentDeliveryError(err)
+	}
+	return err
+}
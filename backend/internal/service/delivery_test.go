@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// newTestDeliveryEngine returns an engine on a fake clock that advances
+// instead of sleeping and records each wait.
+func newTestDeliveryEngine(waits *[]time.Duration) (*DeliveryEngine, *time.Time) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	e := NewDeliveryEngine().WithRetry(4, time.Second).WithCircuitBreaker(0.5, time.Minute)
+	e.now = func() time.Time { return now }
+	e.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		now = now.Add(d)
+		return nil
+	}
+	return e, &now
+}
+
+func TestDeliveryEngineRetriesWithBackoff(t *testing.T) {
+	var waits []time.Duration
+	engine, _ := newTestDeliveryEngine(&waits)
+
+	calls := 0
+	letter := &DeadLetter{ID: "d1", Channel: ChannelAlert, Destination: "hooks.example", send: func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}}
+	engine.deliver(context.Background(), letter)
+
+	if calls != 3 || fmt.Sprint(waits) != "[1s 2s]" {
+		t.Errorf("calls = %d, waits = %v; want 3 calls after 1s and 2s", calls, waits)
+	}
+	if dead := engine.DeadLetters(); len(dead) != 0 {
+		t.Errorf("DeadLetters() = %+v, want none", dead)
+	}
+}
+
+func TestDeliveryEngineDeadLetterAndRedeliver(t *testing.T) {
+	var waits []time.Duration
+	engine, _ := newTestDeliveryEngine(&waits)
+
+	calls := 0
+	letter := &DeadLetter{ID: "d1", Channel: ChannelReport, Destination: "hooks.example", send: func(ctx context.Context) error {
+		calls++
+		return deliveryStatusError("report delivery", 400)
+	}}
+	engine.deliver(context.Background(), letter)
+
+	dead := engine.DeadLetters()
+	if calls != 1 || len(dead) != 1 || dead[0].Attempts != 1 {
+		t.Fatalf("calls = %d, dead letters = %+v; want a permanent failure dead-lettered at once", calls, dead)
+	}
+	if _, err := engine.Redeliver(context.Background(), "d1"); err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+	if _, err := engine.Redeliver(context.Background(), "d1"); !errors.Is(err, domain.ErrDeliveryNotFound) {
+		t.Errorf("second Redeliver = %v, want ErrDeliveryNotFound", err)
+	}
+}
+
+func TestDeliveryEngineCircuitBreaker(t *testing.T) {
+	var waits []time.Duration
+	engine, now := newTestDeliveryEngine(&waits)
+	engine.WithRetry(1, time.Second)
+
+	calls := 0
+	failing := func(ctx context.Context) error {
+		calls++
+		return errors.New("status 503")
+	}
+	for i := 0; i < deliveryBreakerMinimum; i++ {
+		engine.deliver(context.Background(), &DeadLetter{ID: fmt.Sprint(i), Destination: "push.example", send: failing})
+	}
+	states := engine.Destinations()
+	if len(states) != 1 || states[0].OpenUntil == nil {
+		t.Fatalf("Destinations() = %+v, want an open breaker", states)
+	}
+
+	// While open, attempts are used up without contacting the destination.
+	engine.deliver(context.Background(), &DeadLetter{ID: "skipped", Destination: "push.example", send: failing})
+	if calls != deliveryBreakerMinimum {
+		t.Errorf("destination called %d times, want %d", calls, deliveryBreakerMinimum)
+	}
+
+	// After the cooldown one success closes it again.
+	*now = now.Add(time.Minute)
+	engine.deliver(context.Background(), &DeadLetter{ID: "ok", Destination: "push.example", send: func(ctx context.Context) error { return nil }})
+	if states := engine.Destinations(); states[0].OpenUntil != nil {
+		t.Errorf("breaker still open after a success: %+v", states)
+	}
+	if dead := engine.DeadLetters(); len(dead) != deliveryBreakerMinimum+1 {
+		t.Errorf("DeadLetters() = %d, want %d", len(dead), deliveryBreakerMinimum+1)
+	}
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// Document media types the scraper extracts text from by default
+const (
+	PDFMediaType  = "application/pdf"
+	DOCXMediaType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+	octetStreamMediaType = "application/octet-stream"
+)
+
+// maxDocumentText caps the bytes a document may decompress to, so a small
+// download cannot inflate into gigabytes.
+const maxDocumentText = 32 << 20
+
+// DocumentText is the text and metadata extracted from a document.
+type DocumentText struct {
+	Title      string
+	Author     string
+	Paragraphs []string
+}
+
+// DocumentExtractor extracts text from a downloaded document, such as a
+// press release or government notice published as a PDF.
+type DocumentExtractor interface {
+	// Name identifies the extractor in scrape diagnostics and provenance
+	Name() string
+	// Extract stops with ctx's error once ctx is done
+	Extract(ctx context.Context, data []byte) (*DocumentText, error)
+}
+
+// defaultDocumentExtractors returns the extractors a new scraper starts with
+func defaultDocumentExtractors() map[string]DocumentExtractor {
+	return map[string]DocumentExtractor{
+		PDFMediaType:  PDFTextExtractor{},
+		DOCXMediaType: DOCXTextExtractor{},
+	}
+}
+
+// WithDocumentExtractor sets the extractor for documents of a media type.
+// A nil extractor stops the scraper accepting that type.
+func (s *ScraperService) WithDocumentExtractor(mediaType string, extractor DocumentExtractor) *ScraperService {
+	if extractor == nil {
+		delete(s.documents, mediaType)
+		return s
+	}
+	s.documents[mediaType] = extractor
+	return s
+}
+
+// isDocument reports whether responses of mediaType go to a document
+// extractor.
+func (s *ScraperService) isDocument(mediaType string) bool {
+	return s.documents[mediaType] != nil
+}
+
+// sniffDocument recognizes a document served as application/octet-stream,
+// as many servers send PDFs, from its first 512 bytes. It returns the
+// document's media type, or octet-stream for anything else, which the
+// scraper rejects without reading further, and a reader that replays
+// the sniffed bytes before the rest of body.
+func sniffDocument(body io.Reader, urlPath string) (string, io.Reader) {
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(body, head)
+	head = head[:n]
+	return sniffDocumentType(head, urlPath), io.MultiReader(bytes.NewReader(head), body)
+}
+
+// extractDocument turns a document downloaded from host, ending at u after
+// redirects, into a scrape result.
+func (s *ScraperService) extractDocument(ctx context.Context, host string, u *url.URL, mediaType string, data []byte) (*ScrapeResult, error) {
+	extractor := s.documents[mediaType]
+	if extractor == nil {
+		return nil, fmt.Errorf("%w: %s is not a supported document", domain.ErrUnsupportedContentType, mediaType)
+	}
+	doc, err := extractor.Extract(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrURLScrapingFailed, err)
+	}
+
+	result := &ScrapeResult{
+		Title:       doc.Title,
+		Author:      doc.Author,
+		Source:      host,
+		FinalURL:    u.String(),
+		Canonical:   NormalizeURL(u.String()),
+		FaviconURL:  resolveAssetURL(u, "/favicon.ico"),
+		Extractor:   extractor.Name(),
+		ContentType: mediaType,
+	}
+	if result.Title == "" {
+		result.Title = documentFilename(u)
+	}
+	var parts []string
+	for _, p := range doc.Paragraphs {
+		p = strings.Join(strings.Fields(p), " ")
+		if p == "" {
+			continue
+		}
+		parts = append(parts, p)
+		if result.Lead == "" && len(p) > 80 {
+			result.Lead = p
+		}
+	}
+	result.Text = strings.Join(parts, " ")
+	return result, nil
+}
+
+// sniffDocumentType recognizes a document from its leading bytes. DOCX files are ZIP archives, so they also
+// need the extension.
+func sniffDocumentType(data []byte, urlPath string) string {
+	switch {
+	case bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\r\n "), []byte("%PDF-")):
+		return PDFMediaType
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")) && strings.EqualFold(path.Ext(urlPath), ".docx"):
+		return DOCXMediaType
+	}
+	return octetStreamMediaType
+}
+
+// documentFilename titles a document without metadata after its file name.
+func documentFilename(u *url.URL) string {
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return ""
+	}
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// DOCXTextExtractor extracts the paragraphs of a Word document's body and
+// its core title and author. Headers, footers and comments are left out.
+type DOCXTextExtractor struct{}
+
+// Name implements DocumentExtractor.
+func (DOCXTextExtractor) Name() string { return "docx-text" }
+
+// Extract implements DocumentExtractor.
+func (DOCXTextExtractor) Extract(ctx context.Context, data []byte) (*DocumentText, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not a DOCX file: %v", err)
+	}
+	doc := &DocumentText{}
+	var body *zip.File
+	for _, f := range archive.File {
+		switch f.Name {
+		case "word/document.xml":
+			body = f
+		case "docProps/core.xml":
+			// Metadata is optional; a broken core.xml is ignored
+			readDOCXCore(f, doc)
+		}
+	}
+	if body == nil {
+		return nil, errors.New("not a DOCX file: word/document.xml is missing")
+	}
+
+	rc, err := body.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open document body: %v", err)
+	}
+	defer rc.Close()
+	dec := xml.NewDecoder(io.LimitReader(rc, maxDocumentText))
+	var paragraph strings.Builder
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse document body: %v", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab", "br", "cr":
+				paragraph.WriteByte(' ')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				doc.Paragraphs = append(doc.Paragraphs, paragraph.String())
+				paragraph.Reset()
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+			}
+		case xml.CharData:
+			if inText {
+				paragraph.Write(t)
+			}
+		}
+	}
+	if paragraph.Len() > 0 {
+		doc.Paragraphs = append(doc.Paragraphs, paragraph.String())
+	}
+	return doc, nil
+}
+
+// readDOCXCore reads the title and author from docProps/core.xml.
+func readDOCXCore(f *zip.File, doc *DocumentText) {
+	rc, err := f.Open()
+	if err != nil {
+		return
+	}
+	defer rc.Close()
+	var core struct {
+		Title   string `xml:"title"`
+		Creator string `xml:"creator"`
+	}
+	if xml.NewDecoder(io.LimitReader(rc, 1<<20)).Decode(&core) == nil {
+		doc.Title = strings.TrimSpace(core.Title)
+		doc.Author = strings.TrimSpace(core.Creator)
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// testPDF builds a PDF with one plain and one Flate-compressed page.
+func testPDF(t *testing.T) []byte {
+	t.Helper()
+	page1 := "BT /F1 12 Tf 72 720 Td (Ministry of Health notice) Tj 0 -14 Td " +
+		"[(The ministry con) -20 (firmed on Friday that clinics) -300 (will stay open.)] TJ ET"
+	page2 := "BT /F1 12 Tf 1 0 0 1 72 720 Tm (Vaccination appointments \\(walk-ins\\) remain free) Tj " +
+		"1 0 0 1 72 706 Tm <416c6c20726567696f6e73> Tj ET"
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write([]byte(page2))
+	zw.Close()
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	b.WriteString("1 0 obj\n<< /Title (Clinic hours notice) /Author <FEFF004D006F0048> >>\nendobj\n")
+	fmt.Fprintf(&b, "4 0 obj\n<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(page1), page1)
+	fmt.Fprintf(&b, "5 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", compressed.Len())
+	b.Write(compressed.Bytes())
+	b.WriteString("\nendstream\nendobj\n")
+	b.WriteString("6 0 obj\n<< /Type /XObject /Subtype /Image /Length 8 >>\nstream\n(Tj) Tj \nendstream\nendobj\n%%EOF\n")
+	return b.Bytes()
+}
+
+// testDOCX builds a Word document with a title and two paragraphs.
+func testDOCX(t *testing.T) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	files := map[string]string{
+		"docProps/core.xml": `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Budget statement</dc:title><dc:creator>Treasury Press Office</dc:creator></cp:coreProperties>`,
+		"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+			`<w:p><w:r><w:t>The treasury </w:t></w:r><w:r><w:t>published the budget</w:t></w:r><w:r><w:tab/><w:t>today.</w:t></w:r></w:p>` +
+			`<w:p><w:r><w:t>Spending on schools rises by four percent over the next fiscal year.</w:t></w:r></w:p>` +
+			`</w:body></w:document>`,
+	}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestPDFTextExtractor(t *testing.T) {
+	doc, err := PDFTextExtractor{}.Extract(context.Background(), testPDF(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "Clinic hours notice" || doc.Author != "MoH" {
+		t.Errorf("metadata = %q by %q, want the document information", doc.Title, doc.Author)
+	}
+	want := []string{
+		"Ministry of Health notice",
+		"The ministry confirmed on Friday that clinics will stay open.",
+		"Vaccination appointments (walk-ins) remain free",
+		"All regions",
+	}
+	if strings.Join(doc.Paragraphs, "|") != strings.Join(want, "|") {
+		t.Errorf("paragraphs = %q, want %q", doc.Paragraphs, want)
+	}
+
+	if _, err := (PDFTextExtractor{}).Extract(context.Background(), []byte("<html></html>")); err == nil {
+		t.Error("HTML was accepted as a PDF")
+	}
+	// Glyph IDs of fonts without a single-byte encoding are not text
+	cid := []byte("%PDF-1.7\n1 0 obj\n<< /Length 40 >>\nstream\nBT <0024004500460047> Tj ET\nendstream\nendobj\n")
+	if doc, err := (PDFTextExtractor{}).Extract(context.Background(), cid); err != nil || len(doc.Paragraphs) != 0 {
+		t.Errorf("glyph ID text = %q, %v; want nothing", doc.Paragraphs, err)
+	}
+	// Deeply nested arrays are refused rather than overflowing the stack
+	deep := []byte("%PDF-1.7\n1 0 obj\n<< >>\nstream\n" + strings.Repeat("[", 1<<20) + "\nendstream\nendobj\n")
+	if _, err := (PDFTextExtractor{}).Extract(context.Background(), deep); !errors.Is(err, errPDFTooDeep) {
+		t.Errorf("nested arrays: err = %v, want errPDFTooDeep", err)
+	}
+	// Stream keywords are found in one pass: this took seconds when each
+	// one searched back through the whole file
+	keywords := []byte("%PDF-1.7\n" + strings.Repeat(">> stream ", 1<<16))
+	if _, err := (PDFTextExtractor{}).Extract(context.Background(), keywords); err != nil {
+		t.Errorf("stream keywords: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (PDFTextExtractor{}).Extract(ctx, testPDF(t)); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled extraction: err = %v, want context.Canceled", err)
+	}
+}
+
+func TestDOCXTextExtractor(t *testing.T) {
+	doc, err := DOCXTextExtractor{}.Extract(context.Background(), testDOCX(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "Budget statement" || doc.Author != "Treasury Press Office" {
+		t.Errorf("metadata = %q by %q", doc.Title, doc.Author)
+	}
+	if len(doc.Paragraphs) != 2 || doc.Paragraphs[0] != "The treasury published the budget today." {
+		t.Errorf("paragraphs = %q", doc.Paragraphs)
+	}
+	if _, err := (DOCXTextExtractor{}).Extract(context.Background(), []byte("PK\x03\x04 truncated")); err == nil {
+		t.Error("a broken archive was accepted")
+	}
+}
+
+func TestScrapeDocuments(t *testing.T) {
+	pdf, docx := testPDF(t), testDOCX(t)
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/notices/clinics.pdf":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(pdf)
+		case "/statements/budget-2026.docx":
+			w.Header().Set("Content-Type", DOCXMediaType)
+			w.Write(docx)
+		case "/download":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(bytes.Repeat([]byte{0x7f, 'E', 'L', 'F'}, 1<<16))
+		case "/scanned.pdf":
+			w.Header().Set("Content-Type", PDFMediaType)
+			w.Write([]byte("%PDF-1.4\n%%EOF\n"))
+		}
+	}))
+	defer site.Close()
+	scraper := NewScraperService()
+	scraper.httpClient = &http.Client{Transport: rewriteTransport{target: site.URL}}
+	ctx := context.Background()
+
+	result, err := scraper.ScrapeArticle(ctx, "https://health.example/notices/clinics.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ContentType != PDFMediaType || result.Extractor != "pdf-text" || result.Title != "Clinic hours notice" {
+		t.Errorf("pdf result = %s via %s titled %q", result.ContentType, result.Extractor, result.Title)
+	}
+	if !strings.Contains(result.Text, "clinics will stay open. Vaccination") {
+		t.Errorf("pdf text = %q", result.Text)
+	}
+
+	result, err = scraper.ScrapeArticle(ctx, "https://treasury.example/statements/budget-2026.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ContentType != DOCXMediaType || result.Diagnostics.Extractor != "docx-text" || result.Source != "treasury.example" {
+		t.Errorf("docx result = %s via %s from %s", result.ContentType, result.Diagnostics.Extractor, result.Source)
+	}
+
+	// Other binary downloads are refused after sniffing their first bytes
+	if _, err := scraper.ScrapeArticle(ctx, "https://health.example/download"); !errors.Is(err, domain.ErrUnsupportedContentType) {
+		t.Errorf("binary download err = %v, want unsupported content type", err)
+	}
+	if _, err := scraper.ScrapeArticle(ctx, "https://health.example/scanned.pdf"); !errors.Is(err, domain.ErrURLScrapingFailed) {
+		t.Errorf("textless PDF err = %v, want a scraping failure", err)
+	}
+
+	scraper.WithDocumentExtractor(DOCXMediaType, nil)
+	if _, err := scraper.ScrapeArticle(ctx, "https://treasury.example/statements/budget-2026.docx"); !errors.Is(err, domain.ErrUnsupportedContentType) {
+		t.Errorf("disabled DOCX err = %v, want unsupported content type", err)
+	}
+}
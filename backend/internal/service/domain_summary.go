@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// reputationPriorWeight is how many neutral pseudo-observations are mixed
+// into a domain's reputation, so a handful of verdicts cannot swing it to
+// an extreme.
+const reputationPriorWeight = 5.0
+
+// validDomain matches a bare DNS hostname.
+var validDomain = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// DomainSummaryService builds cached per-domain trust summaries.
+type DomainSummaryService struct {
+	repository NewsRepository
+	registry   *SourceRegistry
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*domain.DomainSummary
+}
+
+// NewDomainSummaryService creates a summary service caching results for ttl.
+func NewDomainSummaryService(repo NewsRepository, registry *SourceRegistry, ttl time.Duration) *DomainSummaryService {
+	return &DomainSummaryService{
+		repository: repo,
+		registry:   registry,
+		ttl:        ttl,
+		now:        time.Now,
+		cache:      make(map[string]*domain.DomainSummary),
+	}
+}
+
+// TTL returns how long summaries are cached.
+func (s *DomainSummaryService) TTL() time.Duration {
+	return s.ttl
+}
+
+// Summary returns the trust summary for a domain.
+func (s *DomainSummaryService) Summary(ctx context.Context, host string) (*domain.DomainSummary, error) {
+	host = normalizeDomain(host)
+	if !validDomain.MatchString(host) {
+		return nil, fmt.Errorf("%w: %q", domain.ErrInvalidDomain, host)
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	cached, ok := s.cache[host]
+	s.mu.Unlock()
+	if ok && now.Sub(cached.GeneratedAt) < s.ttl {
+		return cached, nil
+	}
+
+	predictions, err := s.repository.Query(ctx, *domain.NewPredictionQuery().WithDomain(host))
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &domain.DomainSummary{Domain: host, GeneratedAt: now}
+	summary.Source, _ = s.registry.Lookup(host)
+
+	var realScore, confidence float64
+	for _, p := range predictions {
+		summary.Stats.Total++
+		switch p.Result {
+		case "FAKE":
+			summary.Stats.Fake++
+		case "REAL":
+			summary.Stats.Real++
+		}
+		realScore += p.RealProbability
+		confidence += p.Confidence
+		if summary.LastAnalyzedAt == nil || p.CreatedAt.After(*summary.LastAnalyzedAt) {
+			createdAt := p.CreatedAt
+			summary.LastAnalyzedAt = &createdAt
+		}
+	}
+	if summary.Stats.Total > 0 {
+		summary.Stats.AvgConfidence = confidence / float64(summary.Stats.Total)
+	}
+	summary.Reputation = (realScore + 0.5*reputationPriorWeight) / (float64(summary.Stats.Total) + reputationPriorWeight)
+
+	s.mu.Lock()
+	s.cache[host] = summary
+	if len(s.cache) > 10000 {
+		s.evictExpired(now)
+	}
+	s.mu.Unlock()
+
+	return summary, nil
+}
+
+// Invalidate drops the cached summaries a prediction change affects: its
+// source host's and every parent domain's, since summaries include
+// subdomains.
+func (s *DomainSummaryService) Invalidate(change domain.PredictionChange) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, source := range change.Sources() {
+		for host := normalizeDomain(source); host != ""; {
+			delete(s.cache, host)
+			dot := strings.IndexByte(host, '.')
+			if dot < 0 {
+				break
+			}
+			host = host[dot+1:]
+		}
+	}
+}
+
+// evictExpired drops stale cache entries. Callers must hold s.mu.
+func (s *DomainSummaryService) evictExpired(now time.Time) {
+	for host, summary := range s.cache {
+		if now.Sub(summary.GeneratedAt) >= s.ttl {
+			delete(s.cache, host)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestDomainSummary(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	repo := memory.NewPredictionRepository()
+	for i, p := range []*domain.Prediction{
+		{Result: "REAL", RealProbability: 0.9, Confidence: 0.9, ArticleSource: "www.example.com", CreatedAt: now.Add(-2 * time.Hour)},
+		{Result: "REAL", RealProbability: 0.8, Confidence: 0.8, ArticleSource: "news.example.com", CreatedAt: now.Add(-time.Hour)},
+		{Result: "FAKE", RealProbability: 0.2, Confidence: 0.8, ArticleSource: "example.com", CreatedAt: now.Add(-3 * time.Hour)},
+		{Result: "FAKE", RealProbability: 0.1, Confidence: 0.9, ArticleSource: "other.org", CreatedAt: now},
+	} {
+		p.ID = string(rune('a' + i))
+		repo.CreatePrediction(p)
+	}
+	registry := NewSourceRegistry([]domain.Source{{Domain: "www.Example.com", Name: "Example News"}})
+
+	svc := NewDomainSummaryService(repo, registry, time.Minute)
+	svc.now = func() time.Time { return now }
+
+	summary, err := svc.Summary(context.Background(), "WWW.example.com")
+	if err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+	if summary.Domain != "example.com" || summary.Source == nil || summary.Source.Name != "Example News" {
+		t.Errorf("unexpected domain/source: %+v", summary)
+	}
+	if summary.Stats.Total != 3 || summary.Stats.Real != 2 || summary.Stats.Fake != 1 {
+		t.Errorf("unexpected stats: %+v", summary.Stats)
+	}
+	if want := (1.9 + 2.5) / 8; summary.Reputation < want-1e-9 || summary.Reputation > want+1e-9 {
+		t.Errorf("Reputation = %v, want %v", summary.Reputation, want)
+	}
+	if summary.LastAnalyzedAt == nil || !summary.LastAnalyzedAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("LastAnalyzedAt = %v", summary.LastAnalyzedAt)
+	}
+
+	// Cached until the TTL expires.
+	repo.CreatePrediction(&domain.Prediction{ID: "z", Result: "FAKE", ArticleSource: "example.com", CreatedAt: now})
+	if cached, _ := svc.Summary(context.Background(), "example.com"); cached.Stats.Total != 3 {
+		t.Errorf("expected cached summary, got total %d", cached.Stats.Total)
+	}
+	now = now.Add(2 * time.Minute)
+	if fresh, _ := svc.Summary(context.Background(), "example.com"); fresh.Stats.Total != 4 {
+		t.Errorf("expected refreshed summary, got total %d", fresh.Stats.Total)
+	}
+
+	unknown, err := svc.Summary(context.Background(), "unknown.net")
+	if err != nil || unknown.Source != nil || unknown.Reputation != 0.5 || unknown.LastAnalyzedAt != nil {
+		t.Errorf("unexpected summary for unknown domain: %+v, %v", unknown, err)
+	}
+
+	for _, bad := range []string{"", "localhost", "exa mple.com", "http://example.com"} {
+		if _, err := svc.Summary(context.Background(), bad); !errors.Is(err, domain.ErrInvalidDomain) {
+			t.Errorf("Summary(%q) error = %v, want ErrInvalidDomain", bad, err)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// Drift detection defaults
+const (
+	DefaultDriftRecentWindow   = 24 * time.Hour
+	DefaultDriftBaselineWindow = 7 * 24 * time.Hour
+	DefaultDriftMinSamples     = 50
+	DefaultDriftInterval       = time.Hour
+)
+
+// PSI thresholds for the drift levels
+const (
+	psiModerate = 0.1
+	psiMajor    = 0.25
+)
+
+// driftEpsilon stands in for empty bins so PSI and KL stay finite.
+const driftEpsilon = 1e-4
+
+// scoreBins is how many fake probability bins the score distribution has.
+const scoreBins = 10
+
+// DriftMonitor compares the label and confidence distribution of recent
+// model verdicts with the window before it, and alerts when it shifts:
+// either the model or the mix of incoming content changed.
+type DriftMonitor struct {
+	repo       NewsRepository
+	alerter    Alerter
+	recent     time.Duration
+	baseline   time.Duration
+	minSamples int
+	now        func() time.Time
+
+	mu     sync.Mutex
+	latest *domain.DriftReport
+	level  string // level last alerted on
+}
+
+// NewDriftMonitor creates a drift monitor over repo's predictions. A nil
+// alerter logs alerts.
+func NewDriftMonitor(repo NewsRepository, alerter Alerter) *DriftMonitor {
+	if alerter == nil {
+		alerter = LogAlerter{}
+	}
+	return &DriftMonitor{
+		repo:       repo,
+		alerter:    alerter,
+		recent:     DefaultDriftRecentWindow,
+		baseline:   DefaultDriftBaselineWindow,
+		minSamples: DefaultDriftMinSamples,
+		now:        time.Now,
+		level:      domain.DriftNone,
+	}
+}
+
+// WithWindows sets the recent window and the baseline window right
+// before it.
+func (m *DriftMonitor) WithWindows(recent, baseline time.Duration) *DriftMonitor {
+	if recent > 0 {
+		m.recent = recent
+	}
+	if baseline > 0 {
+		m.baseline = baseline
+	}
+	return m
+}
+
+// WithMinSamples sets how many predictions each window needs before
+// drift is judged.
+func (m *DriftMonitor) WithMinSamples(n int) *DriftMonitor {
+	if n > 0 {
+		m.minSamples = n
+	}
+	return m
+}
+
+// Compute compares the windows ending now. recent and baseline override
+// the configured windows when positive; filter narrows the predictions
+// (its date range, ordering and paging are ignored).
+func (m *DriftMonitor) Compute(ctx context.Context, filter domain.PredictionQuery, recent, baseline time.Duration) (*domain.DriftReport, error) {
+	if recent <= 0 {
+		recent = m.recent
+	}
+	if baseline <= 0 {
+		baseline = m.baseline
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := m.now().UTC()
+	report := &domain.DriftReport{
+		Recent:     domain.Period{Since: now.Add(-recent), Until: now},
+		Baseline:   domain.Period{Since: now.Add(-recent - baseline), Until: now.Add(-recent)},
+		ComputedAt: now,
+	}
+	baseLabels, baseScores, err := m.histogram(ctx, filter, report.Baseline, &report.BaselineCount)
+	if err != nil {
+		return nil, err
+	}
+	recentLabels, recentScores, err := m.histogram(ctx, filter, report.Recent, &report.RecentCount)
+	if err != nil {
+		return nil, err
+	}
+
+	labelNames := []string{domain.LabelFake, domain.LabelReal}
+	scoreNames := make([]string, scoreBins)
+	for i := range scoreNames {
+		scoreNames[i] = fmt.Sprintf("%.1f-%.1f", float64(i)/scoreBins, float64(i+1)/scoreBins)
+	}
+	report.Label = compareDistributions(labelNames, baseLabels, recentLabels)
+	report.Score = compareDistributions(scoreNames, baseScores, recentScores)
+
+	if report.BaselineCount < m.minSamples || report.RecentCount < m.minSamples {
+		report.Label.Level = domain.DriftInsufficient
+		report.Score.Level = domain.DriftInsufficient
+		report.Level = domain.DriftInsufficient
+		return report, nil
+	}
+	report.Level = report.Label.Level
+	if driftRank(report.Score.Level) > driftRank(report.Level) {
+		report.Level = report.Score.Level
+	}
+	return report, nil
+}
+
+// histogram counts the model verdicts in period by label and by fake
+// probability bin. Verdicts the model did not reach (trusted sources,
+// blocklists, provisional answers) are left out.
+func (m *DriftMonitor) histogram(ctx context.Context, filter domain.PredictionQuery, period domain.Period, count *int) ([]float64, []float64, error) {
+	q := filter
+	q.Since, q.Until = period.Since, period.Until
+	q.Limit, q.Offset = 0, 0
+
+	labels := make([]float64, 2)
+	scores := make([]float64, scoreBins)
+	err := m.repo.Iterate(ctx, q, func(p *domain.Prediction) error {
+		if (p.Method != "" && p.Method != domain.MethodModel) || p.Provisional {
+			return nil
+		}
+		*count++
+		if p.Result == domain.LabelFake {
+			labels[0]++
+		} else {
+			labels[1]++
+		}
+		bin := int(p.FakeProbability * scoreBins)
+		scores[max(0, min(bin, scoreBins-1))]++
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load predictions: %w", err)
+	}
+	return labels, scores, nil
+}
+
+// compareDistributions computes PSI and KL(recent || baseline) over
+// normalized counts.
+func compareDistributions(names []string, baseline, recent []float64) domain.DistributionDrift {
+	base, rec := normalize(baseline), normalize(recent)
+	drift := domain.DistributionDrift{Bins: make([]domain.DriftBin, len(names))}
+	for i, name := range names {
+		drift.Bins[i] = domain.DriftBin{Bin: name, Baseline: base[i], Recent: rec[i]}
+		b, r := math.Max(base[i], driftEpsilon), math.Max(rec[i], driftEpsilon)
+		drift.PSI += (r - b) * math.Log(r/b)
+		drift.KL += r * math.Log(r/b)
+	}
+	switch {
+	case drift.PSI >= psiMajor:
+		drift.Level = domain.DriftMajor
+	case drift.PSI >= psiModerate:
+		drift.Level = domain.DriftModerate
+	default:
+		drift.Level = domain.DriftNone
+	}
+	return drift
+}
+
+func normalize(counts []float64) []float64 {
+	var total float64
+	for _, c := range counts {
+		total += c
+	}
+	shares := make([]float64, len(counts))
+	if total == 0 {
+		return shares
+	}
+	for i, c := range counts {
+		shares[i] = c / total
+	}
+	return shares
+}
+
+// driftRank orders levels by severity.
+func driftRank(level string) int {
+	switch level {
+	case domain.DriftMajor:
+		return 2
+	case domain.DriftModerate:
+		return 1
+	}
+	return 0
+}
+
+// Latest returns the report of the last scheduled evaluation, or nil.
+func (m *DriftMonitor) Latest() *domain.DriftReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.latest
+}
+
+// Evaluate computes drift over all predictions with the configured
+// windows and alerts when the level changes. Too little data keeps the
+// previous alert state.
+func (m *DriftMonitor) Evaluate(ctx context.Context) (*domain.DriftReport, error) {
+	report, err := m.Compute(ctx, domain.PredictionQuery{}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.latest = report
+	previous := m.level
+	if report.Level != domain.DriftInsufficient {
+		m.level = report.Level
+	}
+	current := m.level
+	m.mu.Unlock()
+
+	if current == previous {
+		return report, nil
+	}
+	alert := Alert{
+		Name:    "PredictionDrift",
+		Labels:  map[string]string{"level": current},
+		FiredAt: report.ComputedAt,
+	}
+	switch current {
+	case domain.DriftMajor:
+		alert.Severity = SeverityCritical
+	case domain.DriftModerate:
+		alert.Severity = SeverityWarning
+	default:
+		alert.Severity = SeverityResolved
+	}
+	if current == domain.DriftNone {
+		alert.Summary = "prediction distribution is back in line with its baseline"
+	} else {
+		alert.Summary = fmt.Sprintf("%s drift in predictions: label PSI %.3f, score PSI %.3f (fake share %.1f%% vs %.1f%% baseline)",
+			current, report.Label.PSI, report.Score.PSI, report.Label.Bins[0].Recent*100, report.Label.Bins[0].Baseline*100)
+	}
+	if err := m.alerter.Fire(alert); err != nil {
+		log.Printf("Warning: failed to deliver alert %s: %v", alert.Name, err)
+	}
+	return report, nil
+}
+
+// Run evaluates drift every interval until ctx is cancelled.
+func (m *DriftMonitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := m.Evaluate(ctx); err != nil {
+				log.Printf("Warning: drift evaluation failed: %v", err)
+			}
+		}
+	}
+}
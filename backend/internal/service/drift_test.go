@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestDriftMonitorDetectsShiftAndAlerts(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	repo := memory.NewPredictionRepository()
+	next := 0
+	add := func(at time.Time, n int, label string, fakeProb float64) {
+		for i := 0; i < n; i++ {
+			next++
+			p := &domain.Prediction{
+				ID:              fmt.Sprintf("p%d", next),
+				Result:          label,
+				FakeProbability: fakeProb,
+				Method:          domain.MethodModel,
+				CreatedAt:       at,
+			}
+			if err := repo.CreatePrediction(p); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// Baseline: a 30/70 fake/real mix
+	add(now.Add(-72*time.Hour), 30, domain.LabelFake, 0.85)
+	add(now.Add(-72*time.Hour), 70, domain.LabelReal, 0.15)
+
+	alerter := &recordingAlerter{}
+	monitor := NewDriftMonitor(repo, alerter).WithMinSamples(20)
+	monitor.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	report, err := monitor.Evaluate(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Level != domain.DriftInsufficient || report.BaselineCount != 100 || report.RecentCount != 0 {
+		t.Fatalf("report = %+v, want insufficient data", report)
+	}
+
+	// The same mix recently is stable; entries not decided by the model
+	// are ignored.
+	add(now.Add(-time.Hour), 9, domain.LabelFake, 0.85)
+	add(now.Add(-time.Hour), 21, domain.LabelReal, 0.15)
+	if err := repo.CreatePrediction(&domain.Prediction{ID: "blocked", Result: domain.LabelFake, Method: domain.MethodOrgPolicy, CreatedAt: now.Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if report, _ = monitor.Evaluate(ctx); report.Level != domain.DriftNone || report.RecentCount != 30 {
+		t.Fatalf("report = %+v, want no drift over 30 predictions", report)
+	}
+	if len(alerter.alerts) != 0 {
+		t.Fatalf("alerts = %v, want none", alerter.alerts)
+	}
+
+	// A flood of fakes shifts both distributions.
+	add(now.Add(-time.Hour), 60, domain.LabelFake, 0.95)
+	report, _ = monitor.Evaluate(ctx)
+	if report.Level != domain.DriftMajor || report.Label.PSI < psiMajor || report.Score.KL <= 0 {
+		t.Fatalf("report = %+v, want major drift", report)
+	}
+	if len(alerter.alerts) != 1 || alerter.alerts[0].Name != "PredictionDrift" || alerter.alerts[0].Severity != SeverityCritical {
+		t.Fatalf("alerts = %v, want one critical PredictionDrift", alerter.alerts)
+	}
+	if monitor.Latest() != report {
+		t.Error("Latest() should return the last evaluation")
+	}
+
+	// Once the shift is a day old it is part of the baseline.
+	monitor.now = func() time.Time { return now.Add(26 * time.Hour) }
+	add(now.Add(25*time.Hour), 6, domain.LabelFake, 0.85)
+	add(now.Add(25*time.Hour), 10, domain.LabelFake, 0.95)
+	add(now.Add(25*time.Hour), 14, domain.LabelReal, 0.15)
+	report, _ = monitor.Evaluate(ctx)
+	if report.Level != domain.DriftNone {
+		t.Fatalf("report = %+v, want the drift to settle", report)
+	}
+	if len(alerter.alerts) != 2 || alerter.alerts[1].Severity != SeverityResolved {
+		t.Errorf("alerts = %v, want a resolution", alerter.alerts)
+	}
+}
+
+func TestDriftMonitorComputeWindows(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	repo := memory.NewPredictionRepository()
+	monitor := NewDriftMonitor(repo, nil)
+	monitor.now = func() time.Time { return now }
+
+	report, err := monitor.Compute(context.Background(), domain.PredictionQuery{}, 6*time.Hour, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Recent.Since.Equal(now.Add(-6*time.Hour)) || !report.Baseline.Since.Equal(now.Add(-30*time.Hour)) ||
+		!report.Baseline.Until.Equal(report.Recent.Since) {
+		t.Errorf("windows = %+v / %+v", report.Baseline, report.Recent)
+	}
+	if len(report.Score.Bins) != scoreBins || report.Score.Bins[0].Bin != "0.0-0.1" {
+		t.Errorf("score bins = %+v", report.Score.Bins)
+	}
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+	"github.com/google/uuid"
+)
+
+// DefaultMaxEvaluationRows caps the rows accepted in one dataset.
+const DefaultMaxEvaluationRows = 5000
+
+// evaluationWorkers is how many rows are scored concurrently.
+const evaluationWorkers = 4
+
+// evaluationProgressEvery is how often (in rows) progress is saved.
+const evaluationProgressEvery = 25
+
+// EvaluationService benchmarks the model against labeled datasets as
+// background jobs.
+type EvaluationService struct {
+	news    *NewsService
+	repo    repository.EvaluationRepository
+	maxRows int
+	queue   *JobQueue
+}
+
+// NewEvaluationService creates a new evaluation service
+func NewEvaluationService(news *NewsService, repo repository.EvaluationRepository) *EvaluationService {
+	return &EvaluationService{news: news, repo: repo, maxRows: DefaultMaxEvaluationRows}
+}
+
+// WithMaxRows sets the largest dataset accepted.
+func (s *EvaluationService) WithMaxRows(n int) *EvaluationService {
+	if n > 0 {
+		s.maxRows = n
+	}
+	return s
+}
+
+// ParseEvaluationCSV reads a text,label CSV. A header row naming the
+// columns is optional and may list them in either order.
+func ParseEvaluationCSV(r io.Reader, maxRows int) ([]domain.EvaluationSample, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	textCol, labelCol := 0, 1
+	samples := make([]domain.EvaluationSample, 0)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", domain.ErrInvalidDataset, err)
+		}
+		if line == 1 && isEvaluationHeader(record) {
+			textCol, labelCol = headerColumns(record)
+			continue
+		}
+		if len(record) <= textCol || len(record) <= labelCol {
+			return nil, fmt.Errorf("%w: line %d: expected text and label columns", domain.ErrInvalidDataset, line)
+		}
+
+		text := strings.TrimSpace(record[textCol])
+		if text == "" {
+			return nil, fmt.Errorf("%w: line %d: empty text", domain.ErrInvalidDataset, line)
+		}
+		label, err := domain.EvaluationLabelMapping.Map(record[labelCol])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", domain.ErrInvalidDataset, line, err)
+		}
+		if len(samples) == maxRows {
+			return nil, fmt.Errorf("%w: more than %d rows", domain.ErrInvalidDataset, maxRows)
+		}
+		samples = append(samples, domain.EvaluationSample{Text: text, Label: label})
+	}
+
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrInvalidDataset)
+	}
+	return samples, nil
+}
+
+func isEvaluationHeader(record []string) bool {
+	textCol, labelCol := headerColumns(record)
+	return textCol >= 0 && labelCol >= 0
+}
+
+func headerColumns(record []string) (textCol, labelCol int) {
+	textCol, labelCol = -1, -1
+	for i, name := range record {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "text":
+			textCol = i
+		case "label":
+			labelCol = i
+		}
+	}
+	return textCol, labelCol
+}
+
+// Start parses a CSV dataset, stores a pending run and scores it in the
+// background. model optionally selects a model version.
+func (s *EvaluationService) Start(ctx context.Context, dataset io.Reader, model string) (*domain.EvaluationRun, error) {
+	samples, err := ParseEvaluationCSV(dataset, s.maxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &domain.EvaluationRun{
+		ID:        uuid.New().String(),
+		Status:    domain.EvaluationPending,
+		Model:     model,
+		Total:     len(samples),
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Save(ctx, run); err != nil {
+		return nil, err
+	}
+
+	if s.queue != nil {
+		if _, err := s.queue.Enqueue(domain.JobKindEvaluation, EvaluationJob{RunID: run.ID, Model: model, Samples: samples}); err != nil {
+			return nil, err
+		}
+		return run, nil
+	}
+
+	// The job outlives the HTTP request but keeps its trace values.
+	jobCtx := context.WithoutCancel(ctx)
+	if model != "" {
+		jobCtx = ContextWithModel(jobCtx, model)
+	}
+	go s.run(jobCtx, *run, samples)
+
+	return run, nil
+}
+
+// Get returns an evaluation run by ID
+func (s *EvaluationService) Get(ctx context.Context, id string) (*domain.EvaluationRun, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// EvaluationJob is the payload of a queued evaluation.
+type EvaluationJob struct {
+	RunID   string                    `json:"run_id"`
+	Model   string                    `json:"model,omitempty"`
+	Samples []domain.EvaluationSample `json:"samples"`
+}
+
+// EvaluationProgress is a running tally of scored rows. Workers report it
+// as heartbeat progress and as the job result.
+type EvaluationProgress struct {
+	Processed       int                    `json:"processed"`
+	Failed          int                    `json:"failed"`
+	ConfusionMatrix domain.ConfusionMatrix `json:"confusion_matrix"`
+}
+
+// ScoreEvaluation runs every sample through the model, calling report
+// with the tally every few rows, and returns the final tally.
+func ScoreEvaluation(ctx context.Context, news *NewsService, samples []domain.EvaluationSample,
+	report func(EvaluationProgress)) EvaluationProgress {
+	var (
+		mu       sync.Mutex
+		progress EvaluationProgress
+		wg       sync.WaitGroup
+	)
+	jobs := make(chan domain.EvaluationSample)
+	for i := 0; i < evaluationWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for sample := range jobs {
+				prediction, err := news.Classify(ctx, sample.Text)
+
+				mu.Lock()
+				progress.Processed++
+				if err != nil {
+					progress.Failed++
+				} else {
+					progress.ConfusionMatrix.Add(sample.Label, prediction.Result)
+				}
+				if report != nil && progress.Processed%evaluationProgressEvery == 0 {
+					report(progress)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, sample := range samples {
+		jobs <- sample
+	}
+	close(jobs)
+	wg.Wait()
+	return progress
+}
+
+func (s *EvaluationService) run(ctx context.Context, run domain.EvaluationRun, samples []domain.EvaluationSample) {
+	run.Status = domain.EvaluationRunning
+	s.save(ctx, &run)
+
+	progress := ScoreEvaluation(ctx, s.news, samples, func(p EvaluationProgress) {
+		run.Processed, run.Failed = p.Processed, p.Failed
+		s.save(ctx, &run)
+	})
+	s.finish(ctx, &run, progress)
+}
+
+// finish records the final tally and metrics of a run.
+func (s *EvaluationService) finish(ctx context.Context, run *domain.EvaluationRun, progress EvaluationProgress) {
+	now := time.Now()
+	run.Processed, run.Failed = progress.Processed, progress.Failed
+	run.CompletedAt = &now
+	if run.Failed == run.Total {
+		run.Status = domain.EvaluationFailed
+		run.Error = "ML service could not score any rows"
+	} else {
+		metrics := progress.ConfusionMatrix.Metrics()
+		run.Metrics = &metrics
+		run.Status = domain.EvaluationCompleted
+	}
+	s.save(ctx, run)
+}
+
+// WithQueue hands evaluations to cmd/worker through the job queue
+// instead of scoring them in this process.
+func (s *EvaluationService) WithQueue(queue *JobQueue) *EvaluationService {
+	s.queue = queue
+	queue.Handle(domain.JobKindEvaluation, JobCallbacks{
+		OnProgress: s.onJobProgress,
+		OnComplete: s.onJobComplete,
+		OnFail:     s.onJobFail,
+		OnRequeue:  s.onJobRequeue,
+	})
+	return s
+}
+
+func (s *EvaluationService) runFromJob(ctx context.Context, job *domain.Job) (*domain.EvaluationRun, error) {
+	var payload EvaluationJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, payload.RunID)
+}
+
+func (s *EvaluationService) onJobProgress(ctx context.Context, job *domain.Job, data json.RawMessage) error {
+	run, err := s.runFromJob(ctx, job)
+	if err != nil {
+		return err
+	}
+	var progress EvaluationProgress
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return fmt.Errorf("invalid evaluation progress: %w", err)
+	}
+	run.Status = domain.EvaluationRunning
+	run.Processed, run.Failed = progress.Processed, progress.Failed
+	return s.repo.Save(ctx, run)
+}
+
+func (s *EvaluationService) onJobComplete(ctx context.Context, job *domain.Job, data json.RawMessage) error {
+	run, err := s.runFromJob(ctx, job)
+	if err != nil {
+		return err
+	}
+	var progress EvaluationProgress
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return fmt.Errorf("invalid evaluation result: %w", err)
+	}
+	s.finish(ctx, run, progress)
+	return nil
+}
+
+func (s *EvaluationService) onJobFail(ctx context.Context, job *domain.Job) {
+	run, err := s.runFromJob(ctx, job)
+	if err != nil {
+		log.Printf("[evaluation] failed job %s has no run: %v", job.ID, err)
+		return
+	}
+	now := time.Now()
+	run.Status = domain.EvaluationFailed
+	run.Error = job.Error
+	run.CompletedAt = &now
+	s.save(ctx, run)
+}
+
+func (s *EvaluationService) onJobRequeue(ctx context.Context, job *domain.Job) {
+	run, err := s.runFromJob(ctx, job)
+	if err != nil {
+		log.Printf("[evaluation] requeued job %s has no run: %v", job.ID, err)
+		return
+	}
+	run.Status = domain.EvaluationPending
+	run.Error = ""
+	run.Processed, run.Failed = 0, 0
+	run.CompletedAt = nil
+	s.save(ctx, run)
+}
+
+func (s *EvaluationService) save(ctx context.Context, run *domain.EvaluationRun) {
+	if err := s.repo.Save(ctx, run); err != nil {
+		log.Printf("[evaluation] failed to save run %s: %v", run.ID, err)
+	}
+}
+
+// RunEvaluationJob scores a queued evaluation on a worker. When no row
+// could be scored because the ML service is down, it returns the health
+// check's error so the job is retried after recovery instead of finishing
+// as failed.
+func RunEvaluationJob(ctx context.Context, news *NewsService, payload json.RawMessage,
+	report func(EvaluationProgress)) (EvaluationProgress, error) {
+	var job EvaluationJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return EvaluationProgress{}, fmt.Errorf("invalid evaluation job: %w", err)
+	}
+	if job.Model != "" {
+		ctx = ContextWithModel(ctx, job.Model)
+	}
+	progress := ScoreEvaluation(ctx, news, job.Samples, report)
+	if progress.Processed > 0 && progress.Failed == progress.Processed {
+		if err := news.CheckMLHealth(); err != nil {
+			return progress, err
+		}
+	}
+	return progress, nil
+}
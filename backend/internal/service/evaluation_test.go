@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestParseEvaluationCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    int
+		wantErr bool
+	}{
+		{name: "no header", csv: "first story,fake\nsecond story,real\n", want: 2},
+		{name: "header in either order", csv: "label,text\nREAL,\"a, quoted story\"\n", want: 1},
+		{name: "unknown label", csv: "story,1\n", wantErr: true},
+		{name: "empty text", csv: " ,fake\n", wantErr: true},
+		{name: "header only", csv: "text,label\n", wantErr: true},
+		{name: "too many rows", csv: "a,fake\nb,fake\nc,real\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples, err := ParseEvaluationCSV(strings.NewReader(tt.csv), 2)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrInvalidDataset) {
+					t.Fatalf("error = %v, want ErrInvalidDataset", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseEvaluationCSV() error = %v", err)
+			}
+			if len(samples) != tt.want {
+				t.Errorf("got %d samples, want %d", len(samples), tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluationRun(t *testing.T) {
+	// The fake model calls everything mentioning "aliens" FAKE.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req MLPredictionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		result := "REAL"
+		if strings.Contains(req.Text, "aliens") {
+			result = "FAKE"
+		}
+		json.NewEncoder(w).Encode(MLPredictionResponse{Result: result})
+	}))
+	defer srv.Close()
+
+	news := NewNewsService(NewMLClient(srv.URL), NewScraperService(), memory.NewPredictionRepository())
+	evaluations := NewEvaluationService(news, memory.NewEvaluationRepository())
+
+	dataset := "text,label\n" +
+		"aliens built the pyramids,fake\n" + // TP
+		"aliens attend city council,real\n" + // FP
+		"budget passes senate,real\n" + // TN
+		"moon is made of cheese,fake\n" // FN
+	run, err := evaluations.Start(context.Background(), strings.NewReader(dataset), "")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for run.Status != domain.EvaluationCompleted {
+		if time.Now().After(deadline) {
+			t.Fatalf("evaluation did not complete, status %q", run.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+		run, _ = evaluations.Get(context.Background(), run.ID)
+	}
+
+	want := domain.ConfusionMatrix{TruePositive: 1, FalsePositive: 1, TrueNegative: 1, FalseNegative: 1}
+	if run.Metrics.ConfusionMatrix != want {
+		t.Errorf("confusion matrix = %+v, want %+v", run.Metrics.ConfusionMatrix, want)
+	}
+	for name, got := range map[string]float64{
+		"accuracy":  run.Metrics.Accuracy,
+		"precision": run.Metrics.Precision,
+		"recall":    run.Metrics.Recall,
+		"f1":        run.Metrics.F1,
+	} {
+		if math.Abs(got-0.5) > 1e-9 {
+			t.Errorf("%s = %v, want 0.5", name, got)
+		}
+	}
+}
@@ -0,0 +1,421 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// Evidence retrieval defaults
+const (
+	DefaultMaxClaims     = 5
+	DefaultEvidenceTopK  = 3
+	minEvidenceRelevance = 0.2
+)
+
+// EvidenceSource finds candidate evidence for a search query. Stance and
+// relevance are assigned by the retriever.
+type EvidenceSource interface {
+	Search(ctx context.Context, query string, limit int) ([]domain.Evidence, error)
+}
+
+// EvidenceRetriever extracts check-worthy claims from an article and
+// attaches the most relevant evidence to each, with a stance.
+//
+// Stance is lexical: evidence that uses debunking language the claim does
+// not is taken to refute it, and closely matching evidence without such
+// language to support it. It is a pointer for readers, not a verdict.
+type EvidenceRetriever struct {
+	source    EvidenceSource
+	maxClaims int
+	topK      int
+	timeout   time.Duration
+}
+
+// NewEvidenceRetriever creates a retriever over source.
+func NewEvidenceRetriever(source EvidenceSource) *EvidenceRetriever {
+	return &EvidenceRetriever{
+		source:    source,
+		maxClaims: DefaultMaxClaims,
+		topK:      DefaultEvidenceTopK,
+		timeout:   10 * time.Second,
+	}
+}
+
+// WithLimits sets how many claims are extracted and how much evidence is
+// kept per claim.
+func (r *EvidenceRetriever) WithLimits(maxClaims, topK int) *EvidenceRetriever {
+	if maxClaims > 0 {
+		r.maxClaims = maxClaims
+	}
+	if topK > 0 {
+		r.topK = topK
+	}
+	return r
+}
+
+// Retrieve returns the article's claims with their evidence. Evidence
+// pointing back at the article itself (excludeURL) is dropped. A failed
+// search is recorded on its claim and does not affect the others.
+func (r *EvidenceRetriever) Retrieve(ctx context.Context, text, excludeURL string) []domain.Claim {
+	claims := ExtractClaims(text, r.maxClaims)
+	if len(claims) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	results := make([]domain.Claim, len(claims))
+	var wg sync.WaitGroup
+	for i, claim := range claims {
+		wg.Add(1)
+		go func(i int, claim string) {
+			defer wg.Done()
+			results[i] = r.retrieveClaim(ctx, claim, excludeURL)
+		}(i, claim)
+	}
+	wg.Wait()
+	return results
+}
+
+func (r *EvidenceRetriever) retrieveClaim(ctx context.Context, claim, excludeURL string) domain.Claim {
+	result := domain.Claim{Text: claim, Evidence: []domain.Evidence{}}
+	terms := claimTerms(claim)
+	candidates, err := r.source.Search(ctx, strings.Join(topTerms(terms, 8), " "), r.topK*3)
+	if err != nil {
+		result.Error = "evidence search failed"
+		return result
+	}
+
+	excluded := NormalizeURL(excludeURL)
+	for _, ev := range candidates {
+		if ev.URL == "" || (excludeURL != "" && NormalizeURL(ev.URL) == excluded) {
+			continue
+		}
+		ev.Score = relevance(terms, ev.Title+" "+ev.Snippet)
+		if ev.Score < minEvidenceRelevance {
+			continue
+		}
+		ev.Stance = stance(claim, ev)
+		result.Evidence = append(result.Evidence, ev)
+	}
+	sort.SliceStable(result.Evidence, func(i, j int) bool {
+		return result.Evidence[i].Score > result.Evidence[j].Score
+	})
+	if len(result.Evidence) > r.topK {
+		result.Evidence = result.Evidence[:r.topK]
+	}
+	return result
+}
+
+// reportingCues mark sentences that assert something checkable.
+var reportingCues = []string{
+	" said", " says", "according to", " reported", " claims", " claimed", " announced",
+	" confirmed", " found", " percent", "%", " million", " billion", " study", " data",
+}
+
+// ExtractClaims picks up to max check-worthy sentences, in article order.
+// Sentences score for numbers, attribution and named entities; questions
+// and very short or long sentences are skipped.
+func ExtractClaims(text string, max int) []string {
+	type scored struct {
+		index int
+		text  string
+		score int
+	}
+	var candidates []scored
+	for i, s := range splitSentences(strings.Join(strings.Fields(text), " ")) {
+		if len(s) < 40 || len(s) > 300 || strings.HasSuffix(s, "?") {
+			continue
+		}
+		score := 0
+		if strings.IndexFunc(s, unicode.IsDigit) >= 0 {
+			score += 2
+		}
+		lower := strings.ToLower(s)
+		for _, cue := range reportingCues {
+			if strings.Contains(lower, cue) {
+				score++
+			}
+		}
+		if properNouns(s) >= 2 {
+			score++
+		}
+		if score >= 2 {
+			candidates = append(candidates, scored{i, s, score})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	if len(candidates) > max {
+		candidates = candidates[:max]
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].index < candidates[j].index })
+
+	claims := make([]string, len(candidates))
+	for i, c := range candidates {
+		claims[i] = c.text
+	}
+	return claims
+}
+
+// properNouns counts capitalized words after the first.
+func properNouns(sentence string) int {
+	n := 0
+	for i, w := range strings.Fields(sentence) {
+		if i > 0 && len(w) > 1 && unicode.IsUpper([]rune(w)[0]) {
+			n++
+		}
+	}
+	return n
+}
+
+// evidenceStopwords are dropped from claim terms.
+var evidenceStopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "that": true, "with": true, "was": true, "are": true,
+	"has": true, "have": true, "this": true, "from": true, "but": true, "not": true, "said": true,
+	"its": true, "his": true, "her": true, "they": true, "their": true, "been": true, "were": true,
+	"will": true, "would": true, "which": true, "who": true, "about": true, "after": true,
+	"also": true, "into": true, "than": true, "more": true, "says": true, "according": true,
+}
+
+// claimTerms returns the distinct content words of a claim, in order.
+func claimTerms(text string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) < 3 || evidenceStopwords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+// topTerms keeps the longest n terms, which tend to be the most specific.
+func topTerms(terms []string, n int) []string {
+	sorted := append([]string(nil), terms...)
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// relevance is the share of the claim's terms found in the evidence.
+func relevance(terms []string, evidence string) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+	found := make(map[string]bool)
+	for _, t := range claimTerms(evidence) {
+		found[t] = true
+	}
+	hits := 0
+	for _, t := range terms {
+		if found[t] {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(terms))
+}
+
+// refuteCues is debunking language in evidence.
+var refuteCues = []string{
+	"false", "fake", "hoax", "debunk", "misleading", "no evidence", "not true", "fabricated",
+	"fact check", "fact-check", "incorrect", "denied", "denies", "baseless", "unfounded",
+}
+
+func stance(claim string, ev domain.Evidence) string {
+	claimLower := strings.ToLower(claim)
+	evidenceLower := strings.ToLower(ev.Title + " " + ev.Snippet)
+	for _, cue := range refuteCues {
+		if strings.Contains(evidenceLower, cue) && !strings.Contains(claimLower, cue) {
+			return domain.StanceRefutes
+		}
+	}
+	if ev.Score >= 0.5 {
+		return domain.StanceSupports
+	}
+	return domain.StanceNeutral
+}
+
+// HTTPEvidenceSource queries a news search API. The endpoint may contain
+// {query} and {limit} placeholders; otherwise q and limit parameters are
+// added. Responses list results under "results" or "articles" with title,
+// url, snippet (or description) and source (a string or {"name": ...}).
+type HTTPEvidenceSource struct {
+	endpoint   string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewHTTPEvidenceSource creates a search API source. A non-empty apiKey is
+// sent as X-Api-Key.
+func NewHTTPEvidenceSource(endpoint, apiKey string) *HTTPEvidenceSource {
+	return &HTTPEvidenceSource{
+		endpoint:   endpoint,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// WithOutboundAudit records search API calls in audit.
+func (s *HTTPEvidenceSource) WithOutboundAudit(audit *OutboundAudit) *HTTPEvidenceSource {
+	audit.Client(s.httpClient, PurposeFactCheck)
+	return s
+}
+
+func (s *HTTPEvidenceSource) Search(ctx context.Context, query string, limit int) ([]domain.Evidence, error) {
+	endpoint := s.endpoint
+	if strings.Contains(endpoint, "{query}") {
+		endpoint = strings.ReplaceAll(endpoint, "{query}", url.QueryEscape(query))
+		endpoint = strings.ReplaceAll(endpoint, "{limit}", fmt.Sprint(limit))
+	} else {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		q := u.Query()
+		q.Set("q", query)
+		q.Set("limit", fmt.Sprint(limit))
+		u.RawQuery = q.Encode()
+		endpoint = u.String()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("X-Api-Key", s.apiKey)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("evidence search returned HTTP %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Results  []searchResult `json:"results"`
+		Articles []searchResult `json:"articles"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid evidence search response: %w", err)
+	}
+
+	var evidence []domain.Evidence
+	for _, r := range append(body.Results, body.Articles...) {
+		snippet := r.Snippet
+		if snippet == "" {
+			snippet = r.Description
+		}
+		evidence = append(evidence, domain.Evidence{
+			Title:   r.Title,
+			URL:     r.URL,
+			Snippet: snippet,
+			Source:  string(r.Source),
+		})
+	}
+	return evidence, nil
+}
+
+type searchResult struct {
+	Title       string       `json:"title"`
+	URL         string       `json:"url"`
+	Snippet     string       `json:"snippet"`
+	Description string       `json:"description"`
+	Source      sourceString `json:"source"`
+}
+
+// sourceString accepts a source given as a string or as {"name": ...}.
+type sourceString string
+
+func (s *sourceString) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*s = sourceString(name)
+		return nil
+	}
+	var obj struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil // unknown shape; the source is optional
+	}
+	*s = sourceString(obj.Name)
+	return nil
+}
+
+// CorpusEvidenceSource searches articles already analyzed by this
+// service, using their title, description and summary as the snippet.
+type CorpusEvidenceSource struct {
+	repository NewsRepository
+}
+
+// NewCorpusEvidenceSource creates a source over stored predictions.
+func NewCorpusEvidenceSource(repo NewsRepository) *CorpusEvidenceSource {
+	return &CorpusEvidenceSource{repository: repo}
+}
+
+func (s *CorpusEvidenceSource) Search(ctx context.Context, query string, limit int) ([]domain.Evidence, error) {
+	articles, err := s.repository.Query(ctx, *domain.NewPredictionQuery().WithRequestType("url"))
+	if err != nil {
+		return nil, err
+	}
+
+	terms := claimTerms(query)
+	type match struct {
+		evidence domain.Evidence
+		score    float64
+	}
+	var matches []match
+	for _, p := range articles {
+		snippet := strings.TrimSpace(p.ArticleDescription + " " + p.Summary)
+		if p.ArticleTitle == "" && snippet == "" {
+			continue
+		}
+		score := relevance(terms, p.ArticleTitle+" "+snippet)
+		if score == 0 {
+			continue
+		}
+		link := p.CanonicalURL
+		if link == "" {
+			link = p.OriginalContent
+		}
+		matches = append(matches, match{domain.Evidence{
+			Title:   p.ArticleTitle,
+			URL:     link,
+			Snippet: snippet,
+			Source:  p.ArticleSource,
+		}, score})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	evidence := make([]domain.Evidence, len(matches))
+	for i, m := range matches {
+		evidence[i] = m.evidence
+	}
+	return evidence, nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+const evidenceArticle = "It was a quiet week. The health ministry said 40 percent of children in Delhi were vaccinated by March. " +
+	"Nobody knows what comes next? Officials in Mumbai announced a new campaign with 2 million doses."
+
+func TestExtractClaims(t *testing.T) {
+	claims := ExtractClaims(evidenceArticle, 5)
+	want := []string{
+		"The health ministry said 40 percent of children in Delhi were vaccinated by March.",
+		"Officials in Mumbai announced a new campaign with 2 million doses.",
+	}
+	if len(claims) != len(want) {
+		t.Fatalf("ExtractClaims = %q, want %q", claims, want)
+	}
+	for i := range want {
+		if claims[i] != want[i] {
+			t.Errorf("claim %d = %q, want %q", i, claims[i], want[i])
+		}
+	}
+
+	if got := ExtractClaims(evidenceArticle, 1); len(got) != 1 {
+		t.Errorf("ExtractClaims(max 1) returned %d claims", len(got))
+	}
+}
+
+type stubEvidence struct {
+	results []domain.Evidence
+	err     error
+}
+
+func (s stubEvidence) Search(ctx context.Context, query string, limit int) ([]domain.Evidence, error) {
+	return s.results, s.err
+}
+
+func TestEvidenceRetrieverStance(t *testing.T) {
+	source := stubEvidence{results: []domain.Evidence{
+		{URL: "https://factcheck.example/1", Title: "Fact check: claim that 40 percent of Delhi children were vaccinated is misleading", Snippet: "Ministry data for March shows a lower figure."},
+		{URL: "https://news.example/2", Title: "Health ministry: 40 percent of children in Delhi vaccinated by March", Snippet: "The ministry said the figure was reached in March."},
+		{URL: "https://news.example/3", Title: "Cricket scores", Snippet: "Mumbai won again."},
+		{URL: "https://origin.example/article", Title: "Health ministry said 40 percent of children in Delhi vaccinated by March"},
+	}}
+	r := NewEvidenceRetriever(source).WithLimits(1, 3)
+
+	claims := r.Retrieve(context.Background(), evidenceArticle, "https://origin.example/article")
+	if len(claims) != 1 {
+		t.Fatalf("got %d claims, want 1", len(claims))
+	}
+	stances := map[string]string{}
+	for _, ev := range claims[0].Evidence {
+		stances[ev.URL] = ev.Stance
+	}
+	want := map[string]string{
+		"https://factcheck.example/1": domain.StanceRefutes,
+		"https://news.example/2":      domain.StanceSupports,
+	}
+	if len(stances) != len(want) {
+		t.Fatalf("evidence = %+v, want the two relevant results without the article itself", claims[0].Evidence)
+	}
+	for url, s := range want {
+		if stances[url] != s {
+			t.Errorf("stance of %s = %q, want %q", url, stances[url], s)
+		}
+	}
+}
+
+func TestEvidenceRetrieverSearchFailure(t *testing.T) {
+	r := NewEvidenceRetriever(stubEvidence{err: errors.New("down")})
+	claims := r.Retrieve(context.Background(), evidenceArticle, "")
+	if len(claims) != 2 || claims[0].Error == "" || len(claims[0].Evidence) != 0 {
+		t.Errorf("claims = %+v, want claims with errors and no evidence", claims)
+	}
+}
+
+func TestHTTPEvidenceSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "delhi vaccinated" || r.Header.Get("X-Api-Key") != "key" {
+			t.Errorf("request = %s key=%q", r.URL, r.Header.Get("X-Api-Key"))
+		}
+		w.Write([]byte(`{"articles":[{"title":"T","url":"https://a.example/1","description":"D","source":{"name":"A News"}}]}`))
+	}))
+	defer srv.Close()
+
+	results, err := NewHTTPEvidenceSource(srv.URL+"/search?q={query}", "key").Search(context.Background(), "delhi vaccinated", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Snippet != "D" || results[0].Source != "A News" {
+		t.Errorf("results = %+v", results)
+	}
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+func TestFeedTokens(t *testing.T) {
+	tokens := NewFeedTokens("secret")
+	token := tokens.Issue("user-42")
+
+	owner, err := tokens.Verify(token)
+	if err != nil || owner != "user-42" {
+		t.Fatalf("Verify(issued) = %q, %v; want user-42", owner, err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", "dXNlci00Mg"},
+		{"tampered owner", NewFeedTokens("secret").Issue("user-43")[:len("dXNlci00Mw")] + token[len("dXNlci00Mg"):]},
+		{"other secret", NewFeedTokens("rotated").Issue("user-42")},
+		{"bad encoding", "!!!." + token},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tokens.Verify(tt.token); !errors.Is(err, domain.ErrInvalidFeedToken) {
+				t.Errorf("Verify(%q) err = %v, want ErrInvalidFeedToken", tt.token, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// InvalidationBus fans prediction changes out to the caches derived from
+// predictions. The repository publishes after every committed write (see
+// internal/repository/invalidating), so no write path can forget a cache.
+//
+// Delivery is synchronous: when a write returns, every subscriber has
+// seen it and the next read cannot serve a stale verdict. Subscribers
+// must therefore be quick and hand slow work off to a goroutine.
+type InvalidationBus struct {
+	mu          sync.RWMutex
+	subscribers []invalidationSubscriber
+
+	statsMu   sync.Mutex
+	published map[string]int64
+	failures  atomic.Int64
+}
+
+type invalidationSubscriber struct {
+	name string
+	fn   func(domain.PredictionChange)
+}
+
+// NewInvalidationBus creates a bus without subscribers.
+func NewInvalidationBus() *InvalidationBus {
+	return &InvalidationBus{published: make(map[string]int64)}
+}
+
+// Subscribe registers fn for every later change. name identifies the
+// subscriber in logs and stats.
+func (b *InvalidationBus) Subscribe(name string, fn func(domain.PredictionChange)) {
+	if b == nil || fn == nil {
+		return
+	}
+	b.mu.Lock()
+	b.subscribers = append(b.subscribers, invalidationSubscriber{name: name, fn: fn})
+	b.mu.Unlock()
+}
+
+// Publish delivers change to every subscriber in subscription order. A
+// panicking subscriber is logged and skipped rather than failing the
+// write that published the change.
+func (b *InvalidationBus) Publish(change domain.PredictionChange) {
+	if b == nil {
+		return
+	}
+	b.statsMu.Lock()
+	b.published[change.Kind]++
+	b.statsMu.Unlock()
+
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+	for _, s := range subscribers {
+		b.deliver(s, change)
+	}
+}
+
+func (b *InvalidationBus) deliver(s invalidationSubscriber, change domain.PredictionChange) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.failures.Add(1)
+			log.Printf("Warning: invalidation subscriber %s failed on %s %s: %v", s.name, change.Kind, change.ID, r)
+		}
+	}()
+	s.fn(change)
+}
+
+// Stats lists the subscribers and counts published changes.
+func (b *InvalidationBus) Stats() *domain.InvalidationStats {
+	if b == nil {
+		return nil
+	}
+	stats := &domain.InvalidationStats{Subscribers: []string{}, Published: make(map[string]int64)}
+	b.mu.RLock()
+	for _, s := range b.subscribers {
+		stats.Subscribers = append(stats.Subscribers, s.name)
+	}
+	b.mu.RUnlock()
+	b.statsMu.Lock()
+	for kind, n := range b.published {
+		stats.Published[kind] = n
+	}
+	b.statsMu.Unlock()
+	stats.Failures = b.failures.Load()
+	return stats
+}
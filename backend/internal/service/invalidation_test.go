@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/invalidating"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestInvalidationBus(t *testing.T) {
+	bus := NewInvalidationBus()
+	repo := invalidating.NewPredictionRepository(memory.NewPredictionRepository(), bus)
+	registry := NewSourceRegistry(nil)
+	summaries := NewDomainSummaryService(repo, registry, time.Hour)
+	filter := NewKnownFakeFilter(repo, registry)
+	bus.Subscribe("broken", func(domain.PredictionChange) { panic("boom") })
+	bus.Subscribe("domain-summaries", summaries.Invalidate)
+	bus.Subscribe("known-fakes", filter.Invalidate)
+
+	ctx := context.Background()
+	summary := func() domain.DomainSummary {
+		t.Helper()
+		s, err := summaries.Summary(ctx, "rumors.example")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *s
+	}
+	const url = "https://news.rumors.example/moon"
+
+	p := &domain.Prediction{ID: "p1", RequestType: "url", CanonicalURL: url, ArticleSource: "news.rumors.example",
+		Result: domain.LabelFake, Confidence: 0.95, Method: domain.MethodModel, CreatedAt: time.Now()}
+	if err := repo.CreatePrediction(p); err != nil {
+		t.Fatal(err)
+	}
+	filter.Observe(p)
+	if s := summary(); s.Stats.Fake != 1 {
+		t.Fatalf("fake = %d, want 1", s.Stats.Fake)
+	}
+	if _, ok := filter.Match(url); !ok {
+		t.Fatal("stored fake not in the filter")
+	}
+
+	// Re-scoring reaches the cached summary and the filter before the TTL
+	rescored := *p
+	rescored.Result, rescored.Confidence = domain.LabelReal, 0.9
+	if err := repo.UpdatePrediction(&rescored); err != nil {
+		t.Fatal(err)
+	}
+	if s := summary(); s.Stats.Fake != 0 || s.Stats.Real != 1 {
+		t.Errorf("after re-score stats = %+v, want 1 real", s.Stats)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, ok := filter.Match(url); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("re-scored URL still in the filter")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := repo.DeletePrediction("p1"); err != nil {
+		t.Fatal(err)
+	}
+	if s := summary(); s.Stats.Total != 0 {
+		t.Errorf("after delete total = %d, want 0", s.Stats.Total)
+	}
+
+	// Failed writes publish nothing
+	if err := repo.DeletePrediction("p1"); err == nil {
+		t.Fatal("deleting a missing prediction succeeded")
+	}
+
+	stats := bus.Stats()
+	for kind, want := range map[string]int64{domain.ChangeCreated: 1, domain.ChangeUpdated: 1, domain.ChangeDeleted: 1} {
+		if got := stats.Published[kind]; got != want {
+			t.Errorf("published %s = %d, want %d", kind, got, want)
+		}
+	}
+	if stats.Failures != 3 {
+		t.Errorf("failures = %d, want 3", stats.Failures)
+	}
+	if len(stats.Subscribers) != 3 {
+		t.Errorf("subscribers = %v", stats.Subscribers)
+	}
+}
@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Job queue defaults
+const (
+	DefaultJobLeaseTTL         = time.Minute
+	DefaultJobMaxAttempts      = 3
+	DefaultJobRecoveryInterval = 30 * time.Second
+)
+
+// JobCallbacks receive a job kind's progress and outcome on the API side.
+// Any callback may be nil.
+type JobCallbacks struct {
+	OnProgress func(ctx context.Context, job *domain.Job, progress json.RawMessage) error
+	OnComplete func(ctx context.Context, job *domain.Job, result json.RawMessage) error
+	OnFail     func(ctx context.Context, job *domain.Job)
+	OnRequeue  func(ctx context.Context, job *domain.Job) // an operator requeued a failed job
+}
+
+// JobQueueStats summarizes the queue for operators.
+type JobQueueStats struct {
+	Queued   int            `json:"queued"`
+	Leased   int            `json:"leased"`
+	Deferred int            `json:"deferred"`
+	Done     int            `json:"done"`
+	Failed   int            `json:"failed"`
+	Workers  map[string]int `json:"workers"` // leased jobs per worker
+}
+
+// JobQueue hands background jobs to workers under time-limited leases.
+// Workers extend a lease with heartbeats; a job whose lease expires is
+// queued again until it runs out of attempts. Jobs that fail transiently
+// wait as deferred until ResumeDeferred, and jobs that fail for good stay
+// as failed until an operator requeues them.
+type JobQueue struct {
+	leaseTTL    time.Duration
+	maxAttempts int
+
+	mu        sync.Mutex
+	jobs      map[string]*domain.Job
+	order     []string // FIFO by enqueue time
+	callbacks map[string]JobCallbacks
+	finished  func(ctx context.Context, job *domain.Job)
+}
+
+// NewJobQueue creates an empty queue.
+func NewJobQueue() *JobQueue {
+	return &JobQueue{
+		leaseTTL:    DefaultJobLeaseTTL,
+		maxAttempts: DefaultJobMaxAttempts,
+		jobs:        make(map[string]*domain.Job),
+		callbacks:   make(map[string]JobCallbacks),
+	}
+}
+
+// WithLease sets how long a lease lasts without a heartbeat and how many
+// times a job is attempted.
+func (q *JobQueue) WithLease(ttl time.Duration, maxAttempts int) *JobQueue {
+	if ttl > 0 {
+		q.leaseTTL = ttl
+	}
+	if maxAttempts > 0 {
+		q.maxAttempts = maxAttempts
+	}
+	return q
+}
+
+// Handle registers the callbacks for a job kind.
+func (q *JobQueue) Handle(kind string, callbacks JobCallbacks) *JobQueue {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.callbacks[kind] = callbacks
+	return q
+}
+
+// OnFinished calls fn for every job of any kind that completes or fails
+// for good, after the kind's own callbacks.
+func (q *JobQueue) OnFinished(fn func(ctx context.Context, job *domain.Job)) *JobQueue {
+	q.finished = fn
+	return q
+}
+
+// Enqueue adds a job with a JSON-encoded payload.
+func (q *JobQueue) Enqueue(kind string, payload interface{}) (*domain.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+	now := time.Now()
+	job := &domain.Job{
+		ID:        uuid.New().String(),
+		Kind:      kind,
+		Payload:   data,
+		Status:    domain.JobQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs[job.ID] = job
+	q.order = append(q.order, job.ID)
+	copied := *job
+	return &copied, nil
+}
+
+// Lease hands the oldest queued job of one of kinds to workerID, or
+// reports false when there is none.
+func (q *JobQueue) Lease(ctx context.Context, workerID string, kinds []string, now time.Time) (*domain.Job, bool) {
+	q.mu.Lock()
+	failed := q.expireLeases(now)
+
+	var leased *domain.Job
+	for _, id := range q.order {
+		job := q.jobs[id]
+		if job.Status != domain.JobQueued || !containsString(kinds, job.Kind) {
+			continue
+		}
+		expires := now.Add(q.leaseTTL)
+		job.Status = domain.JobLeased
+		job.LeasedBy = workerID
+		job.LeaseExpires = &expires
+		job.Attempts++
+		job.UpdatedAt = now
+		copied := *job
+		leased = &copied
+		break
+	}
+	q.compact()
+	q.mu.Unlock()
+
+	q.notifyFailed(ctx, failed)
+	return leased, leased != nil
+}
+
+// Heartbeat extends a lease and passes the worker's progress report to
+// the kind's OnProgress callback.
+func (q *JobQueue) Heartbeat(ctx context.Context, id, workerID string, progress json.RawMessage) error {
+	q.mu.Lock()
+	job, err := q.leasedJob(id, workerID)
+	if err != nil {
+		q.mu.Unlock()
+		return err
+	}
+	now := time.Now()
+	expires := now.Add(q.leaseTTL)
+	job.LeaseExpires = &expires
+	job.UpdatedAt = now
+	copied, callbacks := *job, q.callbacks[job.Kind]
+	q.mu.Unlock()
+
+	if callbacks.OnProgress != nil && len(progress) > 0 && string(progress) != "null" {
+		return callbacks.OnProgress(ctx, &copied, progress)
+	}
+	return nil
+}
+
+// Complete marks a leased job done and hands its result to the kind's
+// OnComplete callback.
+func (q *JobQueue) Complete(ctx context.Context, id, workerID string, result json.RawMessage) error {
+	q.mu.Lock()
+	job, err := q.leasedJob(id, workerID)
+	if err != nil {
+		q.mu.Unlock()
+		return err
+	}
+	job.Status = domain.JobDone
+	job.LeaseExpires = nil
+	job.UpdatedAt = time.Now()
+	copied, callbacks := *job, q.callbacks[job.Kind]
+	q.mu.Unlock()
+
+	if callbacks.OnComplete != nil {
+		err = callbacks.OnComplete(ctx, &copied, result)
+	}
+	if q.finished != nil {
+		q.finished(ctx, &copied)
+	}
+	return err
+}
+
+// Fail records a worker's failure of the given class. Permanent failures
+// fail the job at once, transient ones defer it until the ML service
+// recovers and unclassified ones queue it again, until it has used all
+// its attempts.
+func (q *JobQueue) Fail(ctx context.Context, id, workerID, reason, failure string) error {
+	q.mu.Lock()
+	job, err := q.leasedJob(id, workerID)
+	if err != nil {
+		q.mu.Unlock()
+		return err
+	}
+	job.Failure = failure
+	failed := q.release(job, reason, time.Now())
+	q.mu.Unlock()
+
+	q.notifyFailed(ctx, failed)
+	return nil
+}
+
+// ResumeDeferred queues every deferred job again and returns how many
+// there were.
+func (q *JobQueue) ResumeDeferred() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	resumed := 0
+	now := time.Now()
+	for _, job := range q.jobs {
+		if job.Status == domain.JobDeferred {
+			job.Status = domain.JobQueued
+			job.UpdatedAt = now
+			resumed++
+		}
+	}
+	return resumed
+}
+
+// RunRecovery resumes deferred jobs whenever healthy reports the ML
+// service is up again, checking every interval until ctx is cancelled.
+func (q *JobQueue) RunRecovery(ctx context.Context, interval time.Duration, healthy func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if q.Stats().Deferred == 0 || healthy() != nil {
+				continue
+			}
+			if n := q.ResumeDeferred(); n > 0 {
+				log.Printf("[jobs] ML service recovered, requeued %d deferred jobs", n)
+			}
+		}
+	}
+}
+
+// Requeue gives a failed job a fresh set of attempts, e.g. once the cause
+// of a permanent failure was fixed.
+func (q *JobQueue) Requeue(ctx context.Context, id string) (*domain.Job, error) {
+	q.mu.Lock()
+	job, ok := q.jobs[id]
+	if !ok {
+		q.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", domain.ErrJobNotFound, id)
+	}
+	if job.Status != domain.JobFailed {
+		q.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s is %s", domain.ErrJobNotFailed, id, job.Status)
+	}
+	job.Status = domain.JobQueued
+	job.Attempts = 0
+	job.UpdatedAt = time.Now()
+	q.order = append(q.order, job.ID)
+	copied, callbacks := *job, q.callbacks[job.Kind]
+	q.mu.Unlock()
+
+	if callbacks.OnRequeue != nil {
+		callbacks.OnRequeue(ctx, &copied)
+	}
+	return &copied, nil
+}
+
+// List returns copies of the jobs in status, oldest first; an empty status
+// lists every job.
+func (q *JobQueue) List(status string) []domain.Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	jobs := make([]domain.Job, 0)
+	for _, job := range q.jobs {
+		if status == "" || job.Status == status {
+			jobs = append(jobs, *job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.Before(jobs[j].CreatedAt) })
+	return jobs
+}
+
+// Get returns a copy of a job.
+func (q *JobQueue) Get(id string) (*domain.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrJobNotFound, id)
+	}
+	copied := *job
+	return &copied, nil
+}
+
+// Stats counts jobs by state and leased jobs by worker.
+func (q *JobQueue) Stats() JobQueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := JobQueueStats{Workers: make(map[string]int)}
+	for _, job := range q.jobs {
+		switch job.Status {
+		case domain.JobQueued:
+			stats.Queued++
+		case domain.JobLeased:
+			stats.Leased++
+			stats.Workers[job.LeasedBy]++
+		case domain.JobDeferred:
+			stats.Deferred++
+		case domain.JobDone:
+			stats.Done++
+		case domain.JobFailed:
+			stats.Failed++
+		}
+	}
+	return stats
+}
+
+// leasedJob returns the job if workerID holds its lease. Callers must
+// hold q.mu.
+func (q *JobQueue) leasedJob(id, workerID string) (*domain.Job, error) {
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrJobNotFound, id)
+	}
+	if job.Status != domain.JobLeased || job.LeasedBy != workerID {
+		return nil, fmt.Errorf("%w: %s", domain.ErrJobLeaseLost, id)
+	}
+	return job, nil
+}
+
+// expireLeases releases jobs whose lease ran out and returns those that
+// failed for good. Callers must hold q.mu.
+func (q *JobQueue) expireLeases(now time.Time) []domain.Job {
+	var failed []domain.Job
+	for _, job := range q.jobs {
+		if job.Status == domain.JobLeased && job.LeaseExpires.Before(now) {
+			job.Failure = ""
+			failed = append(failed, q.release(job, "lease expired on worker "+job.LeasedBy, now)...)
+		}
+	}
+	return failed
+}
+
+// release requeues or defers a job by its failure class, or fails it
+// after maxAttempts or a permanent failure. Callers must hold q.mu.
+func (q *JobQueue) release(job *domain.Job, reason string, now time.Time) []domain.Job {
+	job.LeasedBy = ""
+	job.LeaseExpires = nil
+	job.Error = reason
+	job.UpdatedAt = now
+	if job.Attempts < q.maxAttempts && job.Failure != domain.JobFailurePermanent {
+		job.Status = domain.JobQueued
+		if job.Failure == domain.JobFailureTransient {
+			job.Status = domain.JobDeferred
+		}
+		return nil
+	}
+	job.Status = domain.JobFailed
+	return []domain.Job{*job}
+}
+
+func (q *JobQueue) notifyFailed(ctx context.Context, failed []domain.Job) {
+	for i := range failed {
+		job := &failed[i]
+		log.Printf("[jobs] %s job %s failed after %d attempts, moved to the dead-letter queue: %s", job.Kind, job.ID, job.Attempts, job.Error)
+		q.mu.Lock()
+		callbacks := q.callbacks[job.Kind]
+		q.mu.Unlock()
+		if callbacks.OnFail != nil {
+			callbacks.OnFail(ctx, job)
+		}
+		if q.finished != nil {
+			q.finished(ctx, job)
+		}
+	}
+}
+
+// Retention of finished jobs: done jobs stay inspectable for a day, failed
+// ones stay in the dead-letter queue for a week so operators can requeue them.
+const (
+	finishedJobRetention   = 24 * time.Hour
+	deadLetterJobRetention = 7 * 24 * time.Hour
+)
+
+// compact drops finished jobs from the lease order and forgets them once
+// they are old. Callers must hold q.mu.
+func (q *JobQueue) compact() {
+	for id, job := range q.jobs {
+		age := time.Since(job.UpdatedAt)
+		if (job.Status == domain.JobDone && age > finishedJobRetention) ||
+			(job.Status == domain.JobFailed && age > deadLetterJobRetention) {
+			delete(q.jobs, id)
+		}
+	}
+	kept := q.order[:0]
+	for _, id := range q.order {
+		job, ok := q.jobs[id]
+		if !ok {
+			continue
+		}
+		if status := job.Status; status == domain.JobQueued || status == domain.JobLeased || status == domain.JobDeferred {
+			kept = append(kept, id)
+		}
+	}
+	q.order = kept
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+func TestJobQueueLifecycle(t *testing.T) {
+	ctx := context.Background()
+	var progressed, completed string
+	q := NewJobQueue().Handle("echo", JobCallbacks{
+		OnProgress: func(ctx context.Context, job *domain.Job, p json.RawMessage) error {
+			progressed = string(p)
+			return nil
+		},
+		OnComplete: func(ctx context.Context, job *domain.Job, r json.RawMessage) error {
+			completed = string(r)
+			return nil
+		},
+	})
+
+	queued, err := q.Enqueue("echo", map[string]string{"say": "hi"})
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if _, ok := q.Lease(ctx, "w1", []string{"other"}, time.Now()); ok {
+		t.Fatal("leased a job of a kind the worker does not run")
+	}
+
+	job, ok := q.Lease(ctx, "w1", []string{"echo"}, time.Now())
+	if !ok || job.ID != queued.ID || job.Attempts != 1 {
+		t.Fatalf("Lease() = %+v, %v", job, ok)
+	}
+	if _, ok := q.Lease(ctx, "w2", []string{"echo"}, time.Now()); ok {
+		t.Fatal("leased job was handed out twice")
+	}
+
+	if err := q.Heartbeat(ctx, job.ID, "w2", nil); !errors.Is(err, domain.ErrJobLeaseLost) {
+		t.Errorf("heartbeat from another worker: err = %v, want ErrJobLeaseLost", err)
+	}
+	if err := q.Heartbeat(ctx, job.ID, "w1", json.RawMessage(`{"n":1}`)); err != nil || progressed != `{"n":1}` {
+		t.Errorf("Heartbeat() err = %v, progress %q", err, progressed)
+	}
+	if err := q.Complete(ctx, job.ID, "w1", json.RawMessage(`"done"`)); err != nil || completed != `"done"` {
+		t.Errorf("Complete() err = %v, result %q", err, completed)
+	}
+
+	if stats := q.Stats(); stats.Done != 1 || stats.Queued != 0 || stats.Leased != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestJobQueueExpiredLeases(t *testing.T) {
+	ctx := context.Background()
+	failed := 0
+	q := NewJobQueue().WithLease(time.Minute, 2).Handle("slow", JobCallbacks{
+		OnFail: func(ctx context.Context, job *domain.Job) { failed++ },
+	})
+	q.Enqueue("slow", nil)
+
+	now := time.Now()
+	first, _ := q.Lease(ctx, "w1", []string{"slow"}, now)
+
+	// The first lease lapses, so another worker may take the job.
+	now = now.Add(2 * time.Minute)
+	second, ok := q.Lease(ctx, "w2", []string{"slow"}, now)
+	if !ok || second.ID != first.ID || second.Attempts != 2 {
+		t.Fatalf("expired job was not re-leased: %+v", second)
+	}
+	if err := q.Complete(ctx, first.ID, "w1", nil); !errors.Is(err, domain.ErrJobLeaseLost) {
+		t.Errorf("stale worker completed the job: err = %v", err)
+	}
+
+	// Out of attempts: the job fails for good.
+	now = now.Add(2 * time.Minute)
+	if _, ok := q.Lease(ctx, "w3", []string{"slow"}, now); ok {
+		t.Fatal("job was leased beyond its attempts")
+	}
+	job, _ := q.Get(first.ID)
+	if job.Status != domain.JobFailed || failed != 1 {
+		t.Errorf("status = %s, OnFail calls = %d", job.Status, failed)
+	}
+}
+
+func TestJobQueueFailureClasses(t *testing.T) {
+	ctx := context.Background()
+	var failed, requeued int
+	q := NewJobQueue().WithLease(time.Minute, 3).Handle("score", JobCallbacks{
+		OnFail:    func(ctx context.Context, job *domain.Job) { failed++ },
+		OnRequeue: func(ctx context.Context, job *domain.Job) { requeued++ },
+	})
+	lease := func() *domain.Job {
+		t.Helper()
+		job, ok := q.Lease(ctx, "w1", []string{"score"}, time.Now())
+		if !ok {
+			t.Fatal("no job to lease")
+		}
+		return job
+	}
+
+	// A transient failure waits for the ML service instead of retrying.
+	queued, _ := q.Enqueue("score", nil)
+	job := lease()
+	if err := q.Fail(ctx, job.ID, "w1", "ML service is unavailable", domain.JobFailureTransient); err != nil {
+		t.Fatalf("Fail() error = %v", err)
+	}
+	if _, ok := q.Lease(ctx, "w1", []string{"score"}, time.Now()); ok {
+		t.Fatal("deferred job was leased before recovery")
+	}
+	if stats := q.Stats(); stats.Deferred != 1 {
+		t.Errorf("Deferred = %d, want 1", stats.Deferred)
+	}
+	if n := q.ResumeDeferred(); n != 1 {
+		t.Errorf("ResumeDeferred() = %d, want 1", n)
+	}
+	if job = lease(); job.ID != queued.ID || job.Attempts != 2 {
+		t.Errorf("resumed job = %+v", job)
+	}
+
+	// A permanent failure goes straight to the dead-letter queue.
+	if err := q.Fail(ctx, job.ID, "w1", "invalid payload", domain.JobFailurePermanent); err != nil {
+		t.Fatalf("Fail() error = %v", err)
+	}
+	if dead := q.List(domain.JobFailed); len(dead) != 1 || failed != 1 {
+		t.Fatalf("dead letters = %+v, OnFail calls = %d", dead, failed)
+	}
+
+	// Operators can requeue it with fresh attempts.
+	if _, err := q.Requeue(ctx, "missing"); !errors.Is(err, domain.ErrJobNotFound) {
+		t.Errorf("Requeue(missing) err = %v, want ErrJobNotFound", err)
+	}
+	again, err := q.Requeue(ctx, job.ID)
+	if err != nil || again.Status != domain.JobQueued || again.Attempts != 0 || requeued != 1 {
+		t.Fatalf("Requeue() = %+v, %v (OnRequeue calls %d)", again, err, requeued)
+	}
+	if _, err := q.Requeue(ctx, job.ID); !errors.Is(err, domain.ErrJobNotFailed) {
+		t.Errorf("Requeue(queued) err = %v, want ErrJobNotFailed", err)
+	}
+	if job = lease(); job.ID != queued.ID || job.Attempts != 1 {
+		t.Errorf("requeued job = %+v", job)
+	}
+}
+
+func TestJobQueueTransientFailuresUseAttempts(t *testing.T) {
+	ctx := context.Background()
+	q := NewJobQueue().WithLease(time.Minute, 2)
+	q.Enqueue("score", nil)
+	for attempt := 1; attempt <= 2; attempt++ {
+		job, ok := q.Lease(ctx, "w1", []string{"score"}, time.Now())
+		if !ok {
+			t.Fatalf("attempt %d: no job", attempt)
+		}
+		q.Fail(ctx, job.ID, "w1", "ML down", domain.JobFailureTransient)
+		q.ResumeDeferred()
+	}
+	if stats := q.Stats(); stats.Failed != 1 || stats.Queued != 0 {
+		t.Errorf("stats = %+v, want the job dead-lettered after max attempts", stats)
+	}
+}
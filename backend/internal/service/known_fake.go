@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// Known-fake filter defaults
+const (
+	DefaultKnownFakeMinConfidence   = 0.9
+	DefaultKnownFakeRebuildInterval = 10 * time.Minute
+
+	// knownFakeFalsePositiveRate is the filter's target false positive
+	// rate at capacity.
+	knownFakeFalsePositiveRate = 0.01
+	// minKnownFakeCapacity keeps room for URLs flagged between rebuilds.
+	minKnownFakeCapacity = 1024
+)
+
+// knownFakeCategory is the source registry category whose domains are
+// always known fakes.
+const knownFakeCategory = "fake"
+
+// bloomFilter is a fixed-size Bloom filter over strings. Lookups can
+// report false positives, never false negatives.
+type bloomFilter struct {
+	bits []uint64
+	m    uint64 // bits
+	k    uint64 // hash functions
+}
+
+// newBloomFilter sizes a filter for n entries at false positive rate p.
+func newBloomFilter(n int, p float64) *bloomFilter {
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	m = (m + 63) / 64 * 64
+	k := uint64(math.Max(1, math.Round(float64(m)/float64(n)*math.Ln2)))
+	return &bloomFilter{bits: make([]uint64, m/64), m: m, k: k}
+}
+
+// locations derives the key's k bit positions by double hashing.
+func (b *bloomFilter) locations(key string, fn func(bit uint64) bool) bool {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h1 := h.Sum64()
+	h2 := h1>>33 | h1<<31 | 1
+	for i := uint64(0); i < b.k; i++ {
+		if !fn((h1 + i*h2) % b.m) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *bloomFilter) add(key string) {
+	b.locations(key, func(bit uint64) bool {
+		atomic.OrUint64(&b.bits[bit/64], 1<<(bit%64))
+		return true
+	})
+}
+
+func (b *bloomFilter) has(key string) bool {
+	return b.locations(key, func(bit uint64) bool {
+		return atomic.LoadUint64(&b.bits[bit/64])&(1<<(bit%64)) != 0
+	})
+}
+
+// KnownFakeFilter answers "is this URL already known to be fake?" in
+// constant time, so clients can get a provisional verdict before any
+// scraping or scoring. It holds the canonical URLs of high-confidence FAKE
+// model verdicts and the domains the source registry lists as fake, and is
+// rebuilt periodically from the repository.
+type KnownFakeFilter struct {
+	repo          NewsRepository
+	registry      *SourceRegistry
+	minConfidence float64
+
+	filter  atomic.Pointer[bloomFilter]
+	checks  atomic.Int64
+	hits    atomic.Int64
+	mu      sync.Mutex
+	entries int
+	builtAt time.Time
+
+	// rebuildPending and rebuilding coalesce invalidation rebuilds.
+	rebuildPending atomic.Bool
+	rebuilding     atomic.Bool
+}
+
+// KnownFakeStats describes the filter.
+type KnownFakeStats struct {
+	Entries       int       `json:"entries"`
+	Bits          uint64    `json:"bits"`
+	Hashes        uint64    `json:"hashes"`
+	MinConfidence float64   `json:"min_confidence"`
+	Checks        int64     `json:"checks"`
+	Hits          int64     `json:"hits"`
+	BuiltAt       time.Time `json:"built_at"`
+}
+
+// NewKnownFakeFilter creates an empty filter; call Rebuild to fill it.
+func NewKnownFakeFilter(repo NewsRepository, registry *SourceRegistry) *KnownFakeFilter {
+	f := &KnownFakeFilter{repo: repo, registry: registry, minConfidence: DefaultKnownFakeMinConfidence}
+	f.filter.Store(newBloomFilter(minKnownFakeCapacity, knownFakeFalsePositiveRate))
+	return f
+}
+
+// WithMinConfidence sets the confidence a FAKE verdict needs to be added.
+func (f *KnownFakeFilter) WithMinConfidence(min float64) *KnownFakeFilter {
+	if min > 0 && min <= 1 {
+		f.minConfidence = min
+	}
+	return f
+}
+
+// Rebuild replaces the filter with one built from the stored predictions
+// and the registry. It returns the number of entries.
+func (f *KnownFakeFilter) Rebuild(ctx context.Context) (int, error) {
+	q := domain.NewPredictionQuery().WithLabel(domain.LabelFake)
+	q.MinConfidence = f.minConfidence
+	predictions, err := f.repo.Query(ctx, *q)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load known fakes: %w", err)
+	}
+
+	var keys []string
+	for _, p := range predictions {
+		if knownFake(p, f.minConfidence) {
+			keys = append(keys, knownFakeURLKey(p.CanonicalURL))
+		}
+	}
+	for _, d := range f.registry.DomainsIn(knownFakeCategory) {
+		keys = append(keys, knownFakeHostKey(d))
+	}
+
+	filter := newBloomFilter(max(2*len(keys), minKnownFakeCapacity), knownFakeFalsePositiveRate)
+	for _, key := range keys {
+		filter.add(key)
+	}
+	f.filter.Store(filter)
+
+	f.mu.Lock()
+	f.entries, f.builtAt = len(keys), time.Now()
+	f.mu.Unlock()
+	return len(keys), nil
+}
+
+// Run rebuilds the filter every interval until ctx is cancelled.
+func (f *KnownFakeFilter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := f.Rebuild(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Warning: known-fake filter rebuild failed: %v", err)
+			}
+		}
+	}
+}
+
+// Observe adds a freshly stored prediction if it qualifies, so it is known
+// before the next rebuild.
+func (f *KnownFakeFilter) Observe(p *domain.Prediction) {
+	if f == nil || !knownFake(p, f.minConfidence) {
+		return
+	}
+	f.filter.Load().add(knownFakeURLKey(p.CanonicalURL))
+	f.mu.Lock()
+	f.entries++
+	f.mu.Unlock()
+}
+
+// Invalidate keeps the filter in step with a prediction change. A newly
+// qualifying URL is added at once; one that no longer qualifies, because
+// it was re-scored or deleted, cannot be taken out of a Bloom filter, so a
+// rebuild is started in the background. Creations are left to Observe.
+func (f *KnownFakeFilter) Invalidate(change domain.PredictionChange) {
+	if f == nil || change.Kind == domain.ChangeCreated {
+		return
+	}
+	was := change.Before != nil && knownFake(change.Before, f.minConfidence)
+	is := change.After != nil && knownFake(change.After, f.minConfidence)
+	switch {
+	case was && (!is || change.After.CanonicalURL != change.Before.CanonicalURL):
+		f.scheduleRebuild()
+	case is && !was:
+		f.Observe(change.After)
+	}
+}
+
+// scheduleRebuild rebuilds the filter in the background. Requests made
+// while a rebuild runs are folded into one more rebuild after it.
+func (f *KnownFakeFilter) scheduleRebuild() {
+	f.rebuildPending.Store(true)
+	if !f.rebuilding.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		for {
+			for f.rebuildPending.Swap(false) {
+				if _, err := f.Rebuild(context.Background()); err != nil {
+					log.Printf("Warning: known-fake filter rebuild failed: %v", err)
+				}
+			}
+			f.rebuilding.Store(false)
+			// A request made after the last Swap saw the rebuild still running
+			if !f.rebuildPending.Load() || !f.rebuilding.CompareAndSwap(false, true) {
+				return
+			}
+		}
+	}()
+}
+
+// Match reports whether a normalized article URL, or its domain or a
+// parent domain, is probably a known fake, and why.
+func (f *KnownFakeFilter) Match(normalizedURL string) (string, bool) {
+	if f == nil {
+		return "", false
+	}
+	f.checks.Add(1)
+	filter := f.filter.Load()
+	if filter.has(knownFakeURLKey(normalizedURL)) {
+		f.hits.Add(1)
+		return "the article was flagged as fake before", true
+	}
+	u, err := url.Parse(normalizedURL)
+	if err != nil {
+		return "", false
+	}
+	for host := normalizeDomain(u.Hostname()); host != ""; {
+		if filter.has(knownFakeHostKey(host)) {
+			f.hits.Add(1)
+			return host + " is listed as a fake news source", true
+		}
+		dot := strings.IndexByte(host, '.')
+		if dot < 0 {
+			break
+		}
+		host = host[dot+1:]
+	}
+	return "", false
+}
+
+// Stats describes the filter's size and use.
+func (f *KnownFakeFilter) Stats() *KnownFakeStats {
+	if f == nil {
+		return nil
+	}
+	filter := f.filter.Load()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &KnownFakeStats{
+		Entries:       f.entries,
+		Bits:          filter.m,
+		Hashes:        filter.k,
+		MinConfidence: f.minConfidence,
+		Checks:        f.checks.Load(),
+		Hits:          f.hits.Load(),
+		BuiltAt:       f.builtAt,
+	}
+}
+
+// knownFake reports whether a prediction's URL belongs in the filter: a
+// shared (non-tenant) model verdict of FAKE at high confidence that no
+// reviewer overturned.
+func knownFake(p *domain.Prediction, minConfidence float64) bool {
+	if p.CanonicalURL == "" || p.Result != domain.LabelFake || p.Confidence < minConfidence {
+		return false
+	}
+	if p.Method != "" && p.Method != domain.MethodModel {
+		return false
+	}
+	if p.ModelRoute != "" {
+		return false
+	}
+	return p.Review == nil || p.Review.Verdict == domain.LabelFake
+}
+
+func knownFakeURLKey(canonicalURL string) string { return "url:" + canonicalURL }
+
+func knownFakeHostKey(host string) string { return "host:" + host }
+
+// knownFakePrediction is the provisional verdict for a filter hit. It is
+// not stored: the full analysis that follows is.
+func knownFakePrediction(normalizedURL, host, reason string, confidence float64) *domain.Prediction {
+	return &domain.Prediction{
+		Result:        domain.LabelFake,
+		Confidence:    confidence,
+		Method:        domain.MethodKnownFake,
+		Provisional:   true,
+		CanonicalURL:  normalizedURL,
+		ArticleSource: host,
+		Signals: []domain.SignalContribution{{
+			Name:         domain.SignalKnownFake,
+			FakeScore:    1,
+			Weight:       1,
+			Contribution: 1,
+			Detail:       reason,
+		}},
+	}
+}
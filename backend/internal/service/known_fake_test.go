@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestBloomFilter(t *testing.T) {
+	b := newBloomFilter(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		b.add(fmt.Sprintf("url:https://a.example/%d", i))
+	}
+	for i := 0; i < 1000; i++ {
+		if !b.has(fmt.Sprintf("url:https://a.example/%d", i)) {
+			t.Fatalf("added key %d not found", i)
+		}
+	}
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if b.has(fmt.Sprintf("url:https://b.example/%d", i)) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / 10000; rate > 0.03 {
+		t.Errorf("false positive rate = %.3f, want about 0.01", rate)
+	}
+}
+
+func TestKnownFakeFilter(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	add := func(p *domain.Prediction) {
+		t.Helper()
+		if err := repo.CreatePrediction(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add(&domain.Prediction{ID: "fake", RequestType: "url", CanonicalURL: "https://rumors.example/moon", Result: domain.LabelFake, Confidence: 0.95, Method: domain.MethodModel})
+	add(&domain.Prediction{ID: "unsure", RequestType: "url", CanonicalURL: "https://rumors.example/unsure", Result: domain.LabelFake, Confidence: 0.6})
+	add(&domain.Prediction{ID: "overturned", RequestType: "url", CanonicalURL: "https://rumors.example/overturned", Result: domain.LabelFake, Confidence: 0.97,
+		Review: &domain.Review{Verdict: domain.LabelReal}})
+	add(&domain.Prediction{ID: "tenant", RequestType: "url", CanonicalURL: "https://rumors.example/tenant", Result: domain.LabelFake, Confidence: 0.97, ModelRoute: "acme"})
+	registry := NewSourceRegistry([]domain.Source{
+		{Domain: "hoax.example", Category: "fake"},
+		{Domain: "paper.example", Category: "mainstream"},
+	})
+
+	filter := NewKnownFakeFilter(repo, registry)
+	if n, err := filter.Rebuild(context.Background()); err != nil || n != 2 {
+		t.Fatalf("Rebuild = %d, %v; want 2 entries", n, err)
+	}
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://rumors.example/moon", true},
+		{"https://rumors.example/unsure", false},
+		{"https://rumors.example/overturned", false},
+		{"https://rumors.example/tenant", false},
+		{"https://hoax.example/anything", true},
+		{"https://news.hoax.example/anything", true},
+		{"https://paper.example/story", false},
+	}
+	for _, tt := range tests {
+		if _, got := filter.Match(tt.url); got != tt.want {
+			t.Errorf("Match(%s) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+
+	// New verdicts are known before the next rebuild.
+	filter.Observe(&domain.Prediction{CanonicalURL: "https://rumors.example/new", Result: domain.LabelFake, Confidence: 0.92, Method: domain.MethodModel})
+	if _, ok := filter.Match("https://rumors.example/new"); !ok {
+		t.Error("observed prediction not matched")
+	}
+	if stats := filter.Stats(); stats.Entries != 3 || stats.Checks != int64(len(tests)+1) || stats.Hits != 4 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestAnalyzeKnownFakeIsProvisional(t *testing.T) {
+	const article = "https://rumors.example/moon"
+	repo := memory.NewPredictionRepository()
+	if err := repo.CreatePrediction(&domain.Prediction{
+		ID: "stored", RequestType: "url", CanonicalURL: article, Result: domain.LabelFake, Confidence: 0.95, Method: domain.MethodModel,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	filter := NewKnownFakeFilter(repo, nil)
+	if _, err := filter.Rebuild(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewNewsService(nil, nil, repo).WithKnownFakeFilter(filter)
+	ctx := context.Background()
+
+	p, err := svc.AnalyzeNews(ctx, &domain.AnalysisRequest{Type: "url", Content: article, AllowProvisional: true})
+	if err != nil {
+		t.Fatalf("AnalyzeNews: %v", err)
+	}
+	if !p.Provisional || p.Method != domain.MethodKnownFake || p.Result != domain.LabelFake || p.ID != "" {
+		t.Errorf("provisional prediction = %+v", p)
+	}
+
+	// The full analysis runs in the background.
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, running := svc.background.Load(article); !running {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("background analysis did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Without allow_provisional the full analysis is returned.
+	full, err := svc.AnalyzeNews(ctx, &domain.AnalysisRequest{Type: "url", Content: article})
+	if err != nil || full.Provisional || full.ID != "stored" {
+		t.Errorf("full analysis = %+v, %v; want the stored prediction", full, err)
+	}
+}
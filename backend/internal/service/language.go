@@ -0,0 +1,100 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// scriptLanguages maps scripts used by a single major language to its
+// ISO 639-1 code.
+var scriptLanguages = []struct {
+	table *unicode.RangeTable
+	lang  string
+}{
+	{unicode.Devanagari, "hi"},
+	{unicode.Bengali, "bn"},
+	{unicode.Tamil, "ta"},
+	{unicode.Arabic, "ar"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Greek, "el"},
+	{unicode.Hebrew, "he"},
+	{unicode.Thai, "th"},
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Han, "zh"},
+}
+
+// latinStopwords are frequent function words that tell Latin-script
+// languages apart.
+var latinStopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "in", "that", "was", "for", "with"},
+	"es": {"el", "la", "los", "las", "que", "y", "en", "del", "por", "una"},
+	"fr": {"le", "la", "les", "et", "des", "est", "une", "que", "du", "pour"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "mit", "den", "ein", "zu"},
+	"pt": {"o", "os", "que", "e", "do", "da", "em", "um", "não", "para"},
+	"it": {"il", "che", "di", "e", "la", "per", "non", "un", "della", "sono"},
+}
+
+// DetectLanguage guesses the ISO 639-1 language of text: by script for
+// non-Latin text, by stopwords for Latin text. It returns "" when unsure.
+func DetectLanguage(text string) string {
+	counts := make(map[string]int)
+	var letters, latin int
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.Is(unicode.Latin, r) {
+			latin++
+			continue
+		}
+		for _, s := range scriptLanguages {
+			if unicode.Is(s.table, r) {
+				counts[s.lang]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+	if latin*2 < letters {
+		// Kana marks Japanese even when most characters are Han
+		if counts["ja"] > 0 {
+			return "ja"
+		}
+		return argmax(counts, 1)
+	}
+
+	words := make(map[string]int)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		words[w]++
+	}
+	scores := make(map[string]int)
+	for lang, stopwords := range latinStopwords {
+		for _, w := range stopwords {
+			scores[lang] += words[w]
+		}
+	}
+	return argmax(scores, 2)
+}
+
+// argmax returns the key with the highest count of at least min, or "" on a
+// tie.
+func argmax(counts map[string]int, min int) string {
+	best, bestCount, tied := "", 0, false
+	for k, n := range counts {
+		switch {
+		case n > bestCount:
+			best, bestCount, tied = k, n, false
+		case n == bestCount:
+			tied = true
+		}
+	}
+	if tied || bestCount < min {
+		return ""
+	}
+	return best
+}
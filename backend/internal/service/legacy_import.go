@@ -0,0 +1,329 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// maxImportErrors bounds the per-record messages kept in import stats.
+const maxImportErrors = 20
+
+// legacyModelVersion is recorded when a prototype record has none.
+const legacyModelVersion = "prototype-legacy"
+
+// LegacyImportStats reports the outcome of a prototype import.
+type LegacyImportStats struct {
+	Total      int      `json:"total"`
+	Imported   int      `json:"imported"`
+	Duplicates int      `json:"duplicates"`
+	Invalid    int      `json:"invalid"`
+	DryRun     bool     `json:"dry_run"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// legacyRecord is one prediction from the prototype's JSON dump. The
+// prototype's exports were not consistent, so common alternate field
+// names are accepted.
+type legacyRecord struct {
+	ID         flexString      `json:"id"`
+	Text       string          `json:"text"`
+	Content    string          `json:"content"`
+	URL        string          `json:"url"`
+	Label      *flexString     `json:"label"`
+	Result     *flexString     `json:"result"`
+	Prediction json.RawMessage `json:"prediction"` // label, or the nested /explain response
+	Confidence *float64        `json:"confidence"`
+	FakeProb   *float64        `json:"fake_probability"`
+	FakeProbS  *float64        `json:"fake_prob"`
+	RealProb   *float64        `json:"real_probability"`
+	RealProbS  *float64        `json:"real_prob"`
+	Model      string          `json:"model_version"`
+	Timestamp  flexTime        `json:"timestamp"`
+	CreatedAt  flexTime        `json:"created_at"`
+}
+
+// flexString accepts JSON strings and numbers.
+type flexString string
+
+func (f *flexString) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*f = flexString(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("expected string or number, got %s", data)
+	}
+	*f = flexString(n.String())
+	return nil
+}
+
+// flexTime accepts RFC 3339 / ISO 8601 strings and unix seconds.
+type flexTime struct{ time.Time }
+
+func (f *flexTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var n float64
+	if err := json.Unmarshal(data, &n); err == nil {
+		f.Time = time.Unix(int64(n), 0).UTC()
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid timestamp %s", data)
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			f.Time = t.UTC()
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid timestamp %q", s)
+}
+
+// ImportLegacyPredictions ingests the prototype's JSON dump: either an
+// array of records or an object wrapping one under "predictions" or
+// "history". Records get deterministic IDs, so re-running an import only
+// adds what is missing. With dryRun nothing is stored.
+func (s *NewsService) ImportLegacyPredictions(ctx context.Context, data []byte, dryRun bool) (*LegacyImportStats, error) {
+	records, err := decodeLegacyDump(data)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &LegacyImportStats{Total: len(records), DryRun: dryRun}
+	seen := make(map[string]bool, len(records))
+	for i, rec := range records {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		prediction, err := rec.toPrediction(domain.PrototypeLabelMapping)
+		if err != nil {
+			stats.Invalid++
+			stats.addError(fmt.Sprintf("record %d: %v", i, err))
+			continue
+		}
+
+		if seen[prediction.ID] {
+			stats.Duplicates++
+			continue
+		}
+		seen[prediction.ID] = true
+		if _, err := s.repository.GetPredictionByID(prediction.ID); err == nil {
+			stats.Duplicates++
+			continue
+		}
+
+		if !dryRun {
+			if err := s.repository.CreatePrediction(prediction); err != nil {
+				if errors.Is(err, domain.ErrAlreadyExists) {
+					stats.Duplicates++
+					continue
+				}
+				stats.Invalid++
+				stats.addError(fmt.Sprintf("record %d: %v", i, err))
+				continue
+			}
+		}
+		stats.Imported++
+	}
+	return stats, nil
+}
+
+func (st *LegacyImportStats) addError(msg string) {
+	if len(st.Errors) < maxImportErrors {
+		st.Errors = append(st.Errors, msg)
+	}
+}
+
+func decodeLegacyDump(data []byte) ([]legacyRecord, error) {
+	data = bytes.TrimSpace(data)
+	var records []legacyRecord
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImport, err)
+		}
+		return records, nil
+	}
+
+	var wrapped struct {
+		Predictions []legacyRecord `json:"predictions"`
+		History     []legacyRecord `json:"history"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImport, err)
+	}
+	if wrapped.Predictions == nil && wrapped.History == nil {
+		return nil, fmt.Errorf("%w: expected an array or a \"predictions\" field", domain.ErrInvalidImport)
+	}
+	return append(wrapped.Predictions, wrapped.History...), nil
+}
+
+// toPrediction maps a prototype record onto a prediction.
+func (rec *legacyRecord) toPrediction(labels domain.LabelMapping) (*domain.Prediction, error) {
+	p := &domain.Prediction{ModelVersion: rec.Model}
+	if p.ModelVersion == "" {
+		p.ModelVersion = legacyModelVersion
+	}
+
+	text := firstNonEmpty(rec.Text, rec.Content)
+	switch {
+	case rec.URL != "":
+		u, err := url.Parse(rec.URL)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid url %q", rec.URL)
+		}
+		p.RequestType = "url"
+		p.OriginalContent = rec.URL
+		p.CanonicalURL = NormalizeURL(rec.URL)
+		p.ArticleSource = strings.ToLower(u.Hostname())
+		p.License = &domain.ContentLicense{License: domain.LicenseScrapedFairUse}
+	case text != "":
+		p.RequestType = "text"
+		p.OriginalContent = text
+		// The prototype never asked for research consent.
+		p.License = &domain.ContentLicense{License: domain.LicenseUserSubmitted}
+	default:
+		return nil, errors.New("record has neither text nor url")
+	}
+
+	// /explain exports nest the verdict under "prediction".
+	var predictionLabel *flexString
+	if len(rec.Prediction) > 0 && rec.Prediction[0] == '{' {
+		var nested legacyRecord
+		if err := json.Unmarshal(rec.Prediction, &nested); err != nil {
+			return nil, fmt.Errorf("invalid nested prediction: %v", err)
+		}
+		rec.Result = firstLabelPtr(rec.Result, nested.Result)
+		rec.Confidence = firstFloat(rec.Confidence, nested.Confidence)
+		rec.FakeProb = firstFloat(rec.FakeProb, nested.FakeProb, nested.FakeProbS)
+		rec.RealProb = firstFloat(rec.RealProb, nested.RealProb, nested.RealProbS)
+		if rec.Model == "" && nested.Model != "" {
+			rec.Model = nested.Model
+			p.ModelVersion = nested.Model
+		}
+	} else if len(rec.Prediction) > 0 {
+		predictionLabel = new(flexString)
+		if err := json.Unmarshal(rec.Prediction, predictionLabel); err != nil {
+			return nil, fmt.Errorf("invalid prediction: %v", err)
+		}
+	}
+
+	fake := firstFloat(rec.FakeProb, rec.FakeProbS)
+	real := firstFloat(rec.RealProb, rec.RealProbS)
+	switch {
+	case fake != nil && real == nil:
+		r := 1 - *fake
+		real = &r
+	case real != nil && fake == nil:
+		f := 1 - *real
+		fake = &f
+	}
+
+	if raw := firstLabel(rec.Label, rec.Result, predictionLabel); raw != "" {
+		label, err := labels.Map(raw)
+		if err != nil {
+			return nil, err
+		}
+		p.Result = label
+	} else if fake != nil {
+		p.Result = domain.LabelReal
+		if *fake > 0.5 {
+			p.Result = domain.LabelFake
+		}
+	} else {
+		return nil, errors.New("record has no label or probabilities")
+	}
+
+	if fake != nil {
+		p.FakeProbability, p.RealProbability = *fake, *real
+	}
+	if rec.Confidence != nil {
+		p.Confidence = *rec.Confidence
+	} else if p.Result == domain.LabelFake {
+		p.Confidence = p.FakeProbability
+	} else {
+		p.Confidence = p.RealProbability
+	}
+	if fake == nil && rec.Confidence != nil {
+		// Only the winning side's confidence was recorded.
+		if p.Result == domain.LabelFake {
+			p.FakeProbability, p.RealProbability = p.Confidence, 1-p.Confidence
+		} else {
+			p.FakeProbability, p.RealProbability = 1-p.Confidence, p.Confidence
+		}
+	}
+	if p.Confidence < 0 || p.Confidence > 1 {
+		return nil, fmt.Errorf("confidence %v out of range", p.Confidence)
+	}
+
+	p.CreatedAt = rec.Timestamp.Time
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = rec.CreatedAt.Time
+	}
+	if p.CreatedAt.IsZero() {
+		return nil, errors.New("record has no timestamp")
+	}
+
+	p.ID = legacyPredictionID(string(rec.ID), p)
+	return p, nil
+}
+
+// legacyPredictionID derives a stable ID from the prototype's ID, or from
+// the record's content and timestamp when it had none.
+func legacyPredictionID(legacyID string, p *domain.Prediction) string {
+	if legacyID != "" {
+		return "legacy-" + legacyID
+	}
+	sum := sha256.Sum256([]byte(p.RequestType + "\n" + p.OriginalContent + "\n" + strconv.FormatInt(p.CreatedAt.Unix(), 10)))
+	return "legacy-" + hex.EncodeToString(sum[:12])
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func firstFloat(values ...*float64) *float64 {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+func firstLabelPtr(values ...*flexString) *flexString {
+	for _, v := range values {
+		if v != nil && *v != "" {
+			return v
+		}
+	}
+	return nil
+}
+
+func firstLabel(values ...*flexString) string {
+	if v := firstLabelPtr(values...); v != nil {
+		return string(*v)
+	}
+	return ""
+}
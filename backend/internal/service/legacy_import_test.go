@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+const prototypeDump = `{"predictions": [
+  {"id": 1, "text": "Aliens endorse candidate", "label": 1, "fake_prob": 0.93, "timestamp": 1700000000},
+  {"id": 2, "url": "https://www.Example.com/story?utm_source=x", "label": "real", "confidence": 0.8, "timestamp": "2023-11-15T10:00:00"},
+  {"text": "Budget passes senate", "prediction": {"result": "REAL", "confidence": 0.7, "real_probability": 0.7, "model_version": "v2.0-enhanced"}, "created_at": "2023-11-16 09:30:00"},
+  {"id": 1, "text": "Aliens endorse candidate", "label": 1, "timestamp": 1700000000},
+  {"id": 5, "text": "No label here", "timestamp": 1700000000},
+  {"id": 6, "text": "Odd label", "label": "satire", "timestamp": 1700000000}
+]}`
+
+func TestImportLegacyPredictions(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	svc := NewNewsService(nil, nil, repo)
+	ctx := context.Background()
+
+	stats, err := svc.ImportLegacyPredictions(ctx, []byte(prototypeDump), true)
+	if err != nil {
+		t.Fatalf("dry run error = %v", err)
+	}
+	if all, _ := repo.GetAllPredictions(); len(all) != 0 {
+		t.Fatalf("dry run stored %d predictions", len(all))
+	}
+
+	stats, err = svc.ImportLegacyPredictions(ctx, []byte(prototypeDump), false)
+	if err != nil {
+		t.Fatalf("ImportLegacyPredictions() error = %v", err)
+	}
+	if stats.Total != 6 || stats.Imported != 3 || stats.Duplicates != 1 || stats.Invalid != 2 || len(stats.Errors) != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	fake, err := repo.GetPredictionByID("legacy-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.Result != domain.LabelFake || fake.Confidence != 0.93 || fake.ModelVersion != legacyModelVersion {
+		t.Errorf("prototype label 1 should map to FAKE, got %+v", fake)
+	}
+
+	byURL, err := repo.GetPredictionByID("legacy-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byURL.RequestType != "url" || byURL.CanonicalURL != "https://www.example.com/story" ||
+		byURL.ArticleSource != "www.example.com" || byURL.RealProbability != 0.8 {
+		t.Errorf("unexpected url prediction %+v", byURL)
+	}
+
+	// Re-importing is idempotent.
+	stats, err = svc.ImportLegacyPredictions(ctx, []byte(prototypeDump), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Imported != 0 || stats.Duplicates != 4 {
+		t.Errorf("re-import stats %+v", stats)
+	}
+
+	if _, err := svc.ImportLegacyPredictions(ctx, []byte(`{"foo": 1}`), false); !errors.Is(err, domain.ErrInvalidImport) {
+		t.Errorf("expected ErrInvalidImport, got %v", err)
+	}
+}
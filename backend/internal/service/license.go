@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// contentPartner is the partner an analysis is submitted by.
+type contentPartner struct {
+	name           string
+	researchExport bool
+}
+
+type partnerKey struct{}
+
+// ContextWithPartner records that an analysis is submitted by a content
+// partner, whose agreement decides whether its content may be exported.
+func ContextWithPartner(ctx context.Context, name string, researchExport bool) context.Context {
+	return context.WithValue(ctx, partnerKey{}, contentPartner{name: name, researchExport: researchExport})
+}
+
+// contentLicense returns the license content is ingested under: partner
+// content under the partner's agreement, scraped articles as fair use and
+// pasted text as user submitted, with the caller's research consent.
+func contentLicense(ctx context.Context, req *domain.AnalysisRequest) *domain.ContentLicense {
+	if partner, ok := ctx.Value(partnerKey{}).(contentPartner); ok {
+		return &domain.ContentLicense{
+			License:         domain.LicensePartnerProvided,
+			ResearchConsent: partner.researchExport,
+			Partner:         partner.name,
+		}
+	}
+	if req.Type == "url" {
+		return &domain.ContentLicense{License: domain.LicenseScrapedFairUse}
+	}
+	return &domain.ContentLicense{License: domain.LicenseUserSubmitted, ResearchConsent: req.ResearchConsent}
+}
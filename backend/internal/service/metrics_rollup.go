@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// Metrics rollup defaults
+const (
+	DefaultRollupInterval        = time.Hour
+	DefaultRollupSettle          = 48 * time.Hour // reviews arriving later are not counted
+	DefaultRollupHourlyRetention = 14 * 24 * time.Hour
+	DefaultRollupDailyRetention  = 400 * 24 * time.Hour
+)
+
+// MetricsRollupJob persists hourly aggregates of the stored predictions
+// and downsamples them into daily ones, so months of trends survive
+// prediction retention and the metrics scraper's own retention. Each run
+// recomputes the hours still settling, since reviews can arrive after a
+// prediction's hour has passed.
+type MetricsRollupJob struct {
+	predictions     NewsRepository
+	rollups         repository.MetricsRollupRepository
+	settle          time.Duration
+	hourlyRetention time.Duration
+	dailyRetention  time.Duration
+	now             func() time.Time
+}
+
+// NewMetricsRollupJob creates a rollup job with the default windows.
+func NewMetricsRollupJob(predictions NewsRepository, rollups repository.MetricsRollupRepository) *MetricsRollupJob {
+	return &MetricsRollupJob{
+		predictions:     predictions,
+		rollups:         rollups,
+		settle:          DefaultRollupSettle,
+		hourlyRetention: DefaultRollupHourlyRetention,
+		dailyRetention:  DefaultRollupDailyRetention,
+		now:             time.Now,
+	}
+}
+
+// WithSettle sets how far back each run recomputes hourly rollups.
+func (j *MetricsRollupJob) WithSettle(settle time.Duration) *MetricsRollupJob {
+	if settle >= time.Hour {
+		j.settle = settle
+	}
+	return j
+}
+
+// WithRetention sets how long hourly and daily rollups are kept. Hourly
+// rollups are kept at least a day longer than the settle window, since
+// daily rollups are merged from them.
+func (j *MetricsRollupJob) WithRetention(hourly, daily time.Duration) *MetricsRollupJob {
+	if hourly > 0 {
+		j.hourlyRetention = hourly
+	}
+	if daily > 0 {
+		j.dailyRetention = daily
+	}
+	return j
+}
+
+// HourlyRetention returns how long hourly rollups are kept.
+func (j *MetricsRollupJob) HourlyRetention() time.Duration {
+	return max(j.hourlyRetention, j.settle+24*time.Hour)
+}
+
+// RunOnce rolls up the settle window up to the last completed hour and
+// prunes expired rollups.
+func (j *MetricsRollupJob) RunOnce(ctx context.Context) error {
+	now := j.now().UTC()
+	if err := j.Rollup(ctx, now.Add(-j.settle), now); err != nil {
+		return err
+	}
+	if _, err := j.rollups.DeleteBefore(ctx, domain.RollupHour, now.Add(-j.HourlyRetention())); err != nil {
+		return fmt.Errorf("failed to prune hourly rollups: %w", err)
+	}
+	if _, err := j.rollups.DeleteBefore(ctx, domain.RollupDay, now.Add(-j.dailyRetention)); err != nil {
+		return fmt.Errorf("failed to prune daily rollups: %w", err)
+	}
+	return nil
+}
+
+// Rollup recomputes the hourly rollups of every completed hour in [since,
+// until) from the stored predictions, then the daily rollups of the days
+// they fall in from the stored hourly rollups. Hours without predictions
+// are stored too, so a gap in a chart means no data rather than no
+// traffic. The current day's rollup covers its completed hours.
+func (j *MetricsRollupJob) Rollup(ctx context.Context, since, until time.Time) error {
+	start := since.UTC().Truncate(time.Hour)
+	end := until.UTC().Truncate(time.Hour)
+	if !start.Before(end) {
+		return nil
+	}
+	generated := j.now().UTC()
+
+	hours := make(map[time.Time]*domain.MetricsRollup)
+	for h := start; h.Before(end); h = h.Add(time.Hour) {
+		hours[h] = domain.NewMetricsRollup(domain.RollupHour, h, h.Add(time.Hour))
+	}
+	q := domain.NewPredictionQuery().WithDateRange(start, end)
+	err := j.predictions.Iterate(ctx, *q, func(p *domain.Prediction) error {
+		if rollup, ok := hours[p.CreatedAt.UTC().Truncate(time.Hour)]; ok {
+			rollup.Add(p)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load predictions: %w", err)
+	}
+	for _, rollup := range hours {
+		rollup.Finish()
+		rollup.GeneratedAt = generated
+		if err := j.rollups.Save(ctx, rollup); err != nil {
+			return fmt.Errorf("failed to save rollup %s: %w", rollup.ID, err)
+		}
+	}
+
+	for day := utcDay(start); day.Before(end); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		hourly, err := j.rollups.List(ctx, domain.RollupHour, day, next)
+		if err != nil {
+			return fmt.Errorf("failed to load hourly rollups: %w", err)
+		}
+		daily := domain.NewMetricsRollup(domain.RollupDay, day, next)
+		for _, h := range hourly {
+			daily.Merge(h)
+		}
+		daily.Finish()
+		daily.GeneratedAt = generated
+		if err := j.rollups.Save(ctx, daily); err != nil {
+			return fmt.Errorf("failed to save rollup %s: %w", daily.ID, err)
+		}
+	}
+	return nil
+}
+
+// List returns a resolution's rollups starting in [since, until), oldest
+// first.
+func (j *MetricsRollupJob) List(ctx context.Context, resolution string, since, until time.Time) ([]*domain.MetricsRollup, error) {
+	if !domain.IsValidRollupResolution(resolution) {
+		return nil, fmt.Errorf("%w: resolution must be hour or day", domain.ErrInvalidQuery)
+	}
+	return j.rollups.List(ctx, resolution, since.UTC(), until.UTC())
+}
+
+// Run rolls up every interval until ctx is cancelled.
+func (j *MetricsRollupJob) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.RunOnce(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Warning: metrics rollup failed: %v", err)
+			}
+		}
+	}
+}
+
+// utcDay returns the start of t's UTC day.
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestMetricsRollupJobDownsamplesAndPrunes(t *testing.T) {
+	now := time.Date(2024, 6, 2, 3, 30, 0, 0, time.UTC)
+	predictions := memory.NewPredictionRepository()
+	rollups := memory.NewMetricsRollupRepository()
+	next := 0
+	add := func(at time.Time, label string, ms int64, review string) {
+		next++
+		p := &domain.Prediction{
+			ID:             fmt.Sprintf("p%d", next),
+			Result:         label,
+			Confidence:     0.5,
+			RequestType:    "text",
+			ProcessingTime: ms,
+			CreatedAt:      at,
+		}
+		if review != "" {
+			p.Review = &domain.Review{Verdict: review}
+		}
+		if err := predictions.CreatePrediction(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 01:00 on the 1st: 10 predictions, 9 fast and one slow; two reviewed,
+	// one agreeing
+	hour := time.Date(2024, 6, 1, 1, 10, 0, 0, time.UTC)
+	add(hour, domain.LabelReal, 40, domain.LabelFake)
+	for i := 0; i < 8; i++ {
+		add(hour, domain.LabelReal, 40, "")
+	}
+	add(hour, domain.LabelFake, 4000, domain.LabelFake)
+	// 02:00 on the 2nd, and the current, incomplete hour
+	add(time.Date(2024, 6, 2, 2, 5, 0, 0, time.UTC), domain.LabelFake, 200, "")
+	add(now.Add(-time.Minute), domain.LabelFake, 200, "")
+
+	job := NewMetricsRollupJob(predictions, rollups)
+	job.now = func() time.Time { return now }
+	ctx := context.Background()
+	if err := job.Rollup(ctx, now.Add(-48*time.Hour), now); err != nil {
+		t.Fatal(err)
+	}
+
+	hourly, err := job.List(ctx, domain.RollupHour, hour.Truncate(time.Hour), hour.Truncate(time.Hour).Add(time.Hour))
+	if err != nil || len(hourly) != 1 {
+		t.Fatalf("hourly = %v, %v; want one rollup", hourly, err)
+	}
+	h := hourly[0]
+	if h.Total != 10 || h.Fake != 1 || h.Real != 9 || h.TextRequests != 10 {
+		t.Errorf("hourly counts = %+v", h)
+	}
+	if h.Reviewed != 2 || h.Agreed != 1 || h.Accuracy != 0.5 {
+		t.Errorf("accuracy = %d/%d = %v, want 1/2", h.Agreed, h.Reviewed, h.Accuracy)
+	}
+	if h.Latency.P50 != 50 || h.Latency.P99 != 4000 || h.Latency.Max != 4000 {
+		t.Errorf("latency = %+v, want p50 in the 50ms bucket and p99 4000", h.Latency)
+	}
+
+	// 31 May has no traffic but is stored, as zeroes
+	june := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	daily, err := job.List(ctx, domain.RollupDay, june, now)
+	if err != nil || len(daily) != 2 {
+		t.Fatalf("daily = %v, %v; want two days", daily, err)
+	}
+	if daily[0].Total != 10 || daily[0].Reviewed != 2 || daily[0].AvgConfidence != 0.5 || daily[0].Latency.P90 != 50 {
+		t.Errorf("1 June = %+v, want the 01:00 hour merged", daily[0])
+	}
+	if daily[1].Total != 1 || daily[1].Latency.P50 != 200 {
+		t.Errorf("2 June = %+v, want only its completed hours", daily[1])
+	}
+	if _, err := job.List(ctx, "week", now, now); err == nil {
+		t.Error("List accepted an unknown resolution")
+	}
+
+	// Two weeks on, hourly rollups have expired; daily ones are kept
+	job.now = func() time.Time { return now.Add(20 * 24 * time.Hour) }
+	if err := job.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if hourly, _ = job.List(ctx, domain.RollupHour, june, now); len(hourly) != 0 {
+		t.Errorf("hourly = %d rollups, want pruned", len(hourly))
+	}
+	if daily, _ = job.List(ctx, domain.RollupDay, june, now); len(daily) != 2 || daily[0].Total != 10 {
+		t.Errorf("daily = %v, want kept", daily)
+	}
+}
@@ -2,11 +2,15 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/Naman30903/Final-Year-Project/internal/domain"
@@ -19,6 +23,34 @@ type MLClient struct {
 	apiKey      string
 	predictPath string
 	healthPath  string
+
+	summarizeURL  string
+	summarizePath string
+
+	// Secondary model answering when the primary errors or times out
+	fallbackModel  string
+	fallbackURL    string
+	primaryTimeout time.Duration
+	stats          mlStats
+
+	compression *mlCompression // nil sends every body uncompressed
+}
+
+// mlStats counts how often the fallback chain is used.
+type mlStats struct {
+	primaryFailures  atomic.Int64
+	fallbackAnswers  atomic.Int64
+	fallbackFailures atomic.Int64
+}
+
+// MLStats is a snapshot of the client's fallback counters.
+type MLStats struct {
+	FallbackModel    string `json:"fallback_model,omitempty"`
+	PrimaryFailures  int64  `json:"primary_failures"`
+	FallbackAnswers  int64  `json:"fallback_answers"`
+	FallbackFailures int64  `json:"fallback_failures"`
+
+	Compression *MLCompressionStats `json:"compression,omitempty"`
 }
 
 // NewMLClient creates a new ML client.
@@ -28,8 +60,9 @@ func NewMLClient(baseURL string) *MLClient {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		predictPath: "/predict",
-		healthPath:  "/health",
+		predictPath:   "/predict",
+		healthPath:    "/health",
+		summarizePath: "/summarize",
 	}
 }
 
@@ -50,6 +83,57 @@ func (c *MLClient) WithPaths(predictPath, healthPath string) *MLClient {
 	return c
 }
 
+// WithFallback configures a secondary model (e.g. a smaller checkpoint) that
+// answers when the primary model errors or takes longer than primaryTimeout.
+// An empty baseURL sends the fallback to the primary ML service.
+func (c *MLClient) WithFallback(model, baseURL string, primaryTimeout time.Duration) *MLClient {
+	c.fallbackModel = model
+	c.fallbackURL = baseURL
+	if c.fallbackURL == "" {
+		c.fallbackURL = c.baseURL
+	}
+	c.primaryTimeout = primaryTimeout
+	return c
+}
+
+// WithOutboundAudit records every call to the ML services (primary,
+// fallback, per-organization routes and summarizer) in audit.
+func (c *MLClient) WithOutboundAudit(audit *OutboundAudit) *MLClient {
+	audit.Client(c.httpClient, PurposeML)
+	return c
+}
+
+// WithCompression gzips request bodies of at least minBytes for ML
+// services that advertise gzip support. 0 disables compression.
+func (c *MLClient) WithCompression(minBytes int) *MLClient {
+	c.compression = nil
+	if minBytes > 0 {
+		c.compression = newMLCompression(minBytes)
+	}
+	return c
+}
+
+// Stats returns the fallback and compression counters.
+func (c *MLClient) Stats() MLStats {
+	return MLStats{
+		FallbackModel:    c.fallbackModel,
+		PrimaryFailures:  c.stats.primaryFailures.Load(),
+		FallbackAnswers:  c.stats.fallbackAnswers.Load(),
+		FallbackFailures: c.stats.fallbackFailures.Load(),
+		Compression:      c.compression.stats(),
+	}
+}
+
+// WithSummarizer points summarization at another service or path. An empty
+// baseURL uses the prediction service.
+func (c *MLClient) WithSummarizer(baseURL, path string) *MLClient {
+	c.summarizeURL = baseURL
+	if path != "" {
+		c.summarizePath = normalizePath(path)
+	}
+	return c
+}
+
 func normalizePath(path string) string {
 	if strings.HasPrefix(path, "/") {
 		return path
@@ -65,12 +149,15 @@ func buildEndpoint(baseURL, path string) string {
 
 // MLPredictionRequest is the payload for POST /predict.
 type MLPredictionRequest struct {
-	Text string `json:"text"`
+	Text    string   `json:"text"`
+	Model   string   `json:"model,omitempty"`   // optional model/checkpoint override
+	Context []string `json:"context,omitempty"` // lead paragraphs of related articles
 }
 
 // MLURLRequest is the payload for POST /predict/url.
 type MLURLRequest struct {
-	URL string `json:"url"`
+	URL   string `json:"url"`
+	Model string `json:"model,omitempty"`
 }
 
 // MLPredictionResponse represents the full response from the ML service.
@@ -82,30 +169,154 @@ type MLPredictionResponse struct {
 	RealProbability      float64 `json:"real_probability"`
 	SourceURL            string  `json:"source_url,omitempty"`
 	ExtractedTextPreview string  `json:"extracted_text_preview,omitempty"`
+	RequestID            string  `json:"request_id,omitempty"`
+
+	// Monte-Carlo dropout variance of fake_probability and the number of
+	// forward passes behind it; absent when the model runs deterministically
+	FakeProbabilityVariance *float64 `json:"fake_probability_variance,omitempty"`
+	MCSamples               int      `json:"mc_samples,omitempty"`
+}
+
+// withModel returns a copy of the payload targeting another model.
+func (r MLPredictionRequest) withModel(model string) interface{} {
+	r.Model = model
+	return r
+}
+
+func (r MLURLRequest) withModel(model string) interface{} {
+	r.Model = model
+	return r
+}
+
+type modelPayload interface {
+	withModel(model string) interface{}
+}
+
+// MLSummarizeRequest is the payload for POST /summarize.
+type MLSummarizeRequest struct {
+	Text         string `json:"text"`
+	MaxSentences int    `json:"max_sentences"`
+}
+
+// MLSummarizeResponse is the response from POST /summarize.
+type MLSummarizeResponse struct {
+	Summary string `json:"summary"`
+}
+
+// summarySentences is the length of generated summaries.
+const summarySentences = 2
+
+type mlModelKey struct{}
+
+// ContextWithModel requests a specific model version from the ML service
+// for calls made with the returned context.
+func ContextWithModel(ctx context.Context, model string) context.Context {
+	return context.WithValue(ctx, mlModelKey{}, model)
+}
+
+// ModelFromContext returns the requested model version, if any.
+func ModelFromContext(ctx context.Context) string {
+	model, _ := ctx.Value(mlModelKey{}).(string)
+	return model
 }
 
 // ── Public methods ──
 
 // Predict sends pre-extracted text to POST /predict.
-func (c *MLClient) Predict(text string) (*domain.Prediction, error) {
-	reqBody := MLPredictionRequest{Text: text}
-	return c.doPredict(c.predictPath, reqBody)
+func (c *MLClient) Predict(ctx context.Context, text string) (*domain.Prediction, error) {
+	return c.PredictWithRelated(ctx, text, nil)
+}
+
+// PredictWithRelated sends text to POST /predict along with the lead
+// paragraphs of related articles as supporting context.
+func (c *MLClient) PredictWithRelated(ctx context.Context, text string, related []string) (*domain.Prediction, error) {
+	reqBody := MLPredictionRequest{Text: text, Model: ModelFromContext(ctx), Context: related}
+	return c.doPredict(ctx, c.predictPath, reqBody)
 }
 
 // PredictURL sends a URL to POST /predict/url — the ML service scrapes it.
-func (c *MLClient) PredictURL(articleURL string) (*domain.Prediction, error) {
-	reqBody := MLURLRequest{URL: articleURL}
-	return c.doPredict("/predict/url", reqBody)
+func (c *MLClient) PredictURL(ctx context.Context, articleURL string) (*domain.Prediction, error) {
+	reqBody := MLURLRequest{URL: articleURL, Model: ModelFromContext(ctx)}
+	return c.doPredict(ctx, "/predict/url", reqBody)
+}
+
+// Summarize asks the ML service for a short summary of the text.
+func (c *MLClient) Summarize(ctx context.Context, text string) (string, error) {
+	baseURL := c.summarizeURL
+	if baseURL == "" {
+		baseURL = c.baseURL
+	}
+
+	jsonData, err := json.Marshal(MLSummarizeRequest{Text: text, MaxSentences: summarySentences})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+	resp, err := c.postJSON(ctx, buildEndpoint(baseURL, c.summarizePath), c.apiKey, jsonData)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("summarization failed: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var sumResp MLSummarizeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&sumResp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	return strings.TrimSpace(sumResp.Summary), nil
+}
+
+// MLServiceInfo is the ML service's health response.
+type MLServiceInfo struct {
+	Status       string `json:"status"`
+	ModelVersion string `json:"model_version"`
+	Device       string `json:"device,omitempty"`
+}
+
+// ModelInfo fetches the health endpoint and returns the model metadata it
+// reports.
+func (c *MLClient) ModelInfo(ctx context.Context) (*MLServiceInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, buildEndpoint(c.baseURL, c.healthPath), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrMLServiceUnavailable, err)
+	}
+	defer resp.Body.Close()
+	c.compression.learn(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", domain.ErrMLServiceUnavailable, resp.StatusCode)
+	}
+	var info MLServiceInfo
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to parse health response: %w", err)
+	}
+	return &info, nil
 }
 
 // HealthCheck checks if ML service is available.
 func (c *MLClient) HealthCheck() error {
-	endpoint := buildEndpoint(c.baseURL, c.healthPath)
-	resp, err := c.httpClient.Get(endpoint)
+	return c.HealthCheckContext(context.Background())
+}
+
+// HealthCheckContext is HealthCheck bounded by ctx.
+func (c *MLClient) HealthCheckContext(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, buildEndpoint(c.baseURL, c.healthPath), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("%w: %v", domain.ErrMLServiceUnavailable, err)
 	}
 	defer resp.Body.Close()
+	c.compression.learn(resp)
 
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("%w: status %d", domain.ErrMLServiceUnavailable, resp.StatusCode)
@@ -115,34 +326,81 @@ func (c *MLClient) HealthCheck() error {
 
 // ── Internal ──
 
-func (c *MLClient) doPredict(path string, payload interface{}) (*domain.Prediction, error) {
-	startTime := time.Now()
+// doPredict calls the organization's own model when the context carries an
+// ML route, falling back to the default model if that endpoint fails.
+func (c *MLClient) doPredict(ctx context.Context, path string, payload interface{}) (*domain.Prediction, error) {
+	if route, ok := MLRouteFromContext(ctx); ok {
+		routePath := path
+		if path == c.predictPath {
+			routePath = route.PredictPath
+		}
+		prediction, err := c.post(ctx, buildEndpoint(route.BaseURL, routePath), route.MLAPIKey, payload)
+		if err == nil {
+			prediction.ModelRoute = route.Organization
+			return prediction, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		log.Printf("ML route %q failed (%v), falling back to default model", route.Organization, err)
+	}
+	return c.predictWithFallback(ctx, path, payload)
+}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+// predictWithFallback calls the primary model and, if it fails or exceeds
+// the primary timeout, retries once against the configured fallback model.
+// Predictions from the fallback are marked with FallbackModel.
+func (c *MLClient) predictWithFallback(ctx context.Context, path string, payload interface{}) (*domain.Prediction, error) {
+	mp, canFallback := payload.(modelPayload)
+	if c.fallbackModel == "" || !canFallback {
+		return c.post(ctx, buildEndpoint(c.baseURL, path), c.apiKey, payload)
 	}
 
-	endpoint := buildEndpoint(c.baseURL, path)
-	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	primaryCtx := ctx
+	if c.primaryTimeout > 0 {
+		var cancel context.CancelFunc
+		primaryCtx, cancel = context.WithTimeout(ctx, c.primaryTimeout)
+		defer cancel()
+	}
+	prediction, err := c.post(primaryCtx, buildEndpoint(c.baseURL, path), c.apiKey, payload)
+	if err == nil {
+		return prediction, nil
 	}
+	if ctx.Err() != nil {
+		return nil, err
+	}
+	c.stats.primaryFailures.Add(1)
+	log.Printf("Primary model failed (%v), falling back to %q", err, c.fallbackModel)
 
-	req.Header.Set("Content-Type", "application/json")
-	if c.apiKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	prediction, fallbackErr := c.post(ctx, buildEndpoint(c.fallbackURL, path), c.apiKey, mp.withModel(c.fallbackModel))
+	if fallbackErr != nil {
+		c.stats.fallbackFailures.Add(1)
+		return nil, fmt.Errorf("%w (fallback model %s: %v)", err, c.fallbackModel, fallbackErr)
+	}
+	c.stats.fallbackAnswers.Add(1)
+	prediction.FallbackModel = c.fallbackModel
+	if prediction.ModelVersion == "" {
+		prediction.ModelVersion = c.fallbackModel
 	}
+	return prediction, nil
+}
 
-	resp, err := c.httpClient.Do(req)
+func (c *MLClient) post(ctx context.Context, endpoint, apiKey string, payload interface{}) (*domain.Prediction, error) {
+	startTime := time.Now()
+
+	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", domain.ErrMLServiceUnavailable, err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	resp, err := c.postJSON(ctx, endpoint, apiKey, jsonData)
+	if err != nil {
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("%w: failed to read response: %v", domain.ErrMLServiceUnavailable, err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -152,7 +410,7 @@ func (c *MLClient) doPredict(path string, payload interface{}) (*domain.Predicti
 
 	var mlResp MLPredictionResponse
 	if err := json.Unmarshal(body, &mlResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return nil, fmt.Errorf("%w: failed to parse response: %v", domain.ErrPredictionFailed, err)
 	}
 
 	prediction := &domain.Prediction{
@@ -161,9 +419,77 @@ func (c *MLClient) doPredict(path string, payload interface{}) (*domain.Predicti
 		FakeProbability: mlResp.FakeProbability,
 		RealProbability: mlResp.RealProbability,
 		ModelVersion:    mlResp.ModelVersion,
+		MLRequestID:     mlResp.RequestID,
 		ProcessingTime:  time.Since(startTime).Milliseconds(),
 		CreatedAt:       time.Now(),
 	}
+	if mlResp.FakeProbabilityVariance != nil {
+		prediction.Uncertainty = domain.NewUncertainty(mlResp.FakeProbability, *mlResp.FakeProbabilityVariance, mlResp.MCSamples)
+	}
+	if id := resp.Header.Get(HeaderRequestID); id != "" {
+		prediction.MLRequestID = id
+	}
+	prediction.Provenance = &domain.Provenance{
+		ModelEndpoint: redactEndpoint(endpoint),
+		ModelVersion:  mlResp.ModelVersion,
+	}
 
 	return prediction, nil
 }
+
+// redactEndpoint drops credentials and query parameters from an endpoint
+// before it is stored on a prediction.
+func redactEndpoint(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	u.User, u.RawQuery, u.Fragment = nil, "", ""
+	return u.String()
+}
+
+// postJSON sends a JSON body, gzipped when the service accepts it. A
+// service that refuses the compressed body with 415 gets it again plain.
+func (c *MLClient) postJSON(ctx context.Context, endpoint, apiKey string, data []byte) (*http.Response, error) {
+	send := func(body []byte, encoding string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrMLServiceUnavailable, err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if encoding != "" {
+			req.Header.Set("Content-Encoding", encoding)
+		}
+		setTraceHeaders(req)
+		if apiKey != "" {
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrMLServiceUnavailable, err)
+		}
+		c.compression.learn(resp)
+		return resp, nil
+	}
+
+	host := ""
+	if u, err := url.Parse(endpoint); err == nil {
+		host = u.Host
+	}
+	compressed := c.compression.compress(host, data)
+	if compressed == nil {
+		return send(data, "")
+	}
+	resp, err := send(compressed, "gzip")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnsupportedMediaType {
+		c.compression.accepted(len(data), len(compressed))
+		return resp, nil
+	}
+	resp.Body.Close()
+	c.compression.refuse(host)
+	log.Printf("Warning: ML service %s refused a gzip request body; sending it uncompressed from now on", host)
+	return send(data, "")
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMLClientFallback(t *testing.T) {
+	tests := []struct {
+		name         string
+		primary      func(w http.ResponseWriter)
+		wantFallback bool
+	}{
+		{
+			name: "primary answers",
+			primary: func(w http.ResponseWriter) {
+				json.NewEncoder(w).Encode(MLPredictionResponse{Result: "REAL", ModelVersion: "large-v2"})
+			},
+		},
+		{
+			name:         "primary errors",
+			primary:      func(w http.ResponseWriter) { w.WriteHeader(http.StatusInternalServerError) },
+			wantFallback: true,
+		},
+		{
+			name: "primary times out",
+			primary: func(w http.ResponseWriter) {
+				time.Sleep(200 * time.Millisecond)
+				json.NewEncoder(w).Encode(MLPredictionResponse{Result: "REAL"})
+			},
+			wantFallback: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req MLPredictionRequest
+				json.NewDecoder(r.Body).Decode(&req)
+				if req.Model == "small-v1" {
+					json.NewEncoder(w).Encode(MLPredictionResponse{Result: "FAKE"})
+					return
+				}
+				tt.primary(w)
+			}))
+			defer srv.Close()
+
+			client := NewMLClient(srv.URL).WithFallback("small-v1", "", 50*time.Millisecond)
+			prediction, err := client.Predict(context.Background(), "some text")
+			if err != nil {
+				t.Fatalf("Predict() error = %v", err)
+			}
+
+			if got := prediction.FallbackModel != ""; got != tt.wantFallback {
+				t.Errorf("FallbackModel = %q, want fallback %v", prediction.FallbackModel, tt.wantFallback)
+			}
+			if tt.wantFallback && prediction.ModelVersion != "small-v1" {
+				t.Errorf("ModelVersion = %q, want small-v1", prediction.ModelVersion)
+			}
+			stats := client.Stats()
+			if tt.wantFallback && (stats.PrimaryFailures != 1 || stats.FallbackAnswers != 1) {
+				t.Errorf("unexpected stats %+v", stats)
+			}
+		})
+	}
+}
+
+func TestMLClientUncertainty(t *testing.T) {
+	variance := 0.0225
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(MLPredictionResponse{
+			Result: "FAKE", Confidence: 0.8, FakeProbability: 0.8, RealProbability: 0.2,
+			FakeProbabilityVariance: &variance, MCSamples: 30,
+		})
+	}))
+	defer srv.Close()
+
+	prediction, err := NewMLClient(srv.URL).Predict(context.Background(), "some text")
+	if err != nil {
+		t.Fatalf("Predict() error = %v", err)
+	}
+	u := prediction.Uncertainty
+	if u == nil || u.StdDev != 0.15 || u.Samples != 30 {
+		t.Fatalf("Uncertainty = %+v, want std dev 0.15 over 30 samples", u)
+	}
+	if u.Upper != 1 || u.Lower < 0.505 || u.Lower > 0.507 || u.Straddles() {
+		t.Errorf("interval = [%v, %v], want about [0.506, 1] without crossing 0.5", u.Lower, u.Upper)
+	}
+}
+
+func TestMLClientSummarize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/summarize" {
+			http.NotFound(w, r)
+			return
+		}
+		var req MLSummarizeRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.MaxSentences != 2 {
+			t.Errorf("max_sentences = %d, want 2", req.MaxSentences)
+		}
+		json.NewEncoder(w).Encode(MLSummarizeResponse{Summary: " First. Second. "})
+	}))
+	defer srv.Close()
+
+	summary, err := NewMLClient(srv.URL).Summarize(context.Background(), "First. Second. Third.")
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if summary != "First. Second." {
+		t.Errorf("Summarize() = %q", summary)
+	}
+
+	if _, err := NewMLClient(srv.URL).WithSummarizer("", "/missing").Summarize(context.Background(), "x"); err == nil {
+		t.Error("expected error for failing summarizer")
+	}
+}
+
+func TestMLClientTracePropagation(t *testing.T) {
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get(HeaderRequestID); got != "req-1" {
+			t.Errorf("X-Request-ID = %q, want req-1", got)
+		}
+		if got := r.Header.Get(HeaderTraceParent); got != traceParent {
+			t.Errorf("traceparent = %q", got)
+		}
+		w.Header().Set(HeaderRequestID, "ml-42")
+		json.NewEncoder(w).Encode(MLPredictionResponse{Result: "REAL"})
+	}))
+	defer srv.Close()
+
+	ctx := ContextWithTrace(context.Background(), Trace{RequestID: "req-1", TraceParent: traceParent})
+	prediction, err := NewMLClient(srv.URL).Predict(ctx, "some text")
+	if err != nil {
+		t.Fatalf("Predict() error = %v", err)
+	}
+	if prediction.MLRequestID != "ml-42" {
+		t.Errorf("MLRequestID = %q, want ml-42", prediction.MLRequestID)
+	}
+}
+
+func TestMLClientCompression(t *testing.T) {
+	longText := strings.Repeat("The minister said the figures were accurate. ", 100)
+
+	tests := []struct {
+		name          string
+		advertise     bool
+		refuse        bool
+		text          string
+		wantEncodings []string // Content-Encoding of each predict request
+		wantSaved     bool
+	}{
+		{name: "service does not advertise gzip", text: longText, wantEncodings: []string{"", ""}},
+		{name: "advertised and long", advertise: true, text: longText, wantEncodings: []string{"gzip", "gzip"}, wantSaved: true},
+		{name: "advertised but short", advertise: true, text: "short", wantEncodings: []string{"", ""}},
+		{name: "advertised but refused", advertise: true, refuse: true, text: longText, wantEncodings: []string{"gzip", "", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var encodings []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.advertise {
+					w.Header().Set("Accept-Encoding", "gzip")
+				}
+				if r.URL.Path == "/health" {
+					return
+				}
+				encoding := r.Header.Get("Content-Encoding")
+				encodings = append(encodings, encoding)
+				body := io.Reader(r.Body)
+				if encoding == "gzip" {
+					if tt.refuse {
+						w.WriteHeader(http.StatusUnsupportedMediaType)
+						return
+					}
+					gz, err := gzip.NewReader(r.Body)
+					if err != nil {
+						t.Errorf("invalid gzip body: %v", err)
+						return
+					}
+					body = gz
+				}
+				var req MLPredictionRequest
+				if err := json.NewDecoder(body).Decode(&req); err != nil || req.Text != tt.text {
+					t.Errorf("request text mangled: %v", err)
+				}
+				json.NewEncoder(w).Encode(MLPredictionResponse{Result: "REAL"})
+			}))
+			defer srv.Close()
+
+			client := NewMLClient(srv.URL).WithCompression(DefaultMLCompressMinBytes)
+			if err := client.HealthCheck(); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 2; i++ {
+				if _, err := client.Predict(context.Background(), tt.text); err != nil {
+					t.Fatalf("Predict() error = %v", err)
+				}
+			}
+
+			if strings.Join(encodings, ",") != strings.Join(tt.wantEncodings, ",") {
+				t.Errorf("encodings = %q, want %q", encodings, tt.wantEncodings)
+			}
+			stats := client.Stats().Compression
+			if got := stats.BytesSaved > 0; got != tt.wantSaved {
+				t.Errorf("stats = %+v, want bytes saved %v", stats, tt.wantSaved)
+			}
+			if tt.refuse && stats.Refused != 1 {
+				t.Errorf("Refused = %d, want 1", stats.Refused)
+			}
+		})
+	}
+
+	if NewMLClient("http://ml").Stats().Compression != nil {
+		t.Error("compression stats reported while compression is disabled")
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultMLCompressMinBytes is the smallest ML request body worth
+// compressing; shorter bodies barely shrink.
+const DefaultMLCompressMinBytes = 1024
+
+// mlCompression gzips request bodies for ML services that accept it. A
+// service opts in by sending "Accept-Encoding: gzip" on its responses
+// (RFC 7694), so bodies are sent plain until the first response from a
+// host, and again for good if the host answers a compressed body with 415.
+type mlCompression struct {
+	minBytes int
+
+	mu      sync.Mutex
+	hosts   map[string]bool // host -> accepts gzip
+	refused map[string]bool // hosts that answered gzip with 415
+
+	requests    atomic.Int64
+	rawBytes    atomic.Int64
+	sentBytes   atomic.Int64
+	unsupported atomic.Int64
+}
+
+// MLCompressionStats counts compressed ML requests.
+type MLCompressionStats struct {
+	MinBytes           int   `json:"min_bytes"`
+	CompressedRequests int64 `json:"compressed_requests"`
+	BytesBefore        int64 `json:"bytes_before"`
+	BytesSent          int64 `json:"bytes_sent"`
+	BytesSaved         int64 `json:"bytes_saved"`
+	Refused            int64 `json:"refused"` // compressed requests answered with 415
+}
+
+func newMLCompression(minBytes int) *mlCompression {
+	return &mlCompression{minBytes: minBytes, hosts: make(map[string]bool), refused: make(map[string]bool)}
+}
+
+// learn records whether the response's host advertises gzip support.
+func (m *mlCompression) learn(resp *http.Response) {
+	if m == nil || resp.Request == nil {
+		return
+	}
+	accepts := false
+	for _, coding := range strings.Split(resp.Header.Get("Accept-Encoding"), ",") {
+		coding, _, _ = strings.Cut(coding, ";")
+		if strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			accepts = true
+		}
+	}
+	host := resp.Request.URL.Host
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.refused[host] {
+		m.hosts[host] = accepts
+	}
+}
+
+// refuse stops compressing for host.
+func (m *mlCompression) refuse(host string) {
+	m.unsupported.Add(1)
+	m.mu.Lock()
+	m.hosts[host] = false
+	m.refused[host] = true
+	m.mu.Unlock()
+}
+
+// compress returns the gzipped body when host accepts gzip and data is
+// long enough, or nil to send data as is.
+func (m *mlCompression) compress(host string, data []byte) []byte {
+	if m == nil || len(data) < m.minBytes {
+		return nil
+	}
+	m.mu.Lock()
+	accepts := m.hosts[host]
+	m.mu.Unlock()
+	if !accepts {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil || gz.Close() != nil || buf.Len() >= len(data) {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// accepted counts a compressed body the service took.
+func (m *mlCompression) accepted(raw, sent int) {
+	m.requests.Add(1)
+	m.rawBytes.Add(int64(raw))
+	m.sentBytes.Add(int64(sent))
+}
+
+func (m *mlCompression) stats() *MLCompressionStats {
+	if m == nil {
+		return nil
+	}
+	raw, sent := m.rawBytes.Load(), m.sentBytes.Load()
+	return &MLCompressionStats{
+		MinBytes:           m.minBytes,
+		CompressedRequests: m.requests.Load(),
+		BytesBefore:        raw,
+		BytesSent:          sent,
+		BytesSaved:         raw - sent,
+		Refused:            m.unsupported.Load(),
+	}
+}
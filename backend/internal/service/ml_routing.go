@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// MLRoute is an override ML endpoint for a partner organization that runs
+// its own fine-tuned model.
+type MLRoute struct {
+	Organization string   `json:"organization"`
+	APIKeys      []string `json:"api_keys"`     // client API keys routed to this endpoint
+	BaseURL      string   `json:"ml_url"`       // partner ML service base URL
+	MLAPIKey     string   `json:"ml_api_key"`   // bearer token for the partner ML service
+	PredictPath  string   `json:"predict_path"` // optional, defaults to /predict
+}
+
+// MLRouter resolves client API keys to per-organization ML routes.
+type MLRouter struct {
+	byKey map[string]*MLRoute
+}
+
+// NewMLRouter creates a router from a list of routes.
+func NewMLRouter(routes []MLRoute) (*MLRouter, error) {
+	router := &MLRouter{byKey: make(map[string]*MLRoute)}
+	for i := range routes {
+		route := &routes[i]
+		if route.BaseURL == "" {
+			return nil, fmt.Errorf("ml route for %q has no ml_url", route.Organization)
+		}
+		if route.PredictPath == "" {
+			route.PredictPath = "/predict"
+		}
+		route.PredictPath = normalizePath(route.PredictPath)
+		for _, key := range route.APIKeys {
+			if _, dup := router.byKey[key]; dup {
+				return nil, fmt.Errorf("api key assigned to more than one ml route")
+			}
+			router.byKey[key] = route
+		}
+	}
+	return router, nil
+}
+
+// LoadMLRouter reads routes from a JSON file containing an array of MLRoute.
+func LoadMLRouter(path string) (*MLRouter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ml routes: %w", err)
+	}
+	var routes []MLRoute
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, fmt.Errorf("failed to parse ml routes: %w", err)
+	}
+	return NewMLRouter(routes)
+}
+
+// Resolve returns the route configured for an API key, if any.
+func (r *MLRouter) Resolve(apiKey string) (*MLRoute, bool) {
+	if r == nil || apiKey == "" {
+		return nil, false
+	}
+	route, ok := r.byKey[apiKey]
+	return route, ok
+}
+
+// Len returns the number of configured API keys.
+func (r *MLRouter) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.byKey)
+}
+
+type mlRouteKey struct{}
+
+// ContextWithMLRoute attaches an ML route to the request context.
+func ContextWithMLRoute(ctx context.Context, route *MLRoute) context.Context {
+	return context.WithValue(ctx, mlRouteKey{}, route)
+}
+
+// MLRouteFromContext returns the ML route attached to ctx, if any.
+func MLRouteFromContext(ctx context.Context) (*MLRoute, bool) {
+	route, ok := ctx.Value(mlRouteKey{}).(*MLRoute)
+	return route, ok && route != nil
+}
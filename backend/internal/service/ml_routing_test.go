@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestNewMLRouter(t *testing.T) {
+	router, err := NewMLRouter([]MLRoute{
+		{Organization: "acme", APIKeys: []string{"acme-1", "acme-2"}, BaseURL: "http://acme.ml"},
+		{Organization: "globex", APIKeys: []string{"globex-1"}, BaseURL: "http://globex.ml", PredictPath: "v2/score"},
+	})
+	if err != nil {
+		t.Fatalf("NewMLRouter: %v", err)
+	}
+	if router.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", router.Len())
+	}
+	tests := []struct {
+		key      string
+		wantOrg  string
+		wantPath string
+	}{
+		{"acme-2", "acme", "/predict"},
+		{"globex-1", "globex", "/v2/score"},
+		{"unknown", "", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		route, ok := router.Resolve(tt.key)
+		if !ok {
+			if tt.wantOrg != "" {
+				t.Errorf("Resolve(%q) found nothing, want %s", tt.key, tt.wantOrg)
+			}
+			continue
+		}
+		if route.Organization != tt.wantOrg || route.PredictPath != tt.wantPath {
+			t.Errorf("Resolve(%q) = %s %s, want %s %s", tt.key, route.Organization, route.PredictPath, tt.wantOrg, tt.wantPath)
+		}
+	}
+
+	for name, routes := range map[string][]MLRoute{
+		"duplicate key": {
+			{Organization: "acme", APIKeys: []string{"shared"}, BaseURL: "http://acme.ml"},
+			{Organization: "globex", APIKeys: []string{"shared"}, BaseURL: "http://globex.ml"},
+		},
+		"missing ml_url": {{Organization: "acme", APIKeys: []string{"acme-1"}}},
+	} {
+		if _, err := NewMLRouter(routes); err == nil {
+			t.Errorf("%s: NewMLRouter succeeded, want error", name)
+		}
+	}
+
+	var nilRouter *MLRouter
+	if _, ok := nilRouter.Resolve("acme-1"); ok || nilRouter.Len() != 0 {
+		t.Error("a nil router resolved a route")
+	}
+}
+
+func TestMLClientRoutesToPartnerModel(t *testing.T) {
+	var defaultCalls atomic.Int32
+	defaultML := mlServer(t, domain.LabelReal, 0.1, &defaultCalls)
+	var partnerAuth, partnerPath string
+	partnerUp := true
+	partnerML := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		partnerAuth, partnerPath = r.Header.Get("Authorization"), r.URL.Path
+		if !partnerUp {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(MLPredictionResponse{Result: domain.LabelFake, Confidence: 0.9, FakeProbability: 0.9, RealProbability: 0.1})
+	}))
+	defer partnerML.Close()
+
+	router, err := NewMLRouter([]MLRoute{{Organization: "acme", APIKeys: []string{"acme-1"}, BaseURL: partnerML.URL, MLAPIKey: "partner-secret", PredictPath: "/v2/score"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	route, _ := router.Resolve("acme-1")
+	client := NewMLClient(defaultML.URL)
+	ctx := ContextWithMLRoute(context.Background(), route)
+
+	p, err := client.Predict(ctx, "some text")
+	if err != nil {
+		t.Fatalf("Predict: %v", err)
+	}
+	if p.ModelRoute != "acme" || p.Result != domain.LabelFake || defaultCalls.Load() != 0 {
+		t.Errorf("routed prediction = %s by %q with %d default calls; want FAKE by acme only", p.Result, p.ModelRoute, defaultCalls.Load())
+	}
+	if partnerPath != "/v2/score" || partnerAuth != "Bearer partner-secret" {
+		t.Errorf("partner request = %s with %q, want /v2/score with the partner key", partnerPath, partnerAuth)
+	}
+
+	// A failing partner endpoint falls back to the default model
+	partnerUp = false
+	p, err = client.Predict(ctx, "some text")
+	if err != nil {
+		t.Fatalf("Predict with partner down: %v", err)
+	}
+	if p.ModelRoute != "" || p.Result != domain.LabelReal || defaultCalls.Load() != 1 {
+		t.Errorf("fallback prediction = %s by %q with %d default calls; want REAL by the default model", p.Result, p.ModelRoute, defaultCalls.Load())
+	}
+
+	// Requests without a route never reach the partner
+	partnerPath = ""
+	if _, err := client.Predict(context.Background(), "some text"); err != nil || partnerPath != "" {
+		t.Errorf("unrouted Predict = %v, partner path %q; want the default model only", err, partnerPath)
+	}
+}
+
+// mlServer answers every prediction with result and counts the calls.
+func mlServer(t *testing.T, result string, fakeProbability float64, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		json.NewEncoder(w).Encode(MLPredictionResponse{Result: result, Confidence: 0.9, FakeProbability: fakeProbability, RealProbability: 1 - fakeProbability})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAnalyzeURLRoutedModelSkipsSharedVerdicts(t *testing.T) {
+	var defaultCalls, partnerCalls atomic.Int32
+	defaultML := mlServer(t, domain.LabelReal, 0.1, &defaultCalls)
+	partnerML := mlServer(t, domain.LabelFake, 0.9, &partnerCalls)
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><head><title>Harbor reopens</title></head><body><article>
+<p>The city harbor reopened on Monday after three months of repairs to the breakwater, officials said.</p>
+<p>Fishing crews returned to the docks at dawn, and the first ferry left on schedule at nine.</p>
+</article></body></html>`))
+	}))
+	defer site.Close()
+	scraper := NewScraperService()
+	scraper.httpClient = &http.Client{Transport: rewriteTransport{target: site.URL}}
+
+	const article = "http://harbor.example/2024/harbor-reopens"
+	repo := memory.NewPredictionRepository()
+	if err := repo.CreatePrediction(&domain.Prediction{
+		ID: "shared", RequestType: "url", OriginalContent: article, CanonicalURL: NormalizeURL(article),
+		Result: domain.LabelReal, Confidence: 0.7, Method: domain.MethodModel, CreatedAt: time.Now(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewNewsService(NewMLClient(defaultML.URL), scraper, repo)
+	route := &MLRoute{Organization: "acme", BaseURL: partnerML.URL, PredictPath: "/predict"}
+	routed := ContextWithMLRoute(context.Background(), route)
+
+	// A routed caller gets its own model's verdict, not the shared one
+	p, err := svc.AnalyzeNews(routed, &domain.AnalysisRequest{Type: "url", Content: article})
+	if err != nil {
+		t.Fatalf("routed AnalyzeNews: %v", err)
+	}
+	if p.ID == "shared" || p.ModelRoute != "acme" || p.Result != domain.LabelFake || p.CanonicalURL != "" {
+		t.Errorf("routed prediction = %+v; want a new acme verdict without a canonical URL", p)
+	}
+	if partnerCalls.Load() != 1 || defaultCalls.Load() != 0 {
+		t.Errorf("calls = %d partner, %d default; want 1, 0", partnerCalls.Load(), defaultCalls.Load())
+	}
+
+	// A stored partner verdict is never served to other callers
+	if err := repo.DeletePrediction("shared"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreatePrediction(&domain.Prediction{
+		ID: "partner", RequestType: "url", OriginalContent: article, CanonicalURL: NormalizeURL(article),
+		Result: domain.LabelFake, Confidence: 0.9, Method: domain.MethodModel, ModelRoute: "acme", CreatedAt: time.Now(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	p, err = svc.AnalyzeNews(context.Background(), &domain.AnalysisRequest{Type: "url", Content: article})
+	if err != nil {
+		t.Fatalf("default AnalyzeNews: %v", err)
+	}
+	if p.ID == "partner" || p.ModelRoute != "" || p.Result != domain.LabelReal {
+		t.Errorf("default prediction = %+v; want a new default-model verdict", p)
+	}
+	if defaultCalls.Load() != 1 {
+		t.Errorf("default model calls = %d, want 1", defaultCalls.Load())
+	}
+}
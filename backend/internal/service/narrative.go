@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+	"github.com/google/uuid"
+)
+
+// DefaultMaxNarratives caps narratives per organization.
+const DefaultMaxNarratives = 100
+
+// narrativeMatcher is a narrative's compiled keywords and patterns.
+type narrativeMatcher struct {
+	terms    [2][]string // the keywords and patterns compiled
+	keywords []string    // lower-cased
+	patterns []*regexp.Regexp
+}
+
+func compileNarrative(n *domain.Narrative) *narrativeMatcher {
+	m := &narrativeMatcher{terms: [2][]string{n.Keywords, n.Patterns}}
+	for _, k := range n.Keywords {
+		m.keywords = append(m.keywords, strings.ToLower(k))
+	}
+	for _, p := range n.Patterns {
+		re, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			log.Printf("Warning: narrative %s has an invalid pattern %q: %v", n.ID, p, err)
+			continue
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m
+}
+
+// matches reports whether text, or its lower-cased form lower, contains a
+// keyword or matches a pattern.
+func (m *narrativeMatcher) matches(text, lower string) bool {
+	for _, k := range m.keywords {
+		if strings.Contains(lower, k) {
+			return true
+		}
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// NarrativeService manages organizations' glossaries of flagged
+// narratives, tags predictions that match them and aggregates each
+// narrative's predictions.
+//
+// Every organization's narratives are matched against every new
+// prediction, so a narrative tracks a story across all traffic, not only
+// its own organization's analyses. Predictions carry narrative IDs only;
+// names, patterns and stats are visible to the owning organization.
+type NarrativeService struct {
+	repo          repository.NarrativeRepository
+	predictions   NewsRepository
+	maxNarratives int
+	now           func() time.Time
+
+	mu       sync.Mutex
+	matchers map[string]*narrativeMatcher // by narrative ID
+}
+
+// NewNarrativeService creates a narrative service tagging predictions
+// stored in predictions.
+func NewNarrativeService(repo repository.NarrativeRepository, predictions NewsRepository) *NarrativeService {
+	return &NarrativeService{
+		repo:          repo,
+		predictions:   predictions,
+		maxNarratives: DefaultMaxNarratives,
+		now:           time.Now,
+		matchers:      make(map[string]*narrativeMatcher),
+	}
+}
+
+// WithMaxNarratives caps how many narratives one organization can define.
+func (s *NarrativeService) WithMaxNarratives(max int) *NarrativeService {
+	if max > 0 {
+		s.maxNarratives = max
+	}
+	return s
+}
+
+// Create adds a narrative to an organization's glossary and tags the
+// stored predictions that match it. It returns how many were tagged.
+func (s *NarrativeService) Create(ctx context.Context, orgID, actor string, narrative domain.Narrative) (*domain.Narrative, int, error) {
+	if err := narrative.Validate(); err != nil {
+		return nil, 0, err
+	}
+	existing, err := s.repo.ListByOrg(ctx, orgID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(existing) >= s.maxNarratives {
+		return nil, 0, fmt.Errorf("%w (%d)", domain.ErrNarrativeLimitReached, s.maxNarratives)
+	}
+
+	now := s.now()
+	saved := &domain.Narrative{
+		ID:          uuid.New().String(),
+		OrgID:       orgID,
+		Name:        narrative.Name,
+		Description: narrative.Description,
+		Keywords:    narrative.Keywords,
+		Patterns:    narrative.Patterns,
+		CreatedBy:   actor,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Save(ctx, saved); err != nil {
+		return nil, 0, err
+	}
+	tagged, err := s.retag(ctx, saved)
+	return saved, tagged, err
+}
+
+// List returns an organization's narratives.
+func (s *NarrativeService) List(ctx context.Context, orgID string) ([]*domain.Narrative, error) {
+	return s.repo.ListByOrg(ctx, orgID)
+}
+
+// Get returns one of an organization's narratives. Other organizations'
+// narratives are reported as not found.
+func (s *NarrativeService) Get(ctx context.Context, orgID, id string) (*domain.Narrative, error) {
+	narrative, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if narrative.OrgID != orgID {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrNarrativeNotFound, id)
+	}
+	return narrative, nil
+}
+
+// Update replaces a narrative's name, description, keywords and patterns,
+// and re-tags stored predictions against them. It returns how many
+// predictions carry the narrative afterwards.
+func (s *NarrativeService) Update(ctx context.Context, orgID, id string, change domain.Narrative) (*domain.Narrative, int, error) {
+	narrative, err := s.Get(ctx, orgID, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := change.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	narrative.Name = change.Name
+	narrative.Description = change.Description
+	narrative.Keywords = change.Keywords
+	narrative.Patterns = change.Patterns
+	narrative.UpdatedAt = s.now()
+	if err := s.repo.Save(ctx, narrative); err != nil {
+		return nil, 0, err
+	}
+	tagged, err := s.retag(ctx, narrative)
+	return narrative, tagged, err
+}
+
+// Delete removes a narrative and its tag from stored predictions.
+func (s *NarrativeService) Delete(ctx context.Context, orgID, id string) error {
+	if _, err := s.Get(ctx, orgID, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.matchers, id)
+	s.mu.Unlock()
+
+	tagged, err := s.predictions.Query(ctx, *domain.NewPredictionQuery().WithNarrative(id))
+	if err != nil {
+		return fmt.Errorf("failed to load tagged predictions: %w", err)
+	}
+	for _, p := range tagged {
+		updated := *p
+		updated.Narratives = slices.DeleteFunc(slices.Clone(p.Narratives), func(n string) bool { return n == id })
+		if len(updated.Narratives) == 0 {
+			updated.Narratives = nil
+		}
+		if err := s.predictions.UpdatePrediction(&updated); err != nil {
+			return fmt.Errorf("failed to untag prediction %s: %w", p.ID, err)
+		}
+	}
+	return nil
+}
+
+// Tag sets the narratives a new prediction matches, given the article
+// text it was scored on. The title and description are matched too.
+func (s *NarrativeService) Tag(ctx context.Context, p *domain.Prediction, text string) {
+	if s == nil {
+		return
+	}
+	narratives, err := s.repo.List(ctx)
+	if err != nil {
+		log.Printf("Warning: failed to load narratives: %v", err)
+		return
+	}
+	text = matchText(p, text)
+	lower := strings.ToLower(text)
+	p.Narratives = nil
+	for _, n := range narratives {
+		if s.matcher(n).matches(text, lower) {
+			p.Narratives = append(p.Narratives, n.ID)
+		}
+	}
+}
+
+// matcher returns n's compiled matcher, recompiling it when its keywords
+// or patterns changed.
+func (s *NarrativeService) matcher(n *domain.Narrative) *narrativeMatcher {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.matchers[n.ID]
+	if !ok || !slices.Equal(m.terms[0], n.Keywords) || !slices.Equal(m.terms[1], n.Patterns) {
+		m = compileNarrative(n)
+		s.matchers[n.ID] = m
+	}
+	return m
+}
+
+// retag adds or removes n's tag on every stored prediction. Stored
+// predictions keep no article text for URLs, so those are matched on their
+// title and description only. It returns how many predictions carry the
+// tag.
+func (s *NarrativeService) retag(ctx context.Context, n *domain.Narrative) (int, error) {
+	m := s.matcher(n)
+	var changed []*domain.Prediction
+	tagged := 0
+	err := s.predictions.Iterate(ctx, domain.PredictionQuery{}, func(p *domain.Prediction) error {
+		text := ""
+		if p.RequestType == "text" {
+			text = p.OriginalContent
+		}
+		text = matchText(p, text)
+		want := m.matches(text, strings.ToLower(text))
+		if want {
+			tagged++
+		}
+		if want == slices.Contains(p.Narratives, n.ID) {
+			return nil
+		}
+
+		updated := *p
+		if want {
+			updated.Narratives = append(slices.Clone(p.Narratives), n.ID)
+		} else {
+			updated.Narratives = slices.DeleteFunc(slices.Clone(p.Narratives), func(id string) bool { return id == n.ID })
+		}
+		changed = append(changed, &updated)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load predictions: %w", err)
+	}
+	for _, p := range changed {
+		if len(p.Narratives) == 0 {
+			p.Narratives = nil
+		}
+		if err := s.predictions.UpdatePrediction(p); err != nil {
+			return 0, fmt.Errorf("failed to tag prediction %s: %w", p.ID, err)
+		}
+	}
+	return tagged, nil
+}
+
+func matchText(p *domain.Prediction, text string) string {
+	return strings.Join([]string{text, p.ArticleTitle, p.ArticleDescription}, "\n")
+}
+
+// Stats aggregates the predictions tagged with one of an organization's
+// narratives over period, in buckets of interval, with its busiest source
+// domains. First and last seen cover all of history.
+func (s *NarrativeService) Stats(ctx context.Context, orgID, id string, period domain.Period, interval time.Duration, top int) (*domain.NarrativeStats, error) {
+	if top <= 0 || top > domain.MaxCompareTopDomains {
+		return nil, fmt.Errorf("%w: top must be between 1 and %d", domain.ErrInvalidQuery, domain.MaxCompareTopDomains)
+	}
+	series := domain.TimeSeriesQuery{
+		Metric:   domain.MetricFakeRatio,
+		Interval: interval,
+		Since:    period.Since,
+		Until:    period.Until,
+		Filter:   *domain.NewPredictionQuery().WithNarrative(id),
+	}
+	if err := series.Validate(); err != nil {
+		return nil, err
+	}
+	narrative, err := s.Get(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.NarrativeStats{
+		Narrative: narrative,
+		PeriodStats: domain.PeriodStats{
+			Period:     domain.Period{Since: period.Since.UTC(), Until: period.Until.UTC()},
+			TopDomains: []domain.DomainCount{},
+		},
+		Interval: interval.String(),
+	}
+	domains := make(map[string]domainTally)
+	var confidence float64
+	err = s.predictions.Iterate(ctx, series.Filter, func(p *domain.Prediction) error {
+		createdAt := p.CreatedAt
+		if stats.FirstSeen == nil || createdAt.Before(*stats.FirstSeen) {
+			stats.FirstSeen = &createdAt
+		}
+		if stats.LastSeen == nil || createdAt.After(*stats.LastSeen) {
+			stats.LastSeen = &createdAt
+		}
+		if createdAt.Before(period.Since) || !createdAt.Before(period.Until) {
+			return nil
+		}
+
+		stats.Count++
+		confidence += p.Confidence
+		fake := p.Result == domain.LabelFake
+		if fake {
+			stats.FakeCount++
+		}
+		if source := strings.TrimPrefix(strings.ToLower(p.ArticleSource), "www."); source != "" {
+			t := domains[source]
+			t.count++
+			if fake {
+				t.fake++
+			}
+			domains[source] = t
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load predictions: %w", err)
+	}
+	if stats.Count > 0 {
+		stats.FakeRatio = float64(stats.FakeCount) / float64(stats.Count)
+		stats.AvgConfidence = confidence / float64(stats.Count)
+	}
+	for name, t := range domains {
+		stats.TopDomains = append(stats.TopDomains, domain.DomainCount{Domain: name, Count: t.count, FakeRatio: t.fakeRatio()})
+	}
+	sort.Slice(stats.TopDomains, func(i, j int) bool {
+		if stats.TopDomains[i].Count != stats.TopDomains[j].Count {
+			return stats.TopDomains[i].Count > stats.TopDomains[j].Count
+		}
+		return stats.TopDomains[i].Domain < stats.TopDomains[j].Domain
+	})
+	if len(stats.TopDomains) > top {
+		stats.TopDomains = stats.TopDomains[:top]
+	}
+
+	if stats.Series, err = s.predictions.Aggregate(ctx, series); err != nil {
+		return nil, fmt.Errorf("failed to aggregate predictions: %w", err)
+	}
+	return stats, nil
+}
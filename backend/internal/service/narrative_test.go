@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestNarrativeServiceTagsAndAggregates(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	predictions := memory.NewPredictionRepository()
+	for _, p := range []*domain.Prediction{
+		{ID: "text", RequestType: "text", OriginalContent: "The 5G towers spread the virus, insiders say", Result: domain.LabelFake, Confidence: 0.9, CreatedAt: now.Add(-48 * time.Hour)},
+		{ID: "url", RequestType: "url", OriginalContent: "https://rumors.example.com/a", ArticleTitle: "Why 5G masts are making people sick",
+			ArticleSource: "www.rumors.example.com", Result: domain.LabelFake, Confidence: 0.7, CreatedAt: now.Add(-24 * time.Hour)},
+		{ID: "other", RequestType: "text", OriginalContent: "Rates were left unchanged", Result: domain.LabelReal, Confidence: 0.8, CreatedAt: now.Add(-24 * time.Hour)},
+	} {
+		if err := predictions.CreatePrediction(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc := NewNarrativeService(memory.NewNarrativeRepository(), predictions)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, _, err := svc.Create(ctx, "acme", "alice", domain.Narrative{Name: "bad", Patterns: []string{"(unclosed"}}); !errors.Is(err, domain.ErrInvalidNarrative) {
+		t.Fatalf("Create() with a bad pattern = %v, want ErrInvalidNarrative", err)
+	}
+
+	// Existing history is tagged on creation, case-insensitively.
+	narrative, tagged, err := svc.Create(ctx, "acme", "alice", domain.Narrative{
+		Name:     "5G causes illness",
+		Keywords: []string{" 5g towers "},
+		Patterns: []string{`5G\s+masts?`},
+	})
+	if err != nil || tagged != 2 {
+		t.Fatalf("Create() = %d tagged, %v, want 2", tagged, err)
+	}
+	if p, _ := predictions.GetPredictionByID("url"); !slices.Contains(p.Narratives, narrative.ID) {
+		t.Errorf("url prediction narratives = %v, want the new narrative", p.Narratives)
+	}
+
+	// New predictions are matched on their article text.
+	incoming := &domain.Prediction{ID: "new", Result: domain.LabelReal, Confidence: 0.6, ArticleSource: "news.example.org", CreatedAt: now.Add(-time.Hour)}
+	svc.Tag(ctx, incoming, "Officials deny that 5G MASTS cause any harm.")
+	if !slices.Contains(incoming.Narratives, narrative.ID) {
+		t.Fatalf("Tag() narratives = %v, want the narrative", incoming.Narratives)
+	}
+	if err := predictions.CreatePrediction(incoming); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.Get(ctx, "globex", narrative.ID); !errors.Is(err, domain.ErrNarrativeNotFound) {
+		t.Errorf("Get() from another org = %v, want not found", err)
+	}
+
+	stats, err := svc.Stats(ctx, "acme", narrative.ID, domain.Period{Since: now.Add(-30 * time.Hour), Until: now}, 24*time.Hour, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Count != 2 || stats.FakeCount != 1 || stats.FakeRatio != 0.5 {
+		t.Errorf("stats = %+v, want 2 predictions, half fake", stats.PeriodStats)
+	}
+	if !stats.FirstSeen.Equal(now.Add(-48*time.Hour)) || !stats.LastSeen.Equal(now.Add(-time.Hour)) {
+		t.Errorf("seen %v..%v, want all of history", stats.FirstSeen, stats.LastSeen)
+	}
+	if len(stats.TopDomains) != 2 || stats.TopDomains[0].Domain != "news.example.org" {
+		t.Errorf("top domains = %+v", stats.TopDomains)
+	}
+	if len(stats.Series) == 0 {
+		t.Error("Stats() returned no series")
+	}
+
+	// Narrowing the narrative untags what no longer matches.
+	if _, tagged, err = svc.Update(ctx, "acme", narrative.ID, domain.Narrative{Name: "5G towers", Keywords: []string{"5g towers"}}); err != nil || tagged != 1 {
+		t.Fatalf("Update() = %d tagged, %v, want 1", tagged, err)
+	}
+	if p, _ := predictions.GetPredictionByID("url"); p.Narratives != nil {
+		t.Errorf("url prediction narratives = %v, want none", p.Narratives)
+	}
+
+	if err := svc.Delete(ctx, "acme", narrative.ID); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := predictions.GetPredictionByID("text"); p.Narratives != nil {
+		t.Errorf("text prediction narratives = %v after delete, want none", p.Narratives)
+	}
+}
+
+func TestNarrativeServiceLimit(t *testing.T) {
+	svc := NewNarrativeService(memory.NewNarrativeRepository(), memory.NewPredictionRepository()).WithMaxNarratives(1)
+	ctx := context.Background()
+	if _, _, err := svc.Create(ctx, "acme", "alice", domain.Narrative{Name: "one", Keywords: []string{"a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := svc.Create(ctx, "acme", "alice", domain.Narrative{Name: "two", Keywords: []string{"b"}}); !errors.Is(err, domain.ErrNarrativeLimitReached) {
+		t.Errorf("Create() over the limit = %v, want ErrNarrativeLimitReached", err)
+	}
+	if _, _, err := svc.Create(ctx, "globex", "bob", domain.Narrative{Name: "two", Keywords: []string{"b"}}); err != nil {
+		t.Errorf("Create() for another org = %v, want the limit to be per org", err)
+	}
+}
@@ -1,8 +1,15 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/Naman30903/Final-Year-Project/internal/domain"
 	"github.com/google/uuid"
@@ -10,16 +17,45 @@ import (
 
 // NewsRepository defines the interface for news data storage
 type NewsRepository interface {
-	SavePrediction(prediction *domain.Prediction) error
+	CreatePrediction(prediction *domain.Prediction) error
+	UpdatePrediction(prediction *domain.Prediction) error
 	GetPredictionByID(id string) (*domain.Prediction, error)
 	GetAllPredictions() ([]*domain.Prediction, error)
+	GetPredictionByCanonicalURL(canonicalURL string) (*domain.Prediction, error)
+	Query(ctx context.Context, q domain.PredictionQuery) ([]*domain.Prediction, error)
+	Count(ctx context.Context, q domain.PredictionQuery) (int, error)
+	Iterate(ctx context.Context, q domain.PredictionQuery, fn func(*domain.Prediction) error) error
+	Aggregate(ctx context.Context, q domain.TimeSeriesQuery) ([]domain.TimeSeriesPoint, error)
+	DeletePrediction(id string) error
+	// DeleteUnpinnedPrediction deletes a prediction unless it is pinned at
+	// the time of the delete, returning domain.ErrPredictionPinned if so
+	DeleteUnpinnedPrediction(id string) error
 }
 
-// NewsService handles news analysis business logic
+// NewsService handles news analysis business logic. Its errors wrap the
+// domain package's sentinel errors, so callers match them with errors.Is;
+// any other error is an internal failure.
 type NewsService struct {
 	mlClient   *MLClient
 	scraper    *ScraperService
 	repository NewsRepository
+	truncator  *Truncator
+	slo        *SLOTracker
+	fusion     *VerdictFusion
+	evidence   *EvidenceRetriever
+	orgPolicy  *OrgPolicyService
+	translator *TranslationBridge
+	trusted    *TrustedSources
+	branding   *BrandingService
+	archive    *FileArchive
+	knownFake  *KnownFakeFilter
+	reviews    *ReviewQueue
+	narratives *NarrativeService
+	quotas     *QuotaTracker
+	staleAfter time.Duration
+	background sync.Map   // normalized URL -> struct{}; full analyses behind provisional verdicts
+	refreshing sync.Map   // prediction ID -> struct{}; stale verdicts being refreshed
+	pinMu      sync.Mutex // serializes pin quota checks
 }
 
 // NewNewsService creates a new news service
@@ -28,9 +64,127 @@ func NewNewsService(mlClient *MLClient, scraper *ScraperService, repo NewsReposi
 		mlClient:   mlClient,
 		scraper:    scraper,
 		repository: repo,
+		truncator:  NewTruncator(domain.TruncationNone, DefaultMaxInputChars),
+		fusion:     NewVerdictFusion(),
 	}
 }
 
+// WithVerdictFusion sets how signals are combined into the final verdict.
+func (s *NewsService) WithVerdictFusion(fusion *VerdictFusion) *NewsService {
+	s.fusion = fusion
+	return s
+}
+
+// WithEvidence enables per-claim evidence retrieval for requests with
+// include_evidence.
+func (s *NewsService) WithEvidence(retriever *EvidenceRetriever) *NewsService {
+	s.evidence = retriever
+	return s
+}
+
+// WithOrgPolicy enforces organizations' domain blocklists and allowlists
+// for analyses made with ContextWithOrg.
+func (s *NewsService) WithOrgPolicy(policy *OrgPolicyService) *NewsService {
+	s.orgPolicy = policy
+	return s
+}
+
+// WithTrustedSources answers URL analyses of allowlisted domains without
+// scraping or running the model.
+func (s *NewsService) WithTrustedSources(trusted *TrustedSources) *NewsService {
+	s.trusted = trusted
+	return s
+}
+
+// WithBranding records publisher favicons and logos found while scraping
+// and attaches them to URL predictions as source_info.
+func (s *NewsService) WithBranding(branding *BrandingService) *NewsService {
+	s.branding = branding
+	return s
+}
+
+// WithTranslation translates articles in languages the models don't
+// support before scoring them.
+func (s *NewsService) WithTranslation(bridge *TranslationBridge) *NewsService {
+	s.translator = bridge
+	return s
+}
+
+// WithArchive reads predictions from the archive tier when primary
+// storage holds only their summary, or nothing at all.
+func (s *NewsService) WithArchive(archive *FileArchive) *NewsService {
+	s.archive = archive
+	return s
+}
+
+// WithKnownFakeFilter answers URL requests that allow it with a
+// provisional verdict when the URL is a known fake.
+func (s *NewsService) WithKnownFakeFilter(filter *KnownFakeFilter) *NewsService {
+	s.knownFake = filter
+	return s
+}
+
+// WithReviewQueue sends stored verdicts in the uncertain band to human
+// reviewers.
+func (s *NewsService) WithReviewQueue(queue *ReviewQueue) *NewsService {
+	s.reviews = queue
+	return s
+}
+
+// WithNarratives tags new predictions with the organization narratives
+// they match.
+func (s *NewsService) WithNarratives(narratives *NarrativeService) *NewsService {
+	s.narratives = narratives
+	return s
+}
+
+// WithQuotas charges ML inferences to the request's inference quota (see
+// ContextWithQuota). The scraper meters scrapes against the same tracker.
+func (s *NewsService) WithQuotas(quotas *QuotaTracker) *NewsService {
+	s.quotas = quotas
+	return s
+}
+
+// ReviewQueueStats describes the human review queue, or nil without one.
+func (s *NewsService) ReviewQueueStats() *ReviewQueueStats {
+	return s.reviews.Stats()
+}
+
+// KnownFakeStats describes the known-fake filter, or nil without one.
+func (s *NewsService) KnownFakeStats() *KnownFakeStats {
+	return s.knownFake.Stats()
+}
+
+// WithTruncator sets how long article text is prepared before prediction.
+func (s *NewsService) WithTruncator(truncator *Truncator) *NewsService {
+	s.truncator = truncator
+	return s
+}
+
+// MLStats returns the ML client's fallback counters.
+func (s *NewsService) MLStats() MLStats {
+	return s.mlClient.Stats()
+}
+
+// WithSLOTracker records per-stage latencies for SLO tracking.
+func (s *NewsService) WithSLOTracker(tracker *SLOTracker) *NewsService {
+	s.slo = tracker
+	return s
+}
+
+type ownerKey struct{}
+
+// ContextWithOwner records the authenticated caller an analysis is made for.
+func ContextWithOwner(ctx context.Context, ownerID string) context.Context {
+	return context.WithValue(ctx, ownerKey{}, ownerID)
+}
+
+// OwnerFromContext returns the caller recorded by ContextWithOwner, if any.
+func OwnerFromContext(ctx context.Context) string {
+	owner, _ := ctx.Value(ownerKey{}).(string)
+	return owner
+}
+
 // AnalyzeNews analyzes news article or URL for fake news detection.
 //
 // For URL requests the flow is:
@@ -38,76 +192,649 @@ func NewNewsService(mlClient *MLClient, scraper *ScraperService, repo NewsReposi
 //  2. Extracted text is sent to the ML service POST /predict.
 //  3. If Go scraping fails, fall back to ML service POST /predict/url
 //     (the Python service has its own scraper).
-func (s *NewsService) AnalyzeNews(req *domain.AnalysisRequest) (*domain.Prediction, error) {
+func (s *NewsService) AnalyzeNews(ctx context.Context, req *domain.AnalysisRequest) (prediction *domain.Prediction, err error) {
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
-	var prediction *domain.Prediction
-	var err error
+	start := time.Now()
+	defer func() { s.observe(StageAnalyze, start, err) }()
+
+	if req.Model != "" {
+		ctx = ContextWithModel(ctx, req.Model)
+	}
+	if req.Language != "" {
+		ctx = ContextWithLanguage(ctx, req.Language)
+	}
+
+	if req.Type == "url" && req.AllowProvisional {
+		if provisional := s.provisionalVerdict(ctx, req); provisional != nil {
+			return provisional, nil
+		}
+	}
 
 	switch req.Type {
 	case "text":
-		prediction, err = s.mlClient.Predict(req.Content)
+		prediction, err = s.predictText(ctx, req.Content, req.Truncation, nil)
 		if err != nil {
 			return nil, err
 		}
+		s.fusion.Apply(ctx, &SignalInput{Prediction: prediction, Text: req.Content})
+		s.narratives.Tag(ctx, prediction, req.Content)
+		if req.IncludeSummary {
+			s.attachSummary(ctx, prediction, req.Content)
+		}
+		if req.IncludeEvidence {
+			s.attachEvidence(ctx, prediction, req.Content)
+		}
 
 	case "url":
-		prediction, err = s.analyzeURL(req.Content)
+		prediction, err = s.analyzeURL(ctx, req)
 		if err != nil {
 			return nil, err
 		}
+		if prediction.ID != "" {
+			// Already analyzed under the same canonical URL.
+			return s.recordReuse(ctx, req, prediction), nil
+		}
 
 	default:
 		return nil, domain.ErrInvalidRequestType
 	}
 
 	// Enrich with request metadata.
-	prediction.ID = uuid.New().String()
+	if prediction.Method == "" {
+		prediction.Method = domain.MethodModel
+	}
 	prediction.RequestType = req.Type
 	prediction.OriginalContent = req.Content
 	prediction.CreatedAt = time.Now()
+	prediction.OwnerID = OwnerFromContext(ctx)
+	if prediction.OwnerID == "" {
+		prediction.AnonymousSession = AnonymousSessionFromContext(ctx)
+	}
+	prediction.License = contentLicense(ctx, req)
+	if trace, ok := TraceFromContext(ctx); ok {
+		prediction.RequestID = trace.RequestID
+	}
 
 	// Persist (best-effort).
-	if saveErr := s.repository.SavePrediction(prediction); saveErr != nil {
+	if saveErr := s.createPrediction(prediction); saveErr != nil {
 		fmt.Printf("Warning: failed to save prediction: %v\n", saveErr)
+	} else {
+		s.knownFake.Observe(prediction)
+		s.reviews.Observe(prediction)
 	}
 
 	return prediction, nil
 }
 
+// recordReuse stores a copy of a reused verdict for the caller, so it
+// appears in their own history. The copy keeps the verdict's scoring time
+// and goes stale with it. Callers with no identity get the reused
+// prediction as is.
+func (s *NewsService) recordReuse(ctx context.Context, req *domain.AnalysisRequest, reused *domain.Prediction) *domain.Prediction {
+	record := *reused
+	record.OwnerID = OwnerFromContext(ctx)
+	record.AnonymousSession = ""
+	if record.OwnerID == "" {
+		record.AnonymousSession = AnonymousSessionFromContext(ctx)
+		if record.AnonymousSession == "" {
+			return reused
+		}
+	}
+	scoredAt := reused.ScoredAt()
+	record.RefreshedAt = &scoredAt
+	record.CreatedAt = time.Now()
+	record.OriginalContent = req.Content
+	record.Stale = false
+	record.Pinned, record.PinnedBy = false, ""
+	record.License = contentLicense(ctx, req)
+	record.RequestID = ""
+	if trace, ok := TraceFromContext(ctx); ok {
+		record.RequestID = trace.RequestID
+	}
+	if err := s.createPrediction(&record); err != nil {
+		fmt.Printf("Warning: failed to save reused prediction: %v\n", err)
+		return reused
+	}
+	// The stored record is shared with readers; return a copy.
+	result := record
+	result.Stale = reused.Stale
+	return &result
+}
+
+// backgroundAnalysisTimeout bounds a full analysis started behind a
+// provisional verdict.
+const backgroundAnalysisTimeout = 2 * time.Minute
+
+// provisionalVerdict returns a provisional FAKE verdict for a known-fake
+// URL and starts its full analysis in the background, or returns nil.
+// The full result is stored under the canonical URL, so asking again
+// returns it. Callers whose organization has a rule for the domain, or who
+// are routed to their own model, always get the full analysis.
+func (s *NewsService) provisionalVerdict(ctx context.Context, req *domain.AnalysisRequest) *domain.Prediction {
+	if s.knownFake == nil {
+		return nil
+	}
+	normalized := NormalizeURL(req.Content)
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return nil
+	}
+	if _, tenant := s.orgRule(ctx, u.Hostname()); tenant {
+		return nil
+	}
+	if _, routed := MLRouteFromContext(ctx); routed {
+		return nil
+	}
+	reason, ok := s.knownFake.Match(normalized)
+	if !ok {
+		return nil
+	}
+
+	if _, running := s.background.LoadOrStore(normalized, struct{}{}); !running {
+		full := *req
+		full.AllowProvisional = false
+		go func() {
+			defer s.background.Delete(normalized)
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), backgroundAnalysisTimeout)
+			defer cancel()
+			if _, err := s.AnalyzeNews(ctx, &full); err != nil {
+				log.Printf("Warning: background analysis of %s failed: %v", normalized, err)
+			}
+		}()
+	}
+
+	prediction := knownFakePrediction(normalized, u.Hostname(), reason, s.knownFake.minConfidence)
+	prediction.RequestType = req.Type
+	prediction.OriginalContent = req.Content
+	prediction.CreatedAt = time.Now()
+	return prediction
+}
+
+// maxCreateAttempts bounds retries when a generated ID collides.
+const maxCreateAttempts = 3
+
+// createPrediction assigns a fresh ID and stores the prediction, retrying
+// with a new ID if the repository reports a collision.
+func (s *NewsService) createPrediction(prediction *domain.Prediction) error {
+	var err error
+	for attempt := 0; attempt < maxCreateAttempts; attempt++ {
+		prediction.ID = uuid.New().String()
+		err = s.repository.CreatePrediction(prediction)
+		if !errors.Is(err, domain.ErrAlreadyExists) {
+			return err
+		}
+	}
+	return fmt.Errorf("giving up after %d ID collisions: %w", maxCreateAttempts, err)
+}
+
 // analyzeURL tries the Go scraper first, then falls back to the ML service's
-// own /predict/url endpoint.
-func (s *NewsService) analyzeURL(articleURL string) (*domain.Prediction, error) {
+// own /predict/url endpoint. URLs are normalized and resolved to their
+// canonical form; if that article was already analyzed, the stored
+// prediction is returned instead. Depth 1 requests skip that lookup, since
+// their verdict also depends on the linked articles.
+//
+// Domains on the caller's organization blocklist are flagged without being
+// scraped. Verdicts adjusted by an organization's lists, and requests
+// routed to a partner's own model, are neither taken from nor stored under
+// the shared canonical URL, so other organizations never see them.
+func (s *NewsService) analyzeURL(ctx context.Context, req *domain.AnalysisRequest) (*domain.Prediction, error) {
+	articleURL, truncation := req.Content, req.Truncation
+	normalized := NormalizeURL(articleURL)
+	source := ""
+	if u, err := url.Parse(normalized); err == nil {
+		source = u.Hostname()
+	}
+	rule, tenant := s.orgRule(ctx, source)
+	if tenant && rule.List == domain.DomainListBlock {
+		return blockedPrediction(rule, source), nil
+	}
+	if _, routed := MLRouteFromContext(ctx); routed {
+		tenant = true
+	}
+	var cached *domain.Prediction
+	if !tenant {
+		cached = s.findByCanonicalURL(normalized)
+	}
+	// A pinned model only reuses verdicts from that model
+	if cached != nil && req.Model != "" && cached.ModelVersion != req.Model {
+		cached = nil
+	}
+	if cached != nil && req.Depth == 0 && (cached.Summary != "" || !req.IncludeSummary) &&
+		(cached.Claims != nil || !s.wantsEvidence(req)) {
+		return s.reuse(req, cached, req.IncludeSummary, s.wantsEvidence(req)), nil
+	}
+	// Summaries, evidence and related articles need the article text, so
+	// only plain verdicts take the trusted source shortcut.
+	if trusted, ok := s.trusted.Match(source); ok && req.Depth == 0 && !req.IncludeSummary && !s.wantsEvidence(req) {
+		prediction := trustedPrediction(trusted, source)
+		prediction.SourceInfo = s.branding.Info(source)
+		if !tenant {
+			prediction.CanonicalURL = normalized
+		}
+		return prediction, nil
+	}
+
 	// ── primary: scrape locally then send text ──
-	scrapeResult, scrapeErr := s.scraper.ScrapeArticle(articleURL)
+	scrapeStart := time.Now()
+	scrapeResult, scrapeErr := s.scraper.ScrapeArticle(ctx, articleURL)
+	s.observe(StageScrape, scrapeStart, scrapeErr)
 	if scrapeErr == nil {
-		prediction, err := s.mlClient.Predict(scrapeResult.Text)
+		if rule, ok := s.orgRule(ctx, scrapeResult.Source); ok {
+			if rule.List == domain.DomainListBlock {
+				return blockedPrediction(rule, scrapeResult.Source), nil
+			}
+			tenant = true
+		}
+		if existing := s.findByCanonicalURL(scrapeResult.Canonical); existing != nil && req.Depth == 0 && !tenant &&
+			(req.Model == "" || existing.ModelVersion == req.Model) {
+			summaryStored := req.IncludeSummary && existing.Summary != ""
+			evidenceStored := s.wantsEvidence(req) && existing.Claims != nil
+
+			// Cache the summary and evidence on the stored prediction. The
+			// stored pointer is shared with readers, so work on a copy.
+			enriched := *existing
+			updated := false
+			if req.IncludeSummary && enriched.Summary == "" {
+				updated = s.attachSummary(ctx, &enriched, scrapeResult.Text)
+			}
+			if enriched.Claims == nil && s.wantsEvidence(req) {
+				updated = s.attachEvidence(ctx, &enriched, scrapeResult.Text) || updated
+			}
+			if updated {
+				if err := s.saveEnrichment(existing.ID, &enriched); err != nil {
+					fmt.Printf("Warning: failed to save summary or evidence: %v\n", err)
+				}
+			}
+			return s.reuse(req, &enriched, summaryStored, evidenceStored), nil
+		}
+		var related []RelatedArticle
+		if req.Depth > 0 {
+			related = s.scraper.ScrapeRelated(ctx, scrapeResult.Links)
+		}
+		leads := make([]string, 0, len(related))
+		for _, r := range related {
+			leads = append(leads, r.Lead)
+		}
+		prediction, err := s.predictText(ctx, scrapeResult.Text, truncation, leads)
 		if err != nil {
 			return nil, err
 		}
+		for _, r := range related {
+			prediction.RelatedArticles = append(prediction.RelatedArticles, r.URL)
+		}
 		// Attach metadata from the scraper.
+		provenance := provenanceOf(prediction)
+		provenance.ScraperVersion = ScraperVersion
+		provenance.Extractor = scrapeResult.Extractor
+		provenance.Cleaning = append(append([]string(nil), scraperCleaning...), provenance.Cleaning...)
+		if !tenant {
+			prediction.CanonicalURL = scrapeResult.Canonical
+		}
 		prediction.ArticleTitle = scrapeResult.Title
 		prediction.ArticleDescription = scrapeResult.Description
 		prediction.ArticleAuthor = scrapeResult.Author
 		prediction.ArticleSource = scrapeResult.Source
+		prediction.ArticlePublishedAt = scrapeResult.PublishedAt
+		prediction.ContentType = scrapeResult.ContentType
+		prediction.SourceInfo = s.branding.Record(ctx, scrapeResult)
+		prediction.ScrapeDiagnostics = scrapeResult.Diagnostics
+		s.fusion.Apply(ctx, &SignalInput{
+			Prediction:  prediction,
+			Text:        scrapeResult.Text,
+			Source:      scrapeResult.Source,
+			PublishedAt: scrapeResult.PublishedAt,
+		})
+		s.narratives.Tag(ctx, prediction, scrapeResult.Text)
+		if req.IncludeSummary {
+			s.attachSummary(ctx, prediction, scrapeResult.Text)
+		}
+		if req.IncludeEvidence {
+			s.attachEvidence(ctx, prediction, scrapeResult.Text)
+		}
 		return prediction, nil
 	}
 
+	// Pages that are not articles at all, and URLs rejected by the scraper's
+	// network policy, must not be forwarded to the ML service's scraper
+	// either, nor may a caller out of scrape quota have it scrape for them.
+	if errors.Is(scrapeErr, domain.ErrUnsupportedContentType) || errors.Is(scrapeErr, domain.ErrNotAnArticle) ||
+		errors.Is(scrapeErr, domain.ErrInvalidURL) || errors.Is(scrapeErr, domain.ErrQuotaExceeded) {
+		return nil, scrapeErr
+	}
+
+	// Without article text no summary can be added; keep the stored verdict.
+	if cached != nil && req.Depth == 0 {
+		return s.reuse(req, cached, false, false), nil
+	}
+
 	// ── fallback: let the ML service scrape ──
 	fmt.Printf("Go scraper failed (%v), falling back to ML /predict/url\n", scrapeErr)
-	prediction, err := s.mlClient.PredictURL(articleURL)
+	if err := s.quotas.Consume(ctx, domain.QuotaInferences, 1); err != nil {
+		return nil, err
+	}
+	prediction, err := s.mlClient.PredictURL(ctx, articleURL)
 	if err != nil {
 		// Return the original scrape error — it's more descriptive.
 		return nil, fmt.Errorf("%w (ML fallback also failed: %v)", scrapeErr, err)
 	}
+	if !tenant {
+		prediction.CanonicalURL = normalized
+	}
+	provenanceOf(prediction).Extractor = ExtractorMLService
+	prediction.ScrapeDiagnostics = ScrapeDiagnosticsOf(scrapeErr)
+	s.fusion.Apply(ctx, &SignalInput{Prediction: prediction, Source: source})
+	s.narratives.Tag(ctx, prediction, "")
 	return prediction, nil
 }
 
-// GetPrediction retrieves a prediction by ID
+// ExtractorMLService is the provenance extractor of articles the ML
+// service scraped itself.
+const ExtractorMLService = "ml-service"
+
+// reusedPrediction returns a copy of a stored prediction whose provenance
+// records what the request reused from it. The stored prediction is left
+// untouched.
+func reusedPrediction(stored *domain.Prediction, summary, evidence bool) *domain.Prediction {
+	reused := *stored
+	reused.Provenance = stored.Provenance.WithCacheHit(domain.CacheStoredPrediction)
+	if summary {
+		reused.Provenance = reused.Provenance.WithCacheHit(domain.CacheStoredSummary)
+	}
+	if evidence {
+		reused.Provenance = reused.Provenance.WithCacheHit(domain.CacheStoredEvidence)
+	}
+	return &reused
+}
+
+// provenanceOf returns p's provenance, adding an empty one if needed.
+func provenanceOf(p *domain.Prediction) *domain.Provenance {
+	if p.Provenance == nil {
+		p.Provenance = &domain.Provenance{}
+	}
+	return p.Provenance
+}
+
+// orgRule returns the rule the caller's organization has for host.
+func (s *NewsService) orgRule(ctx context.Context, host string) (*domain.OrgDomainRule, bool) {
+	if s.orgPolicy == nil {
+		return nil, false
+	}
+	return s.orgPolicy.Match(ctx, OrgFromContext(ctx), host)
+}
+
+// attachSummary generates a summary for the prediction. Summaries are
+// best-effort: a failure leaves the verdict intact and reports false.
+func (s *NewsService) attachSummary(ctx context.Context, prediction *domain.Prediction, text string) bool {
+	summary, err := s.mlClient.Summarize(ctx, text)
+	if err != nil || summary == "" {
+		fmt.Printf("Warning: failed to summarize article: %v\n", err)
+		return false
+	}
+	prediction.Summary = summary
+	return true
+}
+
+// saveEnrichment stores a summary and evidence generated for a stored
+// prediction. It re-reads the prediction, so a pin or review made while
+// they were generated is kept.
+func (s *NewsService) saveEnrichment(id string, enriched *domain.Prediction) error {
+	latest, err := s.repository.GetPredictionByID(id)
+	if err != nil {
+		return err
+	}
+	updated := *latest
+	if updated.Summary == "" {
+		updated.Summary = enriched.Summary
+	}
+	if updated.Claims == nil {
+		updated.Claims = enriched.Claims
+	}
+	return s.repository.UpdatePrediction(&updated)
+}
+
+// wantsEvidence reports whether the request asks for evidence this
+// service can retrieve.
+func (s *NewsService) wantsEvidence(req *domain.AnalysisRequest) bool {
+	return req.IncludeEvidence && s.evidence != nil
+}
+
+// attachEvidence retrieves evidence for the article's claims. Articles
+// without check-worthy claims get an empty list, so they are not
+// searched again; it reports false when retrieval is disabled.
+func (s *NewsService) attachEvidence(ctx context.Context, prediction *domain.Prediction, text string) bool {
+	if s.evidence == nil {
+		return false
+	}
+	prediction.Claims = s.evidence.Retrieve(ctx, text, prediction.CanonicalURL)
+	if prediction.Claims == nil {
+		prediction.Claims = []domain.Claim{}
+	}
+	return true
+}
+
+// observe reports a stage outcome to the SLO tracker. Errors caused by the
+// caller's input are not held against the service's objectives.
+func (s *NewsService) observe(stage string, start time.Time, err error) {
+	if s.slo == nil || isClientError(err) {
+		return
+	}
+	s.slo.Observe(stage, time.Since(start), err == nil)
+}
+
+// isClientError reports whether err was caused by the request itself.
+func isClientError(err error) bool {
+	for _, target := range []error{
+		domain.ErrInvalidRequestType, domain.ErrEmptyContent, domain.ErrInvalidURL,
+		domain.ErrInvalidTruncation, domain.ErrInvalidDepth, domain.ErrUnsupportedContentType, domain.ErrNotAnArticle,
+		domain.ErrInvalidLanguage,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// findByCanonicalURL returns a stored prediction for the URL, or nil.
+// Archived predictions are too old to reuse, and a partner model's
+// verdicts belong to that partner.
+func (s *NewsService) findByCanonicalURL(canonicalURL string) *domain.Prediction {
+	existing, err := s.repository.GetPredictionByCanonicalURL(canonicalURL)
+	if err != nil || existing.Archived || existing.ModelRoute != "" {
+		return nil
+	}
+	return existing
+}
+
+// predictText applies the truncation strategy and sends the resulting
+// piece(s) to the ML service. Chunked text is scored chunk by chunk and the
+// probabilities are averaged, weighted by chunk length. Related-article
+// leads, if any, accompany every request.
+func (s *NewsService) predictText(ctx context.Context, text, truncation string, related []string) (prediction *domain.Prediction, err error) {
+	inputChars := utf8.RuneCountInString(text)
+	var translatedFrom string
+	if s.translator != nil {
+		translated, lang, err := s.translator.Prepare(ctx, text)
+		if err != nil {
+			fmt.Printf("Warning: %v; scoring the original text\n", err)
+		}
+		text, translatedFrom = translated, lang
+	}
+
+	strategy := s.truncator.Resolve(truncation)
+	pieces := s.truncator.Apply(text, strategy)
+	// Every piece is one ML request
+	if err := s.quotas.Consume(ctx, domain.QuotaInferences, len(pieces)); err != nil {
+		return nil, err
+	}
+
+	mlStart := time.Now()
+	defer func() { s.observe(StageML, mlStart, err) }()
+
+	if len(pieces) == 1 {
+		p, err := s.mlClient.PredictWithRelated(ctx, pieces[0], related)
+		if err != nil {
+			return nil, err
+		}
+		prediction = p
+	} else {
+		p, err := s.predictChunks(ctx, pieces, related)
+		if err != nil {
+			return nil, err
+		}
+		prediction = p
+		prediction.ChunkCount = len(pieces)
+	}
+
+	prediction.TruncationStrategy = strategy
+	prediction.InputChars = inputChars
+	provenance := provenanceOf(prediction)
+	provenance.Truncation = strategy
+	if translatedFrom != "" {
+		provenance.Cleaning = append(provenance.Cleaning, "translate-from-"+translatedFrom)
+		prediction.TranslatedFrom = translatedFrom
+		prediction.ConfidencePenalty = s.translator.penalty
+		applyTranslationPenalty(prediction)
+	}
+	return prediction, nil
+}
+
+func (s *NewsService) predictChunks(ctx context.Context, chunks, related []string) (*domain.Prediction, error) {
+	startTime := time.Now()
+
+	var fake, real, total float64
+	var modelVersion, modelRoute, fallbackModel, mlRequestID string
+	var provenance *domain.Provenance
+	// Chunk variances are averaged like the probabilities, and only when
+	// every chunk reported one
+	var variance float64
+	samples, uncertain := 0, true
+	for _, chunk := range chunks {
+		p, err := s.mlClient.PredictWithRelated(ctx, chunk, related)
+		if err != nil {
+			return nil, err
+		}
+		weight := float64(len(chunk))
+		fake += p.FakeProbability * weight
+		real += p.RealProbability * weight
+		total += weight
+		if p.Uncertainty != nil {
+			variance += p.Uncertainty.Variance * weight
+			samples = p.Uncertainty.Samples
+		} else {
+			uncertain = false
+		}
+		modelVersion = p.ModelVersion
+		modelRoute = p.ModelRoute
+		mlRequestID = p.MLRequestID
+		provenance = p.Provenance
+		if p.FallbackModel != "" {
+			fallbackModel = p.FallbackModel
+		}
+	}
+
+	prediction := &domain.Prediction{
+		FakeProbability: fake / total,
+		RealProbability: real / total,
+		ModelVersion:    modelVersion,
+		ModelRoute:      modelRoute,
+		FallbackModel:   fallbackModel,
+		MLRequestID:     mlRequestID,
+		Provenance:      provenance,
+		CreatedAt:       time.Now(),
+	}
+	if uncertain {
+		prediction.Uncertainty = domain.NewUncertainty(prediction.FakeProbability, variance/total, samples)
+	}
+	if prediction.FakeProbability > prediction.RealProbability {
+		prediction.Result = "FAKE"
+		prediction.Confidence = prediction.FakeProbability
+	} else {
+		prediction.Result = "REAL"
+		prediction.Confidence = prediction.RealProbability
+	}
+	prediction.ProcessingTime = time.Since(startTime).Milliseconds()
+	return prediction, nil
+}
+
+// Rescore re-runs a stored prediction's input through the ML service,
+// optionally against a specific model version. The stored prediction is
+// left untouched; the fresh result is returned for comparison.
+func (s *NewsService) Rescore(ctx context.Context, id, model string) (*domain.Prediction, error) {
+	previous, err := s.GetPrediction(id)
+	if err != nil {
+		return nil, err
+	}
+	if model != "" {
+		ctx = ContextWithModel(ctx, model)
+	}
+
+	var rescored *domain.Prediction
+	switch previous.RequestType {
+	case "text":
+		rescored, err = s.predictText(ctx, previous.OriginalContent, previous.TruncationStrategy, nil)
+	case "url":
+		scrapeResult, scrapeErr := s.scraper.ScrapeArticle(ctx, previous.OriginalContent)
+		if scrapeErr == nil {
+			rescored, err = s.predictText(ctx, scrapeResult.Text, previous.TruncationStrategy, nil)
+		} else {
+			rescored, err = s.mlClient.PredictURL(ctx, previous.OriginalContent)
+		}
+	default:
+		return nil, domain.ErrInvalidRequestType
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rescored.ID = previous.ID
+	rescored.RequestType = previous.RequestType
+	rescored.OriginalContent = previous.OriginalContent
+	rescored.CanonicalURL = previous.CanonicalURL
+	return rescored, nil
+}
+
+// Classify runs text through the ML service without storing a
+// prediction. Used for benchmark runs.
+func (s *NewsService) Classify(ctx context.Context, text string) (*domain.Prediction, error) {
+	return s.predictText(ctx, text, "", nil)
+}
+
+// Probe runs text through the same model and verdict signals as a text
+// analysis without storing a prediction. Used by the canary.
+func (s *NewsService) Probe(ctx context.Context, text string) (*domain.Prediction, error) {
+	prediction, err := s.predictText(ctx, text, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	s.fusion.Apply(ctx, &SignalInput{Prediction: prediction, Text: text})
+	return prediction, nil
+}
+
+// GetPrediction retrieves a prediction by ID. Archived predictions are
+// read back from the archive tier, keeping the pin and review recorded on
+// their summary row.
 func (s *NewsService) GetPrediction(id string) (*domain.Prediction, error) {
-	return s.repository.GetPredictionByID(id)
+	prediction, err := s.repository.GetPredictionByID(id)
+	if s.archive == nil || (err == nil && !prediction.Archived) || (err != nil && !errors.Is(err, domain.ErrPredictionNotFound)) {
+		return prediction, err
+	}
+	full, archiveErr := s.archive.Get(id)
+	if archiveErr != nil {
+		if err == nil {
+			log.Printf("Warning: archived prediction %s unavailable, serving its summary: %v", id, archiveErr)
+			return prediction, nil
+		}
+		return nil, err
+	}
+	if prediction != nil {
+		full.Review, full.Pinned, full.PinnedBy = prediction.Review, prediction.Pinned, prediction.PinnedBy
+	}
+	full.Archived = true
+	return full, nil
 }
 
 // GetHistory retrieves all prediction history
@@ -115,7 +842,188 @@ func (s *NewsService) GetHistory() ([]*domain.Prediction, error) {
 	return s.repository.GetAllPredictions()
 }
 
+// QueryHistory retrieves prediction history matching the query
+func (s *NewsService) QueryHistory(ctx context.Context, q *domain.PredictionQuery) ([]*domain.Prediction, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repository.Query(ctx, *q)
+}
+
+// QueryHistoryPage retrieves one page of the predictions matching q, with
+// where it sits among all of them.
+func (s *NewsService) QueryHistoryPage(ctx context.Context, q *domain.PredictionQuery) ([]*domain.Prediction, domain.PageInfo, error) {
+	predictions, err := s.QueryHistory(ctx, q)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	// A short first page holds every match, so it needs no count
+	total := len(predictions)
+	if q.Offset > 0 || (q.Limit > 0 && total == q.Limit) {
+		if total, err = s.repository.Count(ctx, *q); err != nil {
+			return nil, domain.PageInfo{}, err
+		}
+	}
+	return predictions, domain.NewPageInfo(total, q.Limit, q.Offset, len(predictions)), nil
+}
+
+// StreamHistory calls fn with each prediction matching q without loading
+// the whole result, for exports too large to buffer.
+func (s *NewsService) StreamHistory(ctx context.Context, q *domain.PredictionQuery, fn func(*domain.Prediction) error) error {
+	if err := q.Validate(); err != nil {
+		return err
+	}
+	return s.repository.Iterate(ctx, *q, fn)
+}
+
+// TimeSeries returns bucketed prediction statistics for charting
+func (s *NewsService) TimeSeries(ctx context.Context, q *domain.TimeSeriesQuery) ([]domain.TimeSeriesPoint, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	if err := q.Filter.Validate(); err != nil {
+		return nil, err
+	}
+	if q.Metric == domain.MetricAvgUncertainty {
+		// Buckets count only the predictions that report uncertainty
+		filtered := *q
+		filtered.Filter.HasUncertainty = true
+		return s.repository.Aggregate(ctx, filtered)
+	}
+	return s.repository.Aggregate(ctx, *q)
+}
+
+// PinPrediction pins a prediction on behalf of owner so the retention
+// janitor keeps it. Pinning counts against the owner's plan quota;
+// re-pinning one's own prediction is a no-op.
+func (s *NewsService) PinPrediction(ctx context.Context, id, owner, plan string) (*domain.Prediction, error) {
+	s.pinMu.Lock()
+	defer s.pinMu.Unlock()
+
+	existing, err := s.repository.GetPredictionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Pinned {
+		if existing.PinnedBy != owner {
+			return nil, domain.ErrPinnedByOther
+		}
+		return existing, nil
+	}
+
+	if limit := domain.PinLimit(plan); limit > 0 {
+		q := domain.PredictionQuery{PinnedBy: owner}
+		pinned, err := s.repository.Query(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		if len(pinned) >= limit {
+			return nil, fmt.Errorf("%w (%d)", domain.ErrPinLimitReached, limit)
+		}
+	}
+
+	updated := *existing
+	updated.Pinned = true
+	updated.PinnedBy = owner
+	if err := s.repository.UpdatePrediction(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// ReviewPrediction records a human fact-checker's verdict on a
+// prediction, replacing any earlier review. URL predictions default the
+// claim to the article title. A non-empty version must match the
+// prediction's current Version, so a review is never attached to a
+// revision the reviewer did not see.
+func (s *NewsService) ReviewPrediction(ctx context.Context, id, version string, review domain.Review) (*domain.Prediction, error) {
+	existing, err := s.repository.GetPredictionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if current := existing.Version(); version != "" && version != current {
+		return existing, fmt.Errorf("%w: current version is %s", domain.ErrVersionMismatch, current)
+	}
+	if strings.TrimSpace(review.Claim) == "" {
+		review.Claim = existing.ArticleTitle
+	}
+	if err := review.Validate(); err != nil {
+		return nil, err
+	}
+	review.ReviewedAt = time.Now().UTC()
+
+	updated := *existing
+	updated.Review = &review
+	if err := s.repository.UpdatePrediction(&updated); err != nil {
+		return nil, err
+	}
+	s.reviews.Resolved(&updated)
+	return &updated, nil
+}
+
+// RemoveReview withdraws a prediction's review, unpublishing its
+// ClaimReview.
+func (s *NewsService) RemoveReview(ctx context.Context, id string) (*domain.Prediction, error) {
+	existing, err := s.repository.GetPredictionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	updated := *existing
+	updated.Review = nil
+	if err := s.repository.UpdatePrediction(&updated); err != nil {
+		return nil, err
+	}
+	s.reviews.Observe(&updated)
+	return &updated, nil
+}
+
+// PinUsage returns how many predictions owner has pinned and the plan's
+// pin quota (0 = unlimited).
+func (s *NewsService) PinUsage(ctx context.Context, owner, plan string) (used, limit int, err error) {
+	pinned, err := s.repository.Query(ctx, domain.PredictionQuery{PinnedBy: owner})
+	if err != nil {
+		return 0, 0, err
+	}
+	return len(pinned), domain.PinLimit(plan), nil
+}
+
+// UnpinPrediction removes owner's pin, making the prediction subject to
+// retention again.
+func (s *NewsService) UnpinPrediction(ctx context.Context, id, owner string) (*domain.Prediction, error) {
+	s.pinMu.Lock()
+	defer s.pinMu.Unlock()
+
+	existing, err := s.repository.GetPredictionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !existing.Pinned {
+		return existing, nil
+	}
+	if existing.PinnedBy != owner {
+		return nil, domain.ErrPinnedByOther
+	}
+
+	updated := *existing
+	updated.Pinned = false
+	updated.PinnedBy = ""
+	if err := s.repository.UpdatePrediction(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
 // CheckMLHealth checks if ML service is available
 func (s *NewsService) CheckMLHealth() error {
 	return s.mlClient.HealthCheck()
 }
+
+// CheckScraperHealth runs the scraper self-check
+func (s *NewsService) CheckScraperHealth(ctx context.Context) ScraperHealth {
+	return s.scraper.SelfCheck(ctx)
+}
+
+// ScraperStats returns the scraper's running counters
+func (s *NewsService) ScraperStats() ScraperStats {
+	return s.scraper.Stats()
+}
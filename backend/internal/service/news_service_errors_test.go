@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestNewsServiceErrorsWrapSentinels(t *testing.T) {
+	var mlResponse func(w http.ResponseWriter)
+	ml := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { mlResponse(w) }))
+	defer ml.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	repo := memory.NewPredictionRepository()
+	svc := NewNewsService(NewMLClient(ml.URL), NewScraperService(), repo)
+	ctx := context.Background()
+	text := &domain.AnalysisRequest{Type: "text", Content: "The council approved the budget on Tuesday."}
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"invalid type", func() error {
+			_, err := svc.AnalyzeNews(ctx, &domain.AnalysisRequest{Type: "audio", Content: "x"})
+			return err
+		}, domain.ErrInvalidRequestType},
+		{"empty content", func() error {
+			_, err := svc.AnalyzeNews(ctx, &domain.AnalysisRequest{Type: "text"})
+			return err
+		}, domain.ErrEmptyContent},
+		{"invalid url", func() error {
+			_, err := svc.AnalyzeNews(ctx, &domain.AnalysisRequest{Type: "url", Content: "ftp://example.com/a"})
+			return err
+		}, domain.ErrInvalidURL},
+		{"ml error status", func() error {
+			mlResponse = func(w http.ResponseWriter) { http.Error(w, "model crashed", http.StatusInternalServerError) }
+			_, err := svc.AnalyzeNews(ctx, text)
+			return err
+		}, domain.ErrPredictionFailed},
+		{"ml malformed response", func() error {
+			mlResponse = func(w http.ResponseWriter) { w.Write([]byte("<html>proxy error</html>")) }
+			_, err := svc.AnalyzeNews(ctx, text)
+			return err
+		}, domain.ErrPredictionFailed},
+		{"ml unreachable", func() error {
+			_, err := NewNewsService(NewMLClient(down.URL), NewScraperService(), repo).AnalyzeNews(ctx, text)
+			return err
+		}, domain.ErrMLServiceUnavailable},
+		{"unknown prediction", func() error {
+			_, err := svc.GetPrediction("missing")
+			return err
+		}, domain.ErrPredictionNotFound},
+		{"pin unknown prediction", func() error {
+			_, err := svc.PinPrediction(ctx, "missing", "u1", "")
+			return err
+		}, domain.ErrPredictionNotFound},
+		{"invalid history query", func() error {
+			_, err := svc.QueryHistory(ctx, &domain.PredictionQuery{Limit: -1})
+			return err
+		}, domain.ErrInvalidQuery},
+	}
+	for _, tt := range tests {
+		err := tt.call()
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want it to wrap %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/google/uuid"
+)
+
+// OAuthIdentity is the account a social sign-in was completed with.
+type OAuthIdentity struct {
+	Subject       string // the provider's stable account ID
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OAuthProvider is a social identity provider users can sign in with
+// instead of having an account created for them.
+type OAuthProvider interface {
+	// Name is the provider's path segment, e.g. "google"
+	Name() string
+	// AuthCodeURL returns the provider's consent page for a sign-in
+	AuthCodeURL(state, challenge, redirectURL string) string
+	// Identity redeems an authorization code and returns the account it
+	// was issued for
+	Identity(ctx context.Context, client *http.Client, code, verifier, redirectURL string) (*OAuthIdentity, error)
+}
+
+// GoogleProvider signs users in with their Google account. The endpoint
+// fields default to Google's and are overridable for tests.
+type GoogleProvider struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+}
+
+// NewGoogleProvider creates a Google provider for an OAuth client.
+func NewGoogleProvider(clientID, clientSecret string) *GoogleProvider {
+	return &GoogleProvider{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AuthURL:      "https://accounts.google.com/o/oauth2/v2/auth",
+		TokenURL:     "https://oauth2.googleapis.com/token",
+		UserInfoURL:  "https://openidconnect.googleapis.com/v1/userinfo",
+	}
+}
+
+// Name implements OAuthProvider
+func (p *GoogleProvider) Name() string { return "google" }
+
+// AuthCodeURL implements OAuthProvider
+func (p *GoogleProvider) AuthCodeURL(state, challenge, redirectURL string) string {
+	return authCodeURL(p.AuthURL, p.ClientID, "openid email profile", state, challenge, redirectURL)
+}
+
+// Identity implements OAuthProvider. The profile comes from the userinfo
+// endpoint with the access token, so no ID token needs verifying.
+func (p *GoogleProvider) Identity(ctx context.Context, client *http.Client, code, verifier, redirectURL string) (*OAuthIdentity, error) {
+	token, err := redeemCode(ctx, client, p.TokenURL, p.ClientID, p.ClientSecret, code, verifier, redirectURL)
+	if err != nil {
+		return nil, err
+	}
+	var info struct {
+		Subject       string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := getWithToken(ctx, client, p.UserInfoURL, token, &info); err != nil {
+		return nil, fmt.Errorf("%w: google userinfo: %v", domain.ErrOAuthFailed, err)
+	}
+	return &OAuthIdentity{Subject: info.Subject, Email: info.Email, EmailVerified: info.EmailVerified, Name: info.Name}, nil
+}
+
+// GitHubProvider signs users in with their GitHub account. The endpoint
+// fields default to GitHub's and are overridable for tests.
+type GitHubProvider struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+	APIURL       string
+}
+
+// NewGitHubProvider creates a GitHub provider for an OAuth app.
+func NewGitHubProvider(clientID, clientSecret string) *GitHubProvider {
+	return &GitHubProvider{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AuthURL:      "https://github.com/login/oauth/authorize",
+		TokenURL:     "https://github.com/login/oauth/access_token",
+		APIURL:       "https://api.github.com",
+	}
+}
+
+// Name implements OAuthProvider
+func (p *GitHubProvider) Name() string { return "github" }
+
+// AuthCodeURL implements OAuthProvider
+func (p *GitHubProvider) AuthCodeURL(state, challenge, redirectURL string) string {
+	return authCodeURL(p.AuthURL, p.ClientID, "read:user user:email", state, challenge, redirectURL)
+}
+
+// Identity implements OAuthProvider. The profile email may be hidden or
+// unverified, so the primary verified address is read from /user/emails.
+func (p *GitHubProvider) Identity(ctx context.Context, client *http.Client, code, verifier, redirectURL string) (*OAuthIdentity, error) {
+	token, err := redeemCode(ctx, client, p.TokenURL, p.ClientID, p.ClientSecret, code, verifier, redirectURL)
+	if err != nil {
+		return nil, err
+	}
+	api := strings.TrimRight(p.APIURL, "/")
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := getWithToken(ctx, client, api+"/user", token, &user); err != nil {
+		return nil, fmt.Errorf("%w: github user: %v", domain.ErrOAuthFailed, err)
+	}
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getWithToken(ctx, client, api+"/user/emails", token, &emails); err != nil {
+		return nil, fmt.Errorf("%w: github emails: %v", domain.ErrOAuthFailed, err)
+	}
+	identity := &OAuthIdentity{Name: user.Name}
+	if user.ID != 0 {
+		identity.Subject = strconv.FormatInt(user.ID, 10)
+	}
+	if identity.Name == "" {
+		identity.Name = user.Login
+	}
+	for _, e := range emails {
+		if e.Primary {
+			identity.Email, identity.EmailVerified = e.Email, e.Verified
+		}
+	}
+	return identity, nil
+}
+
+// oauthState round-trips through the provider, like ssoState.
+type oauthState struct {
+	Provider  string    `json:"provider"`
+	Nonce     string    `json:"nonce"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+// OAuthService signs users in with a social account, creating a member
+// account without an organization on first sign-in. It shares users and
+// sessions with single sign-on.
+type OAuthService struct {
+	providers      map[string]OAuthProvider
+	users          *UserService
+	sessions       *SessionTokens
+	baseURL        string
+	allowedDomains []string
+	httpClient     *http.Client
+	now            func() time.Time
+}
+
+// NewOAuthService creates a social sign-in service. baseURL is the public
+// origin; each provider's redirect URI is
+// {baseURL}/api/auth/oauth/{provider}/callback.
+func NewOAuthService(users *UserService, sessions *SessionTokens, baseURL string) *OAuthService {
+	return &OAuthService{
+		providers:  make(map[string]OAuthProvider),
+		users:      users,
+		sessions:   sessions,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		now:        time.Now,
+	}
+}
+
+// WithProvider enables sign-in with a provider.
+func (s *OAuthService) WithProvider(provider OAuthProvider) *OAuthService {
+	s.providers[provider.Name()] = provider
+	return s
+}
+
+// WithAllowedDomains restricts sign-in to email addresses in the domains
+// or their subdomains, e.g. a university's.
+func (s *OAuthService) WithAllowedDomains(domains []string) *OAuthService {
+	s.allowedDomains = domains
+	return s
+}
+
+// WithOutboundAudit records token and profile requests to the providers
+// in audit.
+func (s *OAuthService) WithOutboundAudit(audit *OutboundAudit) *OAuthService {
+	audit.Client(s.httpClient, PurposeSSO)
+	return s
+}
+
+// Providers returns the names of the enabled providers, sorted.
+func (s *OAuthService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RedirectURL returns the callback URL to register with a provider.
+func (s *OAuthService) RedirectURL(provider string) string {
+	return s.baseURL + "/api/auth/oauth/" + provider + "/callback"
+}
+
+// LoginURL starts a sign-in with a provider. It returns the provider URL
+// to send the browser to and the state the callback must present.
+func (s *OAuthService) LoginURL(ctx context.Context, name string) (string, string, error) {
+	provider, ok := s.providers[strings.ToLower(name)]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", domain.ErrUnknownOAuthProvider, name)
+	}
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", "", err
+	}
+	st := oauthState{
+		Provider:  provider.Name(),
+		Nonce:     base64.RawURLEncoding.EncodeToString(nonce),
+		ExpiresAt: s.now().Add(ssoStateTTL).UTC(),
+	}
+	state, err := s.sessions.seal("oauth-state", st)
+	if err != nil {
+		return "", "", err
+	}
+	challenge := sha256.Sum256([]byte(s.pkceVerifier(st.Nonce)))
+	return provider.AuthCodeURL(state, base64.RawURLEncoding.EncodeToString(challenge[:]), s.RedirectURL(provider.Name())), state, nil
+}
+
+// Callback completes a sign-in: it redeems the authorization code,
+// provisions or updates the user and issues a session.
+func (s *OAuthService) Callback(ctx context.Context, name, state, code string) (*SSOLogin, error) {
+	provider, ok := s.providers[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownOAuthProvider, name)
+	}
+	var st oauthState
+	if err := s.sessions.open("oauth-state", state, &st); err != nil || st.Provider != provider.Name() {
+		return nil, fmt.Errorf("%w: invalid state", domain.ErrOAuthFailed)
+	}
+	if !s.now().Before(st.ExpiresAt) {
+		return nil, fmt.Errorf("%w: sign-in took too long, please retry", domain.ErrOAuthFailed)
+	}
+	if code == "" {
+		return nil, fmt.Errorf("%w: missing authorization code", domain.ErrOAuthFailed)
+	}
+
+	identity, err := provider.Identity(ctx, s.httpClient, code, s.pkceVerifier(st.Nonce), s.RedirectURL(provider.Name()))
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.provision(ctx, provider.Name(), identity)
+	if err != nil {
+		return nil, err
+	}
+	token, session, err := s.sessions.Issue(Session{
+		UserID: user.ID,
+		OrgID:  user.OrgID,
+		Role:   user.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SSOLogin{User: user, Token: token, Session: session}, nil
+}
+
+func (s *OAuthService) pkceVerifier(nonce string) string {
+	return s.sessions.derive("oauth-pkce", nonce)
+}
+
+// provision creates the user on first sign-in and refreshes their profile
+// on later ones. Unlike single sign-on, the role is not managed by the
+// provider, so a promoted user keeps their role.
+func (s *OAuthService) provision(ctx context.Context, provider string, identity *OAuthIdentity) (*domain.User, error) {
+	email := strings.ToLower(strings.TrimSpace(identity.Email))
+	switch {
+	case identity.Subject == "":
+		return nil, fmt.Errorf("%w: %s returned no account ID", domain.ErrOAuthFailed, provider)
+	case email == "":
+		return nil, fmt.Errorf("%w: %s returned no email", domain.ErrOAuthFailed, provider)
+	case !identity.EmailVerified:
+		return nil, fmt.Errorf("%w: email %s is not verified", domain.ErrOAuthFailed, email)
+	case !emailDomainAllowed(email, s.allowedDomains):
+		return nil, fmt.Errorf("%w: %s is not allowed to sign in", domain.ErrOAuthFailed, email)
+	}
+
+	name := identity.Name
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+	externalID := provider + "|" + identity.Subject
+
+	user, err := s.users.GetUserByExternalID(ctx, externalID)
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		user = &domain.User{
+			ID:          uuid.New().String(),
+			Email:       email,
+			Name:        name,
+			Role:        domain.RoleMember,
+			ExternalID:  externalID,
+			LastLoginAt: s.now(),
+		}
+		if err := s.users.CreateUser(ctx, user); err != nil {
+			return nil, fmt.Errorf("failed to provision user: %w", err)
+		}
+		log.Printf("OAuth: provisioned %s through %s", email, provider)
+		return user, nil
+	case err != nil:
+		return nil, err
+	}
+
+	updated := *user
+	updated.Email = email
+	updated.Name = name
+	updated.LastLoginAt = s.now()
+	if err := s.users.UpdateUser(ctx, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	return &updated, nil
+}
+
+// authCodeURL builds an authorization code request with PKCE.
+func authCodeURL(endpoint, clientID, scope, state, challenge, redirectURL string) string {
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {clientID},
+		"redirect_uri":          {redirectURL},
+		"scope":                 {scope},
+		"state":                 {state},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+	return endpoint + "?" + q.Encode()
+}
+
+// redeemCode exchanges an authorization code for an access token.
+func redeemCode(ctx context.Context, client *http.Client, tokenURL, clientID, clientSecret, code, verifier, redirectURL string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURL},
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: token request: %v", domain.ErrOAuthFailed, err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("%w: token response: %v", domain.ErrOAuthFailed, err)
+	}
+	// GitHub reports errors with a 200
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		return "", fmt.Errorf("%w: token endpoint returned %d %s %s", domain.ErrOAuthFailed, resp.StatusCode, body.Error, body.ErrorDescription)
+	}
+	if body.AccessToken == "" {
+		return "", fmt.Errorf("%w: no access_token in token response", domain.ErrOAuthFailed)
+	}
+	return body.AccessToken, nil
+}
+
+func getWithToken(ctx context.Context, client *http.Client, rawURL, token string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: HTTP %d", rawURL, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out)
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+// fakeOAuth serves Google's token and userinfo endpoints and GitHub's
+// token and user API endpoints, answering with the configured profile.
+type fakeOAuth struct {
+	*httptest.Server
+	userinfo map[string]interface{}   // Google
+	user     map[string]interface{}   // GitHub
+	emails   []map[string]interface{} // GitHub
+	verifier string
+	redirect string
+}
+
+func newFakeOAuth(t *testing.T) *fakeOAuth {
+	f := &fakeOAuth{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		f.verifier = r.PostForm.Get("code_verifier")
+		f.redirect = r.PostForm.Get("redirect_uri")
+		if r.PostForm.Get("code") != "code-1" {
+			json.NewEncoder(w).Encode(map[string]string{"error": "bad_verification_code"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "at-1"})
+	})
+	authorized := func(h func(w http.ResponseWriter)) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer at-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			h(w)
+		}
+	}
+	mux.HandleFunc("/userinfo", authorized(func(w http.ResponseWriter) { json.NewEncoder(w).Encode(f.userinfo) }))
+	mux.HandleFunc("/user", authorized(func(w http.ResponseWriter) { json.NewEncoder(w).Encode(f.user) }))
+	mux.HandleFunc("/user/emails", authorized(func(w http.ResponseWriter) { json.NewEncoder(w).Encode(f.emails) }))
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func newTestOAuth(f *fakeOAuth) *OAuthService {
+	google := NewGoogleProvider("g-client", "g-secret")
+	google.AuthURL, google.TokenURL, google.UserInfoURL = f.URL+"/authorize", f.URL+"/token", f.URL+"/userinfo"
+	github := NewGitHubProvider("gh-client", "gh-secret")
+	github.AuthURL, github.TokenURL, github.APIURL = f.URL+"/authorize", f.URL+"/token", f.URL
+	return NewOAuthService(NewUserService(memory.NewUserRepository()), NewSessionTokens("secret", time.Hour), "https://api.example.com/").
+		WithProvider(google).
+		WithProvider(github)
+}
+
+// signIn runs the browser side of a social sign-in.
+func signIn(t *testing.T, s *OAuthService, f *fakeOAuth, provider, code string) (*SSOLogin, error) {
+	authURL, state, err := s.LoginURL(context.Background(), provider)
+	if err != nil {
+		t.Fatalf("LoginURL: %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	q := u.Query()
+	if q.Get("state") != state || q.Get("code_challenge_method") != "S256" || q.Get("redirect_uri") != s.RedirectURL(provider) {
+		t.Fatalf("authorization URL = %s", authURL)
+	}
+
+	result, err := s.Callback(context.Background(), provider, state, code)
+	if err == nil {
+		challenge := sha256.Sum256([]byte(f.verifier))
+		if base64.RawURLEncoding.EncodeToString(challenge[:]) != q.Get("code_challenge") {
+			t.Error("code_verifier does not match the code_challenge")
+		}
+		if f.redirect != "https://api.example.com/api/auth/oauth/"+provider+"/callback" {
+			t.Errorf("redirect_uri = %q", f.redirect)
+		}
+	}
+	return result, err
+}
+
+func TestOAuthGoogleSignIn(t *testing.T) {
+	f := newFakeOAuth(t)
+	s := newTestOAuth(f)
+
+	f.userinfo = map[string]interface{}{"sub": "g-1", "email": "Student@uni.edu", "email_verified": true, "name": "Stu"}
+	first, err := signIn(t, s, f, "google", "code-1")
+	if err != nil {
+		t.Fatalf("first sign-in: %v", err)
+	}
+	if first.User.Email != "student@uni.edu" || first.User.Role != domain.RoleMember || first.User.OrgID != "" || first.User.ExternalID != "google|g-1" {
+		t.Errorf("provisioned user = %+v", first.User)
+	}
+	session, err := s.sessions.Verify(first.Token)
+	if err != nil || session.UserID != first.User.ID || session.Role != domain.RoleMember {
+		t.Fatalf("session = %+v, %v", session, err)
+	}
+
+	// A promoted user keeps their role on the next sign-in
+	promoted := *first.User
+	promoted.Role = domain.RoleAnalyst
+	if err := s.users.UpdateUser(context.Background(), &promoted); err != nil {
+		t.Fatal(err)
+	}
+	f.userinfo["name"] = "Student"
+	second, err := signIn(t, s, f, "google", "code-1")
+	if err != nil {
+		t.Fatalf("second sign-in: %v", err)
+	}
+	if second.User.ID != first.User.ID || second.User.Role != domain.RoleAnalyst || second.User.Name != "Student" {
+		t.Errorf("second sign-in user = %+v, want same ID, analyst role and new name", second.User)
+	}
+}
+
+func TestOAuthGitHubSignInUsesPrimaryVerifiedEmail(t *testing.T) {
+	f := newFakeOAuth(t)
+	s := newTestOAuth(f)
+
+	f.user = map[string]interface{}{"id": 42, "login": "octo", "name": ""}
+	f.emails = []map[string]interface{}{
+		{"email": "old@example.com", "primary": false, "verified": true},
+		{"email": "octo@example.com", "primary": true, "verified": true},
+	}
+	login, err := signIn(t, s, f, "github", "code-1")
+	if err != nil {
+		t.Fatalf("sign-in: %v", err)
+	}
+	if login.User.Email != "octo@example.com" || login.User.Name != "octo" || login.User.ExternalID != "github|42" {
+		t.Errorf("provisioned user = %+v", login.User)
+	}
+
+	f.emails[1]["verified"] = false
+	if _, err := signIn(t, s, f, "github", "code-1"); !errors.Is(err, domain.ErrOAuthFailed) {
+		t.Errorf("unverified primary email err = %v, want ErrOAuthFailed", err)
+	}
+}
+
+func TestOAuthSignInRejects(t *testing.T) {
+	f := newFakeOAuth(t)
+	s := newTestOAuth(f).WithAllowedDomains([]string{"uni.edu"})
+	f.userinfo = map[string]interface{}{"sub": "g-2", "email": "eve@evil.com", "email_verified": true}
+
+	if _, err := signIn(t, s, f, "google", "code-1"); !errors.Is(err, domain.ErrOAuthFailed) {
+		t.Errorf("other email domain err = %v, want ErrOAuthFailed", err)
+	}
+	f.userinfo["email"] = "ada@cs.uni.edu"
+	if _, err := signIn(t, s, f, "google", "wrong-code"); !errors.Is(err, domain.ErrOAuthFailed) {
+		t.Errorf("rejected code err = %v, want ErrOAuthFailed", err)
+	}
+	if _, err := signIn(t, s, f, "google", "code-1"); err != nil {
+		t.Errorf("subdomain of an allowed domain: %v", err)
+	}
+
+	// State minted for one provider cannot complete another's sign-in
+	_, state, _ := s.LoginURL(context.Background(), "google")
+	if _, err := s.Callback(context.Background(), "github", state, "code-1"); !errors.Is(err, domain.ErrOAuthFailed) {
+		t.Errorf("cross-provider state err = %v, want ErrOAuthFailed", err)
+	}
+	if _, err := s.Callback(context.Background(), "google", "forged.state", "code-1"); !errors.Is(err, domain.ErrOAuthFailed) {
+		t.Errorf("forged state err = %v, want ErrOAuthFailed", err)
+	}
+	if _, _, err := s.LoginURL(context.Background(), "facebook"); !errors.Is(err, domain.ErrUnknownOAuthProvider) {
+		t.Errorf("unknown provider err = %v, want ErrUnknownOAuthProvider", err)
+	}
+}
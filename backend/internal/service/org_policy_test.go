@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestOrgPolicyMatch(t *testing.T) {
+	ctx := context.Background()
+	policy := NewOrgPolicyService(memory.NewOrgDomainRuleRepository())
+	mustPut := func(org, domainName, list string) *domain.OrgDomainRule {
+		t.Helper()
+		rule, err := policy.Put(ctx, org, domain.OrgDomainRule{Domain: domainName, List: list})
+		if err != nil {
+			t.Fatalf("Put(%s, %s): %v", org, domainName, err)
+		}
+		return rule
+	}
+	mustPut("acme", "www.Example.com", domain.DomainListAllow)
+	mustPut("acme", "blogs.example.com", domain.DomainListBlock)
+	mustPut("globex", "example.com", domain.DomainListBlock)
+
+	tests := []struct {
+		name     string
+		org      string
+		host     string
+		wantList string // empty = no rule
+	}{
+		{"exact domain", "acme", "example.com", domain.DomainListAllow},
+		{"subdomain inherits", "acme", "news.example.com", domain.DomainListAllow},
+		{"most specific rule wins", "acme", "x.blogs.example.com", domain.DomainListBlock},
+		{"suffix is not a subdomain", "acme", "badexample.com", ""},
+		{"other organization's lists", "globex", "news.example.com", domain.DomainListBlock},
+		{"no organization", "", "example.com", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, ok := policy.Match(ctx, tt.org, tt.host)
+			got := ""
+			if ok {
+				got = rule.List
+			}
+			if got != tt.wantList {
+				t.Errorf("Match(%s, %s) list = %q, want %q", tt.org, tt.host, got, tt.wantList)
+			}
+		})
+	}
+}
+
+func TestOrgPolicyPutAndRemove(t *testing.T) {
+	ctx := context.Background()
+	policy := NewOrgPolicyService(memory.NewOrgDomainRuleRepository())
+
+	for _, bad := range []domain.OrgDomainRule{
+		{Domain: "not a domain", List: domain.DomainListBlock},
+		{Domain: "example.com", List: "maybe"},
+		{Domain: "example.com", List: domain.DomainListAllow, Weight: -1},
+	} {
+		if _, err := policy.Put(ctx, "acme", bad); err == nil {
+			t.Errorf("Put(%+v) succeeded, want error", bad)
+		}
+	}
+
+	first, err := policy.Put(ctx, "acme", domain.OrgDomainRule{Domain: "example.com", List: domain.DomainListAllow, Weight: 2})
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	moved, err := policy.Put(ctx, "acme", domain.OrgDomainRule{Domain: "example.com", List: domain.DomainListBlock, Weight: 2})
+	if err != nil {
+		t.Fatalf("Put again: %v", err)
+	}
+	if moved.ID != first.ID || moved.Weight != 0 {
+		t.Errorf("moved rule = %+v; want same ID and weight dropped for blocklist", moved)
+	}
+	if rules, _ := policy.List(ctx, "acme"); len(rules) != 1 {
+		t.Errorf("List = %d rules, want 1", len(rules))
+	}
+
+	if err := policy.Remove(ctx, "globex", first.ID); !errors.Is(err, domain.ErrDomainRuleNotFound) {
+		t.Errorf("Remove from other org = %v, want ErrDomainRuleNotFound", err)
+	}
+	if err := policy.Remove(ctx, "acme", first.ID); err != nil {
+		t.Errorf("Remove: %v", err)
+	}
+}
+
+func TestOrgPolicySignalAdjustsFusion(t *testing.T) {
+	ctx := context.Background()
+	policy := NewOrgPolicyService(memory.NewOrgDomainRuleRepository())
+	if _, err := policy.Put(ctx, "acme", domain.OrgDomainRule{Domain: "example.com", List: domain.DomainListAllow, Weight: 3}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	fusion := NewVerdictFusion().WithSignal(OrgPolicySignal{Policy: policy}, DefaultOrgPolicyWeight)
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantResult string
+	}{
+		{"trusted by caller's organization", ContextWithOrg(ctx, "acme"), domain.LabelReal},
+		{"other organization", ContextWithOrg(ctx, "globex"), domain.LabelFake},
+		{"anonymous", ctx, domain.LabelFake},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &domain.Prediction{FakeProbability: 0.7}
+			fusion.Apply(tt.ctx, &SignalInput{Prediction: p, Source: "news.example.com"})
+			if p.Result != tt.wantResult {
+				t.Errorf("Result = %s, want %s (signals %+v)", p.Result, tt.wantResult, p.Signals)
+			}
+		})
+	}
+}
+
+func TestAnalyzeURLBlockedByOrg(t *testing.T) {
+	ctx := context.Background()
+	policy := NewOrgPolicyService(memory.NewOrgDomainRuleRepository())
+	if _, err := policy.Put(ctx, "acme", domain.OrgDomainRule{Domain: "example.com", List: domain.DomainListBlock}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	// No ML client or scraper: a blocked domain must not reach either.
+	svc := NewNewsService(nil, nil, memory.NewPredictionRepository()).WithOrgPolicy(policy)
+
+	p, err := svc.AnalyzeNews(ContextWithOrg(ctx, "acme"), &domain.AnalysisRequest{Type: "url", Content: "https://www.example.com/story"})
+	if err != nil {
+		t.Fatalf("AnalyzeNews: %v", err)
+	}
+	if p.Result != domain.LabelFake || p.Confidence != 1 || p.CanonicalURL != "" {
+		t.Errorf("prediction = %+v; want FAKE at 1.0 and no shared canonical URL", p)
+	}
+	if len(p.Signals) != 1 || p.Signals[0].Name != domain.SignalOrgPolicy {
+		t.Errorf("signals = %+v, want the org policy only", p.Signals)
+	}
+}
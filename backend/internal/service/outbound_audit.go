@@ -0,0 +1,269 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// OutboundMode controls the outbound request audit.
+type OutboundMode string
+
+const (
+	OutboundOff     OutboundMode = ""
+	OutboundLog     OutboundMode = "log"     // record every destination; flag ones not allow-listed
+	OutboundEnforce OutboundMode = "enforce" // also refuse destinations not allow-listed
+)
+
+// Purposes tag why the service contacted a destination.
+const (
+	PurposeML          = "ml"          // model predictions, health and summaries
+	PurposeScrape      = "scrape"      // article pages, redirects, robots.txt and branding images
+	PurposeWebhook     = "webhook"     // alert, report and subscriber webhooks
+	PurposePush        = "push"        // Web Push services
+	PurposeFactCheck   = "factcheck"   // evidence search APIs
+	PurposeTranslation = "translation" // machine translation
+	PurposeCaptcha     = "captcha"     // CAPTCHA verification
+	PurposeSSO         = "sso"         // identity provider discovery, keys, tokens and social sign-in profiles
+)
+
+// maxOutboundEvents is the number of recent requests kept for the report.
+const maxOutboundEvents = 500
+
+// ParseOutboundMode validates an OUTBOUND_AUDIT value.
+func ParseOutboundMode(s string) (OutboundMode, error) {
+	switch mode := OutboundMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case OutboundOff, OutboundLog, OutboundEnforce:
+		return mode, nil
+	default:
+		return OutboundOff, fmt.Errorf("unknown outbound audit mode %q (want log or enforce)", s)
+	}
+}
+
+// OutboundRule allows a purpose to reach a host and its subdomains. "*"
+// matches any purpose or host.
+type OutboundRule struct {
+	Purpose string `json:"purpose"`
+	Host    string `json:"host"`
+}
+
+func (r OutboundRule) matches(purpose, host string) bool {
+	if r.Purpose != "*" && r.Purpose != purpose {
+		return false
+	}
+	return r.Host == "*" || host == r.Host || strings.HasSuffix(host, "."+r.Host)
+}
+
+// ParseOutboundAllowlist parses comma-separated purpose=host rules, e.g.
+// "ml=ml.internal,scrape=*,webhook=hooks.slack.com".
+func ParseOutboundAllowlist(spec string) ([]OutboundRule, error) {
+	var rules []OutboundRule
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		purpose, host, ok := strings.Cut(entry, "=")
+		purpose = strings.ToLower(strings.TrimSpace(purpose))
+		host = normalizeDomain(strings.TrimSpace(host))
+		if !ok || purpose == "" || host == "" {
+			return nil, fmt.Errorf("invalid outbound rule %q (want purpose=host)", entry)
+		}
+		rules = append(rules, OutboundRule{Purpose: purpose, Host: host})
+	}
+	return rules, nil
+}
+
+// OutboundEvent is one audited request. Query strings are never recorded,
+// as they may carry credentials.
+type OutboundEvent struct {
+	At          time.Time `json:"at"`
+	Purpose     string    `json:"purpose"`
+	Method      string    `json:"method"`
+	Host        string    `json:"host"`
+	Path        string    `json:"path"`
+	Status      int       `json:"status,omitempty"`
+	Allowlisted bool      `json:"allowlisted"`
+	Blocked     bool      `json:"blocked,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMS  int64     `json:"duration_ms"`
+}
+
+// OutboundDestination aggregates the requests to one host for one purpose.
+type OutboundDestination struct {
+	Purpose     string    `json:"purpose"`
+	Host        string    `json:"host"`
+	Allowlisted bool      `json:"allowlisted"`
+	Requests    int64     `json:"requests"`
+	Blocked     int64     `json:"blocked"`
+	Errors      int64     `json:"errors"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// OutboundReport is the audit since startup.
+type OutboundReport struct {
+	Mode         OutboundMode          `json:"mode"`
+	Since        time.Time             `json:"since"`
+	Allowlist    []OutboundRule        `json:"allowlist"`
+	Destinations []OutboundDestination `json:"destinations"`
+	Recent       []OutboundEvent       `json:"recent"` // newest first
+}
+
+// OutboundAudit records every outbound HTTP request made through clients
+// it wraps, writes each one to the audit log and, in enforce mode, refuses
+// destinations that are not allow-listed.
+type OutboundAudit struct {
+	mode  OutboundMode
+	rules []OutboundRule
+	sink  io.Writer
+	since time.Time
+
+	mu           sync.Mutex
+	destinations map[OutboundRule]*OutboundDestination
+	recent       []OutboundEvent
+}
+
+// NewOutboundAudit creates an audit. Events are written to the standard
+// logger until WithSink is called.
+func NewOutboundAudit(mode OutboundMode, rules []OutboundRule) *OutboundAudit {
+	return &OutboundAudit{
+		mode:         mode,
+		rules:        rules,
+		since:        time.Now(),
+		destinations: make(map[OutboundRule]*OutboundDestination),
+	}
+}
+
+// WithSink writes events to w as JSON lines, e.g. an append-only file.
+func (a *OutboundAudit) WithSink(w io.Writer) *OutboundAudit {
+	a.sink = w
+	return a
+}
+
+// Allowed reports whether purpose may contact host.
+func (a *OutboundAudit) Allowed(purpose, host string) bool {
+	host = normalizeDomain(host)
+	for _, r := range a.rules {
+		if r.matches(purpose, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// Client routes c's requests through the audit under purpose. A nil audit
+// leaves c unchanged.
+func (a *OutboundAudit) Client(c *http.Client, purpose string) {
+	if a == nil || a.mode == OutboundOff {
+		return
+	}
+	next := c.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.Transport = &auditTransport{audit: a, purpose: purpose, next: next}
+}
+
+// Report returns the destinations contacted and up to limit recent events.
+func (a *OutboundAudit) Report(limit int) OutboundReport {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	report := OutboundReport{
+		Mode:         a.mode,
+		Since:        a.since,
+		Allowlist:    append([]OutboundRule{}, a.rules...),
+		Destinations: make([]OutboundDestination, 0, len(a.destinations)),
+		Recent:       []OutboundEvent{},
+	}
+	for _, d := range a.destinations {
+		report.Destinations = append(report.Destinations, *d)
+	}
+	sort.Slice(report.Destinations, func(i, j int) bool {
+		di, dj := report.Destinations[i], report.Destinations[j]
+		if di.Purpose != dj.Purpose {
+			return di.Purpose < dj.Purpose
+		}
+		return di.Host < dj.Host
+	})
+	for i := len(a.recent) - 1; i >= 0 && len(report.Recent) < limit; i-- {
+		report.Recent = append(report.Recent, a.recent[i])
+	}
+	return report
+}
+
+func (a *OutboundAudit) record(event OutboundEvent) {
+	a.mu.Lock()
+	key := OutboundRule{Purpose: event.Purpose, Host: event.Host}
+	d, ok := a.destinations[key]
+	if !ok {
+		d = &OutboundDestination{Purpose: event.Purpose, Host: event.Host, Allowlisted: event.Allowlisted, FirstSeen: event.At}
+		a.destinations[key] = d
+	}
+	d.Requests++
+	d.LastSeen = event.At
+	if event.Blocked {
+		d.Blocked++
+	} else if event.Error != "" || event.Status >= 500 {
+		d.Errors++
+	}
+	a.recent = append(a.recent, event)
+	if len(a.recent) > maxOutboundEvents {
+		a.recent = a.recent[len(a.recent)-maxOutboundEvents:]
+	}
+	a.mu.Unlock()
+
+	if a.sink == nil {
+		log.Printf("AUDIT outbound purpose=%s method=%s host=%s path=%s status=%d allowlisted=%t blocked=%t",
+			event.Purpose, event.Method, event.Host, event.Path, event.Status, event.Allowlisted, event.Blocked)
+		return
+	}
+	line, _ := json.Marshal(event)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.sink.Write(append(line, '\n')); err != nil {
+		log.Printf("Warning: failed to write outbound audit event: %v", err)
+	}
+}
+
+// auditTransport records each request, including every redirect hop.
+type auditTransport struct {
+	audit   *OutboundAudit
+	purpose string
+	next    http.RoundTripper
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	event := OutboundEvent{
+		At:          time.Now().UTC(),
+		Purpose:     t.purpose,
+		Method:      req.Method,
+		Host:        strings.ToLower(req.URL.Host),
+		Path:        req.URL.Path,
+		Allowlisted: t.audit.Allowed(t.purpose, req.URL.Hostname()),
+	}
+	if !event.Allowlisted && t.audit.mode == OutboundEnforce {
+		event.Blocked = true
+		t.audit.record(event)
+		return nil, fmt.Errorf("%w: %s to %s", domain.ErrOutboundBlocked, t.purpose, req.URL.Hostname())
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	event.DurationMS = time.Since(event.At).Milliseconds()
+	if err != nil {
+		event.Error = err.Error()
+	} else {
+		event.Status = resp.StatusCode
+	}
+	t.audit.record(event)
+	return resp, err
+}
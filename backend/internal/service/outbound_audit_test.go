@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+func TestParseOutboundAllowlist(t *testing.T) {
+	rules, err := ParseOutboundAllowlist(" ml=ML.Internal , scrape=*,*=www.hooks.example ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []OutboundRule{{"ml", "ml.internal"}, {"scrape", "*"}, {"*", "hooks.example"}}
+	if len(rules) != len(want) {
+		t.Fatalf("rules = %+v, want %+v", rules, want)
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, rules[i], want[i])
+		}
+	}
+	if _, err := ParseOutboundAllowlist("ml.internal"); err == nil {
+		t.Error("rule without purpose accepted")
+	}
+
+	audit := NewOutboundAudit(OutboundEnforce, rules)
+	tests := []struct {
+		purpose, host string
+		want          bool
+	}{
+		{PurposeML, "ml.internal", true},
+		{PurposeML, "gpu.ml.internal", true},
+		{PurposeML, "evilml.internal", false},
+		{PurposeWebhook, "ml.internal", false},
+		{PurposeScrape, "anything.example", true},
+		{PurposePush, "www.hooks.example", true},
+	}
+	for _, tt := range tests {
+		if got := audit.Allowed(tt.purpose, tt.host); got != tt.want {
+			t.Errorf("Allowed(%s, %s) = %t, want %t", tt.purpose, tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestOutboundAuditRecordsAndEnforces(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	var sink bytes.Buffer
+	audit := NewOutboundAudit(OutboundEnforce, []OutboundRule{{Purpose: PurposeWebhook, Host: "127.0.0.1"}}).WithSink(&sink)
+	webhook := &http.Client{}
+	audit.Client(webhook, PurposeWebhook)
+	push := &http.Client{}
+	audit.Client(push, PurposePush)
+
+	resp, err := webhook.Get(srv.URL + "/hook?token=secret")
+	if err != nil {
+		t.Fatalf("allow-listed request error = %v", err)
+	}
+	resp.Body.Close()
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+"/push", nil)
+	if _, err := push.Do(req); !errors.Is(err, domain.ErrOutboundBlocked) {
+		t.Errorf("unlisted request error = %v, want ErrOutboundBlocked", err)
+	}
+
+	report := audit.Report(10)
+	if len(report.Destinations) != 2 || len(report.Recent) != 2 {
+		t.Fatalf("report = %+v, want 2 destinations and 2 events", report)
+	}
+	if d := report.Destinations[0]; d.Purpose != PurposePush || d.Blocked != 1 || d.Allowlisted {
+		t.Errorf("push destination = %+v", d)
+	}
+	if d := report.Destinations[1]; d.Purpose != PurposeWebhook || d.Requests != 1 || d.Blocked != 0 || !d.Allowlisted {
+		t.Errorf("webhook destination = %+v", d)
+	}
+	if e := report.Recent[0]; e.Purpose != PurposePush || !e.Blocked {
+		t.Errorf("newest event = %+v, want the blocked push", e)
+	}
+
+	lines := strings.Split(strings.TrimSpace(sink.String()), "\n")
+	var first OutboundEvent
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[0]), &first) != nil {
+		t.Fatalf("audit log = %q", sink.String())
+	}
+	if first.Path != "/hook" || first.Status != http.StatusNoContent || strings.Contains(sink.String(), "secret") {
+		t.Errorf("audit log = %q, want the path and status without the query", sink.String())
+	}
+
+	// Log mode flags the destination but lets it through.
+	logOnly := NewOutboundAudit(OutboundLog, nil).WithSink(&bytes.Buffer{})
+	client := &http.Client{}
+	logOnly.Client(client, PurposePush)
+	resp, err = client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("log mode request error = %v", err)
+	}
+	resp.Body.Close()
+	if e := logOnly.Report(1).Recent[0]; e.Allowlisted || e.Blocked {
+		t.Errorf("log mode event = %+v, want unlisted but not blocked", e)
+	}
+}
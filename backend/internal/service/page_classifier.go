@@ -0,0 +1,196 @@
+package service
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// PageType is the coarse kind of page a URL points at.
+type PageType string
+
+const (
+	PageArticle PageType = "article"
+	PageListing PageType = "listing" // homepages, category/tag/search pages
+	PageVideo   PageType = "video"
+	PageError   PageType = "error" // soft 404s and error templates
+)
+
+// pageTypeHelp explains each rejected page type to the user.
+var pageTypeHelp = map[PageType]string{
+	PageListing: "this looks like a homepage or section listing — open a specific article and submit its URL",
+	PageVideo:   "this looks like a video page — there is no article text to analyze",
+	PageError:   "this looks like an error or 'page not found' page — check the URL",
+}
+
+// listingSegments are path segments that indicate an index of articles.
+var listingSegments = map[string]bool{
+	"category": true, "categories": true, "tag": true, "tags": true,
+	"topic": true, "topics": true, "section": true, "sections": true,
+	"author": true, "authors": true, "search": true, "archive": true,
+	"archives": true, "latest": true, "page": true,
+}
+
+// videoSegments are path segments that indicate a video page.
+var videoSegments = map[string]bool{
+	"video": true, "videos": true, "watch": true, "live": true, "tv": true,
+}
+
+// ClassifyPage decides whether a page is an article, using structured data,
+// URL patterns and extraction statistics. It must be called before noise
+// elements are removed from the document.
+func ClassifyPage(u *url.URL, doc *goquery.Document) PageType {
+	if isErrorPage(doc) {
+		return PageError
+	}
+
+	// ── structured data is the most reliable signal ──
+	switch structuredDataType(doc) {
+	case PageArticle:
+		return PageArticle
+	case PageVideo:
+		return PageVideo
+	case PageListing:
+		return PageListing
+	}
+
+	// ── URL patterns ──
+	path := strings.Trim(u.Path, "/")
+	if path == "" || strings.EqualFold(path, "index.html") {
+		return PageListing
+	}
+	segments := strings.Split(strings.ToLower(path), "/")
+	for _, seg := range segments {
+		if videoSegments[seg] {
+			return PageVideo
+		}
+	}
+	if listingSegments[segments[0]] {
+		return PageListing
+	}
+
+	// ── extraction statistics ──
+	if looksLikeListing(doc) {
+		return PageListing
+	}
+	return PageArticle
+}
+
+// structuredDataType inspects og:type and JSON-LD @type declarations.
+func structuredDataType(doc *goquery.Document) PageType {
+	var found PageType
+	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(_ int, sel *goquery.Selection) bool {
+		for _, t := range jsonLDTypes(sel.Text()) {
+			switch t {
+			case "NewsArticle", "Article", "ReportageNewsArticle", "AnalysisNewsArticle",
+				"OpinionNewsArticle", "BlogPosting", "Report", "LiveBlogPosting":
+				found = PageArticle
+				return false
+			case "VideoObject":
+				found = PageVideo
+			case "CollectionPage", "ItemList", "SearchResultsPage":
+				if found == "" {
+					found = PageListing
+				}
+			}
+		}
+		return true
+	})
+	if found != "" {
+		return found
+	}
+
+	ogType, _ := doc.Find(`meta[property="og:type"]`).Attr("content")
+	ogType = strings.ToLower(strings.TrimSpace(ogType))
+	switch {
+	case ogType == "article":
+		return PageArticle
+	case strings.HasPrefix(ogType, "video"):
+		return PageVideo
+	}
+	return ""
+}
+
+// jsonLDTypes extracts every @type value from a JSON-LD blob, including
+// nested @graph entries.
+func jsonLDTypes(raw string) []string {
+	var data interface{}
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		return nil
+	}
+	var types []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch node := v.(type) {
+		case []interface{}:
+			for _, item := range node {
+				walk(item)
+			}
+		case map[string]interface{}:
+			switch t := node["@type"].(type) {
+			case string:
+				types = append(types, t)
+			case []interface{}:
+				for _, item := range t {
+					if s, ok := item.(string); ok {
+						types = append(types, s)
+					}
+				}
+			}
+			if graph, ok := node["@graph"]; ok {
+				walk(graph)
+			}
+		}
+	}
+	walk(data)
+	return types
+}
+
+// isErrorPage detects soft 404s served with HTTP 200.
+func isErrorPage(doc *goquery.Document) bool {
+	title := strings.ToLower(strings.TrimSpace(doc.Find("title").First().Text()))
+	h1 := strings.ToLower(strings.TrimSpace(doc.Find("h1").First().Text()))
+	for _, s := range []string{title, h1} {
+		if strings.Contains(s, "page not found") || strings.HasPrefix(s, "404") ||
+			strings.Contains(s, "error 404") || strings.HasPrefix(s, "not found") ||
+			strings.Contains(s, "page does not exist") {
+			return true
+		}
+	}
+	return false
+}
+
+// looksLikeListing uses extraction statistics: many teaser blocks, many
+// linked headlines or mostly-link text all indicate an index page.
+func looksLikeListing(doc *goquery.Document) bool {
+	body := doc.Find("body")
+	if body.Length() == 0 {
+		body = doc.Selection
+	}
+
+	articles := body.Find("article").Length()
+	linkedHeadlines := body.Find("h2 a, h3 a, h4 a").Length()
+
+	longParagraphs := 0
+	body.Find("p").Each(func(_ int, p *goquery.Selection) {
+		if len(strings.TrimSpace(p.Text())) > 120 {
+			longParagraphs++
+		}
+	})
+
+	if articles >= 4 && longParagraphs < articles {
+		return true
+	}
+	if linkedHeadlines >= 12 && longParagraphs < 5 {
+		return true
+	}
+
+	totalText := len(strings.Join(strings.Fields(body.Text()), " "))
+	linkText := 0
+	body.Find("a").Each(func(_ int, a *goquery.Selection) {
+		linkText += len(strings.Join(strings.Fields(a.Text()), " "))
+	})
+	return totalText > 0 && float64(linkText)/float64(totalText) > 0.6
+}
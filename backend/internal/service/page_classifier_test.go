@@ -0,0 +1,77 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestClassifyPage(t *testing.T) {
+	paragraph := "<p>" + strings.Repeat("Officials confirmed the details of the report on Tuesday. ", 5) + "</p>"
+	teasers := strings.Repeat(`<article><h3><a href="/x">Headline</a></h3></article>`, 10)
+
+	tests := []struct {
+		name string
+		url  string
+		html string
+		want PageType
+	}{
+		{
+			name: "json-ld article",
+			url:  "https://example.com/",
+			html: `<script type="application/ld+json">{"@type":"NewsArticle"}</script>` + paragraph,
+			want: PageArticle,
+		},
+		{
+			name: "homepage",
+			url:  "https://example.com/",
+			html: paragraph,
+			want: PageListing,
+		},
+		{
+			name: "category path",
+			url:  "https://example.com/category/politics",
+			html: paragraph,
+			want: PageListing,
+		},
+		{
+			name: "video path",
+			url:  "https://example.com/video/clip-123",
+			html: paragraph,
+			want: PageVideo,
+		},
+		{
+			name: "soft 404",
+			url:  "https://example.com/news/missing-story",
+			html: "<title>Page Not Found</title>",
+			want: PageError,
+		},
+		{
+			name: "teaser grid",
+			url:  "https://example.com/news/world",
+			html: teasers,
+			want: PageListing,
+		},
+		{
+			name: "plain article",
+			url:  "https://example.com/news/2024/05/some-story-slug",
+			html: "<h1>Story</h1>" + paragraph + paragraph,
+			want: PageArticle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := url.Parse(tt.url)
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><body>" + tt.html + "</body></html>"))
+			if err != nil {
+				t.Fatalf("failed to parse html: %v", err)
+			}
+			if got := ClassifyPage(u, doc); got != tt.want {
+				t.Errorf("ClassifyPage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,562 @@
+package service
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// PDFTextExtractor extracts the text a PDF draws, from its uncompressed
+// and Flate-compressed content streams, plus the title and author of its
+// document information. It reads fonts with single-byte encodings, which
+// covers most office and press-release PDFs. Scanned pages, and fonts
+// that only map glyphs to text through a ToUnicode CMap, yield little or
+// no text, and such documents are rejected as too short to score.
+type PDFTextExtractor struct{}
+
+// Name implements DocumentExtractor.
+func (PDFTextExtractor) Name() string { return "pdf-text" }
+
+var (
+	// pdfSkippedStream matches the dictionaries of streams that hold no
+	// page text: images, embedded fonts and files, XMP metadata, cross
+	// reference and object streams
+	pdfSkippedStream = regexp.MustCompile(`/(Subtype\s*/(Image|Type1C|CIDFontType0C|OpenType|XML)|Type\s*/(XRef|ObjStm|Metadata|EmbeddedFile)|Length[123]\b)`)
+	pdfFilter        = regexp.MustCompile(`/Filter\s*(\[\s*)?/(\w+)`)
+	pdfInfoString    = regexp.MustCompile(`/(Title|Author)\s*([(<])`)
+)
+
+// maxPDFNesting caps how deeply content stream arrays may nest. Real
+// content nests one or two levels; a stream of brackets would otherwise
+// recurse until the stack overflows, which kills the process.
+const maxPDFNesting = 64
+
+var errPDFTooDeep = errors.New("PDF content nests too deeply")
+
+// Extract implements DocumentExtractor.
+func (PDFTextExtractor) Extract(ctx context.Context, data []byte) (*DocumentText, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\r\n "), []byte("%PDF-")) {
+		return nil, errors.New("not a PDF file")
+	}
+
+	doc := &DocumentText{}
+	// Only the first of each is decoded: an unterminated string runs to
+	// the end of the file, so decoding every match could be quadratic
+	var haveTitle, haveAuthor bool
+	for _, m := range pdfInfoString.FindAllSubmatchIndex(data, -1) {
+		if haveTitle && haveAuthor {
+			break
+		}
+		switch string(data[m[2]:m[3]]) {
+		case "Title":
+			if !haveTitle {
+				doc.Title, haveTitle = pdfInfoText(data, m[4]), true
+			}
+		case "Author":
+			if !haveAuthor {
+				doc.Author, haveAuthor = pdfInfoText(data, m[4]), true
+			}
+		}
+	}
+
+	budget := int64(maxDocumentText)
+	streams := &pdfStreams{data: data, lastObj: -1}
+	for budget > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		dict, content, ok := streams.next()
+		if !ok {
+			break
+		}
+		if pdfSkippedStream.Match(dict) {
+			continue
+		}
+		if m := pdfFilter.FindSubmatch(dict); m != nil {
+			if string(m[2]) != "FlateDecode" {
+				continue
+			}
+			zr, err := zlib.NewReader(bytes.NewReader(content))
+			if err != nil {
+				continue
+			}
+			// A truncated stream still yields the text before the damage
+			content, _ = io.ReadAll(io.LimitReader(zr, budget))
+			zr.Close()
+		}
+		budget -= int64(len(content))
+		lines, err := pdfContentText(content)
+		if err != nil {
+			return nil, err
+		}
+		doc.Paragraphs = append(doc.Paragraphs, lines...)
+	}
+	return doc, nil
+}
+
+// pdfInfoText decodes the document information string starting at start.
+func pdfInfoText(data []byte, start int) string {
+	value, _ := pdfString(data, start)
+	return strings.TrimSpace(decodePDFText(value))
+}
+
+// pdfStreams walks a PDF's stream objects in one forward pass. Each byte
+// is searched once, however many "stream" keywords a crafted file holds.
+type pdfStreams struct {
+	data    []byte
+	pos     int // where the search for the next keyword resumes
+	lastObj int // offset of the last "obj" keyword before pos, or -1
+}
+
+// next returns the next stream object's dictionary and raw content.
+func (s *pdfStreams) next() (dict, content []byte, ok bool) {
+	for {
+		i := bytes.Index(s.data[s.pos:], []byte("stream"))
+		if i < 0 {
+			s.pos = len(s.data)
+			return nil, nil, false
+		}
+		i += s.pos
+		if j := bytes.LastIndex(s.data[s.pos:i], []byte("obj")); j >= 0 {
+			s.lastObj = s.pos + j
+		}
+		s.pos = i + len("stream")
+		// The keyword must follow a dictionary, which rules out "endstream"
+		before := bytes.TrimRight(s.data[:i], "\x00\t\r\n ")
+		if !bytes.HasSuffix(before, []byte(">>")) || s.lastObj < 0 {
+			continue
+		}
+		dict = before[s.lastObj:]
+
+		begin := s.pos
+		if bytes.HasPrefix(s.data[begin:], []byte("\r\n")) {
+			begin += 2
+		} else if begin < len(s.data) && (s.data[begin] == '\n' || s.data[begin] == '\r') {
+			begin++
+		}
+		end := bytes.Index(s.data[begin:], []byte("endstream"))
+		if end < 0 {
+			s.pos = len(s.data)
+			return dict, s.data[begin:], true
+		}
+		s.pos = begin + end + len("endstream")
+		return dict, s.data[begin : begin+end], true
+	}
+}
+
+// pdfContentText interprets the text operators of a content stream and
+// returns its lines of text.
+func pdfContentText(content []byte) ([]string, error) {
+	var lines []string
+	var line strings.Builder
+	newline := func() {
+		if s := strings.TrimSpace(line.String()); s != "" {
+			lines = append(lines, s)
+		}
+		line.Reset()
+	}
+	space := func() {
+		if s := line.String(); s != "" && !strings.HasSuffix(s, " ") {
+			line.WriteByte(' ')
+		}
+	}
+	show := func(s []byte) {
+		if text, ok := readablePDFText(s); ok {
+			line.WriteString(text)
+		}
+	}
+
+	var operands []pdfToken
+	lastY, haveY := 0.0, false
+	lex := &pdfLexer{data: content}
+	for {
+		tok, ok := lex.next()
+		if lex.err != nil {
+			return nil, lex.err
+		}
+		if !ok {
+			break
+		}
+		if tok.kind != pdfOperator {
+			operands = append(operands, tok)
+			continue
+		}
+		switch tok.op {
+		case "Tj":
+			if s, ok := lastOperand(operands, pdfStringToken); ok {
+				show(s.str)
+			}
+		case "'", `"`:
+			newline()
+			if s, ok := lastOperand(operands, pdfStringToken); ok {
+				show(s.str)
+			}
+		case "TJ":
+			if a, ok := lastOperand(operands, pdfArrayToken); ok {
+				for _, el := range a.array {
+					switch {
+					case el.kind == pdfStringToken:
+						show(el.str)
+					case el.kind == pdfNumberToken && el.num < -200:
+						// A wide negative adjustment is a word gap
+						space()
+					}
+				}
+			}
+		case "T*", "ET":
+			newline()
+		case "Td", "TD":
+			if len(operands) >= 2 && operands[len(operands)-1].num != 0 {
+				newline()
+			} else {
+				space()
+			}
+		case "Tm":
+			if len(operands) >= 6 {
+				y := operands[len(operands)-1].num
+				if haveY && y != lastY {
+					newline()
+				} else {
+					space()
+				}
+				lastY, haveY = y, true
+			}
+		case "BT":
+			haveY = false
+		case "ID":
+			lex.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+	newline()
+	return lines, nil
+}
+
+func lastOperand(operands []pdfToken, kind int) (pdfToken, bool) {
+	if len(operands) == 0 || operands[len(operands)-1].kind != kind {
+		return pdfToken{}, false
+	}
+	return operands[len(operands)-1], true
+}
+
+// Content stream token kinds
+const (
+	pdfOperator = iota
+	pdfNumberToken
+	pdfStringToken
+	pdfArrayToken
+	pdfOtherToken // names, dictionaries and booleans, which text extraction ignores
+)
+
+type pdfToken struct {
+	kind  int
+	op    string
+	num   float64
+	str   []byte
+	array []pdfToken
+}
+
+// pdfLexer splits a content stream into operands and operators.
+type pdfLexer struct {
+	data  []byte
+	pos   int
+	depth int   // arrays open around the token being read
+	err   error // set when the stream cannot be read further
+}
+
+func (l *pdfLexer) next() (pdfToken, bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return pdfToken{}, false
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '(':
+		s, end := pdfString(l.data, l.pos)
+		l.pos = end
+		return pdfToken{kind: pdfStringToken, str: s}, true
+	case c == '<' && l.peek(1) == '<':
+		l.skipDict()
+		return pdfToken{kind: pdfOtherToken}, true
+	case c == '<':
+		s, end := pdfString(l.data, l.pos)
+		l.pos = end
+		return pdfToken{kind: pdfStringToken, str: s}, true
+	case c == '[':
+		if l.depth >= maxPDFNesting {
+			l.err = errPDFTooDeep
+			return pdfToken{}, false
+		}
+		l.depth++
+		defer func() { l.depth-- }()
+		l.pos++
+		tok := pdfToken{kind: pdfArrayToken}
+		for {
+			l.skipSpace()
+			if l.pos >= len(l.data) {
+				return tok, true
+			}
+			if l.data[l.pos] == ']' {
+				l.pos++
+				return tok, true
+			}
+			el, ok := l.next()
+			if l.err != nil {
+				return pdfToken{}, false
+			}
+			if !ok {
+				return tok, true
+			}
+			tok.array = append(tok.array, el)
+		}
+	case c == '/':
+		l.pos++
+		l.word()
+		return pdfToken{kind: pdfOtherToken}, true
+	case c == ']' || c == ')' || c == '>' || c == '{' || c == '}':
+		l.pos++
+		return pdfToken{kind: pdfOtherToken}, true
+	}
+	w := l.word()
+	if w == "" {
+		l.pos++
+		return pdfToken{kind: pdfOtherToken}, true
+	}
+	if n, err := strconv.ParseFloat(w, 64); err == nil {
+		return pdfToken{kind: pdfNumberToken, num: n}, true
+	}
+	if w == "true" || w == "false" || w == "null" {
+		return pdfToken{kind: pdfOtherToken}, true
+	}
+	return pdfToken{kind: pdfOperator, op: w}, true
+}
+
+func (l *pdfLexer) peek(offset int) byte {
+	if l.pos+offset < len(l.data) {
+		return l.data[l.pos+offset]
+	}
+	return 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		switch c := l.data[l.pos]; {
+		case isPDFSpace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// word reads a run of regular characters: a number, operator or name.
+func (l *pdfLexer) word() string {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+// skipDict skips a << >> dictionary, such as marked-content properties.
+func (l *pdfLexer) skipDict() {
+	depth := 0
+	for l.pos < len(l.data) {
+		switch {
+		case l.data[l.pos] == '(':
+			_, l.pos = pdfString(l.data, l.pos)
+			continue
+		case l.data[l.pos] == '<' && l.peek(1) == '<':
+			depth++
+			l.pos += 2
+			continue
+		case l.data[l.pos] == '>' && l.peek(1) == '>':
+			depth--
+			l.pos += 2
+			if depth == 0 {
+				return
+			}
+			continue
+		}
+		l.pos++
+	}
+}
+
+// skipInlineImage skips the binary data of an inline image, up to its EI.
+func (l *pdfLexer) skipInlineImage() {
+	for l.pos+2 < len(l.data) {
+		if isPDFSpace(l.data[l.pos]) && l.data[l.pos+1] == 'E' && l.data[l.pos+2] == 'I' &&
+			(l.pos+3 == len(l.data) || isPDFSpace(l.data[l.pos+3])) {
+			l.pos += 3
+			return
+		}
+		l.pos++
+	}
+	l.pos = len(l.data)
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+// pdfString decodes the literal (…) or hex <…> string starting at start
+// and returns its bytes and the offset after it.
+func pdfString(data []byte, start int) ([]byte, int) {
+	if start >= len(data) {
+		return nil, start
+	}
+	if data[start] == '<' {
+		end := bytes.IndexByte(data[start:], '>')
+		if end < 0 {
+			end = len(data) - start
+		}
+		var digits []byte
+		for _, c := range data[start+1 : start+end] {
+			if v, ok := hexDigit(c); ok {
+				digits = append(digits, v)
+			}
+		}
+		if len(digits)%2 == 1 {
+			digits = append(digits, 0)
+		}
+		out := make([]byte, len(digits)/2)
+		for i := range out {
+			out[i] = digits[2*i]<<4 | digits[2*i+1]
+		}
+		return out, min(start+end+1, len(data))
+	}
+
+	var out []byte
+	depth := 0
+	for i := start; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '(':
+			if depth > 0 {
+				out = append(out, c)
+			}
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return out, i + 1
+			}
+			out = append(out, c)
+		case c == '\\' && i+1 < len(data):
+			i++
+			switch e := data[i]; e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				// A backslash before a line break continues the line
+				if i+1 < len(data) && data[i+1] == '\n' {
+					i++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for n := 1; n < 3 && i+1 < len(data) && data[i+1] >= '0' && data[i+1] <= '7'; n++ {
+						i++
+						v = v*8 + int(data[i]-'0')
+					}
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+	return out, len(data)
+}
+
+func hexDigit(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// winAnsiPunctuation maps the WinAnsiEncoding bytes 0x80-0x9F that most
+// single-byte fonts use for typographic punctuation.
+var winAnsiPunctuation = map[byte]rune{
+	0x80: '€', 0x85: '…', 0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”',
+	0x95: '•', 0x96: '–', 0x97: '—', 0x99: '™',
+}
+
+// decodePDFText decodes a text string: UTF-16 with a byte order mark, else
+// a single-byte encoding read as Latin-1 plus WinAnsi punctuation.
+func decodePDFText(s []byte) string {
+	if len(s) >= 2 && s[0] == 0xFE && s[1] == 0xFF {
+		units := make([]uint16, 0, len(s)/2)
+		for i := 2; i+1 < len(s); i += 2 {
+			units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+	if utf8.Valid(s) && bytes.HasPrefix(s, []byte("\xEF\xBB\xBF")) {
+		return string(s[3:])
+	}
+	var b strings.Builder
+	for _, c := range s {
+		if r, ok := winAnsiPunctuation[c]; ok {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.String()
+}
+
+// readablePDFText decodes a shown string, rejecting strings of glyph IDs
+// from fonts this extractor cannot map to text. Those decode to control
+// characters rather than letters.
+func readablePDFText(s []byte) (string, bool) {
+	text := decodePDFText(s)
+	control := 0
+	for _, r := range text {
+		if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+			control++
+		}
+	}
+	if control*4 > len(s) {
+		return "", false
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, text), true
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// domainTally accumulates one domain's predictions in a period.
+type domainTally struct {
+	count int
+	fake  int
+}
+
+func (t domainTally) fakeRatio() float64 {
+	if t.count == 0 {
+		return 0
+	}
+	return float64(t.fake) / float64(t.count)
+}
+
+// ComparePeriods summarizes the predictions matching filter in periods a
+// and b and computes b's change from a, with the top source domains of
+// each. filter's date range, ordering and paging are ignored.
+func (s *NewsService) ComparePeriods(ctx context.Context, filter domain.PredictionQuery, a, b domain.Period, top int) (*domain.PeriodComparison, error) {
+	if top <= 0 || top > domain.MaxCompareTopDomains {
+		return nil, fmt.Errorf("%w: top must be between 1 and %d", domain.ErrInvalidQuery, domain.MaxCompareTopDomains)
+	}
+	for _, p := range []domain.Period{a, b} {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	statsA, domainsA, err := s.periodStats(ctx, filter, a, top)
+	if err != nil {
+		return nil, err
+	}
+	statsB, domainsB, err := s.periodStats(ctx, filter, b, top)
+	if err != nil {
+		return nil, err
+	}
+
+	cmp := &domain.PeriodComparison{
+		A: *statsA,
+		B: *statsB,
+		Delta: domain.PeriodDelta{
+			Count:         statsB.Count - statsA.Count,
+			FakeRatio:     statsB.FakeRatio - statsA.FakeRatio,
+			AvgConfidence: statsB.AvgConfidence - statsA.AvgConfidence,
+			Domains:       []domain.DomainDelta{},
+		},
+	}
+	cmp.Delta.AvgUncertainty = statsB.AvgUncertainty - statsA.AvgUncertainty
+	if statsA.Count > 0 {
+		change := float64(statsB.Count-statsA.Count) / float64(statsA.Count)
+		cmp.Delta.CountChange = &change
+	}
+
+	seen := make(map[string]bool)
+	for _, list := range [][]domain.DomainCount{statsA.TopDomains, statsB.TopDomains} {
+		for _, d := range list {
+			if seen[d.Domain] {
+				continue
+			}
+			seen[d.Domain] = true
+			ta, tb := domainsA[d.Domain], domainsB[d.Domain]
+			cmp.Delta.Domains = append(cmp.Delta.Domains, domain.DomainDelta{
+				Domain:     d.Domain,
+				CountA:     ta.count,
+				CountB:     tb.count,
+				CountDelta: tb.count - ta.count,
+				FakeRatioA: ta.fakeRatio(),
+				FakeRatioB: tb.fakeRatio(),
+			})
+		}
+	}
+	// Biggest movers first.
+	sort.Slice(cmp.Delta.Domains, func(i, j int) bool {
+		di, dj := cmp.Delta.Domains[i], cmp.Delta.Domains[j]
+		if abs(di.CountDelta) != abs(dj.CountDelta) {
+			return abs(di.CountDelta) > abs(dj.CountDelta)
+		}
+		return di.Domain < dj.Domain
+	})
+	return cmp, nil
+}
+
+// periodStats summarizes one period and returns every domain's tally.
+func (s *NewsService) periodStats(ctx context.Context, filter domain.PredictionQuery, period domain.Period, top int) (*domain.PeriodStats, map[string]domainTally, error) {
+	q := filter
+	q.Since, q.Until = period.Since, period.Until
+	q.Limit, q.Offset = 0, 0
+	predictions, err := s.repository.Query(ctx, q)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load predictions: %w", err)
+	}
+
+	stats := &domain.PeriodStats{Period: domain.Period{Since: period.Since.UTC(), Until: period.Until.UTC()}, TopDomains: []domain.DomainCount{}}
+	domains := make(map[string]domainTally)
+	var confidence, stdDev float64
+	uncertain := 0
+	for _, p := range predictions {
+		stats.Count++
+		confidence += p.Confidence
+		if p.Uncertainty != nil {
+			stdDev += p.Uncertainty.StdDev
+			uncertain++
+		}
+		fake := p.Result == domain.LabelFake
+		if fake {
+			stats.FakeCount++
+		}
+		if source := strings.TrimPrefix(strings.ToLower(p.ArticleSource), "www."); source != "" {
+			t := domains[source]
+			t.count++
+			if fake {
+				t.fake++
+			}
+			domains[source] = t
+		}
+	}
+	if stats.Count > 0 {
+		stats.FakeRatio = float64(stats.FakeCount) / float64(stats.Count)
+		stats.AvgConfidence = confidence / float64(stats.Count)
+	}
+	if uncertain > 0 {
+		stats.AvgUncertainty = stdDev / float64(uncertain)
+	}
+
+	for name, t := range domains {
+		stats.TopDomains = append(stats.TopDomains, domain.DomainCount{Domain: name, Count: t.count, FakeRatio: t.fakeRatio()})
+	}
+	sort.Slice(stats.TopDomains, func(i, j int) bool {
+		if stats.TopDomains[i].Count != stats.TopDomains[j].Count {
+			return stats.TopDomains[i].Count > stats.TopDomains[j].Count
+		}
+		return stats.TopDomains[i].Domain < stats.TopDomains[j].Domain
+	})
+	if len(stats.TopDomains) > top {
+		stats.TopDomains = stats.TopDomains[:top]
+	}
+	return stats, domains, nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
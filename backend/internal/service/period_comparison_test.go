@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestComparePeriods(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	weekBefore := domain.Period{Since: time.Date(2024, 5, 27, 0, 0, 0, 0, time.UTC), Until: time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)}
+	election := domain.Period{Since: weekBefore.Until, Until: weekBefore.Until.AddDate(0, 0, 7)}
+	n := 0
+	add := func(at time.Time, source, result string, confidence float64) {
+		t.Helper()
+		n++
+		p := &domain.Prediction{ID: fmt.Sprintf("p-%d", n), Result: result, Confidence: confidence,
+			RequestType: "url", ArticleSource: source, CreatedAt: at}
+		if err := repo.CreatePrediction(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Week before: 4 predictions, one FAKE.
+	add(weekBefore.Since.Add(time.Hour), "www.wire.example", domain.LabelReal, 0.9)
+	add(weekBefore.Since.Add(2*time.Hour), "wire.example", domain.LabelReal, 0.8)
+	add(weekBefore.Since.Add(3*time.Hour), "rumors.example", domain.LabelFake, 0.7)
+	add(weekBefore.Since.Add(4*time.Hour), "daily.example", domain.LabelReal, 0.6)
+	// Election week: 6 predictions, four FAKE, mostly from rumors.example.
+	for i := 0; i < 4; i++ {
+		add(election.Since.Add(time.Duration(i)*time.Hour), "rumors.example", domain.LabelFake, 0.6)
+	}
+	add(election.Since.Add(5*time.Hour), "wire.example", domain.LabelReal, 0.9)
+	add(election.Since.Add(6*time.Hour), "", domain.LabelReal, 0.9)
+	// Outside both ranges.
+	add(election.Until, "rumors.example", domain.LabelFake, 0.9)
+
+	news := NewNewsService(nil, nil, repo)
+	cmp, err := news.ComparePeriods(context.Background(), *domain.NewPredictionQuery(), weekBefore, election, 2)
+	if err != nil {
+		t.Fatalf("ComparePeriods: %v", err)
+	}
+	if cmp.A.Count != 4 || cmp.A.FakeRatio != 0.25 || cmp.B.Count != 6 || cmp.B.FakeCount != 4 {
+		t.Errorf("a = %+v, b = %+v", cmp.A, cmp.B)
+	}
+	if len(cmp.A.TopDomains) != 2 || cmp.A.TopDomains[0] != (domain.DomainCount{Domain: "wire.example", Count: 2}) {
+		t.Errorf("a top domains = %+v", cmp.A.TopDomains)
+	}
+	if cmp.Delta.Count != 2 || cmp.Delta.CountChange == nil || *cmp.Delta.CountChange != 0.5 {
+		t.Errorf("count delta = %d, change = %v", cmp.Delta.Count, cmp.Delta.CountChange)
+	}
+	if d := cmp.Delta.FakeRatio - (4.0/6 - 0.25); d > 1e-9 || d < -1e-9 {
+		t.Errorf("fake ratio delta = %v", cmp.Delta.FakeRatio)
+	}
+	// Both top lists together name three domains; rumors.example moved most.
+	if len(cmp.Delta.Domains) != 3 || cmp.Delta.Domains[0].Domain != "rumors.example" ||
+		cmp.Delta.Domains[0].CountDelta != 3 || cmp.Delta.Domains[2] != (domain.DomainDelta{Domain: "wire.example", CountA: 2, CountB: 1, CountDelta: -1}) {
+		t.Errorf("domain deltas = %+v", cmp.Delta.Domains)
+	}
+
+	empty := domain.Period{Since: election.Until.AddDate(1, 0, 0), Until: election.Until.AddDate(1, 0, 7)}
+	cmp, err = news.ComparePeriods(context.Background(), *domain.NewPredictionQuery(), empty, election, 10)
+	if err != nil || cmp.Delta.CountChange != nil {
+		t.Errorf("comparing with an empty range: err = %v, count change = %v", err, cmp.Delta.CountChange)
+	}
+
+	backwards := domain.Period{Since: election.Until, Until: election.Since}
+	if _, err := news.ComparePeriods(context.Background(), *domain.NewPredictionQuery(), backwards, election, 10); !errors.Is(err, domain.ErrInvalidQuery) {
+		t.Errorf("backwards range: err = %v, want ErrInvalidQuery", err)
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// PreferencesService stores each user's default analysis options.
+type PreferencesService struct {
+	repo repository.PreferencesRepository
+}
+
+// NewPreferencesService creates a preferences service.
+func NewPreferencesService(repo repository.PreferencesRepository) *PreferencesService {
+	return &PreferencesService{repo: repo}
+}
+
+// Defaults returns the user's saved options; users who saved none get
+// empty defaults.
+func (s *PreferencesService) Defaults(ctx context.Context, userID string) (*domain.AnalysisDefaults, error) {
+	defaults, err := s.repo.Get(ctx, userID)
+	if errors.Is(err, domain.ErrPreferencesNotFound) {
+		return &domain.AnalysisDefaults{}, nil
+	}
+	return defaults, err
+}
+
+// SetDefaults validates and replaces the user's saved options.
+func (s *PreferencesService) SetDefaults(ctx context.Context, userID string, defaults *domain.AnalysisDefaults) (*domain.AnalysisDefaults, error) {
+	if err := defaults.Validate(); err != nil {
+		return nil, err
+	}
+	defaults.UpdatedAt = time.Now()
+	if err := s.repo.Save(ctx, userID, defaults); err != nil {
+		return nil, err
+	}
+	return defaults, nil
+}
+
+// ClearDefaults removes the user's saved options.
+func (s *PreferencesService) ClearDefaults(ctx context.Context, userID string) error {
+	err := s.repo.Delete(ctx, userID)
+	if errors.Is(err, domain.ErrPreferencesNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestAnalyzeRecordsProvenance(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(MLPredictionResponse{Result: "FAKE", Confidence: 0.8, FakeProbability: 0.8, RealProbability: 0.2, ModelVersion: "m-7"})
+	}))
+	defer srv.Close()
+
+	fusion := NewVerdictFusion().WithSignal(MLSignal{}, 0.9).WithSignal(HeuristicSignal{}, 0.1)
+	svc := NewNewsService(NewMLClient(srv.URL), nil, memory.NewPredictionRepository()).
+		WithVerdictFusion(fusion).
+		WithTranslation(NewTranslationBridge(&fakeTranslator{}, []string{"en"}))
+
+	p, err := svc.AnalyzeNews(context.Background(), &domain.AnalysisRequest{Type: "text", Content: "सरकार ने नए बजट की घोषणा की"})
+	if err != nil {
+		t.Fatalf("AnalyzeNews: %v", err)
+	}
+	got := p.Provenance
+	if got == nil {
+		t.Fatal("prediction has no provenance")
+	}
+	if got.ModelEndpoint != srv.URL+"/predict" || got.ModelVersion != "m-7" {
+		t.Errorf("model = %s %s, want %s/predict m-7", got.ModelEndpoint, got.ModelVersion, srv.URL)
+	}
+	if got.Truncation != p.TruncationStrategy || got.Truncation == "" {
+		t.Errorf("truncation = %q, want %q", got.Truncation, p.TruncationStrategy)
+	}
+	if !reflect.DeepEqual(got.Cleaning, []string{"translate-from-hi"}) {
+		t.Errorf("cleaning = %v, want the translation step", got.Cleaning)
+	}
+	wantWeights := map[string]float64{domain.SignalML: 0.9, domain.SignalHeuristic: 0.1}
+	if !reflect.DeepEqual(got.FusionWeights, wantWeights) || got.FakeThreshold != domain.DefaultFakeThreshold {
+		t.Errorf("fusion = %v at %v, want %v at %v", got.FusionWeights, got.FakeThreshold, wantWeights, domain.DefaultFakeThreshold)
+	}
+	if got.ScraperVersion != "" || len(got.CacheHits) != 0 {
+		t.Errorf("text analysis recorded scraper %q and cache hits %v", got.ScraperVersion, got.CacheHits)
+	}
+}
+
+func TestReusedPredictionRecordsCacheHits(t *testing.T) {
+	stored := &domain.Prediction{ID: "p1", Provenance: &domain.Provenance{ModelVersion: "m-7"}}
+
+	reused := reusedPrediction(stored, true, false)
+	want := []string{domain.CacheStoredPrediction, domain.CacheStoredSummary}
+	if !reflect.DeepEqual(reused.Provenance.CacheHits, want) {
+		t.Errorf("cache hits = %v, want %v", reused.Provenance.CacheHits, want)
+	}
+	if reused.Provenance.ModelVersion != "m-7" {
+		t.Errorf("model version = %q, want the stored one", reused.Provenance.ModelVersion)
+	}
+	if stored.Provenance.CacheHits != nil {
+		t.Errorf("stored prediction was modified: %v", stored.Provenance.CacheHits)
+	}
+
+	// Predictions stored before provenance was recorded still report hits.
+	legacy := reusedPrediction(&domain.Prediction{ID: "p2"}, false, false)
+	if !reflect.DeepEqual(legacy.Provenance.CacheHits, []string{domain.CacheStoredPrediction}) {
+		t.Errorf("legacy cache hits = %v", legacy.Provenance.CacheHits)
+	}
+}
+
+func TestExtractArticleBodyNamesExtractor(t *testing.T) {
+	paragraph := "<p>" + strings.Repeat("Officials confirmed the figures on Tuesday. ", 3) + "</p>"
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"article tag", "<article>" + strings.Repeat(paragraph, 3) + "</article>", "article-tag"},
+		{"dense container", "<div>" + strings.Repeat(paragraph, 3) + "</div>", "paragraph-density"},
+		{"bare paragraphs", paragraph, "all-paragraphs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><body>" + tt.html + "</body></html>"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, got := extractArticleBody(doc); got != tt.want {
+				t.Errorf("extractor = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeSummaryKeepsPinMadeMeanwhile(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><head><title>Harbor reopens</title></head><body><article>
+<p>The city harbor reopened on Monday after three months of repairs to the breakwater, officials said.</p>
+<p>Fishing crews returned to the docks at dawn, and the first ferry left on schedule at nine.</p>
+</article></body></html>`))
+	}))
+	defer site.Close()
+	scraper := NewScraperService()
+	scraper.httpClient = &http.Client{Transport: rewriteTransport{target: site.URL}}
+
+	repo := memory.NewPredictionRepository()
+	stored := &domain.Prediction{
+		ID: "stored", RequestType: "url", CanonicalURL: NormalizeURL(site.URL + "/2024/harbor-reopens"),
+		Result: domain.LabelReal, Confidence: 0.8, Method: domain.MethodModel,
+	}
+	if err := repo.CreatePrediction(stored); err != nil {
+		t.Fatal(err)
+	}
+	ml := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Someone pins the prediction while its summary is generated
+		pinned := *stored
+		pinned.Pinned, pinned.PinnedBy = true, "ada"
+		if err := repo.UpdatePrediction(&pinned); err != nil {
+			t.Error(err)
+		}
+		json.NewEncoder(w).Encode(MLSummarizeResponse{Summary: "The harbor reopened."})
+	}))
+	defer ml.Close()
+	svc := NewNewsService(NewMLClient(ml.URL), scraper, repo)
+
+	p, err := svc.AnalyzeNews(context.Background(), &domain.AnalysisRequest{
+		Type: "url", Content: "http://harbor.example/2024/harbor-reopens", IncludeSummary: true,
+	})
+	if err != nil {
+		t.Fatalf("AnalyzeNews: %v", err)
+	}
+	if p.ID != "stored" || p.Summary != "The harbor reopened." {
+		t.Errorf("prediction = %+v; want the stored verdict with the new summary", p)
+	}
+	got, _ := repo.GetPredictionByID("stored")
+	if !got.Pinned || got.PinnedBy != "ada" || got.Summary != "The harbor reopened." {
+		t.Errorf("stored = pinned %v by %q, summary %q; want the pin kept and the summary saved", got.Pinned, got.PinnedBy, got.Summary)
+	}
+	if stored.Summary != "" {
+		t.Error("the prediction handed out by the repository was modified in place")
+	}
+}
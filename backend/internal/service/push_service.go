@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+	"github.com/google/uuid"
+)
+
+// PushService manages Web Push subscriptions and delivers notifications
+type PushService struct {
+	repo     repository.PushSubscriptionRepository
+	sender   *WebPushSender
+	delivery *DeliveryEngine
+}
+
+// NewPushService creates a new push service
+func NewPushService(repo repository.PushSubscriptionRepository, sender *WebPushSender) *PushService {
+	return &PushService{repo: repo, sender: sender}
+}
+
+// WithDelivery retries notifications through engine instead of sending
+// each once.
+func (s *PushService) WithDelivery(engine *DeliveryEngine) *PushService {
+	s.delivery = engine
+	return s
+}
+
+// PublicKey returns the VAPID public key the frontend subscribes with
+func (s *PushService) PublicKey() string {
+	return s.sender.PublicKey()
+}
+
+// Subscribe stores a subscription for a user. The endpoint must be a
+// public https URL, since notifications are posted to it.
+func (s *PushService) Subscribe(ctx context.Context, sub *domain.PushSubscription) error {
+	if err := sub.Validate(); err != nil {
+		return err
+	}
+	u, _ := url.Parse(sub.Endpoint)
+	if err := checkPublicHost(u.Hostname()); err != nil {
+		return fmt.Errorf("endpoint: %v", err)
+	}
+	if sub.ID == "" {
+		sub.ID = uuid.New().String()
+	}
+	sub.CreatedAt = time.Now()
+	return s.repo.Save(ctx, sub)
+}
+
+// Unsubscribe removes a subscription by endpoint. Unless userID is empty,
+// as it is for admins, it must be one of userID's subscriptions; another
+// user's is reported as not found, so callers cannot probe for endpoints.
+func (s *PushService) Unsubscribe(ctx context.Context, endpoint, userID string) error {
+	sub, err := s.repo.GetByEndpoint(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if userID != "" && sub.UserID != userID {
+		return domain.ErrPushSubscriptionNotFound
+	}
+	return s.repo.DeleteByEndpoint(ctx, endpoint)
+}
+
+// NotifyUser sends a notification to every subscription of a user.
+// Expired subscriptions are removed; other failures are returned joined,
+// or retried by the delivery engine when there is one.
+func (s *PushService) NotifyUser(ctx context.Context, userID string, notification *domain.PushNotification) error {
+	subs, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, sub := range subs {
+		send := func(ctx context.Context) error {
+			err := s.sender.Send(sub, notification)
+			if errors.Is(err, ErrPushSubscriptionGone) {
+				s.repo.DeleteByEndpoint(ctx, sub.Endpoint)
+				return nil
+			}
+			return err
+		}
+		if err := s.delivery.Send(ctx, ChannelPush, deliveryDestination(sub.Endpoint), send); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotifyAnalysisComplete tells a user that an analysis has finished
+func (s *PushService) NotifyAnalysisComplete(ctx context.Context, userID string, prediction *domain.Prediction) {
+	title := prediction.ArticleTitle
+	if title == "" {
+		title = "Your analysis is ready"
+	}
+	notification := &domain.PushNotification{
+		Title: title,
+		Body:  fmt.Sprintf("Verdict: %s (%.0f%% confidence)", prediction.Result, prediction.Confidence*100),
+		URL:   "/result/" + prediction.ID,
+		Tag:   "prediction-" + prediction.ID,
+		Data:  map[string]string{"prediction_id": prediction.ID},
+	}
+	if err := s.NotifyUser(ctx, userID, notification); err != nil {
+		log.Printf("Warning: push notification failed for user %s: %v", userID, err)
+	}
+}
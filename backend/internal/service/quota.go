@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+type quotaKey struct{}
+
+type quotaSubject struct {
+	id   string
+	plan string
+}
+
+// ContextWithQuota records who the scrapes and inferences made for a
+// request are charged to: an organization, API key or user, on plan.
+// Work without a quota subject, such as background crawls, is not metered.
+func ContextWithQuota(ctx context.Context, subject, plan string) context.Context {
+	return context.WithValue(ctx, quotaKey{}, quotaSubject{id: subject, plan: plan})
+}
+
+func quotaFromContext(ctx context.Context) (quotaSubject, bool) {
+	subject, ok := ctx.Value(quotaKey{}).(quotaSubject)
+	return subject, ok && subject.id != ""
+}
+
+// QuotaTracker meters scrapes and ML inferences per subject and UTC day,
+// each against its own plan limit. Counts are kept in memory and start
+// over at midnight UTC.
+type QuotaTracker struct {
+	mu     sync.Mutex
+	limits map[string]map[string]int // dimension -> plan -> overridden limit
+	day    time.Time
+	used   map[string]map[string]int // subject -> dimension -> count today
+	now    func() time.Time
+
+	onThreshold func(subject string, crossing domain.QuotaCrossing)
+}
+
+// NewQuotaTracker creates a tracker enforcing the plans' default quotas.
+func NewQuotaTracker() *QuotaTracker {
+	return &QuotaTracker{
+		limits: make(map[string]map[string]int),
+		used:   make(map[string]map[string]int),
+		now:    time.Now,
+	}
+}
+
+// WithLimit overrides a plan's daily limit for one dimension (0 =
+// unlimited). Negative limits are ignored.
+func (t *QuotaTracker) WithLimit(dimension, plan string, limit int) *QuotaTracker {
+	if limit < 0 || !domain.IsValidQuota(dimension) {
+		return t
+	}
+	if t.limits[dimension] == nil {
+		t.limits[dimension] = make(map[string]int)
+	}
+	t.limits[dimension][plan] = limit
+	return t
+}
+
+// OnThreshold calls fn, outside the tracker's lock, whenever a subject's
+// use of a limited dimension reaches one of domain.QuotaThresholds.
+func (t *QuotaTracker) OnThreshold(fn func(subject string, crossing domain.QuotaCrossing)) *QuotaTracker {
+	t.onThreshold = fn
+	return t
+}
+
+// Limit returns a plan's daily limit for a dimension (0 = unlimited).
+// Unknown or empty plans get the free limit.
+func (t *QuotaTracker) Limit(dimension, plan string) int {
+	plan = domain.NormalizePlan(plan)
+	if limit, ok := t.limits[dimension][plan]; ok {
+		return limit
+	}
+	return domain.DailyQuota(dimension, plan)
+}
+
+// Consume charges n operations of dimension to the context's quota
+// subject. Nothing is charged when they would exceed the subject's limit,
+// and the error wraps domain.ErrQuotaExceeded.
+func (t *QuotaTracker) Consume(ctx context.Context, dimension string, n int) error {
+	if t == nil || n <= 0 {
+		return nil
+	}
+	subject, ok := quotaFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	limit := t.Limit(dimension, subject.plan)
+
+	t.mu.Lock()
+	t.rollover()
+	used := t.used[subject.id]
+	if limit > 0 && used[dimension]+n > limit {
+		t.mu.Unlock()
+		return fmt.Errorf("%w: %d %s per day on the %s plan", domain.ErrQuotaExceeded, limit, dimension, domain.NormalizePlan(subject.plan))
+	}
+	if used == nil {
+		used = make(map[string]int)
+		t.used[subject.id] = used
+	}
+	before := used[dimension]
+	used[dimension] += n
+	after, resetsAt := used[dimension], t.day.AddDate(0, 0, 1)
+	t.mu.Unlock()
+
+	if t.onThreshold == nil || limit == 0 {
+		return nil
+	}
+	for _, threshold := range domain.QuotaThresholds {
+		// Reached with this charge: before < threshold% of limit <= after
+		if before*100 < threshold*limit && after*100 >= threshold*limit {
+			t.onThreshold(subject.id, domain.QuotaCrossing{
+				Dimension: dimension,
+				Plan:      domain.NormalizePlan(subject.plan),
+				Threshold: threshold,
+				Used:      after,
+				Limit:     limit,
+				ResetsAt:  resetsAt,
+			})
+		}
+	}
+	return nil
+}
+
+// Usage returns subject's use of every dimension today.
+func (t *QuotaTracker) Usage(subject, plan string) map[string]domain.QuotaUsage {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	t.rollover()
+	used := t.used[subject]
+	resetsAt := t.day.AddDate(0, 0, 1)
+	usage := map[string]domain.QuotaUsage{}
+	for _, dimension := range []string{domain.QuotaScrapes, domain.QuotaInferences} {
+		usage[dimension] = domain.QuotaUsage{Used: used[dimension], Limit: t.Limit(dimension, plan), ResetsAt: resetsAt}
+	}
+	t.mu.Unlock()
+	return usage
+}
+
+// rollover forgets yesterday's counts. Callers must hold t.mu.
+func (t *QuotaTracker) rollover() {
+	today := t.now().UTC().Truncate(24 * time.Hour)
+	if !today.Equal(t.day) {
+		t.day = today
+		t.used = make(map[string]map[string]int)
+	}
+}
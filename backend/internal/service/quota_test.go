@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestQuotaTracker(t *testing.T) {
+	now := time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC)
+	quotas := NewQuotaTracker().WithLimit(domain.QuotaScrapes, domain.PlanFree, 3)
+	quotas.now = func() time.Time { return now }
+	acme := ContextWithQuota(context.Background(), "org:acme", "")
+
+	if err := quotas.Consume(acme, domain.QuotaScrapes, 2); err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	if err := quotas.Consume(acme, domain.QuotaScrapes, 2); !errors.Is(err, domain.ErrQuotaExceeded) {
+		t.Fatalf("over quota err = %v, want ErrQuotaExceeded", err)
+	}
+	// A rejected charge is not counted, and dimensions are independent
+	if err := quotas.Consume(acme, domain.QuotaScrapes, 1); err != nil {
+		t.Fatalf("last scrape: %v", err)
+	}
+	if err := quotas.Consume(acme, domain.QuotaInferences, 50); err != nil {
+		t.Fatalf("inferences: %v", err)
+	}
+	// Other subjects and unmetered work are unaffected
+	if err := quotas.Consume(ContextWithQuota(context.Background(), "org:globex", ""), domain.QuotaScrapes, 3); err != nil {
+		t.Errorf("other subject: %v", err)
+	}
+	if err := quotas.Consume(context.Background(), domain.QuotaScrapes, 100); err != nil {
+		t.Errorf("unmetered: %v", err)
+	}
+
+	usage := quotas.Usage("org:acme", "")
+	want := domain.QuotaUsage{Used: 3, Limit: 3, ResetsAt: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)}
+	if usage[domain.QuotaScrapes] != want {
+		t.Errorf("scrapes = %+v, want %+v", usage[domain.QuotaScrapes], want)
+	}
+	if got := usage[domain.QuotaInferences]; got.Used != 50 || got.Limit != domain.DailyQuota(domain.QuotaInferences, domain.PlanFree) {
+		t.Errorf("inferences = %+v", got)
+	}
+	if got := quotas.Usage("org:acme", domain.PlanEnterprise)[domain.QuotaScrapes]; got.Limit != 0 {
+		t.Errorf("enterprise limit = %d, want unlimited", got.Limit)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if got := quotas.Usage("org:acme", "")[domain.QuotaScrapes].Used; got != 0 {
+		t.Errorf("used after midnight = %d, want 0", got)
+	}
+}
+
+func TestAnalyzeNewsChargesQuotas(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		json.NewEncoder(w).Encode(MLPredictionResponse{Result: "REAL", Confidence: 0.8, FakeProbability: 0.2, RealProbability: 0.8})
+	}))
+	defer srv.Close()
+
+	quotas := NewQuotaTracker().
+		WithLimit(domain.QuotaInferences, domain.PlanFree, 1).
+		WithLimit(domain.QuotaScrapes, domain.PlanFree, 1)
+	scraper := NewScraperService().WithQuotas(quotas)
+	svc := NewNewsService(NewMLClient(srv.URL), scraper, memory.NewPredictionRepository()).WithQuotas(quotas)
+	ctx := ContextWithQuota(context.Background(), "key:bulk", domain.PlanFree)
+	text := &domain.AnalysisRequest{Type: "text", Content: "The council approved the new budget on Tuesday."}
+
+	if _, err := svc.AnalyzeNews(ctx, text); err != nil {
+		t.Fatalf("first analysis: %v", err)
+	}
+	if _, err := svc.AnalyzeNews(ctx, text); !errors.Is(err, domain.ErrQuotaExceeded) {
+		t.Fatalf("second analysis err = %v, want ErrQuotaExceeded", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("ML calls = %d, want 1", calls.Load())
+	}
+
+	// The scrape quota is separate: the failed fetch is charged, and the
+	// next URL is refused before reaching the ML service's scraper.
+	url := &domain.AnalysisRequest{Type: "url", Content: "http://127.0.0.1:1/article"}
+	if _, err := svc.AnalyzeNews(ctx, url); errors.Is(err, domain.ErrQuotaExceeded) {
+		t.Fatalf("first scrape refused: %v", err)
+	}
+	if _, err := svc.AnalyzeNews(ctx, url); !errors.Is(err, domain.ErrQuotaExceeded) {
+		t.Fatalf("second scrape err = %v, want ErrQuotaExceeded", err)
+	}
+	if got := quotas.Usage("key:bulk", domain.PlanFree)[domain.QuotaScrapes].Used; got != 1 {
+		t.Errorf("scrapes used = %d, want 1", got)
+	}
+}
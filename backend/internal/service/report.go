@@ -0,0 +1,351 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+)
+
+// Report defaults
+const (
+	DefaultReportHour    = 2  // UTC hour the previous day's report is generated
+	reportTopDomains     = 10 // domains listed per report
+	reportMaxClusters    = 10 // clusters listed per report
+	reportClusterMinimum = 2  // analyses of one story that make a cluster
+)
+
+// ReportDelivery sends a finished report somewhere people will read it.
+type ReportDelivery interface {
+	Deliver(ctx context.Context, report *domain.Report, html []byte) error
+}
+
+// WebhookReportDelivery posts reports as JSON, with the rendered HTML
+// under "html", to a webhook such as an email digest bridge.
+type WebhookReportDelivery struct {
+	url        string
+	httpClient *http.Client
+	delivery   *DeliveryEngine
+}
+
+// NewWebhookReportDelivery creates a webhook delivery.
+func NewWebhookReportDelivery(url string) *WebhookReportDelivery {
+	return &WebhookReportDelivery{
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// WithOutboundAudit records webhook posts in audit.
+func (d *WebhookReportDelivery) WithOutboundAudit(audit *OutboundAudit) *WebhookReportDelivery {
+	audit.Client(d.httpClient, PurposeWebhook)
+	return d
+}
+
+// WithDelivery retries reports through engine instead of posting each once.
+func (d *WebhookReportDelivery) WithDelivery(engine *DeliveryEngine) *WebhookReportDelivery {
+	d.delivery = engine
+	return d
+}
+
+// Deliver posts the report to the webhook.
+func (d *WebhookReportDelivery) Deliver(ctx context.Context, report *domain.Report, html []byte) error {
+	body, err := json.Marshal(map[string]interface{}{"report": report, "html": string(html)})
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+	return d.delivery.Send(ctx, ChannelReport, deliveryDestination(d.url), func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(body))
+		if err != nil {
+			return PermanentDeliveryError(fmt.Errorf("report delivery failed: %w", err))
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := d.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("report delivery failed: %w", err)
+		}
+		defer resp.Body.Close()
+		return deliveryStatusError("report delivery", resp.StatusCode)
+	})
+}
+
+// ReportService compiles nightly summaries of the previous UTC day's
+// analyses and stores them as JSON and HTML.
+type ReportService struct {
+	predictions NewsRepository
+	reports     repository.ReportRepository
+	evaluations repository.EvaluationRepository
+	deliveries  []ReportDelivery
+	now         func() time.Time
+}
+
+// NewReportService creates a report service.
+func NewReportService(predictions NewsRepository, reports repository.ReportRepository) *ReportService {
+	return &ReportService{predictions: predictions, reports: reports, now: time.Now}
+}
+
+// WithEvaluations includes benchmark runs completed each day in its report.
+func (s *ReportService) WithEvaluations(evaluations repository.EvaluationRepository) *ReportService {
+	s.evaluations = evaluations
+	return s
+}
+
+// WithDelivery sends every scheduled report to d as well as storing it.
+func (s *ReportService) WithDelivery(d ReportDelivery) *ReportService {
+	s.deliveries = append(s.deliveries, d)
+	return s
+}
+
+// List returns every stored report, newest first.
+func (s *ReportService) List(ctx context.Context) ([]*domain.Report, error) {
+	return s.reports.List(ctx)
+}
+
+// Get returns a stored report.
+func (s *ReportService) Get(ctx context.Context, id string) (*domain.Report, error) {
+	return s.reports.GetByID(ctx, id)
+}
+
+// HTML returns a stored report's rendered HTML.
+func (s *ReportService) HTML(ctx context.Context, id string) ([]byte, error) {
+	return s.reports.HTML(ctx, id)
+}
+
+// Generate compiles, renders and stores the report for the UTC day
+// containing day, replacing any earlier report for that day.
+func (s *ReportService) Generate(ctx context.Context, day time.Time) (*domain.Report, []byte, error) {
+	start := time.Date(day.UTC().Year(), day.UTC().Month(), day.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+	predictions, err := s.predictions.Query(ctx, *domain.NewPredictionQuery().WithDateRange(start, end))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	date := start.Format("2006-01-02")
+	report := &domain.Report{
+		ID:          domain.ReportID(date),
+		Date:        date,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		GeneratedAt: s.now(),
+		TopDomains:  []domain.ReportDomain{},
+		Models:      []domain.ReportModel{},
+		Clusters:    []domain.ReportCluster{},
+		Evaluations: []domain.ReportEvaluation{},
+	}
+	summarizePredictions(report, predictions)
+	if s.evaluations != nil {
+		runs, err := s.evaluations.List(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, run := range runs {
+			if run.Status != domain.EvaluationCompleted || run.Metrics == nil || run.CompletedAt == nil ||
+				run.CompletedAt.Before(start) || !run.CompletedAt.Before(end) {
+				continue
+			}
+			report.Evaluations = append(report.Evaluations, domain.ReportEvaluation{
+				ID:       run.ID,
+				Model:    run.Model,
+				Total:    run.Total,
+				Accuracy: run.Metrics.Accuracy,
+				F1:       run.Metrics.F1,
+			})
+		}
+	}
+
+	var html bytes.Buffer
+	if err := reportTemplate.Execute(&html, report); err != nil {
+		return nil, nil, fmt.Errorf("failed to render report: %w", err)
+	}
+	if err := s.reports.Save(ctx, report, html.Bytes()); err != nil {
+		return nil, nil, err
+	}
+	return report, html.Bytes(), nil
+}
+
+// Run generates the previous day's report at hour (UTC) every day until
+// ctx is cancelled, and hands it to every delivery.
+func (s *ReportService) Run(ctx context.Context, hour int) {
+	if hour < 0 || hour > 23 {
+		hour = DefaultReportHour
+	}
+	for {
+		now := s.now().UTC()
+		next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		if err := sleepContext(ctx, next.Sub(now)); err != nil {
+			return
+		}
+
+		report, html, err := s.Generate(ctx, next.AddDate(0, 0, -1))
+		if err != nil {
+			log.Printf("Warning: nightly report failed: %v", err)
+			continue
+		}
+		log.Printf("Generated report %s: %d analyses", report.ID, report.Total)
+		for _, d := range s.deliveries {
+			if err := d.Deliver(ctx, report, html); err != nil {
+				log.Printf("Warning: report %s delivery failed: %v", report.ID, err)
+			}
+		}
+	}
+}
+
+// summarizePredictions fills the report's volume, domain, model and
+// cluster sections.
+func summarizePredictions(report *domain.Report, predictions []*domain.Prediction) {
+	type tally struct {
+		count, fake int
+		confidence  float64
+		fallbacks   int
+		title       string
+	}
+	domains := make(map[string]*tally)
+	models := make(map[string]*tally)
+	clusters := make(map[string]*tally)
+	add := func(m map[string]*tally, key string, p *domain.Prediction) *tally {
+		t, ok := m[key]
+		if !ok {
+			t = &tally{}
+			m[key] = t
+		}
+		t.count++
+		t.confidence += p.Confidence
+		if p.Result == domain.LabelFake {
+			t.fake++
+		}
+		return t
+	}
+
+	var confidence float64
+	for _, p := range predictions {
+		report.Total++
+		confidence += p.Confidence
+		switch p.Result {
+		case domain.LabelFake:
+			report.Fake++
+		case domain.LabelReal:
+			report.Real++
+		}
+		if p.RequestType == "url" {
+			report.URLRequests++
+		} else {
+			report.TextRequests++
+		}
+
+		if source := normalizeDomain(p.ArticleSource); source != "" {
+			add(domains, source, p)
+		}
+		model := add(models, p.ModelVersion, p)
+		if p.FallbackModel != "" {
+			model.fallbacks++
+		}
+		if key := clusterKey(p); key != "" {
+			cluster := add(clusters, key, p)
+			if cluster.title == "" {
+				cluster.title = p.ArticleTitle
+			}
+		}
+	}
+	if report.Total > 0 {
+		report.FakeRatio = float64(report.Fake) / float64(report.Total)
+		report.AvgConfidence = confidence / float64(report.Total)
+	}
+
+	for name, t := range domains {
+		report.TopDomains = append(report.TopDomains, domain.ReportDomain{
+			Domain: name, Count: t.count, FakeRatio: float64(t.fake) / float64(t.count),
+		})
+	}
+	sort.Slice(report.TopDomains, func(i, j int) bool {
+		a, b := report.TopDomains[i], report.TopDomains[j]
+		return a.Count > b.Count || (a.Count == b.Count && a.Domain < b.Domain)
+	})
+	if len(report.TopDomains) > reportTopDomains {
+		report.TopDomains = report.TopDomains[:reportTopDomains]
+	}
+
+	for version, t := range models {
+		report.Models = append(report.Models, domain.ReportModel{
+			Version: version, Count: t.count, AvgConfidence: t.confidence / float64(t.count), Fallbacks: t.fallbacks,
+		})
+	}
+	sort.Slice(report.Models, func(i, j int) bool {
+		a, b := report.Models[i], report.Models[j]
+		return a.Count > b.Count || (a.Count == b.Count && a.Version < b.Version)
+	})
+
+	for key, t := range clusters {
+		if t.count < reportClusterMinimum {
+			continue
+		}
+		report.Clusters = append(report.Clusters, domain.ReportCluster{
+			Key: key, Title: t.title, Count: t.count, FakeRatio: float64(t.fake) / float64(t.count),
+		})
+	}
+	sort.Slice(report.Clusters, func(i, j int) bool {
+		a, b := report.Clusters[i], report.Clusters[j]
+		return a.Count > b.Count || (a.Count == b.Count && a.Key < b.Key)
+	})
+	if len(report.Clusters) > reportMaxClusters {
+		report.Clusters = report.Clusters[:reportMaxClusters]
+	}
+}
+
+// clusterKey identifies the story a prediction analyzed: its canonical
+// URL, or its title when there is none. Untitled text has no key.
+func clusterKey(p *domain.Prediction) string {
+	if p.CanonicalURL != "" {
+		return p.CanonicalURL
+	}
+	return strings.ToLower(strings.TrimSpace(p.ArticleTitle))
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(f float64) string { return fmt.Sprintf("%.1f%%", f*100) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Analysis report {{.Date}}</title></head>
+<body>
+<h1>Analysis report for {{.Date}}</h1>
+<p>{{.Total}} analyses ({{.URLRequests}} URLs, {{.TextRequests}} texts): {{.Fake}} fake, {{.Real}} real.
+Fake ratio {{percent .FakeRatio}}, average confidence {{percent .AvgConfidence}}.</p>
+
+<h2>Top domains</h2>
+{{if .TopDomains}}<table>
+<tr><th>Domain</th><th>Analyses</th><th>Fake ratio</th></tr>
+{{range .TopDomains}}<tr><td>{{.Domain}}</td><td>{{.Count}}</td><td>{{percent .FakeRatio}}</td></tr>
+{{end}}</table>{{else}}<p>No URL analyses.</p>{{end}}
+
+<h2>Models</h2>
+{{if .Models}}<table>
+<tr><th>Version</th><th>Analyses</th><th>Average confidence</th><th>Fallbacks</th></tr>
+{{range .Models}}<tr><td>{{.Version}}</td><td>{{.Count}}</td><td>{{percent .AvgConfidence}}</td><td>{{.Fallbacks}}</td></tr>
+{{end}}</table>{{else}}<p>No analyses.</p>{{end}}
+
+<h2>Benchmark evaluations</h2>
+{{if .Evaluations}}<table>
+<tr><th>Run</th><th>Model</th><th>Samples</th><th>Accuracy</th><th>F1</th></tr>
+{{range .Evaluations}}<tr><td>{{.ID}}</td><td>{{.Model}}</td><td>{{.Total}}</td><td>{{percent .Accuracy}}</td><td>{{percent .F1}}</td></tr>
+{{end}}</table>{{else}}<p>No evaluations completed.</p>{{end}}
+
+<h2>Notable stories</h2>
+{{if .Clusters}}<table>
+<tr><th>Story</th><th>Analyses</th><th>Fake ratio</th></tr>
+{{range .Clusters}}<tr><td>{{if .Title}}{{.Title}}<br>{{end}}{{.Key}}</td><td>{{.Count}}</td><td>{{percent .FakeRatio}}</td></tr>
+{{end}}</table>{{else}}<p>No story was analyzed more than once.</p>{{end}}
+</body>
+</html>
+`))
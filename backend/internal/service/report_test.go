@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestReportServiceGenerate(t *testing.T) {
+	day := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewPredictionRepository()
+	for _, p := range []*domain.Prediction{
+		{ID: "a", Result: domain.LabelFake, Confidence: 0.9, ModelVersion: "v2", RequestType: "url",
+			ArticleSource: "www.rumors.example", CanonicalURL: "https://rumors.example/x", ArticleTitle: "Shock claim", CreatedAt: day.Add(time.Hour)},
+		{ID: "b", Result: domain.LabelFake, Confidence: 0.7, ModelVersion: "v2", RequestType: "url",
+			ArticleSource: "rumors.example", CanonicalURL: "https://rumors.example/x", CreatedAt: day.Add(2 * time.Hour)},
+		{ID: "c", Result: domain.LabelReal, Confidence: 0.8, ModelVersion: "v1", FallbackModel: "v1", RequestType: "text",
+			CreatedAt: day.Add(3 * time.Hour)},
+		{ID: "next-day", Result: domain.LabelReal, Confidence: 0.6, ModelVersion: "v2", RequestType: "text",
+			CreatedAt: day.AddDate(0, 0, 1)},
+	} {
+		if err := repo.CreatePrediction(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	evaluations := memory.NewEvaluationRepository()
+	completed := day.Add(5 * time.Hour)
+	evaluations.Save(context.Background(), &domain.EvaluationRun{
+		ID: "eval-1", Status: domain.EvaluationCompleted, Total: 100, CompletedAt: &completed,
+		Metrics: &domain.EvaluationMetrics{Accuracy: 0.9, F1: 0.88},
+	})
+
+	reports := NewReportService(repo, memory.NewReportRepository()).WithEvaluations(evaluations)
+	report, html, err := reports.Generate(context.Background(), day.Add(12*time.Hour))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if report.ID != "report-2024-06-01" || report.Total != 3 || report.Fake != 2 || report.URLRequests != 2 {
+		t.Errorf("report = %+v", report)
+	}
+	if len(report.TopDomains) != 1 || report.TopDomains[0].Domain != "rumors.example" || report.TopDomains[0].Count != 2 {
+		t.Errorf("TopDomains = %+v", report.TopDomains)
+	}
+	if len(report.Models) != 2 || report.Models[0].Version != "v2" || report.Models[1].Fallbacks != 1 {
+		t.Errorf("Models = %+v", report.Models)
+	}
+	if len(report.Clusters) != 1 || report.Clusters[0].Count != 2 || report.Clusters[0].Title != "Shock claim" {
+		t.Errorf("Clusters = %+v", report.Clusters)
+	}
+	if len(report.Evaluations) != 1 || report.Evaluations[0].Accuracy != 0.9 {
+		t.Errorf("Evaluations = %+v", report.Evaluations)
+	}
+	if !strings.Contains(string(html), "rumors.example") || !strings.Contains(string(html), "66.7%") {
+		t.Errorf("HTML is missing the report contents:\n%s", html)
+	}
+
+	// Regenerating a day replaces its report.
+	if _, _, err := reports.Generate(context.Background(), day); err != nil {
+		t.Fatal(err)
+	}
+	list, _ := reports.List(context.Background())
+	if len(list) != 1 {
+		t.Errorf("List() returned %d reports, want 1", len(list))
+	}
+}
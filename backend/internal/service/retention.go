@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// RetentionJanitor deletes predictions older than the retention period.
+// Pinned predictions are never deleted.
+type RetentionJanitor struct {
+	repository NewsRepository
+	maxAge     time.Duration
+	now        func() time.Time
+}
+
+// NewRetentionJanitor creates a janitor keeping predictions for maxAge.
+func NewRetentionJanitor(repo NewsRepository, maxAge time.Duration) *RetentionJanitor {
+	return &RetentionJanitor{repository: repo, maxAge: maxAge, now: time.Now}
+}
+
+// Sweep deletes expired, unpinned predictions and returns how many were
+// removed.
+func (j *RetentionJanitor) Sweep(ctx context.Context) (int, error) {
+	q := domain.NewPredictionQuery().WithDateRange(time.Time{}, j.now().Add(-j.maxAge))
+	expired, err := j.repository.Query(ctx, *q)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, p := range expired {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+		// The pin is checked at delete time, so one made since the query is kept
+		err := j.repository.DeleteUnpinnedPrediction(p.ID)
+		if errors.Is(err, domain.ErrPredictionPinned) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Warning: retention failed to delete prediction %s: %v", p.ID, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// Run sweeps every interval until ctx is cancelled.
+func (j *RetentionJanitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := j.Sweep(ctx); err != nil {
+				log.Printf("Warning: retention sweep failed: %v", err)
+			} else if n > 0 {
+				log.Printf("Retention removed %d expired predictions", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestRetentionJanitorSkipsPinned(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := memory.NewPredictionRepository()
+	for _, p := range []*domain.Prediction{
+		{ID: "old", CreatedAt: now.AddDate(0, 0, -40)},
+		{ID: "old-pinned", CreatedAt: now.AddDate(0, 0, -40), Pinned: true, PinnedBy: "alice"},
+		{ID: "recent", CreatedAt: now.AddDate(0, 0, -5)},
+	} {
+		if err := repo.CreatePrediction(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	janitor := NewRetentionJanitor(repo, 30*24*time.Hour)
+	janitor.now = func() time.Time { return now }
+
+	deleted, err := janitor.Sweep(context.Background())
+	if err != nil {
+		t.Fatalf("Sweep() error = %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Sweep() deleted %d, want 1", deleted)
+	}
+	if _, err := repo.GetPredictionByID("old"); !errors.Is(err, domain.ErrPredictionNotFound) {
+		t.Errorf("expected old prediction to be deleted, got %v", err)
+	}
+	for _, id := range []string{"old-pinned", "recent"} {
+		if _, err := repo.GetPredictionByID(id); err != nil {
+			t.Errorf("expected %s to be kept, got %v", id, err)
+		}
+	}
+}
+
+// pinAfterQuery pins a prediction right after the first query, as a user
+// could between the janitor's query and its deletes.
+type pinAfterQuery struct {
+	*memory.PredictionRepository
+	id string
+}
+
+func (r *pinAfterQuery) Query(ctx context.Context, q domain.PredictionQuery) ([]*domain.Prediction, error) {
+	matched, err := r.PredictionRepository.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	p, err := r.GetPredictionByID(r.id)
+	if err != nil {
+		return nil, err
+	}
+	pinned := *p
+	pinned.Pinned, pinned.PinnedBy = true, "alice"
+	return matched, r.UpdatePrediction(&pinned)
+}
+
+func TestRetentionJanitorKeepsPinsMadeDuringSweep(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := &pinAfterQuery{PredictionRepository: memory.NewPredictionRepository(), id: "pinned-meanwhile"}
+	for _, id := range []string{"old", "pinned-meanwhile"} {
+		if err := repo.CreatePrediction(&domain.Prediction{ID: id, CreatedAt: now.AddDate(0, 0, -40)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	janitor := NewRetentionJanitor(repo, 30*24*time.Hour)
+	janitor.now = func() time.Time { return now }
+
+	deleted, err := janitor.Sweep(context.Background())
+	if err != nil || deleted != 1 {
+		t.Fatalf("Sweep() = %d, %v; want 1 deleted", deleted, err)
+	}
+	if _, err := repo.GetPredictionByID("pinned-meanwhile"); err != nil {
+		t.Errorf("prediction pinned during the sweep was deleted: %v", err)
+	}
+}
+
+func TestPinPredictionLimits(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	limit := domain.PinLimit(domain.PlanFree)
+	for i := 0; i <= limit; i++ {
+		repo.CreatePrediction(&domain.Prediction{ID: string(rune('a' + i)), CreatedAt: time.Now()})
+	}
+	svc := NewNewsService(nil, nil, repo)
+	ctx := context.Background()
+
+	for i := 0; i < limit; i++ {
+		if _, err := svc.PinPrediction(ctx, string(rune('a'+i)), "alice", ""); err != nil {
+			t.Fatalf("PinPrediction(%d) error = %v", i, err)
+		}
+	}
+	last := string(rune('a' + limit))
+	if _, err := svc.PinPrediction(ctx, last, "alice", domain.PlanFree); !errors.Is(err, domain.ErrPinLimitReached) {
+		t.Errorf("expected ErrPinLimitReached, got %v", err)
+	}
+	if _, err := svc.PinPrediction(ctx, last, "bob", domain.PlanFree); err != nil {
+		t.Errorf("other caller should have own quota, got %v", err)
+	}
+	if _, err := svc.UnpinPrediction(ctx, last, "alice"); !errors.Is(err, domain.ErrPinnedByOther) {
+		t.Errorf("expected ErrPinnedByOther, got %v", err)
+	}
+	if p, err := svc.PinPrediction(ctx, "a", "alice", domain.PlanFree); err != nil || !p.Pinned {
+		t.Errorf("re-pinning own prediction should succeed, got %v", err)
+	}
+}
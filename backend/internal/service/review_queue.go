@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// Review queue defaults
+const (
+	DefaultReviewMinConfidence  = 0.5
+	DefaultReviewMaxConfidence  = 0.65
+	DefaultReviewMaxUncertainty = 0.15
+	DefaultReviewClaimTTL       = 30 * time.Minute
+	DefaultReviewSLA            = 24 * time.Hour
+)
+
+// PredictionReviewer records a reviewer's verdict on a prediction.
+// NewsService implements it.
+type PredictionReviewer interface {
+	ReviewPrediction(ctx context.Context, id, version string, review domain.Review) (*domain.Prediction, error)
+}
+
+// ReviewQueueStats describes the queue's depth and throughput.
+type ReviewQueueStats struct {
+	MinConfidence      float64        `json:"min_confidence"`
+	MaxConfidence      float64        `json:"max_confidence"`
+	MaxUncertainty     float64        `json:"max_uncertainty"` // std dev above which any verdict is queued
+	Pending            int            `json:"pending"`
+	Claimed            int            `json:"claimed"`
+	Overdue            int            `json:"overdue"`
+	OldestAgeSeconds   float64        `json:"oldest_age_seconds"`
+	Decided            int            `json:"decided"`
+	DecidedLate        int            `json:"decided_late"` // decided after the SLA deadline
+	Overturned         int            `json:"overturned"`   // reviewer disagreed with the model
+	AvgDecisionSeconds float64        `json:"avg_decision_seconds"`
+	Reviewers          map[string]int `json:"reviewers"` // claimed items per reviewer
+}
+
+// ReviewQueue holds model verdicts in the uncertain confidence band until
+// a human reviews them. A reviewer claims an item before deciding it, so
+// two reviewers never work on the same prediction; a claim that is not
+// decided within the claim TTL goes back to the queue. Items are handed
+// out in SLA order.
+type ReviewQueue struct {
+	reviews       PredictionReviewer
+	minConfidence float64
+	maxConfidence float64
+	maxStdDev     float64
+	claimTTL      time.Duration
+	sla           time.Duration
+	now           func() time.Time
+
+	mu           sync.Mutex
+	items        map[string]*domain.ReviewItem
+	decided      int
+	decidedLate  int
+	overturned   int
+	decisionTime time.Duration
+}
+
+// NewReviewQueue creates an empty queue whose decisions are recorded
+// through reviews.
+func NewReviewQueue(reviews PredictionReviewer) *ReviewQueue {
+	return &ReviewQueue{
+		reviews:       reviews,
+		minConfidence: DefaultReviewMinConfidence,
+		maxConfidence: DefaultReviewMaxConfidence,
+		maxStdDev:     DefaultReviewMaxUncertainty,
+		claimTTL:      DefaultReviewClaimTTL,
+		sla:           DefaultReviewSLA,
+		now:           time.Now,
+		items:         make(map[string]*domain.ReviewItem),
+	}
+}
+
+// WithBand sets the inclusive confidence range that needs review.
+func (q *ReviewQueue) WithBand(min, max float64) *ReviewQueue {
+	if min >= 0 && min < max && max <= 1 {
+		q.minConfidence, q.maxConfidence = min, max
+	}
+	return q
+}
+
+// WithMaxUncertainty queues verdicts whose Monte-Carlo dropout standard
+// deviation exceeds stdDev, whatever their confidence.
+func (q *ReviewQueue) WithMaxUncertainty(stdDev float64) *ReviewQueue {
+	if stdDev > 0 {
+		q.maxStdDev = stdDev
+	}
+	return q
+}
+
+// WithClaimTTL sets how long a reviewer holds an undecided claim.
+func (q *ReviewQueue) WithClaimTTL(ttl time.Duration) *ReviewQueue {
+	if ttl > 0 {
+		q.claimTTL = ttl
+	}
+	return q
+}
+
+// WithSLA sets how soon after enqueueing an item should be decided.
+func (q *ReviewQueue) WithSLA(sla time.Duration) *ReviewQueue {
+	if sla > 0 {
+		q.sla = sla
+	}
+	return q
+}
+
+// Observe enqueues a stored, unreviewed model verdict whose confidence
+// falls in the uncertain band, or whose model uncertainty is too high.
+func (q *ReviewQueue) Observe(p *domain.Prediction) {
+	if q == nil || p.ID == "" || p.Provisional || p.Review != nil {
+		return
+	}
+	if p.Method != "" && p.Method != domain.MethodModel {
+		return
+	}
+	inBand := p.Confidence >= q.minConfidence && p.Confidence <= q.maxConfidence
+	var stdDev float64
+	if p.Uncertainty != nil {
+		stdDev = p.Uncertainty.StdDev
+	}
+	if !inBand && stdDev <= q.maxStdDev {
+		return
+	}
+
+	now := q.now()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.items[p.ID]; ok {
+		return
+	}
+	q.items[p.ID] = &domain.ReviewItem{
+		PredictionID: p.ID,
+		Result:       p.Result,
+		Confidence:   p.Confidence,
+		Uncertainty:  stdDev,
+		Status:       domain.ReviewItemPending,
+		EnqueuedAt:   now,
+		DueAt:        now.Add(q.sla),
+	}
+}
+
+// Claim hands reviewer the unclaimed item closest to its SLA deadline, or
+// reports false when there is none.
+func (q *ReviewQueue) Claim(reviewer string) (*domain.ReviewItem, bool) {
+	now := q.now()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expireClaims(now)
+
+	var next *domain.ReviewItem
+	for _, item := range q.items {
+		if item.Status != domain.ReviewItemPending {
+			continue
+		}
+		if next == nil || item.DueAt.Before(next.DueAt) ||
+			(item.DueAt.Equal(next.DueAt) && item.PredictionID < next.PredictionID) {
+			next = item
+		}
+	}
+	if next == nil {
+		return nil, false
+	}
+	expires := now.Add(q.claimTTL)
+	next.Status = domain.ReviewItemClaimed
+	next.ClaimedBy = reviewer
+	next.ClaimExpires = &expires
+	copied := *next
+	return &copied, true
+}
+
+// Release returns a claimed item to the queue.
+func (q *ReviewQueue) Release(id, reviewer string) error {
+	now := q.now()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expireClaims(now)
+	item, err := q.claimedBy(id, reviewer)
+	if err != nil {
+		return err
+	}
+	item.Status = domain.ReviewItemPending
+	item.ClaimedBy = ""
+	item.ClaimExpires = nil
+	return nil
+}
+
+// Decide records reviewer's verdict on a claimed item. An item whose
+// prediction no longer exists is dropped from the queue.
+func (q *ReviewQueue) Decide(ctx context.Context, id, reviewer string, review domain.Review) (*domain.Prediction, error) {
+	now := q.now()
+	q.mu.Lock()
+	q.expireClaims(now)
+	_, err := q.claimedBy(id, reviewer)
+	q.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	review.Reviewer = reviewer
+	prediction, err := q.reviews.ReviewPrediction(ctx, id, "", review)
+	if errors.Is(err, domain.ErrPredictionNotFound) {
+		q.Remove(id)
+	}
+	return prediction, err
+}
+
+// Resolved takes a reviewed prediction off the queue, however the review
+// was made, and records the decision.
+func (q *ReviewQueue) Resolved(p *domain.Prediction) {
+	if q == nil || p.Review == nil {
+		return
+	}
+	now := q.now()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	item, ok := q.items[p.ID]
+	if !ok {
+		return
+	}
+	delete(q.items, p.ID)
+	q.decided++
+	q.decisionTime += now.Sub(item.EnqueuedAt)
+	if item.Overdue(now) {
+		q.decidedLate++
+	}
+	if p.Review.Verdict != item.Result {
+		q.overturned++
+	}
+}
+
+// Remove drops an item without a decision.
+func (q *ReviewQueue) Remove(id string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.items, id)
+}
+
+// List returns the queued items in SLA order. status filters by pending,
+// claimed or overdue; empty lists everything.
+func (q *ReviewQueue) List(status string) []domain.ReviewItem {
+	now := q.now()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expireClaims(now)
+
+	items := make([]domain.ReviewItem, 0, len(q.items))
+	for _, item := range q.items {
+		switch {
+		case status == "overdue" && !item.Overdue(now):
+			continue
+		case status != "" && status != "overdue" && item.Status != status:
+			continue
+		}
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].DueAt.Equal(items[j].DueAt) {
+			return items[i].DueAt.Before(items[j].DueAt)
+		}
+		return items[i].PredictionID < items[j].PredictionID
+	})
+	return items
+}
+
+// Stats reports queue depth, SLA breaches and decision throughput, or nil
+// without a queue.
+func (q *ReviewQueue) Stats() *ReviewQueueStats {
+	if q == nil {
+		return nil
+	}
+	now := q.now()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expireClaims(now)
+
+	stats := &ReviewQueueStats{
+		MinConfidence:  q.minConfidence,
+		MaxConfidence:  q.maxConfidence,
+		MaxUncertainty: q.maxStdDev,
+		Decided:        q.decided,
+		DecidedLate:    q.decidedLate,
+		Overturned:     q.overturned,
+		Reviewers:      make(map[string]int),
+	}
+	if q.decided > 0 {
+		stats.AvgDecisionSeconds = (q.decisionTime / time.Duration(q.decided)).Seconds()
+	}
+	for _, item := range q.items {
+		switch item.Status {
+		case domain.ReviewItemPending:
+			stats.Pending++
+		case domain.ReviewItemClaimed:
+			stats.Claimed++
+			stats.Reviewers[item.ClaimedBy]++
+		}
+		if item.Overdue(now) {
+			stats.Overdue++
+		}
+		if age := now.Sub(item.EnqueuedAt).Seconds(); age > stats.OldestAgeSeconds {
+			stats.OldestAgeSeconds = age
+		}
+	}
+	return stats
+}
+
+// claimedBy returns the item if reviewer holds an unexpired claim on it.
+// Callers hold q.mu and have expired lapsed claims.
+func (q *ReviewQueue) claimedBy(id, reviewer string) (*domain.ReviewItem, error) {
+	item, ok := q.items[id]
+	if !ok {
+		return nil, domain.ErrReviewItemNotFound
+	}
+	if item.Status != domain.ReviewItemClaimed || item.ClaimedBy != reviewer {
+		return nil, domain.ErrReviewItemClaimed
+	}
+	return item, nil
+}
+
+// expireClaims returns lapsed claims to the queue. Callers hold q.mu.
+func (q *ReviewQueue) expireClaims(now time.Time) {
+	for _, item := range q.items {
+		if item.Status == domain.ReviewItemClaimed && item.ClaimExpires != nil && now.After(*item.ClaimExpires) {
+			item.Status = domain.ReviewItemPending
+			item.ClaimedBy = ""
+			item.ClaimExpires = nil
+		}
+	}
+}
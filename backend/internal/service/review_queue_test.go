@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestReviewQueueObserve(t *testing.T) {
+	queue := NewReviewQueue(nil).WithBand(0.5, 0.7)
+	tests := []struct {
+		name   string
+		p      *domain.Prediction
+		queued bool
+	}{
+		{"uncertain", &domain.Prediction{ID: "a", Confidence: 0.6, Method: domain.MethodModel}, true},
+		{"band edge", &domain.Prediction{ID: "b", Confidence: 0.7}, true},
+		{"confident", &domain.Prediction{ID: "c", Confidence: 0.9, Method: domain.MethodModel}, false},
+		{"not a model verdict", &domain.Prediction{ID: "d", Confidence: 0.6, Method: domain.MethodTrustedSource}, false},
+		{"already reviewed", &domain.Prediction{ID: "e", Confidence: 0.6, Review: &domain.Review{Verdict: domain.LabelReal}}, false},
+		{"provisional", &domain.Prediction{ID: "f", Confidence: 0.6, Provisional: true}, false},
+		{"not stored", &domain.Prediction{Confidence: 0.6}, false},
+		{"confident but unstable", &domain.Prediction{ID: "g", Confidence: 0.9, Uncertainty: domain.NewUncertainty(0.9, 0.04, 20)}, true},
+		{"confident and stable", &domain.Prediction{ID: "h", Confidence: 0.9, Uncertainty: domain.NewUncertainty(0.9, 0.001, 20)}, false},
+	}
+	for _, tt := range tests {
+		queue.Observe(tt.p)
+	}
+	queued := make(map[string]bool)
+	for _, item := range queue.List("") {
+		queued[item.PredictionID] = true
+	}
+	for _, tt := range tests {
+		if tt.p.ID != "" && queued[tt.p.ID] != tt.queued {
+			t.Errorf("%s: queued = %v, want %v", tt.name, queued[tt.p.ID], tt.queued)
+		}
+	}
+}
+
+func TestReviewQueueWorkflow(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	news := NewNewsService(nil, nil, repo)
+	queue := NewReviewQueue(news).WithClaimTTL(time.Minute).WithSLA(time.Hour)
+	news.WithReviewQueue(queue)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	queue.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for _, id := range []string{"first", "second", "third"} {
+		p := &domain.Prediction{ID: id, Result: domain.LabelFake, Confidence: 0.55, Method: domain.MethodModel, ArticleTitle: "Headline " + id}
+		if err := repo.CreatePrediction(p); err != nil {
+			t.Fatal(err)
+		}
+		queue.Observe(p)
+		now = now.Add(time.Second)
+	}
+
+	// Items go out in SLA order, one reviewer each.
+	a, ok := queue.Claim("alice")
+	if !ok || a.PredictionID != "first" {
+		t.Fatalf("alice claimed %+v, want first", a)
+	}
+	b, ok := queue.Claim("bob")
+	if !ok || b.PredictionID != "second" {
+		t.Fatalf("bob claimed %+v, want second", b)
+	}
+	if _, err := queue.Decide(ctx, "first", "bob", domain.Review{Verdict: domain.LabelReal}); !errors.Is(err, domain.ErrReviewItemClaimed) {
+		t.Errorf("deciding another reviewer's item: err = %v, want ErrReviewItemClaimed", err)
+	}
+
+	p, err := queue.Decide(ctx, "first", "alice", domain.Review{Verdict: domain.LabelReal})
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if p.Review == nil || p.Review.Reviewer != "alice" || p.Review.Claim != "Headline first" {
+		t.Errorf("review = %+v", p.Review)
+	}
+	if _, err := queue.Decide(ctx, "first", "alice", domain.Review{Verdict: domain.LabelReal}); !errors.Is(err, domain.ErrReviewItemNotFound) {
+		t.Errorf("deciding twice: err = %v, want ErrReviewItemNotFound", err)
+	}
+
+	// Bob's claim lapses and the item is handed out again.
+	now = now.Add(2 * time.Minute)
+	c, ok := queue.Claim("carol")
+	if !ok || c.PredictionID != "second" {
+		t.Fatalf("carol claimed %+v, want the lapsed second", c)
+	}
+	if err := queue.Release("second", "bob"); !errors.Is(err, domain.ErrReviewItemClaimed) {
+		t.Errorf("releasing a lapsed claim: err = %v", err)
+	}
+	if err := queue.Release("second", "carol"); err != nil {
+		t.Errorf("Release: %v", err)
+	}
+
+	// A review made outside the queue also takes the item off it.
+	now = now.Add(2 * time.Hour)
+	if _, err := news.ReviewPrediction(ctx, "third", "", domain.Review{Verdict: domain.LabelFake, Reviewer: "dave"}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := queue.Stats()
+	if stats.Pending != 1 || stats.Claimed != 0 || stats.Overdue != 1 ||
+		stats.Decided != 2 || stats.DecidedLate != 1 || stats.Overturned != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if overdue := queue.List("overdue"); len(overdue) != 1 || overdue[0].PredictionID != "second" {
+		t.Errorf("overdue = %+v", overdue)
+	}
+
+	// Withdrawing a review puts the prediction back in the queue.
+	if _, err := news.RemoveReview(ctx, "first"); err != nil {
+		t.Fatal(err)
+	}
+	if pending := queue.List(domain.ReviewItemPending); len(pending) != 2 {
+		t.Errorf("pending after withdrawal = %+v, want 2 items", pending)
+	}
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Saved search defaults
+const (
+	DefaultMaxSavedSearches    = 50 // per user
+	DefaultSavedSearchInterval = 15 * time.Minute
+)
+
+// SavedSearchService stores users' named history searches, re-runs them
+// and, for searches with notify set, tells their owner when new
+// predictions match.
+type SavedSearchService struct {
+	news        *NewsService
+	repo        repository.SavedSearchRepository
+	notifier    WatchNotifier
+	maxSearches int
+	now         func() time.Time
+}
+
+// NewSavedSearchService creates a saved search service.
+func NewSavedSearchService(news *NewsService, repo repository.SavedSearchRepository) *SavedSearchService {
+	return &SavedSearchService{
+		news:        news,
+		repo:        repo,
+		maxSearches: DefaultMaxSavedSearches,
+		now:         time.Now,
+	}
+}
+
+// WithNotifier sends new-match notifications through notifier. Without
+// one, matches are only recorded on the search.
+func (s *SavedSearchService) WithNotifier(notifier WatchNotifier) *SavedSearchService {
+	s.notifier = notifier
+	return s
+}
+
+// WithMaxSearches caps how many searches one user can save.
+func (s *SavedSearchService) WithMaxSearches(max int) *SavedSearchService {
+	if max > 0 {
+		s.maxSearches = max
+	}
+	return s
+}
+
+// Create saves a search for a user. Only predictions created from now on
+// trigger notifications.
+func (s *SavedSearchService) Create(ctx context.Context, userID string, search domain.SavedSearch) (*domain.SavedSearch, error) {
+	if err := search.Validate(); err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= s.maxSearches {
+		return nil, fmt.Errorf("%w (%d)", domain.ErrSavedSearchLimitReached, s.maxSearches)
+	}
+
+	now := s.now()
+	saved := &domain.SavedSearch{
+		ID:           uuid.New().String(),
+		UserID:       userID,
+		Name:         search.Name,
+		Filters:      search.Filters,
+		Notify:       search.Notify,
+		CheckedUntil: now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.repo.Save(ctx, saved); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// List returns a user's saved searches.
+func (s *SavedSearchService) List(ctx context.Context, userID string) ([]*domain.SavedSearch, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// Get returns one of a user's saved searches. Other users' searches are
+// reported as not found.
+func (s *SavedSearchService) Get(ctx context.Context, userID, id string) (*domain.SavedSearch, error) {
+	search, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if search.UserID != userID {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrSavedSearchNotFound, id)
+	}
+	return search, nil
+}
+
+// Update replaces a saved search's name, filters and notify setting.
+// Changing the filters or turning notify on starts notifications afresh.
+func (s *SavedSearchService) Update(ctx context.Context, userID, id string, change domain.SavedSearch) (*domain.SavedSearch, error) {
+	search, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := change.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if change.Filters != search.Filters || (change.Notify && !search.Notify) {
+		search.CheckedUntil = now
+	}
+	search.Name = change.Name
+	search.Filters = change.Filters
+	search.Notify = change.Notify
+	search.UpdatedAt = now
+	if err := s.repo.Save(ctx, search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// Delete removes one of a user's saved searches.
+func (s *SavedSearchService) Delete(ctx context.Context, userID, id string) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Results re-runs a saved search over prediction history, newest first.
+func (s *SavedSearchService) Results(ctx context.Context, userID, id string, limit, offset int) (*domain.SavedSearch, []*domain.Prediction, error) {
+	search, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	q := search.Filters.Query()
+	q.Limit, q.Offset = limit, offset
+	predictions, err := s.news.QueryHistory(ctx, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return search, predictions, nil
+}
+
+// CheckNew notifies owners of searches with notify set about predictions
+// created since the previous check. It returns how many searches had new
+// matches.
+func (s *SavedSearchService) CheckNew(ctx context.Context) (int, error) {
+	searches, err := s.repo.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	matched := 0
+	for _, search := range searches {
+		if !search.Notify {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return matched, err
+		}
+
+		// Predictions in (CheckedUntil, checkedAt] are new to this pass.
+		checkedAt := s.now()
+		q := search.Filters.Query()
+		if q.Since.Before(search.CheckedUntil) {
+			q.Since = search.CheckedUntil
+		}
+		if !q.Until.IsZero() && !q.Since.Before(q.Until) {
+			continue
+		}
+		predictions, err := s.news.QueryHistory(ctx, q)
+		if err != nil {
+			log.Printf("Warning: saved search %s failed: %v", search.ID, err)
+			continue
+		}
+		var matches []*domain.Prediction
+		for _, p := range predictions {
+			if p.CreatedAt.After(search.CheckedUntil) && !p.CreatedAt.After(checkedAt) {
+				matches = append(matches, p)
+			}
+		}
+
+		search.CheckedUntil = checkedAt
+		if len(matches) > 0 {
+			matched++
+			search.LastMatchAt = &checkedAt
+			s.notify(ctx, search, matches)
+		}
+		if err := s.repo.Save(ctx, search); err != nil {
+			log.Printf("Warning: failed to save saved search %s: %v", search.ID, err)
+		}
+	}
+	return matched, nil
+}
+
+// Run checks for new matches every interval until ctx is cancelled.
+func (s *SavedSearchService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.CheckNew(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				log.Printf("Warning: saved search check failed: %v", err)
+			} else if n > 0 {
+				log.Printf("Saved search check found new matches for %d searches", n)
+			}
+		}
+	}
+}
+
+func (s *SavedSearchService) notify(ctx context.Context, search *domain.SavedSearch, matches []*domain.Prediction) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.NotifyUser(ctx, search.UserID, savedSearchNotification(search, matches)); err != nil {
+		log.Printf("Warning: failed to notify %s of saved search matches: %v", search.UserID, err)
+	}
+}
+
+// savedSearchNotification summarizes new matches, newest first.
+func savedSearchNotification(search *domain.SavedSearch, matches []*domain.Prediction) *domain.PushNotification {
+	newest := matches[0]
+	body := fmt.Sprintf("%d new analyses match", len(matches))
+	if len(matches) == 1 {
+		body = "1 new analysis matches"
+	}
+	if newest.ArticleTitle != "" {
+		body += fmt.Sprintf(", latest: %s (%s)", newest.ArticleTitle, newest.Result)
+	}
+
+	notification := &domain.PushNotification{
+		Title: search.Name,
+		Body:  body,
+		Tag:   "search-" + search.ID,
+		Data: map[string]string{
+			"search_id":     search.ID,
+			"matches":       fmt.Sprint(len(matches)),
+			"prediction_id": newest.ID,
+		},
+	}
+	if len(matches) == 1 && newest.RequestType == "url" {
+		notification.URL = newest.OriginalContent
+	}
+	return notification
+}
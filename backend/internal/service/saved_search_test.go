@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestSavedSearches(t *testing.T) {
+	repo := memory.NewPredictionRepository()
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	add := func(id, result string, at time.Time) {
+		t.Helper()
+		if err := repo.CreatePrediction(&domain.Prediction{
+			ID: id, RequestType: "text", Result: result, Confidence: 0.9, CreatedAt: at,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("old-fake", domain.LabelFake, start.Add(-time.Hour))
+	add("old-real", domain.LabelReal, start.Add(-time.Hour))
+
+	notifier := &recordingNotifier{sent: make(map[string][]*domain.PushNotification)}
+	now := start
+	svc := NewSavedSearchService(NewNewsService(nil, nil, repo), memory.NewSavedSearchRepository()).
+		WithNotifier(notifier).
+		WithMaxSearches(2)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	fakes, err := svc.Create(ctx, "alice", domain.SavedSearch{
+		Name: "  Fakes ", Filters: domain.SearchFilters{Label: domain.LabelFake}, Notify: true,
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if fakes.Name != "Fakes" {
+		t.Errorf("name = %q, want trimmed", fakes.Name)
+	}
+	if _, err := svc.Create(ctx, "alice", domain.SavedSearch{Name: "bad", Filters: domain.SearchFilters{Label: "MAYBE"}}); !errors.Is(err, domain.ErrInvalidSavedSearch) {
+		t.Errorf("Create invalid label = %v, want ErrInvalidSavedSearch", err)
+	}
+	if _, err := svc.Create(ctx, "alice", domain.SavedSearch{Name: "All"}); err != nil {
+		t.Fatalf("Create second: %v", err)
+	}
+	if _, err := svc.Create(ctx, "alice", domain.SavedSearch{Name: "Third"}); !errors.Is(err, domain.ErrSavedSearchLimitReached) {
+		t.Errorf("Create over limit = %v, want ErrSavedSearchLimitReached", err)
+	}
+
+	// Results re-run the filters over all history.
+	_, results, err := svc.Results(ctx, "alice", fakes.ID, 0, 0)
+	if err != nil || len(results) != 1 || results[0].ID != "old-fake" {
+		t.Fatalf("Results = %v, %v; want old-fake", results, err)
+	}
+	if _, _, err := svc.Results(ctx, "bob", fakes.ID, 0, 0); !errors.Is(err, domain.ErrSavedSearchNotFound) {
+		t.Errorf("Results for another user = %v, want ErrSavedSearchNotFound", err)
+	}
+
+	// Matches older than the search do not notify.
+	now = start.Add(time.Minute)
+	if n, err := svc.CheckNew(ctx); err != nil || n != 0 {
+		t.Fatalf("CheckNew with no new matches = %d, %v; want 0", n, err)
+	}
+
+	// A new FAKE prediction notifies once; a new REAL one does not.
+	add("new-fake", domain.LabelFake, start.Add(2*time.Minute))
+	add("new-real", domain.LabelReal, start.Add(2*time.Minute))
+	now = start.Add(3 * time.Minute)
+	if n, err := svc.CheckNew(ctx); err != nil || n != 1 {
+		t.Fatalf("CheckNew = %d, %v; want 1", n, err)
+	}
+	sent := notifier.sent["alice"]
+	if len(sent) != 1 || sent[0].Tag != "search-"+fakes.ID || sent[0].Data.(map[string]string)["prediction_id"] != "new-fake" {
+		t.Fatalf("notifications = %+v, want one for new-fake", sent)
+	}
+	if n, _ := svc.CheckNew(ctx); n != 0 {
+		t.Errorf("CheckNew repeated = %d, want 0", n)
+	}
+
+	// Turning notify off stops notifications.
+	if _, err := svc.Update(ctx, "alice", fakes.ID, domain.SavedSearch{Name: "Fakes", Filters: fakes.Filters}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	add("later-fake", domain.LabelFake, start.Add(4*time.Minute))
+	now = start.Add(5 * time.Minute)
+	if n, _ := svc.CheckNew(ctx); n != 0 {
+		t.Errorf("CheckNew with notify off = %d, want 0", n)
+	}
+
+	if err := svc.Delete(ctx, "bob", fakes.ID); !errors.Is(err, domain.ErrSavedSearchNotFound) {
+		t.Errorf("Delete by another user = %v, want ErrSavedSearchNotFound", err)
+	}
+	if err := svc.Delete(ctx, "alice", fakes.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if list, _ := svc.List(ctx, "alice"); len(list) != 1 {
+		t.Errorf("List after delete = %d searches, want 1", len(list))
+	}
+}
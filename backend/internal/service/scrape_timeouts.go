@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Adaptive scrape timeout defaults
+const (
+	DefaultScrapeTimeout        = 15 * time.Second // domains without enough history
+	DefaultScrapeTimeoutFloor   = 3 * time.Second
+	DefaultScrapeTimeoutCeiling = 30 * time.Second
+	scrapeLatencyWindow         = 50 // recent fetches kept per domain
+	scrapeLatencyMinSamples     = 5  // fetches needed before a domain's timeout adapts
+	scrapeTimeoutHeadroom       = 2  // timeout = headroom × p95 latency
+	maxScrapeLatencyDomains     = 1000
+)
+
+// DomainLatency is one domain's fetch latency and adaptive timeout as
+// shown in scraper stats.
+type DomainLatency struct {
+	Domain    string `json:"domain"`
+	Samples   int    `json:"samples"`
+	P50MS     int64  `json:"p50_ms"`
+	P90MS     int64  `json:"p90_ms"`
+	P95MS     int64  `json:"p95_ms"`
+	P99MS     int64  `json:"p99_ms"`
+	TimeoutMS int64  `json:"timeout_ms"`
+	TimedOut  int    `json:"timed_out"` // fetches cut off by the timeout
+}
+
+// ScrapeTimeoutStats describes the adaptive timeouts.
+type ScrapeTimeoutStats struct {
+	DefaultMS int64           `json:"default_ms"`
+	FloorMS   int64           `json:"floor_ms"`
+	CeilingMS int64           `json:"ceiling_ms"`
+	Domains   []DomainLatency `json:"domains"`
+}
+
+type domainLatency struct {
+	samples  []time.Duration // ring of recent fetch latencies
+	next     int
+	timedOut int
+	lastSeen time.Time
+}
+
+// ScrapeTimeouts derives a per-domain page fetch timeout from how long the
+// domain's recent fetches took: twice their 95th percentile, kept between
+// a floor and a ceiling. Domains with too little history get the default.
+// A fetch cut off by its timeout counts as taking the whole timeout, so a
+// domain that slows down earns a longer one on the next fetch.
+type ScrapeTimeouts struct {
+	defaultTimeout time.Duration
+	floor          time.Duration
+	ceiling        time.Duration
+	now            func() time.Time
+
+	mu      sync.Mutex
+	domains map[string]*domainLatency
+}
+
+// NewScrapeTimeouts creates timeouts with the default bounds.
+func NewScrapeTimeouts() *ScrapeTimeouts {
+	return &ScrapeTimeouts{
+		defaultTimeout: DefaultScrapeTimeout,
+		floor:          DefaultScrapeTimeoutFloor,
+		ceiling:        DefaultScrapeTimeoutCeiling,
+		now:            time.Now,
+		domains:        make(map[string]*domainLatency),
+	}
+}
+
+// WithBounds sets the timeout for unknown domains and the range adaptive
+// timeouts are kept in. Invalid combinations are ignored.
+func (t *ScrapeTimeouts) WithBounds(defaultTimeout, floor, ceiling time.Duration) *ScrapeTimeouts {
+	if floor > 0 && floor <= defaultTimeout && defaultTimeout <= ceiling {
+		t.defaultTimeout, t.floor, t.ceiling = defaultTimeout, floor, ceiling
+	}
+	return t
+}
+
+// Ceiling returns the longest timeout any domain can get.
+func (t *ScrapeTimeouts) Ceiling() time.Duration {
+	return t.ceiling
+}
+
+// Timeout returns the page fetch timeout for host.
+func (t *ScrapeTimeouts) Timeout(host string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	d, ok := t.domains[host]
+	if !ok {
+		return t.defaultTimeout
+	}
+	return t.timeoutFor(sortedLatencies(d.samples))
+}
+
+// Observe records how long a fetch from host took. timedOut marks a fetch
+// cut off by its timeout.
+func (t *ScrapeTimeouts) Observe(host string, latency time.Duration, timedOut bool) {
+	if host == "" {
+		return
+	}
+	now := t.now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	d, ok := t.domains[host]
+	if !ok {
+		if len(t.domains) >= maxScrapeLatencyDomains {
+			t.evictOldest()
+		}
+		d = &domainLatency{}
+		t.domains[host] = d
+	}
+	d.lastSeen = now
+	if timedOut {
+		d.timedOut++
+	}
+	if len(d.samples) < scrapeLatencyWindow {
+		d.samples = append(d.samples, latency)
+		return
+	}
+	d.samples[d.next] = latency
+	d.next = (d.next + 1) % scrapeLatencyWindow
+}
+
+// Stats returns the bounds and every tracked domain's latency, slowest
+// timeout first.
+func (t *ScrapeTimeouts) Stats() *ScrapeTimeoutStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := &ScrapeTimeoutStats{
+		DefaultMS: t.defaultTimeout.Milliseconds(),
+		FloorMS:   t.floor.Milliseconds(),
+		CeilingMS: t.ceiling.Milliseconds(),
+		Domains:   make([]DomainLatency, 0, len(t.domains)),
+	}
+	for host, d := range t.domains {
+		sorted := sortedLatencies(d.samples)
+		stats.Domains = append(stats.Domains, DomainLatency{
+			Domain:    host,
+			Samples:   len(sorted),
+			P50MS:     percentile(sorted, 0.50).Milliseconds(),
+			P90MS:     percentile(sorted, 0.90).Milliseconds(),
+			P95MS:     percentile(sorted, 0.95).Milliseconds(),
+			P99MS:     percentile(sorted, 0.99).Milliseconds(),
+			TimeoutMS: t.timeoutFor(sorted).Milliseconds(),
+			TimedOut:  d.timedOut,
+		})
+	}
+	sort.Slice(stats.Domains, func(i, j int) bool {
+		if stats.Domains[i].TimeoutMS != stats.Domains[j].TimeoutMS {
+			return stats.Domains[i].TimeoutMS > stats.Domains[j].TimeoutMS
+		}
+		return stats.Domains[i].Domain < stats.Domains[j].Domain
+	})
+	return stats
+}
+
+// timeoutFor derives a timeout from sorted latencies. Callers hold t.mu.
+func (t *ScrapeTimeouts) timeoutFor(sorted []time.Duration) time.Duration {
+	if len(sorted) < scrapeLatencyMinSamples {
+		return t.defaultTimeout
+	}
+	return min(max(scrapeTimeoutHeadroom*percentile(sorted, 0.95), t.floor), t.ceiling)
+}
+
+// evictOldest forgets the least recently fetched domain. Callers hold t.mu.
+func (t *ScrapeTimeouts) evictOldest() {
+	var oldest string
+	for host, d := range t.domains {
+		if oldest == "" || d.lastSeen.Before(t.domains[oldest].lastSeen) {
+			oldest = host
+		}
+	}
+	delete(t.domains, oldest)
+}
+
+func sortedLatencies(samples []time.Duration) []time.Duration {
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// percentile returns the nearest-rank p-quantile of sorted, or 0.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+// fetchTimedOut reports whether err came from the fetch's own timeout
+// rather than the caller giving up.
+func fetchTimedOut(parent context.Context, err error) bool {
+	if err == nil || parent.Err() != nil {
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestScrapeTimeouts(t *testing.T) {
+	timeouts := NewScrapeTimeouts().WithBounds(10*time.Second, time.Second, 20*time.Second)
+	observe := func(host string, latency time.Duration, n int) {
+		for i := 0; i < n; i++ {
+			timeouts.Observe(host, latency, false)
+		}
+	}
+	observe("fast.example", 200*time.Millisecond, 20)
+	observe("steady.example", 3*time.Second, 20)
+	observe("crawling.example", 15*time.Second, 20)
+	observe("new.example", 100*time.Millisecond, scrapeLatencyMinSamples-1)
+
+	tests := []struct {
+		host string
+		want time.Duration
+	}{
+		{"fast.example", time.Second},          // 2 × p95 is below the floor
+		{"steady.example", 6 * time.Second},    // 2 × p95
+		{"crawling.example", 20 * time.Second}, // capped at the ceiling
+		{"new.example", 10 * time.Second},      // too little history
+		{"unknown.example", 10 * time.Second},  // never fetched
+	}
+	for _, tt := range tests {
+		if got := timeouts.Timeout(tt.host); got != tt.want {
+			t.Errorf("Timeout(%s) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+
+	// A domain that starts timing out earns longer timeouts.
+	for i := 0; i < scrapeLatencyWindow/10; i++ {
+		timeouts.Observe("steady.example", timeouts.Timeout("steady.example"), true)
+	}
+	if got := timeouts.Timeout("steady.example"); got != 20*time.Second {
+		t.Errorf("after timeouts: Timeout(steady.example) = %v, want the 20s ceiling", got)
+	}
+
+	stats := timeouts.Stats()
+	if len(stats.Domains) != 4 || stats.Domains[0].TimeoutMS != 20000 || stats.DefaultMS != 10000 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	for _, d := range stats.Domains {
+		if d.Domain == "steady.example" && (d.TimedOut != 5 || d.P50MS != 3000 || d.Samples != 25) {
+			t.Errorf("steady.example = %+v", d)
+		}
+	}
+}
+
+func TestScraperAdaptiveTimeout(t *testing.T) {
+	article := `<html><head><title>Harbor reopens</title></head><body><article>
+<p>The city harbor reopened on Monday after three weeks of repairs to the storm-damaged breakwater, officials said.</p>
+<p>Ferry services will resume their normal timetable from Wednesday, the port authority confirmed in a statement.</p>
+</article></body></html>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/2024/stalled" {
+			select {
+			case <-time.After(5 * time.Second):
+			case <-r.Context().Done():
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(article))
+	}))
+	defer srv.Close()
+	scraper := NewScraperService().WithTimeouts(10*time.Second, 200*time.Millisecond, 20*time.Second)
+	scraper.httpClient = &http.Client{Transport: rewriteTransport{target: srv.URL}}
+	ctx := context.Background()
+
+	for i := 0; i < scrapeLatencyMinSamples; i++ {
+		if _, err := scraper.ScrapeArticle(ctx, "http://harbor.example/2024/harbor-reopens"); err != nil {
+			t.Fatalf("ScrapeArticle: %v", err)
+		}
+	}
+	// The domain answers quickly, so a stalled page is cut off at the floor
+	// instead of the 10s default.
+	start := time.Now()
+	_, err := scraper.ScrapeArticle(ctx, "http://harbor.example/2024/stalled")
+	if err == nil || time.Since(start) > 2*time.Second {
+		t.Fatalf("stalled scrape returned %v after %v", err, time.Since(start))
+	}
+	diag := ScrapeDiagnosticsOf(err)
+	if diag == nil || diag.TimeoutMS != 200 || diag.BlockedBy != BlockedByTimeout {
+		t.Errorf("diagnostics = %+v", diag)
+	}
+	domains := scraper.Stats().Timeouts.Domains
+	if len(domains) != 1 || domains[0].TimedOut != 1 || domains[0].Samples != scrapeLatencyMinSamples+1 {
+		t.Errorf("timeout stats = %+v", domains)
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Naman30903/Final-Year-Project/internal/crypto"
+)
+
+// ScraperCredential is the access configuration for a partner archive.
+// It applies to the host and its subdomains, and only over HTTPS.
+//
+// Values may reference environment variables as ${NAME}, or be encrypted
+// with fnctl encrypt, so the file itself need not contain secrets.
+type ScraperCredential struct {
+	Host        string            `json:"host"`
+	Username    string            `json:"username,omitempty"` // HTTP basic auth
+	Password    string            `json:"password,omitempty"`
+	BearerToken string            `json:"bearer_token,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"` // e.g. a partner API key header
+}
+
+// ScraperCredentials maps hosts to their credentials.
+type ScraperCredentials struct {
+	byHost map[string]ScraperCredential
+}
+
+// NewScraperCredentials creates a credential set, expanding ${NAME}
+// references from the environment and decrypting encrypted values with
+// keyring, which may be nil when no value is encrypted.
+func NewScraperCredentials(creds []ScraperCredential, keyring *crypto.Keyring) (*ScraperCredentials, error) {
+	c := &ScraperCredentials{byHost: make(map[string]ScraperCredential)}
+	for _, cred := range creds {
+		cred.Host = normalizeDomain(cred.Host)
+		if cred.Host == "" {
+			return nil, fmt.Errorf("scraper credential has no host")
+		}
+		if cred.Username == "" && cred.BearerToken == "" && len(cred.Headers) == 0 {
+			return nil, fmt.Errorf("scraper credential for %s sets no auth", cred.Host)
+		}
+		if cred.Username != "" && cred.BearerToken != "" {
+			return nil, fmt.Errorf("scraper credential for %s sets both basic auth and a bearer token", cred.Host)
+		}
+
+		var err error
+		for _, field := range []*string{&cred.Username, &cred.Password, &cred.BearerToken} {
+			if *field, err = credentialSecret(*field, keyring); err != nil {
+				return nil, fmt.Errorf("scraper credential for %s: %w", cred.Host, err)
+			}
+		}
+		headers := make(map[string]string, len(cred.Headers))
+		for name, value := range cred.Headers {
+			if headers[http.CanonicalHeaderKey(name)], err = credentialSecret(value, keyring); err != nil {
+				return nil, fmt.Errorf("scraper credential for %s: %w", cred.Host, err)
+			}
+		}
+		cred.Headers = headers
+
+		c.byHost[cred.Host] = cred
+	}
+	return c, nil
+}
+
+// credentialSecret expands ${NAME} references in value and decrypts it
+// if it is encrypted.
+func credentialSecret(value string, keyring *crypto.Keyring) (string, error) {
+	value = os.ExpandEnv(value)
+	if !crypto.IsEncrypted(value) {
+		return value, nil
+	}
+	if keyring == nil {
+		return "", fmt.Errorf("value is encrypted but ENCRYPTION_KEYS is not set")
+	}
+	return keyring.Decrypt(value)
+}
+
+// LoadScraperCredentials reads a JSON array of ScraperCredential from a
+// file. A file readable by other users is loaded with a warning.
+func LoadScraperCredentials(path string, keyring *crypto.Keyring) (*ScraperCredentials, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scraper credentials: %w", err)
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		log.Printf("WARNING: scraper credentials file %s is accessible by other users (mode %v)", path, info.Mode().Perm())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scraper credentials: %w", err)
+	}
+	var creds []ScraperCredential
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse scraper credentials: %w", err)
+	}
+	return NewScraperCredentials(creds, keyring)
+}
+
+// Len returns the number of configured hosts.
+func (c *ScraperCredentials) Len() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.byHost)
+}
+
+// Lookup returns the credential for a host, falling back to its parent
+// domains.
+func (c *ScraperCredentials) Lookup(host string) (ScraperCredential, bool) {
+	if c == nil {
+		return ScraperCredential{}, false
+	}
+	host = normalizeDomain(host)
+	for host != "" {
+		if cred, ok := c.byHost[host]; ok {
+			return cred, true
+		}
+		dot := strings.IndexByte(host, '.')
+		if dot < 0 {
+			break
+		}
+		host = host[dot+1:]
+	}
+	return ScraperCredential{}, false
+}
+
+// apply sets the credential's headers on req.
+func (cred ScraperCredential) apply(req *http.Request) {
+	switch {
+	case cred.Username != "":
+		req.SetBasicAuth(cred.Username, cred.Password)
+	case cred.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+cred.BearerToken)
+	}
+	for name, value := range cred.Headers {
+		req.Header.Set(name, value)
+	}
+}
+
+// WithCredentials sets per-host credentials for partner archives.
+func (s *ScraperService) WithCredentials(creds *ScraperCredentials) *ScraperService {
+	s.credentials = creds
+	return s
+}
+
+// credentialTransport adds partner credentials to each request hop whose
+// host matches, so they never follow a redirect to another site.
+type credentialTransport struct {
+	scraper *ScraperService
+	next    http.RoundTripper
+}
+
+func (t *credentialTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "https" {
+		if cred, ok := t.scraper.credentials.Lookup(req.URL.Hostname()); ok {
+			req = req.Clone(req.Context())
+			cred.apply(req)
+		}
+	}
+	return t.next.RoundTrip(req)
+}
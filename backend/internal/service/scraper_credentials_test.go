@@ -0,0 +1,98 @@
+package service
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/crypto"
+)
+
+type recordingTransport struct {
+	req *http.Request
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.req = req
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestCredentialTransport(t *testing.T) {
+	t.Setenv("PARTNER_TOKEN", "s3cret")
+	creds, err := NewScraperCredentials([]ScraperCredential{
+		{Host: "archive.journalism.edu", BearerToken: "${PARTNER_TOKEN}", Headers: map[string]string{"x-partner-id": "fnd"}},
+		{Host: "Press.example.org", Username: "reader", Password: "pw"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewScraperCredentials() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		wantAuth string
+		wantID   string
+	}{
+		{name: "bearer with env expansion", url: "https://archive.journalism.edu/2024/story", wantAuth: "Bearer s3cret", wantID: "fnd"},
+		{name: "subdomain of basic auth host", url: "https://www.press.example.org/a", wantAuth: "Basic cmVhZGVyOnB3"},
+		{name: "plain http gets nothing", url: "http://archive.journalism.edu/2024/story"},
+		{name: "other host gets nothing", url: "https://evil.example.com/journalism.edu"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &recordingTransport{}
+			transport := &credentialTransport{scraper: NewScraperService().WithCredentials(creds), next: next}
+
+			req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			if _, err := transport.RoundTrip(req); err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+
+			if got := next.req.Header.Get("Authorization"); got != tt.wantAuth {
+				t.Errorf("Authorization = %q, want %q", got, tt.wantAuth)
+			}
+			if got := next.req.Header.Get("X-Partner-Id"); got != tt.wantID {
+				t.Errorf("X-Partner-Id = %q, want %q", got, tt.wantID)
+			}
+			if req.Header.Get("Authorization") != "" {
+				t.Error("caller's request was modified")
+			}
+		})
+	}
+}
+
+func TestNewScraperCredentialsRejectsInvalid(t *testing.T) {
+	tests := map[string]ScraperCredential{
+		"no host": {BearerToken: "t"},
+		"no auth": {Host: "example.com"},
+		"both":    {Host: "example.com", Username: "u", BearerToken: "t"},
+	}
+	for name, cred := range tests {
+		if _, err := NewScraperCredentials([]ScraperCredential{cred}, nil); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestNewScraperCredentialsDecrypts(t *testing.T) {
+	keyring, err := crypto.NewKeyring("k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, crypto.MasterKeySize)})
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	sealed, _ := keyring.Encrypt("s3cret")
+	cred := ScraperCredential{Host: "archive.journalism.edu", BearerToken: sealed, Headers: map[string]string{"x-partner-key": sealed}}
+
+	creds, err := NewScraperCredentials([]ScraperCredential{cred}, keyring)
+	if err != nil {
+		t.Fatalf("NewScraperCredentials() error = %v", err)
+	}
+	got := creds.byHost["archive.journalism.edu"]
+	if got.BearerToken != "s3cret" || got.Headers["X-Partner-Key"] != "s3cret" {
+		t.Errorf("credential = %+v, want decrypted values", got)
+	}
+
+	if _, err := NewScraperCredentials([]ScraperCredential{cred}, nil); err == nil {
+		t.Error("expected error for encrypted values without a keyring")
+	}
+}
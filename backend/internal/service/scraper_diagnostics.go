@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// Blocked-by heuristics recorded in ScrapeDiagnostics.BlockedBy
+const (
+	BlockedByPlatformPolicy = "platform_policy"     // a site on the scraper's blocked list
+	BlockedByNetworkPolicy  = "network_policy"      // internal or non-public destination
+	BlockedByBotChallenge   = "bot_challenge"       // Cloudflare-style interstitial
+	BlockedByCaptcha        = "captcha"             // human verification page
+	BlockedByRateLimit      = "rate_limited"        // HTTP 429
+	BlockedByAccessDenied   = "access_denied"       // HTTP 401 or 403 without a challenge page
+	BlockedByPaywall        = "paywall"             // subscription prompt instead of the article
+	BlockedByJavaScript     = "javascript_required" // article rendered client-side
+	BlockedBySizeLimit      = "size_limit"          // page larger than SCRAPER_MAX_BYTES
+	BlockedByTimeout        = "timeout"             // the site did not answer in time
+	BlockedByCrawlBudget    = "crawl_budget"        // background crawl refused by the crawl budget
+	blockedBySnippetBytes   = 16 << 10              // body read from error responses
+)
+
+// ScrapeError is a failed scrape with its diagnostics. It unwraps to the
+// scraper's domain error.
+type ScrapeError struct {
+	Err         error
+	Diagnostics *domain.ScrapeDiagnostics
+}
+
+func (e *ScrapeError) Error() string { return e.Err.Error() }
+
+func (e *ScrapeError) Unwrap() error { return e.Err }
+
+// ScrapeDiagnosticsOf returns the diagnostics carried by a scrape error,
+// or nil.
+func ScrapeDiagnosticsOf(err error) *domain.ScrapeDiagnostics {
+	var scrapeErr *ScrapeError
+	if errors.As(err, &scrapeErr) {
+		return scrapeErr.Diagnostics
+	}
+	return nil
+}
+
+type diagnosticsKey struct{}
+
+func contextWithDiagnostics(ctx context.Context, diag *domain.ScrapeDiagnostics) context.Context {
+	return context.WithValue(ctx, diagnosticsKey{}, diag)
+}
+
+// diagnosticsTransport records every hop of a scrape, redirects included,
+// in the diagnostics carried by the request context.
+type diagnosticsTransport struct {
+	next http.RoundTripper
+}
+
+func (t *diagnosticsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if diag, ok := req.Context().Value(diagnosticsKey{}).(*domain.ScrapeDiagnostics); ok {
+		hop := domain.ScrapeHop{URL: req.URL.String()}
+		if err == nil {
+			hop.StatusCode = resp.StatusCode
+		}
+		diag.RedirectChain = append(diag.RedirectChain, hop)
+	}
+	return resp, err
+}
+
+// blockedByResponse guesses why a site answered with an error status from
+// its headers and the start of its body.
+func blockedByResponse(resp *http.Response) string {
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, blockedBySnippetBytes))
+	body := strings.ToLower(string(snippet))
+	switch {
+	case resp.Header.Get("Cf-Mitigated") == "challenge",
+		strings.Contains(body, "just a moment") || strings.Contains(body, "checking your browser"):
+		return BlockedByBotChallenge
+	case hasCaptcha(body):
+		return BlockedByCaptcha
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return BlockedByRateLimit
+	case resp.StatusCode == http.StatusPaymentRequired || hasPaywall(body):
+		return BlockedByPaywall
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return BlockedByAccessDenied
+	}
+	return ""
+}
+
+// blockedByPage guesses why an article page yielded too little text.
+func blockedByPage(pageText string) string {
+	text := strings.ToLower(pageText)
+	switch {
+	case hasCaptcha(text):
+		return BlockedByCaptcha
+	case hasPaywall(text):
+		return BlockedByPaywall
+	}
+	return BlockedByJavaScript
+}
+
+// blockedByError classifies failures that happened before any response.
+func blockedByError(err error) string {
+	msg := err.Error()
+	switch {
+	case errors.Is(err, domain.ErrCrawlBudgetExhausted):
+		return BlockedByCrawlBudget
+	case errors.Is(err, domain.ErrInvalidURL) &&
+		(strings.Contains(msg, "internal host") || strings.Contains(msg, "not a public address") ||
+			strings.Contains(msg, errBlockedAddress.Error())):
+		return BlockedByNetworkPolicy
+	case strings.Contains(msg, "blocks automated scraping"):
+		return BlockedByPlatformPolicy
+	case strings.Contains(msg, errByteBudgetExceeded.Error()) || strings.Contains(msg, "bytes") && strings.Contains(msg, "exceeds"):
+		return BlockedBySizeLimit
+	case errors.Is(err, context.DeadlineExceeded) || strings.Contains(msg, "Client.Timeout") || strings.Contains(msg, "deadline exceeded"):
+		return BlockedByTimeout
+	}
+	return ""
+}
+
+func hasCaptcha(text string) bool {
+	return strings.Contains(text, "captcha") || strings.Contains(text, "verify you are human") ||
+		strings.Contains(text, "are you a robot")
+}
+
+func hasPaywall(text string) bool {
+	for _, phrase := range []string{"subscribe to continue", "subscribers only", "subscribe to read",
+		"to continue reading", "already a subscriber", "start your subscription"} {
+		if strings.Contains(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+func TestScrapeDiagnostics(t *testing.T) {
+	article := `<html><head><title>Harbor reopens</title></head><body><article>
+<p>The city harbor reopened on Monday after three weeks of repairs to the storm-damaged breakwater, officials said.</p>
+<p>Ferry services will resume their normal timetable from Wednesday, the port authority confirmed in a statement.</p>
+</article></body></html>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/old/harbor-reopens":
+			http.Redirect(w, r, "/2024/harbor-reopens", http.StatusMovedPermanently)
+		case "/2024/harbor-reopens":
+			w.Write([]byte(article))
+		case "/2024/challenge":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<html><title>Just a moment...</title><body>Checking your browser</body></html>"))
+		case "/2024/busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/2024/premium-story":
+			w.Write([]byte(`<html><head><title>Budget leak</title></head><body><article>
+<p>Subscribe to continue reading this story.</p></article></body></html>`))
+		}
+	}))
+	defer srv.Close()
+	scraper := NewScraperService()
+	scraper.httpClient = &http.Client{Transport: &diagnosticsTransport{next: rewriteTransport{target: srv.URL}}}
+	ctx := context.Background()
+
+	result, err := scraper.ScrapeArticle(ctx, "http://harbor.example/old/harbor-reopens")
+	if err != nil {
+		t.Fatalf("ScrapeArticle: %v", err)
+	}
+	diag := result.Diagnostics
+	if diag == nil || diag.Stage != domain.ScrapeStageDone || diag.StatusCode != http.StatusOK ||
+		diag.Robots != domain.RobotsNotChecked || diag.TextChars != len(result.Text) || diag.Paragraphs != 2 {
+		t.Fatalf("diagnostics = %+v", diag)
+	}
+	if len(diag.RedirectChain) != 2 || diag.RedirectChain[0].StatusCode != http.StatusMovedPermanently ||
+		diag.RedirectChain[1].URL != "http://harbor.example/2024/harbor-reopens" {
+		t.Errorf("redirect chain = %+v", diag.RedirectChain)
+	}
+
+	tests := []struct {
+		name      string
+		url       string
+		stage     string
+		blockedBy string
+	}{
+		{"bot challenge", "http://harbor.example/2024/challenge", domain.ScrapeStageFetch, BlockedByBotChallenge},
+		{"rate limited", "http://harbor.example/2024/busy", domain.ScrapeStageFetch, BlockedByRateLimit},
+		{"paywall", "http://harbor.example/2024/premium-story", domain.ScrapeStageExtract, BlockedByPaywall},
+		{"blocked platform", "https://x.com/someone/status/1", domain.ScrapeStageValidate, BlockedByPlatformPolicy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := scraper.ScrapeArticle(ctx, tt.url)
+			var scrapeErr *ScrapeError
+			if !errors.As(err, &scrapeErr) || !errors.Is(err, domain.ErrURLScrapingFailed) {
+				t.Fatalf("err = %v, want a ScrapeError wrapping ErrURLScrapingFailed", err)
+			}
+			diag := ScrapeDiagnosticsOf(err)
+			if diag.Stage != tt.stage || diag.BlockedBy != tt.blockedBy || diag.Error == "" {
+				t.Errorf("diagnostics = %+v, want stage %s blocked by %s", diag, tt.stage, tt.blockedBy)
+			}
+		})
+	}
+}
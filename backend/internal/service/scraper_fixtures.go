@@ -0,0 +1,172 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FixtureMode controls whether the scraper records or replays responses.
+type FixtureMode string
+
+const (
+	FixtureOff    FixtureMode = ""
+	FixtureRecord FixtureMode = "record" // fetch live and save every response
+	FixtureReplay FixtureMode = "replay" // serve saved responses, never touch the network
+)
+
+// fixtureHeaders are the response headers kept in a fixture; the rest
+// (cookies, caching, tracing) do not affect extraction.
+var fixtureHeaders = []string{"Content-Type", "Location", "Retry-After"}
+
+// errFixtureMissing marks replayed requests that have no recorded response.
+var errFixtureMissing = errors.New("no recorded fixture")
+
+// ParseFixtureMode validates a SCRAPER_FIXTURE_MODE value.
+func ParseFixtureMode(s string) (FixtureMode, error) {
+	switch mode := FixtureMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case FixtureOff, FixtureRecord, FixtureReplay:
+		return mode, nil
+	default:
+		return FixtureOff, fmt.Errorf("unknown fixture mode %q (want record or replay)", s)
+	}
+}
+
+// scraperFixture is the metadata file of one recorded response. The body
+// is stored next to it with a .body extension.
+type scraperFixture struct {
+	URL        string            `json:"url"`
+	Status     int               `json:"status"`
+	Header     map[string]string `json:"header,omitempty"`
+	RecordedAt time.Time         `json:"recorded_at"`
+}
+
+// WithFixtures records every response the scraper fetches into dir, or
+// replays them from dir without network access. Redirect hops and
+// robots.txt are recorded like any other response. Responses are keyed by
+// URL, so a fixture directory can hold any number of pages.
+func (s *ScraperService) WithFixtures(mode FixtureMode, dir string) (*ScraperService, error) {
+	if mode == FixtureOff {
+		return s, nil
+	}
+	if dir == "" {
+		return nil, fmt.Errorf("fixture mode %s needs a fixture directory", mode)
+	}
+	if mode == FixtureRecord {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create fixture directory: %w", err)
+		}
+	}
+	s.httpClient.Transport = &fixtureTransport{mode: mode, dir: dir, next: s.httpClient.Transport}
+	return s, nil
+}
+
+// fixtureTransport sits outside the policy transports: recorded bodies
+// are already size-limited, and replay never dials.
+type fixtureTransport struct {
+	mode FixtureMode
+	dir  string
+	next http.RoundTripper
+}
+
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := fixturePath(t.dir, req.URL)
+	if t.mode == FixtureReplay {
+		return replayFixture(req, path)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Request = req // as on replay, even if an inner transport rewrote it
+
+	fixture := scraperFixture{URL: req.URL.String(), Status: resp.StatusCode, RecordedAt: time.Now().UTC()}
+	for _, name := range fixtureHeaders {
+		if v := resp.Header.Get(name); v != "" {
+			if fixture.Header == nil {
+				fixture.Header = make(map[string]string)
+			}
+			fixture.Header[name] = v
+		}
+	}
+	if err := writeFixture(path, fixture, body); err != nil {
+		log.Printf("Warning: failed to record scraper fixture for %s: %v", req.URL, err)
+	}
+	return resp, nil
+}
+
+// fixturePath names a URL's fixture after its host and a hash of the URL.
+func fixturePath(dir string, u *url.URL) string {
+	host := strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(u.Hostname()))
+	sum := sha256.Sum256([]byte(u.String()))
+	return filepath.Join(dir, host+"-"+hex.EncodeToString(sum[:8]))
+}
+
+func writeFixture(path string, fixture scraperFixture, body []byte) error {
+	meta, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".body", body, 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(path+".json", append(meta, '\n'), 0o644)
+}
+
+func replayFixture(req *http.Request, path string) (*http.Response, error) {
+	meta, err := os.ReadFile(path + ".json")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s", errFixtureMissing, req.URL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var fixture scraperFixture
+	if err := json.Unmarshal(meta, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s.json: %w", path, err)
+	}
+	body, err := os.ReadFile(path + ".body")
+	if err != nil {
+		return nil, err
+	}
+
+	header := make(http.Header, len(fixture.Header))
+	for name, v := range fixture.Header {
+		header.Set(name, v)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Status, http.StatusText(fixture.Status)),
+		StatusCode:    fixture.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Health check states.
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+	HealthDown     = "down"
+	HealthSkipped  = "skipped"
+)
+
+// DefaultScraperProbeURL is fetched by the scraper self-check.
+const DefaultScraperProbeURL = "https://example.com/"
+
+// DefaultScraperPoolSize is the number of concurrent scrapes above which
+// the scraper reports itself saturated.
+const DefaultScraperPoolSize = 32
+
+// scraperHealthTTL is how long a self-check result is reused.
+const scraperHealthTTL = 30 * time.Second
+
+// HealthCheck is the result of one subsystem probe.
+type HealthCheck struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+// ScraperHealth is the scraper's self-check report.
+type ScraperHealth struct {
+	Status    string        `json:"status"`
+	Checks    []HealthCheck `json:"checks"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+// ScraperStats are the scraper's running counters.
+type ScraperStats struct {
+	Scrapes  int64 `json:"scrapes"`
+	Failures int64 `json:"failures"`
+	InFlight int64 `json:"in_flight"`
+	PoolSize int   `json:"pool_size"`
+
+	CrawlBudget []CrawlDomainState  `json:"crawl_budget,omitempty"` // per-domain background crawl budget
+	Timeouts    *ScrapeTimeoutStats `json:"timeouts"`               // per-domain fetch latency and adaptive timeouts
+}
+
+// scraperMetrics counts scrapes and tracks the last self-check.
+type scraperMetrics struct {
+	scrapes  atomic.Int64
+	failures atomic.Int64
+	inFlight atomic.Int64
+
+	mu         sync.Mutex
+	lastHealth *ScraperHealth
+}
+
+// WithHealthProbe sets the URL fetched by the self-check and the number of
+// concurrent scrapes treated as saturation.
+func (s *ScraperService) WithHealthProbe(probeURL string, poolSize int) *ScraperService {
+	if probeURL != "" {
+		s.probeURL = probeURL
+	}
+	if poolSize > 0 {
+		s.poolSize = poolSize
+	}
+	return s
+}
+
+// Stats returns the scraper's running counters.
+func (s *ScraperService) Stats() ScraperStats {
+	stats := ScraperStats{
+		Scrapes:  s.metrics.scrapes.Load(),
+		Failures: s.metrics.failures.Load(),
+		InFlight: s.metrics.inFlight.Load(),
+		PoolSize: s.poolSize,
+		Timeouts: s.timeouts.Stats(),
+	}
+	if s.budget != nil {
+		stats.CrawlBudget = s.budget.Stats()
+	}
+	return stats
+}
+
+// SelfCheck probes DNS resolution, outbound connectivity and pool
+// saturation. Results are cached briefly so readiness probes stay cheap.
+func (s *ScraperService) SelfCheck(ctx context.Context) ScraperHealth {
+	s.metrics.mu.Lock()
+	defer s.metrics.mu.Unlock()
+	if last := s.metrics.lastHealth; last != nil && time.Since(last.CheckedAt) < scraperHealthTTL {
+		return *last
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	health := ScraperHealth{CheckedAt: time.Now()}
+	dns := s.checkDNS(ctx)
+	health.Checks = append(health.Checks, dns)
+	if dns.Status == HealthOK {
+		health.Checks = append(health.Checks, s.checkConnectivity(ctx))
+	} else {
+		health.Checks = append(health.Checks, HealthCheck{Name: "connectivity", Status: HealthSkipped, Detail: "DNS check failed"})
+	}
+	health.Checks = append(health.Checks,
+		s.checkRobotsCache(),
+		s.checkPool(),
+	)
+
+	health.Status = HealthOK
+	for _, c := range health.Checks {
+		switch c.Status {
+		case HealthDown:
+			health.Status = HealthDown
+		case HealthDegraded:
+			if health.Status == HealthOK {
+				health.Status = HealthDegraded
+			}
+		}
+	}
+
+	s.metrics.lastHealth = &health
+	return health
+}
+
+func (s *ScraperService) checkDNS(ctx context.Context) HealthCheck {
+	check := HealthCheck{Name: "dns"}
+	u, err := url.Parse(s.probeURL)
+	if err != nil || u.Hostname() == "" {
+		check.Status = HealthDown
+		check.Detail = fmt.Sprintf("invalid probe URL %q", s.probeURL)
+		return check
+	}
+
+	start := time.Now()
+	addrs, err := s.resolver.LookupIPAddr(ctx, u.Hostname())
+	check.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil || len(addrs) == 0 {
+		check.Status = HealthDown
+		check.Detail = fmt.Sprintf("cannot resolve %s: %v", u.Hostname(), err)
+		return check
+	}
+	check.Status = HealthOK
+	check.Detail = fmt.Sprintf("%s resolved to %d addresses", u.Hostname(), len(addrs))
+	return check
+}
+
+func (s *ScraperService) checkConnectivity(ctx context.Context) HealthCheck {
+	check := HealthCheck{Name: "connectivity"}
+	req, err := http.NewRequestWithContext(contextWithByteBudget(ctx, s.maxBytes), http.MethodGet, s.probeURL, nil)
+	if err != nil {
+		check.Status = HealthDown
+		check.Detail = err.Error()
+		return check
+	}
+
+	start := time.Now()
+	resp, err := s.httpClient.Do(req)
+	check.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		check.Status = HealthDown
+		check.Detail = unwrapURLError(err).Error()
+		return check
+	}
+	resp.Body.Close()
+
+	check.Status = HealthOK
+	if resp.StatusCode >= 400 {
+		check.Status = HealthDegraded
+	}
+	check.Detail = fmt.Sprintf("GET %s: HTTP %d", s.probeURL, resp.StatusCode)
+	return check
+}
+
+func (s *ScraperService) checkRobotsCache() HealthCheck {
+	if s.budget == nil {
+		return HealthCheck{Name: "robots_cache", Status: HealthSkipped, Detail: "no crawl budget configured"}
+	}
+	return HealthCheck{
+		Name:   "robots_cache",
+		Status: HealthOK,
+		Detail: fmt.Sprintf("robots.txt cached for %d domains", s.budget.RobotsCached()),
+	}
+}
+
+func (s *ScraperService) checkPool() HealthCheck {
+	inFlight := s.metrics.inFlight.Load()
+	check := HealthCheck{
+		Name:   "pool",
+		Status: HealthOK,
+		Detail: fmt.Sprintf("%d of %d concurrent scrapes in use", inFlight, s.poolSize),
+	}
+	if inFlight >= int64(s.poolSize) {
+		check.Status = HealthDegraded
+	}
+	return check
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScraperSelfCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		probeURL   string
+		wantStatus string
+	}{
+		{name: "healthy", status: http.StatusOK, wantStatus: HealthOK},
+		{name: "probe returns error", status: http.StatusServiceUnavailable, wantStatus: HealthDegraded},
+		{name: "unreachable", probeURL: "http://127.0.0.1:1/", wantStatus: HealthDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			probeURL := srv.URL
+			if tt.probeURL != "" {
+				probeURL = tt.probeURL
+			}
+			s := NewScraperService().WithAllowPrivateNetworks(true).WithHealthProbe(probeURL, 4)
+
+			health := s.SelfCheck(context.Background())
+			if health.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (checks: %+v)", health.Status, tt.wantStatus, health.Checks)
+			}
+			names := map[string]bool{}
+			for _, c := range health.Checks {
+				names[c.Name] = true
+			}
+			for _, want := range []string{"dns", "connectivity", "robots_cache", "pool"} {
+				if !names[want] {
+					t.Errorf("missing %s check", want)
+				}
+			}
+		})
+	}
+}
+
+func TestScraperPoolSaturation(t *testing.T) {
+	s := NewScraperService().WithHealthProbe("", 2)
+	s.metrics.inFlight.Store(2)
+	if c := s.checkPool(); c.Status != HealthDegraded {
+		t.Errorf("pool status = %s, want degraded", c.Status)
+	}
+	s.metrics.inFlight.Store(1)
+	if c := s.checkPool(); c.Status != HealthOK {
+		t.Errorf("pool status = %s, want ok", c.Status)
+	}
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// DefaultMaxScrapeBytes caps the bytes read for one scrape, summed across
+// every redirect hop.
+const DefaultMaxScrapeBytes = 5 << 20
+
+// errBlockedAddress marks connections refused by the network policy.
+var errBlockedAddress = errors.New("destination address is not allowed")
+
+// errByteBudgetExceeded marks responses larger than the scrape budget.
+var errByteBudgetExceeded = errors.New("response exceeds size limit")
+
+// blockedNetworks are ranges the scraper must never connect to: loopback,
+// private, link-local (incl. cloud metadata), CGNAT, multicast and reserved.
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8",
+	"169.254.0.0/16", "172.16.0.0/12", "192.0.0.0/24", "192.0.2.0/24",
+	"192.168.0.0/16", "198.18.0.0/15", "198.51.100.0/24", "203.0.113.0/24",
+	"224.0.0.0/4", "240.0.0.0/4",
+	"::/128", "::1/128", "64:ff9b::/96", "100::/64", "2001:db8::/32",
+	"fc00::/7", "fe80::/10", "ff00::/8",
+)
+
+// allowedPorts lists the destination ports articles may be served on.
+var allowedPorts = map[string]bool{"": true, "80": true, "443": true, "8080": true, "8443": true}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
+
+// isPublicIP reports whether ip is a routable public address.
+func isPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	for _, n := range blockedNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkURLPolicy validates a URL (the original or any redirect hop) before
+// it is fetched.
+func (s *ScraperService) checkURLPolicy(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme must be http or https", domain.ErrInvalidURL)
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return fmt.Errorf("%w: missing host", domain.ErrInvalidURL)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: credentials in URL are not allowed", domain.ErrInvalidURL)
+	}
+	if !allowedPorts[u.Port()] {
+		return fmt.Errorf("%w: port %s is not allowed", domain.ErrInvalidURL, u.Port())
+	}
+
+	for _, blocked := range blockedDomains {
+		if host == blocked || strings.HasSuffix(host, "."+blocked) {
+			return fmt.Errorf("%w: %s blocks automated scraping — paste the article text instead",
+				domain.ErrURLScrapingFailed, host)
+		}
+	}
+
+	if s.allowPrivateNetworks {
+		return nil
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return fmt.Errorf("%w: %s is an internal host", domain.ErrInvalidURL, host)
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return fmt.Errorf("%w: %s is not a public address", domain.ErrInvalidURL, host)
+	}
+	return nil
+}
+
+// safeDialContext resolves the host itself and refuses to connect if any
+// resolved address is non-public, then dials the vetted IP directly so a
+// second (rebinding) DNS answer cannot be used.
+func (s *ScraperService) safeDialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if s.allowPrivateNetworks {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		return dialPublic(ctx, s.resolver, dialer, network, addr)
+	}
+}
+
+// checkPublicHost refuses hosts that are plainly internal: localhost,
+// .internal names and non-public IP literals. Names resolving to private
+// addresses are refused by dialPublic when dialed.
+func checkPublicHost(host string) error {
+	host = strings.ToLower(host)
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return fmt.Errorf("%s is an internal host", host)
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return fmt.Errorf("%s is not a public address", host)
+	}
+	return nil
+}
+
+// dialPublic connects to addr only if every address its host resolves to
+// is public, dialing the vetted addresses directly.
+func dialPublic(ctx context.Context, resolver *net.Resolver, dialer *net.Dialer, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ips, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no addresses found for %s", host)
+	}
+	for _, ip := range ips {
+		if !isPublicIP(ip.IP) {
+			return nil, fmt.Errorf("%w: %s resolves to %s", errBlockedAddress, host, ip.IP)
+		}
+	}
+
+	var lastErr error
+	for _, ip := range ips {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// newPolicyHTTPClient builds the scraper's HTTP client: every redirect hop
+// is re-validated, connections go through the safe dialer and responses
+// draw from a per-scrape byte budget. Partner credentials are added per hop.
+func (s *ScraperService) newPolicyHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = s.safeDialContext(dialer)
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &credentialTransport{scraper: s, next: &diagnosticsTransport{next: &budgetTransport{next: transport}}},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("too many redirects")
+			}
+			return s.checkURLPolicy(req.URL)
+		},
+	}
+}
+
+// byteBudget is the remaining number of bytes a single scrape may read.
+type byteBudget struct {
+	remaining int64
+}
+
+type byteBudgetKey struct{}
+
+func contextWithByteBudget(ctx context.Context, limit int64) context.Context {
+	return context.WithValue(ctx, byteBudgetKey{}, &byteBudget{remaining: limit})
+}
+
+// budgetTransport charges every response body, including redirect bodies
+// drained by the client, against the request's byte budget.
+type budgetTransport struct {
+	next http.RoundTripper
+}
+
+func (t *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	budget, ok := req.Context().Value(byteBudgetKey{}).(*byteBudget)
+	if !ok {
+		return resp, nil
+	}
+	if resp.ContentLength > budget.remaining {
+		resp.Body.Close()
+		return nil, errByteBudgetExceeded
+	}
+	resp.Body = &budgetReader{ReadCloser: resp.Body, budget: budget}
+	return resp, nil
+}
+
+type budgetReader struct {
+	io.ReadCloser
+	budget *byteBudget
+}
+
+func (r *budgetReader) Read(p []byte) (int, error) {
+	if r.budget.remaining <= 0 {
+		return 0, errByteBudgetExceeded
+	}
+	if int64(len(p)) > r.budget.remaining {
+		p = p[:r.budget.remaining]
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.budget.remaining -= int64(n)
+	return n, err
+}
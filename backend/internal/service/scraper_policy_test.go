@@ -0,0 +1,41 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+func TestScraperService_CheckURLPolicy(t *testing.T) {
+	scraper := NewScraperService()
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr error
+	}{
+		{name: "public https", url: "https://example.com/news/story", wantErr: nil},
+		{name: "file scheme", url: "file:///etc/passwd", wantErr: domain.ErrInvalidURL},
+		{name: "loopback literal", url: "http://127.0.0.1/admin", wantErr: domain.ErrInvalidURL},
+		{name: "metadata service", url: "http://169.254.169.254/latest/meta-data", wantErr: domain.ErrInvalidURL},
+		{name: "private ipv6", url: "http://[fd00::1]/", wantErr: domain.ErrInvalidURL},
+		{name: "localhost", url: "http://localhost:8080/", wantErr: domain.ErrInvalidURL},
+		{name: "odd port", url: "https://example.com:6379/", wantErr: domain.ErrInvalidURL},
+		{name: "blocked domain", url: "https://www.facebook.com/post", wantErr: domain.ErrURLScrapingFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := url.Parse(tt.url)
+			err := scraper.checkURLPolicy(u)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("checkURLPolicy() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkURLPolicy() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
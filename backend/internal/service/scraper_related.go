@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// DefaultMaxRelated is how many linked articles a depth=1 scrape fetches.
+const DefaultMaxRelated = 3
+
+// maxRelatedLinks caps the candidate links collected from one page.
+const maxRelatedLinks = 20
+
+// RelatedArticle is the lead of an article linked from the analyzed page.
+type RelatedArticle struct {
+	URL   string
+	Title string
+	Lead  string
+}
+
+// WithMaxRelated sets how many linked articles a depth=1 scrape fetches.
+func (s *ScraperService) WithMaxRelated(n int) *ScraperService {
+	if n > 0 {
+		s.maxRelated = n
+	}
+	return s
+}
+
+// ScrapeRelated fetches linked articles concurrently and returns the lead
+// paragraphs of up to maxRelated of them, in link order. Links that fail to
+// scrape or are not articles are skipped. They count against the crawl
+// budget.
+func (s *ScraperService) ScrapeRelated(ctx context.Context, links []string) []RelatedArticle {
+	ctx = ContextWithCrawl(ctx)
+	if len(links) > 2*s.maxRelated {
+		links = links[:2*s.maxRelated]
+	}
+
+	results := make([]*RelatedArticle, len(links))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, s.maxRelated)
+	for i, link := range links {
+		wg.Add(1)
+		go func(i int, link string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			res, err := s.ScrapeArticle(ctx, link)
+			if err != nil || res.Lead == "" {
+				return
+			}
+			results[i] = &RelatedArticle{URL: link, Title: res.Title, Lead: res.Lead}
+		}(i, link)
+	}
+	wg.Wait()
+
+	related := make([]RelatedArticle, 0, s.maxRelated)
+	for _, r := range results {
+		if r != nil && len(related) < s.maxRelated {
+			related = append(related, *r)
+		}
+	}
+	return related
+}
+
+// extractLead returns the first meaningful paragraph of the article body.
+func extractLead(doc *goquery.Document) string {
+	scope := doc.Selection
+	if article := doc.Find("article"); article.Length() > 0 {
+		scope = article
+	}
+	var lead string
+	scope.Find("p").EachWithBreak(func(_ int, p *goquery.Selection) bool {
+		t := strings.Join(strings.Fields(p.Text()), " ")
+		if len(t) > 80 {
+			lead = t
+			return false
+		}
+		return true
+	})
+	return lead
+}
+
+// extractRelatedLinks collects normalized links to other pages on the same
+// site from the article body, excluding the page itself.
+func extractRelatedLinks(doc *goquery.Document, base *url.URL, self string) []string {
+	host := strings.TrimPrefix(strings.ToLower(base.Hostname()), "www.")
+	seen := map[string]bool{self: true}
+	var links []string
+	doc.Find("article a[href], p a[href]").Each(func(_ int, a *goquery.Selection) {
+		if len(links) >= maxRelatedLinks {
+			return
+		}
+		href, _ := a.Attr("href")
+		ref, err := url.Parse(strings.TrimSpace(href))
+		if err != nil {
+			return
+		}
+		u := base.ResolveReference(ref)
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return
+		}
+		if strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.") != host {
+			return
+		}
+		if strings.Trim(u.Path, "/") == "" {
+			return
+		}
+		normalized := NormalizeURL(u.String())
+		if seen[normalized] {
+			return
+		}
+		seen[normalized] = true
+		links = append(links, normalized)
+	})
+	return links
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestExtractRelatedLinks(t *testing.T) {
+	html := `<html><body>
+<nav><a href="/politics">Politics</a></nav>
+<article>
+  <p>Lead paragraph mentioning <a href="/2024/05/earlier-report?utm_source=x">an earlier report</a>
+  and <a href="https://www.example.com/2024/05/follow-up#comments">a follow-up</a>.</p>
+  <p><a href="https://other.com/story">Elsewhere</a> <a href="/">Home</a>
+  <a href="/2024/05/this-story">Self</a> <a href="mailto:tips@example.com">Tips</a>
+  <a href="/2024/05/earlier-report">Duplicate</a></p>
+</article>
+</body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, _ := url.Parse("https://example.com/2024/05/this-story")
+
+	got := extractRelatedLinks(doc, base, NormalizeURL(base.String()))
+	want := []string{
+		"https://example.com/2024/05/earlier-report",
+		"https://www.example.com/2024/05/follow-up",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractRelatedLinks() = %v, want %v", got, want)
+	}
+}
+
+func TestExtractCanonical(t *testing.T) {
+	final, _ := url.Parse("https://example.com/2024/05/story?ref=home")
+	tests := []struct {
+		name string
+		head string
+		want string
+	}{
+		{"none declared", ``, "https://example.com/2024/05/story?ref=home"},
+		{"relative", `<link rel="canonical" href="/2024/05/story">`, "https://example.com/2024/05/story"},
+		{"www variant", `<link rel="canonical" href="https://www.example.com/2024/05/story">`, "https://www.example.com/2024/05/story"},
+		{"other host", `<link rel="canonical" href="https://bbc.com/news/some-article">`, "https://example.com/2024/05/story?ref=home"},
+		{"subdomain", `<link rel="canonical" href="https://evil.example.com/story">`, "https://example.com/2024/05/story?ref=home"},
+		{"non-http scheme", `<link rel="canonical" href="javascript:alert(1)">`, "https://example.com/2024/05/story?ref=home"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><head>" + tt.head + "</head><body></body></html>"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := extractCanonical(doc, final); got != tt.want {
+				t.Errorf("extractCanonical() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -12,6 +16,17 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
+// ScraperVersion identifies the extraction and cleaning rules below in
+// prediction provenance. Bump it whenever they change what text is scored.
+const ScraperVersion = "v1"
+
+// scraperCleaning lists the cleaning steps ScrapeArticle applies, in order.
+var scraperCleaning = []string{
+	"remove-boilerplate-elements", // scripts, navigation, ads, comments, ...
+	"drop-short-paragraphs",       // paragraphs of 40 characters or fewer
+	"collapse-whitespace",
+}
+
 // blockedDomains lists hosts that block automated scraping and return garbage.
 var blockedDomains = []string{
 	"twitter.com", "x.com",
@@ -20,9 +35,30 @@ var blockedDomains = []string{
 	"youtube.com", "youtu.be",
 }
 
+// allowedContentTypes lists the media types we accept as news articles
+// pages. Documents go to the scraper's document extractors (see
+// WithDocumentExtractor); anything else (images, JSON APIs, archives) is
+// rejected so the analyze endpoint cannot be used as a generic proxy.
+var allowedContentTypes = map[string]bool{
+	"text/html":             true,
+	"application/xhtml+xml": true,
+}
+
 // ScraperService handles URL scraping with best-practice article extraction.
 type ScraperService struct {
-	httpClient *http.Client
+	httpClient           *http.Client
+	resolver             *net.Resolver
+	maxBytes             int64
+	maxRelated           int
+	allowPrivateNetworks bool
+	probeURL             string
+	poolSize             int
+	credentials          *ScraperCredentials
+	budget               *CrawlBudget
+	timeouts             *ScrapeTimeouts
+	quotas               *QuotaTracker
+	documents            map[string]DocumentExtractor // by media type
+	metrics              scraperMetrics
 }
 
 // ScrapeResult contains extracted article data.
@@ -31,99 +67,274 @@ type ScrapeResult struct {
 	Title       string
 	Description string
 	Author      string
-	Source      string // hostname
+	Source      string     // hostname
+	PublishedAt *time.Time // article:published_time or equivalent, if declared
+	FinalURL    string     // URL after following redirects
+	Canonical   string     // normalized <link rel="canonical"> or final URL
+	Lead        string     // first paragraph of the article body
+	Links       []string   // same-site links found in the article body
+	SiteName    string     // og:site_name or the JSON-LD publisher name
+	FaviconURL  string     // declared site icon, else /favicon.ico
+	LogoURL     string     // JSON-LD publisher logo, if declared
+	Extractor   string     // body extraction strategy that produced Text
+	ContentType string     // media type of the response: text/html or a document type
+
+	Diagnostics *domain.ScrapeDiagnostics `json:"-"` // how the scrape went; timings vary per run
 }
 
 // NewScraperService creates a new scraper service.
 func NewScraperService() *ScraperService {
-	return &ScraperService{
-		httpClient: &http.Client{
-			Timeout: 15 * time.Second,
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				if len(via) >= 10 {
-					return fmt.Errorf("too many redirects")
-				}
-				return nil
-			},
-		},
+	s := &ScraperService{
+		resolver:   net.DefaultResolver,
+		maxBytes:   DefaultMaxScrapeBytes,
+		maxRelated: DefaultMaxRelated,
+		probeURL:   DefaultScraperProbeURL,
+		poolSize:   DefaultScraperPoolSize,
+		timeouts:   NewScrapeTimeouts(),
+		documents:  defaultDocumentExtractors(),
 	}
+	// Page fetches get a per-domain deadline from s.timeouts; the client
+	// timeout only has to cover the longest of them.
+	s.httpClient = s.newPolicyHTTPClient(s.timeouts.Ceiling())
+	return s
+}
+
+// WithTimeouts sets the page fetch timeout for domains without history and
+// the range per-domain adaptive timeouts are kept in.
+func (s *ScraperService) WithTimeouts(defaultTimeout, floor, ceiling time.Duration) *ScraperService {
+	s.timeouts.WithBounds(defaultTimeout, floor, ceiling)
+	s.httpClient.Timeout = s.timeouts.Ceiling()
+	return s
+}
+
+// WithMaxBytes caps the bytes read per scrape across all redirect hops.
+func (s *ScraperService) WithMaxBytes(maxBytes int64) *ScraperService {
+	if maxBytes > 0 {
+		s.maxBytes = maxBytes
+	}
+	return s
+}
+
+// WithAllowPrivateNetworks disables the private-address checks. Only meant
+// for local development against sites on localhost.
+func (s *ScraperService) WithAllowPrivateNetworks(allow bool) *ScraperService {
+	s.allowPrivateNetworks = allow
+	return s
+}
+
+// WithOutboundAudit records every page, redirect hop, robots.txt and
+// branding image fetch in audit. Call it before WithFixtures.
+func (s *ScraperService) WithOutboundAudit(audit *OutboundAudit) *ScraperService {
+	audit.Client(s.httpClient, PurposeScrape)
+	return s
+}
+
+// WithCrawlBudget limits background crawl fetches (see ContextWithCrawl)
+// per domain. robots.txt is fetched through the scraper's URL policy.
+func (s *ScraperService) WithCrawlBudget(budget *CrawlBudget) *ScraperService {
+	budget.httpClient = s.httpClient
+	s.budget = budget
+	return s
+}
+
+// WithQuotas charges every article fetched, related articles included, to
+// the request's scrape quota (see ContextWithQuota).
+func (s *ScraperService) WithQuotas(quotas *QuotaTracker) *ScraperService {
+	s.quotas = quotas
+	return s
 }
 
 // ScrapeURL fetches a URL and returns extracted article content.
 // Kept for backward-compat — returns only the body text.
 func (s *ScraperService) ScrapeURL(urlStr string) (string, error) {
-	res, err := s.ScrapeArticle(urlStr)
+	res, err := s.ScrapeArticle(context.Background(), urlStr)
 	if err != nil {
 		return "", err
 	}
 	return res.Text, nil
 }
 
-// ScrapeArticle fetches a URL and returns structured article data.
-func (s *ScraperService) ScrapeArticle(urlStr string) (*ScrapeResult, error) {
+// ScrapeArticle fetches a URL and returns structured article data. The
+// result, or a *ScrapeError on failure, carries the scrape's diagnostics.
+func (s *ScraperService) ScrapeArticle(ctx context.Context, urlStr string) (result *ScrapeResult, err error) {
+	if err := s.quotas.Consume(ctx, domain.QuotaScrapes, 1); err != nil {
+		return nil, err
+	}
+	s.metrics.scrapes.Add(1)
+	s.metrics.inFlight.Add(1)
+	defer func() {
+		s.metrics.inFlight.Add(-1)
+		if err != nil && !errors.Is(err, domain.ErrCrawlBudgetExhausted) {
+			s.metrics.failures.Add(1)
+		}
+	}()
+
+	start := time.Now()
+	diag := &domain.ScrapeDiagnostics{URL: urlStr, Stage: domain.ScrapeStageValidate, Robots: domain.RobotsNotChecked}
+	result, err = s.scrapeArticle(contextWithDiagnostics(ctx, diag), urlStr, diag)
+	diag.DurationMS = time.Since(start).Milliseconds()
+	if err != nil {
+		diag.Error = err.Error()
+		if diag.BlockedBy == "" {
+			diag.BlockedBy = blockedByError(err)
+		}
+		return nil, &ScrapeError{Err: err, Diagnostics: diag}
+	}
+	diag.Stage = domain.ScrapeStageDone
+	result.Diagnostics = diag
+	return result, nil
+}
+
+func (s *ScraperService) scrapeArticle(ctx context.Context, urlStr string, diag *domain.ScrapeDiagnostics) (*ScrapeResult, error) {
 	// ---------- validate ----------
 	parsed, err := s.validateURL(urlStr)
 	if err != nil {
 		return nil, err
 	}
 
-	// ---------- blocked domains ----------
 	host := strings.ToLower(parsed.Hostname())
-	for _, blocked := range blockedDomains {
-		if host == blocked || strings.HasSuffix(host, "."+blocked) {
-			return nil, fmt.Errorf("%w: %s blocks automated scraping — paste the article text instead",
-				domain.ErrURLScrapingFailed, host)
+	if s.budget != nil && isCrawl(ctx) {
+		if err := s.budget.Acquire(ctx, parsed); err != nil {
+			diag.Robots = domain.RobotsBudgetExhausted
+			return nil, err
 		}
+		diag.Robots = domain.RobotsCrawlDelay
 	}
 
 	// ---------- fetch ----------
-	req, err := http.NewRequest("GET", urlStr, nil)
+	diag.Stage = domain.ScrapeStageFetch
+	timeout := s.timeouts.Timeout(host)
+	diag.TimeoutMS = timeout.Milliseconds()
+	parent := ctx
+	ctx, cancel := context.WithTimeout(contextWithByteBudget(ctx, s.maxBytes), timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidURL, err)
 	}
 	req.Header.Set("User-Agent",
 		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 "+
 			"(KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36")
 	req.Header.Set("Accept", "text/html,application/xhtml+xml")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	setTraceHeaders(req)
 
+	start := time.Now()
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
+		if fetchTimedOut(parent, err) {
+			s.timeouts.Observe(host, timeout, true)
+		}
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			diag.FinalURL = urlErr.URL
+		}
+		// Redirects that violate the URL policy surface the policy error.
+		if errors.Is(err, domain.ErrInvalidURL) || errors.Is(err, domain.ErrURLScrapingFailed) {
+			return nil, unwrapURLError(err)
+		}
+		if errors.Is(err, errBlockedAddress) {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidURL, unwrapURLError(err))
+		}
 		return nil, fmt.Errorf("%w: %v", domain.ErrURLScrapingFailed, err)
 	}
 	defer resp.Body.Close()
+	diag.FinalURL = resp.Request.URL.String()
+	diag.StatusCode = resp.StatusCode
+	diag.ContentType = resp.Header.Get("Content-Type")
+	if s.budget != nil {
+		s.budget.Report(host, resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
 
 	if resp.StatusCode != http.StatusOK {
+		s.timeouts.Observe(host, time.Since(start), false)
+		diag.BlockedBy = blockedByResponse(resp)
 		return nil, fmt.Errorf("%w: HTTP %d from %s",
 			domain.ErrURLScrapingFailed, resp.StatusCode, host)
 	}
 
-	ct := resp.Header.Get("Content-Type")
-	if ct != "" && !strings.Contains(ct, "html") {
-		return nil, fmt.Errorf("%w: expected HTML, got %s", domain.ErrURLScrapingFailed, ct)
+	mediaType := "text/html"
+	var body io.Reader = resp.Body
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err = mime.ParseMediaType(ct)
+		if err == nil && mediaType == octetStreamMediaType {
+			mediaType, body = sniffDocument(resp.Body, resp.Request.URL.Path)
+		}
+		if err != nil || (!allowedContentTypes[mediaType] && !s.isDocument(mediaType)) {
+			return nil, fmt.Errorf("%w: %s is not a news article page", domain.ErrUnsupportedContentType, ct)
+		}
+	}
+
+	if s.isDocument(mediaType) {
+		diag.Stage = domain.ScrapeStageParse
+		data, err := io.ReadAll(body)
+		s.timeouts.Observe(host, time.Since(start), fetchTimedOut(parent, err))
+		if err != nil {
+			if errors.Is(err, errByteBudgetExceeded) {
+				return nil, fmt.Errorf("%w: document from %s exceeds %d bytes", domain.ErrURLScrapingFailed, host, s.maxBytes)
+			}
+			return nil, fmt.Errorf("%w: %v", domain.ErrURLScrapingFailed, err)
+		}
+		diag.Stage = domain.ScrapeStageExtract
+		result, err := s.extractDocument(ctx, host, resp.Request.URL, mediaType, data)
+		if err != nil {
+			return nil, err
+		}
+		diag.Extractor = result.Extractor
+		diag.TextChars = len(result.Text)
+		if len(result.Text) < 80 {
+			return nil, fmt.Errorf(
+				"%w: extracted only %d chars from the document at %s — it may be scanned or use fonts without extractable text",
+				domain.ErrURLScrapingFailed, len(result.Text), host)
+		}
+		return result, nil
 	}
 
 	// ---------- parse ----------
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	diag.Stage = domain.ScrapeStageParse
+	doc, err := goquery.NewDocumentFromReader(body)
+	s.timeouts.Observe(host, time.Since(start), fetchTimedOut(parent, err))
 	if err != nil {
+		if errors.Is(err, errByteBudgetExceeded) {
+			return nil, fmt.Errorf("%w: page from %s exceeds %d bytes", domain.ErrURLScrapingFailed, host, s.maxBytes)
+		}
 		return nil, fmt.Errorf("%w: %v", domain.ErrURLScrapingFailed, err)
 	}
 
-	result := &ScrapeResult{Source: host}
+	result := &ScrapeResult{Source: host, FinalURL: resp.Request.URL.String(), ContentType: mediaType}
 
 	// Extract metadata first (before removing elements).
 	result.Title, result.Description, result.Author = extractMeta(doc)
+	result.PublishedAt = extractPublishedAt(doc)
+	result.Canonical = NormalizeURL(extractCanonical(doc, resp.Request.URL))
+	result.SiteName, result.FaviconURL, result.LogoURL = extractBranding(doc, resp.Request.URL)
+
+	// Reject homepages, section listings, video pages and soft 404s.
+	diag.Stage = domain.ScrapeStageClassify
+	pageType := ClassifyPage(resp.Request.URL, doc)
+	diag.PageType = string(pageType)
+	if pageType != PageArticle {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotAnArticle, pageTypeHelp[pageType])
+	}
+	pageText := doc.Text()
 
 	// Remove noise.
+	diag.Stage = domain.ScrapeStageExtract
 	doc.Find("script, style, nav, header, footer, aside, form, iframe, " +
 		"noscript, svg, button, [role='navigation'], [role='banner'], " +
 		"[role='complementary'], .sidebar, .comments, .social-share, " +
 		".newsletter-signup, .ad, .advertisement, #comments").Remove()
 
 	// Extract body.
-	result.Text = extractArticleBody(doc)
+	result.Text, result.Extractor = extractArticleBody(doc)
+	result.Lead = extractLead(doc)
+	result.Links = extractRelatedLinks(doc, resp.Request.URL, result.Canonical)
+	diag.Extractor = result.Extractor
+	diag.TextChars = len(result.Text)
+	diag.Paragraphs = doc.Find("p").Length()
 
 	if len(result.Text) < 80 {
+		diag.BlockedBy = blockedByPage(pageText)
 		return nil, fmt.Errorf(
 			"%w: extracted only %d chars from %s — the site may require JavaScript rendering",
 			domain.ErrURLScrapingFailed, len(result.Text), host)
@@ -139,15 +350,21 @@ func (s *ScraperService) validateURL(urlStr string) (*url.URL, error) {
 	if err != nil {
 		return nil, domain.ErrInvalidURL
 	}
-	if u.Scheme != "http" && u.Scheme != "https" {
-		return nil, fmt.Errorf("%w: scheme must be http or https", domain.ErrInvalidURL)
-	}
-	if u.Host == "" {
-		return nil, fmt.Errorf("%w: missing host", domain.ErrInvalidURL)
+	if err := s.checkURLPolicy(u); err != nil {
+		return nil, err
 	}
 	return u, nil
 }
 
+// unwrapURLError strips the *url.Error wrapper added by http.Client.
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
 // extractMeta pulls title, description, and author from <head> metadata.
 func extractMeta(doc *goquery.Document) (title, description, author string) {
 	// Title: og:title → <title>
@@ -176,12 +393,61 @@ func extractMeta(doc *goquery.Document) (title, description, author string) {
 	return
 }
 
-// extractArticleBody applies a priority cascade to pull the article body text.
-func extractArticleBody(doc *goquery.Document) string {
+// publishedTimeSelectors are checked in order for the publication date.
+var publishedTimeSelectors = []struct{ selector, attr string }{
+	{`meta[property="article:published_time"]`, "content"},
+	{`meta[name="pubdate"]`, "content"},
+	{`meta[itemprop="datePublished"]`, "content"},
+	{`time[datetime]`, "datetime"},
+}
+
+// extractPublishedAt returns the article's declared publication time.
+func extractPublishedAt(doc *goquery.Document) *time.Time {
+	for _, sel := range publishedTimeSelectors {
+		value, ok := doc.Find(sel.selector).First().Attr(sel.attr)
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+			if t, err := time.Parse(layout, value); err == nil {
+				return &t
+			}
+		}
+	}
+	return nil
+}
+
+// extractCanonical returns the page's <link rel="canonical"> resolved
+// against the final URL, or the final URL itself when none is declared.
+// A canonical on another host is ignored: verdicts are shared by canonical
+// URL, so a page must not be able to claim someone else's article.
+func extractCanonical(doc *goquery.Document, finalURL *url.URL) string {
+	href, ok := doc.Find(`link[rel="canonical"]`).First().Attr("href")
+	if !ok || strings.TrimSpace(href) == "" {
+		return finalURL.String()
+	}
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return finalURL.String()
+	}
+	canonical := finalURL.ResolveReference(ref)
+	if canonical.Scheme != "http" && canonical.Scheme != "https" {
+		return finalURL.String()
+	}
+	if normalizeDomain(canonical.Hostname()) != normalizeDomain(finalURL.Hostname()) {
+		return finalURL.String()
+	}
+	return canonical.String()
+}
+
+// extractArticleBody applies a priority cascade to pull the article body
+// text, and names the strategy that produced it.
+func extractArticleBody(doc *goquery.Document) (text, extractor string) {
 	// ── Strategy 1: <article> tag ──
 	if article := doc.Find("article"); article.Length() > 0 {
 		if text := paragraphsFrom(article); len(text) > 200 {
-			return text
+			return text, "article-tag"
 		}
 	}
 
@@ -206,7 +472,7 @@ func extractArticleBody(doc *goquery.Document) string {
 	})
 	if best.node != nil && best.score > 300 {
 		if text := paragraphsFrom(best.node); len(text) > 200 {
-			return text
+			return text, "paragraph-density"
 		}
 	}
 
@@ -223,12 +489,12 @@ func extractArticleBody(doc *goquery.Document) string {
 			continue
 		}
 		if text := paragraphsFrom(node); len(text) > 200 {
-			return text
+			return text, "selector " + sel
 		}
 	}
 
 	// ── Strategy 4: all <p> fallback ──
-	return paragraphsFrom(doc.Selection)
+	return paragraphsFrom(doc.Selection), "all-paragraphs"
 }
 
 // paragraphsFrom concatenates meaningful <p> text within a container.
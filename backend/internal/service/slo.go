@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stages observed by the SLO tracker.
+const (
+	StageAnalyze = "analyze" // end-to-end interactive analysis
+	StageScrape  = "scrape"
+	StageML      = "ml"
+)
+
+// SLO is a latency objective: Objective of Stage events must succeed within
+// Threshold.
+type SLO struct {
+	Name      string        `json:"name"`
+	Stage     string        `json:"stage"`
+	Objective float64       `json:"objective"` // e.g. 0.95
+	Threshold time.Duration `json:"-"`
+}
+
+// burnWindow pairs a long and short window with the burn rate that should
+// page (multi-window burn-rate alerting, as in the Google SRE workbook).
+type burnWindow struct {
+	name     string
+	long     time.Duration
+	short    time.Duration
+	burnRate float64
+	severity string
+}
+
+var burnWindows = []burnWindow{
+	{name: "fast", long: time.Hour, short: 5 * time.Minute, burnRate: 14.4, severity: SeverityCritical},
+	{name: "slow", long: 6 * time.Hour, short: 30 * time.Minute, burnRate: 6, severity: SeverityWarning},
+}
+
+// sloBucketCount covers the longest burn window at one-minute resolution.
+const sloBucketCount = 6 * 60
+
+type sloBucket struct {
+	minute int64 // unix minute this bucket holds
+	total  int
+	good   int
+}
+
+type sloSeries struct {
+	slo     SLO
+	buckets [sloBucketCount]sloBucket
+	firing  map[string]bool // burn window name -> alert currently firing
+}
+
+// WindowStatus is the compliance of one SLO over one window.
+type WindowStatus struct {
+	Window     string  `json:"window"`
+	Total      int     `json:"total"`
+	Good       int     `json:"good"`
+	Compliance float64 `json:"compliance"`
+	BurnRate   float64 `json:"burn_rate"`
+}
+
+// SLOStatus is the burn-rate state of one SLO.
+type SLOStatus struct {
+	SLO
+	ThresholdMS int64          `json:"threshold_ms"`
+	Windows     []WindowStatus `json:"windows"`
+	Burning     []string       `json:"burning"` // burn windows currently alerting
+}
+
+// SLOTracker records per-stage latencies and evaluates burn rates.
+type SLOTracker struct {
+	mu      sync.Mutex
+	series  []*sloSeries
+	alerter Alerter
+	now     func() time.Time
+}
+
+// NewSLOTracker creates a tracker for the given SLOs.
+func NewSLOTracker(slos []SLO, alerter Alerter) *SLOTracker {
+	if alerter == nil {
+		alerter = LogAlerter{}
+	}
+	t := &SLOTracker{alerter: alerter, now: time.Now}
+	for _, slo := range slos {
+		t.series = append(t.series, &sloSeries{slo: slo, firing: make(map[string]bool)})
+	}
+	return t
+}
+
+// Observe records one event for a stage. An event is good when it
+// succeeded within the SLO's latency threshold.
+func (t *SLOTracker) Observe(stage string, latency time.Duration, success bool) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	minute := t.now().Unix() / 60
+	for _, s := range t.series {
+		if s.slo.Stage != stage {
+			continue
+		}
+		b := &s.buckets[minute%sloBucketCount]
+		if b.minute != minute {
+			*b = sloBucket{minute: minute}
+		}
+		b.total++
+		if success && latency <= s.slo.Threshold {
+			b.good++
+		}
+	}
+}
+
+// Status returns the current compliance and burn state of every SLO.
+func (t *SLOTracker) Status() []SLOStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	statuses := make([]SLOStatus, 0, len(t.series))
+	for _, s := range t.series {
+		status := SLOStatus{SLO: s.slo, ThresholdMS: s.slo.Threshold.Milliseconds(), Burning: []string{}}
+		seen := map[time.Duration]bool{}
+		for _, w := range burnWindows {
+			for _, d := range []time.Duration{w.short, w.long} {
+				if seen[d] {
+					continue
+				}
+				seen[d] = true
+				status.Windows = append(status.Windows, t.window(s, d))
+			}
+			if s.firing[w.name] {
+				status.Burning = append(status.Burning, w.name)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Evaluate checks every burn window and fires alerts on state changes.
+func (t *SLOTracker) Evaluate() {
+	t.mu.Lock()
+	var alerts []Alert
+	for _, s := range t.series {
+		for _, w := range burnWindows {
+			long := t.window(s, w.long)
+			short := t.window(s, w.short)
+			burning := long.Total > 0 && short.Total > 0 &&
+				long.BurnRate >= w.burnRate && short.BurnRate >= w.burnRate
+
+			if burning == s.firing[w.name] {
+				continue
+			}
+			s.firing[w.name] = burning
+
+			alert := Alert{
+				Name:    fmt.Sprintf("SLOBurnRate%s", capitalize(w.name)),
+				Labels:  map[string]string{"slo": s.slo.Name, "stage": s.slo.Stage, "window": w.name},
+				FiredAt: t.now(),
+			}
+			if burning {
+				alert.Severity = w.severity
+				alert.Summary = fmt.Sprintf("%s is burning error budget %.1fx too fast (%.1f%% good over %s, objective %.1f%%)",
+					s.slo.Name, long.BurnRate, long.Compliance*100, w.long, s.slo.Objective*100)
+			} else {
+				alert.Severity = SeverityResolved
+				alert.Summary = fmt.Sprintf("%s %s burn rate back under %.1fx", s.slo.Name, w.name, w.burnRate)
+			}
+			alerts = append(alerts, alert)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, alert := range alerts {
+		if err := t.alerter.Fire(alert); err != nil {
+			log.Printf("Warning: failed to deliver alert %s: %v", alert.Name, err)
+		}
+	}
+}
+
+// Run evaluates burn rates every interval until ctx is cancelled.
+func (t *SLOTracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.Evaluate()
+		}
+	}
+}
+
+// window sums the buckets covering the last d. Callers must hold t.mu.
+func (t *SLOTracker) window(s *sloSeries, d time.Duration) WindowStatus {
+	now := t.now().Unix() / 60
+	minutes := int64(d / time.Minute)
+	ws := WindowStatus{Window: d.String()}
+	for i := int64(0); i < minutes && i < sloBucketCount; i++ {
+		b := s.buckets[(now-i)%sloBucketCount]
+		if b.minute != now-i {
+			continue
+		}
+		ws.Total += b.total
+		ws.Good += b.good
+	}
+	ws.Compliance = 1
+	if ws.Total > 0 {
+		ws.Compliance = float64(ws.Good) / float64(ws.Total)
+	}
+	if budget := 1 - s.slo.Objective; budget > 0 {
+		ws.BurnRate = (1 - ws.Compliance) / budget
+	}
+	return ws
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+type recordingAlerter struct {
+	alerts []Alert
+}
+
+func (r *recordingAlerter) Fire(alert Alert) error {
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func TestSLOTrackerBurnRateAlerts(t *testing.T) {
+	alerter := &recordingAlerter{}
+	tracker := NewSLOTracker([]SLO{
+		{Name: "analyze-latency", Stage: StageAnalyze, Objective: 0.95, Threshold: time.Second},
+	}, alerter)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	// Other stages are ignored.
+	tracker.Observe(StageML, 10*time.Second, false)
+
+	for i := 0; i < 10; i++ {
+		tracker.Observe(StageAnalyze, 100*time.Millisecond, true)
+	}
+	tracker.Evaluate()
+	if len(alerter.alerts) != 0 {
+		t.Fatalf("expected no alerts while compliant, got %v", alerter.alerts)
+	}
+
+	// Slow and failed requests both burn budget.
+	for i := 0; i < 15; i++ {
+		tracker.Observe(StageAnalyze, 2*time.Second, true)
+		tracker.Observe(StageAnalyze, 100*time.Millisecond, false)
+	}
+	tracker.Evaluate()
+	if len(alerter.alerts) != 2 {
+		t.Fatalf("expected fast and slow burn alerts, got %v", alerter.alerts)
+	}
+	if alerter.alerts[0].Severity != SeverityCritical || alerter.alerts[0].Name != "SLOBurnRateFast" {
+		t.Errorf("unexpected first alert %+v", alerter.alerts[0])
+	}
+
+	// No repeat while the state is unchanged.
+	tracker.Evaluate()
+	if len(alerter.alerts) != 2 {
+		t.Fatalf("expected alerts to fire once, got %d", len(alerter.alerts))
+	}
+
+	status := tracker.Status()
+	if len(status) != 1 || status[0].Windows[0].Total != 40 || len(status[0].Burning) != 2 {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	// Once the bad minutes age out the alerts resolve.
+	now = now.Add(7 * time.Hour)
+	tracker.Observe(StageAnalyze, 100*time.Millisecond, true)
+	tracker.Evaluate()
+	if len(alerter.alerts) != 4 || alerter.alerts[3].Severity != SeverityResolved {
+		t.Errorf("expected resolved alerts, got %v", alerter.alerts)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// SourceRegistry holds curated information about known publishers.
+type SourceRegistry struct {
+	sources map[string]domain.Source
+}
+
+// NewSourceRegistry creates a registry from a list of sources.
+func NewSourceRegistry(sources []domain.Source) *SourceRegistry {
+	r := &SourceRegistry{sources: make(map[string]domain.Source)}
+	for _, src := range sources {
+		src.Domain = normalizeDomain(src.Domain)
+		r.sources[src.Domain] = src
+	}
+	return r
+}
+
+// LoadSourceRegistry reads a JSON array of sources from a file.
+func LoadSourceRegistry(path string) (*SourceRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source registry: %w", err)
+	}
+	var sources []domain.Source
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil, fmt.Errorf("failed to parse source registry: %w", err)
+	}
+	return NewSourceRegistry(sources), nil
+}
+
+// Lookup returns the registry entry for a domain, falling back to its
+// parent domains (news.example.com → example.com).
+func (r *SourceRegistry) Lookup(host string) (*domain.Source, bool) {
+	if r == nil {
+		return nil, false
+	}
+	host = normalizeDomain(host)
+	for host != "" {
+		if src, ok := r.sources[host]; ok {
+			return &src, true
+		}
+		dot := strings.IndexByte(host, '.')
+		if dot < 0 {
+			break
+		}
+		host = host[dot+1:]
+	}
+	return nil, false
+}
+
+// DomainsIn returns the registered domains in a category.
+func (r *SourceRegistry) DomainsIn(category string) []string {
+	if r == nil {
+		return nil
+	}
+	var domains []string
+	for d, src := range r.sources {
+		if strings.EqualFold(src.Category, category) {
+			domains = append(domains, d)
+		}
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+// Len returns the number of registered sources.
+func (r *SourceRegistry) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.sources)
+}
+
+// normalizeDomain lowercases a host and strips a leading "www.".
+func normalizeDomain(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	return strings.TrimPrefix(host, "www.")
+}
@@ -0,0 +1,614 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ssoStateTTL bounds how long a user may take at the identity provider.
+const ssoStateTTL = 10 * time.Minute
+
+// jwksRefreshInterval limits refetching signing keys on unknown key IDs.
+const jwksRefreshInterval = time.Minute
+
+// SSOProvider is one organization's OpenID Connect identity provider.
+// Secrets may reference environment variables as ${NAME}.
+type SSOProvider struct {
+	OrgID          string            `json:"org_id"`
+	Name           string            `json:"name"`
+	Protocol       string            `json:"protocol,omitempty"` // "oidc" (default); SAML is not supported
+	Issuer         string            `json:"issuer"`
+	ClientID       string            `json:"client_id"`
+	ClientSecret   string            `json:"client_secret"`
+	Scopes         []string          `json:"scopes,omitempty"`       // default openid, email, profile
+	GroupsClaim    string            `json:"groups_claim,omitempty"` // default "groups"
+	RoleMapping    map[string]string `json:"role_mapping,omitempty"` // IdP group -> role
+	DefaultRole    string            `json:"default_role,omitempty"` // default member
+	Plan           string            `json:"plan,omitempty"`
+	AllowedDomains []string          `json:"allowed_domains,omitempty"` // email domains; empty allows any
+}
+
+// SSOLogin is the outcome of a completed sign-on.
+type SSOLogin struct {
+	User    *domain.User `json:"user"`
+	Token   string       `json:"token"`
+	Session Session      `json:"session"`
+}
+
+// ssoState round-trips through the identity provider. The PKCE verifier
+// is derived from the nonce rather than carried, so the state in the
+// browser's URL does not reveal it.
+type ssoState struct {
+	OrgID     string    `json:"org"`
+	Nonce     string    `json:"nonce"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+// oidcDiscovery is the part of the provider metadata we use.
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jwkSet struct {
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// SSOService signs users in through their organization's identity
+// provider, provisioning accounts on first login and mapping IdP groups
+// to roles on every login.
+type SSOService struct {
+	providers   map[string]SSOProvider
+	users       *UserService
+	sessions    *SessionTokens
+	redirectURL string
+	httpClient  *http.Client
+	now         func() time.Time
+
+	mu        sync.Mutex
+	discovery map[string]*oidcDiscovery
+	jwks      map[string]*jwkSet
+}
+
+// NewSSOService validates the provider configurations. redirectURL is the
+// public URL of the callback endpoint registered with every provider.
+func NewSSOService(providers []SSOProvider, users *UserService, sessions *SessionTokens, redirectURL string) (*SSOService, error) {
+	s := &SSOService{
+		providers:   make(map[string]SSOProvider, len(providers)),
+		users:       users,
+		sessions:    sessions,
+		redirectURL: redirectURL,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		now:         time.Now,
+		discovery:   make(map[string]*oidcDiscovery),
+		jwks:        make(map[string]*jwkSet),
+	}
+	for _, p := range providers {
+		p.OrgID = strings.ToLower(strings.TrimSpace(p.OrgID))
+		switch {
+		case p.OrgID == "":
+			return nil, errors.New("sso provider has no org_id")
+		case p.Protocol != "" && p.Protocol != "oidc":
+			return nil, fmt.Errorf("sso provider %s: unsupported protocol %q (only oidc)", p.OrgID, p.Protocol)
+		case p.Issuer == "" || p.ClientID == "":
+			return nil, fmt.Errorf("sso provider %s: issuer and client_id are required", p.OrgID)
+		}
+		if _, dup := s.providers[p.OrgID]; dup {
+			return nil, fmt.Errorf("sso provider %s configured twice", p.OrgID)
+		}
+		p.Issuer = strings.TrimRight(p.Issuer, "/")
+		p.ClientSecret = os.ExpandEnv(p.ClientSecret)
+		if len(p.Scopes) == 0 {
+			p.Scopes = []string{"openid", "email", "profile"}
+		}
+		if p.GroupsClaim == "" {
+			p.GroupsClaim = "groups"
+		}
+		if p.DefaultRole == "" {
+			p.DefaultRole = domain.RoleMember
+		}
+		for group, role := range p.RoleMapping {
+			if !domain.IsValidRole(role) {
+				return nil, fmt.Errorf("sso provider %s: group %q maps to unknown role %q", p.OrgID, group, role)
+			}
+		}
+		if !domain.IsValidRole(p.DefaultRole) {
+			return nil, fmt.Errorf("sso provider %s: unknown default_role %q", p.OrgID, p.DefaultRole)
+		}
+		s.providers[p.OrgID] = p
+	}
+	return s, nil
+}
+
+// LoadSSOProviders reads a JSON array of SSOProvider from a file. A file
+// readable by other users is loaded with a warning.
+func LoadSSOProviders(path string) ([]SSOProvider, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sso providers: %w", err)
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		log.Printf("WARNING: sso providers file %s is accessible by other users (mode %v)", path, info.Mode().Perm())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sso providers: %w", err)
+	}
+	var providers []SSOProvider
+	if err := json.Unmarshal(data, &providers); err != nil {
+		return nil, fmt.Errorf("failed to parse sso providers: %w", err)
+	}
+	return providers, nil
+}
+
+// WithOutboundAudit records discovery, key and token requests to the
+// identity providers in audit.
+func (s *SSOService) WithOutboundAudit(audit *OutboundAudit) *SSOService {
+	audit.Client(s.httpClient, PurposeSSO)
+	return s
+}
+
+// Organizations returns the org IDs and display names users can sign in
+// with, sorted by org ID.
+func (s *SSOService) Organizations() []map[string]string {
+	orgs := make([]map[string]string, 0, len(s.providers))
+	for _, p := range s.providers {
+		orgs = append(orgs, map[string]string{"org_id": p.OrgID, "name": p.Name})
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i]["org_id"] < orgs[j]["org_id"] })
+	return orgs
+}
+
+// LoginURL starts a sign-on for an organization. It returns the identity
+// provider URL to send the browser to and the state the callback must
+// present.
+func (s *SSOService) LoginURL(ctx context.Context, orgID string) (string, string, error) {
+	provider, ok := s.providers[strings.ToLower(orgID)]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", domain.ErrUnknownOrganization, orgID)
+	}
+	meta, err := s.discover(ctx, provider)
+	if err != nil {
+		return "", "", err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", "", err
+	}
+	st := ssoState{
+		OrgID:     provider.OrgID,
+		Nonce:     base64.RawURLEncoding.EncodeToString(nonce),
+		ExpiresAt: s.now().Add(ssoStateTTL).UTC(),
+	}
+	state, err := s.sessions.seal("sso-state", st)
+	if err != nil {
+		return "", "", err
+	}
+	challenge := sha256.Sum256([]byte(s.pkceVerifier(st.Nonce)))
+
+	authURL, err := url.Parse(meta.AuthorizationEndpoint)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: bad authorization endpoint: %v", domain.ErrSSOFailed, err)
+	}
+	q := authURL.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", provider.ClientID)
+	q.Set("redirect_uri", s.redirectURL)
+	q.Set("scope", strings.Join(provider.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", st.Nonce)
+	q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	q.Set("code_challenge_method", "S256")
+	authURL.RawQuery = q.Encode()
+	return authURL.String(), state, nil
+}
+
+// Callback completes a sign-on: it redeems the authorization code,
+// verifies the ID token, provisions or updates the user and issues a
+// session.
+func (s *SSOService) Callback(ctx context.Context, state, code string) (*SSOLogin, error) {
+	var st ssoState
+	if err := s.sessions.open("sso-state", state, &st); err != nil {
+		return nil, fmt.Errorf("%w: invalid state", domain.ErrSSOFailed)
+	}
+	if !s.now().Before(st.ExpiresAt) {
+		return nil, fmt.Errorf("%w: sign-on took too long, please retry", domain.ErrSSOFailed)
+	}
+	provider, ok := s.providers[st.OrgID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownOrganization, st.OrgID)
+	}
+	if code == "" {
+		return nil, fmt.Errorf("%w: missing authorization code", domain.ErrSSOFailed)
+	}
+
+	meta, err := s.discover(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+	rawIDToken, err := s.exchange(ctx, provider, meta, code, s.pkceVerifier(st.Nonce))
+	if err != nil {
+		return nil, err
+	}
+	claims, err := s.verifyIDToken(ctx, provider, meta, rawIDToken, st.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.provision(ctx, provider, claims)
+	if err != nil {
+		return nil, err
+	}
+	token, session, err := s.sessions.Issue(Session{
+		UserID: user.ID,
+		OrgID:  user.OrgID,
+		Role:   user.Role,
+		Plan:   provider.Plan,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SSOLogin{User: user, Token: token, Session: session}, nil
+}
+
+func (s *SSOService) pkceVerifier(nonce string) string {
+	return s.sessions.derive("sso-pkce", nonce)
+}
+
+// discover fetches and caches the provider's OpenID configuration.
+func (s *SSOService) discover(ctx context.Context, provider SSOProvider) (*oidcDiscovery, error) {
+	s.mu.Lock()
+	meta, ok := s.discovery[provider.OrgID]
+	s.mu.Unlock()
+	if ok {
+		return meta, nil
+	}
+
+	meta = &oidcDiscovery{}
+	if err := s.getJSON(ctx, provider.Issuer+"/.well-known/openid-configuration", meta); err != nil {
+		return nil, fmt.Errorf("%w: discovery for %s: %v", domain.ErrSSOFailed, provider.OrgID, err)
+	}
+	if strings.TrimRight(meta.Issuer, "/") != provider.Issuer {
+		return nil, fmt.Errorf("%w: %s reports issuer %q", domain.ErrSSOFailed, provider.Issuer, meta.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: incomplete provider metadata for %s", domain.ErrSSOFailed, provider.OrgID)
+	}
+
+	s.mu.Lock()
+	s.discovery[provider.OrgID] = meta
+	s.mu.Unlock()
+	return meta, nil
+}
+
+// exchange redeems an authorization code for an ID token.
+func (s *SSOService) exchange(ctx context.Context, provider SSOProvider, meta *oidcDiscovery, code, verifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {s.redirectURL},
+		"client_id":     {provider.ClientID},
+		"client_secret": {provider.ClientSecret},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: token request: %v", domain.ErrSSOFailed, err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("%w: token response: %v", domain.ErrSSOFailed, err)
+	}
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		return "", fmt.Errorf("%w: token endpoint returned %d %s %s", domain.ErrSSOFailed, resp.StatusCode, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return "", fmt.Errorf("%w: no id_token in token response", domain.ErrSSOFailed)
+	}
+	return body.IDToken, nil
+}
+
+// idTokenClaims are the ID token claims we check or use. Groups are kept
+// raw because the claim name is configurable.
+type idTokenClaims struct {
+	Issuer        string          `json:"iss"`
+	Subject       string          `json:"sub"`
+	Audience      audience        `json:"aud"`
+	ExpiresAt     int64           `json:"exp"`
+	Nonce         string          `json:"nonce"`
+	Email         string          `json:"email"`
+	EmailVerified *bool           `json:"email_verified"`
+	Name          string          `json:"name"`
+	raw           json.RawMessage `json:"-"`
+}
+
+// audience accepts the single-string and array forms of "aud".
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// verifyIDToken checks an RS256 ID token's signature and claims.
+func (s *SSOService) verifyIDToken(ctx context.Context, provider SSOProvider, meta *oidcDiscovery, raw, nonce string) (*idTokenClaims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed id_token", domain.ErrSSOFailed)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed id_token header", domain.ErrSSOFailed)
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("%w: unsupported id_token algorithm %q", domain.ErrSSOFailed, header.Alg)
+	}
+
+	key, err := s.signingKey(ctx, meta.JWKSURI, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed id_token signature", domain.ErrSSOFailed)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("%w: id_token signature does not verify", domain.ErrSSOFailed)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed id_token payload", domain.ErrSSOFailed)
+	}
+	claims := &idTokenClaims{raw: payload}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed id_token claims", domain.ErrSSOFailed)
+	}
+
+	switch {
+	case strings.TrimRight(claims.Issuer, "/") != provider.Issuer:
+		return nil, fmt.Errorf("%w: id_token issuer %q", domain.ErrSSOFailed, claims.Issuer)
+	case !containsString(claims.Audience, provider.ClientID):
+		return nil, fmt.Errorf("%w: id_token not issued for this client", domain.ErrSSOFailed)
+	case s.now().Unix() >= claims.ExpiresAt:
+		return nil, fmt.Errorf("%w: id_token expired", domain.ErrSSOFailed)
+	case claims.Nonce != nonce:
+		return nil, fmt.Errorf("%w: id_token nonce mismatch", domain.ErrSSOFailed)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: id_token has no subject", domain.ErrSSOFailed)
+	}
+	return claims, nil
+}
+
+// groups returns the configured groups claim as a list.
+func (c *idTokenClaims) groups(claim string) []string {
+	var all map[string]json.RawMessage
+	if json.Unmarshal(c.raw, &all) != nil {
+		return nil
+	}
+	value, ok := all[claim]
+	if !ok {
+		return nil
+	}
+	var many []string
+	if json.Unmarshal(value, &many) == nil {
+		return many
+	}
+	var single string
+	if json.Unmarshal(value, &single) == nil && single != "" {
+		return strings.Fields(strings.ReplaceAll(single, ",", " "))
+	}
+	return nil
+}
+
+// signingKey returns the provider key with the given ID, refetching the
+// key set when the ID is unknown (the provider may have rotated keys).
+func (s *SSOService) signingKey(ctx context.Context, jwksURI, kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	set := s.jwks[jwksURI]
+	s.mu.Unlock()
+	if set != nil {
+		if key := set.lookup(kid); key != nil {
+			return key, nil
+		}
+		if s.now().Sub(set.fetchedAt) < jwksRefreshInterval {
+			return nil, fmt.Errorf("%w: unknown signing key %q", domain.ErrSSOFailed, kid)
+		}
+	}
+
+	var doc struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := s.getJSON(ctx, jwksURI, &doc); err != nil {
+		return nil, fmt.Errorf("%w: signing keys: %v", domain.ErrSSOFailed, err)
+	}
+	set = &jwkSet{keys: make(map[string]*rsa.PublicKey), fetchedAt: s.now()}
+	for _, k := range doc.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		set.keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+
+	s.mu.Lock()
+	s.jwks[jwksURI] = set
+	s.mu.Unlock()
+
+	if key := set.lookup(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", domain.ErrSSOFailed, kid)
+}
+
+// lookup finds a key by ID; a token without a key ID matches a set with a
+// single key.
+func (set *jwkSet) lookup(kid string) *rsa.PublicKey {
+	if key, ok := set.keys[kid]; ok {
+		return key
+	}
+	if kid == "" && len(set.keys) == 1 {
+		for _, key := range set.keys {
+			return key
+		}
+	}
+	return nil
+}
+
+// provision creates the user on first sign-on and refreshes their
+// profile and role on later ones.
+func (s *SSOService) provision(ctx context.Context, provider SSOProvider, claims *idTokenClaims) (*domain.User, error) {
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if email == "" {
+		return nil, fmt.Errorf("%w: identity provider returned no email", domain.ErrSSOFailed)
+	}
+	if claims.EmailVerified != nil && !*claims.EmailVerified {
+		return nil, fmt.Errorf("%w: email %s is not verified", domain.ErrSSOFailed, email)
+	}
+	if !emailDomainAllowed(email, provider.AllowedDomains) {
+		return nil, fmt.Errorf("%w: %s is not allowed to sign in to %s", domain.ErrSSOFailed, email, provider.OrgID)
+	}
+
+	name := claims.Name
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+	role := provider.DefaultRole
+	for _, group := range claims.groups(provider.GroupsClaim) {
+		if mapped, ok := provider.RoleMapping[group]; ok {
+			role = domain.HigherRole(role, mapped)
+		}
+	}
+	externalID := provider.Issuer + "|" + claims.Subject
+
+	user, err := s.users.GetUserByExternalID(ctx, externalID)
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		user = &domain.User{
+			ID:          uuid.New().String(),
+			Email:       email,
+			Name:        name,
+			OrgID:       provider.OrgID,
+			Role:        role,
+			ExternalID:  externalID,
+			LastLoginAt: s.now(),
+		}
+		if err := s.users.CreateUser(ctx, user); err != nil {
+			return nil, fmt.Errorf("failed to provision user: %w", err)
+		}
+		log.Printf("SSO: provisioned %s in %s as %s", email, provider.OrgID, role)
+		return user, nil
+	case err != nil:
+		return nil, err
+	}
+
+	updated := *user
+	updated.Email = email
+	updated.Name = name
+	updated.OrgID = provider.OrgID
+	updated.Role = role
+	updated.LastLoginAt = s.now()
+	if err := s.users.UpdateUser(ctx, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	return &updated, nil
+}
+
+func (s *SSOService) getJSON(ctx context.Context, rawURL string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: HTTP %d", rawURL, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out)
+}
+
+func decodeJWTPart(part string, out interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func emailDomainAllowed(email string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	_, domainPart, _ := strings.Cut(email, "@")
+	for _, d := range allowed {
+		d = strings.ToLower(strings.TrimPrefix(d, "@"))
+		if domainPart == d || strings.HasSuffix(domainPart, "."+d) {
+			return true
+		}
+	}
+	return false
+}
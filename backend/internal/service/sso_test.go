@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+// fakeIdP is a minimal OpenID provider that issues an ID token with the
+// configured claims for any code.
+type fakeIdP struct {
+	*httptest.Server
+	key      *rsa.PrivateKey
+	claims   map[string]interface{}
+	verifier string // code_verifier seen at the token endpoint
+	nonce    string // nonce sent on the authorization request
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &fakeIdP{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+			"jwks_uri":               idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "k1", "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		idp.verifier = r.PostForm.Get("code_verifier")
+		json.NewEncoder(w).Encode(map[string]string{"id_token": idp.sign(t)})
+	})
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func (idp *fakeIdP) sign(t *testing.T) string {
+	claims := map[string]interface{}{
+		"iss": idp.URL, "aud": "client-1", "exp": time.Now().Add(time.Hour).Unix(), "nonce": idp.nonce,
+	}
+	for k, v := range idp.claims {
+		claims[k] = v
+	}
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "k1"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, idp.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func newTestSSO(t *testing.T, idp *fakeIdP) *SSOService {
+	sso, err := NewSSOService([]SSOProvider{{
+		OrgID:          "uni",
+		Issuer:         idp.URL,
+		ClientID:       "client-1",
+		RoleMapping:    map[string]string{"staff": domain.RoleAnalyst, "it-admins": domain.RoleAdmin},
+		AllowedDomains: []string{"uni.edu"},
+	}}, NewUserService(memory.NewUserRepository()), NewSessionTokens("secret", time.Hour), "https://api.example.com/api/auth/sso/callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sso
+}
+
+// login runs the browser side of a sign-on.
+func login(t *testing.T, sso *SSOService, idp *fakeIdP) (*SSOLogin, error) {
+	authURL, state, err := sso.LoginURL(context.Background(), "uni")
+	if err != nil {
+		t.Fatalf("LoginURL: %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	q := u.Query()
+	if q.Get("state") != state || q.Get("code_challenge_method") != "S256" {
+		t.Fatalf("authorization URL = %s", authURL)
+	}
+	idp.nonce = q.Get("nonce")
+
+	result, err := sso.Callback(context.Background(), state, "code-1")
+	if err == nil {
+		challenge := sha256.Sum256([]byte(idp.verifier))
+		if base64.RawURLEncoding.EncodeToString(challenge[:]) != q.Get("code_challenge") {
+			t.Error("code_verifier does not match the code_challenge")
+		}
+	}
+	return result, err
+}
+
+func TestSSOLoginProvisionsAndMapsRoles(t *testing.T) {
+	idp := newFakeIdP(t)
+	sso := newTestSSO(t, idp)
+
+	idp.claims = map[string]interface{}{"sub": "u-1", "email": "Ada@uni.edu", "name": "Ada", "groups": []string{"students", "staff"}}
+	first, err := login(t, sso, idp)
+	if err != nil {
+		t.Fatalf("first login: %v", err)
+	}
+	if first.User.Role != domain.RoleAnalyst || first.User.Email != "ada@uni.edu" || first.User.OrgID != "uni" {
+		t.Errorf("provisioned user = %+v", first.User)
+	}
+
+	session, err := sso.sessions.Verify(first.Token)
+	if err != nil || session.UserID != first.User.ID || session.Role != domain.RoleAnalyst {
+		t.Fatalf("session = %+v, %v", session, err)
+	}
+
+	// A group change at the IdP takes effect on the next login, for the
+	// same account.
+	idp.claims["groups"] = []string{"staff", "it-admins"}
+	second, err := login(t, sso, idp)
+	if err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+	if second.User.ID != first.User.ID || second.User.Role != domain.RoleAdmin {
+		t.Errorf("second login user = %+v, want same ID with admin role", second.User)
+	}
+}
+
+func TestSSOLoginRejects(t *testing.T) {
+	idp := newFakeIdP(t)
+	sso := newTestSSO(t, idp)
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+	}{
+		{"other email domain", map[string]interface{}{"sub": "u-2", "email": "eve@evil.com"}},
+		{"wrong audience", map[string]interface{}{"sub": "u-3", "email": "a@uni.edu", "aud": "someone-else"}},
+		{"expired", map[string]interface{}{"sub": "u-4", "email": "a@uni.edu", "exp": time.Now().Add(-time.Minute).Unix()}},
+		{"unverified email", map[string]interface{}{"sub": "u-5", "email": "a@uni.edu", "email_verified": false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp.claims = tt.claims
+			if _, err := login(t, sso, idp); !errors.Is(err, domain.ErrSSOFailed) {
+				t.Errorf("err = %v, want ErrSSOFailed", err)
+			}
+		})
+	}
+
+	if _, err := sso.Callback(context.Background(), "forged.state", "code"); !errors.Is(err, domain.ErrSSOFailed) {
+		t.Errorf("forged state err = %v, want ErrSSOFailed", err)
+	}
+	if _, _, err := sso.LoginURL(context.Background(), "nowhere"); !errors.Is(err, domain.ErrUnknownOrganization) {
+		t.Errorf("unknown org err = %v, want ErrUnknownOrganization", err)
+	}
+}
+
+func TestNewSSOServiceValidates(t *testing.T) {
+	bad := []SSOProvider{
+		{Issuer: "https://idp", ClientID: "c"},
+		{OrgID: "x", Protocol: "saml", Issuer: "https://idp", ClientID: "c"},
+		{OrgID: "x", Issuer: "https://idp", ClientID: "c", RoleMapping: map[string]string{"g": "superuser"}},
+	}
+	for _, p := range bad {
+		if _, err := NewSSOService([]SSOProvider{p}, nil, nil, ""); err == nil {
+			t.Errorf("NewSSOService(%+v) succeeded, want error", p)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// WithStaleAfter enables stale-while-revalidate: requests with AllowStale
+// that hit a stored URL verdict scored more than age ago get it at once,
+// marked stale, while it is re-scored in the background. Zero disables it.
+func (s *NewsService) WithStaleAfter(age time.Duration) *NewsService {
+	s.staleAfter = age
+	return s
+}
+
+// reuse returns a stored prediction like reusedPrediction, marking it
+// stale and starting its refresh when the request allows that.
+func (s *NewsService) reuse(req *domain.AnalysisRequest, stored *domain.Prediction, summary, evidence bool) *domain.Prediction {
+	reused := reusedPrediction(stored, summary, evidence)
+	if s.isStale(req, stored) {
+		reused.Stale = true
+		s.refreshInBackground(stored.ID)
+	}
+	return reused
+}
+
+// isStale reports whether a stored verdict is old enough to refresh for
+// the request. Verdicts of a pinned model or a partner's own model are not
+// refreshed, since the refresh uses the default model, and neither are
+// verdicts the model did not reach.
+func (s *NewsService) isStale(req *domain.AnalysisRequest, stored *domain.Prediction) bool {
+	if s.staleAfter <= 0 || !req.AllowStale || req.Model != "" || stored.ModelRoute != "" {
+		return false
+	}
+	if stored.RequestType != "url" || stored.Archived || (stored.Method != "" && stored.Method != domain.MethodModel) {
+		return false
+	}
+	return time.Since(stored.ScoredAt()) >= s.staleAfter
+}
+
+// refreshInBackground re-scores a stored prediction unless a refresh of it
+// is already running. The refresh is charged to nobody's quota.
+func (s *NewsService) refreshInBackground(id string) {
+	if _, running := s.refreshing.LoadOrStore(id, struct{}{}); running {
+		return
+	}
+	go func() {
+		defer s.refreshing.Delete(id)
+		ctx, cancel := context.WithTimeout(context.Background(), backgroundAnalysisTimeout)
+		defer cancel()
+		if err := s.refresh(ctx, id); err != nil {
+			log.Printf("Warning: refreshing stale verdict %s failed: %v", id, err)
+		}
+	}()
+}
+
+// errFallbackAnswered keeps a stored verdict when only the fallback model
+// could re-score it.
+var errFallbackAnswered = errors.New("only the fallback model answered")
+
+// refresh re-scrapes and re-scores a stored URL prediction the way a new
+// analysis would, then replaces its verdict in place. A failed scrape
+// keeps the stored verdict, so the next stale hit retries.
+func (s *NewsService) refresh(ctx context.Context, id string) error {
+	stored, err := s.repository.GetPredictionByID(id)
+	if err != nil {
+		return err
+	}
+	scrapeResult, err := s.scraper.ScrapeArticle(ctx, stored.OriginalContent)
+	if err != nil {
+		return err
+	}
+	rescored, err := s.predictText(ctx, scrapeResult.Text, stored.TruncationStrategy, nil)
+	if err != nil {
+		return err
+	}
+	if rescored.FallbackModel != "" {
+		return errFallbackAnswered
+	}
+	provenance := provenanceOf(rescored)
+	provenance.ScraperVersion = ScraperVersion
+	provenance.Extractor = scrapeResult.Extractor
+	provenance.Cleaning = append(append([]string(nil), scraperCleaning...), provenance.Cleaning...)
+	s.fusion.Apply(ctx, &SignalInput{
+		Prediction:  rescored,
+		Text:        scrapeResult.Text,
+		Source:      scrapeResult.Source,
+		PublishedAt: scrapeResult.PublishedAt,
+	})
+
+	// Re-read, so a review or pin made during the refresh is kept
+	latest, err := s.repository.GetPredictionByID(id)
+	if err != nil {
+		return err
+	}
+	updated := *latest
+	updated.Result = rescored.Result
+	updated.Confidence = rescored.Confidence
+	updated.FakeProbability = rescored.FakeProbability
+	updated.RealProbability = rescored.RealProbability
+	updated.ModelVersion = rescored.ModelVersion
+	updated.ModelRoute = rescored.ModelRoute
+	updated.FallbackModel = ""
+	updated.Method = domain.MethodModel
+	updated.Uncertainty = rescored.Uncertainty
+	updated.Signals = rescored.Signals
+	updated.Provenance = rescored.Provenance
+	updated.TruncationStrategy = rescored.TruncationStrategy
+	updated.InputChars = rescored.InputChars
+	updated.ChunkCount = rescored.ChunkCount
+	updated.TranslatedFrom = rescored.TranslatedFrom
+	updated.ConfidencePenalty = rescored.ConfidencePenalty
+	updated.MLRequestID = rescored.MLRequestID
+	now := time.Now()
+	updated.RefreshedAt = &now
+	if err := s.repository.UpdatePrediction(&updated); err != nil {
+		return fmt.Errorf("failed to save refreshed verdict: %w", err)
+	}
+	s.knownFake.Observe(&updated)
+	return nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestAnalyzeStaleVerdictIsRefreshedInBackground(t *testing.T) {
+	ml := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(MLPredictionResponse{Result: domain.LabelFake, Confidence: 0.9, FakeProbability: 0.9, RealProbability: 0.1, ModelVersion: "v2"})
+	}))
+	defer ml.Close()
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><head><title>Harbor reopens</title></head><body><article>
+<p>The city harbor reopened on Monday after three months of repairs to the breakwater, officials said.</p>
+<p>Fishing crews returned to the docks at dawn, and the first ferry left on schedule at nine.</p>
+</article></body></html>`))
+	}))
+	defer site.Close()
+	scraper := NewScraperService()
+	scraper.httpClient = &http.Client{Transport: rewriteTransport{target: site.URL}}
+
+	const article = "http://harbor.example/2024/harbor-reopens"
+	repo := memory.NewPredictionRepository()
+	if err := repo.CreatePrediction(&domain.Prediction{
+		ID: "stored", RequestType: "url", OriginalContent: article, CanonicalURL: NormalizeURL(article),
+		Result: domain.LabelReal, Confidence: 0.7, ModelVersion: "v1", Method: domain.MethodModel,
+		CreatedAt: time.Now().Add(-30 * 24 * time.Hour),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewNewsService(NewMLClient(ml.URL), scraper, repo).WithStaleAfter(24 * time.Hour)
+	ctx := context.Background()
+
+	// Without allow_stale the old verdict is returned as before
+	p, err := svc.AnalyzeNews(ctx, &domain.AnalysisRequest{Type: "url", Content: article})
+	if err != nil || p.ID != "stored" || p.Stale {
+		t.Fatalf("plain request = %+v, %v; want the stored verdict, not stale", p, err)
+	}
+	if _, running := svc.refreshing.Load("stored"); running {
+		t.Fatal("refresh started for a request that did not allow stale verdicts")
+	}
+
+	p, err = svc.AnalyzeNews(ctx, &domain.AnalysisRequest{Type: "url", Content: article, AllowStale: true})
+	if err != nil || p.ID != "stored" || !p.Stale || p.Result != domain.LabelReal {
+		t.Fatalf("stale request = %+v, %v; want the stored REAL verdict marked stale", p, err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, running := svc.refreshing.Load("stored"); !running {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("background refresh did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	stored, _ := repo.GetPredictionByID("stored")
+	if stored.Result != domain.LabelFake || stored.ModelVersion != "v2" || stored.RefreshedAt == nil || stored.Stale {
+		t.Fatalf("refreshed prediction = %+v", stored)
+	}
+
+	// The refreshed verdict is fresh again
+	p, err = svc.AnalyzeNews(ctx, &domain.AnalysisRequest{Type: "url", Content: article, AllowStale: true})
+	if err != nil || p.Stale || p.Result != domain.LabelFake {
+		t.Errorf("after refresh = %+v, %v; want the fresh FAKE verdict", p, err)
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	svc := NewNewsService(nil, nil, memory.NewPredictionRepository()).WithStaleAfter(24 * time.Hour)
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	tests := []struct {
+		name   string
+		req    domain.AnalysisRequest
+		stored domain.Prediction
+		want   bool
+	}{
+		{"old model verdict", domain.AnalysisRequest{AllowStale: true}, domain.Prediction{RequestType: "url", Method: domain.MethodModel, CreatedAt: old}, true},
+		{"fresh verdict", domain.AnalysisRequest{AllowStale: true}, domain.Prediction{RequestType: "url", Method: domain.MethodModel, CreatedAt: time.Now()}, false},
+		{"stale not allowed", domain.AnalysisRequest{}, domain.Prediction{RequestType: "url", Method: domain.MethodModel, CreatedAt: old}, false},
+		{"pinned model", domain.AnalysisRequest{AllowStale: true, Model: "small-v1"}, domain.Prediction{RequestType: "url", Method: domain.MethodModel, CreatedAt: old}, false},
+		{"partner model verdict", domain.AnalysisRequest{AllowStale: true}, domain.Prediction{RequestType: "url", Method: domain.MethodModel, ModelRoute: "acme", CreatedAt: old}, false},
+		{"not scored by the model", domain.AnalysisRequest{AllowStale: true}, domain.Prediction{RequestType: "url", Method: domain.MethodTrustedSource, CreatedAt: old}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := svc.isStale(&tt.req, &tt.stored); got != tt.want {
+				t.Errorf("isStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
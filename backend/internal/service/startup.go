@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Startup defaults
+const (
+	DefaultStartupDeadline = 60 * time.Second
+	DefaultStartupBackoff  = 500 * time.Millisecond
+	maxStartupBackoff      = 10 * time.Second
+	startupAttemptTimeout  = 5 * time.Second
+)
+
+// Startup states. A dependency that misses the boot deadline leaves the
+// process degraded until it comes up.
+const (
+	StartupStarting = "starting"
+	StartupReady    = "ready"
+	StartupDegraded = "degraded"
+)
+
+// Dependency is something the process needs at runtime, such as the ML
+// service. Check must honour ctx.
+type Dependency struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// DependencyStatus is one dependency's boot progress.
+type DependencyStatus struct {
+	Name     string     `json:"name"`
+	Status   string     `json:"status"` // ok or down
+	Attempts int        `json:"attempts"`
+	Detail   string     `json:"detail,omitempty"` // last error while down
+	ReadyAt  *time.Time `json:"ready_at,omitempty"`
+}
+
+// StartupReport describes the boot sequence.
+type StartupReport struct {
+	Status       string             `json:"status"`
+	StartedAt    time.Time          `json:"started_at"`
+	Deadline     time.Time          `json:"deadline"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// StartupOrchestrator waits for dependencies at boot instead of failing on
+// the first refused connection, which is common when containers start in
+// any order. Each dependency is retried with exponential backoff until it
+// answers; past the boot deadline the process carries on degraded and the
+// retries continue in the background.
+type StartupOrchestrator struct {
+	deps     []Dependency
+	deadline time.Duration
+	backoff  time.Duration
+
+	mu        sync.Mutex
+	startedAt time.Time
+	statuses  []DependencyStatus
+	pending   int
+	ready     chan struct{}
+}
+
+// NewStartupOrchestrator creates an orchestrator for deps.
+func NewStartupOrchestrator(deps ...Dependency) *StartupOrchestrator {
+	return &StartupOrchestrator{
+		deps:     deps,
+		deadline: DefaultStartupDeadline,
+		backoff:  DefaultStartupBackoff,
+		ready:    make(chan struct{}),
+	}
+}
+
+// WithDeadline sets how long boot waits for dependencies.
+func (o *StartupOrchestrator) WithDeadline(deadline time.Duration) *StartupOrchestrator {
+	if deadline > 0 {
+		o.deadline = deadline
+	}
+	return o
+}
+
+// WithBackoff sets the first retry delay, which doubles up to 10s.
+func (o *StartupOrchestrator) WithBackoff(backoff time.Duration) *StartupOrchestrator {
+	if backoff > 0 {
+		o.backoff = backoff
+	}
+	return o
+}
+
+// Start begins checking every dependency concurrently and returns at once.
+// Checks stop when ctx is cancelled.
+func (o *StartupOrchestrator) Start(ctx context.Context) {
+	o.mu.Lock()
+	o.startedAt = time.Now()
+	o.pending = len(o.deps)
+	o.statuses = make([]DependencyStatus, len(o.deps))
+	for i, dep := range o.deps {
+		o.statuses[i] = DependencyStatus{Name: dep.Name, Status: HealthDown}
+	}
+	if o.pending == 0 {
+		close(o.ready)
+	}
+	o.mu.Unlock()
+
+	for i, dep := range o.deps {
+		go o.await(ctx, i, dep)
+	}
+}
+
+// Wait blocks until every dependency is up or the boot deadline passes,
+// and returns the report at that point. Call it after Start.
+func (o *StartupOrchestrator) Wait(ctx context.Context) StartupReport {
+	o.mu.Lock()
+	remaining := o.deadline - time.Since(o.startedAt)
+	o.mu.Unlock()
+
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case <-o.ready:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	report := o.Report()
+	if report.Status == StartupDegraded {
+		for _, d := range report.Dependencies {
+			if d.Status != HealthOK {
+				log.Printf("Warning: starting degraded: %s is unavailable after %d attempts: %s", d.Name, d.Attempts, d.Detail)
+			}
+		}
+	}
+	return report
+}
+
+// Report returns the current boot state.
+func (o *StartupOrchestrator) Report() StartupReport {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	report := StartupReport{
+		Status:       StartupReady,
+		StartedAt:    o.startedAt,
+		Deadline:     o.startedAt.Add(o.deadline),
+		Dependencies: append([]DependencyStatus(nil), o.statuses...),
+	}
+	if o.pending > 0 {
+		report.Status = StartupStarting
+		if time.Now().After(report.Deadline) {
+			report.Status = StartupDegraded
+		}
+	}
+	return report
+}
+
+// Degraded reports whether a dependency missed the boot deadline and is
+// still down.
+func (o *StartupOrchestrator) Degraded() bool {
+	return o.Report().Status == StartupDegraded
+}
+
+// await retries one dependency until it answers or ctx is cancelled.
+func (o *StartupOrchestrator) await(ctx context.Context, i int, dep Dependency) {
+	backoff := o.backoff
+	for {
+		attemptCtx, cancel := context.WithTimeout(ctx, startupAttemptTimeout)
+		err := dep.Check(attemptCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		o.mu.Lock()
+		status := &o.statuses[i]
+		status.Attempts++
+		if err == nil {
+			now := time.Now()
+			status.Status, status.Detail, status.ReadyAt = HealthOK, "", &now
+			o.pending--
+			if o.pending == 0 {
+				close(o.ready)
+			}
+			late, attempts := now.Sub(o.startedAt) > o.deadline, status.Attempts
+			o.mu.Unlock()
+			if late {
+				log.Printf("Dependency %s recovered after %d attempts", dep.Name, attempts)
+			}
+			return
+		}
+		status.Detail = err.Error()
+		o.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxStartupBackoff)
+	}
+}
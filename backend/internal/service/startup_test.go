@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyDependency fails until it has been checked `failures` times, or
+// until up is set when failures is negative.
+func flakyDependency(name string, failures int32, up *atomic.Bool) Dependency {
+	var calls atomic.Int32
+	return Dependency{Name: name, Check: func(ctx context.Context) error {
+		n := calls.Add(1)
+		if (failures >= 0 && n > failures) || (up != nil && up.Load()) {
+			return nil
+		}
+		return errors.New("connection refused")
+	}}
+}
+
+func TestStartupOrchestratorRetriesUntilReady(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	o := NewStartupOrchestrator(flakyDependency("ml", 2, nil), flakyDependency("api", 0, nil)).
+		WithBackoff(time.Millisecond).WithDeadline(5 * time.Second)
+	o.Start(ctx)
+	report := o.Wait(ctx)
+
+	if report.Status != StartupReady || o.Degraded() {
+		t.Fatalf("Wait() status = %s, want ready", report.Status)
+	}
+	if d := report.Dependencies[0]; d.Name != "ml" || d.Status != HealthOK || d.Attempts != 3 || d.ReadyAt == nil {
+		t.Errorf("ml = %+v, want ok after 3 attempts", d)
+	}
+	if d := report.Dependencies[1]; d.Attempts != 1 {
+		t.Errorf("api attempts = %d, want 1", d.Attempts)
+	}
+}
+
+func TestStartupOrchestratorDegradesAfterDeadline(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var up atomic.Bool
+	o := NewStartupOrchestrator(flakyDependency("ml", -1, &up)).
+		WithBackoff(time.Millisecond).WithDeadline(30 * time.Millisecond)
+	o.Start(ctx)
+	if got := o.Report().Status; got != StartupStarting {
+		t.Errorf("status before the deadline = %s, want starting", got)
+	}
+
+	start := time.Now()
+	report := o.Wait(ctx)
+	if report.Status != StartupDegraded || !o.Degraded() {
+		t.Fatalf("Wait() status = %s, want degraded", report.Status)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Wait() took %s, want the 30ms deadline", elapsed)
+	}
+	if d := report.Dependencies[0]; d.Status != HealthDown || d.Detail != "connection refused" || d.Attempts == 0 {
+		t.Errorf("ml = %+v, want down with the last error", d)
+	}
+
+	// Checks continue in the background and leave degraded mode.
+	up.Store(true)
+	deadline := time.Now().Add(2 * time.Second)
+	for o.Degraded() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := o.Report().Status; got != StartupReady {
+		t.Errorf("status after recovery = %s, want ready", got)
+	}
+}
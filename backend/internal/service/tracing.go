@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"net/http"
+)
+
+// Tracing headers propagated to downstream services.
+const (
+	HeaderRequestID   = "X-Request-ID"
+	HeaderTraceParent = "traceparent" // W3C Trace Context
+)
+
+// Trace identifies the incoming request that triggered outbound calls.
+type Trace struct {
+	RequestID   string
+	TraceParent string
+}
+
+type traceKey struct{}
+
+// ContextWithTrace attaches the incoming request's tracing identifiers to
+// ctx so ML and scraper calls can forward them.
+func ContextWithTrace(ctx context.Context, t Trace) context.Context {
+	return context.WithValue(ctx, traceKey{}, t)
+}
+
+// TraceFromContext returns the tracing identifiers carried by ctx, if any.
+func TraceFromContext(ctx context.Context) (Trace, bool) {
+	t, ok := ctx.Value(traceKey{}).(Trace)
+	return t, ok
+}
+
+// setTraceHeaders copies the context's tracing identifiers onto an
+// outbound request.
+func setTraceHeaders(req *http.Request) {
+	t, ok := TraceFromContext(req.Context())
+	if !ok {
+		return
+	}
+	if t.RequestID != "" {
+		req.Header.Set(HeaderRequestID, t.RequestID)
+	}
+	if t.TraceParent != "" {
+		req.Header.Set(HeaderTraceParent, t.TraceParent)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// DefaultTranslationPenalty scales how far a translated article's
+// confidence may sit from 0.5: translation loses nuance the model relies on.
+const DefaultTranslationPenalty = 0.85
+
+// Translator translates text between ISO 639-1 languages.
+type Translator interface {
+	Translate(ctx context.Context, text, source, target string) (string, error)
+}
+
+// HTTPTranslator calls a LibreTranslate-compatible /translate endpoint.
+type HTTPTranslator struct {
+	endpoint   string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewHTTPTranslator creates a translator for endpoint. A non-empty apiKey
+// is sent in the request body as api_key.
+func NewHTTPTranslator(endpoint, apiKey string) *HTTPTranslator {
+	return &HTTPTranslator{
+		endpoint:   endpoint,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// WithOutboundAudit records translation calls in audit.
+func (t *HTTPTranslator) WithOutboundAudit(audit *OutboundAudit) *HTTPTranslator {
+	audit.Client(t.httpClient, PurposeTranslation)
+	return t
+}
+
+func (t *HTTPTranslator) Translate(ctx context.Context, text, source, target string) (string, error) {
+	body, err := json.Marshal(map[string]string{
+		"q":       text,
+		"source":  source,
+		"target":  target,
+		"format":  "text",
+		"api_key": t.apiKey,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("translation service returned HTTP %d", resp.StatusCode)
+	}
+
+	var out struct {
+		TranslatedText string `json:"translatedText"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(&out); err != nil {
+		return "", fmt.Errorf("invalid translation response: %w", err)
+	}
+	if strings.TrimSpace(out.TranslatedText) == "" {
+		return "", fmt.Errorf("translation service returned no text")
+	}
+	return out.TranslatedText, nil
+}
+
+// TranslationBridge translates articles in languages no configured model
+// supports into one that is, before prediction.
+type TranslationBridge struct {
+	translator Translator
+	supported  []string
+	penalty    float64
+}
+
+// NewTranslationBridge creates a bridge for models that support the given
+// languages; translations target the first of them.
+func NewTranslationBridge(translator Translator, supported []string) *TranslationBridge {
+	langs := make([]string, 0, len(supported))
+	for _, lang := range supported {
+		if lang = strings.ToLower(strings.TrimSpace(lang)); lang != "" {
+			langs = append(langs, lang)
+		}
+	}
+	if len(langs) == 0 {
+		langs = []string{"en"}
+	}
+	return &TranslationBridge{translator: translator, supported: langs, penalty: DefaultTranslationPenalty}
+}
+
+// WithPenalty sets the confidence penalty factor (0-1] for translated
+// articles.
+func (b *TranslationBridge) WithPenalty(penalty float64) *TranslationBridge {
+	if penalty > 0 && penalty <= 1 {
+		b.penalty = penalty
+	}
+	return b
+}
+
+type languageKey struct{}
+
+// ContextWithLanguage tells the translation bridge the language of text
+// analyzed with the returned context, instead of detecting it.
+func ContextWithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// LanguageFromContext returns the caller's language hint, if any.
+func LanguageFromContext(ctx context.Context) string {
+	lang, _ := ctx.Value(languageKey{}).(string)
+	return lang
+}
+
+// Prepare returns the text to score. When text is in a detectable language
+// no model supports it is translated, and the source language is returned.
+// A language hint in ctx replaces detection.
+func (b *TranslationBridge) Prepare(ctx context.Context, text string) (string, string, error) {
+	lang := LanguageFromContext(ctx)
+	if lang == "" {
+		lang = DetectLanguage(text)
+	}
+	if lang == "" || containsString(b.supported, lang) {
+		return text, "", nil
+	}
+	translated, err := b.translator.Translate(ctx, text, lang, b.supported[0])
+	if err != nil {
+		return text, "", fmt.Errorf("translating from %s: %w", lang, err)
+	}
+	return translated, lang, nil
+}
+
+// applyTranslationPenalty pulls a translated prediction's confidence toward
+// 0.5 by its penalty factor.
+func applyTranslationPenalty(p *domain.Prediction) {
+	if p.TranslatedFrom == "" || p.ConfidencePenalty <= 0 {
+		return
+	}
+	p.Confidence = 0.5 + (p.Confidence-0.5)*p.ConfidencePenalty
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "The minister said that the budget was approved for the year.", "en"},
+		{"spanish", "El gobierno anunció que los precios de la energía subirán en el próximo año.", "es"},
+		{"german", "Die Regierung hat gesagt, dass der Haushalt nicht mit den Zielen übereinstimmt.", "de"},
+		{"hindi", "सरकार ने नए बजट की घोषणा की", "hi"},
+		{"russian", "Правительство объявило новый бюджет", "ru"},
+		{"japanese", "政府は新しい予算を発表しました", "ja"},
+		{"chinese", "政府宣布了新的预算", "zh"},
+		{"too little to tell", "Breaking", ""},
+		{"no letters", "12345 !!!", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLanguage(tt.text); got != tt.want {
+				t.Errorf("DetectLanguage(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeTranslator struct {
+	calls int
+	err   error
+}
+
+func (f *fakeTranslator) Translate(ctx context.Context, text, source, target string) (string, error) {
+	f.calls++
+	if f.err != nil {
+		return "", f.err
+	}
+	return "[" + source + "->" + target + "] the translated story", nil
+}
+
+func TestTranslationBridgePrepare(t *testing.T) {
+	hindi := "सरकार ने नए बजट की घोषणा की"
+	english := "The minister said that the budget was approved for the year."
+	tests := []struct {
+		name      string
+		supported []string
+		err       error
+		text      string
+		wantText  string
+		wantFrom  string
+		wantErr   bool
+	}{
+		{"supported language untouched", []string{"en"}, nil, english, english, "", false},
+		{"unsupported language translated", []string{"en", "hi "}, nil, "Правительство объявило новый бюджет", "[ru->en] the translated story", "ru", false},
+		{"second supported language untouched", []string{"en", "HI"}, nil, hindi, hindi, "", false},
+		{"undetected language untouched", nil, nil, "Breaking", "Breaking", "", false},
+		{"failure keeps original", nil, errors.New("down"), hindi, hindi, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge := NewTranslationBridge(&fakeTranslator{err: tt.err}, tt.supported)
+			text, from, err := bridge.Prepare(context.Background(), tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Prepare() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if text != tt.wantText || from != tt.wantFrom {
+				t.Errorf("Prepare() = %q, %q; want %q, %q", text, from, tt.wantText, tt.wantFrom)
+			}
+		})
+	}
+}
+
+func TestAnalyzeTranslatedText(t *testing.T) {
+	var scored string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req MLPredictionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		scored = req.Text
+		json.NewEncoder(w).Encode(MLPredictionResponse{Result: "FAKE", Confidence: 0.9, FakeProbability: 0.9, RealProbability: 0.1})
+	}))
+	defer srv.Close()
+
+	translator := &fakeTranslator{}
+	svc := NewNewsService(NewMLClient(srv.URL), nil, memory.NewPredictionRepository()).
+		WithTranslation(NewTranslationBridge(translator, []string{"en"}).WithPenalty(0.5))
+
+	p, err := svc.AnalyzeNews(context.Background(), &domain.AnalysisRequest{Type: "text", Content: "सरकार ने नए बजट की घोषणा की"})
+	if err != nil {
+		t.Fatalf("AnalyzeNews: %v", err)
+	}
+	if !strings.HasPrefix(scored, "[hi->en]") {
+		t.Errorf("model scored %q, want the translation", scored)
+	}
+	if p.TranslatedFrom != "hi" || p.ConfidencePenalty != 0.5 {
+		t.Errorf("translated_from = %q, confidence_penalty = %v", p.TranslatedFrom, p.ConfidencePenalty)
+	}
+	if p.Result != domain.LabelFake || math.Abs(p.Confidence-0.7) > 1e-9 {
+		t.Errorf("verdict = %s at %v, want FAKE at 0.7", p.Result, p.Confidence)
+	}
+	if p.OriginalContent != "सरकार ने नए बजट की घोषणा की" {
+		t.Errorf("OriginalContent = %q, want the untranslated text", p.OriginalContent)
+	}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// DefaultMaxInputChars roughly matches the 512-token window of the
+// transformer models served by the ML service.
+const DefaultMaxInputChars = 4000
+
+// Truncator prepares long article text for the ML service.
+type Truncator struct {
+	defaultStrategy string
+	maxChars        int
+}
+
+// NewTruncator creates a new truncator. Unknown strategies fall back to
+// sending the text as-is.
+func NewTruncator(defaultStrategy string, maxChars int) *Truncator {
+	if !domain.IsValidTruncationStrategy(defaultStrategy) {
+		defaultStrategy = domain.TruncationNone
+	}
+	if maxChars <= 0 {
+		maxChars = DefaultMaxInputChars
+	}
+	return &Truncator{
+		defaultStrategy: defaultStrategy,
+		maxChars:        maxChars,
+	}
+}
+
+// Resolve returns the strategy to apply, preferring the per-request override.
+func (t *Truncator) Resolve(override string) string {
+	if override != "" {
+		return override
+	}
+	return t.defaultStrategy
+}
+
+// Apply splits text into the pieces that should be sent to the ML service.
+// Every strategy except chunk returns a single piece.
+func (t *Truncator) Apply(text, strategy string) []string {
+	if utf8.RuneCountInString(text) <= t.maxChars {
+		return []string{text}
+	}
+
+	switch strategy {
+	case domain.TruncationHead:
+		return []string{truncateHead(text, t.maxChars)}
+	case domain.TruncationHeadTail:
+		return []string{truncateHeadTail(text, t.maxChars)}
+	case domain.TruncationLead:
+		return []string{truncateLead(text, t.maxChars)}
+	case domain.TruncationChunk:
+		return chunkText(text, t.maxChars)
+	default:
+		return []string{text}
+	}
+}
+
+// truncateHead keeps the first maxChars characters, cut at a word boundary.
+func truncateHead(text string, maxChars int) string {
+	runes := []rune(text)
+	if len(runes) <= maxChars {
+		return text
+	}
+	cut := string(runes[:maxChars])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut)
+}
+
+// truncateTail keeps the last maxChars characters, cut at a word boundary.
+func truncateTail(text string, maxChars int) string {
+	runes := []rune(text)
+	if len(runes) <= maxChars {
+		return text
+	}
+	cut := string(runes[len(runes)-maxChars:])
+	if i := strings.IndexByte(cut, ' '); i >= 0 {
+		cut = cut[i+1:]
+	}
+	return strings.TrimSpace(cut)
+}
+
+// truncateHeadTail keeps two thirds of the budget from the start and the
+// rest from the end, where articles tend to put conclusions and sourcing.
+func truncateHeadTail(text string, maxChars int) string {
+	headChars := maxChars * 2 / 3
+	tailChars := maxChars - headChars - 1
+	return truncateHead(text, headChars) + " " + truncateTail(text, tailChars)
+}
+
+// truncateLead keeps the lead (first sentences, up to half the budget) and
+// fills the remainder with sentences sampled evenly from the rest of the
+// article, preserving their order.
+func truncateLead(text string, maxChars int) string {
+	sentences := splitSentences(text)
+	if len(sentences) == 0 {
+		return truncateHead(text, maxChars)
+	}
+
+	var b strings.Builder
+	i := 0
+	for ; i < len(sentences); i++ {
+		if b.Len()+len(sentences[i])+1 > maxChars/2 && b.Len() > 0 {
+			break
+		}
+		appendSentence(&b, sentences[i])
+	}
+
+	rest := sentences[i:]
+	if len(rest) == 0 {
+		return truncateHead(b.String(), maxChars)
+	}
+
+	// Estimate how many of the remaining sentences fit and sample with an
+	// even stride across the rest of the article.
+	remaining := maxChars - b.Len()
+	avg := 1
+	for _, s := range rest {
+		avg += len(s) + 1
+	}
+	avg /= len(rest)
+	fit := remaining / avg
+	if fit < 1 {
+		fit = 1
+	}
+	stride := float64(len(rest)) / float64(fit)
+	if stride < 1 {
+		stride = 1
+	}
+	for f := 0.0; int(f) < len(rest); f += stride {
+		s := rest[int(f)]
+		if b.Len()+len(s)+1 > maxChars {
+			break
+		}
+		appendSentence(&b, s)
+	}
+	return truncateHead(b.String(), maxChars)
+}
+
+// chunkText splits text into consecutive pieces of at most maxChars,
+// cut at word boundaries.
+func chunkText(text string, maxChars int) []string {
+	var chunks []string
+	rest := strings.TrimSpace(text)
+	for utf8.RuneCountInString(rest) > maxChars {
+		chunk := truncateHead(rest, maxChars)
+		if chunk == "" {
+			chunk = string([]rune(rest)[:maxChars])
+		}
+		chunks = append(chunks, chunk)
+		rest = strings.TrimSpace(rest[len(chunk):])
+	}
+	if rest != "" {
+		chunks = append(chunks, rest)
+	}
+	return chunks
+}
+
+// splitSentences performs a simple punctuation-based sentence split. The
+// Devanagari danda (।) and double danda (॥) end a sentence wherever they
+// appear, since unlike '.' they have no other use.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	cut := func(end int) {
+		if s := strings.TrimSpace(text[start:end]); s != "" {
+			sentences = append(sentences, s)
+		}
+		start = end
+	}
+	for i, r := range text {
+		switch r {
+		case '.', '!', '?':
+			if i+1 == len(text) || text[i+1] == ' ' {
+				cut(i + 1)
+			}
+		case '।', '॥':
+			cut(i + utf8.RuneLen(r))
+		}
+	}
+	if s := strings.TrimSpace(text[start:]); s != "" {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
+
+func appendSentence(b *strings.Builder, s string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(s)
+}
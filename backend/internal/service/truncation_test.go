@@ -0,0 +1,76 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+func TestTruncator_Apply(t *testing.T) {
+	text := strings.Repeat("This is a sentence about the news. ", 200)
+	truncator := NewTruncator(domain.TruncationNone, 500)
+
+	tests := []struct {
+		name       string
+		strategy   string
+		wantPieces int
+	}{
+		{name: "none", strategy: domain.TruncationNone, wantPieces: 1},
+		{name: "head", strategy: domain.TruncationHead, wantPieces: 1},
+		{name: "head_tail", strategy: domain.TruncationHeadTail, wantPieces: 1},
+		{name: "lead", strategy: domain.TruncationLead, wantPieces: 1},
+		{name: "chunk", strategy: domain.TruncationChunk, wantPieces: 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pieces := truncator.Apply(text, tt.strategy)
+			if len(pieces) != tt.wantPieces {
+				t.Fatalf("Apply() got %d pieces, want %d", len(pieces), tt.wantPieces)
+			}
+			if tt.strategy == domain.TruncationNone {
+				return
+			}
+			for _, p := range pieces {
+				if n := utf8.RuneCountInString(p); n > 500 {
+					t.Errorf("Apply() piece has %d chars, want <= 500", n)
+				}
+			}
+		})
+	}
+}
+
+func TestTruncator_ShortTextUnchanged(t *testing.T) {
+	truncator := NewTruncator(domain.TruncationHead, 500)
+	pieces := truncator.Apply("short text", truncator.Resolve(""))
+	if len(pieces) != 1 || pieces[0] != "short text" {
+		t.Errorf("Apply() = %v, want unchanged text", pieces)
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"english", "Markets fell. Did they recover? Not yet!", []string{"Markets fell.", "Did they recover?", "Not yet!"}},
+		{"decimal point", "Growth was 3.5 percent. Analysts agreed.", []string{"Growth was 3.5 percent.", "Analysts agreed."}},
+		{"hindi", "सरकार ने नए बजट की घोषणा की। विपक्ष ने इसका विरोध किया। बहस जारी है",
+			[]string{"सरकार ने नए बजट की घोषणा की।", "विपक्ष ने इसका विरोध किया।", "बहस जारी है"}},
+		{"danda without a space", "पहला वाक्य।दूसरा वाक्य।", []string{"पहला वाक्य।", "दूसरा वाक्य।"}},
+		{"double danda", "श्लोक समाप्त॥ अगला भाग।", []string{"श्लोक समाप्त॥", "अगला भाग।"}},
+		{"mixed scripts", "The minister spoke. उन्होंने कहा कि काम जारी है। Details follow.",
+			[]string{"The minister spoke.", "उन्होंने कहा कि काम जारी है।", "Details follow."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitSentences(tt.text)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("splitSentences() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
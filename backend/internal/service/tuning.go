@@ -0,0 +1,162 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// TuningService changes verdict weights, the FAKE threshold and source
+// category scores at runtime. Every change becomes a new version recording
+// who made it and when; the history is saved to a file and the latest
+// version is applied again on restart.
+type TuningService struct {
+	fusion     *VerdictFusion
+	categories *CategoryScores
+	path       string
+
+	mu      sync.Mutex
+	history []domain.Tuning // oldest first; version 0 is the startup configuration
+}
+
+// NewTuningService captures the current configuration as version 0 and
+// loads saved versions from path, applying the latest. An empty path
+// keeps the history in memory.
+func NewTuningService(fusion *VerdictFusion, categories *CategoryScores, path string) (*TuningService, error) {
+	s := &TuningService{fusion: fusion, categories: categories, path: path}
+	initial := domain.Tuning{
+		Weights:        fusion.Weights(),
+		FakeThreshold:  fusion.Threshold(),
+		CategoryScores: categories.All(),
+		UpdatedBy:      "startup",
+		UpdatedAt:      time.Now().UTC(),
+	}
+	s.history = []domain.Tuning{initial}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tuning state: %w", err)
+	}
+	var saved []domain.Tuning
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse tuning state %s: %w", path, err)
+	}
+	if len(saved) > 1 {
+		if err := s.apply(saved[len(saved)-1]); err != nil {
+			return nil, fmt.Errorf("saved tuning version %d: %w", saved[len(saved)-1].Version, err)
+		}
+		// The saved startup configuration is replaced by this process's own.
+		s.history = append([]domain.Tuning{initial}, saved[1:]...)
+	}
+	return s, nil
+}
+
+// Current returns the tuning in effect.
+func (s *TuningService) Current() domain.Tuning {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.history[len(s.history)-1]
+}
+
+// History returns every version, newest first.
+func (s *TuningService) History() []domain.Tuning {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	history := make([]domain.Tuning, len(s.history))
+	for i, t := range s.history {
+		history[len(s.history)-1-i] = t
+	}
+	return history
+}
+
+// Update applies a new version on behalf of actor. Fields left empty keep
+// their current values; weights and category scores are replaced as a
+// whole when given.
+func (s *TuningService) Update(change domain.Tuning, actor string) (domain.Tuning, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.history[len(s.history)-1]
+	next := domain.Tuning{
+		Version:        current.Version + 1,
+		Weights:        current.Weights,
+		FakeThreshold:  current.FakeThreshold,
+		CategoryScores: current.CategoryScores,
+		Note:           change.Note,
+		UpdatedBy:      actor,
+		UpdatedAt:      time.Now().UTC(),
+	}
+	if change.Weights != nil {
+		next.Weights = change.Weights
+	}
+	if change.FakeThreshold != 0 {
+		next.FakeThreshold = change.FakeThreshold
+	}
+	if change.CategoryScores != nil {
+		next.CategoryScores = change.CategoryScores
+	}
+	if err := next.Validate(); err != nil {
+		return domain.Tuning{}, err
+	}
+
+	if err := s.apply(next); err != nil {
+		return domain.Tuning{}, err
+	}
+	if err := s.save(append(s.history, next)); err != nil {
+		s.apply(current)
+		return domain.Tuning{}, err
+	}
+	s.history = append(s.history, next)
+	return next, nil
+}
+
+func (s *TuningService) apply(t domain.Tuning) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	if err := s.fusion.Retune(t.Weights, t.FakeThreshold); err != nil {
+		return err
+	}
+	s.categories.Set(t.CategoryScores)
+	return nil
+}
+
+// save writes the history through a temporary file so a crash never
+// leaves a truncated file behind.
+func (s *TuningService) save(history []domain.Tuning) error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".tuning-*")
+	if err != nil {
+		return fmt.Errorf("failed to save tuning state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save tuning state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save tuning state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save tuning state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+func TestTuningServiceRetunesAndPersists(t *testing.T) {
+	registry := NewSourceRegistry([]domain.Source{{Domain: "tabloid.example", Category: "tabloid"}})
+	newFusion := func(categories *CategoryScores) *VerdictFusion {
+		return NewVerdictFusion().WithCatalog(SourceReputationSignal{Registry: registry, Scores: categories})
+	}
+	verdict := func(fusion *VerdictFusion, fake float64) *domain.Prediction {
+		in := SignalInput{Prediction: &domain.Prediction{FakeProbability: fake}, Source: "tabloid.example"}
+		fusion.Apply(context.Background(), &in)
+		return in.Prediction
+	}
+
+	path := filepath.Join(t.TempDir(), "tuning.json")
+	categories := NewCategoryScores()
+	fusion := newFusion(categories)
+	tuning, err := NewTuningService(fusion, categories, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tuning.Current(); got.Version != 0 || got.FakeThreshold != domain.DefaultFakeThreshold ||
+		got.Weights[domain.SignalML] != 1 || got.Weights[domain.SignalSourceReputation] != 0 {
+		t.Errorf("startup tuning = %+v", got)
+	}
+	if p := verdict(fusion, 0.55); p.Result != domain.LabelFake {
+		t.Fatalf("default threshold gave %s", p.Result)
+	}
+
+	for _, bad := range []domain.Tuning{
+		{FakeThreshold: 1.5},
+		{Weights: map[string]float64{"astrology": 1}},
+		{Weights: map[string]float64{domain.SignalML: 0}},
+		{CategoryScores: map[string]float64{"tabloid": 2}},
+	} {
+		if _, err := tuning.Update(bad, "ana"); !errors.Is(err, domain.ErrInvalidTuning) {
+			t.Errorf("Update(%+v) error = %v, want ErrInvalidTuning", bad, err)
+		}
+	}
+
+	updated, err := tuning.Update(domain.Tuning{FakeThreshold: 0.6, Note: "pilot week 1"}, "ana")
+	if err != nil {
+		t.Fatalf("Update(threshold) error = %v", err)
+	}
+	if updated.Version != 1 || updated.UpdatedBy != "ana" || updated.Weights[domain.SignalML] != 1 {
+		t.Errorf("version 1 = %+v, want the threshold change by ana keeping the weights", updated)
+	}
+	if p := verdict(fusion, 0.55); p.Result != domain.LabelReal {
+		t.Errorf("threshold 0.6 gave %s for 0.55", p.Result)
+	}
+
+	if _, err := tuning.Update(domain.Tuning{
+		Weights:        map[string]float64{domain.SignalML: 1, domain.SignalSourceReputation: 1},
+		CategoryScores: map[string]float64{"Tabloid": 0.9},
+	}, "ben"); err != nil {
+		t.Fatalf("Update(weights) error = %v", err)
+	}
+	if p := verdict(fusion, 0.1); len(p.Signals) != 2 || p.Signals[1].FakeScore != 0.9 {
+		t.Errorf("signals = %+v, want the retuned tabloid score", p.Signals)
+	}
+	if history := tuning.History(); len(history) != 3 || history[0].Version != 2 || history[2].Version != 0 {
+		t.Errorf("History() = %+v, want versions 2, 1, 0", history)
+	}
+
+	// A restart applies the latest saved version over the startup weights.
+	categories = NewCategoryScores()
+	fusion = newFusion(categories)
+	reloaded, err := NewTuningService(fusion, categories, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Current(); got.Version != 2 || got.UpdatedBy != "ben" || got.FakeThreshold != 0.6 {
+		t.Errorf("reloaded tuning = %+v, want version 2", got)
+	}
+	if p := verdict(fusion, 0.1); len(p.Signals) != 2 || p.Signals[1].FakeScore != 0.9 {
+		t.Errorf("reloaded signals = %+v", p.Signals)
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+)
+
+// trackingParams lists query parameters that never change article content.
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true,
+	"mc_cid": true, "mc_eid": true, "igshid": true, "yclid": true,
+	"_ga": true, "ref_src": true, "ref_url": true, "cmpid": true,
+	"ocid": true, "smid": true, "share": true,
+}
+
+// NormalizeURL returns a canonical form of an article URL so that the same
+// article shared through different links deduplicates to one entry: the
+// scheme and host are lower-cased, default ports, fragments and tracking
+// parameters (utm_*, fbclid, ...) are dropped and the remaining query is
+// sorted. Unparseable input is returned unchanged.
+func NormalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if port != "" {
+		host = host + ":" + port
+	}
+	u.Host = host
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.User = nil
+
+	query := u.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || trackingParams[lower] {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode() // Encode sorts by key
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
@@ -0,0 +1,35 @@
+package service
+
+import "testing"
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "strips tracking params and fragment",
+			in:   "https://News.Example.com/story?id=7&utm_source=twitter&fbclid=abc#comments",
+			want: "https://news.example.com/story?id=7",
+		},
+		{
+			name: "drops default port and sorts query",
+			in:   "http://example.com:80/a?b=2&a=1",
+			want: "http://example.com/a?a=1&b=2",
+		},
+		{
+			name: "adds root path",
+			in:   "https://example.com",
+			want: "https://example.com/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeURL(tt.in); got != tt.want {
+				t.Errorf("NormalizeURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Usage tracking resolution and retention
+const (
+	usageBucketSize   = 5 * time.Minute
+	usageRetention    = 30 * 24 * time.Hour
+	usageRecentLimits = 20 // rate-limit hits kept per caller
+)
+
+// UsageWindows are the windows usage can be summarized over.
+var UsageWindows = map[string]time.Duration{
+	"1h":  time.Hour,
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// DefaultUsageWindow is summarized when the caller picks none.
+const DefaultUsageWindow = "24h"
+
+// RateLimitHit is one request a caller had rejected with 429.
+type RateLimitHit struct {
+	At     time.Time `json:"at"`
+	Method string    `json:"method"`
+	Path   string    `json:"path"`
+}
+
+// UsageSummary is one caller's API usage over a window.
+type UsageSummary struct {
+	Window        string         `json:"window"`
+	Since         time.Time      `json:"since"`
+	Requests      int            `json:"requests"`
+	ClientErrors  int            `json:"client_errors"` // 4xx, including rate limiting
+	ServerErrors  int            `json:"server_errors"` // 5xx
+	RateLimited   int            `json:"rate_limited"`  // 429
+	ErrorRate     float64        `json:"error_rate"`
+	AvgLatencyMS  float64        `json:"avg_latency_ms"`
+	RateLimitHits []RateLimitHit `json:"rate_limit_hits"` // most recent first, within the window
+}
+
+type usageBucket struct {
+	requests     int
+	clientErrors int
+	serverErrors int
+	rateLimited  int
+	latency      time.Duration
+}
+
+type callerUsage struct {
+	buckets map[int64]*usageBucket // keyed by bucket start (unix seconds)
+	limits  []RateLimitHit         // oldest first
+}
+
+// UsageTracker records per-caller request counts, latencies and errors
+// in five-minute buckets kept for 30 days.
+type UsageTracker struct {
+	mu      sync.Mutex
+	callers map[string]*callerUsage
+	now     func() time.Time
+}
+
+// NewUsageTracker creates an empty usage tracker.
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{callers: make(map[string]*callerUsage), now: time.Now}
+}
+
+// Record counts one finished request by caller.
+func (t *UsageTracker) Record(caller, method, path string, status int, latency time.Duration) {
+	if t == nil || caller == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	c, ok := t.callers[caller]
+	if !ok {
+		c = &callerUsage{buckets: make(map[int64]*usageBucket)}
+		t.callers[caller] = c
+	}
+	key := now.Truncate(usageBucketSize).Unix()
+	b, ok := c.buckets[key]
+	if !ok {
+		// A new bucket at most every five minutes: prune the old ones.
+		cutoff := now.Add(-usageRetention).Unix()
+		for k := range c.buckets {
+			if k < cutoff {
+				delete(c.buckets, k)
+			}
+		}
+		b = &usageBucket{}
+		c.buckets[key] = b
+	}
+
+	b.requests++
+	b.latency += latency
+	switch {
+	case status >= 500:
+		b.serverErrors++
+	case status >= 400:
+		b.clientErrors++
+	}
+	if status == 429 {
+		b.rateLimited++
+		c.limits = append(c.limits, RateLimitHit{At: now, Method: method, Path: path})
+		if len(c.limits) > usageRecentLimits {
+			c.limits = c.limits[len(c.limits)-usageRecentLimits:]
+		}
+	}
+}
+
+// Summary returns caller's usage over one of UsageWindows.
+func (t *UsageTracker) Summary(caller, window string) (*UsageSummary, error) {
+	span, ok := UsageWindows[window]
+	if !ok {
+		return nil, fmt.Errorf("unknown usage window %q", window)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	since := t.now().Add(-span)
+	summary := &UsageSummary{Window: window, Since: since, RateLimitHits: []RateLimitHit{}}
+	c, ok := t.callers[caller]
+	if !ok {
+		return summary, nil
+	}
+
+	// Buckets overlapping the window count in full.
+	first := since.Truncate(usageBucketSize).Unix()
+	var latency time.Duration
+	for start, b := range c.buckets {
+		if start < first {
+			continue
+		}
+		summary.Requests += b.requests
+		summary.ClientErrors += b.clientErrors
+		summary.ServerErrors += b.serverErrors
+		summary.RateLimited += b.rateLimited
+		latency += b.latency
+	}
+	if summary.Requests > 0 {
+		summary.ErrorRate = float64(summary.ClientErrors+summary.ServerErrors) / float64(summary.Requests)
+		summary.AvgLatencyMS = float64(latency) / float64(time.Millisecond) / float64(summary.Requests)
+	}
+	for _, hit := range c.limits {
+		if !hit.At.Before(since) {
+			summary.RateLimitHits = append(summary.RateLimitHits, hit)
+		}
+	}
+	sort.Slice(summary.RateLimitHits, func(i, j int) bool {
+		return summary.RateLimitHits[i].At.After(summary.RateLimitHits[j].At)
+	})
+	return summary, nil
+}
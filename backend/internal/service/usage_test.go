@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsageTrackerSummary(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewUsageTracker()
+	tracker.now = func() time.Time { return now }
+
+	tracker.Record("signature:partner", "POST", "/api/analyze", 200, 100*time.Millisecond)
+	tracker.Record("signature:partner", "POST", "/api/analyze", 500, 300*time.Millisecond)
+	tracker.Record("signature:partner", "GET", "/api/v1/domains/x.com/summary", 429, 0)
+	tracker.Record("signature:other", "GET", "/api/history", 200, time.Second)
+
+	// Two days later only the longer windows still see the traffic.
+	now = now.Add(48 * time.Hour)
+	tracker.Record("signature:partner", "GET", "/api/history", 404, 200*time.Millisecond)
+
+	tests := []struct {
+		window       string
+		wantRequests int
+		wantLimited  int
+		wantLatency  float64
+	}{
+		{"24h", 1, 0, 200},
+		{"7d", 4, 1, 150},
+	}
+	for _, tt := range tests {
+		t.Run(tt.window, func(t *testing.T) {
+			s, err := tracker.Summary("signature:partner", tt.window)
+			if err != nil {
+				t.Fatalf("Summary() error = %v", err)
+			}
+			if s.Requests != tt.wantRequests || s.RateLimited != tt.wantLimited || s.AvgLatencyMS != tt.wantLatency {
+				t.Errorf("Summary() = %+v", s)
+			}
+			if len(s.RateLimitHits) != tt.wantLimited {
+				t.Errorf("RateLimitHits = %+v, want %d", s.RateLimitHits, tt.wantLimited)
+			}
+		})
+	}
+
+	s, _ := tracker.Summary("signature:partner", "7d")
+	if s.ServerErrors != 1 || s.ClientErrors != 2 || s.ErrorRate != 0.75 {
+		t.Errorf("error counts = %+v", s)
+	}
+	if _, err := tracker.Summary("signature:partner", "90d"); err == nil {
+		t.Error("Summary() accepted an unknown window")
+	}
+}
@@ -47,3 +47,8 @@ func (s *UserService) DeleteUser(ctx context.Context, id string) error {
 func (s *UserService) ListUsers(ctx context.Context, limit, offset int) ([]*domain.User, error) {
 	return s.repo.List(ctx, limit, offset)
 }
+
+// GetUserByExternalID retrieves a user by their identity provider account
+func (s *UserService) GetUserByExternalID(ctx context.Context, externalID string) (*domain.User, error) {
+	return s.repo.GetByExternalID(ctx, externalID)
+}
@@ -0,0 +1,432 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// DefaultVerdictWeights keeps the verdict purely model-driven.
+const DefaultVerdictWeights = "ml=1"
+
+// SignalInput is what signals may look at when scoring an analysis.
+type SignalInput struct {
+	Prediction  *domain.Prediction // ML output and article metadata
+	Text        string             // article text; empty when the ML service scraped
+	Source      string             // article hostname, if known
+	PublishedAt *time.Time         // declared publication time, if known
+}
+
+// Signal scores one aspect of an analysis. Score returns a fake score in
+// [0, 1] and a short reason, or ok=false to abstain.
+type Signal interface {
+	Name() string
+	Score(ctx context.Context, in *SignalInput) (fakeScore float64, detail string, ok bool)
+}
+
+// WeightedSignal is a signal that can override its configured weight for
+// one analysis, e.g. per tenant.
+type WeightedSignal interface {
+	Signal
+	WeightFor(ctx context.Context, in *SignalInput) (weight float64, ok bool)
+}
+
+type weightedSignal struct {
+	signal Signal
+	weight float64
+}
+
+// VerdictFusion combines weighted signals into the final verdict. Signals
+// that abstain are left out and the remaining weights are renormalized.
+// Weights and the FAKE threshold can be retuned while analyses run.
+type VerdictFusion struct {
+	mu        sync.RWMutex
+	signals   []weightedSignal
+	catalog   []Signal // every signal Retune may weight, in registration order
+	threshold float64
+}
+
+// NewVerdictFusion creates a fusion stage with only the ML signal.
+func NewVerdictFusion() *VerdictFusion {
+	return (&VerdictFusion{threshold: domain.DefaultFakeThreshold}).WithSignal(MLSignal{}, 1)
+}
+
+// WithSignal adds or reweights a signal. A weight of 0 removes it.
+func (f *VerdictFusion) WithSignal(signal Signal, weight float64) *VerdictFusion {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.register(signal)
+	for i, ws := range f.signals {
+		if ws.signal.Name() == signal.Name() {
+			f.signals = append(f.signals[:i], f.signals[i+1:]...)
+			break
+		}
+	}
+	if weight > 0 {
+		f.signals = append(f.signals, weightedSignal{signal: signal, weight: weight})
+	}
+	return f
+}
+
+// WithCatalog makes signals available to Retune without weighting them.
+func (f *VerdictFusion) WithCatalog(signals ...Signal) *VerdictFusion {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, signal := range signals {
+		f.register(signal)
+	}
+	return f
+}
+
+// WithThreshold sets the fused fake score at which a verdict becomes FAKE.
+func (f *VerdictFusion) WithThreshold(threshold float64) *VerdictFusion {
+	f.mu.Lock()
+	f.threshold = threshold
+	f.mu.Unlock()
+	return f
+}
+
+func (f *VerdictFusion) register(signal Signal) {
+	for i, known := range f.catalog {
+		if known.Name() == signal.Name() {
+			f.catalog[i] = signal
+			return
+		}
+	}
+	f.catalog = append(f.catalog, signal)
+}
+
+// Weights returns every known signal's weight, 0 for unweighted ones.
+func (f *VerdictFusion) Weights() map[string]float64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	weights := make(map[string]float64, len(f.catalog))
+	for _, signal := range f.catalog {
+		weights[signal.Name()] = 0
+	}
+	for _, ws := range f.signals {
+		weights[ws.signal.Name()] = ws.weight
+	}
+	return weights
+}
+
+// Threshold returns the fused fake score at which a verdict becomes FAKE.
+func (f *VerdictFusion) Threshold() float64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.threshold
+}
+
+// Retune replaces every signal weight and the FAKE threshold at once.
+// Signals missing from weights are disabled.
+func (f *VerdictFusion) Retune(weights map[string]float64, threshold float64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for name := range weights {
+		if !slices.ContainsFunc(f.catalog, func(s Signal) bool { return s.Name() == name }) {
+			return fmt.Errorf("%w: unknown signal %q", domain.ErrInvalidTuning, name)
+		}
+	}
+	var signals []weightedSignal
+	for _, signal := range f.catalog {
+		if weight := weights[signal.Name()]; weight > 0 {
+			signals = append(signals, weightedSignal{signal: signal, weight: weight})
+		}
+	}
+	f.signals = signals
+	f.threshold = threshold
+	return nil
+}
+
+// ParseVerdictWeights parses "name=weight,..." as used by VERDICT_WEIGHTS.
+func ParseVerdictWeights(spec string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid verdict weight %q: want name=weight", part)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid verdict weight %q: want a non-negative number", part)
+		}
+		weights[strings.TrimSpace(name)] = weight
+	}
+	return weights, nil
+}
+
+// Apply scores the analysis with every signal, sets the prediction's
+// Result and Confidence from the fused fake score and records each
+// signal's contribution. Translated articles keep their confidence penalty.
+func (f *VerdictFusion) Apply(ctx context.Context, in *SignalInput) {
+	f.mu.RLock()
+	signals, threshold := f.signals, f.threshold
+	f.mu.RUnlock()
+
+	var total, fused float64
+	contributions := make([]domain.SignalContribution, 0, len(signals))
+	for _, ws := range signals {
+		score, detail, ok := ws.signal.Score(ctx, in)
+		if !ok {
+			continue
+		}
+		score = math.Max(0, math.Min(1, score))
+		weight := ws.weight
+		if wsig, ok := ws.signal.(WeightedSignal); ok {
+			if w, ok := wsig.WeightFor(ctx, in); ok {
+				weight = w
+			}
+		}
+		total += weight
+		fused += weight * score
+		contributions = append(contributions, domain.SignalContribution{
+			Name:      ws.signal.Name(),
+			FakeScore: score,
+			Weight:    weight,
+			Detail:    detail,
+		})
+	}
+	if total == 0 {
+		return
+	}
+
+	fused /= total
+	for i := range contributions {
+		contributions[i].Contribution = contributions[i].Weight * contributions[i].FakeScore / total
+	}
+
+	p := in.Prediction
+	p.Signals = contributions
+	weights := make(map[string]float64, len(signals))
+	for _, ws := range signals {
+		weights[ws.signal.Name()] = ws.weight
+	}
+	provenance := provenanceOf(p)
+	provenance.FusionWeights, provenance.FakeThreshold = weights, threshold
+	if fused >= threshold {
+		p.Result = domain.LabelFake
+		p.Confidence = fused
+	} else {
+		p.Result = domain.LabelReal
+		p.Confidence = 1 - fused
+	}
+	applyTranslationPenalty(p)
+}
+
+// MLSignal is the model's own fake probability.
+type MLSignal struct{}
+
+func (MLSignal) Name() string { return domain.SignalML }
+
+func (MLSignal) Score(ctx context.Context, in *SignalInput) (float64, string, bool) {
+	p := in.Prediction
+	return p.FakeProbability, fmt.Sprintf("model %s", p.ModelVersion), true
+}
+
+// clickbaitPhrases are typical of sensational or fabricated stories.
+var clickbaitPhrases = []string{
+	"you won't believe", "shocking", "doctors hate", "they don't want you to know",
+	"mainstream media won't", "share before", "wake up", "100% proof", "miracle cure",
+}
+
+// minHeuristicChars is the least text the heuristic signal will judge.
+const minHeuristicChars = 200
+
+// HeuristicSignal scores writing style: clickbait phrases, shouting in
+// capitals and exclamation marks.
+type HeuristicSignal struct{}
+
+func (HeuristicSignal) Name() string { return domain.SignalHeuristic }
+
+func (HeuristicSignal) Score(ctx context.Context, in *SignalInput) (float64, string, bool) {
+	if len(in.Text) < minHeuristicChars {
+		return 0, "", false
+	}
+
+	phrases, capsRatio, exclaimRatio := styleMarkers(in.Text)
+
+	// Clean prose scores 0.3; each marker pushes the score up.
+	score := 0.3 + 0.15*math.Min(float64(phrases), 3) + math.Min(capsRatio*2, 0.2) + math.Min(exclaimRatio*0.05, 0.1)
+	return score, fmt.Sprintf("%d clickbait phrases, %.0f%% shouted words, %.1f exclamations per 100 words",
+		phrases, capsRatio*100, exclaimRatio), true
+}
+
+// styleMarkers counts the clickbait phrases in text, and measures the
+// share of shouted (all-capitals) words and exclamation marks per 100
+// words.
+func styleMarkers(text string) (phrases int, capsRatio, exclaimRatio float64) {
+	lower := strings.ToLower(text)
+	for _, phrase := range clickbaitPhrases {
+		if strings.Contains(lower, phrase) {
+			phrases++
+		}
+	}
+
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return phrases, 0, 0
+	}
+	shouted := 0
+	for _, w := range words {
+		if len(w) >= 4 && strings.IndexFunc(w, unicode.IsLower) < 0 && strings.IndexFunc(w, unicode.IsUpper) >= 0 {
+			shouted++
+		}
+	}
+	capsRatio = float64(shouted) / float64(len(words))
+	exclaimRatio = float64(strings.Count(text, "!")) / float64(len(words)) * 100
+	return phrases, capsRatio, exclaimRatio
+}
+
+// defaultCategoryScores maps registry categories onto fake scores.
+var defaultCategoryScores = map[string]float64{
+	"mainstream":    0.2,
+	"wire":          0.15,
+	"public media":  0.2,
+	"state media":   0.6,
+	"tabloid":       0.65,
+	"hyperpartisan": 0.75,
+	"satire":        0.9,
+	"fake":          0.95,
+}
+
+// CategoryScores maps source registry categories onto fake scores for
+// SourceReputationSignal. It can be retuned while analyses run.
+type CategoryScores struct {
+	mu     sync.RWMutex
+	scores map[string]float64
+}
+
+// NewCategoryScores creates the default category scores.
+func NewCategoryScores() *CategoryScores {
+	return &CategoryScores{scores: maps.Clone(defaultCategoryScores)}
+}
+
+// Score returns a category's fake score. A nil CategoryScores uses the
+// defaults.
+func (c *CategoryScores) Score(category string) (float64, bool) {
+	if c == nil {
+		score, ok := defaultCategoryScores[strings.ToLower(category)]
+		return score, ok
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	score, ok := c.scores[strings.ToLower(category)]
+	return score, ok
+}
+
+// All returns a copy of every category's score.
+func (c *CategoryScores) All() map[string]float64 {
+	if c == nil {
+		return maps.Clone(defaultCategoryScores)
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return maps.Clone(c.scores)
+}
+
+// Set replaces the scores. Categories left out abstain.
+func (c *CategoryScores) Set(scores map[string]float64) {
+	next := make(map[string]float64, len(scores))
+	for category, score := range scores {
+		next[strings.ToLower(strings.TrimSpace(category))] = score
+	}
+	c.mu.Lock()
+	c.scores = next
+	c.mu.Unlock()
+}
+
+// SourceReputationSignal scores the publisher by its category in the
+// source registry. Unlisted publishers and unscored categories abstain.
+type SourceReputationSignal struct {
+	Registry *SourceRegistry
+	Scores   *CategoryScores // nil uses the default scores
+}
+
+func (SourceReputationSignal) Name() string { return domain.SignalSourceReputation }
+
+func (s SourceReputationSignal) Score(ctx context.Context, in *SignalInput) (float64, string, bool) {
+	src, ok := s.Registry.Lookup(in.Source)
+	if !ok {
+		return 0, "", false
+	}
+	score, ok := s.Scores.Score(src.Category)
+	if !ok {
+		return 0, "", false
+	}
+	return score, fmt.Sprintf("%s is listed as %s", src.Domain, src.Category), true
+}
+
+// FactCheckMatch is a published fact check of a claim in the article.
+type FactCheckMatch struct {
+	Claim     string
+	Publisher string
+	FakeScore float64 // 0 if the claim was rated true, 1 if false
+}
+
+// FactChecker finds published fact checks for an article's claims.
+type FactChecker interface {
+	Match(ctx context.Context, text string) ([]FactCheckMatch, error)
+}
+
+// FactCheckSignal averages the ratings of matching fact checks. It
+// abstains when nothing matches or the checker fails.
+type FactCheckSignal struct {
+	Checker FactChecker
+}
+
+func (FactCheckSignal) Name() string { return domain.SignalFactCheck }
+
+func (s FactCheckSignal) Score(ctx context.Context, in *SignalInput) (float64, string, bool) {
+	if s.Checker == nil || in.Text == "" {
+		return 0, "", false
+	}
+	matches, err := s.Checker.Match(ctx, in.Text)
+	if err != nil || len(matches) == 0 {
+		return 0, "", false
+	}
+	var sum float64
+	for _, m := range matches {
+		sum += m.FakeScore
+	}
+	return sum / float64(len(matches)), fmt.Sprintf("%d matching fact checks", len(matches)), true
+}
+
+// staleArticleAge is the age at which recirculated news scores highest.
+const staleArticleAge = 2 * 365 * 24 * time.Hour
+
+// RecencySignal treats old stories presented as current news as
+// suspicious. Fresh articles are neutral; articles without a date abstain.
+type RecencySignal struct {
+	Now func() time.Time
+}
+
+func (RecencySignal) Name() string { return domain.SignalRecency }
+
+func (s RecencySignal) Score(ctx context.Context, in *SignalInput) (float64, string, bool) {
+	if in.PublishedAt == nil {
+		return 0, "", false
+	}
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	age := now().Sub(*in.PublishedAt)
+	if age < 0 {
+		age = 0
+	}
+	score := 0.5 + 0.2*math.Min(float64(age)/float64(staleArticleAge), 1)
+	return score, fmt.Sprintf("published %d days ago", int(age.Hours()/24)), true
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+type stubFactChecker []FactCheckMatch
+
+func (s stubFactChecker) Match(ctx context.Context, text string) ([]FactCheckMatch, error) {
+	return s, nil
+}
+
+func TestVerdictFusion(t *testing.T) {
+	registry := NewSourceRegistry([]domain.Source{{Domain: "satire.example", Category: "satire"}})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-3 * 365 * 24 * time.Hour)
+
+	tests := []struct {
+		name        string
+		fusion      *VerdictFusion
+		in          SignalInput
+		wantResult  string
+		wantScore   float64 // fused fake score
+		wantSignals int
+	}{
+		{
+			name:        "ml only keeps the model verdict",
+			fusion:      NewVerdictFusion(),
+			in:          SignalInput{Prediction: &domain.Prediction{FakeProbability: 0.3, RealProbability: 0.7}},
+			wantResult:  domain.LabelReal,
+			wantScore:   0.3,
+			wantSignals: 1,
+		},
+		{
+			name:        "source reputation outweighs a confident model",
+			fusion:      NewVerdictFusion().WithSignal(SourceReputationSignal{Registry: registry}, 3),
+			in:          SignalInput{Prediction: &domain.Prediction{FakeProbability: 0.1}, Source: "www.satire.example"},
+			wantResult:  domain.LabelFake,
+			wantScore:   (0.1 + 3*0.9) / 4,
+			wantSignals: 2,
+		},
+		{
+			name: "abstaining signals are renormalized away",
+			fusion: NewVerdictFusion().
+				WithSignal(SourceReputationSignal{Registry: registry}, 1).
+				WithSignal(HeuristicSignal{}, 1).
+				WithSignal(FactCheckSignal{}, 1),
+			in:          SignalInput{Prediction: &domain.Prediction{FakeProbability: 0.8}, Source: "unknown.example"},
+			wantResult:  domain.LabelFake,
+			wantScore:   0.8,
+			wantSignals: 1,
+		},
+		{
+			name: "fact checks and recency",
+			fusion: NewVerdictFusion().
+				WithSignal(FactCheckSignal{Checker: stubFactChecker{{FakeScore: 1}, {FakeScore: 0.5}}}, 1).
+				WithSignal(RecencySignal{Now: func() time.Time { return now }}, 1),
+			in:          SignalInput{Prediction: &domain.Prediction{FakeProbability: 0.2}, Text: "claim", PublishedAt: &old},
+			wantResult:  domain.LabelFake,
+			wantScore:   (0.2 + 0.75 + 0.7) / 3,
+			wantSignals: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fusion.Apply(context.Background(), &tt.in)
+			p := tt.in.Prediction
+
+			if p.Result != tt.wantResult {
+				t.Errorf("Result = %q, want %q", p.Result, tt.wantResult)
+			}
+			if len(p.Signals) != tt.wantSignals {
+				t.Fatalf("got %d signals, want %d: %+v", len(p.Signals), tt.wantSignals, p.Signals)
+			}
+			var sum float64
+			for _, s := range p.Signals {
+				sum += s.Contribution
+			}
+			if math.Abs(sum-tt.wantScore) > 1e-9 {
+				t.Errorf("fused score = %v, want %v", sum, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestHeuristicSignal(t *testing.T) {
+	calm := strings.Repeat("The council approved the budget after a long debate on Tuesday. ", 5)
+	loud := strings.Repeat("SHOCKING! You won't believe what DOCTORS HATE about this miracle cure! ", 5)
+
+	calmScore, _, ok := HeuristicSignal{}.Score(context.Background(), &SignalInput{Text: calm})
+	if !ok {
+		t.Fatal("expected a score for calm text")
+	}
+	loudScore, _, _ := HeuristicSignal{}.Score(context.Background(), &SignalInput{Text: loud})
+	if loudScore <= calmScore || loudScore <= 0.5 {
+		t.Errorf("loud score %v should exceed calm score %v and 0.5", loudScore, calmScore)
+	}
+	if _, _, ok := (HeuristicSignal{}).Score(context.Background(), &SignalInput{Text: "short"}); ok {
+		t.Error("expected short text to abstain")
+	}
+}
+
+func TestParseVerdictWeights(t *testing.T) {
+	weights, err := ParseVerdictWeights("ml=1, heuristic=0.2,recency=0")
+	if err != nil {
+		t.Fatalf("ParseVerdictWeights() error = %v", err)
+	}
+	if weights["ml"] != 1 || weights["heuristic"] != 0.2 || weights["recency"] != 0 {
+		t.Errorf("unexpected weights %v", weights)
+	}
+	for _, bad := range []string{"ml", "ml=x", "ml=-1"} {
+		if _, err := ParseVerdictWeights(bad); err == nil {
+			t.Errorf("ParseVerdictWeights(%q) expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Watch defaults
+const (
+	DefaultWatchInterval = 6 * time.Hour
+	DefaultMaxWatches    = 50 // per user
+)
+
+// ArticleChecker takes a fresh snapshot of an article.
+type ArticleChecker interface {
+	Snapshot(ctx context.Context, articleURL string) (*domain.ArticleSnapshot, error)
+}
+
+// WatchNotifier delivers change notifications to a user. PushService
+// implements it.
+type WatchNotifier interface {
+	NotifyUser(ctx context.Context, userID string, notification *domain.PushNotification) error
+}
+
+// Snapshot scrapes an article and scores its current text, without
+// storing a prediction. Watches compare snapshots to spot edits and
+// verdict changes.
+func (s *NewsService) Snapshot(ctx context.Context, articleURL string) (*domain.ArticleSnapshot, error) {
+	scrapeResult, err := s.scraper.ScrapeArticle(ctx, articleURL)
+	if err != nil {
+		return nil, err
+	}
+	prediction, err := s.predictText(ctx, scrapeResult.Text, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	s.fusion.Apply(ctx, &SignalInput{
+		Prediction:  prediction,
+		Text:        scrapeResult.Text,
+		Source:      scrapeResult.Source,
+		PublishedAt: scrapeResult.PublishedAt,
+	})
+	return &domain.ArticleSnapshot{
+		CanonicalURL: scrapeResult.Canonical,
+		Title:        scrapeResult.Title,
+		ContentHash:  contentHash(scrapeResult.Text),
+		Result:       prediction.Result,
+		Confidence:   prediction.Confidence,
+	}, nil
+}
+
+// contentHash fingerprints article text, ignoring whitespace-only changes.
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(text), " ")))
+	return hex.EncodeToString(sum[:])
+}
+
+// WatchService lets users watch analyzed articles and tells them when an
+// article is edited or its verdict changes on a periodic recheck.
+type WatchService struct {
+	checker    ArticleChecker
+	repo       repository.WatchRepository
+	notifier   WatchNotifier
+	maxWatches int
+	budget     *CrawlBudget
+	now        func() time.Time
+
+	passMu  sync.Mutex
+	pending map[string]int // domain -> articles the running recheck has yet to fetch
+}
+
+// NewWatchService creates a watch service.
+func NewWatchService(checker ArticleChecker, repo repository.WatchRepository) *WatchService {
+	return &WatchService{
+		checker:    checker,
+		repo:       repo,
+		maxWatches: DefaultMaxWatches,
+		now:        time.Now,
+	}
+}
+
+// WithNotifier sends change notifications through notifier. Without one,
+// changes are only recorded on the watch.
+func (s *WatchService) WithNotifier(notifier WatchNotifier) *WatchService {
+	s.notifier = notifier
+	return s
+}
+
+// WithCrawlBudget shows the crawl budget rechecks wait for on listed
+// watches. It should be the scraper's budget.
+func (s *WatchService) WithCrawlBudget(budget *CrawlBudget) *WatchService {
+	s.budget = budget
+	return s
+}
+
+// WithMaxWatches caps how many articles one user can watch.
+func (s *WatchService) WithMaxWatches(max int) *WatchService {
+	if max > 0 {
+		s.maxWatches = max
+	}
+	return s
+}
+
+// Watch starts watching an article for a user. The article is checked
+// once up front to record the snapshot later checks are compared with;
+// watching an article twice returns the existing watch.
+func (s *WatchService) Watch(ctx context.Context, userID, articleURL string) (*domain.Watch, error) {
+	req := domain.AnalysisRequest{Type: "url", Content: articleURL}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	normalized := NormalizeURL(articleURL)
+	for _, w := range existing {
+		if w.CanonicalURL == normalized || w.URL == articleURL {
+			return w, nil
+		}
+	}
+	if len(existing) >= s.maxWatches {
+		return nil, fmt.Errorf("%w (%d)", domain.ErrWatchLimitReached, s.maxWatches)
+	}
+
+	snapshot, err := s.checker.Snapshot(ctx, articleURL)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range existing {
+		if w.CanonicalURL == snapshot.CanonicalURL {
+			return w, nil
+		}
+	}
+
+	now := s.now()
+	watch := &domain.Watch{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		URL:           articleURL,
+		CanonicalURL:  snapshot.CanonicalURL,
+		Title:         snapshot.Title,
+		ContentHash:   snapshot.ContentHash,
+		Result:        snapshot.Result,
+		Confidence:    snapshot.Confidence,
+		CreatedAt:     now,
+		LastCheckedAt: &now,
+	}
+	if err := s.repo.Save(ctx, watch); err != nil {
+		return nil, err
+	}
+	return watch, nil
+}
+
+// List returns a user's watches, each with its domain's crawl schedule
+// when a crawl budget is set. Queued counts the articles on the domain
+// that the running recheck has not reached yet.
+func (s *WatchService) List(ctx context.Context, userID string) ([]*domain.Watch, error) {
+	watches, err := s.repo.ListByUser(ctx, userID)
+	if err != nil || s.budget == nil {
+		return watches, err
+	}
+	s.passMu.Lock()
+	defer s.passMu.Unlock()
+	listed := make([]*domain.Watch, len(watches))
+	for i, w := range watches {
+		copied := *w
+		host := watchDomain(w.URL)
+		schedule := s.budget.Schedule(host)
+		schedule.Queued += s.pending[host]
+		copied.Schedule = &schedule
+		listed[i] = &copied
+	}
+	return listed, nil
+}
+
+// Unwatch stops a user's watch. Other users' watches are reported as not
+// found.
+func (s *WatchService) Unwatch(ctx context.Context, userID, id string) error {
+	watch, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if watch.UserID != userID {
+		return fmt.Errorf("%w with id: %s", domain.ErrWatchNotFound, id)
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Recheck snapshots every watched article and notifies watchers of
+// changes. It returns how many watches changed. Fetches count against the
+// crawl budget; articles over budget wait for the next pass.
+func (s *WatchService) Recheck(ctx context.Context) (int, error) {
+	watches, err := s.repo.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Articles watched by several users are only fetched once per pass.
+	s.startPass(watches)
+	defer s.startPass(nil)
+	snapshots := make(map[string]*domain.ArticleSnapshot)
+	failures := make(map[string]error)
+	changed := 0
+	for _, watch := range watches {
+		if err := ctx.Err(); err != nil {
+			return changed, err
+		}
+
+		snapshot, seen := snapshots[watch.URL]
+		checkErr := failures[watch.URL]
+		if !seen && checkErr == nil {
+			s.passReached(watch.URL)
+			snapshot, checkErr = s.checker.Snapshot(ContextWithCrawl(ctx), watch.URL)
+			if checkErr != nil {
+				failures[watch.URL] = checkErr
+			} else {
+				snapshots[watch.URL] = snapshot
+			}
+		}
+
+		if errors.Is(checkErr, domain.ErrCrawlBudgetExhausted) {
+			continue
+		}
+		now := s.now()
+		watch.LastCheckedAt = &now
+		if checkErr != nil {
+			watch.LastError = checkErr.Error()
+			s.save(ctx, watch)
+			continue
+		}
+		watch.LastError = ""
+
+		change := watch.Diff(snapshot)
+		if change.Changed() {
+			changed++
+			watch.LastChangedAt = &now
+			s.notify(ctx, watch, snapshot, change)
+		}
+		watch.Title = snapshot.Title
+		watch.ContentHash = snapshot.ContentHash
+		watch.Result = snapshot.Result
+		watch.Confidence = snapshot.Confidence
+		s.save(ctx, watch)
+	}
+	return changed, nil
+}
+
+// Run rechecks every interval until ctx is cancelled.
+func (s *WatchService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.Recheck(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				log.Printf("Warning: watch recheck failed: %v", err)
+			} else if n > 0 {
+				log.Printf("Watch recheck found %d changed articles", n)
+			}
+		}
+	}
+}
+
+// startPass records the articles a recheck will fetch, per domain; nil
+// ends the pass.
+func (s *WatchService) startPass(watches []*domain.Watch) {
+	pending := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, w := range watches {
+		if !seen[w.URL] {
+			seen[w.URL] = true
+			pending[watchDomain(w.URL)]++
+		}
+	}
+	s.passMu.Lock()
+	s.pending = pending
+	s.passMu.Unlock()
+}
+
+// passReached takes an article off the pending count as its fetch starts;
+// from then on the crawl budget counts it while it waits.
+func (s *WatchService) passReached(articleURL string) {
+	s.passMu.Lock()
+	defer s.passMu.Unlock()
+	if host := watchDomain(articleURL); s.pending[host] > 0 {
+		s.pending[host]--
+	}
+}
+
+// watchDomain is the crawl budget domain of an article URL.
+func watchDomain(articleURL string) string {
+	u, err := url.Parse(articleURL)
+	if err != nil {
+		return ""
+	}
+	return normalizeDomain(u.Hostname())
+}
+
+func (s *WatchService) save(ctx context.Context, watch *domain.Watch) {
+	if err := s.repo.Save(ctx, watch); err != nil {
+		log.Printf("Warning: failed to save watch %s: %v", watch.ID, err)
+	}
+}
+
+func (s *WatchService) notify(ctx context.Context, watch *domain.Watch, snapshot *domain.ArticleSnapshot, change domain.WatchChange) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.NotifyUser(ctx, watch.UserID, watchNotification(watch, snapshot, change)); err != nil {
+		log.Printf("Warning: failed to notify watcher %s: %v", watch.UserID, err)
+	}
+}
+
+// watchNotification describes a change to a watched article.
+func watchNotification(watch *domain.Watch, snapshot *domain.ArticleSnapshot, change domain.WatchChange) *domain.PushNotification {
+	title := snapshot.Title
+	if title == "" {
+		title = "A watched article changed"
+	}
+
+	var body string
+	switch {
+	case change.Edited && change.VerdictChanged:
+		body = fmt.Sprintf("The article was edited and its verdict changed from %s to %s (%.0f%% confidence)",
+			change.PreviousResult, snapshot.Result, snapshot.Confidence*100)
+	case change.VerdictChanged:
+		body = fmt.Sprintf("Verdict changed from %s to %s (%.0f%% confidence)",
+			change.PreviousResult, snapshot.Result, snapshot.Confidence*100)
+	default:
+		body = fmt.Sprintf("The article was edited; verdict is still %s (%.0f%% confidence)",
+			snapshot.Result, snapshot.Confidence*100)
+	}
+
+	return &domain.PushNotification{
+		Title: title,
+		Body:  body,
+		URL:   watch.URL,
+		Tag:   "watch-" + watch.ID,
+		Data: map[string]string{
+			"watch_id": watch.ID,
+			"result":   snapshot.Result,
+			"edited":   fmt.Sprint(change.Edited),
+		},
+	}
+}
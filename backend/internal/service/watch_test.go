@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+type fakeChecker struct {
+	snapshots map[string]*domain.ArticleSnapshot
+	calls     int
+}
+
+func (c *fakeChecker) Snapshot(ctx context.Context, articleURL string) (*domain.ArticleSnapshot, error) {
+	c.calls++
+	s, ok := c.snapshots[articleURL]
+	if !ok {
+		return nil, domain.ErrURLScrapingFailed
+	}
+	copied := *s
+	return &copied, nil
+}
+
+type recordingNotifier struct {
+	sent map[string][]*domain.PushNotification
+}
+
+func (n *recordingNotifier) NotifyUser(ctx context.Context, userID string, notification *domain.PushNotification) error {
+	n.sent[userID] = append(n.sent[userID], notification)
+	return nil
+}
+
+func TestWatchRecheck(t *testing.T) {
+	const article = "https://news.example.com/story"
+	checker := &fakeChecker{snapshots: map[string]*domain.ArticleSnapshot{
+		article: {CanonicalURL: article, Title: "Story", ContentHash: "v1", Result: "REAL", Confidence: 0.9},
+	}}
+	notifier := &recordingNotifier{sent: make(map[string][]*domain.PushNotification)}
+	svc := NewWatchService(checker, memory.NewWatchRepository()).WithNotifier(notifier)
+	ctx := context.Background()
+
+	alice, err := svc.Watch(ctx, "alice", article)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	again, err := svc.Watch(ctx, "alice", article)
+	if err != nil || again.ID != alice.ID {
+		t.Fatalf("second Watch = %v, %v; want existing watch", again, err)
+	}
+	if _, err := svc.Watch(ctx, "bob", article); err != nil {
+		t.Fatalf("Watch(bob): %v", err)
+	}
+
+	// Nothing changed: no notifications.
+	checker.calls = 0
+	if n, err := svc.Recheck(ctx); err != nil || n != 0 {
+		t.Fatalf("Recheck unchanged = %d, %v; want 0", n, err)
+	}
+	if checker.calls != 1 {
+		t.Errorf("article fetched %d times in one pass, want 1", checker.calls)
+	}
+
+	// The article is edited and now scores as fake.
+	checker.snapshots[article] = &domain.ArticleSnapshot{CanonicalURL: article, Title: "Story", ContentHash: "v2", Result: "FAKE", Confidence: 0.8}
+	if n, err := svc.Recheck(ctx); err != nil || n != 2 {
+		t.Fatalf("Recheck changed = %d, %v; want 2", n, err)
+	}
+	if len(notifier.sent["alice"]) != 1 || len(notifier.sent["bob"]) != 1 {
+		t.Fatalf("notifications = %v, want one per watcher", notifier.sent)
+	}
+	if got := notifier.sent["alice"][0].Body; got != "The article was edited and its verdict changed from REAL to FAKE (80% confidence)" {
+		t.Errorf("notification body = %q", got)
+	}
+
+	// The new snapshot is the baseline for the next pass.
+	if n, _ := svc.Recheck(ctx); n != 0 {
+		t.Errorf("Recheck after update = %d, want 0", n)
+	}
+
+	if err := svc.Unwatch(ctx, "bob", alice.ID); !errors.Is(err, domain.ErrWatchNotFound) {
+		t.Errorf("Unwatch of another user's watch err = %v, want ErrWatchNotFound", err)
+	}
+	if err := svc.Unwatch(ctx, "alice", alice.ID); err != nil {
+		t.Errorf("Unwatch: %v", err)
+	}
+}
+
+func TestWatchLimit(t *testing.T) {
+	checker := &fakeChecker{snapshots: map[string]*domain.ArticleSnapshot{
+		"https://a.example.com/1": {CanonicalURL: "https://a.example.com/1", ContentHash: "a"},
+		"https://a.example.com/2": {CanonicalURL: "https://a.example.com/2", ContentHash: "b"},
+	}}
+	svc := NewWatchService(checker, memory.NewWatchRepository()).WithMaxWatches(1)
+
+	if _, err := svc.Watch(context.Background(), "alice", "https://a.example.com/1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Watch(context.Background(), "alice", "https://a.example.com/2"); !errors.Is(err, domain.ErrWatchLimitReached) {
+		t.Errorf("err = %v, want ErrWatchLimitReached", err)
+	}
+}
+
+// hookChecker runs during before each snapshot, while a recheck is under way.
+type hookChecker struct {
+	*fakeChecker
+	during func(articleURL string)
+}
+
+func (c *hookChecker) Snapshot(ctx context.Context, articleURL string) (*domain.ArticleSnapshot, error) {
+	if c.during != nil {
+		c.during(articleURL)
+	}
+	return c.fakeChecker.Snapshot(ctx, articleURL)
+}
+
+func TestWatchListShowsCrawlSchedule(t *testing.T) {
+	articles := []string{"https://www.news.example.com/a", "https://news.example.com/b", "https://other.example.org/c"}
+	checker := &hookChecker{fakeChecker: &fakeChecker{snapshots: make(map[string]*domain.ArticleSnapshot)}}
+	for _, article := range articles {
+		checker.snapshots[article] = &domain.ArticleSnapshot{CanonicalURL: article, ContentHash: "v1", Result: "REAL"}
+	}
+	budget := NewCrawlBudget(10)
+	svc := NewWatchService(checker, memory.NewWatchRepository()).WithCrawlBudget(budget)
+	ctx := context.Background()
+	for _, article := range articles {
+		if _, err := svc.Watch(ctx, "alice", article); err != nil {
+			t.Fatalf("Watch(%s): %v", article, err)
+		}
+	}
+
+	queued := func() map[string]int {
+		t.Helper()
+		watches, err := svc.List(ctx, "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts := make(map[string]int)
+		for _, w := range watches {
+			if w.Schedule == nil {
+				t.Fatalf("watch %s has no schedule", w.URL)
+			}
+			counts[w.Schedule.Domain] = w.Schedule.Queued
+		}
+		return counts
+	}
+
+	if got := queued(); got["news.example.com"] != 0 || got["other.example.org"] != 0 {
+		t.Errorf("queued before a recheck = %v", got)
+	}
+	var during []map[string]int
+	checker.during = func(string) { during = append(during, queued()) }
+	if _, err := svc.Recheck(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Each fetch sees the articles the pass has not reached yet.
+	if len(during) != 3 {
+		t.Fatalf("%d fetches, want 3", len(during))
+	}
+	for i, counts := range during {
+		if total := counts["news.example.com"] + counts["other.example.org"]; total != 2-i || counts["news.example.com"] > 2 || counts["other.example.org"] > 1 {
+			t.Errorf("queued at fetch %d = %v, want %d in total", i+1, counts, 2-i)
+		}
+	}
+	if got := queued(); got["news.example.com"] != 0 {
+		t.Errorf("queued after the recheck = %v", got)
+	}
+
+	// Listing does not store the schedule.
+	stored, _ := svc.repo.List(ctx)
+	for _, w := range stored {
+		if w.Schedule != nil {
+			t.Errorf("stored watch %s has a schedule", w.URL)
+		}
+	}
+}
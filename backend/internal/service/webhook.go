@@ -0,0 +1,409 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Webhook defaults
+const (
+	DefaultMaxWebhooks = 10 // per owner
+	webhookTimeout     = 10 * time.Second
+)
+
+// Webhook delivery headers. The signature is "t=<unix seconds>,v1=<hex
+// HMAC-SHA256 of "<t>.<body>" keyed with the subscription secret>".
+const (
+	HeaderWebhookEvent     = "X-Webhook-Event"
+	HeaderWebhookID        = "X-Webhook-ID"
+	HeaderWebhookSignature = "X-Webhook-Signature"
+)
+
+// WebhookTestResult is the outcome of a test delivery.
+type WebhookTestResult struct {
+	Delivered  bool   `json:"delivered"`
+	Status     int    `json:"status,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// WebhookService stores webhook subscriptions and sends them matching
+// events: new predictions, verdicts changed by a re-score, review
+// decisions, finished jobs and quota thresholds. Deliveries are signed
+// and retried through the delivery engine. Destinations must resolve to
+// public addresses.
+type WebhookService struct {
+	repo         repository.WebhookRepository
+	delivery     *DeliveryEngine
+	httpClient   *http.Client
+	allowPrivate bool
+	maxPerOwner  int
+	now          func() time.Time
+}
+
+// NewWebhookService creates a webhook service.
+func NewWebhookService(repo repository.WebhookRepository) *WebhookService {
+	s := &WebhookService{
+		repo:        repo,
+		maxPerOwner: DefaultMaxWebhooks,
+		now:         time.Now,
+	}
+	dialer := &net.Dialer{Timeout: webhookTimeout, KeepAlive: 30 * time.Second}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if s.allowPrivate {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		return dialPublic(ctx, net.DefaultResolver, dialer, network, addr)
+	}
+	s.httpClient = &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		// A redirect could lead to an address the URL check never saw
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	return s
+}
+
+// WithDelivery retries deliveries through engine instead of posting each
+// once.
+func (s *WebhookService) WithDelivery(engine *DeliveryEngine) *WebhookService {
+	s.delivery = engine
+	return s
+}
+
+// WithOutboundAudit records webhook posts in audit.
+func (s *WebhookService) WithOutboundAudit(audit *OutboundAudit) *WebhookService {
+	audit.Client(s.httpClient, PurposeWebhook)
+	return s
+}
+
+// WithMaxSubscriptions caps how many subscriptions one owner can create.
+func (s *WebhookService) WithMaxSubscriptions(max int) *WebhookService {
+	if max > 0 {
+		s.maxPerOwner = max
+	}
+	return s
+}
+
+// WithPrivateNetworks allows destinations on loopback and private
+// networks, for local development.
+func (s *WebhookService) WithPrivateNetworks(allow bool) *WebhookService {
+	s.allowPrivate = allow
+	return s
+}
+
+// Create stores a subscription for owner and returns it with its signing
+// secret, which is not shown again. Callers decide whether owner may set
+// AllOwners.
+func (s *WebhookService) Create(ctx context.Context, ownerID string, sub domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	if err := s.validate(&sub); err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.ListByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= s.maxPerOwner {
+		return nil, fmt.Errorf("%w (%d)", domain.ErrWebhookLimitReached, s.maxPerOwner)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	now := s.now()
+	saved := &domain.WebhookSubscription{
+		ID:        uuid.New().String(),
+		OwnerID:   ownerID,
+		URL:       sub.URL,
+		Events:    sub.Events,
+		Filter:    sub.Filter,
+		AllOwners: sub.AllOwners,
+		Active:    true,
+		Secret:    "whsec_" + hex.EncodeToString(secret),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.Save(ctx, saved); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// List returns an owner's subscriptions without their secrets.
+func (s *WebhookService) List(ctx context.Context, ownerID string) ([]*domain.WebhookSubscription, error) {
+	subs, err := s.repo.ListByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		sub.Secret = ""
+	}
+	return subs, nil
+}
+
+// Get returns one of an owner's subscriptions without its secret. Other
+// owners' subscriptions are reported as not found.
+func (s *WebhookService) Get(ctx context.Context, ownerID, id string) (*domain.WebhookSubscription, error) {
+	sub, err := s.get(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	sub.Secret = ""
+	return sub, nil
+}
+
+// Update replaces a subscription's URL, events, filter, AllOwners and
+// active setting. The secret is kept.
+func (s *WebhookService) Update(ctx context.Context, ownerID, id string, change domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	sub, err := s.get(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(&change); err != nil {
+		return nil, err
+	}
+	sub.URL = change.URL
+	sub.Events = change.Events
+	sub.Filter = change.Filter
+	sub.AllOwners = change.AllOwners
+	sub.Active = change.Active
+	sub.UpdatedAt = s.now()
+	if err := s.repo.Save(ctx, sub); err != nil {
+		return nil, err
+	}
+	sub.Secret = ""
+	return sub, nil
+}
+
+// Delete removes one of an owner's subscriptions.
+func (s *WebhookService) Delete(ctx context.Context, ownerID, id string) error {
+	if _, err := s.get(ctx, ownerID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Test posts a webhook.test event to one of an owner's subscriptions once,
+// whether or not it is active, and reports the outcome.
+func (s *WebhookService) Test(ctx context.Context, ownerID, id string) (*WebhookTestResult, error) {
+	sub, err := s.get(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	event := &domain.WebhookEvent{
+		ID:        uuid.New().String(),
+		Type:      domain.WebhookEventTest,
+		CreatedAt: s.now().UTC(),
+		OwnerID:   ownerID,
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+
+	start := time.Now()
+	status, err := s.post(ctx, sub, event, body)
+	result := &WebhookTestResult{Status: status, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Delivered = true
+	}
+	return result, nil
+}
+
+// Publish sends event to every subscription that wants it. Deliveries run
+// in the background when a delivery engine is set.
+func (s *WebhookService) Publish(ctx context.Context, event *domain.WebhookEvent) {
+	if s == nil {
+		return
+	}
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = s.now().UTC()
+	}
+	subs, err := s.repo.List(ctx)
+	if err != nil {
+		log.Printf("Warning: failed to list webhook subscriptions: %v", err)
+		return
+	}
+	var body []byte
+	for _, sub := range subs {
+		if !sub.Wants(event) {
+			continue
+		}
+		if body == nil {
+			if body, err = json.Marshal(event); err != nil {
+				log.Printf("Warning: failed to marshal %s webhook event: %v", event.Type, err)
+				return
+			}
+		}
+		sub := sub
+		send := func(ctx context.Context) error {
+			_, err := s.post(ctx, sub, event, body)
+			return err
+		}
+		if err := s.delivery.Send(ctx, ChannelWebhook, deliveryDestination(sub.URL), send); err != nil {
+			log.Printf("Warning: webhook %s delivery of %s failed: %v", sub.ID, event.Type, err)
+		}
+	}
+}
+
+// Invalidate turns prediction writes into prediction.created,
+// prediction.verdict_changed and review.decided events. It subscribes to
+// the invalidation bus.
+func (s *WebhookService) Invalidate(change domain.PredictionChange) {
+	if s == nil || change.After == nil {
+		return
+	}
+	ctx := context.Background()
+	after := change.After
+	switch change.Kind {
+	case domain.ChangeCreated:
+		s.Publish(ctx, &domain.WebhookEvent{
+			Type:       domain.WebhookPredictionCreated,
+			Prediction: domain.NewWebhookPrediction(after),
+			OwnerID:    after.OwnerID,
+		})
+	case domain.ChangeUpdated:
+		before := change.Before
+		if before == nil {
+			return
+		}
+		if after.Result != before.Result {
+			s.Publish(ctx, &domain.WebhookEvent{
+				Type:       domain.WebhookVerdictChanged,
+				Prediction: domain.NewWebhookPrediction(after),
+				Previous: &domain.WebhookVerdict{
+					Result:       before.Result,
+					Confidence:   before.Confidence,
+					ModelVersion: before.ModelVersion,
+				},
+				OwnerID: after.OwnerID,
+			})
+		}
+		if after.Review != nil && (before.Review == nil || *before.Review != *after.Review) {
+			review := *after.Review
+			s.Publish(ctx, &domain.WebhookEvent{
+				Type:       domain.WebhookReviewDecided,
+				Prediction: domain.NewWebhookPrediction(after),
+				Review:     &review,
+				OwnerID:    after.OwnerID,
+			})
+		}
+	}
+}
+
+// JobFinished publishes job.completed for a job that completed or failed
+// for good. Jobs are system events, sent only to AllOwners subscriptions.
+func (s *WebhookService) JobFinished(ctx context.Context, job *domain.Job) {
+	if s == nil {
+		return
+	}
+	s.Publish(ctx, &domain.WebhookEvent{
+		Type: domain.WebhookJobCompleted,
+		Job: &domain.WebhookJob{
+			ID:       job.ID,
+			Kind:     job.Kind,
+			Status:   job.Status,
+			Attempts: job.Attempts,
+			Error:    job.Error,
+		},
+	})
+}
+
+// QuotaCrossed publishes quota.threshold for a quota subject. Users and
+// keys own their own quota events; organization and client quotas go to
+// AllOwners subscriptions only.
+func (s *WebhookService) QuotaCrossed(subject string, crossing domain.QuotaCrossing) {
+	if s == nil {
+		return
+	}
+	owner := ""
+	if method, id, ok := strings.Cut(subject, ":"); ok && method != "org" && method != "client" {
+		owner = id
+	}
+	s.Publish(context.Background(), &domain.WebhookEvent{
+		Type:    domain.WebhookQuotaThreshold,
+		Quota:   &crossing,
+		OwnerID: owner,
+	})
+}
+
+// SignWebhook returns the signature header value for a delivery body
+// sent at timestamp. Receivers recompute it to verify a delivery.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	t := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t + "."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *WebhookService) get(ctx context.Context, ownerID, id string) (*domain.WebhookSubscription, error) {
+	sub, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.OwnerID != ownerID {
+		return nil, fmt.Errorf("%w with id: %s", domain.ErrWebhookNotFound, id)
+	}
+	return sub, nil
+}
+
+// validate checks sub and refuses destinations that are plainly internal;
+// hosts resolving to private addresses are refused when dialed.
+func (s *WebhookService) validate(sub *domain.WebhookSubscription) error {
+	if err := sub.Validate(); err != nil {
+		return err
+	}
+	if s.allowPrivate {
+		return nil
+	}
+	u, _ := url.Parse(sub.URL)
+	if err := checkPublicHost(u.Hostname()); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidWebhook, err)
+	}
+	return nil
+}
+
+// post makes one signed delivery attempt and returns the response status.
+func (s *WebhookService) post(ctx context.Context, sub *domain.WebhookSubscription, event *domain.WebhookEvent, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, PermanentDeliveryError(fmt.Errorf("webhook delivery failed: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderWebhookEvent, event.Type)
+	req.Header.Set(HeaderWebhookID, event.ID)
+	req.Header.Set(HeaderWebhookSignature, SignWebhook(sub.Secret, s.now().Unix(), body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook delivery failed: %w", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode, deliveryStatusError("webhook delivery", resp.StatusCode)
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+// webhookReceiver records the deliveries it receives and checks their
+// signatures against the secret set once a subscription exists.
+type webhookReceiver struct {
+	mu     sync.Mutex
+	secret string
+	events []domain.WebhookEvent
+	bad    int
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sig := req.Header.Get(HeaderWebhookSignature)
+	t, _, _ := strings.Cut(strings.TrimPrefix(sig, "t="), ",")
+	ts, _ := strconv.ParseInt(t, 10, 64)
+	var event domain.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil || sig != SignWebhook(r.secret, ts, body) || req.Header.Get(HeaderWebhookEvent) != event.Type {
+		r.bad++
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.events = append(r.events, event)
+}
+
+func (r *webhookReceiver) types() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	types := make([]string, len(r.events))
+	for i, e := range r.events {
+		types[i] = e.Type
+	}
+	r.events = nil
+	return types
+}
+
+func TestWebhookServiceDeliversSubscribedEvents(t *testing.T) {
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	ctx := context.Background()
+	webhooks := NewWebhookService(memory.NewWebhookRepository()).WithPrivateNetworks(true)
+	sub, err := webhooks.Create(ctx, "alice", domain.WebhookSubscription{
+		URL:    server.URL,
+		Events: []string{domain.WebhookPredictionCreated, domain.WebhookVerdictChanged, domain.WebhookReviewDecided, domain.WebhookQuotaThreshold},
+		Filter: domain.WebhookFilter{Domain: "example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sub.Secret, "whsec_") {
+		t.Fatalf("secret = %q, want one shown on creation", sub.Secret)
+	}
+	receiver.secret = sub.Secret
+	if listed, _ := webhooks.List(ctx, "alice"); len(listed) != 1 || listed[0].Secret != "" {
+		t.Fatalf("listed = %+v, want one subscription without its secret", listed)
+	}
+
+	p := &domain.Prediction{ID: "p1", RequestType: "url", OriginalContent: "https://news.example.com/a", ArticleSource: "news.example.com", Result: domain.LabelReal, Confidence: 0.7, OwnerID: "alice"}
+	webhooks.Invalidate(domain.PredictionChange{Kind: domain.ChangeCreated, ID: "p1", After: p})
+
+	// A re-score flipping the verdict, then a review decision
+	rescored := *p
+	rescored.Result = domain.LabelFake
+	webhooks.Invalidate(domain.PredictionChange{Kind: domain.ChangeUpdated, ID: "p1", Before: p, After: &rescored})
+	reviewed := rescored
+	reviewed.Review = &domain.Review{Verdict: domain.LabelFake, Reviewer: "rita"}
+	webhooks.Invalidate(domain.PredictionChange{Kind: domain.ChangeUpdated, ID: "p1", Before: &rescored, After: &reviewed})
+	// An update changing neither sends nothing
+	webhooks.Invalidate(domain.PredictionChange{Kind: domain.ChangeUpdated, ID: "p1", Before: &reviewed, After: &reviewed})
+
+	want := []string{domain.WebhookPredictionCreated, domain.WebhookVerdictChanged, domain.WebhookReviewDecided}
+	if got := receiver.types(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+
+	// Other owners' predictions, filtered-out domains and unselected
+	// event types are not sent
+	other := *p
+	other.OwnerID = "bob"
+	webhooks.Invalidate(domain.PredictionChange{Kind: domain.ChangeCreated, After: &other})
+	elsewhere := *p
+	elsewhere.ArticleSource = "example.org"
+	webhooks.Invalidate(domain.PredictionChange{Kind: domain.ChangeCreated, After: &elsewhere})
+	webhooks.JobFinished(ctx, &domain.Job{ID: "j1", Kind: "evaluation", Status: domain.JobDone})
+	if got := receiver.types(); len(got) != 0 {
+		t.Fatalf("events = %v, want none", got)
+	}
+
+	// Quota thresholds reach the user whose quota it is
+	quotas := NewQuotaTracker().WithLimit(domain.QuotaInferences, domain.PlanFree, 10).OnThreshold(webhooks.QuotaCrossed)
+	quotaCtx := ContextWithQuota(ctx, "session:alice", "")
+	for i := 0; i < 10; i++ {
+		if err := quotas.Consume(quotaCtx, domain.QuotaInferences, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := receiver.types(); len(got) != 2 {
+		t.Fatalf("events = %v, want the 80%% and 100%% thresholds", got)
+	}
+
+	result, err := webhooks.Test(ctx, "alice", sub.ID)
+	if err != nil || !result.Delivered || result.Status != http.StatusOK {
+		t.Fatalf("test = %+v, %v; want delivered", result, err)
+	}
+	if got := receiver.types(); len(got) != 1 || got[0] != domain.WebhookEventTest {
+		t.Fatalf("events = %v, want a test event", got)
+	}
+	if _, err := webhooks.Test(ctx, "bob", sub.ID); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Fatalf("err = %v, want another owner's subscription hidden", err)
+	}
+	if receiver.bad != 0 {
+		t.Fatalf("%d deliveries had bad signatures", receiver.bad)
+	}
+}
+
+func TestWebhookServiceAllOwnersAndValidation(t *testing.T) {
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	ctx := context.Background()
+	webhooks := NewWebhookService(memory.NewWebhookRepository()).WithPrivateNetworks(true)
+	sub, err := webhooks.Create(ctx, "admin", domain.WebhookSubscription{
+		URL:       server.URL,
+		Events:    []string{domain.WebhookJobCompleted, domain.WebhookPredictionCreated},
+		Filter:    domain.WebhookFilter{JobKind: "evaluation", Label: domain.LabelFake},
+		AllOwners: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver.secret = sub.Secret
+
+	webhooks.JobFinished(ctx, &domain.Job{ID: "j1", Kind: "evaluation", Status: domain.JobFailed, Error: "boom"})
+	webhooks.JobFinished(ctx, &domain.Job{ID: "j2", Kind: "import", Status: domain.JobDone})
+	webhooks.Invalidate(domain.PredictionChange{Kind: domain.ChangeCreated, After: &domain.Prediction{ID: "p1", Result: domain.LabelFake, OwnerID: "bob"}})
+	webhooks.Invalidate(domain.PredictionChange{Kind: domain.ChangeCreated, After: &domain.Prediction{ID: "p2", Result: domain.LabelReal, OwnerID: "bob"}})
+	if got := receiver.types(); strings.Join(got, ",") != domain.WebhookJobCompleted+","+domain.WebhookPredictionCreated {
+		t.Fatalf("events = %v, want the evaluation job and the FAKE prediction", got)
+	}
+
+	// Paused subscriptions receive nothing
+	if _, err := webhooks.Update(ctx, "admin", sub.ID, domain.WebhookSubscription{URL: server.URL, Events: sub.Events, AllOwners: true}); err != nil {
+		t.Fatal(err)
+	}
+	webhooks.JobFinished(ctx, &domain.Job{ID: "j3", Kind: "evaluation", Status: domain.JobDone})
+	if got := receiver.types(); len(got) != 0 {
+		t.Fatalf("events = %v, want none while paused", got)
+	}
+
+	strict := NewWebhookService(memory.NewWebhookRepository())
+	for name, sub := range map[string]domain.WebhookSubscription{
+		"no events":     {URL: "https://hooks.example.com/x"},
+		"unknown event": {URL: "https://hooks.example.com/x", Events: []string{"webhook.test"}},
+		"bad scheme":    {URL: "ftp://hooks.example.com/x", Events: []string{domain.WebhookJobCompleted}},
+		"loopback":      {URL: "http://127.0.0.1:9000/x", Events: []string{domain.WebhookJobCompleted}},
+		"internal host": {URL: "https://metadata.internal/x", Events: []string{domain.WebhookJobCompleted}},
+		"bad filter":    {URL: "https://hooks.example.com/x", Events: []string{domain.WebhookJobCompleted}, Filter: domain.WebhookFilter{Dimension: "bytes"}},
+	} {
+		if _, err := strict.Create(ctx, "alice", sub); !errors.Is(err, domain.ErrInvalidWebhook) {
+			t.Errorf("%s: err = %v, want ErrInvalidWebhook", name, err)
+		}
+	}
+}
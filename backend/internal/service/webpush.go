@@ -0,0 +1,256 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// ErrPushSubscriptionGone is returned when the push service reports that a
+// subscription has expired or been revoked (HTTP 404/410).
+var ErrPushSubscriptionGone = errors.New("push subscription is gone")
+
+// pushRecordSize is the aes128gcm record size; payloads fit in one record.
+const pushRecordSize = 4096
+
+// WebPushSender delivers VAPID-signed, RFC 8291 encrypted push messages.
+type WebPushSender struct {
+	subject    string // mailto: or https: contact for the push service
+	publicKey  []byte // uncompressed P-256 point
+	signingKey *ecdsa.PrivateKey
+	httpClient *http.Client
+	ttl        time.Duration
+}
+
+// NewWebPushSender creates a sender from a base64url-encoded raw P-256
+// private key (the format produced by common VAPID key generators).
+func NewWebPushSender(subject, privateKeyB64 string) (*WebPushSender, error) {
+	raw, err := decodeBase64URL(privateKeyB64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	priv, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	pub := priv.PublicKey().Bytes()
+
+	signingKey := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(pub[1:33]),
+			Y:     new(big.Int).SetBytes(pub[33:65]),
+		},
+		D: new(big.Int).SetBytes(raw),
+	}
+
+	// Endpoints come from browsers, so they are dialed only at public
+	// addresses, as webhooks are
+	dialer := &net.Dialer{Timeout: 15 * time.Second, KeepAlive: 30 * time.Second}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dialPublic(ctx, net.DefaultResolver, dialer, network, addr)
+	}
+	return &WebPushSender{
+		subject:    subject,
+		publicKey:  pub,
+		signingKey: signingKey,
+		httpClient: &http.Client{
+			Timeout:   15 * time.Second,
+			Transport: transport,
+			// A redirect could lead to an address the endpoint check never saw
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		ttl: 24 * time.Hour,
+	}, nil
+}
+
+// WithOutboundAudit records deliveries to push services in audit.
+func (s *WebPushSender) WithOutboundAudit(audit *OutboundAudit) *WebPushSender {
+	audit.Client(s.httpClient, PurposePush)
+	return s
+}
+
+// PublicKey returns the VAPID application server key for
+// PushManager.subscribe({applicationServerKey}).
+func (s *WebPushSender) PublicKey() string {
+	return base64.RawURLEncoding.EncodeToString(s.publicKey)
+}
+
+// Send encrypts and delivers a notification to one subscription.
+func (s *WebPushSender) Send(sub *domain.PushSubscription, notification *domain.PushNotification) error {
+	// Subscriptions stored before endpoints were checked may point inside
+	u, err := url.Parse(sub.Endpoint)
+	if err == nil {
+		err = checkPublicHost(u.Hostname())
+	}
+	if err != nil {
+		return PermanentDeliveryError(fmt.Errorf("invalid push endpoint: %v", err))
+	}
+
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	body, err := encryptPushPayload(sub, payload)
+	if err != nil {
+		return err
+	}
+
+	jwt, err := s.vapidJWT(sub.Endpoint)
+	if err != nil {
+		return PermanentDeliveryError(err)
+	}
+
+	req, err := http.NewRequest("POST", sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", fmt.Sprintf("%d", int(s.ttl.Seconds())))
+	req.Header.Set("Urgency", "normal")
+	req.Header.Set("Authorization", fmt.Sprintf("vapid t=%s, k=%s", jwt, s.PublicKey()))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("push delivery failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrPushSubscriptionGone
+	case resp.StatusCode >= 300:
+		return deliveryStatusError("push delivery", resp.StatusCode)
+	}
+	return nil
+}
+
+// vapidJWT builds the ES256 JWT identifying us to the push service (RFC 8292).
+func (s *WebPushSender) vapidJWT(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return "", fmt.Errorf("invalid push endpoint")
+	}
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims, _ := json.Marshal(map[string]interface{}{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": s.subject,
+	})
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	r, sig, err := ecdsa.Sign(rand.Reader, s.signingKey, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign VAPID token: %w", err)
+	}
+	raw := make([]byte, 64)
+	r.FillBytes(raw[:32])
+	sig.FillBytes(raw[32:])
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// encryptPushPayload implements the aes128gcm content encoding for Web Push
+// (RFC 8291 on top of RFC 8188) as a single record.
+func encryptPushPayload(sub *domain.PushSubscription, plaintext []byte) ([]byte, error) {
+	uaPublicRaw, err := decodeBase64URL(sub.Keys.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	authSecret, err := decodeBase64URL(sub.Keys.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth secret: %w", err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicRaw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+	sharedSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	keyInfo := "WebPush: info\x00" + string(uaPublicRaw) + string(asPublic)
+	ikm, err := hkdf.Key(sha256.New, sharedSecret, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// 0x02 marks the last (and only) record.
+	padded := append(append([]byte{}, plaintext...), 0x02)
+	if len(padded)+gcm.Overhead() > pushRecordSize {
+		return nil, fmt.Errorf("push payload too large")
+	}
+	ciphertext := gcm.Seal(nil, nonce, padded, nil)
+
+	// Header: salt(16) | record size(4) | key id length(1) | key id
+	var buf bytes.Buffer
+	buf.Write(salt)
+	binary.Write(&buf, binary.BigEndian, uint32(pushRecordSize))
+	buf.WriteByte(byte(len(asPublic)))
+	buf.Write(asPublic)
+	buf.Write(ciphertext)
+	return buf.Bytes(), nil
+}
+
+func decodeBase64URL(s string) ([]byte, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "=")
+	s = strings.NewReplacer("+", "-", "/", "_").Replace(s)
+	return base64.RawURLEncoding.DecodeString(s)
+}
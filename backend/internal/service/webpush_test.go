@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+	"github.com/Naman30903/Final-Year-Project/internal/repository/memory"
+)
+
+// TestEncryptPushPayload decrypts the payload the way a browser would.
+func TestEncryptPushPayload(t *testing.T) {
+	uaPrivate, _ := ecdh.P256().GenerateKey(rand.Reader)
+	authSecret := make([]byte, 16)
+	rand.Read(authSecret)
+
+	sub := &domain.PushSubscription{
+		Keys: domain.PushSubscriptionKeys{
+			P256dh: base64.RawURLEncoding.EncodeToString(uaPrivate.PublicKey().Bytes()),
+			Auth:   base64.RawURLEncoding.EncodeToString(authSecret),
+		},
+	}
+
+	body, err := encryptPushPayload(sub, []byte(`{"title":"hi"}`))
+	if err != nil {
+		t.Fatalf("encryptPushPayload() error = %v", err)
+	}
+
+	salt := body[:16]
+	idLen := int(body[20])
+	asPublicRaw := body[21 : 21+idLen]
+	ciphertext := body[21+idLen:]
+
+	asPublic, err := ecdh.P256().NewPublicKey(asPublicRaw)
+	if err != nil {
+		t.Fatalf("invalid sender key: %v", err)
+	}
+	shared, _ := uaPrivate.ECDH(asPublic)
+	keyInfo := "WebPush: info\x00" + string(uaPrivate.PublicKey().Bytes()) + string(asPublicRaw)
+	ikm, _ := hkdf.Key(sha256.New, shared, authSecret, keyInfo, 32)
+	cek, _ := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	nonce, _ := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		t.Fatalf("failed to decrypt payload: %v", err)
+	}
+	if got := string(plaintext[:len(plaintext)-1]); got != `{"title":"hi"}` {
+		t.Errorf("decrypted payload = %q", got)
+	}
+	if plaintext[len(plaintext)-1] != 0x02 {
+		t.Errorf("missing last-record delimiter")
+	}
+}
+
+func TestPushEndpointsMustBePublic(t *testing.T) {
+	key, _ := ecdh.P256().GenerateKey(rand.Reader)
+	sender, err := NewWebPushSender("mailto:ops@example.com", base64.RawURLEncoding.EncodeToString(key.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	push := NewPushService(memory.NewPushSubscriptionRepository(), sender)
+	keys := domain.PushSubscriptionKeys{P256dh: "p", Auth: "a"}
+
+	tests := []struct {
+		endpoint string
+		ok       bool
+	}{
+		{"https://fcm.googleapis.com/fcm/send/abc", true},
+		{"http://fcm.googleapis.com/fcm/send/abc", false},
+		{"https://localhost/push", false},
+		{"https://metadata.google.internal/computeMetadata", false},
+		{"https://169.254.169.254/latest", false},
+		{"https://[::1]:8443/push", false},
+	}
+	for _, tt := range tests {
+		err := push.Subscribe(context.Background(), &domain.PushSubscription{UserID: "ada", Endpoint: tt.endpoint, Keys: keys})
+		if (err == nil) != tt.ok {
+			t.Errorf("Subscribe(%s) error = %v, want ok = %t", tt.endpoint, err, tt.ok)
+		}
+	}
+
+	// A subscription stored before endpoints were checked is not posted to
+	stored := &domain.PushSubscription{Endpoint: "https://10.0.0.5/push", Keys: keys}
+	if err := sender.Send(stored, &domain.PushNotification{Title: "hi"}); err == nil || !strings.Contains(err.Error(), "not a public address") {
+		t.Errorf("Send() to a private endpoint error = %v", err)
+	}
+}
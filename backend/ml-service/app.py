@@ -7,10 +7,14 @@ GET  /           → service info
 GET  /health     → readiness check
 POST /predict    → classify raw text       { "text": "..." }
 POST /predict/url → scrape URL & classify  { "url": "https://..." }
+POST /summarize  → extractive summary      { "text": "...", "max_sentences": 2 }
 """
 
 import os
 import re
+import uuid
+import zlib
+from collections import Counter
 from typing import Optional
 from urllib.parse import urlparse
 
@@ -18,8 +22,9 @@ import httpx
 import torch
 import uvicorn
 from bs4 import BeautifulSoup
-from fastapi import FastAPI, HTTPException
+from fastapi import FastAPI, HTTPException, Request
 from fastapi.middleware.cors import CORSMiddleware
+from fastapi.responses import JSONResponse
 from pydantic import BaseModel, HttpUrl
 from transformers import AutoModelForSequenceClassification, AutoTokenizer
 
@@ -30,6 +35,7 @@ MAX_LENGTH         = int(os.getenv("MAX_LENGTH", "384"))
 FAKE_LABEL_ID      = int(os.getenv("FAKE_LABEL_ID", "0"))
 REAL_LABEL_ID      = int(os.getenv("REAL_LABEL_ID", "1"))
 SCRAPE_TIMEOUT     = int(os.getenv("SCRAPE_TIMEOUT", "15"))
+MAX_REQUEST_BYTES  = int(os.getenv("MAX_REQUEST_BYTES", str(16 * 1024 * 1024)))
 
 # ── App ───────────────────────────────────────────────────────────────────
 app = FastAPI(
@@ -46,6 +52,87 @@ app.add_middleware(
     allow_headers=["*"],
 )
 
+@app.middleware("http")
+async def request_id_middleware(request: Request, call_next):
+    """Echo the caller's X-Request-ID (or a new one) for cross-service debugging."""
+    request_id = request.headers.get("x-request-id") or uuid.uuid4().hex
+    traceparent = request.headers.get("traceparent", "-")
+    response = await call_next(request)
+    response.headers["X-Request-ID"] = request_id
+    print(f"[request] id={request_id} traceparent={traceparent} {request.method} "
+          f"{request.url.path} -> {response.status_code}")
+    return response
+
+
+class GzipRequestMiddleware:
+    """Inflate gzip request bodies and advertise support to clients.
+
+    Every response carries ``Accept-Encoding: gzip`` (RFC 7694); the Go
+    backend only compresses request bodies after seeing it. Inflated bodies
+    are capped at MAX_REQUEST_BYTES.
+    """
+
+    def __init__(self, app):
+        self.app = app
+
+    async def __call__(self, scope, receive, send):
+        if scope["type"] != "http":
+            await self.app(scope, receive, send)
+            return
+
+        async def send_advertising(message):
+            if message["type"] == "http.response.start":
+                message["headers"] = list(message.get("headers", [])) + [(b"accept-encoding", b"gzip")]
+            await send(message)
+
+        headers = dict(scope["headers"])
+        encoding = headers.get(b"content-encoding", b"identity").strip().lower()
+        if encoding == b"identity":
+            await self.app(scope, receive, send_advertising)
+            return
+        if encoding != b"gzip":
+            response = JSONResponse({"detail": "Unsupported Content-Encoding"}, status_code=415)
+            await response(scope, receive, send_advertising)
+            return
+
+        compressed = b""
+        more_body = True
+        while more_body:
+            message = await receive()
+            compressed += message.get("body", b"")
+            more_body = message.get("more_body", False)
+        inflater = zlib.decompressobj(16 + zlib.MAX_WBITS)
+        try:
+            body = inflater.decompress(compressed, MAX_REQUEST_BYTES + 1)
+        except zlib.error:
+            response = JSONResponse({"detail": "Invalid gzip body"}, status_code=400)
+            await response(scope, receive, send_advertising)
+            return
+        if len(body) > MAX_REQUEST_BYTES or inflater.unconsumed_tail:
+            response = JSONResponse({"detail": "Request body too large"}, status_code=413)
+            await response(scope, receive, send_advertising)
+            return
+
+        scope = dict(scope)
+        scope["headers"] = [
+            (name, value) for name, value in scope["headers"]
+            if name not in (b"content-encoding", b"content-length")
+        ] + [(b"content-length", str(len(body)).encode())]
+        delivered = False
+
+        async def receive_inflated():
+            nonlocal delivered
+            if delivered:
+                return await receive()
+            delivered = True
+            return {"type": "http.request", "body": body, "more_body": False}
+
+        await self.app(scope, receive_inflated, send_advertising)
+
+
+app.add_middleware(GzipRequestMiddleware)
+
+
 # ── Model state ───────────────────────────────────────────────────────────
 _model: Optional[AutoModelForSequenceClassification] = None
 _tokenizer: Optional[AutoTokenizer] = None
@@ -61,6 +148,15 @@ class UrlRequest(BaseModel):
     url: HttpUrl
 
 
+class SummarizeRequest(BaseModel):
+    text: str
+    max_sentences: int = 2
+
+
+class SummarizeResponse(BaseModel):
+    summary: str
+
+
 class PredictionResponse(BaseModel):
     result: str
     confidence: float
@@ -197,6 +293,68 @@ def _run_inference(text: str) -> tuple[str, float, float, float]:
     return "REAL", real_prob, fake_prob, real_prob
 
 
+# ── Summarization ─────────────────────────────────────────────────────────
+# Per-script text rules. English splits sentences on . ! ? before a capital;
+# Devanagari (Hindi, Marathi, Nepali) has no case and ends sentences with the
+# danda, so it needs its own splitter, word pattern and stopwords.
+_LANGUAGES = {
+    "en": {
+        "sentence_split": re.compile(r"(?<=[.!?])\s+(?=[A-Z\"'])"),
+        "word": re.compile(r"[a-z']{3,}"),
+        "min_sentence_chars": 20,
+        "stopwords": {
+            "the", "and", "for", "that", "with", "was", "are", "has", "have", "this",
+            "from", "but", "not", "said", "its", "his", "her", "they", "their", "been",
+            "were", "will", "would", "which", "who", "about", "after", "also", "into",
+        },
+    },
+    "hi": {
+        "sentence_split": re.compile(r"(?<=[।॥?!])\s*"),
+        "word": re.compile(r"[\u0900-\u0963\u0966-\u097F]{2,}"),
+        "min_sentence_chars": 10,
+        "stopwords": {
+            "और", "का", "की", "के", "को", "में", "से", "है", "हैं", "था", "थी", "थे",
+            "पर", "ने", "यह", "वह", "इस", "उस", "एक", "भी", "तो", "कि", "जो", "लिए",
+            "हो", "गया", "गई", "किया", "कर", "रहा", "रही", "साथ", "बाद", "नहीं",
+            "ही", "तक", "अपने", "कहा", "होने", "वाले", "द्वारा",
+        },
+    },
+}
+
+
+def detect_language(text: str) -> str:
+    """Picks the summarizer rules by script: mostly Devanagari letters is "hi"."""
+    devanagari = sum(1 for ch in text if "\u0900" <= ch <= "\u097f")
+    latin = sum(1 for ch in text if ch.isascii() and ch.isalpha())
+    return "hi" if devanagari > latin else "en"
+
+
+def summarize_text(text: str, max_sentences: int) -> str:
+    """Frequency-scored extractive summary, sentences kept in article order."""
+    rules = _LANGUAGES[detect_language(text)]
+    word, stopwords = rules["word"], rules["stopwords"]
+
+    sentences = [
+        s.strip() for s in rules["sentence_split"].split(text)
+        if len(s.strip()) > rules["min_sentence_chars"]
+    ]
+    if len(sentences) <= max_sentences:
+        return " ".join(sentences)
+
+    freq = Counter(w for w in word.findall(text.lower()) if w not in stopwords)
+
+    def score(idx_sentence):
+        idx, sentence = idx_sentence
+        words = [w for w in word.findall(sentence.lower()) if w not in stopwords]
+        if not words:
+            return 0.0
+        lead_bonus = 1.5 if idx == 0 else 1.0
+        return lead_bonus * sum(freq[w] for w in words) / len(words)
+
+    best = sorted(enumerate(sentences), key=score, reverse=True)[:max_sentences]
+    return " ".join(sentence for _, sentence in sorted(best))
+
+
 # ── Routes ────────────────────────────────────────────────────────────────
 @app.get("/")
 def root():
@@ -209,6 +367,7 @@ def root():
             "GET  /health":      "Readiness check",
             "POST /predict":     "Classify raw text",
             "POST /predict/url": "Scrape URL & classify",
+            "POST /summarize":   "Extractive article summary",
         },
     }
 
@@ -265,6 +424,15 @@ async def predict_url(request: UrlRequest):
     )
 
 
+@app.post("/summarize", response_model=SummarizeResponse)
+def summarize(request: SummarizeRequest):
+    text = request.text.strip()
+    if not text:
+        raise HTTPException(status_code=400, detail="text cannot be empty")
+    max_sentences = min(max(request.max_sentences, 1), 5)
+    return SummarizeResponse(summary=summarize_text(text, max_sentences))
+
+
 if __name__ == "__main__":
     port = int(os.environ.get("PORT", 7860))
     uvicorn.run(app, host="0.0.0.0", port=port)
@@ -0,0 +1,309 @@
+// Package client is a Go client for the fake news detection REST API.
+//
+// It covers analysis, prediction history, the admin rescore and import
+// endpoints and the internal worker job queue, with typed requests and
+// responses, retries on transient failures and bearer, API key or
+// request-signing authentication:
+//
+//	c := client.New("https://api.example.com", client.WithAPIKey(key))
+//	prediction, err := c.Analyze(ctx, client.AnalysisRequest{Type: "url", Content: articleURL})
+//
+// The API has no webhook endpoints, so there is nothing to wrap for them
+// yet; push notifications are delivered through Web Push instead.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Retry defaults
+const (
+	DefaultMaxRetries = 3
+	DefaultBackoff    = 500 * time.Millisecond
+	DefaultTimeout    = 2 * time.Minute
+
+	maxBackoff = 30 * time.Second
+)
+
+// APIError is a non-2xx response from the API.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string // the response's "error" field, if any
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s %s: %s (HTTP %d)", e.Method, e.Path, e.Message, e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s: HTTP %d", e.Method, e.Path, e.StatusCode)
+}
+
+// IsStatus reports whether err is an APIError with the given status code.
+func IsStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// Client calls the API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+
+	token         string
+	apiKey        string
+	signingKeyID  string
+	signingSecret string
+	scopes        []string
+
+	maxRetries int
+	backoff    time.Duration
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// New creates a client for the API at baseURL, e.g. "http://localhost:8080".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		userAgent:  "fakenews-go-client",
+		maxRetries: DefaultMaxRetries,
+		backoff:    DefaultBackoff,
+		sleep:      sleepContext,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithToken authenticates with a bearer token: an admin or worker token,
+// or a session token from SSO login.
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithAPIKey identifies the caller as a registered API client.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithSigningKey HMAC-SHA256 signs every request with a partner signing
+// key, as verified by the API's request signer.
+func WithSigningKey(keyID, secret string) Option {
+	return func(c *Client) {
+		c.signingKeyID = keyID
+		c.signingSecret = secret
+	}
+}
+
+// WithScopes narrows the key's permissions to scopes on every request,
+// e.g. "history:read" for a component that only reads history.
+func WithScopes(scopes ...string) Option {
+	return func(c *Client) { c.scopes = scopes }
+}
+
+// WithHTTPClient replaces the default HTTP client, e.g. to change the
+// timeout or transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithRetries sets how many times a failed request is retried and the
+// initial backoff, which doubles on each attempt. Zero retries disables
+// retrying.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		if maxRetries >= 0 {
+			c.maxRetries = maxRetries
+		}
+		if backoff > 0 {
+			c.backoff = backoff
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// BaseURL returns the API base URL.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// do sends a JSON request and decodes the response into out. A 204
+// response leaves out untouched.
+//
+// Rate limiting (429) and unavailability (503) are retried for every
+// request, since the API rejected it without acting on it. Network
+// errors and gateway failures are only retried for GET requests and
+// calls marked idempotent, where running twice is harmless.
+func (c *Client) do(ctx context.Context, method, path string, payload, out interface{}, idempotent bool) error {
+	var body []byte
+	if payload != nil {
+		if raw, ok := payload.(json.RawMessage); ok {
+			body = raw
+		} else {
+			data, err := json.Marshal(payload)
+			if err != nil {
+				return fmt.Errorf("failed to marshal request: %w", err)
+			}
+			body = data
+		}
+	}
+	idempotent = idempotent || method == http.MethodGet
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, path, body)
+		if err != nil {
+			if ctx.Err() != nil || !idempotent || attempt >= c.maxRetries {
+				return fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			if err := c.sleep(ctx, c.retryDelay(attempt, "")); err != nil {
+				return err
+			}
+			continue
+		}
+
+		data, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			return fmt.Errorf("failed to read response: %w", readErr)
+		}
+
+		if resp.StatusCode >= 300 {
+			if attempt < c.maxRetries && retryableStatus(resp.StatusCode, idempotent) {
+				if err := c.sleep(ctx, c.retryDelay(attempt, resp.Header.Get("Retry-After"))); err != nil {
+					return err
+				}
+				continue
+			}
+			apiErr := &APIError{Method: method, Path: path, StatusCode: resp.StatusCode}
+			var errBody struct {
+				Error   string `json:"error"`
+				Message string `json:"message"`
+			}
+			if json.Unmarshal(data, &errBody) == nil {
+				apiErr.Message = errBody.Error
+				if errBody.Message != "" {
+					apiErr.Message = errBody.Message
+				}
+			}
+			return apiErr
+		}
+
+		if out == nil || resp.StatusCode == http.StatusNoContent {
+			return nil
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		return nil
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if len(c.scopes) > 0 {
+		req.Header.Set("X-Scope", strings.Join(c.scopes, " "))
+	}
+	if c.signingKeyID != "" {
+		// Signed per attempt so retries carry a fresh timestamp.
+		ts := time.Now().Unix()
+		req.Header.Set("X-Signature-Key-Id", c.signingKeyID)
+		req.Header.Set("X-Signature-Timestamp", strconv.FormatInt(ts, 10))
+		req.Header.Set("X-Signature", sign(c.signingSecret, method, req.URL.RequestURI(), ts, body))
+	}
+	return c.httpClient.Do(req)
+}
+
+// sign produces the hex HMAC-SHA256 signature over
+// METHOD \n PATH?QUERY \n TIMESTAMP \n hex(sha256(BODY)).
+func sign(secret, method, requestURI string, timestamp int64, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	payload := strings.Join([]string{
+		strings.ToUpper(method),
+		requestURI,
+		strconv.FormatInt(timestamp, 10),
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func retryableStatus(statusCode int, idempotent bool) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// retryDelay honours a Retry-After header in seconds, otherwise backs off
+// exponentially from the configured base.
+func (c *Client) retryDelay(attempt int, retryAfter string) time.Duration {
+	if secs, err := strconv.Atoi(strings.TrimSpace(retryAfter)); err == nil && secs >= 0 {
+		return min(time.Duration(secs)*time.Second, maxBackoff)
+	}
+	if attempt > 10 {
+		return maxBackoff
+	}
+	return min(c.backoff<<attempt, maxBackoff)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
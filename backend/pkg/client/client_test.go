@@ -0,0 +1,243 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/middleware"
+)
+
+// newTestClient returns a client for srv that never actually sleeps.
+func newTestClient(srv *httptest.Server, opts ...Option) *Client {
+	c := New(srv.URL, opts...)
+	c.sleep = func(ctx context.Context, d time.Duration) error { return ctx.Err() }
+	return c
+}
+
+func TestAnalyzeSendsAuthAndDecodesPrediction(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/analyze" {
+			t.Errorf("got %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if got := r.Header.Get("X-API-Key"); got != "key" {
+			t.Errorf("X-API-Key = %q", got)
+		}
+		var req AnalysisRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Content != "some text" {
+			t.Errorf("request = %+v, %v", req, err)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":    true,
+			"prediction": map[string]interface{}{"id": "p1", "result": "FAKE", "confidence": 0.9},
+		})
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv, WithToken("tok"), WithAPIKey("key"))
+	p, err := c.Analyze(context.Background(), AnalysisRequest{Type: "text", Content: "some text"})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if p.ID != "p1" || p.Result != "FAKE" || p.Confidence != 0.9 {
+		t.Errorf("prediction = %+v", p)
+	}
+}
+
+func TestHistoryEncodesQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "domain=example.com&label=FAKE&min_confidence=0.5&order=oldest&since=2024-01-02T00%3A00%3A00Z"
+		if r.URL.RawQuery != want {
+			t.Errorf("query = %q, want %q", r.URL.RawQuery, want)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"history": []map[string]interface{}{{"id": "a"}, {"id": "b"}},
+		})
+	}))
+	defer srv.Close()
+
+	history, err := newTestClient(srv).History(context.Background(), HistoryQuery{
+		Label:         "FAKE",
+		Domain:        "example.com",
+		MinConfidence: 0.5,
+		Since:         time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		OldestFirst:   true,
+	})
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(history) != 2 || history[1].ID != "b" {
+		t.Errorf("history = %+v", history)
+	}
+}
+
+func TestHistoryFollowsPages(t *testing.T) {
+	pages := map[string]string{
+		"":  `{"history":[{"id":"a"},{"id":"b"}],"pagination":{"total":4,"limit":2,"offset":0,"has_more":true,"next_offset":2}}`,
+		"2": `{"history":[{"id":"b"},{"id":"c"}],"pagination":{"total":4,"limit":2,"offset":2,"has_more":true,"next_offset":4}}`,
+		"4": `{"history":[{"id":"d"}],"pagination":{"total":4,"limit":2,"offset":4,"has_more":false}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, pages[r.URL.Query().Get("offset")])
+	}))
+	defer srv.Close()
+
+	history, err := newTestClient(srv).History(context.Background(), HistoryQuery{All: true})
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	var ids []string
+	for _, p := range history {
+		ids = append(ids, p.ID)
+	}
+	if strings.Join(ids, ",") != "a,b,c,d" {
+		t.Errorf("ids = %v, want every page once", ids)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		status    int
+		wantCalls int32
+	}{
+		{"rate limited POST is retried", http.MethodPost, http.StatusTooManyRequests, 3},
+		{"unavailable POST is retried", http.MethodPost, http.StatusServiceUnavailable, 3},
+		{"bad gateway GET is retried", http.MethodGet, http.StatusBadGateway, 3},
+		{"bad gateway POST is not retried", http.MethodPost, http.StatusBadGateway, 1},
+		{"client error is not retried", http.MethodGet, http.StatusNotFound, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(tt.status)
+				json.NewEncoder(w).Encode(map[string]string{"error": "nope"})
+			}))
+			defer srv.Close()
+
+			c := newTestClient(srv, WithRetries(2, time.Millisecond))
+			err := c.do(context.Background(), tt.method, "/x", nil, nil, false)
+			if !IsStatus(err, tt.status) {
+				t.Fatalf("err = %v, want HTTP %d", err, tt.status)
+			}
+			var apiErr *APIError
+			if errors.As(err, &apiErr); apiErr.Message != "nope" {
+				t.Errorf("Message = %q", apiErr.Message)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetrySucceedsAfterTransientFailure(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jobs": map[string]int{"queued": 4}})
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	c := newTestClient(srv, WithToken("worker"))
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	stats, err := c.Jobs("w1").Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Queued != 4 {
+		t.Errorf("Queued = %d, want 4", stats.Queued)
+	}
+	if len(delays) != 1 || delays[0] != time.Second {
+		t.Errorf("delays = %v, want [1s] from Retry-After", delays)
+	}
+}
+
+func TestJobQueueLease(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantJob string
+	}{
+		{"job waiting", http.StatusOK, `{"success":true,"job":{"id":"j1","kind":"evaluation"}}`, "j1"},
+		{"no job", http.StatusNoContent, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					WorkerID string   `json:"worker_id"`
+					Kinds    []string `json:"kinds"`
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+				if r.URL.Path != "/api/internal/jobs/lease" || req.WorkerID != "w1" || len(req.Kinds) != 1 {
+					t.Errorf("got %s %+v", r.URL.Path, req)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			job, err := newTestClient(srv).Jobs("w1").Lease(context.Background(), []string{"evaluation"})
+			if err != nil {
+				t.Fatalf("Lease: %v", err)
+			}
+			var got string
+			if job != nil {
+				got = job.ID
+			}
+			if got != tt.wantJob {
+				t.Errorf("job = %q, want %q", got, tt.wantJob)
+			}
+		})
+	}
+}
+
+func TestSigningKeySignsRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Signature-Key-Id") != "partner" {
+			t.Errorf("missing key id")
+		}
+		// Must match what the API's request signer verifies.
+		ts, _ := strconv.ParseInt(r.Header.Get("X-Signature-Timestamp"), 10, 64)
+		key := middleware.SigningKey{KeyID: "partner", Secret: "s3cret"}
+		want := hex.EncodeToString(middleware.Sign(key, r.Method, r.URL.RequestURI(), ts, []byte(`{"id":"p1","model":""}`)))
+		if got := r.Header.Get("X-Signature"); got != want {
+			t.Errorf("X-Signature = %q, want %q", got, want)
+		}
+		w.Write([]byte(`{"flipped":true}`))
+	}))
+	defer srv.Close()
+
+	res, err := newTestClient(srv, WithSigningKey("partner", "s3cret")).Rescore(context.Background(), "p1", "")
+	if err != nil {
+		t.Fatalf("Rescore: %v", err)
+	}
+	if !res.Flipped {
+		t.Error("Flipped = false, want true")
+	}
+}
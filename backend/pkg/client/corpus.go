@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// Research corpus types shared with the API.
+type (
+	CorpusRequest  = domain.CorpusRequest
+	CorpusManifest = domain.CorpusManifest
+	CorpusRecord   = domain.CorpusRecord
+)
+
+// BuildCorpus samples a balanced, anonymized training corpus from stored
+// predictions. It requires the admin token.
+func (c *Client) BuildCorpus(ctx context.Context, req CorpusRequest) (*CorpusManifest, error) {
+	var resp struct {
+		Manifest *CorpusManifest `json:"manifest"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/admin/corpora", req, &resp, false); err != nil {
+		return nil, err
+	}
+	return resp.Manifest, nil
+}
+
+// CorpusData downloads a corpus's JSONL export.
+func (c *Client) CorpusData(ctx context.Context, id string) ([]byte, error) {
+	path := "/api/admin/corpora/" + url.PathEscape(id) + "/data"
+	resp, err := c.send(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", http.MethodGet, path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{Method: http.MethodGet, Path: path, StatusCode: resp.StatusCode}
+	}
+	return data, nil
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// Job failure classes for JobQueue.Fail.
+const (
+	FailureTransient = domain.JobFailureTransient // retried once the ML service recovers
+	FailurePermanent = domain.JobFailurePermanent // moved straight to the dead-letter queue
+)
+
+// JobStats counts jobs in the API's worker queue.
+type JobStats struct {
+	Queued   int            `json:"queued"`
+	Leased   int            `json:"leased"`
+	Deferred int            `json:"deferred"`
+	Done     int            `json:"done"`
+	Failed   int            `json:"failed"`
+	Workers  map[string]int `json:"workers"` // leased jobs per worker
+}
+
+// JobQueue talks to the internal worker job queue as one worker. The
+// client must be authenticated with the worker token.
+type JobQueue struct {
+	client   *Client
+	workerID string
+}
+
+// Jobs returns the job queue as seen by workerID.
+func (c *Client) Jobs(workerID string) *JobQueue {
+	return &JobQueue{client: c, workerID: workerID}
+}
+
+// WorkerID returns the worker the queue leases jobs for.
+func (q *JobQueue) WorkerID() string {
+	return q.workerID
+}
+
+// Lease asks for the next job of one of kinds; nil means none is waiting.
+func (q *JobQueue) Lease(ctx context.Context, kinds []string) (*Job, error) {
+	var resp struct {
+		Job *Job `json:"job"`
+	}
+	payload := map[string]interface{}{"worker_id": q.workerID, "kinds": kinds}
+	if err := q.client.do(ctx, http.MethodPost, "/api/internal/jobs/lease", payload, &resp, false); err != nil {
+		return nil, err
+	}
+	return resp.Job, nil
+}
+
+// Heartbeat extends the lease on a job and reports progress.
+func (q *JobQueue) Heartbeat(ctx context.Context, id string, progress interface{}) error {
+	payload := map[string]interface{}{"worker_id": q.workerID, "progress": progress}
+	return q.update(ctx, id, "heartbeat", payload)
+}
+
+// Complete reports a job's result.
+func (q *JobQueue) Complete(ctx context.Context, id string, result interface{}) error {
+	payload := map[string]interface{}{"worker_id": q.workerID, "result": result}
+	return q.update(ctx, id, "complete", payload)
+}
+
+// Fail reports that a job could not be run. failure classifies the error
+// as FailureTransient or FailurePermanent; empty leaves it to the queue.
+func (q *JobQueue) Fail(ctx context.Context, id string, reason error, failure string) error {
+	payload := map[string]interface{}{"worker_id": q.workerID, "error": reason.Error(), "failure": failure}
+	return q.update(ctx, id, "fail", payload)
+}
+
+// Stats returns queue counts.
+func (q *JobQueue) Stats(ctx context.Context) (*JobStats, error) {
+	var resp struct {
+		Jobs JobStats `json:"jobs"`
+	}
+	if err := q.client.do(ctx, http.MethodGet, "/api/internal/jobs", nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp.Jobs, nil
+}
+
+// update posts a lease update. Updates only apply to the worker holding
+// the lease, so repeating one is harmless.
+func (q *JobQueue) update(ctx context.Context, id, action string, payload interface{}) error {
+	return q.client.do(ctx, http.MethodPost, "/api/internal/jobs/"+url.PathEscape(id)+"/"+action, payload, nil, true)
+}
+
+// FailedJobs lists the dead-letter queue: jobs that ran out of attempts or
+// failed permanently. Requires an admin token.
+func (c *Client) FailedJobs(ctx context.Context) ([]Job, error) {
+	var resp struct {
+		Jobs []Job `json:"jobs"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/admin/jobs?status="+domain.JobFailed, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return resp.Jobs, nil
+}
+
+// RequeueJob gives a failed job a fresh set of attempts. Requires an admin
+// token.
+func (c *Client) RequeueJob(ctx context.Context, id string) (*Job, error) {
+	var resp struct {
+		Job *Job `json:"job"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/admin/jobs/"+url.PathEscape(id)+"/requeue", nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return resp.Job, nil
+}
@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// Types shared with the API. They are aliases so callers outside this
+// module can name them.
+type (
+	AnalysisRequest = domain.AnalysisRequest
+	Prediction      = domain.Prediction
+	Job             = domain.Job
+	SourceInfo      = domain.SourceInfo
+	PageInfo        = domain.PageInfo
+)
+
+// Analyze submits text or a URL for analysis. The prediction's fields are
+// filled according to the request's verbosity.
+func (c *Client) Analyze(ctx context.Context, req AnalysisRequest) (*Prediction, error) {
+	var resp struct {
+		Prediction *Prediction `json:"prediction"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/analyze", req, &resp, false); err != nil {
+		return nil, err
+	}
+	return resp.Prediction, nil
+}
+
+// GetPrediction fetches a stored prediction by ID.
+func (c *Client) GetPrediction(ctx context.Context, id string) (*Prediction, error) {
+	var prediction Prediction
+	if err := c.do(ctx, http.MethodGet, "/api/predictions?id="+url.QueryEscape(id), nil, &prediction, false); err != nil {
+		return nil, err
+	}
+	return &prediction, nil
+}
+
+// HistoryQuery filters GET /api/history. Zero values mean no filter.
+type HistoryQuery struct {
+	Label         string // "FAKE" or "REAL"
+	Type          string // "text" or "url"
+	Domain        string
+	Model         string
+	MinConfidence float64
+	MaxConfidence float64
+	Since         time.Time
+	Until         time.Time
+	PinnedOnly    bool
+	PinnedFirst   bool
+	OldestFirst   bool
+	All           bool // every caller's predictions, not only the client's own; needs an admin credential
+	Limit         int  // page size for HistoryPage; the server's default when zero
+	Offset        int
+}
+
+func (q HistoryQuery) values() url.Values {
+	v := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	set("label", q.Label)
+	set("type", q.Type)
+	set("domain", q.Domain)
+	set("model", q.Model)
+	if q.MinConfidence > 0 {
+		v.Set("min_confidence", strconv.FormatFloat(q.MinConfidence, 'f', -1, 64))
+	}
+	if q.MaxConfidence > 0 {
+		v.Set("max_confidence", strconv.FormatFloat(q.MaxConfidence, 'f', -1, 64))
+	}
+	if !q.Since.IsZero() {
+		v.Set("since", q.Since.Format(time.RFC3339))
+	}
+	if !q.Until.IsZero() {
+		v.Set("until", q.Until.Format(time.RFC3339))
+	}
+	if q.PinnedOnly {
+		v.Set("pinned", "true")
+	}
+	if q.PinnedFirst {
+		v.Set("pinned_first", "true")
+	}
+	if q.OldestFirst {
+		v.Set("order", "oldest")
+	}
+	if q.All {
+		v.Set("all", "true")
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Offset > 0 {
+		v.Set("offset", strconv.Itoa(q.Offset))
+	}
+	return v
+}
+
+// History lists the caller's predictions matching q. Without a limit it
+// follows every page, so it returns all matches starting at q.Offset.
+func (c *Client) History(ctx context.Context, q HistoryQuery) ([]*Prediction, error) {
+	if q.Limit > 0 {
+		history, _, err := c.HistoryPage(ctx, q)
+		return history, err
+	}
+	var history []*Prediction
+	seen := make(map[string]bool)
+	for {
+		page, info, err := c.HistoryPage(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range page {
+			// Predictions stored mid-listing shift later pages
+			if !seen[p.ID] {
+				seen[p.ID] = true
+				history = append(history, p)
+			}
+		}
+		if !info.HasMore || info.NextOffset == nil {
+			return history, nil
+		}
+		q.Offset = *info.NextOffset
+	}
+}
+
+// HistoryPage fetches one page of the caller's predictions matching q,
+// with where it sits among all matches.
+func (c *Client) HistoryPage(ctx context.Context, q HistoryQuery) ([]*Prediction, PageInfo, error) {
+	path := "/api/history"
+	if params := q.values().Encode(); params != "" {
+		path += "?" + params
+	}
+	var resp struct {
+		History    []*Prediction `json:"history"`
+		Pagination PageInfo      `json:"pagination"`
+	}
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp, false); err != nil {
+		return nil, PageInfo{}, err
+	}
+	return resp.History, resp.Pagination, nil
+}
+
+// RescoreResult is the response of POST /api/admin/rescore.
+type RescoreResult struct {
+	Previous *Prediction `json:"previous"`
+	Rescored *Prediction `json:"rescored"`
+	Flipped  bool        `json:"flipped"`
+}
+
+// Rescore re-runs a stored prediction through model, or the default model
+// when model is empty. Requires an admin token.
+func (c *Client) Rescore(ctx context.Context, id, model string) (*RescoreResult, error) {
+	payload := map[string]string{"id": id, "model": model}
+	var resp RescoreResult
+	if err := c.do(ctx, http.MethodPost, "/api/admin/rescore", payload, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ImportStats summarizes a history import.
+type ImportStats struct {
+	Total      int      `json:"total"`
+	Imported   int      `json:"imported"`
+	Duplicates int      `json:"duplicates"`
+	Invalid    int      `json:"invalid"`
+	DryRun     bool     `json:"dry_run"`
+	Errors     []string `json:"errors"`
+}
+
+// Import uploads a prototype history dump. Requires an admin token.
+// Imports skip duplicates, so retrying one is safe.
+func (c *Client) Import(ctx context.Context, dump json.RawMessage, dryRun bool) (*ImportStats, error) {
+	path := "/api/admin/import"
+	if dryRun {
+		path += "?dry_run=true"
+	}
+	var resp struct {
+		Stats ImportStats `json:"stats"`
+	}
+	if err := c.do(ctx, http.MethodPost, path, dump, &resp, true); err != nil {
+		return nil, err
+	}
+	return &resp.Stats, nil
+}
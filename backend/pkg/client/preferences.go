@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Naman30903/Final-Year-Project/internal/domain"
+)
+
+// AnalysisDefaults are the caller's saved analysis options.
+type AnalysisDefaults = domain.AnalysisDefaults
+
+type preferencesResponse struct {
+	Preferences *AnalysisDefaults `json:"preferences"`
+}
+
+// Preferences returns the caller's saved analysis defaults.
+func (c *Client) Preferences(ctx context.Context) (*AnalysisDefaults, error) {
+	var resp preferencesResponse
+	if err := c.do(ctx, http.MethodGet, "/api/users/me/preferences", nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return resp.Preferences, nil
+}
+
+// SetPreferences replaces the caller's saved analysis defaults. Analyze
+// omits options left at their zero value, so saved defaults apply to them;
+// a saved include_summary or include_evidence of true therefore cannot be
+// turned off for a single Analyze call.
+func (c *Client) SetPreferences(ctx context.Context, defaults AnalysisDefaults) (*AnalysisDefaults, error) {
+	var resp preferencesResponse
+	if err := c.do(ctx, http.MethodPut, "/api/users/me/preferences", defaults, &resp, true); err != nil {
+		return nil, err
+	}
+	return resp.Preferences, nil
+}
+
+// ClearPreferences removes the caller's saved analysis defaults.
+func (c *Client) ClearPreferences(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/api/users/me/preferences", nil, nil, true)
+}
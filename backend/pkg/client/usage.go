@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Usage is the response of GET /api/users/me/usage.
+type Usage struct {
+	Caller string `json:"caller"`
+	Usage  struct {
+		Window        string    `json:"window"`
+		Since         time.Time `json:"since"`
+		Requests      int       `json:"requests"`
+		ClientErrors  int       `json:"client_errors"`
+		ServerErrors  int       `json:"server_errors"`
+		RateLimited   int       `json:"rate_limited"`
+		ErrorRate     float64   `json:"error_rate"`
+		AvgLatencyMS  float64   `json:"avg_latency_ms"`
+		RateLimitHits []struct {
+			At     time.Time `json:"at"`
+			Method string    `json:"method"`
+			Path   string    `json:"path"`
+		} `json:"rate_limit_hits"`
+	} `json:"usage"`
+	Quota struct {
+		Pins *struct {
+			Used  int `json:"used"`
+			Limit int `json:"limit"` // 0 = unlimited
+		} `json:"pins,omitempty"`
+	} `json:"quota"`
+}
+
+// Usage returns the caller's own API usage over window ("1h", "24h", "7d"
+// or "30d"; empty for the server default).
+func (c *Client) Usage(ctx context.Context, window string) (*Usage, error) {
+	path := "/api/users/me/usage"
+	if window != "" {
+		path += "?window=" + url.QueryEscape(window)
+	}
+	var usage Usage
+	if err := c.do(ctx, http.MethodGet, path, nil, &usage, false); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
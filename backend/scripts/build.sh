@@ -9,7 +9,7 @@ echo "Building Go application..."
 # Set build variables
 BUILD_DIR="bin"
 BINARY_NAME="api"
-MAIN_PATH="cmd/api/main.go"
+MAIN_PATH="./cmd/api"
 
 # Create build directory if it doesn't exist
 mkdir -p $BUILD_DIR